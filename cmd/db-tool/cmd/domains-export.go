@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/spf13/cobra"
+)
+
+// domainsExportCmd represents the domains export command
+var domainsExportCmd = &cobra.Command{
+	Use:   "export --org [org_id]",
+	Short: "Export the domains of an organization",
+	Long: `The export command writes a versioned JSON document with the
+domains of an organization, their ipa information, certificates,
+servers, locations and subnet to location mappings.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var w io.Writer = os.Stdout
+		orgID, _ := cmd.Flags().GetString("org")
+		output, _ := cmd.Flags().GetString("output")
+		if output != "" && output != "-" {
+			file, err := os.Create(output)
+			if err != nil {
+				slog.Error("Export failed", slog.String("error", err.Error()))
+				os.Exit(2)
+			}
+			defer file.Close()
+			w = file
+		}
+		cfg := config.Get()
+		r := datastore.NewDomainExportDb(cfg, slog.Default())
+		err := r.Export(orgID, w)
+		if err != nil {
+			slog.Error("Export failed", slog.String("error", err.Error()))
+			os.Exit(2)
+		} else {
+			slog.Info("Done")
+		}
+	},
+}
+
+func init() {
+	domainsExportCmd.Flags().String("org", "", "organization id to export")
+	domainsExportCmd.Flags().StringP("output", "o", "-", "file to write the document, '-' for the standard output")
+	_ = domainsExportCmd.MarkFlagRequired("org")
+	domainsCmd.AddCommand(domainsExportCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	"github.com/spf13/cobra"
+)
+
+// domainsImportCmd represents the domains import command
+var domainsImportCmd = &cobra.Command{
+	Use:   "import --org [org_id]",
+	Short: "Import the domains of an organization",
+	Long: `The import command reads a document written by the export command
+and registers its domains in an organization. The domain uuids are
+preserved, unless the uuid is used by other organization. The
+--strategy flag chooses what to do with the domains which already exist
+in the organization: skip, overwrite or fail.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var r io.Reader = os.Stdin
+		orgID, _ := cmd.Flags().GetString("org")
+		input, _ := cmd.Flags().GetString("input")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		value, _ := cmd.Flags().GetString("strategy")
+		strategy, err := domainexport.ParseStrategy(value)
+		if err != nil {
+			slog.Error("Import failed", slog.String("error", err.Error()))
+			os.Exit(2)
+		}
+		if input != "" && input != "-" {
+			file, err := os.Open(input)
+			if err != nil {
+				slog.Error("Import failed", slog.String("error", err.Error()))
+				os.Exit(2)
+			}
+			defer file.Close()
+			r = file
+		}
+		cfg := config.Get()
+		db := datastore.NewDomainExportDb(cfg, slog.Default())
+		err = db.Import(orgID, r, dryRun, strategy)
+		if err != nil {
+			slog.Error("Import failed", slog.String("error", err.Error()))
+			os.Exit(2)
+		} else {
+			slog.Info("Done")
+		}
+	},
+}
+
+func init() {
+	domainsImportCmd.Flags().String("org", "", "organization id where the domains are imported")
+	domainsImportCmd.Flags().StringP("input", "i", "-", "file to read the document, '-' for the standard input")
+	domainsImportCmd.Flags().Bool("dry-run", false, "log the changes without applying them")
+	domainsImportCmd.Flags().String("strategy", string(domainexport.StrategyFail), "what to do with the existing domains: skip, overwrite or fail")
+	_ = domainsImportCmd.MarkFlagRequired("org")
+	domainsCmd.AddCommand(domainsImportCmd)
+}
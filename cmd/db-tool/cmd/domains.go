@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// domainsCmd represents the domains command
+var domainsCmd = &cobra.Command{
+	Use:   "domains",
+	Short: "Domains export and import",
+}
+
+func init() {
+	rootCmd.AddCommand(domainsCmd)
+}
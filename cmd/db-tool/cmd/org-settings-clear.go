@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/spf13/cobra"
+)
+
+// orgSettingsClearCmd represents the org-settings clear command
+var orgSettingsClearCmd = &cobra.Command{
+	Use:   "clear --org [org_id]",
+	Short: "Clear the settings of an organization",
+	Long: `The clear command removes the settings set for an organization,
+so it uses again the app.registration_approval option of the
+configuration.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		orgID, _ := cmd.Flags().GetString("org")
+		cfg := config.Get()
+		err := datastore.NewOrgSettingsDb(cfg, slog.Default()).SetRegistrationApproval(orgID, nil)
+		if err != nil {
+			slog.Error("Clear failed", slog.String("error", err.Error()))
+			os.Exit(2)
+		} else {
+			slog.Info("Done")
+		}
+	},
+}
+
+func init() {
+	orgSettingsClearCmd.Flags().String("org", "", "organization id to update")
+	_ = orgSettingsClearCmd.MarkFlagRequired("org")
+	orgSettingsCmd.AddCommand(orgSettingsClearCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/spf13/cobra"
+)
+
+// orgSettingsSetCmd represents the org-settings set command
+var orgSettingsSetCmd = &cobra.Command{
+	Use:   "set --org [org_id] --registration-approval=[true|false]",
+	Short: "Set the settings of an organization",
+	Long: `The set command overrides for an organization the
+app.registration_approval option of the configuration; when it is
+true, the domains registered in the organization are pending until an
+organization administrator approves them.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		orgID, _ := cmd.Flags().GetString("org")
+		value, _ := cmd.Flags().GetBool("registration-approval")
+		cfg := config.Get()
+		err := datastore.NewOrgSettingsDb(cfg, slog.Default()).SetRegistrationApproval(orgID, &value)
+		if err != nil {
+			slog.Error("Set failed", slog.String("error", err.Error()))
+			os.Exit(2)
+		} else {
+			slog.Info("Done")
+		}
+	},
+}
+
+func init() {
+	orgSettingsSetCmd.Flags().String("org", "", "organization id to update")
+	orgSettingsSetCmd.Flags().Bool("registration-approval", false, "require the approval of the registered domains")
+	_ = orgSettingsSetCmd.MarkFlagRequired("org")
+	_ = orgSettingsSetCmd.MarkFlagRequired("registration-approval")
+	orgSettingsCmd.AddCommand(orgSettingsSetCmd)
+}
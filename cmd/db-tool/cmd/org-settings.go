@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// orgSettingsCmd represents the org-settings command
+var orgSettingsCmd = &cobra.Command{
+	Use:   "org-settings",
+	Short: "Settings of the organizations",
+}
+
+func init() {
+	rootCmd.AddCommand(orgSettingsCmd)
+}
@@ -16,6 +16,8 @@ var rootCmd = &cobra.Command{
 	db-tool migrate up [steps]
 	db-tool migrate down [steps]
 	db-tool jwk refresh
+	db-tool domains export --org [org_id] > domains.json
+	db-tool domains import --org [org_id] --dry-run < domains.json
 `,
 }
 
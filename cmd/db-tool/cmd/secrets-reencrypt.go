@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/spf13/cobra"
+)
+
+// secretsReencryptCmd represents the secrets reencrypt command
+var secretsReencryptCmd = &cobra.Command{
+	Use:   "reencrypt",
+	Short: "Reencrypt the stored keys with the current main secret",
+	Long: `The reencrypt command encrypts with the current main secret the
+private JWKs and the webhook secrets which are encrypted with a
+previous main secret. Once it succeeds, and the outstanding
+registration tokens have expired, the previous main secret can be
+removed from app.previous_secrets.
+
+The webhooks created before their secret was stored sign with a
+secret derived from the main secret; the command stores it, so run it
+before the first rotation of the main secret to keep their secret.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		cfg := config.Get()
+		err := datastore.NewHostconfJwkDb(cfg, slog.Default()).Reencrypt(dryRun)
+		if err == nil {
+			err = datastore.NewWebhookDb(cfg, slog.Default()).Reencrypt(dryRun)
+		}
+		if err != nil {
+			slog.Error("Reencrypt failed", slog.String("error", err.Error()))
+			os.Exit(2)
+		} else {
+			slog.Info("Done")
+		}
+	},
+}
+
+func init() {
+	secretsReencryptCmd.Flags().Bool("dry-run", false, "log the changes without applying them")
+	secretsCmd.AddCommand(secretsReencryptCmd)
+}
@@ -5,7 +5,9 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/logger"
@@ -52,6 +54,22 @@ func main() {
 		panic("rbac mock profile not found")
 	}
 
+	// Fault injection to test the resilience of the clients
+	if value := os.Getenv("APP_CLIENTS_RBAC_LATENCY"); value != "" {
+		latency, err := time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+		mockRbac.SetLatency(latency)
+	}
+	if value := os.Getenv("APP_CLIENTS_RBAC_ERROR_STATUS"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil {
+			panic(err)
+		}
+		mockRbac.SetErrors(status, 0)
+	}
+
 	if err := srvRbac.Start(); err != nil {
 		panic(err)
 	}
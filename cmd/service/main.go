@@ -11,9 +11,8 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/logger"
 	impl_service "github.com/podengo-project/idmsvc-backend/internal/infrastructure/service/impl"
-	"github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
 	client_pendo "github.com/podengo-project/idmsvc-backend/internal/usecase/client/pendo"
-	client_rbac "github.com/podengo-project/idmsvc-backend/internal/usecase/client/rbac"
 )
 
 const component = "service"
@@ -32,18 +31,6 @@ func startSignalHandler(c context.Context) (context.Context, context.CancelFunc)
 	return ctx, cancel
 }
 
-// initRbacWrapper initialize the client wrapper to communicate with
-// rbac microservice.
-func initRbacWrapper(ctx context.Context, cfg *config.Config) rbac.Rbac {
-	// Initialize the rbac client wrapper
-	rbacClient, err := client_rbac.NewClient("idmsvc", client_rbac.WithBaseURL(cfg.Clients.RbacBaseURL))
-	if err != nil {
-		panic(err)
-	}
-	rbac := client_rbac.New(cfg.Clients.RbacBaseURL, rbacClient)
-	return rbac
-}
-
 func main() {
 	wg := &sync.WaitGroup{}
 	logger.LogBuildInfo(component)
@@ -51,13 +38,26 @@ func main() {
 	logger.InitLogger(cfg, component)
 	defer logger.DoneLogger()
 
+	shutdownTracing, err := tracing.Init(context.Background(), cfg)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
 	db := datastore.NewDB(cfg)
 	defer datastore.Close(db)
+	if err = datastore.VerifySchemaOnStartup(cfg, db); err != nil {
+		panic(err)
+	}
+	if err = datastore.StoreLegacyWebhookSecretsOnStartup(cfg, db); err != nil {
+		panic(err)
+	}
 
 	ctx, cancel := startSignalHandler(context.Background())
-	rbac := initRbacWrapper(ctx, cfg)
 	pendo := client_pendo.NewClient(cfg)
-	s := impl_service.NewApplication(ctx, wg, cfg, db, rbac, pendo)
+	s := impl_service.NewApplication(ctx, wg, cfg, db, pendo)
 	if e := s.Start(); e != nil {
 		panic(e)
 	}
@@ -46,11 +46,15 @@ import (
 const (
 	// Topic constants
 	TopicTodoCreated = "platform.idmsvc.todo-created"
+	// TopicRbacPermissionChanged notify the permissions of an
+	// organization or principal changed, so the cached ACLs are dropped
+	TopicRbacPermissionChanged = "platform.rbac.permission-changed"
 )
 
 // FIXME Refactor this to make it more dynamic and reduce work for the developer
 var AllowedTopics = []string{
 	TopicTodoCreated,
+	TopicRbacPermissionChanged,
 	// TODO Add here new topics
 }
 
@@ -61,11 +65,15 @@ var AllowedTopics = []string{
 //go:embed "todo_created.event.json"
 var schemaEventTocoCreated string
 
+//go:embed "rbac_permission_changed.event.json"
+var schemaEventRbacPermissionChanged string
+
 // TODO Embed here new event schema string contents
 
 var (
 	schemaKey2JsonSpec = map[string]string{
-		TopicTodoCreated: schemaEventTocoCreated,
+		TopicTodoCreated:           schemaEventTocoCreated,
+		TopicRbacPermissionChanged: schemaEventRbacPermissionChanged,
 		// TODO Add here new event schemas
 	}
 )
@@ -8,4 +8,6 @@ const (
 	HeaderXRHFakeID               = "X-Rh-Fake-Identity"
 	HeaderXForwardedFor           = "X-Forwarded-For"
 	HeaderXPendoIntegrationKey    = "X-Pendo-Integration-Key"
+	HeaderIdempotencyKey          = "Idempotency-Key"
+	HeaderIdempotentReplayed      = "Idempotent-Replayed"
 )
@@ -18,18 +18,24 @@ import (
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/8xUS2/jNhD+K8S0hxYWLOVx0i1AkyZA0Rhxewp8YKiRxJoaskPSrTfQf1+Q8iNx9oVg",
-	"D3ujKM73Gg6fQdnBWUIKHupnYPTOksf8cYvShH57I7WJjGlHWQpIIS2lc0YrGbSl8h9vKe151eMg0+pn",
-	"xhZq+Kk8wpfTX19eM1uGcRwLaNAr1i6BQA0PO3LRWhaR+oleOLZPCGOx17OMSqH3303PDm8x0XxF1omo",
-	"sdiRZDmTsySmaXSqlmbB1iEHnRJtpfF4ir7M5SJY0WCrCUXoUWACEvtmQAHuBUyy3eR24P9ycAahvqwu",
-	"C2gtDzJADZrCxTkUELYOp0/skFOCA3ovu9e18KcN4sZGao4lPrCmLttj/Ddqxgbqx4n3iLIaC3gVXv18",
-	"Yu4v21ix60IBSHFIMPdrWL2lGgvQ1Nq3INeTUmFbcbW4Ew163ZFoNfsgpHNspeqFpEZEr6kTnTWSOuGD",
-	"VGvxy0Y75Bmq3s46y8NMWWpNRAozZVCSZNXrgCpExl+Tf+TB37dL5I1WSV4fgvN1WSpL3hqcMza9DHNl",
-	"h1I6Xepm8BtVbs5SrQ45z+UkN3u/WtxBARtkP3mp5tX8LHXCOiTpNNRwMa/mF6nFMvS5u6XRG/yQVh2G",
-	"t3HkqPMt0dSy9IFjli+0P9zOlAZjiEzivKrEfz2SkMYIybg/A1kD54G5a6CG3zH8kYmL16/AeVV9boIO",
-	"58qT0cwz1MpowjeX7l+ZPFJxGCRvoYakiNB7sY5PyIQB/TR6AqlxVlNIwcvOp3s1OVM9qjWsEk7JKJvt",
-	"+6LMpV8MMp/4VIwPE+uPlGOSpN8bZEJCTpcY6sfTDP/ePYy/4QaNdQNmpMgGaijT08MkDYyrA/opwO2R",
-	"TByi9FAAySFP4Asx42r8GAAA//+gDCaMsgYAAA==",
+	"H4sIAAAAAAACA91WUW/bNhD+KwS3hw1xIrfJ9pA9FWu7FhjWINmwhyEoGPFksZZIjaScuoX/+74j5ViW",
+	"nSEdBgzYk2ny7uPdfd8d9VmWru2cJRuDvPwsPQX8C5T+vCHVxHr9Wpmm98Q7pbMRprxUXdeYUkXjbPEh",
+	"OMt7oaypVbz62lMlL+VXxQ6+yKehyLDX1Dkf5WazmUlNofSmYyw4XQ8xiMp50ds6RyE67+5IwnoI66Yv",
+	"SwrhvwlrEhSMBxi+5ZX3zqdgtDbsrZor7zry0XBhK9UEmqLfJHcRndBUGUsi1iSIgcSWEzmT3QiG09aJ",
+	"Ffqo2q7B8mJ+MZMIr1WohTQ2nj+HU1x3lP/SgjxXsEXd1GLfV/7ionjteqt3LiF6YxcpPU9/9saTlpd/",
+	"5Ht3KLc4Hsi4SuUA7H5yvzrtxMDCTJLtW4Z5t5S3B1dt6R14+LIigqK+icJVqXqZI4Fry2XYbmrqyGqy",
+	"JaNMCxqiin04jN8txX1NVtCK/DoDisAZkyY9A+bCK6yyUXAtCevsKQAipNgMDhXaiK2V1cN6ZH/UdlQr",
+	"t5ScbL4Hy8HgWP1yvpyEidSGp8n+R3Zi7wFOea/WB8QP9Xm4Y3e9u/tAZdzRl/H+LfZ4T+2oWx8QZ1U7",
+	"UbNWUd2p1DQHFQrMo4nrQ6JfbJmZ8NGqJYUUViC/MiUTHNGYSq9HJG2dsDXm/yhLO6ntUfw3vI6adtJd",
+	"CAuhYNhta1cNE3s2KghmpAVFcIFx1QfS8jHxkH6v4uE9v7NaGT7X5F4F4Xv7Q9ryibsglMexgoFOY1JB",
+	"4khIjqYSiKHTaNqjzOjep9H9vj3ShS+Hw22WOQxjRWuaxgRChjrI/QH4/cWRAThRdRLPSBWzic5zOfaD",
+	"O1Q+oxpbucO4X2UOOOwXV28h42AWVlTGhyjwXHmHeqWp0AeUQSxco/CDGJDdNysDmZ9QWbuTBfI6QZJV",
+	"06ODT8qGlFW+rNHmZQTd33Kq5NvwrrrJKsXldYxduCzQ+Ta4hs6Qc63iGQZBoTpTGN2GVVmsnrGviUkp",
+	"NzncNLQRME5QmJBzmZ/Nz54xU+g+CwBsnWPrnDtSxTqxVjRmRZ94taAjMkpvxI7AwBLhArCGovJRlK5v",
+	"IB/zMRXFE3Kz4vl8nselappBAG1S2/ASyxRSJugtaJU/Ufw5xTHb/6gB0GMj8cGumHxipG+BSkHhT3bd",
+	"fjTB9bv5+Ze78Yjo21Z5TCnJiVgEIpb9HXlLEcMofXkIDMTOQdhMn1oElnMuSKotnmbgFGlOPYWQ8eN4",
+	"tPjWiUdeNcJAF8hU3BtMbQYDG6fZRlOEVThG0XUO7X/AEWdi/ilJ6VHy3GY4nPLz2/DN+RIDqnFdSwmp",
+	"9w3OCh5qHq+r3Nw+oE8B3uwuEw8MMB352dwLZnO7+QsQiwnHFAwAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
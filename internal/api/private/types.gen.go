@@ -3,6 +3,29 @@
 // Code generated by github.com/deepmap/oapi-codegen version v1.16.3 DO NOT EDIT.
 package private
 
+import (
+	"time"
+)
+
+// Defines values for HealthCheckSeverity.
+const (
+	Critical    HealthCheckSeverity = "critical"
+	NonCritical HealthCheckSeverity = "non-critical"
+)
+
+// Defines values for HealthCheckStatus.
+const (
+	HealthCheckStatusFailed HealthCheckStatus = "failed"
+	HealthCheckStatusOk     HealthCheckStatus = "ok"
+)
+
+// Defines values for HealthReportStatus.
+const (
+	HealthReportStatusDegraded HealthReportStatus = "degraded"
+	HealthReportStatusFailed   HealthReportStatus = "failed"
+	HealthReportStatusOk       HealthReportStatus = "ok"
+)
+
 // Defines values for SuccessProbe.
 const (
 	Ok SuccessProbe = "Ok"
@@ -14,11 +37,45 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// HealthCheck Result of the health check of a dependency
+type HealthCheck struct {
+	// CheckedAt When the check was run; the results are cached for a while
+	CheckedAt time.Time `json:"checked_at"`
+
+	// DurationMs Duration of the check in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+
+	// Message The reason of the failure
+	Message *string `json:"message,omitempty"`
+	Name    string  `json:"name"`
+
+	// Severity A failed critical check makes the service not ready
+	Severity HealthCheckSeverity `json:"severity"`
+	Status   HealthCheckStatus   `json:"status"`
+}
+
+// HealthCheckSeverity A failed critical check makes the service not ready
+type HealthCheckSeverity string
+
+// HealthCheckStatus defines model for HealthCheck.Status.
+type HealthCheckStatus string
+
+// HealthReport Result of the health checks of the dependencies
+type HealthReport struct {
+	Checks []HealthCheck `json:"checks"`
+
+	// Status ok when every check succeeded, degraded when some non-critical check failed, and failed when some critical check failed
+	Status HealthReportStatus `json:"status"`
+}
+
+// HealthReportStatus ok when every check succeeded, degraded when some non-critical check failed, and failed when some critical check failed
+type HealthReportStatus string
+
 // SuccessProbe Todo schema
 type SuccessProbe string
 
-// HealthyFailure Schema to define the error response
-type HealthyFailure = Error
+// HealthyFailure Result of the health checks of the dependencies
+type HealthyFailure = HealthReport
 
-// HealthySuccess Todo schema
-type HealthySuccess = SuccessProbe
+// HealthySuccess Result of the health checks of the dependencies
+type HealthySuccess = HealthReport
@@ -14,11 +14,113 @@ const (
 	X_rh_idm_registration_tokenScopes = "x_rh_idm_registration_token.Scopes"
 )
 
+// Defines values for BatchDomainOperationType.
+const (
+	Delete  BatchDomainOperationType = "delete"
+	Disable BatchDomainOperationType = "disable"
+	Enable  BatchDomainOperationType = "enable"
+	Update  BatchDomainOperationType = "update"
+)
+
 // Defines values for DomainType.
 const (
-	RhelIdm DomainType = "rhel-idm"
+	ActiveDirectory DomainType = "active-directory"
+	RhelIdm         DomainType = "rhel-idm"
+)
+
+// Defines values for IdRangeType.
+const (
+	IpaAdTrust      IdRangeType = "ipa-ad-trust"
+	IpaAdTrustPosix IdRangeType = "ipa-ad-trust-posix"
+	IpaLocal        IdRangeType = "ipa-local"
+	IpaLocalSubid   IdRangeType = "ipa-local-subid"
+)
+
+// Defines values for LocationPolicy.
+const (
+	Prefer   LocationPolicy = "prefer"
+	Restrict LocationPolicy = "restrict"
+)
+
+// Defines values for RegistrationState.
+const (
+	Active          RegistrationState = "active"
+	PendingApproval RegistrationState = "pending_approval"
+	Rejected        RegistrationState = "rejected"
+)
+
+// Defines values for TrustDirection.
+const (
+	Inbound  TrustDirection = "inbound"
+	Outbound TrustDirection = "outbound"
+	TwoWay   TrustDirection = "two-way"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	Failed    WebhookDeliveryStatus = "failed"
+	Pending   WebhookDeliveryStatus = "pending"
+	Succeeded WebhookDeliveryStatus = "succeeded"
 )
 
+// Defines values for WebhookEvent.
+const (
+	DomainApproved WebhookEvent = "domain.approved"
+	DomainCreated  WebhookEvent = "domain.created"
+	DomainDeleted  WebhookEvent = "domain.deleted"
+	DomainRejected WebhookEvent = "domain.rejected"
+	DomainUpdated  WebhookEvent = "domain.updated"
+	HostConfigured WebhookEvent = "host.configured"
+)
+
+// BatchDomainOperation An operation of a batch request.
+type BatchDomainOperation struct {
+	// DomainId A domain id
+	DomainId DomainId `json:"domain_id"`
+
+	// Op Operation to run on a domain; enable and disable set auto_enrollment_enabled.
+	Op BatchDomainOperationType `json:"op"`
+
+	// Update A domain resource
+	Update *UpdateDomainUserRequest `json:"update,omitempty"`
+}
+
+// BatchDomainOperationType Operation to run on a domain; enable and disable set auto_enrollment_enabled.
+type BatchDomainOperationType string
+
+// BatchDomainResult The result of an operation of a batch request.
+type BatchDomainResult struct {
+	// Domain A domain resource
+	Domain *Domain `json:"domain,omitempty"`
+
+	// DomainId A domain id
+	DomainId DomainId   `json:"domain_id"`
+	Error    *ErrorInfo `json:"error,omitempty"`
+
+	// Index Position of the operation into the request.
+	Index int `json:"index"`
+
+	// Op Operation to run on a domain; enable and disable set auto_enrollment_enabled.
+	Op BatchDomainOperationType `json:"op"`
+
+	// Status The HTTP status code of the operation.
+	Status int `json:"status"`
+}
+
+// BatchDomainsRequest A list of operations on the domains of the organization.
+type BatchDomainsRequest struct {
+	// Atomic Run every operation inside one transaction, so none is applied when one fails; else every operation is applied on its own and it has its result.
+	Atomic     *bool                  `json:"atomic,omitempty"`
+	Operations []BatchDomainOperation `json:"operations"`
+}
+
+// BatchDomainsResponseSchema The results of a batch request, in the order of the operations.
+type BatchDomainsResponseSchema struct {
+	// Atomic The operations were run inside one transaction.
+	Atomic  bool                `json:"atomic"`
+	Results []BatchDomainResult `json:"results"`
+}
+
 // CaCertBundle A string of concatenated, PEM-encoded X.509 certificates
 type CaCertBundle = string
 
@@ -33,8 +135,23 @@ type Certificate struct {
 	Subject      string    `json:"subject"`
 }
 
+// CreateWebhookRequest A request to create a webhook.
+type CreateWebhookRequest struct {
+	// Enabled Indicate if the events are delivered to the webhook.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Events The events delivered to a webhook.
+	Events WebhookEvents `json:"events"`
+
+	// Url The https url which receive the events.
+	Url WebhookUrl `json:"url"`
+}
+
 // Domain A domain resource
 type Domain struct {
+	// ActiveDirectory Options for active-directory domains
+	ActiveDirectory *DomainActiveDirectory `json:"active-directory,omitempty"`
+
 	// AutoEnrollmentEnabled Enable or disable host vm auto-enrollment for this domain
 	AutoEnrollmentEnabled *bool `json:"auto_enrollment_enabled,omitempty"`
 
@@ -47,9 +164,15 @@ type Domain struct {
 	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
 	DomainName DomainName `json:"domain_name"`
 
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType DomainType `json:"domain_type"`
 
+	// Registration The registration of a domain which requires approval; it records the server which registered the domain and the decision.
+	Registration *DomainRegistration `json:"registration,omitempty"`
+
+	// RegistrationState State of the registration of a domain; only the active domains can configure hosts. A domain registered by an organization which requires approval is pending approval until an organization administrator approves or rejects it.
+	RegistrationState *RegistrationState `json:"registration_state,omitempty"`
+
 	// RhelIdm Options for ipa domains
 	RhelIdm *DomainIpa `json:"rhel-idm,omitempty"`
 
@@ -57,6 +180,63 @@ type Domain struct {
 	Title *string `json:"title,omitempty"`
 }
 
+// DomainActiveDirectory Options for active-directory domains
+type DomainActiveDirectory struct {
+	// DomainControllers List of domain controllers for this domain.
+	DomainControllers []DomainActiveDirectoryController `json:"domain_controllers"`
+
+	// ForestName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
+	ForestName DomainName `json:"forest_name"`
+
+	// RealmName A Kerberos realm name (usually all upper-case domain name). The realm can only be set during initial registration and not be modified by updates.
+	RealmName RealmName `json:"realm_name"`
+
+	// Sites List of Active Directory sites
+	Sites []Location `json:"sites"`
+
+	// UpdateAgents Subscription Manager IDs of the RHEL hosts allowed to update this domain.
+	UpdateAgents []SubscriptionManagerId `json:"update_agents"`
+}
+
+// DomainActiveDirectoryController Domain controller schema for an entry into the active-directory domain type.
+type DomainActiveDirectoryController struct {
+	// Fqdn A host's Fully Qualified Domain Name (all lower-case).
+	Fqdn          Fqdn `json:"fqdn"`
+	GlobalCatalog bool `json:"global_catalog"`
+
+	// Site A location identifier (lower-case DNS label)
+	Site *LocationName `json:"site,omitempty"`
+}
+
+// DomainDecisionRequest The decision of an organization administrator about a domain registration.
+type DomainDecisionRequest struct {
+	// Reason Reason for the decision, which is recorded with the registration.
+	Reason *string `json:"reason,omitempty"`
+}
+
+// DomainExport A versioned document with the domains of an organization, to back up or move them to other environment.
+type DomainExport struct {
+	// Checksum SHA-256 checksum of the compact JSON encoding of 'domains', with the 'sha256:' prefix.
+	Checksum string             `json:"checksum"`
+	Domains  []DomainExportItem `json:"domains"`
+
+	// ExportedAt Time when the document was created.
+	ExportedAt time.Time `json:"exported_at"`
+
+	// OrgId Organization of the exported domains.
+	OrgId string `json:"org_id"`
+
+	// Version Version of the document format.
+	Version string `json:"version"`
+}
+
+// DomainExportItem A domain of an export document, with its subnet to location mappings.
+type DomainExportItem struct {
+	// Domain A domain resource
+	Domain          Domain           `json:"domain"`
+	LocationSubnets []LocationSubnet `json:"location_subnets"`
+}
+
 // DomainId A domain id
 type DomainId = openapi_types.UUID
 
@@ -68,6 +248,9 @@ type DomainIpa struct {
 	// CaCerts A base64 representation of all the list of chain of certificates, including the server ca.
 	CaCerts []Certificate `json:"ca_certs"`
 
+	// IdRanges List of ID ranges of the IdM domain, including the subordinate ID ranges.
+	IdRanges *[]DomainIpaIdRange `json:"id_ranges,omitempty"`
+
 	// Locations List of DNS locations
 	Locations []Location `json:"locations"`
 
@@ -79,6 +262,30 @@ type DomainIpa struct {
 
 	// Servers List of auto-enrollment enabled servers for this domain.
 	Servers []DomainIpaServer `json:"servers"`
+
+	// Trusts List of AD forests trusted by the IdM domain
+	Trusts *[]DomainIpaTrust `json:"trusts,omitempty"`
+}
+
+// DomainIpaIdRange ID range of the IdM domain, as listed by 'ipa idrange-find'
+type DomainIpaIdRange struct {
+	// BaseId First POSIX ID of the range
+	BaseId int64 `json:"base_id"`
+
+	// BaseRid First RID of the corresponding RID range
+	BaseRid *int64 `json:"base_rid,omitempty"`
+
+	// Name Name of the ID range
+	Name string `json:"name"`
+
+	// RangeSize Number of IDs in the range
+	RangeSize int64 `json:"range_size"`
+
+	// SecondaryBaseRid First RID of the secondary RID range
+	SecondaryBaseRid *int64 `json:"secondary_base_rid,omitempty"`
+
+	// Type Type of an ID range
+	Type IdRangeType `json:"type"`
 }
 
 // DomainIpaServer Server schema for an entry into the Ipa domain type.
@@ -98,6 +305,54 @@ type DomainIpaServer struct {
 	SubscriptionManagerId *SubscriptionManagerId `json:"subscription_manager_id,omitempty"`
 }
 
+// DomainIpaTrust Trust between the IdM domain and an AD forest
+type DomainIpaTrust struct {
+	// Direction Direction of a trust between the IdM domain and an AD forest
+	Direction TrustDirection `json:"direction"`
+
+	// Domains List of trusted domains of the forest, including the forest root domain.
+	Domains []DomainIpaTrustDomain `json:"domains"`
+
+	// FlatName NetBIOS name of the forest root domain
+	FlatName *string `json:"flat_name,omitempty"`
+
+	// ForestName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
+	ForestName DomainName `json:"forest_name"`
+
+	// IdRanges List of ID ranges for the identities of the forest.
+	IdRanges []DomainIpaTrustIdRange `json:"id_ranges"`
+
+	// Sid A Windows security identifier (SID) of a domain
+	Sid SecurityIdentifier `json:"sid"`
+}
+
+// DomainIpaTrustDomain A domain of a trusted AD forest
+type DomainIpaTrustDomain struct {
+	// FlatName NetBIOS name of the domain
+	FlatName *string `json:"flat_name,omitempty"`
+
+	// Name A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
+	Name DomainName `json:"name"`
+
+	// Sid A Windows security identifier (SID) of a domain
+	Sid SecurityIdentifier `json:"sid"`
+}
+
+// DomainIpaTrustIdRange ID range that maps the identities of a trusted domain
+type DomainIpaTrustIdRange struct {
+	// BaseId First POSIX ID of the range
+	BaseId int64 `json:"base_id"`
+
+	// Name Name of the ID range
+	Name string `json:"name"`
+
+	// RangeSize Number of IDs in the range
+	RangeSize int64 `json:"range_size"`
+
+	// Type Type of an ID range
+	Type IdRangeType `json:"type"`
+}
+
 // DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
 type DomainName = string
 
@@ -106,29 +361,83 @@ type DomainRegToken struct {
 	// DomainId A domain id
 	DomainId DomainId `json:"domain_id"`
 
+	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
+	DomainName *DomainName `json:"domain_name,omitempty"`
+
 	// DomainToken A domain registration token string
 	DomainToken string `json:"domain_token"`
 
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType DomainType `json:"domain_type"`
 
 	// Expiration Expiration time stamp (Unix timestamp)
 	Expiration int `json:"expiration"`
+
+	// Label A note set by the user who requests the token; it is the default description of the registered domain.
+	Label *string `json:"label,omitempty"`
+
+	// SubscriptionManagerId A Red Hat Subcription Manager ID of a RHEL host.
+	SubscriptionManagerId *SubscriptionManagerId `json:"subscription_manager_id,omitempty"`
 }
 
 // DomainRegTokenRequest A domain registration request
 type DomainRegTokenRequest struct {
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
+	DomainName *DomainName `json:"domain_name,omitempty"`
+
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType DomainType `json:"domain_type"`
+
+	// Label A note set by the user who requests the token; it is the default description of the registered domain.
+	Label *string `json:"label,omitempty"`
+
+	// SubscriptionManagerId A Red Hat Subcription Manager ID of a RHEL host.
+	SubscriptionManagerId *SubscriptionManagerId `json:"subscription_manager_id,omitempty"`
 }
 
 // DomainRegisterResponse A domain resource
 type DomainRegisterResponse = Domain
 
+// DomainRegistration The registration of a domain which requires approval; it records the server which registered the domain and the decision.
+type DomainRegistration struct {
+	// CaCerts CA certificates of the domain at the time of the registration.
+	CaCerts []Certificate `json:"ca_certs"`
+
+	// DecidedAt Time when the registration was approved or rejected.
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+
+	// DecidedBy User or service account which approved or rejected the registration.
+	DecidedBy *string `json:"decided_by,omitempty"`
+
+	// Fqdn A host's Fully Qualified Domain Name (all lower-case).
+	Fqdn *Fqdn `json:"fqdn,omitempty"`
+
+	// IpaHccVersion Version of ipa-hcc on the registering server.
+	IpaHccVersion string `json:"ipa_hcc_version"`
+
+	// IpaVersion Version of ipa on the registering server.
+	IpaVersion string `json:"ipa_version"`
+
+	// OsReleaseId Operating system id of the registering server.
+	OsReleaseId string `json:"os_release_id"`
+
+	// OsReleaseVersionId Operating system version of the registering server.
+	OsReleaseVersionId string `json:"os_release_version_id"`
+
+	// Reason Reason given for the decision.
+	Reason *string `json:"reason,omitempty"`
+
+	// RegisteredAt Time when the domain was registered.
+	RegisteredAt time.Time `json:"registered_at"`
+
+	// SubscriptionManagerId A Red Hat Subcription Manager ID of a RHEL host.
+	SubscriptionManagerId SubscriptionManagerId `json:"subscription_manager_id"`
+}
+
 // DomainResponse A domain resource
 type DomainResponse = Domain
 
-// DomainType Type of domain (currently only rhel-idm)
+// DomainType Type of domain (rhel-idm or active-directory)
 type DomainType string
 
 // DomainUpdateResponse A domain resource
@@ -163,14 +472,50 @@ type Fqdn = string
 
 // HostConf Represent the request payload for the /host-conf/:inventory_id/:fqdn endpoint.
 type HostConf struct {
+	// ClientSubnet IP address or subnet in CIDR notation of the host, used to find its location when 'location' is not provided
+	ClientSubnet *string `json:"client_subnet,omitempty"`
+
 	// DomainId A domain id
 	DomainId *DomainId `json:"domain_id,omitempty"`
 
 	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
 	DomainName *DomainName `json:"domain_name,omitempty"`
 
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType *DomainType `json:"domain_type,omitempty"`
+
+	// Location A location identifier (lower-case DNS label)
+	Location *LocationName `json:"location,omitempty"`
+
+	// LocationPolicy How the location of the host selects the enrollment servers: 'prefer' lists the servers in the location first, followed by the rest as fallback; 'restrict' only lists the servers in the location, unless it has none.
+	LocationPolicy *LocationPolicy `json:"location_policy,omitempty"`
+}
+
+// HostConfActiveDirectory Options for active-directory domains to join with realmd and adcli
+type HostConfActiveDirectory struct {
+	// ClientSoftware Client software for 'realm join --client-software'
+	ClientSoftware string `json:"client_software"`
+
+	// DomainControllers List of domain controllers for this domain.
+	DomainControllers []HostConfActiveDirectoryController `json:"domain_controllers"`
+
+	// MembershipSoftware Membership software for 'realm join --membership-software'
+	MembershipSoftware string `json:"membership_software"`
+
+	// RealmJoinArgs List of additional arguments for 'realm join'
+	RealmJoinArgs *[]string `json:"realm_join_args,omitempty"`
+
+	// RealmName A Kerberos realm name (usually all upper-case domain name). The realm can only be set during initial registration and not be modified by updates.
+	RealmName RealmName `json:"realm_name"`
+}
+
+// HostConfActiveDirectoryController Domain controller for an active-directory domain.
+type HostConfActiveDirectoryController struct {
+	// Fqdn A host's Fully Qualified Domain Name (all lower-case).
+	Fqdn Fqdn `json:"fqdn"`
+
+	// Site A location identifier (lower-case DNS label)
+	Site *LocationName `json:"site,omitempty"`
 }
 
 // HostConfIpa Options for ipa domains
@@ -189,6 +534,9 @@ type HostConfIpa struct {
 
 	// RealmName A Kerberos realm name (usually all upper-case domain name). The realm can only be set during initial registration and not be modified by updates.
 	RealmName RealmName `json:"realm_name"`
+
+	// Trusts List of AD forests trusted by the IdM domain
+	Trusts *[]HostConfIpaTrust `json:"trusts,omitempty"`
 }
 
 // HostConfIpaServer Auto-enrollment enabled server for this domain.
@@ -200,8 +548,23 @@ type HostConfIpaServer struct {
 	Location *LocationName `json:"location,omitempty"`
 }
 
+// HostConfIpaTrust External realms that the host can expect through a trust
+type HostConfIpaTrust struct {
+	// Direction Direction of a trust between the IdM domain and an AD forest
+	Direction TrustDirection `json:"direction"`
+
+	// ForestName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
+	ForestName DomainName `json:"forest_name"`
+
+	// Realms List of the Kerberos realms of the trusted domains
+	Realms []RealmName `json:"realms"`
+}
+
 // HostConfResponseSchema The response for the action to retrieve the host vm information when it is being enrolled. This action is taken from the host vm.
 type HostConfResponseSchema struct {
+	// ActiveDirectory Options for active-directory domains to join with realmd and adcli
+	ActiveDirectory *HostConfActiveDirectory `json:"active-directory,omitempty"`
+
 	// AutoEnrollmentEnabled Enable or disable host vm auto-enrollment for this domain
 	AutoEnrollmentEnabled bool `json:"auto_enrollment_enabled"`
 
@@ -211,11 +574,11 @@ type HostConfResponseSchema struct {
 	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
 	DomainName DomainName `json:"domain_name"`
 
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType DomainType `json:"domain_type"`
 
 	// RhelIdm Options for ipa domains
-	RhelIdm HostConfIpa `json:"rhel-idm"`
+	RhelIdm *HostConfIpa `json:"rhel-idm,omitempty"`
 
 	// Token A serialized JWS token or JWT to authenticate a host registration request.
 	Token *HostToken `json:"token,omitempty"`
@@ -227,6 +590,9 @@ type HostId = openapi_types.UUID
 // HostToken A serialized JWS token or JWT to authenticate a host registration request.
 type HostToken = string
 
+// IdRangeType Type of an ID range
+type IdRangeType string
+
 // ListDomainsData The data listed for the domains.
 type ListDomainsData struct {
 	AutoEnrollmentEnabled bool `json:"auto_enrollment_enabled"`
@@ -240,9 +606,12 @@ type ListDomainsData struct {
 	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
 	DomainName DomainName `json:"domain_name"`
 
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType DomainType `json:"domain_type"`
 
+	// RegistrationState State of the registration of a domain; only the active domains can configure hosts. A domain registered by an organization which requires approval is pending approval until an organization administrator approves or rejects it.
+	RegistrationState *RegistrationState `json:"registration_state,omitempty"`
+
 	// Title Human-friendly title for the domain entry.
 	Title string `json:"title"`
 }
@@ -270,6 +639,23 @@ type Location struct {
 // LocationName A location identifier (lower-case DNS label)
 type LocationName = string
 
+// LocationPolicy How the location of the host selects the enrollment servers: 'prefer' lists the servers in the location first, followed by the rest as fallback; 'restrict' only lists the servers in the location, unless it has none.
+type LocationPolicy string
+
+// LocationSubnet Map the hosts of an IP subnet to a location of the domain.
+type LocationSubnet struct {
+	// Location A location identifier (lower-case DNS label)
+	Location LocationName `json:"location"`
+
+	// Subnet IPv4 or IPv6 subnet in CIDR notation
+	Subnet string `json:"subnet"`
+}
+
+// LocationSubnets List of subnet to location mappings of a domain, used to select the enrollment servers for the host-conf request.
+type LocationSubnets struct {
+	Subnets []LocationSubnet `json:"subnets"`
+}
+
 // PaginationLinks Represent the navigation links for the data paginated.
 type PaginationLinks struct {
 	// First Reference to the first page of the request.
@@ -297,12 +683,42 @@ type PaginationMeta struct {
 	Offset int `json:"offset"`
 }
 
+// Problem Error response following RFC 7807, returned when the request accepts application/problem+json.
+type Problem struct {
+	// Code Stable machine-readable error code.
+	Code string `json:"code"`
+
+	// Detail Human-readable explanation specific to this occurrence of the problem.
+	Detail *string `json:"detail,omitempty"`
+
+	// Instance The path of the request.
+	Instance *string `json:"instance,omitempty"`
+
+	// RequestId The X-Rh-Insights-Request-Id of the request.
+	RequestId *string `json:"request_id,omitempty"`
+
+	// Status The HTTP status code.
+	Status int32 `json:"status"`
+
+	// Title Short human-readable summary of the problem type.
+	Title string `json:"title"`
+
+	// Type URI reference that identifies the problem type.
+	Type string `json:"type"`
+}
+
 // RealmName A Kerberos realm name (usually all upper-case domain name). The realm can only be set during initial registration and not be modified by updates.
 type RealmName = string
 
 // RegisterDomainRequest A domain resource
 type RegisterDomainRequest = Domain
 
+// RegistrationState State of the registration of a domain; only the active domains can configure hosts. A domain registered by an organization which requires approval is pending approval until an organization administrator approves or rejects it.
+type RegistrationState string
+
+// SecurityIdentifier A Windows security identifier (SID) of a domain
+type SecurityIdentifier = string
+
 // SigningKeysResponse Serialized JWKs with revocation information
 type SigningKeysResponse struct {
 	// Keys An array of serialized JSON Web Keys (JWK strings)
@@ -315,16 +731,22 @@ type SigningKeysResponse struct {
 // SubscriptionManagerId A Red Hat Subcription Manager ID of a RHEL host.
 type SubscriptionManagerId = openapi_types.UUID
 
+// TrustDirection Direction of a trust between the IdM domain and an AD forest
+type TrustDirection string
+
 // UpdateDomainAgentRequest A domain resource
 type UpdateDomainAgentRequest struct {
+	// ActiveDirectory Options for active-directory domains
+	ActiveDirectory *DomainActiveDirectory `json:"active-directory,omitempty"`
+
 	// DomainName A name of a domain (all lower-case). The domain name can only be set during initial registration and not be modified by updates.
 	DomainName DomainName `json:"domain_name"`
 
-	// DomainType Type of domain (currently only rhel-idm)
+	// DomainType Type of domain (rhel-idm or active-directory)
 	DomainType DomainType `json:"domain_type"`
 
 	// RhelIdm Options for ipa domains
-	RhelIdm DomainIpa `json:"rhel-idm"`
+	RhelIdm *DomainIpa `json:"rhel-idm,omitempty"`
 }
 
 // UpdateDomainUserRequest A domain resource
@@ -339,9 +761,104 @@ type UpdateDomainUserRequest struct {
 	Title *string `json:"title,omitempty"`
 }
 
+// UpdateWebhookRequest A request to update a webhook; enabling a webhook reset its failed deliveries.
+type UpdateWebhookRequest struct {
+	// Enabled Indicate if the events are delivered to the webhook.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Events The events delivered to a webhook.
+	Events *WebhookEvents `json:"events,omitempty"`
+
+	// Url The https url which receive the events.
+	Url *WebhookUrl `json:"url,omitempty"`
+}
+
+// Webhook A webhook which receive the domain and host-conf events of the organization.
+type Webhook struct {
+	// ConsecutiveFailures The number of consecutive failed delivery attempts; the webhook is disabled when it reaches the failure threshold.
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	CreatedAt           time.Time `json:"created_at"`
+
+	// DisabledAt When the webhook was disabled by the failed deliveries.
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+
+	// Enabled Indicate if the events are delivered to the webhook; the webhook is disabled after repeated failed deliveries.
+	Enabled bool `json:"enabled"`
+
+	// Events The events delivered to a webhook.
+	Events WebhookEvents `json:"events"`
+
+	// Secret The key to verify the signature of the deliveries; it is only returned when the webhook is created.
+	Secret    *string   `json:"secret,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Url The https url which receive the events.
+	Url WebhookUrl `json:"url"`
+
+	// WebhookId The uuid that identify the webhook.
+	WebhookId WebhookId `json:"webhook_id"`
+}
+
+// WebhookDeliveries The most recent deliveries of a webhook.
+type WebhookDeliveries struct {
+	Data []WebhookDelivery `json:"data"`
+}
+
+// WebhookDelivery A delivery of an event to a webhook.
+type WebhookDelivery struct {
+	// Attempts The number of attempts to deliver the event.
+	Attempts    int        `json:"attempts"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+
+	// DeliveryId The uuid of the delivery; it is sent into the X-Idmsvc-Delivery header.
+	DeliveryId openapi_types.UUID `json:"delivery_id"`
+
+	// Error The error of the last attempt.
+	Error *string `json:"error,omitempty"`
+
+	// Event An event which is delivered to the webhooks.
+	Event WebhookEvent `json:"event"`
+
+	// NextAttemptAt When the next attempt is scheduled for a pending delivery.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	// ResponseStatus The HTTP status code of the last attempt.
+	ResponseStatus *int `json:"response_status,omitempty"`
+
+	// Status The state of a delivery.
+	Status WebhookDeliveryStatus `json:"status"`
+}
+
+// WebhookDeliveryStatus The state of a delivery.
+type WebhookDeliveryStatus string
+
+// WebhookEvent An event which is delivered to the webhooks.
+type WebhookEvent string
+
+// WebhookEvents The events delivered to a webhook.
+type WebhookEvents = []WebhookEvent
+
+// WebhookId The uuid that identify the webhook.
+type WebhookId = openapi_types.UUID
+
+// WebhookUrl The https url which receive the events.
+type WebhookUrl = string
+
+// Webhooks The webhooks of the organization.
+type Webhooks struct {
+	Data []Webhook `json:"data"`
+}
+
 // DomainIdParam A domain id
 type DomainIdParam = DomainId
 
+// IdempotencyKeyHeader defines model for IdempotencyKeyHeader.
+type IdempotencyKeyHeader = string
+
+// WebhookIdParam The uuid that identify the webhook.
+type WebhookIdParam = WebhookId
+
 // XRhIdmRegistrationTokenHeader defines model for XRhIdmRegistrationTokenHeader.
 type XRhIdmRegistrationTokenHeader = string
 
@@ -351,11 +868,20 @@ type XRhIdmVersionHeader = string
 // XRhInsightsRequestIdHeader defines model for XRhInsightsRequestIdHeader.
 type XRhInsightsRequestIdHeader = string
 
+// BatchDomainsResponse The results of a batch request, in the order of the operations.
+type BatchDomainsResponse = BatchDomainsResponseSchema
+
 // DomainRegTokenResponse A domain registration response
 type DomainRegTokenResponse = DomainRegToken
 
-// ErrorResponse General error response returned by the idmsvc API
-type ErrorResponse = Errors
+// ErrorResponseApplicationJSON General error response returned by the idmsvc API
+type ErrorResponseApplicationJSON = Errors
+
+// ErrorResponseApplicationProblemPlusJSON Error response following RFC 7807, returned when the request accepts application/problem+json.
+type ErrorResponseApplicationProblemPlusJSON = Problem
+
+// ExportDomainsResponse A versioned document with the domains of an organization, to back up or move them to other environment.
+type ExportDomainsResponse = DomainExport
 
 // HostConfResponse The response for the action to retrieve the host vm information when it is being enrolled. This action is taken from the host vm.
 type HostConfResponse = HostConfResponseSchema
@@ -363,6 +889,12 @@ type HostConfResponse = HostConfResponseSchema
 // ListDomainsResponse Represent a paginated result for a list of domains
 type ListDomainsResponse = ListDomainsResponseSchema
 
+// ListWebhooksResponse The webhooks of the organization.
+type ListWebhooksResponse = Webhooks
+
+// LocationSubnetsResponse List of subnet to location mappings of a domain, used to select the enrollment servers for the host-conf request.
+type LocationSubnetsResponse = LocationSubnets
+
 // ReadDomainResponse A domain resource
 type ReadDomainResponse = DomainResponse
 
@@ -378,6 +910,12 @@ type UpdateDomainAgentResponse = DomainUpdateResponse
 // UpdateDomainUserResponse A domain resource
 type UpdateDomainUserResponse = DomainResponse
 
+// WebhookDeliveriesResponse The most recent deliveries of a webhook.
+type WebhookDeliveriesResponse = WebhookDeliveries
+
+// WebhookResponse A webhook which receive the domain and host-conf events of the organization.
+type WebhookResponse = Webhook
+
 // ListDomainsParams defines parameters for ListDomains.
 type ListDomainsParams struct {
 	// Offset pagination offset
@@ -398,18 +936,33 @@ type RegisterDomainParams struct {
 	// XRhIdmVersion ipa-hcc agent version
 	XRhIdmVersion XRhIdmVersionHeader `json:"X-Rh-Idm-Version"`
 
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// ExportDomainsParams defines parameters for ExportDomains.
+type ExportDomainsParams struct {
 	// XRhInsightsRequestId Request id for distributed tracing.
 	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
 }
 
 // CreateDomainTokenParams defines parameters for CreateDomainToken.
 type CreateDomainTokenParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
 	// XRhInsightsRequestId Request id for distributed tracing.
 	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
 }
 
 // DeleteDomainParams defines parameters for DeleteDomain.
 type DeleteDomainParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
 	// XRhInsightsRequestId Request id for distributed tracing.
 	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
 }
@@ -422,6 +975,9 @@ type ReadDomainParams struct {
 
 // UpdateDomainUserParams defines parameters for UpdateDomainUser.
 type UpdateDomainUserParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
 	// XRhInsightsRequestId Request id for distributed tracing.
 	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
 }
@@ -431,6 +987,51 @@ type UpdateDomainAgentParams struct {
 	// XRhIdmVersion ipa-hcc agent version
 	XRhIdmVersion XRhIdmVersionHeader `json:"X-Rh-Idm-Version"`
 
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// ApproveDomainParams defines parameters for ApproveDomain.
+type ApproveDomainParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// ReadDomainLocationSubnetsParams defines parameters for ReadDomainLocationSubnets.
+type ReadDomainLocationSubnetsParams struct {
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// UpdateDomainLocationSubnetsParams defines parameters for UpdateDomainLocationSubnets.
+type UpdateDomainLocationSubnetsParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// RejectDomainParams defines parameters for RejectDomain.
+type RejectDomainParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// BatchDomainsParams defines parameters for BatchDomains.
+type BatchDomainsParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
 	// XRhInsightsRequestId Request id for distributed tracing.
 	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
 }
@@ -447,6 +1048,54 @@ type GetSigningKeysParams struct {
 	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
 }
 
+// ListWebhooksParams defines parameters for ListWebhooks.
+type ListWebhooksParams struct {
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// CreateWebhookParams defines parameters for CreateWebhook.
+type CreateWebhookParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// DeleteWebhookParams defines parameters for DeleteWebhook.
+type DeleteWebhookParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// ReadWebhookParams defines parameters for ReadWebhook.
+type ReadWebhookParams struct {
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// UpdateWebhookParams defines parameters for UpdateWebhook.
+type UpdateWebhookParams struct {
+	// IdempotencyKey Unique key generated by the client to retry the request safely; a retry with the same key gets the response of the first request.
+	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
+// ListWebhookDeliveriesParams defines parameters for ListWebhookDeliveries.
+type ListWebhookDeliveriesParams struct {
+	// Limit Number of deliveries
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// XRhInsightsRequestId Request id for distributed tracing.
+	XRhInsightsRequestId *XRhInsightsRequestIdHeader `json:"X-Rh-Insights-Request-Id,omitempty"`
+}
+
 // RegisterDomainJSONRequestBody defines body for RegisterDomain for application/json ContentType.
 type RegisterDomainJSONRequestBody = RegisterDomainRequest
 
@@ -459,5 +1108,23 @@ type UpdateDomainUserJSONRequestBody = UpdateDomainUserRequest
 // UpdateDomainAgentJSONRequestBody defines body for UpdateDomainAgent for application/json ContentType.
 type UpdateDomainAgentJSONRequestBody = UpdateDomainAgentRequest
 
+// ApproveDomainJSONRequestBody defines body for ApproveDomain for application/json ContentType.
+type ApproveDomainJSONRequestBody = DomainDecisionRequest
+
+// UpdateDomainLocationSubnetsJSONRequestBody defines body for UpdateDomainLocationSubnets for application/json ContentType.
+type UpdateDomainLocationSubnetsJSONRequestBody = LocationSubnets
+
+// RejectDomainJSONRequestBody defines body for RejectDomain for application/json ContentType.
+type RejectDomainJSONRequestBody = DomainDecisionRequest
+
+// BatchDomainsJSONRequestBody defines body for BatchDomains for application/json ContentType.
+type BatchDomainsJSONRequestBody = BatchDomainsRequest
+
 // HostConfJSONRequestBody defines body for HostConf for application/json ContentType.
 type HostConfJSONRequestBody = HostConf
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody = CreateWebhookRequest
+
+// UpdateWebhookJSONRequestBody defines body for UpdateWebhook for application/json ContentType.
+type UpdateWebhookJSONRequestBody = UpdateWebhookRequest
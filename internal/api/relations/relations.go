@@ -0,0 +1,62 @@
+// Package relations define the messages of the relationship-based
+// check API, modelled after the Kessel and SpiceDB check and
+// lookup APIs.
+package relations
+
+import "strings"
+
+const (
+	// PathCheck is the endpoint to check a relation of a subject
+	// with a resource.
+	PathCheck = "/check"
+	// PathLookupResources is the endpoint to list the resources
+	// where a subject has a relation.
+	PathLookupResources = "/lookup-resources"
+)
+
+const (
+	// TypePrincipal is the object type for the identities.
+	TypePrincipal = "rbac/principal"
+	// TypeWorkspace is the object type for the organizations; a
+	// relation on the workspace is granted for all its resources.
+	TypeWorkspace = "rbac/workspace"
+	// TypeDomain is the object type for the domains.
+	TypeDomain = "idmsvc/domain"
+)
+
+// ObjectReference identify a subject or a resource.
+type ObjectReference struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// CheckRequest is the body for PathCheck.
+type CheckRequest struct {
+	Subject  ObjectReference `json:"subject"`
+	Relation string          `json:"relation"`
+	Resource ObjectReference `json:"resource"`
+}
+
+// CheckResponse is the response for PathCheck.
+type CheckResponse struct {
+	Allowed bool `json:"allowed"`
+}
+
+// LookupResourcesRequest is the body for PathLookupResources.
+type LookupResourcesRequest struct {
+	Subject      ObjectReference `json:"subject"`
+	Relation     string          `json:"relation"`
+	ResourceType string          `json:"resource_type"`
+}
+
+// LookupResourcesResponse is the response for PathLookupResources.
+type LookupResourcesResponse struct {
+	Resources []ObjectReference `json:"resources"`
+}
+
+// RelationFromPermission translate a 'service:resource:verb'
+// permission to the relation name, for instance
+// 'idmsvc:domains:update' to 'idmsvc_domains_update'.
+func RelationFromPermission(permission string) string {
+	return strings.ReplaceAll(permission, ":", "_")
+}
@@ -0,0 +1,12 @@
+package relations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationFromPermission(t *testing.T) {
+	assert.Equal(t, "idmsvc_domains_update", RelationFromPermission("idmsvc:domains:update"))
+	assert.Equal(t, "", RelationFromPermission(""))
+}
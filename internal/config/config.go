@@ -36,9 +36,121 @@ const (
 	DefaultWebPort = 8000
 	// DefaultEnableRBAC is true
 	DefaultEnableRBAC = true
+	// DefaultMetricsStatsInterval is the time between the refresh
+	// of the domain statistics; 5 minutes by default
+	DefaultMetricsStatsInterval = time.Duration(5 * time.Minute)
+	// DefaultRbacCacheTTL is the time the ACLs retrieved from rbac
+	// are cached; 30 seconds by default
+	DefaultRbacCacheTTL = time.Duration(30 * time.Second)
+	// DefaultRbacTimeout is the timeout for every request to rbac
+	DefaultRbacTimeout = time.Duration(5 * time.Second)
+	// DefaultRbacRetries is the number of retries for a failed
+	// request to rbac
+	DefaultRbacRetries = 2
+	// DefaultRbacRetryBackoff is the delay before the first retry
+	DefaultRbacRetryBackoff = time.Duration(100 * time.Millisecond)
+	// DefaultRbacBreakerThreshold is the number of consecutive
+	// failures that open the rbac circuit breaker
+	DefaultRbacBreakerThreshold = 5
+	// DefaultRbacBreakerOpenTimeout is the time the rbac circuit
+	// breaker keeps open
+	DefaultRbacBreakerOpenTimeout = time.Duration(30 * time.Second)
+	// AuthzProviderRbac check the permissions with rbac v1
+	AuthzProviderRbac = "rbac"
+	// AuthzProviderRelations check the permissions with the
+	// relationship-based check API
+	AuthzProviderRelations = "relations"
+
+	// RateLimitBackendMemory keep the rate limit buckets in memory
+	RateLimitBackendMemory = "memory"
+	// RateLimitBackendPostgres keep the rate limit buckets into the
+	// database, shared by every replica
+	RateLimitBackendPostgres = "postgres"
+	// DefaultRateLimitEnabled is true
+	DefaultRateLimitEnabled = true
+
+	// EntitlementsProviderStub consider every organization entitled
+	EntitlementsProviderStub = "stub"
+	// EntitlementsProviderHTTP check the entitlements service
+	EntitlementsProviderHTTP = "http"
+	// DefaultEntitlementsBundle is the bundle which entitle an
+	// organization to use Directory & Domain Services
+	DefaultEntitlementsBundle = "rhel"
+	// DefaultEntitlementsCacheTTL is the time the entitlements of
+	// an organization are cached; 5 minutes by default
+	DefaultEntitlementsCacheTTL = time.Duration(5 * time.Minute)
+	// DefaultEntitlementsTimeout is the timeout for every request
+	// to the entitlements service; 5 seconds by default
+	DefaultEntitlementsTimeout = time.Duration(5 * time.Second)
+
+	// DefaultQuotaMaxDomains is the max domains for an organization
+	DefaultQuotaMaxDomains = 10
+	// DefaultQuotaMaxServersPerDomain is the max servers and domain
+	// controllers for a domain
+	DefaultQuotaMaxServersPerDomain = 50
+	// DefaultQuotaMaxHostConfPerDay is the max host-conf requests
+	// for an organization every day
+	DefaultQuotaMaxHostConfPerDay = 10000
+	// DefaultQuotaMaxWebhooks is the max webhooks for an
+	// organization
+	DefaultQuotaMaxWebhooks = 10
+
+	// DefaultHealthCacheTTL is the time the result of a health
+	// check is reused
+	DefaultHealthCacheTTL = time.Duration(5 * time.Second)
+	// DefaultHealthTimeout is the max duration of a health check
+	DefaultHealthTimeout = time.Duration(2 * time.Second)
+
+	// DefaultIdempotencyEnabled is true
+	DefaultIdempotencyEnabled = true
+	// DefaultIdempotencyTTL is the time the responses for an
+	// idempotency key are kept
+	DefaultIdempotencyTTL = time.Duration(24 * time.Hour)
+
+	// DefaultWebhooksEnabled is true
+	DefaultWebhooksEnabled = true
+	// DefaultWebhooksInterval is the time between the polls for the
+	// pending deliveries
+	DefaultWebhooksInterval = time.Duration(5 * time.Second)
+	// DefaultWebhooksTimeout is the max duration of a delivery
+	DefaultWebhooksTimeout = time.Duration(10 * time.Second)
+	// DefaultWebhooksMaxAttempts is the max attempts for a delivery
+	DefaultWebhooksMaxAttempts = 8
+	// DefaultWebhooksBackoff is the wait before the first retry,
+	// which is doubled on every retry
+	DefaultWebhooksBackoff = time.Duration(30 * time.Second)
+	// DefaultWebhooksMaxBackoff is the max wait between retries
+	DefaultWebhooksMaxBackoff = time.Duration(1 * time.Hour)
+	// DefaultWebhooksFailureThreshold is the number of consecutive
+	// failed delivery attempts which disable a webhook
+	DefaultWebhooksFailureThreshold = 10
+
+	// TracingExporterOTLP send the spans to an OTLP/HTTP collector
+	TracingExporterOTLP = "otlp"
+	// TracingExporterStdout print the spans to the standard output
+	TracingExporterStdout = "stdout"
+	// TracingExporterNone disables the tracing
+	TracingExporterNone = "none"
+	// DefaultTracingSampleRatio sample every trace
+	DefaultTracingSampleRatio = 1.0
 
 	// DefaultDatabaseMaxOpenConn is the default for max open database connections
 	DefaultDatabaseMaxOpenConn = 30
+	// DefaultDatabaseSlowQueryThreshold is the default duration from
+	// which the statements are logged as slow queries
+	DefaultDatabaseSlowQueryThreshold = time.Duration(200 * time.Millisecond)
+
+	// SchemaCheckFail stops the service at startup when the schema
+	// version is not compatible
+	SchemaCheckFail = "fail"
+	// SchemaCheckNotReady fails the readiness probe when the schema
+	// version is not compatible
+	SchemaCheckNotReady = "not-ready"
+	// SchemaCheckWarn only logs that the schema version is not
+	// compatible
+	SchemaCheckWarn = "warn"
+	// DefaultDatabaseSchemaCheck is not-ready
+	DefaultDatabaseSchemaCheck = SchemaCheckNotReady
 
 	// DefaultIdleTimeout 5 mins by default
 	DefaultIdleTimeout = time.Duration(5 * time.Minute)
@@ -69,6 +181,12 @@ var (
 	DefaultSizeLimitRequestHeader = (32 * 1024)
 	// DefaultSizeLimitRequestBody in bytes. Default 128KB
 	DefaultSizeLimitRequestBody = (128 * 1024)
+	// DefaultMetricsAgentVersions are the 'major.minor' versions of
+	// the ipa-hcc agent that label the business metrics by default
+	DefaultMetricsAgentVersions = []string{
+		"0.7", "0.8", "0.9", "0.10", "0.11", "0.12", "0.13",
+		"0.14", "0.15", "0.16", "0.17", "0.18", "0.19", "0.20",
+	}
 )
 
 type Config struct {
@@ -78,6 +196,7 @@ type Config struct {
 	Logging     Logging
 	Kafka       Kafka
 	Metrics     Metrics
+	Tracing     Tracing
 	Clients     Clients
 	Application Application `mapstructure:"app"`
 	// Secrets is an untagged field and filled out on load
@@ -97,6 +216,21 @@ type Database struct {
 	// https://stackoverflow.com/questions/54844546/how-to-unmarshal-golang-viper-snake-case-values
 	CACertPath   string `mapstructure:"ca_cert_path"`
 	MaxOpenConns int    `mapstructure:"max_open_conns"`
+	// SlowQueryThreshold is the duration from which the statements
+	// are logged as slow queries; 0 disable the slow query log.
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold" validate:"gte=0"`
+	// SchemaCheck select what happens when the schema version of
+	// the database does not match the migrations of the binary:
+	// 'fail' stops the service at startup, 'not-ready' (default)
+	// fails the readiness probe, and 'warn' only logs it.
+	SchemaCheck string `mapstructure:"schema_check" validate:"omitempty,oneof=fail not-ready warn"`
+	// SchemaMinVersion is the oldest schema version compatible with
+	// the binary; zero means the latest migration of the binary.
+	SchemaMinVersion uint64 `mapstructure:"schema_min_version"`
+	// SchemaMaxVersion is the newest schema version compatible with
+	// the binary; zero means any newer version, so it keeps serving
+	// while a rolling deployment applies newer migrations.
+	SchemaMaxVersion uint64 `mapstructure:"schema_max_version" validate:"omitempty,gtefield=SchemaMinVersion"`
 }
 
 type Cloudwatch struct {
@@ -172,6 +306,32 @@ type Metrics struct {
 	// Defines the metrics port that the app should be configured to listen on for
 	// metric traffic.
 	Port int `mapstructure:"port"`
+
+	// StatsInterval is the time between the refresh of the domain
+	// statistics (total domains, servers per domain and CA
+	// certificates near to expire).
+	StatsInterval time.Duration `mapstructure:"stats_interval" validate:"omitempty,gte=1s,lte=24h"`
+
+	// AgentVersions are the 'major.minor' versions of the ipa-hcc
+	// agent used as label by the business metrics; any other
+	// version is labeled as 'other', so the series stay bounded.
+	AgentVersions []string `mapstructure:"agent_versions" validate:"max=64,dive,required,max=7"`
+}
+
+// Tracing hold the configuration for the OpenTelemetry traces.
+type Tracing struct {
+	// Exporter select where the spans are sent; 'otlp' to an
+	// OpenTelemetry collector, 'stdout' for development, or 'none'
+	// (default) to disable the tracing.
+	Exporter string `mapstructure:"exporter" validate:"omitempty,oneof=otlp stdout none"`
+	// Endpoint is the host:port of the OTLP/HTTP collector, used
+	// when Exporter is 'otlp'.
+	Endpoint string `mapstructure:"endpoint" validate:"required_if=Exporter otlp"`
+	// Insecure disables TLS for the OTLP collector.
+	Insecure bool `mapstructure:"insecure"`
+	// SampleRatio is the fraction of the traces sampled, when the
+	// parent span does not decide it.
+	SampleRatio float64 `mapstructure:"sample_ratio" validate:"gte=0,lte=1"`
 }
 
 // Clients gather all the configuration to properly setup
@@ -179,6 +339,46 @@ type Metrics struct {
 type Clients struct {
 	// RbacBaseURL is the base endpoint to launch RBAC requests.
 	RbacBaseURL string `mapstructure:"rbac_base_url"`
+	// RbacCacheTTL is the time the ACLs retrieved from rbac are kept
+	// in memory; zero disables the cache.
+	RbacCacheTTL time.Duration `mapstructure:"rbac_cache_ttl" validate:"gte=0,lte=1h"`
+	// RbacTimeout is the timeout for every request to the
+	// authorization service; zero means no timeout.
+	RbacTimeout time.Duration `mapstructure:"rbac_timeout" validate:"gte=0,lte=1m"`
+	// RbacRetries is the number of times a request to rbac is
+	// retried when rbac is unavailable.
+	RbacRetries int `mapstructure:"rbac_retries" validate:"gte=0,lte=5"`
+	// RbacRetryBackoff is the delay before the first retry; it is
+	// doubled on every retry, with a random jitter.
+	RbacRetryBackoff time.Duration `mapstructure:"rbac_retry_backoff" validate:"gte=0,lte=10s"`
+	// RbacBreakerThreshold is the number of consecutive failures
+	// that open the rbac circuit breaker; zero disables it.
+	RbacBreakerThreshold int `mapstructure:"rbac_breaker_threshold" validate:"gte=0"`
+	// RbacBreakerOpenTimeout is the time the rbac circuit breaker
+	// keeps open before probing rbac again.
+	RbacBreakerOpenTimeout time.Duration `mapstructure:"rbac_breaker_open_timeout" validate:"gte=0,lte=10m"`
+	// AuthzProvider select the backend used to authorize the
+	// requests; 'rbac' (default) or 'relations'.
+	AuthzProvider string `mapstructure:"authz_provider" validate:"omitempty,oneof=rbac relations"`
+	// RelationsBaseURL is the base endpoint of the relationship-based
+	// check API, used when AuthzProvider is 'relations'.
+	RelationsBaseURL string `mapstructure:"relations_base_url" validate:"required_if=AuthzProvider relations"`
+	// EntitlementsProvider select the source of the entitlements;
+	// 'stub' (default) entitle every organization, 'http' check the
+	// entitlements service.
+	EntitlementsProvider string `mapstructure:"entitlements_provider" validate:"omitempty,oneof=stub http"`
+	// EntitlementsBaseURL is the base endpoint of the entitlements
+	// service, used when EntitlementsProvider is 'http'.
+	EntitlementsBaseURL string `mapstructure:"entitlements_base_url" validate:"required_if=EntitlementsProvider http"`
+	// EntitlementsBundle is the bundle checked to entitle an
+	// organization.
+	EntitlementsBundle string `mapstructure:"entitlements_bundle"`
+	// EntitlementsCacheTTL is the time the entitlements of an
+	// organization are kept in memory; zero disables the cache.
+	EntitlementsCacheTTL time.Duration `mapstructure:"entitlements_cache_ttl" validate:"gte=0,lte=24h"`
+	// EntitlementsTimeout is the timeout for every request to the
+	// entitlements service; zero means no timeout.
+	EntitlementsTimeout time.Duration `mapstructure:"entitlements_timeout" validate:"gte=0,lte=1m"`
 	// PendoBaseURL is the base url to reach out the pendo API.
 	PendoBaseURL string `mapstructure:"pendo_base_url"`
 	// PendoAPIKey indicates the shared key to communicate with the API.
@@ -217,6 +417,11 @@ type Application struct {
 	// token and encrypted private JWKs.
 	// Secrets are derived with HKDF-SHA256.
 	MainSecret string `mapstructure:"secret" validate:"required,base64rawurl" json:"-"`
+	// PreviousSecrets are the main secrets used before the last
+	// rotations, from the newest to the oldest. The registration tokens
+	// are verified and the private JWKs are decrypted with every
+	// generation, but only the current one signs and encrypts.
+	PreviousSecrets []string `mapstructure:"previous_secrets" validate:"dive,base64rawurl" json:"-"`
 	// Flag to enable/disable rbac
 	EnableRBAC bool `mapstructure:"enable_rbac"`
 	// IdleTimeout for the API endpoints.
@@ -229,6 +434,121 @@ type Application struct {
 	SizeLimitRequestHeader int `mapstructure:"size_limit_request_header"`
 	// SizeLimitRequestBody for the API endpoints.
 	SizeLimitRequestBody int `mapstructure:"size_limit_request_body"`
+	// RateLimit for the public API endpoints.
+	RateLimit RateLimit `mapstructure:"rate_limit"`
+	// Quotas is the default quotas for every organization; they
+	// can be overridden per organization into the database.
+	Quotas Quotas `mapstructure:"quotas"`
+	// Health configure the checks of the readiness and liveness
+	// probes.
+	Health Health `mapstructure:"health"`
+	// Idempotency configure the Idempotency-Key header for the
+	// mutating operations of the public API.
+	Idempotency Idempotency `mapstructure:"idempotency"`
+	// Webhooks configure the delivery of the domain events to the
+	// webhooks of the organizations.
+	Webhooks Webhooks `mapstructure:"webhooks"`
+	// RegistrationApproval require an organization administrator to
+	// approve the registered domains before they configure hosts; it
+	// can be overridden per organization into the database.
+	RegistrationApproval bool `mapstructure:"registration_approval"`
+}
+
+// Webhooks hold the configuration for the delivery of the webhooks.
+type Webhooks struct {
+	// Enabled run the service which deliver the events.
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is the time between the polls for the pending
+	// deliveries.
+	Interval time.Duration `mapstructure:"interval" validate:"gte=0,lte=1h"`
+	// Timeout is the max duration of a delivery.
+	Timeout time.Duration `mapstructure:"timeout" validate:"gte=0,lte=1m"`
+	// MaxAttempts is the max attempts for a delivery before it is
+	// marked as failed.
+	MaxAttempts int `mapstructure:"max_attempts" validate:"gte=0,lte=100"`
+	// Backoff is the wait before the first retry; it is doubled on
+	// every retry up to MaxBackoff.
+	Backoff time.Duration `mapstructure:"backoff" validate:"gte=0,lte=24h"`
+	// MaxBackoff is the max wait between retries.
+	MaxBackoff time.Duration `mapstructure:"max_backoff" validate:"gte=0,lte=168h"`
+	// FailureThreshold is the number of consecutive failed delivery
+	// attempts which disable a webhook; zero never disable it.
+	FailureThreshold int `mapstructure:"failure_threshold" validate:"gte=0"`
+	// AllowHTTP accept webhook urls without TLS; it is intended
+	// for local test receivers.
+	AllowHTTP bool `mapstructure:"allow_http"`
+	// AllowPrivateNetworks accept webhook urls with an ip address or
+	// a local name, and deliveries to loopback, private and other
+	// non public addresses; it is intended for local test
+	// receivers.
+	AllowPrivateNetworks bool `mapstructure:"allow_private_networks"`
+}
+
+// Idempotency hold the configuration for the idempotency keys.
+type Idempotency struct {
+	// Enabled add the idempotency middleware to the public API.
+	Enabled bool `mapstructure:"enabled"`
+	// TTL is the time the response for an idempotency key is
+	// replayed; a zero value disables the idempotency keys.
+	TTL time.Duration `mapstructure:"ttl" validate:"gte=0,lte=168h"`
+}
+
+// Health hold the configuration for the health checks of the
+// dependencies.
+type Health struct {
+	// CacheTTL is the time the result of a check is reused, so the
+	// probes do not load the dependencies.
+	CacheTTL time.Duration `mapstructure:"cache_ttl" validate:"gte=0,lte=5m"`
+	// Timeout is the max duration of every check.
+	Timeout time.Duration `mapstructure:"timeout" validate:"gte=0,lte=1m"`
+}
+
+// Quotas hold the limits for the resources of an organization;
+// a zero value means unlimited.
+type Quotas struct {
+	// MaxDomains is the max domains registered by an organization.
+	MaxDomains int `mapstructure:"max_domains" validate:"gte=0"`
+	// MaxServersPerDomain is the max servers and domain controllers
+	// for a domain.
+	MaxServersPerDomain int `mapstructure:"max_servers_per_domain" validate:"gte=0"`
+	// MaxHostConfPerDay is the max host-conf requests for an
+	// organization every day (UTC).
+	MaxHostConfPerDay int `mapstructure:"max_host_conf_per_day" validate:"gte=0"`
+	// MaxWebhooks is the max webhooks registered by an organization.
+	MaxWebhooks int `mapstructure:"max_webhooks" validate:"gte=0"`
+}
+
+// RateLimit hold the limits for the requests to the public API,
+// for every rate class of the security policy.
+type RateLimit struct {
+	// Enabled add the rate limit middleware to the public API.
+	Enabled bool `mapstructure:"enabled"`
+	// Backend keep the token buckets; 'memory' (default) for every
+	// replica, or 'postgres' to share them between replicas.
+	Backend string `mapstructure:"backend" validate:"omitempty,oneof=memory postgres"`
+	// Read is the limit for the operations which only read data.
+	Read RateLimitClass `mapstructure:"read"`
+	// Write is the limit for the operations which modify data on
+	// behalf of a user.
+	Write RateLimitClass `mapstructure:"write"`
+	// Agent is the limit for the operations called by the ipa-hcc
+	// agents.
+	Agent RateLimitClass `mapstructure:"agent"`
+}
+
+// RateLimitClass hold the token buckets configuration for a rate
+// class; a zero rate or burst disables that limit.
+type RateLimitClass struct {
+	// OrgRate is the requests per second for an organization.
+	OrgRate float64 `mapstructure:"org_rate" validate:"gte=0"`
+	// OrgBurst is the requests allowed at once for an organization.
+	OrgBurst int `mapstructure:"org_burst" validate:"gte=0"`
+	// IdentityRate is the requests per second for an user, service
+	// account or system.
+	IdentityRate float64 `mapstructure:"identity_rate" validate:"gte=0"`
+	// IdentityBurst is the requests allowed at once for an user,
+	// service account or system.
+	IdentityBurst int `mapstructure:"identity_burst" validate:"gte=0"`
 }
 
 var config *Config = nil
@@ -256,6 +576,10 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.name", "")
 	v.SetDefault("database.ca_cert_path", "")
 	v.SetDefault("database.max_open_conns", DefaultDatabaseMaxOpenConn)
+	v.SetDefault("database.slow_query_threshold", DefaultDatabaseSlowQueryThreshold)
+	v.SetDefault("database.schema_check", DefaultDatabaseSchemaCheck)
+	v.SetDefault("database.schema_min_version", 0)
+	v.SetDefault("database.schema_max_version", 0)
 
 	// Kafka
 	addEventConfigDefaults(v)
@@ -266,6 +590,16 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("logging.location", false)
 	v.SetDefault("logging.type", "null")
 
+	// Metrics
+	v.SetDefault("metrics.stats_interval", DefaultMetricsStatsInterval)
+	v.SetDefault("metrics.agent_versions", DefaultMetricsAgentVersions)
+
+	// Tracing
+	v.SetDefault("tracing.exporter", TracingExporterNone)
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.insecure", false)
+	v.SetDefault("tracing.sample_ratio", DefaultTracingSampleRatio)
+
 	// Cloudwatch
 	v.SetDefault("logging.cloudwatch.region", "")
 	v.SetDefault("logging.cloudwatch.group", "")
@@ -276,6 +610,19 @@ func setDefaults(v *viper.Viper) {
 
 	// Clients
 	v.SetDefault("clients.rbac_base_url", "")
+	v.SetDefault("clients.rbac_cache_ttl", DefaultRbacCacheTTL)
+	v.SetDefault("clients.rbac_timeout", DefaultRbacTimeout)
+	v.SetDefault("clients.rbac_retries", DefaultRbacRetries)
+	v.SetDefault("clients.rbac_retry_backoff", DefaultRbacRetryBackoff)
+	v.SetDefault("clients.rbac_breaker_threshold", DefaultRbacBreakerThreshold)
+	v.SetDefault("clients.rbac_breaker_open_timeout", DefaultRbacBreakerOpenTimeout)
+	v.SetDefault("clients.authz_provider", AuthzProviderRbac)
+	v.SetDefault("clients.relations_base_url", "")
+	v.SetDefault("clients.entitlements_provider", EntitlementsProviderStub)
+	v.SetDefault("clients.entitlements_base_url", "")
+	v.SetDefault("clients.entitlements_bundle", DefaultEntitlementsBundle)
+	v.SetDefault("clients.entitlements_cache_ttl", DefaultEntitlementsCacheTTL)
+	v.SetDefault("clients.entitlements_timeout", DefaultEntitlementsTimeout)
 	v.SetDefault("clients.pendo_base_url", "")
 	v.SetDefault("clients.pendo_api_key", "")
 	v.SetDefault("clients.pendo_track_event_key", "")
@@ -295,6 +642,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("app.enable_rbac", DefaultEnableRBAC)
 	v.SetDefault("app.url_path_prefix", DefaultPathPrefix)
 	v.SetDefault("app.secret", "")
+	v.SetDefault("app.previous_secrets", []string{})
 	v.SetDefault("app.debug", false)
 
 	// Timeouts and limits
@@ -303,6 +651,38 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("app.write_timeout", DefaultWriteTimeout)
 	v.SetDefault("app.size_limit_request_header", DefaultSizeLimitRequestHeader)
 	v.SetDefault("app.size_limit_request_body", DefaultSizeLimitRequestBody)
+	v.SetDefault("app.rate_limit.enabled", DefaultRateLimitEnabled)
+	v.SetDefault("app.rate_limit.backend", RateLimitBackendMemory)
+	v.SetDefault("app.rate_limit.read.org_rate", 50)
+	v.SetDefault("app.rate_limit.read.org_burst", 100)
+	v.SetDefault("app.rate_limit.read.identity_rate", 10)
+	v.SetDefault("app.rate_limit.read.identity_burst", 20)
+	v.SetDefault("app.rate_limit.write.org_rate", 10)
+	v.SetDefault("app.rate_limit.write.org_burst", 20)
+	v.SetDefault("app.rate_limit.write.identity_rate", 2)
+	v.SetDefault("app.rate_limit.write.identity_burst", 5)
+	v.SetDefault("app.rate_limit.agent.org_rate", 20)
+	v.SetDefault("app.rate_limit.agent.org_burst", 50)
+	v.SetDefault("app.rate_limit.agent.identity_rate", 5)
+	v.SetDefault("app.rate_limit.agent.identity_burst", 20)
+	v.SetDefault("app.quotas.max_domains", DefaultQuotaMaxDomains)
+	v.SetDefault("app.quotas.max_servers_per_domain", DefaultQuotaMaxServersPerDomain)
+	v.SetDefault("app.quotas.max_host_conf_per_day", DefaultQuotaMaxHostConfPerDay)
+	v.SetDefault("app.quotas.max_webhooks", DefaultQuotaMaxWebhooks)
+	v.SetDefault("app.health.cache_ttl", DefaultHealthCacheTTL)
+	v.SetDefault("app.health.timeout", DefaultHealthTimeout)
+	v.SetDefault("app.idempotency.enabled", DefaultIdempotencyEnabled)
+	v.SetDefault("app.idempotency.ttl", DefaultIdempotencyTTL)
+	v.SetDefault("app.webhooks.enabled", DefaultWebhooksEnabled)
+	v.SetDefault("app.webhooks.interval", DefaultWebhooksInterval)
+	v.SetDefault("app.webhooks.timeout", DefaultWebhooksTimeout)
+	v.SetDefault("app.webhooks.max_attempts", DefaultWebhooksMaxAttempts)
+	v.SetDefault("app.webhooks.backoff", DefaultWebhooksBackoff)
+	v.SetDefault("app.webhooks.max_backoff", DefaultWebhooksMaxBackoff)
+	v.SetDefault("app.webhooks.failure_threshold", DefaultWebhooksFailureThreshold)
+	v.SetDefault("app.webhooks.allow_http", false)
+	v.SetDefault("app.webhooks.allow_private_networks", false)
+	v.SetDefault("app.registration_approval", false)
 }
 
 func setClowderConfiguration(v *viper.Viper, clowderConfig *clowder.AppConfig) {
@@ -524,6 +904,10 @@ func (c *Config) Log(logger *slog.Logger) {
 			slog.String("Name", c.Database.Name),
 			slog.String("CACertPath", c.Database.CACertPath),
 			slog.Int("MaxOpenConns", c.Database.MaxOpenConns),
+			slog.Duration("SlowQueryThreshold", c.Database.SlowQueryThreshold),
+			slog.String("SchemaCheck", c.Database.SchemaCheck),
+			slog.Uint64("SchemaMinVersion", c.Database.SchemaMinVersion),
+			slog.Uint64("SchemaMaxVersion", c.Database.SchemaMaxVersion),
 		),
 		slog.Group("Logging",
 			slog.String("Level", c.Logging.Level),
@@ -542,9 +926,30 @@ func (c *Config) Log(logger *slog.Logger) {
 		slog.Group("Metrics",
 			slog.String("Path", c.Metrics.Path),
 			slog.Int("Port", c.Metrics.Port),
+			slog.Duration("StatsInterval", c.Metrics.StatsInterval),
+			slog.Any("AgentVersions", c.Metrics.AgentVersions),
+		),
+		slog.Group("Tracing",
+			slog.String("Exporter", c.Tracing.Exporter),
+			slog.String("Endpoint", c.Tracing.Endpoint),
+			slog.Bool("Insecure", c.Tracing.Insecure),
+			slog.Float64("SampleRatio", c.Tracing.SampleRatio),
 		),
 		slog.Group("Clients",
 			slog.String("RbacBaseURL", c.Clients.RbacBaseURL),
+			slog.Duration("RbacCacheTTL", c.Clients.RbacCacheTTL),
+			slog.Duration("RbacTimeout", c.Clients.RbacTimeout),
+			slog.Int("RbacRetries", c.Clients.RbacRetries),
+			slog.Duration("RbacRetryBackoff", c.Clients.RbacRetryBackoff),
+			slog.Int("RbacBreakerThreshold", c.Clients.RbacBreakerThreshold),
+			slog.Duration("RbacBreakerOpenTimeout", c.Clients.RbacBreakerOpenTimeout),
+			slog.String("AuthzProvider", c.Clients.AuthzProvider),
+			slog.String("RelationsBaseURL", c.Clients.RelationsBaseURL),
+			slog.String("EntitlementsProvider", c.Clients.EntitlementsProvider),
+			slog.String("EntitlementsBaseURL", c.Clients.EntitlementsBaseURL),
+			slog.String("EntitlementsBundle", c.Clients.EntitlementsBundle),
+			slog.Duration("EntitlementsCacheTTL", c.Clients.EntitlementsCacheTTL),
+			slog.Duration("EntitlementsTimeout", c.Clients.EntitlementsTimeout),
 			slog.String("PendoBaseURL", c.Clients.PendoBaseURL),
 			slog.String("PendoAPIKey", obfuscateSecret(c.Clients.PendoAPIKey)),
 			slog.String("PendoTrackEventKey", obfuscateSecret(c.Clients.PendoTrackEventKey)),
@@ -561,12 +966,41 @@ func (c *Config) Log(logger *slog.Logger) {
 			slog.Bool("AcceptXRHFakeIdentity", c.Application.AcceptXRHFakeIdentity),
 			slog.Bool("ValidateAPI", c.Application.ValidateAPI),
 			slog.String("MainSecret", obfuscateSecret(c.Application.MainSecret)),
+			slog.Int("PreviousSecrets", len(c.Application.PreviousSecrets)),
 			slog.Bool("EnableRBAC", c.Application.EnableRBAC),
 			slog.Duration("IdleTimeout", c.Application.IdleTimeout),
 			slog.Duration("ReadTimeout", c.Application.ReadTimeout),
 			slog.Duration("WriteTimeout", c.Application.WriteTimeout),
 			slog.Int("SizeLimitRequestHeader", c.Application.SizeLimitRequestHeader),
 			slog.Int("SizeLimitRequestBody", c.Application.SizeLimitRequestBody),
+			slog.Group("RateLimit",
+				slog.Bool("Enabled", c.Application.RateLimit.Enabled),
+				slog.String("Backend", c.Application.RateLimit.Backend),
+				slog.Any("Read", c.Application.RateLimit.Read),
+				slog.Any("Write", c.Application.RateLimit.Write),
+				slog.Any("Agent", c.Application.RateLimit.Agent),
+			),
+			slog.Any("Quotas", c.Application.Quotas),
+			slog.Group("Health",
+				slog.Duration("CacheTTL", c.Application.Health.CacheTTL),
+				slog.Duration("Timeout", c.Application.Health.Timeout),
+			),
+			slog.Group("Idempotency",
+				slog.Bool("Enabled", c.Application.Idempotency.Enabled),
+				slog.Duration("TTL", c.Application.Idempotency.TTL),
+			),
+			slog.Group("Webhooks",
+				slog.Bool("Enabled", c.Application.Webhooks.Enabled),
+				slog.Duration("Interval", c.Application.Webhooks.Interval),
+				slog.Duration("Timeout", c.Application.Webhooks.Timeout),
+				slog.Int("MaxAttempts", c.Application.Webhooks.MaxAttempts),
+				slog.Duration("Backoff", c.Application.Webhooks.Backoff),
+				slog.Duration("MaxBackoff", c.Application.Webhooks.MaxBackoff),
+				slog.Int("FailureThreshold", c.Application.Webhooks.FailureThreshold),
+				slog.Bool("AllowHTTP", c.Application.Webhooks.AllowHTTP),
+				slog.Bool("AllowPrivateNetworks", c.Application.Webhooks.AllowPrivateNetworks),
+			),
+			slog.Bool("RegistrationApproval", c.Application.RegistrationApproval),
 		),
 	)
 }
@@ -611,7 +1045,10 @@ func Get() *Config {
 		panic("Invalid configuration")
 	}
 
-	sec, err := secrets.NewAppSecrets(config.Application.MainSecret)
+	sec, err := secrets.NewAppSecrets(
+		config.Application.MainSecret,
+		config.Application.PreviousSecrets...,
+	)
 	if err != nil {
 		panic(err)
 	}
@@ -33,6 +33,7 @@ func TestSetDefaults(t *testing.T) {
 	assert.Equal(t, PaginationMaxLimit, v.Get("app.pagination_max_limit"))
 
 	assert.Equal(t, DefaultEnableRBAC, v.Get("app.enable_rbac"))
+	assert.Equal(t, DefaultEntitlementsTimeout, v.Get("clients.entitlements_timeout"))
 }
 
 func TestSetClowderConfiguration(t *testing.T) {
@@ -155,6 +156,16 @@ func TestLoad(t *testing.T) {
 	})
 }
 
+func TestLoadPreviousSecrets(t *testing.T) {
+	previous := secrets.GenerateRandomMainSecret()
+	oldest := secrets.GenerateRandomMainSecret()
+	t.Setenv("APP_PREVIOUS_SECRETS", previous+","+oldest)
+
+	cfg := Config{}
+	Load(&cfg)
+	assert.Equal(t, []string{previous, oldest}, cfg.Application.PreviousSecrets)
+}
+
 func TestLog(t *testing.T) {
 	// Given
 	buf := &bytes.Buffer{}
@@ -183,8 +194,9 @@ func TestLog(t *testing.T) {
 			PendoTrackEventKey: "testpendotrackeventkey",
 		},
 		Application: Application{
-			Name:       "appname",
-			MainSecret: "testmainsecret",
+			Name:            "appname",
+			MainSecret:      "testmainsecret",
+			PreviousSecrets: []string{"testprevioussecret"},
 		},
 	}
 
@@ -204,6 +216,7 @@ func TestLog(t *testing.T) {
 	assert.Contains(t, loggedStr, "Clients.PendoTrackEventKey=***")
 	assert.Contains(t, loggedStr, "Application.Name=appname")
 	assert.Contains(t, loggedStr, "Application.MainSecret=***")
+	assert.Contains(t, loggedStr, "Application.PreviousSecrets=1")
 
 	// No password in the log
 	assert.NotContains(t, loggedStr, "testdbpassword")
@@ -211,6 +224,7 @@ func TestLog(t *testing.T) {
 	assert.NotContains(t, loggedStr, "testpendoapikey")
 	assert.NotContains(t, loggedStr, "testpendotrackeventkey")
 	assert.NotContains(t, loggedStr, "testmainsecret")
+	assert.NotContains(t, loggedStr, "testprevioussecret")
 }
 
 func TestValidateConfig(t *testing.T) {
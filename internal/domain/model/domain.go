@@ -1,7 +1,6 @@
 package model
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -11,14 +10,24 @@ import (
 
 // See: https://gorm.io/docs/models.html
 
+// The undefined domain type; the domain types register their id
+// and name with RegisterDomainType from their own package.
 const (
-	DomainTypeUndefined uint = iota
-	DomainTypeIpa
-	// DomainTypeAzure
-	// DomainTypeActiveDirector
+	DomainTypeUndefined       uint = 0
+	DomainTypeUndefinedString      = ""
+)
 
-	DomainTypeIpaString       = "rhel-idm"
-	DomainTypeUndefinedString = ""
+const (
+	// DomainRegistrationActive is the state of the domains which can
+	// configure hosts.
+	DomainRegistrationActive = "active"
+	// DomainRegistrationPendingApproval is the state of the domains
+	// registered by an organization which requires approval, until
+	// an organization administrator decides about them.
+	DomainRegistrationPendingApproval = "pending_approval"
+	// DomainRegistrationRejected is the state of the domains which
+	// registration was rejected; they are kept until they are deleted.
+	DomainRegistrationRejected = "rejected"
 )
 
 var (
@@ -40,25 +49,30 @@ type Domain struct {
 	Description           *string
 	Type                  *uint
 	AutoEnrollmentEnabled *bool
-	IpaDomain             *Ipa `gorm:"foreignKey:ID"`
+	// RegistrationState is any of the DomainRegistration* states.
+	RegistrationState string
+	// TypeData is the specific information of the domain type; it
+	// is owned by the package of the type, which fills it on preload.
+	TypeData     any                 `gorm:"-"`
+	Registration *DomainRegistration `gorm:"foreignKey:ID"`
 }
 
+// DomainTypeString return the domain_type name for a registered
+// domain type, or DomainTypeUndefinedString if it is not registered.
 func DomainTypeString(data uint) string {
-	switch data {
-	case DomainTypeIpa:
-		return DomainTypeIpaString
-	default:
-		return DomainTypeUndefinedString
+	if entry, ok := lookupDomainType(data); ok {
+		return entry.name
 	}
+	return DomainTypeUndefinedString
 }
 
+// DomainTypeUint return the domain type id for a registered
+// domain_type name, or DomainTypeUndefined if it is not registered.
 func DomainTypeUint(data string) uint {
-	switch data {
-	case DomainTypeIpaString:
-		return DomainTypeIpa
-	default:
-		return DomainTypeUndefined
+	if entry, ok := lookupDomainTypeByName(data); ok {
+		return entry.id
 	}
+	return DomainTypeUndefined
 }
 
 // See: https://gorm.io/docs/hooks.html
@@ -66,13 +80,8 @@ func (d *Domain) AfterCreate(tx *gorm.DB) (err error) {
 	if d.Type == nil {
 		return internal_errors.NilArgError("Type")
 	}
-	switch *d.Type {
-	case DomainTypeIpa:
-		{
-			if d.IpaDomain != nil {
-				d.IpaDomain.ID = d.ID
-			}
-		}
+	if entry, ok := lookupDomainType(*d.Type); ok {
+		entry.hooks.AfterCreate(d)
 	}
 	return nil
 }
@@ -83,25 +92,22 @@ func (d *Domain) FillAndPreload(db *gorm.DB) (err error) {
 	if d.Type == nil {
 		return internal_errors.NilArgError("Type")
 	}
-	switch *d.Type {
-	case DomainTypeIpa:
-		d.IpaDomain = &Ipa{}
-		if err := db.
-			Model(&Ipa{}).
-			Preload("CaCerts").
-			Preload("Servers").
-			Preload("Locations").
-			First(d.IpaDomain, "id = ?", d.ID).
-			Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				// FIXME Something different to do here?
-				return err
-			} else {
-				return err
-			}
-		}
-		return nil
-	default:
+	entry, ok := lookupDomainType(*d.Type)
+	if !ok {
 		return fmt.Errorf("'Type' is invalid")
 	}
+	return entry.hooks.Preload(db, d)
+}
+
+// IsPendingApproval return true when the domain waits for the
+// decision of an organization administrator.
+func (d *Domain) IsPendingApproval() bool {
+	return d.RegistrationState == DomainRegistrationPendingApproval
+}
+
+// IsRegistrationApproved return false when the domain is pending
+// approval or it was rejected, so it cannot configure hosts.
+func (d *Domain) IsRegistrationApproved() bool {
+	return d.RegistrationState != DomainRegistrationPendingApproval &&
+		d.RegistrationState != DomainRegistrationRejected
 }
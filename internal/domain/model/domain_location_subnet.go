@@ -0,0 +1,102 @@
+package model
+
+import (
+	"net/netip"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// DomainLocationSubnet map the hosts of an IP subnet to a location of
+// the domain (an IPA location or an AD site).
+type DomainLocationSubnet struct {
+	gorm.Model
+	DomainID uint
+	// Subnet is the subnet in CIDR notation.
+	Subnet   string
+	Location string
+}
+
+// ResolveLocation return the location of the most specific subnet
+// that contains client, or nil when no subnet matches.
+// subnets is the list of mappings for a domain.
+// client is the address or subnet of the requesting host.
+func ResolveLocation(subnets []DomainLocationSubnet, client netip.Prefix) *string {
+	var (
+		location *string
+		bits     = -1
+	)
+	if !client.IsValid() {
+		return nil
+	}
+	for i := range subnets {
+		prefix, err := netip.ParsePrefix(subnets[i].Subnet)
+		if err != nil {
+			continue
+		}
+		if prefix.Bits() > client.Bits() || !prefix.Contains(client.Addr()) {
+			continue
+		}
+		if prefix.Bits() > bits {
+			bits = prefix.Bits()
+			location = &subnets[i].Location
+		}
+	}
+	return location
+}
+
+// HostLocation is the location of a host which requests its
+// configuration, which selects the servers listed to the host.
+type HostLocation struct {
+	// Name is the IPA location or AD site of the host.
+	Name string
+	// Restrict only list the servers in the location. All the
+	// servers are listed when the location has none, so a stale
+	// mapping does not leave the host without servers.
+	Restrict bool
+}
+
+// SelectLocation return the items for a host in location; the ones
+// in the location come first, followed by the rest as fallback, or
+// only the ones in the location when it is restricted and it has
+// any. items is not modified. It return items when location is nil.
+// locationOf return the location of an item, or nil if it has none.
+func SelectLocation[T any](items []T, location *HostLocation, locationOf func(item *T) *string) []T {
+	if location == nil {
+		return items
+	}
+	output := make([]T, len(items))
+	copy(output, items)
+	PreferLocation(output, &location.Name, locationOf)
+	if !location.Restrict {
+		return output
+	}
+	count := 0
+	for count < len(output) {
+		if l := locationOf(&output[count]); l == nil || *l != location.Name {
+			break
+		}
+		count++
+	}
+	if count == 0 {
+		return output
+	}
+	return output[:count]
+}
+
+// PreferLocation reorders items so the ones in location come first,
+// followed by the rest as fallback; the relative order of the items
+// is kept otherwise. It does nothing when location is nil.
+// locationOf return the location of an item, or nil if it has none.
+func PreferLocation[T any](items []T, location *string, locationOf func(item *T) *string) {
+	if location == nil {
+		return
+	}
+	inLocation := func(item *T) bool {
+		l := locationOf(item)
+		return l != nil && *l == *location
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return inLocation(&items[i]) && !inLocation(&items[j])
+	})
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.openly.dev/pointy"
+)
+
+func TestResolveLocation(t *testing.T) {
+	subnets := []DomainLocationSubnet{
+		{Subnet: "192.0.2.0/24", Location: "alpha"},
+		{Subnet: "192.0.2.128/25", Location: "beta"},
+		{Subnet: "2001:db8::/32", Location: "gamma"},
+		{Subnet: "not a subnet", Location: "delta"},
+	}
+
+	assert.Nil(t, ResolveLocation(subnets, netip.Prefix{}))
+	assert.Nil(t, ResolveLocation(nil, netip.MustParsePrefix("192.0.2.1/32")))
+	assert.Nil(t, ResolveLocation(subnets, netip.MustParsePrefix("198.51.100.1/32")))
+	assert.Equal(t, pointy.String("alpha"), ResolveLocation(subnets, netip.MustParsePrefix("192.0.2.1/32")))
+	assert.Equal(t, pointy.String("beta"), ResolveLocation(subnets, netip.MustParsePrefix("192.0.2.129/32")))
+	assert.Equal(t, pointy.String("gamma"), ResolveLocation(subnets, netip.MustParsePrefix("2001:db8:1::/48")))
+
+	// A client subnet wider than the mapped subnets does not match them
+	assert.Equal(t, pointy.String("alpha"), ResolveLocation(subnets, netip.MustParsePrefix("192.0.2.0/24")))
+	assert.Nil(t, ResolveLocation(subnets, netip.MustParsePrefix("192.0.0.0/16")))
+}
+
+func TestPreferLocation(t *testing.T) {
+	type item struct {
+		name     string
+		location *string
+	}
+	locationOf := func(i *item) *string { return i.location }
+	items := func() []item {
+		return []item{
+			{"a", pointy.String("alpha")},
+			{"b", nil},
+			{"c", pointy.String("beta")},
+			{"d", pointy.String("alpha")},
+			{"e", pointy.String("beta")},
+		}
+	}
+
+	output := items()
+	PreferLocation(output, nil, locationOf)
+	assert.Equal(t, items(), output)
+
+	output = items()
+	PreferLocation(output, pointy.String("unknown"), locationOf)
+	assert.Equal(t, items(), output)
+
+	output = items()
+	PreferLocation(output, pointy.String("beta"), locationOf)
+	assert.Equal(t, []item{
+		{"c", pointy.String("beta")},
+		{"e", pointy.String("beta")},
+		{"a", pointy.String("alpha")},
+		{"b", nil},
+		{"d", pointy.String("alpha")},
+	}, output)
+}
+
+func TestSelectLocation(t *testing.T) {
+	type item struct {
+		name     string
+		location *string
+	}
+	locationOf := func(i *item) *string { return i.location }
+	items := []item{
+		{"a", pointy.String("alpha")},
+		{"b", nil},
+		{"c", pointy.String("beta")},
+	}
+
+	assert.Equal(t, items, SelectLocation(items, nil, locationOf))
+
+	assert.Equal(t, []item{
+		{"c", pointy.String("beta")},
+		{"a", pointy.String("alpha")},
+		{"b", nil},
+	}, SelectLocation(items, &HostLocation{Name: "beta"}, locationOf))
+	assert.Equal(t, "a", items[0].name, "items is not modified")
+
+	assert.Equal(t, []item{
+		{"c", pointy.String("beta")},
+	}, SelectLocation(items, &HostLocation{Name: "beta", Restrict: true}, locationOf))
+
+	// A location without items lists all of them
+	assert.Equal(t, items, SelectLocation(items, &HostLocation{Name: "unknown", Restrict: true}, locationOf))
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainRegistration record the registration of a domain which
+// requires approval, and the decision about it. Its ID is the ID of
+// the domain.
+type DomainRegistration struct {
+	ID        uint `gorm:"primarykey;autoIncrement:false"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// SubscriptionManagerID is the id of the registering server.
+	SubscriptionManagerID uuid.UUID
+	// FQDN is the name of the registering server, when it is one
+	// of the servers of the domain.
+	FQDN               *string
+	IpaHccVersion      string
+	IpaVersion         string
+	OsReleaseID        string
+	OsReleaseVersionID string
+	// CaCerts are the CA certificates of the domain when it was
+	// registered.
+	CaCerts []RegistrationCert `gorm:"serializer:json"`
+	// DecidedBy is the user or service account which approved or
+	// rejected the registration.
+	DecidedBy *string
+	DecidedAt *time.Time
+	Reason    *string
+}
+
+// RegistrationCert is a CA certificate of a domain registration.
+type RegistrationCert struct {
+	Issuer       string    `json:"issuer"`
+	Nickname     string    `json:"nickname"`
+	NotAfter     time.Time `json:"not_after"`
+	NotBefore    time.Time `json:"not_before"`
+	Pem          string    `json:"pem"`
+	SerialNumber string    `json:"serial_number"`
+	Subject      string    `json:"subject"`
+}
+
+// Decide record the decision about the registration.
+// decidedBy is the user or service account which decided.
+// decidedAt is the time of the decision.
+// reason is the optional reason given for the decision.
+func (r *DomainRegistration) Decide(decidedBy string, decidedAt time.Time, reason *string) {
+	r.DecidedBy = &decidedBy
+	r.DecidedAt = &decidedAt
+	r.Reason = reason
+}
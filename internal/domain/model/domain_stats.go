@@ -0,0 +1,16 @@
+package model
+
+// DomainStats is a snapshot of the domains registered in the
+// service, for all the organizations.
+type DomainStats struct {
+	// Domains is the number of registered domains.
+	Domains int64
+	// ServersPerDomain has the number of servers of every domain.
+	ServersPerDomain []int64
+	// CertsExpiringIn7Days is the number of CA certificates which
+	// are expired or expire in the next 7 days.
+	CertsExpiringIn7Days int64
+	// CertsExpiringIn30Days is the number of CA certificates which
+	// are expired or expire in the next 30 days.
+	CertsExpiringIn30Days int64
+}
@@ -12,13 +12,13 @@ import (
 func TestDomainTypeString(t *testing.T) {
 	assert.Equal(t, DomainTypeUndefinedString, DomainTypeString(DomainTypeUndefined))
 	assert.Equal(t, DomainTypeUndefinedString, DomainTypeString(1000))
-	assert.Equal(t, DomainTypeIpaString, DomainTypeString(DomainTypeIpa))
+	assert.Equal(t, testDomainTypeName, DomainTypeString(testDomainTypeID))
 }
 
 func TestDomainTypeUint(t *testing.T) {
 	assert.Equal(t, DomainTypeUndefined, DomainTypeUint(""))
 	assert.Equal(t, DomainTypeUndefined, DomainTypeUint("anything"))
-	assert.Equal(t, DomainTypeIpa, DomainTypeUint(DomainTypeIpaString))
+	assert.Equal(t, testDomainTypeID, DomainTypeUint(testDomainTypeName))
 }
 
 func TestDomainAfterCreate(t *testing.T) {
@@ -31,13 +31,27 @@ func TestDomainAfterCreate(t *testing.T) {
 		Model: gorm.Model{
 			ID: 1,
 		},
-		Type: pointy.Uint(DomainTypeIpa),
-		IpaDomain: &Ipa{
-			Model: gorm.Model{
-				ID: 0,
-			},
-		},
+		Type:     pointy.Uint(testDomainTypeID),
+		TypeData: &testDomainTypeData{},
 	}
 	require.NoError(t, item.AfterCreate(nil))
-	assert.Equal(t, item.ID, item.IpaDomain.ID)
+	assert.Equal(t, item.ID, item.TypeData.(*testDomainTypeData).ID)
+}
+
+func TestDomainRegistrationState(t *testing.T) {
+	d := &Domain{}
+	assert.False(t, d.IsPendingApproval())
+	assert.True(t, d.IsRegistrationApproved())
+
+	d.RegistrationState = DomainRegistrationActive
+	assert.False(t, d.IsPendingApproval())
+	assert.True(t, d.IsRegistrationApproved())
+
+	d.RegistrationState = DomainRegistrationPendingApproval
+	assert.True(t, d.IsPendingApproval())
+	assert.False(t, d.IsRegistrationApproved())
+
+	d.RegistrationState = DomainRegistrationRejected
+	assert.False(t, d.IsPendingApproval())
+	assert.False(t, d.IsRegistrationApproved())
 }
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// DomainTypeModel is implemented by every domain type to hook its
+// specific tables into the persistence of a Domain. The usecase
+// layers dispatch the rest of the type specific logic through
+// the registry at internal/usecase/domaintype.
+type DomainTypeModel interface {
+	// AfterCreate propagates the domain ID to the specific record.
+	AfterCreate(d *Domain)
+	// Preload fills the specific information of the domain type.
+	Preload(db *gorm.DB, d *Domain) error
+}
+
+type domainTypeEntry struct {
+	id    uint
+	name  string
+	hooks DomainTypeModel
+}
+
+var (
+	domainTypesLock   sync.RWMutex
+	domainTypesByID   = map[uint]domainTypeEntry{}
+	domainTypesByName = map[string]domainTypeEntry{}
+)
+
+// RegisterDomainType register the model hooks for a domain type.
+// id is the value stored at domains.type.
+// name is the domain_type used by the API.
+// hooks is the specific model behaviour of the type.
+// Return nil on success, else an error if the id or the name are
+// undefined or already registered.
+func RegisterDomainType(id uint, name string, hooks DomainTypeModel) error {
+	if id == DomainTypeUndefined {
+		return fmt.Errorf("'id' is undefined")
+	}
+	if name == DomainTypeUndefinedString {
+		return fmt.Errorf("'name' is empty")
+	}
+	if hooks == nil {
+		return fmt.Errorf("'hooks' is nil")
+	}
+	domainTypesLock.Lock()
+	defer domainTypesLock.Unlock()
+	if _, ok := domainTypesByID[id]; ok {
+		return fmt.Errorf("domain type '%d' is already registered", id)
+	}
+	if _, ok := domainTypesByName[name]; ok {
+		return fmt.Errorf("domain type '%s' is already registered", name)
+	}
+	entry := domainTypeEntry{id: id, name: name, hooks: hooks}
+	domainTypesByID[id] = entry
+	domainTypesByName[name] = entry
+	return nil
+}
+
+// MustRegisterDomainType is like RegisterDomainType but it
+// panics on error; to be used from init functions.
+func MustRegisterDomainType(id uint, name string, hooks DomainTypeModel) {
+	if err := RegisterDomainType(id, name, hooks); err != nil {
+		panic(err)
+	}
+}
+
+// DomainTypes return the ids of the registered domain types,
+// sorted in ascending order.
+func DomainTypes() []uint {
+	domainTypesLock.RLock()
+	defer domainTypesLock.RUnlock()
+	ids := make([]uint, 0, len(domainTypesByID))
+	for id := range domainTypesByID {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func lookupDomainType(id uint) (domainTypeEntry, bool) {
+	domainTypesLock.RLock()
+	defer domainTypesLock.RUnlock()
+	entry, ok := domainTypesByID[id]
+	return entry, ok
+}
+
+func lookupDomainTypeByName(name string) (domainTypeEntry, bool) {
+	domainTypesLock.RLock()
+	defer domainTypesLock.RUnlock()
+	entry, ok := domainTypesByName[name]
+	return entry, ok
+}
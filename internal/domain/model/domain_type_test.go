@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// testDomainType is a domain type registered only for the tests of
+// this package; the real types register from their own package.
+const (
+	testDomainTypeID   uint = 100
+	testDomainTypeName      = "test-type"
+)
+
+type testDomainTypeData struct {
+	ID uint
+}
+
+type testDomainType struct{}
+
+func init() {
+	MustRegisterDomainType(testDomainTypeID, testDomainTypeName, testDomainType{})
+}
+
+func (testDomainType) AfterCreate(d *Domain) {
+	if data, ok := d.TypeData.(*testDomainTypeData); ok {
+		data.ID = d.ID
+	}
+}
+
+func (testDomainType) Preload(db *gorm.DB, d *Domain) error {
+	d.TypeData = &testDomainTypeData{ID: d.ID}
+	return nil
+}
+
+func TestRegisterDomainType(t *testing.T) {
+	assert.EqualError(t, RegisterDomainType(DomainTypeUndefined, "other", testDomainType{}),
+		"'id' is undefined")
+	assert.EqualError(t, RegisterDomainType(1000, DomainTypeUndefinedString, testDomainType{}),
+		"'name' is empty")
+	assert.EqualError(t, RegisterDomainType(1000, "other", nil),
+		"'hooks' is nil")
+	assert.EqualError(t, RegisterDomainType(testDomainTypeID, "other", testDomainType{}),
+		"domain type '100' is already registered")
+	assert.EqualError(t, RegisterDomainType(1000, testDomainTypeName, testDomainType{}),
+		"domain type 'test-type' is already registered")
+	assert.Panics(t, func() {
+		MustRegisterDomainType(testDomainTypeID, testDomainTypeName, testDomainType{})
+	})
+	assert.Contains(t, DomainTypes(), testDomainTypeID)
+}
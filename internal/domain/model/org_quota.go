@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Quota is the effective quota of an organization; a zero value
+// means unlimited.
+type Quota struct {
+	// MaxDomains is the maximum number of domains registered.
+	MaxDomains int
+	// MaxServersPerDomain is the maximum number of servers of
+	// every domain.
+	MaxServersPerDomain int
+	// MaxHostConfPerDay is the maximum number of hosts configured
+	// every day.
+	MaxHostConfPerDay int
+}
+
+// OrgQuota override the default quota for an organization; the nil
+// fields keep the default value.
+type OrgQuota struct {
+	OrgID               string `gorm:"primaryKey"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	MaxDomains          *int
+	MaxServersPerDomain *int
+	MaxHostConfPerDay   *int
+}
+
+// Apply return the defaults with the overrides of the organization;
+// a nil receiver return the defaults.
+func (q *OrgQuota) Apply(defaults Quota) Quota {
+	if q == nil {
+		return defaults
+	}
+	if q.MaxDomains != nil {
+		defaults.MaxDomains = *q.MaxDomains
+	}
+	if q.MaxServersPerDomain != nil {
+		defaults.MaxServersPerDomain = *q.MaxServersPerDomain
+	}
+	if q.MaxHostConfPerDay != nil {
+		defaults.MaxHostConfPerDay = *q.MaxHostConfPerDay
+	}
+	return defaults
+}
+
+// TableName return the table for the organization quotas, as the
+// default naming strategy does not pluralize 'quota'.
+func (OrgQuota) TableName() string {
+	return "org_quotas"
+}
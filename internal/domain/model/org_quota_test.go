@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.openly.dev/pointy"
+)
+
+func TestOrgQuotaApply(t *testing.T) {
+	defaults := Quota{MaxDomains: 10, MaxServersPerDomain: 50, MaxHostConfPerDay: 1000}
+
+	var q *OrgQuota
+	assert.Equal(t, defaults, q.Apply(defaults))
+
+	q = &OrgQuota{OrgID: "12345"}
+	assert.Equal(t, defaults, q.Apply(defaults))
+
+	q.MaxDomains = pointy.Int(1)
+	q.MaxHostConfPerDay = pointy.Int(0)
+	assert.Equal(t, Quota{MaxDomains: 1, MaxServersPerDomain: 50, MaxHostConfPerDay: 0}, q.Apply(defaults))
+
+	q.MaxServersPerDomain = pointy.Int(5)
+	assert.Equal(t, Quota{MaxDomains: 1, MaxServersPerDomain: 5, MaxHostConfPerDay: 0}, q.Apply(defaults))
+}
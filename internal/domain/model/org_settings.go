@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// OrgSettings override the default settings for an organization; the
+// nil fields keep the default value.
+type OrgSettings struct {
+	OrgID     string `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// RegistrationApproval require an organization administrator to
+	// approve the registered domains before they configure hosts.
+	RegistrationApproval *bool
+}
+
+// RequireRegistrationApproval return the registration approval of
+// the organization; a nil receiver return the default.
+func (s *OrgSettings) RequireRegistrationApproval(defaultValue bool) bool {
+	if s == nil || s.RegistrationApproval == nil {
+		return defaultValue
+	}
+	return *s.RegistrationApproval
+}
+
+// TableName return the table for the organization settings.
+func (OrgSettings) TableName() string {
+	return "org_settings"
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.openly.dev/pointy"
+)
+
+func TestOrgSettingsRequireRegistrationApproval(t *testing.T) {
+	var s *OrgSettings
+	assert.False(t, s.RequireRegistrationApproval(false))
+	assert.True(t, s.RequireRegistrationApproval(true))
+
+	s = &OrgSettings{OrgID: "12345"}
+	assert.True(t, s.RequireRegistrationApproval(true))
+
+	s.RegistrationApproval = pointy.Bool(false)
+	assert.False(t, s.RequireRegistrationApproval(true))
+
+	s.RegistrationApproval = pointy.Bool(true)
+	assert.True(t, s.RequireRegistrationApproval(false))
+}
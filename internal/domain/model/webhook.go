@@ -0,0 +1,82 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// WebhookDeliveryPending is the state of a delivery which is
+	// waiting for its next attempt.
+	WebhookDeliveryPending = "pending"
+	// WebhookDeliverySucceeded is the state of a delivery which was
+	// accepted by the webhook.
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed is the state of a delivery which ran out
+	// of attempts, or which webhook was disabled.
+	WebhookDeliveryFailed = "failed"
+)
+
+// Webhook is an https endpoint of an organization which receive the
+// domain and host-conf events.
+type Webhook struct {
+	ID          uint `gorm:"primarykey"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	OrgId       string
+	WebhookUuid uuid.UUID `gorm:"unique"`
+	URL         string    `gorm:"column:url"`
+	// Events is the list of events delivered to the webhook.
+	Events []string `gorm:"serializer:json"`
+	// Enabled indicate if the events are delivered to the webhook.
+	Enabled *bool
+	// ConsecutiveFailures is the number of failed deliveries since
+	// the last succeeded one.
+	ConsecutiveFailures int
+	// DisabledAt is set when the webhook is disabled by the failed
+	// deliveries.
+	DisabledAt *time.Time
+	// EncryptedSecret is the signing secret of the webhook, encrypted
+	// with the main secret generation identified by EncryptionId. Both
+	// are nil for the webhooks which secret is derived from their uuid.
+	EncryptedSecret []byte
+	EncryptionId    *string
+	// Secret is the signing secret, which is only set when the webhook
+	// is created, and it is not stored.
+	Secret string `gorm:"-"`
+}
+
+// Subscribed return true when the webhook is enabled and it receive
+// event.
+func (w *Webhook) Subscribed(event string) bool {
+	return w.IsEnabled() && slices.Contains(w.Events, event)
+}
+
+// IsEnabled return true when the webhook is enabled.
+func (w *Webhook) IsEnabled() bool {
+	return w != nil && w.Enabled != nil && *w.Enabled
+}
+
+// WebhookDelivery is the delivery of an event to a webhook, which
+// record the state of its attempts.
+type WebhookDelivery struct {
+	ID           uint `gorm:"primarykey"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	WebhookID    uint
+	Webhook      *Webhook  `gorm:"foreignKey:WebhookID"`
+	DeliveryUuid uuid.UUID `gorm:"unique"`
+	Event        string
+	// Payload is the body sent to the webhook.
+	Payload       []byte
+	Status        string
+	Attempts      int
+	NextAttemptAt *time.Time
+	// ResponseStatus is the http status of the last attempt.
+	ResponseStatus *int
+	// Error is the error of the last attempt.
+	Error       *string
+	DeliveredAt *time.Time
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.openly.dev/pointy"
+)
+
+func TestWebhookIsEnabled(t *testing.T) {
+	var w *Webhook
+	assert.False(t, w.IsEnabled())
+
+	w = &Webhook{}
+	assert.False(t, w.IsEnabled())
+
+	w.Enabled = pointy.Bool(false)
+	assert.False(t, w.IsEnabled())
+
+	w.Enabled = pointy.Bool(true)
+	assert.True(t, w.IsEnabled())
+}
+
+func TestWebhookSubscribed(t *testing.T) {
+	var w *Webhook
+	assert.False(t, w.Subscribed("domain.created"))
+
+	w = &Webhook{Events: []string{"domain.created", "domain.deleted"}}
+	assert.False(t, w.Subscribed("domain.created"))
+
+	w.Enabled = pointy.Bool(true)
+	assert.True(t, w.Subscribed("domain.created"))
+	assert.True(t, w.Subscribed("domain.deleted"))
+	assert.False(t, w.Subscribed("domain.updated"))
+}
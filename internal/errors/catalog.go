@@ -0,0 +1,168 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Code is a stable machine-readable error code, so the clients can
+// handle specific failures programmatically. The codes are part of
+// the API: never change or reuse them.
+type Code string
+
+// Definition is an entry of the error catalogue.
+type Definition struct {
+	// Code is the stable error code.
+	Code Code
+	// Status is the http status code of the response.
+	Status int
+	// Title is a short human-readable summary of the problem, which
+	// does not change between occurrences.
+	Title string
+}
+
+// Generic definitions, used for the errors out of the catalogue.
+var (
+	ErrBadRequest       = Definition{"IDMSVC-BAD-REQUEST", http.StatusBadRequest, "Bad request"}
+	ErrUnauthorized     = Definition{"IDMSVC-UNAUTHORIZED", http.StatusUnauthorized, "Unauthorized"}
+	ErrForbidden        = Definition{"IDMSVC-FORBIDDEN", http.StatusForbidden, "Forbidden"}
+	ErrNotFound         = Definition{"IDMSVC-NOT-FOUND", http.StatusNotFound, "Not found"}
+	ErrMethodNotAllowed = Definition{"IDMSVC-METHOD-NOT-ALLOWED", http.StatusMethodNotAllowed, "Method not allowed"}
+	ErrConflict         = Definition{"IDMSVC-CONFLICT", http.StatusConflict, "Conflict"}
+	ErrTooManyRequests  = Definition{"IDMSVC-TOO-MANY-REQUESTS", http.StatusTooManyRequests, "Too many requests"}
+	ErrClient           = Definition{"IDMSVC-CLIENT-ERROR", http.StatusBadRequest, "Client error"}
+	ErrInternal         = Definition{"IDMSVC-INTERNAL", http.StatusInternalServerError, "Internal server error"}
+	ErrUnavailable      = Definition{"IDMSVC-UNAVAILABLE", http.StatusServiceUnavailable, "Service unavailable"}
+)
+
+// Specific definitions of the catalogue.
+var (
+	ErrIdentityMissing = Definition{"IDMSVC-IDENTITY-MISSING", http.StatusBadRequest, "Identity header is missing"}
+	ErrIdentityInvalid = Definition{"IDMSVC-IDENTITY-INVALID", http.StatusBadRequest, "Identity header is invalid"}
+	ErrRequestInvalid  = Definition{"IDMSVC-REQUEST-INVALID", http.StatusBadRequest, "Request does not match the API specification"}
+
+	ErrAuthzUnavailable        = Definition{"IDMSVC-AUTHZ-UNAVAILABLE", http.StatusServiceUnavailable, "Authorization service is unavailable"}
+	ErrNotEntitled             = Definition{"IDMSVC-NOT-ENTITLED", http.StatusForbidden, "Organization is not entitled"}
+	ErrEntitlementsUnavailable = Definition{"IDMSVC-ENTITLEMENTS-UNAVAILABLE", http.StatusServiceUnavailable, "Entitlements could not be checked"}
+	ErrRateLimited             = Definition{"IDMSVC-RATE-LIMITED", http.StatusTooManyRequests, "Rate limit exceeded"}
+	ErrQuotaDomains            = Definition{"IDMSVC-QUOTA-DOMAINS", http.StatusForbidden, "Domains quota exceeded"}
+	ErrQuotaServers            = Definition{"IDMSVC-QUOTA-SERVERS", http.StatusForbidden, "Servers per domain quota exceeded"}
+	ErrQuotaHostConf           = Definition{"IDMSVC-QUOTA-HOSTCONF", http.StatusTooManyRequests, "Daily host-conf quota exceeded"}
+	ErrQuotaWebhooks           = Definition{"IDMSVC-QUOTA-WEBHOOKS", http.StatusForbidden, "Webhooks quota exceeded"}
+
+	ErrTokenInvalid = Definition{"IDMSVC-TOKEN-INVALID", http.StatusUnauthorized, "Domain registration token is invalid"}
+	ErrTokenExpired = Definition{"IDMSVC-TOKEN-EXPIRED", http.StatusUnauthorized, "Domain registration token has expired"}
+	ErrTokenClaims  = Definition{"IDMSVC-TOKEN-CLAIMS", http.StatusForbidden, "Domain registration token does not allow the registration"}
+
+	ErrIdempotencyKeyInvalid    = Definition{"IDMSVC-IDEMPOTENCY-KEY-INVALID", http.StatusBadRequest, "Idempotency key is invalid"}
+	ErrIdempotencyKeyMismatch   = Definition{"IDMSVC-IDEMPOTENCY-KEY-MISMATCH", http.StatusUnprocessableEntity, "Idempotency key was used for a different request"}
+	ErrIdempotencyKeyInProgress = Definition{"IDMSVC-IDEMPOTENCY-KEY-IN-PROGRESS", http.StatusConflict, "A request with the same idempotency key is in progress"}
+
+	ErrDomainNotFound      = Definition{"IDMSVC-DOMAIN-NOT-FOUND", http.StatusNotFound, "Domain not found"}
+	ErrDomainAlreadyExists = Definition{"IDMSVC-DOMAIN-ALREADY-EXISTS", http.StatusConflict, "Domain is already registered"}
+	ErrDomainNotPending    = Definition{"IDMSVC-DOMAIN-NOT-PENDING", http.StatusConflict, "Domain is not pending approval"}
+
+	ErrHostConfNoMatch                = Definition{"IDMSVC-HOSTCONF-NO-MATCH", http.StatusNotFound, "No matching domain"}
+	ErrHostConfAutoEnrollmentDisabled = Definition{"IDMSVC-HOSTCONF-AUTO-ENROLLMENT-DISABLED", http.StatusNotFound, "Auto enrollment is disabled"}
+	ErrHostConfMultipleMatches        = Definition{"IDMSVC-HOSTCONF-MULTIPLE-MATCHES", http.StatusConflict, "More than one matching domain"}
+	ErrHostConfNoSigningKey           = Definition{"IDMSVC-HOSTCONF-NO-SIGNING-KEY", http.StatusInternalServerError, "No signing key is available"}
+	ErrHostConfDomainNotApproved      = Definition{"IDMSVC-HOSTCONF-DOMAIN-NOT-APPROVED", http.StatusNotFound, "Domain registration is not approved"}
+
+	ErrWebhookNotFound   = Definition{"IDMSVC-WEBHOOK-NOT-FOUND", http.StatusNotFound, "Webhook not found"}
+	ErrWebhookURLInvalid = Definition{"IDMSVC-WEBHOOK-URL-INVALID", http.StatusBadRequest, "Webhook url is invalid"}
+)
+
+// codedError attach the definition of the catalogue to the internal
+// cause of an echo.HTTPError.
+type codedError struct {
+	definition Definition
+	err        error
+}
+
+func (e *codedError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s: %s", e.definition.Code, e.err.Error())
+	}
+	return string(e.definition.Code)
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// New create an error of the catalogue. The error is an
+// echo.HTTPError with the status of the definition.
+// internal is the cause, which is logged but never sent to the
+// client; it can be nil.
+// format and a build the detail sent to the client, so it must not
+// include internal error text.
+func (d Definition) New(internal error, format string, a ...any) error {
+	var msg string
+	if len(a) != 0 {
+		msg = fmt.Sprintf(format, a...)
+	} else {
+		msg = format
+	}
+	return &echo.HTTPError{
+		Code:     d.Status,
+		Message:  msg,
+		Internal: &codedError{definition: d, err: internal},
+	}
+}
+
+// DefinitionOf return the definition of the catalogue for err. The
+// errors out of the catalogue get the generic definition for their
+// http status; the errors which are not an echo.HTTPError get
+// ErrInternal.
+func DefinitionOf(err error) Definition {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.definition
+	}
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		return ErrInternal
+	}
+	switch httpErr.Code {
+	case http.StatusBadRequest:
+		return ErrBadRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrMethodNotAllowed
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrTooManyRequests
+	case http.StatusInternalServerError:
+		return ErrInternal
+	case http.StatusServiceUnavailable:
+		return ErrUnavailable
+	}
+	if httpErr.Code >= http.StatusBadRequest && httpErr.Code < http.StatusInternalServerError {
+		return Definition{ErrClient.Code, httpErr.Code, http.StatusText(httpErr.Code)}
+	}
+	return Definition{ErrInternal.Code, httpErr.Code, http.StatusText(httpErr.Code)}
+}
+
+// PublicDetail return the message of err which can be sent to the
+// client. The message of an echo.HTTPError is public, except for the
+// server errors out of the catalogue, which could include internal
+// error text; those and any other error get the status text.
+func PublicDetail(err error) string {
+	definition := DefinitionOf(err)
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		if httpErr.Code < http.StatusInternalServerError || definition.Code != ErrInternal.Code {
+			return fmt.Sprintf("%v", httpErr.Message)
+		}
+	}
+	return http.StatusText(definition.Status)
+}
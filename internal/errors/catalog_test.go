@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefinitionNew(t *testing.T) {
+	internal := errors.New("record not found")
+	err := ErrDomainNotFound.New(internal, "unknown domain '%s'", "test")
+	he := httpErrorFromErr(t, err)
+	assert.Equal(t, http.StatusNotFound, he.Code)
+	assert.Equal(t, "unknown domain 'test'", he.Message)
+	assert.ErrorIs(t, err, internal)
+	assert.EqualError(t, err, "code=404, message=unknown domain 'test', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found")
+
+	err = ErrRateLimited.New(nil, "Too many requests")
+	assert.EqualError(t, err, "code=429, message=Too many requests, internal=IDMSVC-RATE-LIMITED")
+}
+
+func TestDefinitionOf(t *testing.T) {
+	// Errors of the catalogue, even wrapped
+	assert.Equal(t, ErrHostConfNoMatch, DefinitionOf(ErrHostConfNoMatch.New(nil, "no matching domains")))
+	assert.Equal(t, ErrTokenExpired, DefinitionOf(fmt.Errorf("registering: %w", ErrTokenExpired.New(nil, "expired"))))
+
+	// Errors out of the catalogue
+	assert.Equal(t, ErrInternal, DefinitionOf(errors.New("raw")))
+	assert.Equal(t, ErrInternal, DefinitionOf(echo.NewHTTPError(http.StatusInternalServerError, "raw")))
+	for status, expected := range map[int]Definition{
+		http.StatusBadRequest:         ErrBadRequest,
+		http.StatusUnauthorized:       ErrUnauthorized,
+		http.StatusForbidden:          ErrForbidden,
+		http.StatusNotFound:           ErrNotFound,
+		http.StatusMethodNotAllowed:   ErrMethodNotAllowed,
+		http.StatusConflict:           ErrConflict,
+		http.StatusTooManyRequests:    ErrTooManyRequests,
+		http.StatusServiceUnavailable: ErrUnavailable,
+	} {
+		assert.Equal(t, expected, DefinitionOf(NewHTTPErrorF(status, "message")))
+	}
+	definition := DefinitionOf(echo.ErrUnsupportedMediaType)
+	assert.Equal(t, Definition{ErrClient.Code, http.StatusUnsupportedMediaType, "Unsupported Media Type"}, definition)
+	definition = DefinitionOf(echo.ErrBadGateway)
+	assert.Equal(t, Definition{ErrInternal.Code, http.StatusBadGateway, "Bad Gateway"}, definition)
+}
+
+func TestPublicDetail(t *testing.T) {
+	assert.Equal(t, "unknown domain", PublicDetail(ErrDomainNotFound.New(errors.New("record not found"), "unknown domain")))
+	assert.Equal(t, "no keys available", PublicDetail(ErrHostConfNoSigningKey.New(nil, "no keys available")))
+	assert.Equal(t, "Not Found", PublicDetail(echo.NewHTTPError(http.StatusNotFound, "Not Found")))
+	assert.Equal(t, "Internal Server Error", PublicDetail(echo.NewHTTPError(http.StatusInternalServerError, "pq: connection refused")))
+	assert.Equal(t, "Internal Server Error", PublicDetail(errors.New("pq: connection refused")))
+}
+
+func TestCatalogCodesAreUnique(t *testing.T) {
+	definitions := []Definition{
+		ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound,
+		ErrMethodNotAllowed, ErrConflict, ErrTooManyRequests, ErrClient,
+		ErrInternal, ErrUnavailable,
+		ErrIdentityMissing, ErrIdentityInvalid, ErrRequestInvalid,
+		ErrAuthzUnavailable, ErrNotEntitled, ErrEntitlementsUnavailable,
+		ErrRateLimited, ErrQuotaDomains, ErrQuotaServers, ErrQuotaHostConf,
+		ErrQuotaWebhooks,
+		ErrTokenInvalid, ErrTokenExpired, ErrTokenClaims,
+		ErrIdempotencyKeyInvalid, ErrIdempotencyKeyMismatch,
+		ErrIdempotencyKeyInProgress,
+		ErrDomainNotFound, ErrDomainAlreadyExists, ErrDomainNotPending,
+		ErrHostConfNoMatch, ErrHostConfAutoEnrollmentDisabled,
+		ErrHostConfMultipleMatches, ErrHostConfNoSigningKey,
+		ErrHostConfDomainNotApproved,
+		ErrWebhookNotFound, ErrWebhookURLInvalid,
+	}
+	seen := map[Code]bool{}
+	for _, d := range definitions {
+		require.False(t, seen[d.Code], d.Code)
+		seen[d.Code] = true
+		assert.Regexp(t, `^IDMSVC-[A-Z-]+$`, string(d.Code))
+		assert.NotEmpty(t, d.Title)
+		assert.NotEmpty(t, http.StatusText(d.Status))
+	}
+}
@@ -7,18 +7,33 @@
 package echo
 
 import (
-	"errors"
-	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"go.openly.dev/pointy"
 )
 
+// MIMEApplicationProblemJSON is the media type of the RFC 7807
+// error responses.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problemTypePrefix is the prefix of the problem type, followed by
+// the error code.
+const problemTypePrefix = "urn:idmsvc:error:"
+
+// DefaultErrorHandler write the error response with the stable error
+// code of the catalogue and the request id. The response is an
+// application/problem+json document (RFC 7807) when the request
+// accepts it, else the api_public.ErrorResponseApplicationJSON document. The
+// internal causes are only logged, never sent to the client.
 func DefaultErrorHandler(err error, c echo.Context) {
 	logger := app_context.LogFromCtx(c.Request().Context())
 	if c.Response().Committed {
@@ -27,43 +42,63 @@ func DefaultErrorHandler(err error, c echo.Context) {
 		)
 		return
 	}
-	errList := make([]api_public.ErrorInfo, 0, 10)
 
-	firstCode := 0
-	response := api_public.ErrorResponse{}
-	for {
-		logger.Debug("parsing error", slog.String("msg", err.Error()))
-		errInfo, code := parseError(err)
-		errList = append(errList, errInfo)
-		if firstCode == 0 {
-			firstCode = code
+	definition := internal_errors.DefinitionOf(err)
+	detail := internal_errors.PublicDetail(err)
+	requestID := c.Request().Header.Get(header.HeaderXRequestID)
+	if requestID == "" {
+		requestID = c.Response().Header().Get(header.HeaderXRequestID)
+	}
+	logger.Debug("error response",
+		slog.String("code", string(definition.Code)),
+		slog.Int("status", definition.Status),
+		slog.String("err", err.Error()),
+	)
+
+	if acceptsProblem(c.Request()) {
+		problem := api_public.Problem{
+			Type:     problemTypePrefix + string(definition.Code),
+			Title:    definition.Title,
+			Status:   int32(definition.Status),
+			Code:     string(definition.Code),
+			Detail:   pointy.String(detail),
+			Instance: pointy.String(c.Request().URL.Path),
+		}
+		if requestID != "" {
+			problem.RequestId = pointy.String(requestID)
 		}
-		if err = errors.Unwrap(err); err == nil {
-			break
+		c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+		err = c.JSON(definition.Status, &problem)
+	} else {
+		response := api_public.ErrorResponseApplicationJSON{
+			Errors: &[]api_public.ErrorInfo{
+				{
+					Id:     requestID,
+					Status: strconv.Itoa(definition.Status),
+					Title:  detail,
+					Code:   pointy.String(string(definition.Code)),
+				},
+			},
 		}
+		err = c.JSON(definition.Status, &response)
 	}
-	response.Errors = &errList
-	if err = c.JSON(firstCode, &response); err != nil {
+	if err != nil {
 		logger.Error("failed to send JSON error http response",
 			slog.String("msg", err.Error()),
 		)
 	}
 }
 
-// parseError translate an error into api_public.ErrorInfo and
-// return the first http status code of the error list.
-func parseError(err error) (api_public.ErrorInfo, int) {
-	switch value := err.(type) { //nolint:all
-	case *echo.HTTPError:
-		return api_public.ErrorInfo{
-			Status: strconv.Itoa(value.Code),
-			Title:  fmt.Sprintf("%v", value.Message),
-		}, value.Code
-	default:
-		return api_public.ErrorInfo{
-			Status: strconv.Itoa(http.StatusInternalServerError),
-			Title:  http.StatusText(http.StatusInternalServerError),
-			Detail: pointy.String(err.Error()),
-		}, http.StatusInternalServerError
+// acceptsProblem return true when the Accept header of the request
+// list the application/problem+json media type.
+func acceptsProblem(req *http.Request) bool {
+	for _, accept := range req.Header.Values(echo.HeaderAccept) {
+		for _, item := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(item))
+			if err == nil && mediaType == MIMEApplicationProblemJSON {
+				return true
+			}
+		}
 	}
+	return false
 }
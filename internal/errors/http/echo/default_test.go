@@ -8,13 +8,18 @@ import (
 	"testing"
 
 	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
 	builder_api "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
 )
 
+const testRequestID = "27c19744-29f2-42a0-8669-8fa050f8ffdf"
+
 func helperNewEcho(err error) *echo.Echo {
 	e := echo.New()
 	e.Use(middleware.ContextLogConfig(&middleware.LogConfig{}))
@@ -28,7 +33,7 @@ func helperNewEcho(err error) *echo.Echo {
 func TestDefaultErrorHandler(t *testing.T) {
 	type TestCaseExpected struct {
 		Code int
-		Body api_public.ErrorResponse
+		Body api_public.ErrorResponseApplicationJSON
 	}
 	type TestCase struct {
 		Name     string
@@ -44,8 +49,9 @@ func TestDefaultErrorHandler(t *testing.T) {
 				Code: http.StatusInternalServerError,
 				Body: *builder_api.NewErrorResponse().
 					Add(*builder_api.NewErrorInfo(http.StatusInternalServerError).
+						WithId(testRequestID).
 						WithTitle(http.StatusText(http.StatusInternalServerError)).
-						WithDetail("Test error").
+						WithCode("IDMSVC-INTERNAL").
 						Build(),
 					).Build(),
 			},
@@ -57,25 +63,54 @@ func TestDefaultErrorHandler(t *testing.T) {
 				Code: http.StatusNotFound,
 				Body: *builder_api.NewErrorResponse().
 					Add(*builder_api.NewErrorInfo(http.StatusNotFound).
+						WithId(testRequestID).
 						WithTitle("Not Found").
+						WithCode("IDMSVC-NOT-FOUND").
 						Build(),
 					).Build(),
 			},
 		},
 		{
-			Name:  "Nested echo.HTTPError",
+			Name:  "Nested error",
 			Given: fmt.Errorf("primary error: %w", fmt.Errorf("secondary error")),
 			Expected: TestCaseExpected{
 				Code: http.StatusInternalServerError,
 				Body: *builder_api.NewErrorResponse().
 					Add(*builder_api.NewErrorInfo(http.StatusInternalServerError).
+						WithId(testRequestID).
 						WithTitle("Internal Server Error").
-						WithDetail("primary error: secondary error").
+						WithCode("IDMSVC-INTERNAL").
 						Build(),
 					).
+					Build(),
+			},
+		},
+		{
+			Name:  "Server echo.HTTPError hides the message",
+			Given: echo.NewHTTPError(http.StatusInternalServerError, "pq: connection refused"),
+			Expected: TestCaseExpected{
+				Code: http.StatusInternalServerError,
+				Body: *builder_api.NewErrorResponse().
 					Add(*builder_api.NewErrorInfo(http.StatusInternalServerError).
+						WithId(testRequestID).
 						WithTitle("Internal Server Error").
-						WithDetail("secondary error").
+						WithCode("IDMSVC-INTERNAL").
+						Build(),
+					).
+					Build(),
+			},
+		},
+		{
+			Name: "Error of the catalogue",
+			Given: fmt.Errorf("wrapped: %w", internal_errors.ErrHostConfNoMatch.New(
+				fmt.Errorf("internal detail"), "no matching domains")),
+			Expected: TestCaseExpected{
+				Code: http.StatusNotFound,
+				Body: *builder_api.NewErrorResponse().
+					Add(*builder_api.NewErrorInfo(http.StatusNotFound).
+						WithId(testRequestID).
+						WithTitle("no matching domains").
+						WithCode("IDMSVC-HOSTCONF-NO-MATCH").
 						Build(),
 					).
 					Build(),
@@ -88,12 +123,68 @@ func TestDefaultErrorHandler(t *testing.T) {
 			e := helperNewEcho(testCase.Given)
 			resp := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.Header.Set(header.HeaderXRequestID, testRequestID)
 			e.ServeHTTP(resp, req)
 			assert.Equal(t, testCase.Expected.Code, resp.Code)
-			currentResponse := api_public.ErrorResponse{}
+			assert.Equal(t, echo.MIMEApplicationJSON, resp.Header().Get(echo.HeaderContentType))
+			assert.NotContains(t, resp.Body.String(), "internal detail")
+			currentResponse := api_public.ErrorResponseApplicationJSON{}
 			err := json.Unmarshal(resp.Body.Bytes(), &currentResponse)
 			require.NoError(t, err)
 			assert.Equal(t, testCase.Expected.Body, currentResponse)
 		})
 	}
 }
+
+func TestDefaultErrorHandlerProblem(t *testing.T) {
+	get := func(err error, accept string) *httptest.ResponseRecorder {
+		e := helperNewEcho(err)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(echo.HeaderAccept, accept)
+		req.Header.Set(header.HeaderXRequestID, testRequestID)
+		e.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// Error of the catalogue
+	resp := get(internal_errors.ErrTokenExpired.New(fmt.Errorf("Token has expired"), "Domain registration token is invalid"),
+		"application/json;q=0.9, application/problem+json")
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, MIMEApplicationProblemJSON, resp.Header().Get(echo.HeaderContentType))
+	problem := api_public.Problem{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &problem))
+	assert.Equal(t, api_public.Problem{
+		Type:      "urn:idmsvc:error:IDMSVC-TOKEN-EXPIRED",
+		Title:     "Domain registration token has expired",
+		Status:    http.StatusUnauthorized,
+		Code:      "IDMSVC-TOKEN-EXPIRED",
+		Detail:    pointy.String("Domain registration token is invalid"),
+		Instance:  pointy.String("/"),
+		RequestId: pointy.String(testRequestID),
+	}, problem)
+
+	// Raw error does not leak
+	resp = get(fmt.Errorf("pq: password authentication failed"), MIMEApplicationProblemJSON)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "password")
+	problem = api_public.Problem{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &problem))
+	assert.Equal(t, "IDMSVC-INTERNAL", problem.Code)
+	assert.Equal(t, pointy.String("Internal Server Error"), problem.Detail)
+}
+
+func TestAcceptsProblem(t *testing.T) {
+	for accept, expected := range map[string]bool{
+		"":                         false,
+		"*/*":                      false,
+		"application/json":         false,
+		"application/problem+json": true,
+		"text/html, application/problem+json;q=0.5": true,
+		"invalid;;": false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(echo.HeaderAccept, accept)
+		assert.Equal(t, expected, acceptsProblem(req), accept)
+	}
+}
@@ -3,8 +3,10 @@ package impl
 import (
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/handler"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	client_entitlements "github.com/podengo-project/idmsvc-backend/internal/interface/client/entitlements"
 	client_pendo "github.com/podengo-project/idmsvc-backend/internal/interface/client/pendo"
-	client_rbac "github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/presenter"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
@@ -24,6 +26,7 @@ const (
 	errDBGeneralError = "failed on database operation"
 	errDBTXCommit     = "failed to commit database transaction"
 	errOutputAdapter  = "failed to translate the business object to the API response"
+	errWebhookEnqueue = "failed to enqueue the webhook deliveries"
 )
 
 type domainComponent struct {
@@ -44,17 +47,32 @@ type hostconfJwkComponent struct {
 	presenter  presenter.HostconfJwkPresenter
 }
 
+type webhookComponent struct {
+	interactor interactor.WebhookInteractor
+	repository repository.WebhookRepository
+	presenter  presenter.WebhookPresenter
+}
+
+type quotaComponent struct {
+	repository repository.QuotaRepository
+}
+
 type application struct {
-	config      *config.Config
-	metrics     *metrics.Metrics
-	domain      domainComponent
-	host        hostComponent
-	hostconfjwk hostconfJwkComponent
-	db          *gorm.DB
-	pendo       client_pendo.Pendo
+	config       *config.Config
+	metrics      *metrics.Metrics
+	domain       domainComponent
+	host         hostComponent
+	hostconfjwk  hostconfJwkComponent
+	quota        quotaComponent
+	webhook      webhookComponent
+	db           *gorm.DB
+	pendo        client_pendo.Pendo
+	authorizer   authz.Authorizer
+	health       *health.Registry
+	entitlements client_entitlements.Entitlements
 }
 
-func guardNewHandler(cfg *config.Config, db *gorm.DB, m *metrics.Metrics, rbac client_rbac.Rbac, pendo client_pendo.Pendo) {
+func guardNewHandler(cfg *config.Config, db *gorm.DB, m *metrics.Metrics, authorizer authz.Authorizer, pendo client_pendo.Pendo, entitlements client_entitlements.Entitlements) {
 	if cfg == nil {
 		panic("'cfg' is nil")
 	}
@@ -64,15 +82,23 @@ func guardNewHandler(cfg *config.Config, db *gorm.DB, m *metrics.Metrics, rbac c
 	if m == nil {
 		panic("'m' is nil")
 	}
-	if rbac == nil {
-		panic("'rbac' is nil")
+	if authorizer == nil {
+		panic("'authorizer' is nil")
 	}
 	if pendo == nil {
 		panic("'pendo' is nil")
 	}
+	if entitlements == nil {
+		panic("'entitlements' is nil")
+	}
 }
 
-func NewHandler(cfg *config.Config, db *gorm.DB, m *metrics.Metrics, rbac client_rbac.Rbac, pendo client_pendo.Pendo) handler.Application {
+// NewHandler create the handlers for the application.
+// registry hold the health checks for the liveness and readiness
+// probes; it can be nil.
+// entitlements check the organizations allowed to register domains
+// and hosts.
+func NewHandler(cfg *config.Config, db *gorm.DB, m *metrics.Metrics, authorizer authz.Authorizer, registry *health.Registry, pendo client_pendo.Pendo, entitlements client_entitlements.Entitlements) handler.Application {
 	dc := domainComponent{
 		usecase_interactor.NewDomainInteractor(),
 		usecase_repository.NewDomainRepository(),
@@ -88,15 +114,28 @@ func NewHandler(cfg *config.Config, db *gorm.DB, m *metrics.Metrics, rbac client
 		usecase_repository.NewHostconfJwkRepository(cfg),
 		usecase_presenter.NewHostconfJwkPresenter(cfg),
 	}
+	qc := quotaComponent{
+		usecase_repository.NewQuotaRepository(),
+	}
+	wc := webhookComponent{
+		usecase_interactor.NewWebhookInteractor(cfg),
+		usecase_repository.NewWebhookRepository(),
+		usecase_presenter.NewWebhookPresenter(cfg),
+	}
 
 	// Instantiate application
 	return &application{
-		config:      cfg,
-		db:          db,
-		metrics:     m,
-		domain:      dc,
-		host:        hc,
-		hostconfjwk: hcjc,
-		pendo:       pendo,
+		config:       cfg,
+		db:           db,
+		metrics:      m,
+		domain:       dc,
+		host:         hc,
+		hostconfjwk:  hcjc,
+		quota:        qc,
+		webhook:      wc,
+		pendo:        pendo,
+		authorizer:   authorizer,
+		health:       registry,
+		entitlements: entitlements,
 	}
 }
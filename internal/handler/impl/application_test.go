@@ -6,8 +6,9 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/entitlements"
 	"github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/pendo"
-	"github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/rbac"
 	// client_rbac "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/rbac"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,12 +17,12 @@ import (
 
 func TestGuardNewHandler(t *testing.T) {
 	assert.PanicsWithValue(t, "'cfg' is nil", func() {
-		guardNewHandler(nil, nil, nil, nil, nil)
+		guardNewHandler(nil, nil, nil, nil, nil, nil)
 	})
 
 	cfg := test.GetTestConfig()
 	assert.PanicsWithValue(t, "'db' is nil", func() {
-		guardNewHandler(&config.Config{}, nil, nil, nil, nil)
+		guardNewHandler(&config.Config{}, nil, nil, nil, nil, nil)
 	})
 
 	sqlMock, gormDB, err := test.NewSqlMock(&gorm.Session{SkipHooks: true})
@@ -29,25 +30,30 @@ func TestGuardNewHandler(t *testing.T) {
 	require.NotNil(t, sqlMock)
 	require.NotNil(t, gormDB)
 	assert.PanicsWithValue(t, "'m' is nil", func() {
-		guardNewHandler(cfg, gormDB, nil, nil, nil)
+		guardNewHandler(cfg, gormDB, nil, nil, nil, nil)
 	})
 
 	m := &metrics.Metrics{}
-	assert.PanicsWithValue(t, "'rbac' is nil", func() {
-		guardNewHandler(cfg, gormDB, m, nil, nil)
+	assert.PanicsWithValue(t, "'authorizer' is nil", func() {
+		guardNewHandler(cfg, gormDB, m, nil, nil, nil)
 	})
 
-	rbacClient := rbac.NewRbac(t)
+	authorizer := authz.NewAuthorizer(t)
 	assert.PanicsWithValue(t, "'pendo' is nil", func() {
-		guardNewHandler(cfg, gormDB, m, rbacClient, nil)
+		guardNewHandler(cfg, gormDB, m, authorizer, nil, nil)
 	})
 
 	pendoClient := pendo.NewPendo(t)
+	assert.PanicsWithValue(t, "'entitlements' is nil", func() {
+		guardNewHandler(cfg, gormDB, m, authorizer, pendoClient, nil)
+	})
+
+	entitlementsClient := entitlements.NewEntitlements(t)
 	assert.NotPanics(t, func() {
-		guardNewHandler(cfg, gormDB, m, rbacClient, pendoClient)
+		guardNewHandler(cfg, gormDB, m, authorizer, pendoClient, entitlementsClient)
 	})
 
-	rbacClient.AssertExpectations(t)
+	authorizer.AssertExpectations(t)
 	pendoClient.AssertExpectations(t)
 	require.NoError(t, sqlMock.ExpectationsWereMet())
 }
@@ -59,13 +65,14 @@ func TestNewHandler(t *testing.T) {
 	require.NotNil(t, sqlMock)
 	require.NotNil(t, gormDB)
 	m := &metrics.Metrics{}
-	rbacClient := rbac.NewRbac(t)
+	authorizer := authz.NewAuthorizer(t)
 	pendoClient := pendo.NewPendo(t)
+	entitlementsClient := entitlements.NewEntitlements(t)
 	assert.NotPanics(t, func() {
-		require.NotNil(t, NewHandler(cfg, gormDB, m, rbacClient, pendoClient))
+		require.NotNil(t, NewHandler(cfg, gormDB, m, authorizer, nil, pendoClient, entitlementsClient))
 	})
 
-	rbacClient.AssertExpectations(t)
+	authorizer.AssertExpectations(t)
 	pendoClient.AssertExpectations(t)
 	require.NoError(t, sqlMock.ExpectationsWereMet())
 }
@@ -75,9 +82,9 @@ func TestAppSecrets(t *testing.T) {
 	require.NoError(t, err)
 	cfg := test.GetTestConfig()
 
-	rbacClient := rbac.NewRbac(t)
+	authorizer := authz.NewAuthorizer(t)
 	pendoClient := pendo.NewPendo(t)
-	handler := NewHandler(cfg, gormDB, &metrics.Metrics{}, rbacClient, pendoClient)
+	handler := NewHandler(cfg, gormDB, &metrics.Metrics{}, authorizer, nil, pendoClient, entitlements.NewEntitlements(t))
 	app := handler.(*application)
 
 	assert.NotEmpty(t, app.config.Secrets.DomainRegKey)
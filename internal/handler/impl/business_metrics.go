@@ -0,0 +1,87 @@
+package impl
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+)
+
+// The label values of the business metrics are bounded, so the
+// number of series does not grow with the requests.
+const (
+	outcomeSuccess = "success"
+	outcomeFailure = "failure"
+
+	hostConfIssued  = "issued"
+	hostConfRefused = "refused"
+
+	reasonNone                   = "none"
+	reasonNoMatch                = "no_match"
+	reasonConflict               = "conflict"
+	reasonAutoEnrollmentDisabled = "auto_enrollment_disabled"
+	reasonQuota                  = "quota"
+	reasonOther                  = "other"
+
+	agentVersionUnknown = "unknown"
+	agentVersionOther   = "other"
+)
+
+// agentVersionRegexp match the major and minor parts of the
+// ipa-hcc version.
+var agentVersionRegexp = regexp.MustCompile(`^(\d{1,3})\.(\d{1,3})(\D|$)`)
+
+// outcomeLabel return the outcome label for the result of a
+// request handler.
+func outcomeLabel(err error) string {
+	if err != nil {
+		return outcomeFailure
+	}
+	return outcomeSuccess
+}
+
+// agentVersionLabel return the 'major.minor' version of the
+// ipa-hcc agent from the X-Rh-Idm-Version header when it is one of
+// the known versions, 'other' for the rest of versions, or
+// 'unknown' when the version cannot be read.
+func agentVersionLabel(value string, known []string) string {
+	version := header.NewXRHIDMVersionWithHeader(value)
+	if version == nil {
+		return agentVersionUnknown
+	}
+	match := agentVersionRegexp.FindStringSubmatch(version.IPAHCCVersion)
+	if match == nil {
+		return agentVersionUnknown
+	}
+	label := match[1] + "." + match[2]
+	if !slices.Contains(known, label) {
+		return agentVersionOther
+	}
+	return label
+}
+
+// hostConfLabels return the result and reason labels for the
+// result of a host-conf request.
+func hostConfLabels(err error) (result string, reason string) {
+	var httpErr *echo.HTTPError
+	switch {
+	case err == nil:
+		return hostConfIssued, reasonNone
+	case errors.Is(err, repository.ErrAutoEnrollmentDisabled):
+		return hostConfRefused, reasonAutoEnrollmentDisabled
+	case !errors.As(err, &httpErr):
+		return hostConfRefused, reasonOther
+	case httpErr.Code == http.StatusNotFound:
+		return hostConfRefused, reasonNoMatch
+	case httpErr.Code == http.StatusConflict:
+		return hostConfRefused, reasonConflict
+	case httpErr.Code == http.StatusTooManyRequests:
+		return hostConfRefused, reasonQuota
+	default:
+		return hostConfRefused, reasonOther
+	}
+}
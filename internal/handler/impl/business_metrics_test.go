@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutcomeLabel(t *testing.T) {
+	assert.Equal(t, outcomeSuccess, outcomeLabel(nil))
+	assert.Equal(t, outcomeFailure, outcomeLabel(errors.New("boom")))
+}
+
+func TestAgentVersionLabel(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Given    string
+		Expected string
+	}{
+		{Name: "Empty header", Given: "", Expected: agentVersionUnknown},
+		{Name: "Invalid header", Given: "{", Expected: agentVersionUnknown},
+		{Name: "Missing version", Given: `{"ipa":"4.10.0"}`, Expected: agentVersionUnknown},
+		{Name: "Invalid version", Given: `{"ipa-hcc":"latest"}`, Expected: agentVersionUnknown},
+		{Name: "Release", Given: `{"ipa-hcc":"0.9.2"}`, Expected: "0.9"},
+		{Name: "Pre-release", Given: `{"ipa-hcc":"0.12.0rc1"}`, Expected: "0.12"},
+		{Name: "Major and minor", Given: `{"ipa-hcc":"0.13"}`, Expected: "0.13"},
+		{Name: "Unknown version", Given: `{"ipa-hcc":"1.12.0"}`, Expected: agentVersionOther},
+		{
+			Name:     "Random version",
+			Given:    fmt.Sprintf(`{"ipa-hcc":"%d.%d.0"}`, rand.IntN(1000), 100+rand.IntN(900)),
+			Expected: agentVersionOther,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		assert.Equal(t, testCase.Expected, agentVersionLabel(testCase.Given, config.DefaultMetricsAgentVersions))
+	}
+}
+
+func TestHostConfLabels(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Given          error
+		ExpectedResult string
+		ExpectedReason string
+	}{
+		{Name: "Issued", Given: nil, ExpectedResult: hostConfIssued, ExpectedReason: reasonNone},
+		{
+			Name:           "No match",
+			Given:          echo.NewHTTPError(http.StatusNotFound, "no matching domains"),
+			ExpectedResult: hostConfRefused,
+			ExpectedReason: reasonNoMatch,
+		},
+		{
+			Name: "Auto-enrollment disabled",
+			Given: internal_errors.NewHTTPErrorWithInternal(
+				repository.ErrAutoEnrollmentDisabled, http.StatusNotFound, "no matching domains"),
+			ExpectedResult: hostConfRefused,
+			ExpectedReason: reasonAutoEnrollmentDisabled,
+		},
+		{
+			Name:           "Conflict",
+			Given:          echo.NewHTTPError(http.StatusConflict, "matched 2 domains"),
+			ExpectedResult: hostConfRefused,
+			ExpectedReason: reasonConflict,
+		},
+		{
+			Name:           "Quota",
+			Given:          echo.NewHTTPError(http.StatusTooManyRequests, "quota"),
+			ExpectedResult: hostConfRefused,
+			ExpectedReason: reasonQuota,
+		},
+		{
+			Name:           "Other http error",
+			Given:          echo.NewHTTPError(http.StatusForbidden, "forbidden"),
+			ExpectedResult: hostConfRefused,
+			ExpectedReason: reasonOther,
+		},
+		{
+			Name:           "Other error",
+			Given:          errors.New("boom"),
+			ExpectedResult: hostConfRefused,
+			ExpectedReason: reasonOther,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		result, reason := hostConfLabels(testCase.Given)
+		assert.Equal(t, testCase.ExpectedResult, result)
+		assert.Equal(t, testCase.ExpectedReason, reason)
+	}
+}
@@ -0,0 +1,168 @@
+package impl
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+// ApproveDomain approve the registration of a domain which is
+// pending approval, so it can configure hosts.
+// (POST /domains/{uuid}/approve)
+func (a *application) ApproveDomain(
+	ctx echo.Context,
+	UUID uuid.UUID,
+	params public.ApproveDomainParams,
+) error {
+	var (
+		err      error
+		input    public.DomainDecisionRequest
+		decision *model.DomainRegistration
+		orgID    string
+		xrhid    *identity.XRHID
+	)
+	handlerName := "ApproveDomain"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if err = ctx.Bind(&input); err != nil {
+		logger.Error(errUnserializing)
+		return err
+	}
+	if orgID, decision, err = a.domain.interactor.ApproveDomain(
+		xrhid,
+		UUID,
+		&params,
+		&input,
+	); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	return a.decideDomain(ctx, logger, orgID, UUID, decision,
+		model.DomainRegistrationActive, public.DomainApproved)
+}
+
+// RejectDomain reject the registration of a domain which is pending
+// approval; the domain is kept, but it cannot configure hosts.
+// (POST /domains/{uuid}/reject)
+func (a *application) RejectDomain(
+	ctx echo.Context,
+	UUID uuid.UUID,
+	params public.RejectDomainParams,
+) error {
+	var (
+		err      error
+		input    public.DomainDecisionRequest
+		decision *model.DomainRegistration
+		orgID    string
+		xrhid    *identity.XRHID
+	)
+	handlerName := "RejectDomain"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if err = ctx.Bind(&input); err != nil {
+		logger.Error(errUnserializing)
+		return err
+	}
+	if orgID, decision, err = a.domain.interactor.RejectDomain(
+		xrhid,
+		UUID,
+		&params,
+		&input,
+	); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	return a.decideDomain(ctx, logger, orgID, UUID, decision,
+		model.DomainRegistrationRejected, public.DomainRejected)
+}
+
+// decideDomain store the decision about a domain which is pending
+// approval, and publish the event of the decision in the same
+// transaction.
+// state is the new registration state of the domain.
+// event is the webhook event of the decision.
+func (a *application) decideDomain(
+	ctx echo.Context,
+	logger *slog.Logger,
+	orgID string,
+	UUID uuid.UUID,
+	decision *model.DomainRegistration,
+	state string,
+	event public.WebhookEvent,
+) error {
+	var (
+		err    error
+		data   *model.Domain
+		output *public.ReadDomainResponse
+		tx     *gorm.DB
+	)
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if data, err = a.domain.repository.FindByID(c, orgID, UUID); err != nil {
+		logger.Error("failed to read the domain")
+		return err
+	}
+	if !data.IsPendingApproval() {
+		err = internal_errors.ErrDomainNotPending.New(nil,
+			"domain '%s' is not pending approval", UUID.String())
+		logger.Error(err.Error())
+		return err
+	}
+	if data.Registration == nil {
+		data.Registration = &model.DomainRegistration{ID: data.ID}
+	}
+	data.Registration.Decide(*decision.DecidedBy, *decision.DecidedAt, decision.Reason)
+	data.RegistrationState = state
+	if err = a.domain.repository.DecideRegistration(c, orgID, data); err != nil {
+		logger.Error("failed to store the decision about the domain registration")
+		return err
+	}
+	if err = a.enqueueDomainEvent(c, event, orgID, data, nil); err != nil {
+		logger.Error(errWebhookEnqueue)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	logger.Info("domain registration decided",
+		slog.String("registration_state", state),
+		slog.String("decided_by", *decision.DecidedBy),
+		slog.String("reason", pointy.StringValue(decision.Reason, "")),
+	)
+
+	if output, err = a.domain.presenter.Get(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
@@ -0,0 +1,346 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+const (
+	// permissionDomainsUpdate is the relation to update a domain; it
+	// matches the permission of UpdateDomainUser in the security
+	// policy.
+	permissionDomainsUpdate = "idmsvc:domains:update"
+	// permissionDomainsDelete is the relation to delete a domain; it
+	// matches the permission of DeleteDomain in the security policy.
+	permissionDomainsDelete = "idmsvc:domains:delete"
+)
+
+// batchOperation hold the state of an operation of a batch request.
+type batchOperation struct {
+	index     int
+	operation *public.BatchDomainOperation
+	data      *model.Domain
+	output    *public.Domain
+	status    int
+	err       error
+}
+
+// BatchDomains run a list of update, enable, disable and delete
+// operations on the domains of the organization. The operations
+// are run inside one transaction when the request is atomic, so
+// the first failure cancel all of them; else every operation is
+// run inside its own transaction and has its own result. Every
+// operation is checked against the domains granted by rbac, which
+// are resolved before any transaction starts, and has the audit
+// record of its single domain endpoint.
+// (POST /domains:batch)
+func (a *application) BatchDomains(ctx echo.Context, params public.BatchDomainsParams) error {
+	var (
+		err         error
+		input       public.BatchDomainsRequest
+		data        []*model.Domain
+		orgID       string
+		atomic      bool
+		xrhid       *identity.XRHID
+		operations  []*batchOperation
+		permissions map[string]*authz.ResourceSet
+	)
+	handlerName := "BatchDomains"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(slog.String("handler", handlerName))
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if err = ctx.Bind(&input); err != nil {
+		logger.Error(errUnserializing)
+		return err
+	}
+	if orgID, atomic, data, err = a.domain.interactor.Batch(
+		xrhid,
+		&params,
+		&input,
+	); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	logger = logger.With(
+		slog.Bool("atomic", atomic),
+		slog.Int("operations", len(data)),
+	)
+
+	operations = make([]*batchOperation, len(data))
+	for idx := range data {
+		operations[idx] = &batchOperation{
+			index:     idx,
+			operation: &input.Operations[idx],
+			data:      data[idx],
+		}
+	}
+	if permissions, err = a.batchPermissions(ctx, xrhid, operations); err != nil {
+		logger.Error("failed to resolve the domains granted by rbac")
+		return err
+	}
+	if atomic {
+		if err = a.batchDomainsAtomic(ctx, permissions, orgID, operations); err != nil {
+			return err
+		}
+	} else {
+		a.batchDomainsEach(ctx, permissions, orgID, operations)
+	}
+
+	output := public.BatchDomainsResponse{
+		Atomic:  atomic,
+		Results: make([]public.BatchDomainResult, 0, len(operations)),
+	}
+	for _, op := range operations {
+		output.Results = append(output.Results, a.batchDomainResult(ctx, op))
+	}
+	return ctx.JSON(http.StatusOK, output)
+}
+
+// batchPermission return the permission required by an operation.
+func batchPermission(op *batchOperation) string {
+	if op.operation.Op == public.Delete {
+		return permissionDomainsDelete
+	}
+	return permissionDomainsUpdate
+}
+
+// batchPermissions resolve the domains where the identity has the
+// permissions required by the operations, with one lookup for every
+// permission, so no transaction is held while rbac is queried.
+// Return the domains granted by permission, or the authorizer error.
+func (a *application) batchPermissions(
+	ctx echo.Context,
+	xrhid *identity.XRHID,
+	operations []*batchOperation,
+) (map[string]*authz.ResourceSet, error) {
+	c := ctx.Request().Context()
+	subject := authz.NewSubjectFromIdentity(xrhid)
+	permissions := map[string]*authz.ResourceSet{}
+	for _, op := range operations {
+		permission := batchPermission(op)
+		if _, ok := permissions[permission]; ok {
+			continue
+		}
+		resources, err := a.authorizer.LookupResources(c, subject, permission, authz.ResourceTypeDomain)
+		if err != nil {
+			return nil, middleware.AuthorizerError(ctx, err)
+		}
+		permissions[permission] = resources
+	}
+	return permissions, nil
+}
+
+// batchDomainsAtomic run every operation inside one transaction.
+// Return nil when every operation was applied, else the error of
+// the first operation which failed, and no operation is applied.
+func (a *application) batchDomainsAtomic(
+	ctx echo.Context,
+	permissions map[string]*authz.ResourceSet,
+	orgID string,
+	operations []*batchOperation,
+) error {
+	var (
+		err error
+		tx  *gorm.DB
+	)
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	for _, op := range operations {
+		a.batchDomainOperation(c, permissions, orgID, op)
+		if op.err != nil {
+			a.auditBatchOperation(ctx, op)
+			definition := internal_errors.DefinitionOf(op.err)
+			return definition.New(
+				op.err,
+				"'operations[%d]' failed, no operation was applied: %s",
+				op.index, internal_errors.PublicDetail(op.err),
+			)
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	for _, op := range operations {
+		a.auditBatchOperation(ctx, op)
+	}
+	return nil
+}
+
+// batchDomainsEach run every operation inside its own transaction,
+// so the result of every operation is independent.
+func (a *application) batchDomainsEach(
+	ctx echo.Context,
+	permissions map[string]*authz.ResourceSet,
+	orgID string,
+	operations []*batchOperation,
+) {
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	for _, op := range operations {
+		tx := a.db.Begin()
+		if tx.Error != nil {
+			logger.Error(errDBTXBegin)
+			op.status, op.err = http.StatusInternalServerError, tx.Error
+		} else {
+			c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+			a.batchDomainOperation(c, permissions, orgID, op)
+			if op.err == nil {
+				if err := tx.Commit().Error; err != nil {
+					logger.Error(errDBTXCommit)
+					op.status, op.err, op.output = http.StatusInternalServerError, err, nil
+				}
+			}
+			tx.Rollback()
+		}
+		a.auditBatchOperation(ctx, op)
+	}
+}
+
+// batchDomainOperation check the permission and run one operation
+// with the transaction of c; the status and the error are set
+// into op.
+// permissions are the domains granted by permission, resolved by
+// batchPermissions.
+func (a *application) batchDomainOperation(
+	c context.Context,
+	permissions map[string]*authz.ResourceSet,
+	orgID string,
+	op *batchOperation,
+) {
+	var (
+		err         error
+		currentData *model.Domain
+	)
+	logger := app_context.LogFromCtx(c).With(
+		slog.Int("index", op.index),
+		slog.String("op", string(op.operation.Op)),
+		slog.String("uuid", op.operation.DomainId.String()),
+	)
+	fail := func(err error) {
+		op.status, op.err = internal_errors.DefinitionOf(err).Status, err
+	}
+
+	permission := batchPermission(op)
+	if !permissions[permission].Contains(op.operation.DomainId.String()) {
+		a.metrics.RbacDenials.WithLabelValues(permission).Inc()
+		logger.Error("unauthorized", slog.String("permission", permission))
+		fail(internal_errors.ErrForbidden.New(nil,
+			"not allowed to %s domain '%s'", op.operation.Op, op.operation.DomainId.String()))
+		return
+	}
+
+	if op.operation.Op == public.Delete {
+		if err = a.domain.repository.DeleteById(c, orgID, op.operation.DomainId); err != nil {
+			logger.Error("failed to delete domain by ID on the database")
+			fail(batchDomainNotFound(err, "cannot delete unknown domain '%s'.", op.operation.DomainId.String()))
+			return
+		}
+		if err = a.enqueueDomainEvent(c, public.DomainDeleted, orgID, &model.Domain{DomainUuid: op.operation.DomainId}, nil); err != nil {
+			logger.Error(errWebhookEnqueue)
+			fail(err)
+			return
+		}
+		op.status = http.StatusNoContent
+		return
+	}
+
+	if currentData, err = a.domain.repository.FindByID(c, orgID, op.operation.DomainId); err != nil {
+		logger.Error(errDBGeneralError)
+		fail(batchDomainNotFound(err, "cannot update unknown domain '%s'.", op.operation.DomainId.String()))
+		return
+	}
+	if err = a.fillDomainUser(currentData, op.data); err != nil {
+		logger.Error("failed to fill the domain information for a user update")
+		fail(err)
+		return
+	}
+	if err = a.domain.repository.UpdateUser(c, orgID, op.data); err != nil {
+		logger.Error("failed to update domain information in the database for a user update")
+		fail(err)
+		return
+	}
+	if err = a.enqueueDomainEvent(c, public.DomainUpdated, orgID, currentData, nil); err != nil {
+		logger.Error(errWebhookEnqueue)
+		fail(err)
+		return
+	}
+	if op.output, err = a.domain.presenter.UpdateUser(currentData); err != nil {
+		logger.Error(errOutputAdapter)
+		fail(err)
+		return
+	}
+	op.status = http.StatusOK
+}
+
+// batchDomainNotFound return the not found error of the catalogue
+// when err is a record not found error, else err.
+func batchDomainNotFound(err error, format string, a ...any) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return internal_errors.ErrDomainNotFound.New(err, format, a...)
+	}
+	return err
+}
+
+// batchDomainResult translate the state of an operation into its
+// result for the response.
+func (a *application) batchDomainResult(ctx echo.Context, op *batchOperation) public.BatchDomainResult {
+	result := public.BatchDomainResult{
+		Index:    op.index,
+		Op:       op.operation.Op,
+		DomainId: op.operation.DomainId,
+		Status:   op.status,
+		Domain:   op.output,
+	}
+	if op.err != nil {
+		result.Error = &public.ErrorInfo{
+			Id:     ctx.Request().Header.Get(header.HeaderXRequestID),
+			Status: strconv.Itoa(op.status),
+			Title:  internal_errors.PublicDetail(op.err),
+			Code:   pointy.String(string(internal_errors.DefinitionOf(op.err).Code)),
+		}
+	}
+	return result
+}
+
+// auditBatchOperation write the audit record of an operation, as it
+// is written for the PATCH or DELETE /domains/{uuid} endpoints.
+func (a *application) auditBatchOperation(ctx echo.Context, op *batchOperation) {
+	method := http.MethodPatch
+	if op.operation.Op == public.Delete {
+		method = http.MethodDelete
+	}
+	route := strings.TrimSuffix(ctx.Path(), "\\:batch") + "/:uuid"
+	uri := strings.TrimSuffix(ctx.Request().URL.Path, ":batch") + "/" + op.operation.DomainId.String()
+	middleware.Audit(
+		app_context.LogFromCtx(ctx.Request().Context()),
+		method, route, uri, op.status,
+		slog.String("batch_route", ctx.Path()),
+		slog.Int("batch_index", op.index),
+		slog.String("batch_op", string(op.operation.Op)),
+	)
+}
@@ -0,0 +1,89 @@
+package impl
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"gorm.io/gorm"
+)
+
+// permissionDomainsRead is the relation to read a domain; it matches
+// the permission of ExportDomains in the security policy.
+const permissionDomainsRead = "idmsvc:domains:read"
+
+// ExportDomains retrieve a versioned document with the domains of the
+// organization that the caller can read, with their subnet to location
+// mappings, so they can be imported later by the db-tool.
+// (GET /domains/export)
+func (a *application) ExportDomains(ctx echo.Context, params public.ExportDomainsParams) error {
+	var (
+		err       error
+		data      []model.Domain
+		subnets   map[uuid.UUID][]model.DomainLocationSubnet
+		output    *public.ExportDomainsResponse
+		orgID     string
+		resources *authz.ResourceSet
+		tx        *gorm.DB
+		xrhid     *identity.XRHID
+	)
+	handlerName := "ExportDomains"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(slog.String("handler", handlerName))
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if orgID, err = a.domain.interactor.Export(xrhid, &params); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if resources, err = a.authorizer.LookupResources(
+		ctx.Request().Context(),
+		authz.NewSubjectFromIdentity(xrhid),
+		permissionDomainsRead,
+		authz.ResourceTypeDomain,
+	); err != nil {
+		logger.Error("failed to look up the allowed domains")
+		return middleware.AuthorizerError(ctx, err)
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if data, subnets, err = domainexport.Collect(
+		c,
+		a.domain.repository,
+		orgID,
+		allowedDomainIDs(resources),
+	); err != nil {
+		logger.Error("failed to collect the domains from the database")
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.domain.presenter.Export(
+		orgID,
+		time.Now().UTC().Truncate(time.Second),
+		data,
+		subnets,
+	); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
@@ -13,11 +13,18 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"gorm.io/gorm"
 )
 
+// permissionDomainsList is the relation to list the domains; it
+// matches the permission of ListDomains in the security policy.
+const permissionDomainsList = "idmsvc:domains:list"
+
 // About defer Rollback
 //
 // In the case of a commit, the rollback operation does not have any effect into
@@ -35,15 +42,16 @@ func (a *application) ListDomains(
 	params public.ListDomainsParams,
 ) error {
 	var (
-		err    error
-		data   []model.Domain
-		output *public.ListDomainsResponse
-		orgID  string
-		offset int
-		limit  int
-		count  int64
-		tx     *gorm.DB
-		xrhid  *identity.XRHID
+		err       error
+		data      []model.Domain
+		output    *public.ListDomainsResponse
+		orgID     string
+		offset    int
+		limit     int
+		count     int64
+		tx        *gorm.DB
+		xrhid     *identity.XRHID
+		resources *authz.ResourceSet
 	)
 	handlerName := "ListDomains"
 	logger := app_context.LogFromCtx(ctx.Request().Context())
@@ -57,6 +65,15 @@ func (a *application) ListDomains(
 		logger.Error(errInputAdapter)
 		return err
 	}
+	if resources, err = a.authorizer.LookupResources(
+		ctx.Request().Context(),
+		authz.NewSubjectFromIdentity(xrhid),
+		permissionDomainsList,
+		authz.ResourceTypeDomain,
+	); err != nil {
+		logger.Error("failed to look up the allowed domains")
+		return middleware.AuthorizerError(ctx, err)
+	}
 	logger = logger.With(
 		slog.Int("offset", offset),
 		slog.Int("limit", limit),
@@ -71,6 +88,7 @@ func (a *application) ListDomains(
 	if data, count, err = a.domain.repository.List(
 		c,
 		orgID,
+		allowedDomainIDs(resources),
 		offset,
 		limit,
 	); err != nil {
@@ -145,8 +163,8 @@ func (a *application) ReadDomain(
 	); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Error(errDBNotFound)
-			return internal_errors.NewHTTPErrorF(
-				http.StatusNotFound,
+			return internal_errors.ErrDomainNotFound.New(
+				err,
 				"cannot read unknown domain '%s'.",
 				UUID.String(),
 			)
@@ -209,8 +227,8 @@ func (a *application) DeleteDomain(
 	); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Error(errDBNotFound)
-			return internal_errors.NewHTTPErrorF(
-				http.StatusNotFound,
+			return internal_errors.ErrDomainNotFound.New(
+				err,
 				"cannot delete unknown domain '%s'.",
 				UUID.String(),
 			)
@@ -218,6 +236,10 @@ func (a *application) DeleteDomain(
 		logger.Error("failed to delete domain by ID on the database")
 		return err
 	}
+	if err = a.enqueueDomainEvent(c, public.DomainDeleted, orgId, &model.Domain{DomainUuid: domainUUID}, nil); err != nil {
+		logger.Error(errWebhookEnqueue)
+		return err
+	}
 	if tx.Commit(); tx.Error != nil {
 		logger.Error(errDBTXCommit)
 		return err
@@ -238,6 +260,18 @@ func (a *application) DeleteDomain(
 func (a *application) RegisterDomain(
 	ctx echo.Context,
 	params public.RegisterDomainParams,
+) error {
+	err := a.registerDomain(ctx, params)
+	a.metrics.DomainRegistrations.WithLabelValues(
+		outcomeLabel(err),
+		agentVersionLabel(params.XRhIdmVersion, a.config.Metrics.AgentVersions),
+	).Inc()
+	return err
+}
+
+func (a *application) registerDomain(
+	ctx echo.Context,
+	params public.RegisterDomainParams,
 ) error {
 	var (
 		err           error
@@ -248,6 +282,8 @@ func (a *application) RegisterDomain(
 		output        *public.RegisterDomainResponse
 		clientVersion *header.XRHIDMVersion
 		xrhid         *identity.XRHID
+		quota         model.Quota
+		approval      bool
 	)
 	handlerName := "RegisterDomain"
 	logger := app_context.LogFromCtx(ctx.Request().Context())
@@ -256,6 +292,9 @@ func (a *application) RegisterDomain(
 		logger.Error(errXRHIDIsNil)
 		return err
 	}
+	if err = a.ensureEntitled(ctx, xrhid); err != nil {
+		return err
+	}
 
 	if err = ctx.Bind(&input); err != nil {
 		logger.Error(errUnserializing)
@@ -263,7 +302,7 @@ func (a *application) RegisterDomain(
 	}
 
 	if orgId, clientVersion, data, err = a.domain.interactor.Register(
-		a.config.Secrets.DomainRegKey,
+		a.config.Secrets.DomainRegKeys(),
 		xrhid,
 		&params,
 		&input,
@@ -281,10 +320,10 @@ func (a *application) RegisterDomain(
 	)
 
 	updateServerRSHMId := xrhid.Identity.System.CommonName
-	if err = ensureUpdateServerEnabledForUpdates(
+	if err = ensureUpdateAgentEnabledForDomain(
 		ctx.Request().Context(),
 		updateServerRSHMId,
-		data.IpaDomain.Servers,
+		data,
 	); err != nil {
 		logger.Error("failed to ensure that the requesting server is authorized for updating the data, on register domain process")
 		return err
@@ -297,10 +336,38 @@ func (a *application) RegisterDomain(
 	defer tx.Rollback()
 
 	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if quota, err = a.quotaFor(c, orgId); err != nil {
+		logger.Error("failed to read the quota of the organization")
+		return err
+	}
+	if err = a.ensureDomainsQuota(c, orgId, quota); err != nil {
+		logger.Error("failed because the organization reached the domains quota")
+		return err
+	}
+	if err = ensureServersQuota(quota, data); err != nil {
+		logger.Error("failed because the domain exceeds the servers quota")
+		return err
+	}
+	if approval, err = a.requireRegistrationApproval(c, orgId); err != nil {
+		logger.Error("failed to read the settings of the organization")
+		return err
+	}
+	if approval {
+		if data.Registration, err = newDomainRegistration(updateServerRSHMId, clientVersion, data); err != nil {
+			logger.Error("failed to build the registration pending approval")
+			return err
+		}
+		data.RegistrationState = model.DomainRegistrationPendingApproval
+		logger.Info("domain registration is pending approval")
+	}
 	if err = a.domain.repository.Register(c, orgId, data); err != nil {
 		logger.Error("failed to register domain on the database")
 		return err
 	}
+	if err = a.enqueueDomainEvent(c, public.DomainCreated, orgId, data, nil); err != nil {
+		logger.Error(errWebhookEnqueue)
+		return err
+	}
 
 	if err = tx.Commit().Error; err != nil {
 		logger.Error(errDBTXCommit)
@@ -326,6 +393,15 @@ func (a *application) RegisterDomain(
 // params contains the x-rh-identity, x-rh-insights-request-id
 // and x-rh-idm-version header contents.
 func (a *application) UpdateDomainAgent(ctx echo.Context, domain_id uuid.UUID, params public.UpdateDomainAgentParams) error {
+	err := a.updateDomainAgent(ctx, domain_id, params)
+	a.metrics.DomainAgentUpdates.WithLabelValues(
+		outcomeLabel(err),
+		agentVersionLabel(params.XRhIdmVersion, a.config.Metrics.AgentVersions),
+	).Inc()
+	return err
+}
+
+func (a *application) updateDomainAgent(ctx echo.Context, domain_id uuid.UUID, params public.UpdateDomainAgentParams) error {
 	var (
 		err           error
 		input         public.UpdateDomainAgentRequest
@@ -336,6 +412,7 @@ func (a *application) UpdateDomainAgent(ctx echo.Context, domain_id uuid.UUID, p
 		output        *public.UpdateDomainAgentResponse
 		clientVersion *header.XRHIDMVersion
 		xrhid         *identity.XRHID
+		quota         model.Quota
 	)
 	handlerName := "UpdateDomainAgent"
 	logger := app_context.LogFromCtx(ctx.Request().Context())
@@ -376,10 +453,10 @@ func (a *application) UpdateDomainAgent(ctx echo.Context, domain_id uuid.UUID, p
 	// Check that the update server is included in the request
 	updateServerRSHMId := xrhid.Identity.System.CommonName
 	logger = logger.With(slog.String("server", updateServerRSHMId))
-	if err = ensureUpdateServerEnabledForUpdates(
+	if err = ensureUpdateAgentEnabledForDomain(
 		ctx.Request().Context(),
 		updateServerRSHMId,
-		data.IpaDomain.Servers,
+		data,
 	); err != nil {
 		logger.Error("failed to ensure that the requesting server is authorized for updating the data, on updating domain process from a system")
 		return err
@@ -390,20 +467,20 @@ func (a *application) UpdateDomainAgent(ctx echo.Context, domain_id uuid.UUID, p
 	if currentData, err = a.domain.repository.FindByID(c, orgID, domain_id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Error(errDBNotFound)
-			return internal_errors.NewHTTPErrorF(
-				http.StatusNotFound,
-				"%s",
-				err.Error(),
+			return internal_errors.ErrDomainNotFound.New(
+				err,
+				"cannot update unknown domain '%s'.",
+				domain_id.String(),
 			)
 		}
 		logger.Error(errDBGeneralError)
 		return err
 	}
 
-	if err = ensureSubscriptionManagerIDAuthorizedToUpdate(
+	if err = ensureUpdateAgentAuthorizedForDomain(
 		c,
 		updateServerRSHMId,
-		currentData.IpaDomain.Servers,
+		currentData,
 	); err != nil {
 		logger.Error("failed because the requesting server is not authorized to update the domain")
 		return err
@@ -421,27 +498,43 @@ func (a *application) UpdateDomainAgent(ctx echo.Context, domain_id uuid.UUID, p
 		)
 	}
 
-	if data.IpaDomain != nil && currentData.IpaDomain != nil &&
-		data.IpaDomain.RealmName != nil && currentData.IpaDomain.RealmName != nil &&
-		*data.IpaDomain.RealmName != *currentData.IpaDomain.RealmName {
-		logger.Error("failed because realm_name is immutable and cannot be modified, on updating domain data from a server",
-			slog.String("realm_name", *currentData.IpaDomain.RealmName),
+	if data.Type != nil && currentData.Type != nil && *data.Type != *currentData.Type {
+		logger.Error("failed because domain_type is immutable and cannot be modified, on updating domain data from a server",
+			slog.String("domain_type", model.DomainTypeString(*currentData.Type)),
 		)
 		return internal_errors.NewHTTPErrorF(
 			http.StatusBadRequest,
-			"'realm_name' may not be changed",
+			"'domain_type' may not be changed",
 		)
 	}
 
+	if err = validateUpdateAgentForDomain(currentData, data); err != nil {
+		logger.Error("failed because the update modifies immutable fields of the domain type, on updating domain data from a server")
+		return err
+	}
+
 	if err = a.fillDomain(currentData, data); err != nil {
 		logger.Error("failed to fill the new domain information for an agent update")
 		return err
 	}
 
+	if quota, err = a.quotaFor(c, orgID); err != nil {
+		logger.Error("failed to read the quota of the organization")
+		return err
+	}
+	if err = ensureServersQuota(quota, currentData); err != nil {
+		logger.Error("failed because the domain exceeds the servers quota")
+		return err
+	}
+
 	if err = a.domain.repository.UpdateAgent(c, orgID, currentData); err != nil {
 		logger.Error("failed to update the new data in the database")
 		return err
 	}
+	if err = a.enqueueDomainEvent(c, public.DomainUpdated, orgID, currentData, nil); err != nil {
+		logger.Error(errWebhookEnqueue)
+		return err
+	}
 	if err = tx.Commit().Error; err != nil {
 		logger.Error(errDBTXCommit)
 		return tx.Error
@@ -511,10 +604,10 @@ func (a *application) UpdateDomainUser(ctx echo.Context, domain_id uuid.UUID, pa
 	if currentData, err = a.domain.repository.FindByID(c, orgID, domain_id); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Error(errDBNotFound)
-			return internal_errors.NewHTTPErrorF(
-				http.StatusNotFound,
-				"%s",
-				err.Error(),
+			return internal_errors.ErrDomainNotFound.New(
+				err,
+				"cannot update unknown domain '%s'.",
+				domain_id.String(),
 			)
 		}
 		logger.Error(errDBGeneralError)
@@ -530,6 +623,10 @@ func (a *application) UpdateDomainUser(ctx echo.Context, domain_id uuid.UUID, pa
 		logger.Error("failed to update domain information in the database for a user update")
 		return err
 	}
+	if err = a.enqueueDomainEvent(c, public.DomainUpdated, orgID, currentData, nil); err != nil {
+		logger.Error(errWebhookEnqueue)
+		return err
+	}
 	if err = tx.Commit().Error; err != nil {
 		logger.Error(errDBTXCommit)
 		return tx.Error
@@ -551,6 +648,7 @@ func (a *application) CreateDomainToken(ctx echo.Context, params public.CreateDo
 		input      public.DomainRegTokenRequest
 		token      *repository.DomainRegToken
 		domainType public.DomainType
+		claims     domain_token.Claims
 		orgID      string
 		output     *public.DomainRegToken
 		xrhid      *identity.XRHID
@@ -562,12 +660,15 @@ func (a *application) CreateDomainToken(ctx echo.Context, params public.CreateDo
 		logger.Error(errXRHIDIsNil, slog.String("handler", handlerName))
 		return err
 	}
+	if err = a.ensureEntitled(ctx, xrhid); err != nil {
+		return err
+	}
 
 	if err = ctx.Bind(&input); err != nil {
 		logger.Error(errUnserializing)
 		return err
 	}
-	if orgID, domainType, err = a.domain.interactor.CreateDomainToken(
+	if orgID, domainType, claims, err = a.domain.interactor.CreateDomainToken(
 		xrhid,
 		&params,
 		&input,
@@ -575,7 +676,14 @@ func (a *application) CreateDomainToken(ctx echo.Context, params public.CreateDo
 		logger.Error(errInputAdapter)
 		return err
 	}
-	logger = logger.With(slog.String("domain_type", string(domainType)))
+	logger = logger.With(
+		slog.String("domain_type", string(domainType)),
+		slog.String("claim_domain_name", claims.DomainName),
+		slog.String("claim_label", claims.Label),
+	)
+	if claims.SubscriptionManagerId != uuid.Nil {
+		logger = logger.With(slog.String("claim_subscription_manager_id", claims.SubscriptionManagerId.String()))
+	}
 
 	validity := time.Duration(a.config.Application.TokenExpirationTimeSeconds) * time.Second
 	if token, err = a.domain.repository.CreateDomainToken(
@@ -584,10 +692,12 @@ func (a *application) CreateDomainToken(ctx echo.Context, params public.CreateDo
 		validity,
 		orgID,
 		domainType,
+		claims,
 	); err != nil {
 		logger.Error("failed to create a registration token")
 		return err
 	}
+	a.metrics.DomainTokens.WithLabelValues(string(domainType)).Inc()
 
 	if output, err = a.domain.presenter.CreateDomainToken(token); err != nil {
 		logger.Error(errOutputAdapter)
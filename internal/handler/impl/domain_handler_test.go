@@ -0,0 +1,121 @@
+package impl
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	mock_interactor "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/interactor"
+	mock_repository "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+	"github.com/prometheus/client_golang/prometheus"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+func helperDomainHandlerNotFound(t *testing.T) (*application, *mock_interactor.DomainInteractor, *mock_repository.DomainRepository) {
+	sqlMock, db, err := test.NewSqlMock(&gorm.Session{SkipHooks: true})
+	require.NoError(t, err)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+	t.Cleanup(func() {
+		assert.NoError(t, sqlMock.ExpectationsWereMet())
+	})
+	interactor := mock_interactor.NewDomainInteractor(t)
+	repository := mock_repository.NewDomainRepository(t)
+	return &application{
+		config:  &config.Config{},
+		db:      db,
+		metrics: metrics.NewMetrics(prometheus.NewRegistry()),
+		domain: domainComponent{
+			interactor: interactor,
+			repository: repository,
+		},
+	}, interactor, repository
+}
+
+func helperDomainHandlerContext(xrhid *identity.XRHID) echo.Context {
+	req := httptest.NewRequest(http.MethodPut, "/", http.NoBody)
+	req = req.WithContext(app_context.CtxWithLog(req.Context(), slog.Default()))
+	ctx := middleware.NewContext(echo.New().NewContext(req, httptest.NewRecorder()))
+	ctx.SetXRHID(xrhid)
+	return ctx
+}
+
+func assertDomainNotFound(t *testing.T, err error) {
+	require.Error(t, err)
+	assert.Equal(t, internal_errors.ErrDomainNotFound, internal_errors.DefinitionOf(err))
+}
+
+func TestDomainHandlersNotFound(t *testing.T) {
+	const orgID = "12345"
+	subscriptionManagerID := uuid.NewString()
+	domainID := uuid.New()
+	userXRHID := &identity.XRHID{Identity: identity.Identity{OrgID: orgID, Type: "User"}}
+	systemXRHID := &identity.XRHID{Identity: identity.Identity{
+		OrgID:  orgID,
+		Type:   "System",
+		System: &identity.System{CommonName: subscriptionManagerID},
+	}}
+	errNotFound := internal_errors.ErrDomainNotFound.New(gorm.ErrRecordNotFound, "unknown domain '%s'", domainID.String())
+
+	t.Run("ReadDomain", func(t *testing.T) {
+		app, interactor, repository := helperDomainHandlerNotFound(t)
+		params := public.ReadDomainParams{}
+		interactor.On("GetByID", userXRHID, &params).Return(orgID, nil)
+		repository.On("FindByID", mock.Anything, orgID, domainID).Return(nil, errNotFound)
+		assertDomainNotFound(t, app.ReadDomain(helperDomainHandlerContext(userXRHID), domainID, params))
+	})
+
+	t.Run("DeleteDomain", func(t *testing.T) {
+		app, interactor, repository := helperDomainHandlerNotFound(t)
+		params := public.DeleteDomainParams{}
+		interactor.On("Delete", userXRHID, domainID, &params).Return(orgID, domainID, nil)
+		repository.On("DeleteById", mock.Anything, orgID, domainID).Return(errNotFound)
+		assertDomainNotFound(t, app.DeleteDomain(helperDomainHandlerContext(userXRHID), domainID, params))
+	})
+
+	t.Run("UpdateDomainAgent", func(t *testing.T) {
+		app, interactor, repository := helperDomainHandlerNotFound(t)
+		params := public.UpdateDomainAgentParams{}
+		data := &model.Domain{
+			Type: pointy.Uint(ipa.TypeID),
+			TypeData: &ipa.Ipa{
+				Servers: []ipa.IpaServer{{
+					FQDN:            "server.mydomain.example",
+					RHSMId:          pointy.String(subscriptionManagerID),
+					HCCUpdateServer: true,
+				}},
+			},
+		}
+		interactor.On("UpdateAgent", systemXRHID, domainID, &params, mock.Anything).
+			Return(orgID, header.NewXRHIDMVersion("0.12", "4.11.0", "rhel", "9.4"), data, nil)
+		repository.On("FindByID", mock.Anything, orgID, domainID).Return(nil, errNotFound)
+		assertDomainNotFound(t, app.UpdateDomainAgent(helperDomainHandlerContext(systemXRHID), domainID, params))
+	})
+
+	t.Run("UpdateDomainUser", func(t *testing.T) {
+		app, interactor, repository := helperDomainHandlerNotFound(t)
+		params := public.UpdateDomainUserParams{}
+		interactor.On("UpdateUser", userXRHID, domainID, &params, mock.Anything).
+			Return(orgID, &model.Domain{}, nil)
+		repository.On("FindByID", mock.Anything, orgID, domainID).Return(nil, errNotFound)
+		assertDomainNotFound(t, app.UpdateDomainUser(helperDomainHandlerContext(userXRHID), domainID, params))
+	})
+}
@@ -0,0 +1,132 @@
+package impl
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"gorm.io/gorm"
+)
+
+// ReadDomainLocationSubnets retrieve the subnet to location mappings
+// of a domain.
+// (GET /domains/{uuid}/location-subnets)
+func (a *application) ReadDomainLocationSubnets(
+	ctx echo.Context,
+	UUID uuid.UUID,
+	params public.ReadDomainLocationSubnetsParams,
+) error {
+	var (
+		err    error
+		data   []model.DomainLocationSubnet
+		output *public.LocationSubnetsResponse
+		orgID  string
+		tx     *gorm.DB
+		xrhid  *identity.XRHID
+	)
+	handlerName := "ReadDomainLocationSubnets"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if orgID, err = a.domain.interactor.ReadLocationSubnets(
+		xrhid,
+		UUID,
+		&params,
+	); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if data, err = a.domain.repository.ListLocationSubnets(c, orgID, UUID); err != nil {
+		logger.Error("failed to list the subnet to location mappings")
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.domain.presenter.LocationSubnets(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
+
+// UpdateDomainLocationSubnets replace the subnet to location mappings
+// of a domain.
+// (PUT /domains/{uuid}/location-subnets)
+func (a *application) UpdateDomainLocationSubnets(
+	ctx echo.Context,
+	UUID uuid.UUID,
+	params public.UpdateDomainLocationSubnetsParams,
+) error {
+	var (
+		err    error
+		input  public.LocationSubnets
+		data   []model.DomainLocationSubnet
+		output *public.LocationSubnetsResponse
+		orgID  string
+		tx     *gorm.DB
+		xrhid  *identity.XRHID
+	)
+	handlerName := "UpdateDomainLocationSubnets"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if err = ctx.Bind(&input); err != nil {
+		logger.Error(errUnserializing)
+		return err
+	}
+	if orgID, data, err = a.domain.interactor.UpdateLocationSubnets(
+		xrhid,
+		UUID,
+		&params,
+		&input,
+	); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if err = a.domain.repository.UpdateLocationSubnets(c, orgID, UUID, data); err != nil {
+		logger.Error("failed to update the subnet to location mappings")
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.domain.presenter.LocationSubnets(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
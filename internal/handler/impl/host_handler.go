@@ -1,15 +1,19 @@
 package impl
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/webhook"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"gorm.io/gorm"
 )
@@ -25,7 +29,9 @@ func (a *application) HostConf(
 	fqdn string,
 	params public.HostConfParams,
 ) error {
-	if err := a.hostConf(ctx, inventoryId, fqdn, params); err != nil {
+	err := a.hostConf(ctx, inventoryId, fqdn, params)
+	a.metrics.HostConfs.WithLabelValues(hostConfLabels(err)).Inc()
+	if err != nil {
 		sendPendoTrackEvent(ctx, a.pendo, pendoHostConfFailure)
 		return err
 	}
@@ -40,15 +46,17 @@ func (a *application) hostConf(
 	params public.HostConfParams,
 ) error {
 	var (
-		err     error
-		input   public.HostConf
-		domain  *model.Domain
-		output  *public.HostConfResponse
-		options *interactor.HostConfOptions
-		hctoken public.HostToken
-		tx      *gorm.DB
-		xrhid   *identity.XRHID
-		keys    []jwk.Key
+		err      error
+		input    public.HostConf
+		domain   *model.Domain
+		output   *public.HostConfResponse
+		options  *interactor.HostConfOptions
+		location *model.HostLocation
+		hctoken  public.HostToken
+		tx       *gorm.DB
+		xrhid    *identity.XRHID
+		keys     []jwk.Key
+		quota    model.Quota
 	)
 	handlerName := "HostConf"
 	logger := app_context.LogFromCtx(ctx.Request().Context())
@@ -58,6 +66,9 @@ func (a *application) hostConf(
 		logger.Error(errXRHIDIsNil)
 		return err
 	}
+	if err = a.ensureEntitled(ctx, xrhid); err != nil {
+		return err
+	}
 
 	if err = ctx.Bind(&input); err != nil {
 		logger.Error(errUnserializing)
@@ -87,13 +98,33 @@ func (a *application) hostConf(
 		return err
 	}
 
+	if quota, err = a.quotaFor(c, options.OrgId); err != nil {
+		logger.Error("failed to read the quota of the organization")
+		return err
+	}
+	now := time.Now()
+	if err = a.ensureHostConfQuota(ctx, app_context.CtxWithDB(c, a.db), options.OrgId, quota, now); err != nil {
+		logger.Error("failed because the organization reached the host-conf quota")
+		return err
+	}
+	defer func() {
+		if err != nil {
+			a.releaseHostConfQuota(app_context.CtxWithDB(context.WithoutCancel(c), a.db), options.OrgId, quota, now)
+		}
+	}()
+
+	if location, err = a.matchHostLocation(c, options, domain); err != nil {
+		logger.Error("failed to match location on requesting host-conf")
+		return err
+	}
+
 	if keys, err = a.hostconfjwk.repository.GetPrivateSigningKeys(c); err != nil {
 		logger.Error("failed to read private signing keys")
 		return err
 	}
 	if len(keys) == 0 {
 		logger.Error("failed because no keys available")
-		err = echo.NewHTTPError(http.StatusInternalServerError, "no keys available")
+		err = internal_errors.ErrHostConfNoSigningKey.New(nil, "no keys available")
 		return err
 	}
 
@@ -106,17 +137,45 @@ func (a *application) hostConf(
 		logger.Error("failed to sign host-conf token")
 		return err
 	}
+	if err = a.enqueueDomainEvent(c, public.HostConfigured, options.OrgId, domain, &webhook.Host{
+		InventoryId: inventoryId,
+		Fqdn:        fqdn,
+	}); err != nil {
+		logger.Error(errWebhookEnqueue)
+		return err
+	}
 
-	if tx.Commit(); tx.Error != nil {
+	if err = tx.Commit().Error; err != nil {
 		logger.Error(errDBTXCommit)
-		return tx.Error
+		return err
 	}
 
 	if output, err = a.host.presenter.HostConf(
-		domain, hctoken,
+		domain, location, hctoken,
 	); err != nil {
 		logger.Error(errOutputAdapter)
 		return err
 	}
 	return ctx.JSON(http.StatusOK, *output)
 }
+
+// matchHostLocation return the location of the requesting host with
+// the location policy of the request, or nil when the location of
+// the host is unknown.
+// c is the request context with the db transaction.
+// options is the host-conf request.
+// domain is the matched domain.
+func (a *application) matchHostLocation(
+	c context.Context,
+	options *interactor.HostConfOptions,
+	domain *model.Domain,
+) (*model.HostLocation, error) {
+	location, err := a.host.repository.MatchLocation(c, options, domain)
+	if err != nil || location == nil {
+		return nil, err
+	}
+	return &model.HostLocation{
+		Name:     *location,
+		Restrict: options.RestrictLocation,
+	}, nil
+}
@@ -1,22 +1,60 @@
 package impl
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 	api_private "github.com/podengo-project/idmsvc-backend/internal/api/private"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
 )
 
 // Liveness kubernetes probe endpoint
 // (GET /livez)
 func (a application) GetLivez(ctx echo.Context) error {
-	// TODO Add probes to the added services
-	return ctx.JSON(http.StatusOK, api_private.HealthySuccess(api_private.Ok))
+	if a.health == nil {
+		return healthReportResponse(ctx, &health.Report{Status: health.StatusOk})
+	}
+	return healthReportResponse(ctx, a.health.Liveness(ctx.Request().Context()))
 }
 
 // Readiness kubernetes probe endpoint
 // (GET /readyz)
 func (a application) GetReadyz(ctx echo.Context) error {
-	// TODO Add probes to the added services
-	return ctx.JSON(http.StatusOK, api_private.HealthySuccess(api_private.Ok))
+	if a.health == nil {
+		return healthReportResponse(ctx, &health.Report{Status: health.StatusOk})
+	}
+	return healthReportResponse(ctx, a.health.Readiness(ctx.Request().Context()))
+}
+
+// healthReportResponse write the report with 503 status code when a
+// critical check failed, else 200.
+func healthReportResponse(ctx echo.Context, report *health.Report) error {
+	output := api_private.HealthReport{
+		Status: api_private.HealthReportStatus(report.Status),
+		Checks: make([]api_private.HealthCheck, 0, len(report.Checks)),
+	}
+	for i := range report.Checks {
+		result := &report.Checks[i]
+		check := api_private.HealthCheck{
+			Name:       result.Name,
+			Severity:   api_private.HealthCheckSeverity(result.Severity),
+			Status:     api_private.HealthCheckStatus(result.Status),
+			CheckedAt:  result.CheckedAt,
+			DurationMs: result.Duration.Milliseconds(),
+		}
+		if result.Message != "" {
+			message := result.Message
+			check.Message = &message
+		}
+		output.Checks = append(output.Checks, check)
+	}
+	if report.Status == health.StatusFailed {
+		if retryAfter := report.RetryAfter(); retryAfter > 0 {
+			ctx.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		}
+		return ctx.JSON(http.StatusServiceUnavailable, api_private.HealthyFailure(output))
+	}
+	return ctx.JSON(http.StatusOK, api_private.HealthySuccess(output))
 }
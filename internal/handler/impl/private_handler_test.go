@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	api_private "github.com/podengo-project/idmsvc-backend/internal/api/private"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReadyz(t *testing.T) {
+	e := echo.New()
+	get := func(app *application) (*httptest.ResponseRecorder, api_private.HealthReport) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/private/readyz", http.NoBody), rec)
+		require.NoError(t, app.GetReadyz(c))
+		var report api_private.HealthReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return rec, report
+	}
+	ok := func(ctx context.Context) error { return nil }
+	var retryAfter time.Duration
+	database := func(ctx context.Context) error {
+		if retryAfter > 0 {
+			return &health.UnavailableError{RetryAfter: retryAfter, Err: errors.New("too many connections")}
+		}
+		return nil
+	}
+	breakerOpen := false
+	authzState := func() string {
+		if breakerOpen {
+			return "rbac circuit breaker is open"
+		}
+		return ""
+	}
+
+	// No health registry
+	rec, report := get(&application{})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, api_private.HealthReportStatusOk, report.Status)
+	assert.Empty(t, report.Checks)
+
+	// Every check succeed
+	registry := health.NewRegistry(0, time.Second)
+	registry.Register(health.Check{Name: "database", Severity: health.Critical, Probe: database})
+	registry.Register(health.Check{Name: "authz", Severity: health.Critical, Probe: ok, State: authzState})
+	rec, report = get(&application{health: registry})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, api_private.HealthReportStatusOk, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "authz", report.Checks[0].Name)
+	assert.Equal(t, api_private.Critical, report.Checks[0].Severity)
+	assert.Equal(t, api_private.HealthCheckStatusOk, report.Checks[0].Status)
+	assert.Nil(t, report.Checks[0].Message)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+
+	// A non-critical check fails
+	registry.Register(health.Check{Name: "pendo", Severity: health.NonCritical, Probe: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}})
+	rec, report = get(&application{health: registry})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, api_private.HealthReportStatusDegraded, report.Status)
+	require.Len(t, report.Checks, 3)
+	assert.Equal(t, api_private.NonCritical, report.Checks[2].Severity)
+	assert.Equal(t, api_private.HealthCheckStatusFailed, report.Checks[2].Status)
+	require.NotNil(t, report.Checks[2].Message)
+	assert.Equal(t, "connection refused", *report.Checks[2].Message)
+
+	// The open circuit breaker is reported, but the service is ready
+	breakerOpen = true
+	rec, report = get(&application{health: registry})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+	assert.Equal(t, api_private.HealthCheckStatusOk, report.Checks[0].Status)
+	require.NotNil(t, report.Checks[0].Message)
+	assert.Equal(t, "rbac circuit breaker is open", *report.Checks[0].Message)
+
+	// A critical check fails
+	retryAfter = 10 * time.Second
+	rec, report = get(&application{health: registry})
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Retry-After"))
+	assert.Equal(t, api_private.HealthReportStatusFailed, report.Status)
+	assert.Equal(t, api_private.HealthCheckStatusFailed, report.Checks[1].Status)
+	require.NotNil(t, report.Checks[1].Message)
+	assert.Equal(t, "too many connections", *report.Checks[1].Message)
+}
+
+func TestGetLivez(t *testing.T) {
+	e := echo.New()
+	get := func(app *application) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/private/livez", http.NoBody), rec)
+		require.NoError(t, app.GetLivez(c))
+		return rec
+	}
+	running := true
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	// No health registry
+	rec := get(&application{})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok","checks":[]}`, rec.Body.String())
+
+	// Only the liveness checks are run
+	registry := health.NewRegistry(0, time.Second)
+	registry.Register(health.Check{Name: "database", Probe: fail})
+	registry.Register(health.Check{Name: "kafka-consumer", Liveness: true, Probe: func(ctx context.Context) error {
+		if !running {
+			return errors.New("kafka consumer is not running")
+		}
+		return nil
+	}})
+	rec = get(&application{health: registry})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	running = false
+	rec = get(&application{health: registry})
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
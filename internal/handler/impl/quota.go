@@ -0,0 +1,143 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+// ensureEntitled check that the organization of xrhid is entitled
+// to use Directory & Domain Services.
+// Return nil when it is entitled, a 403 error when it is not, or a
+// 503 error when the entitlements could not be checked.
+func (a *application) ensureEntitled(ctx echo.Context, xrhid *identity.XRHID) error {
+	c := ctx.Request().Context()
+	logger := app_context.LogFromCtx(c)
+	entitled, err := a.entitlements.IsEntitled(c, xrhid)
+	if err != nil {
+		logger.Error("failed to check the entitlements", slog.String("error", err.Error()))
+		return internal_errors.ErrEntitlementsUnavailable.New(err,
+			"the entitlements could not be checked")
+	}
+	if !entitled {
+		logger.Warn("organization is not entitled")
+		return internal_errors.ErrNotEntitled.New(nil,
+			"organization '%s' is not entitled to use Directory & Domain Services", xrhid.Identity.OrgID)
+	}
+	return nil
+}
+
+// quotaFor return the effective quota of the organization, which is
+// the configured defaults with the overrides of the organization.
+// c is the request context with the db transaction.
+func (a *application) quotaFor(c context.Context, orgID string) (model.Quota, error) {
+	defaults := model.Quota{
+		MaxDomains:          a.config.Application.Quotas.MaxDomains,
+		MaxServersPerDomain: a.config.Application.Quotas.MaxServersPerDomain,
+		MaxHostConfPerDay:   a.config.Application.Quotas.MaxHostConfPerDay,
+	}
+	overrides, err := a.quota.repository.FindByOrgID(c, orgID)
+	if err != nil {
+		return model.Quota{}, err
+	}
+	return overrides.Apply(defaults), nil
+}
+
+// ensureDomainsQuota check that the organization can register one
+// more domain. The domains of the organization are locked until the
+// end of the transaction, so concurrent registrations can not exceed
+// the quota together.
+// c is the request context with the db transaction.
+// Return nil on success, else a 403 error or the database error.
+func (a *application) ensureDomainsQuota(c context.Context, orgID string, quota model.Quota) error {
+	if quota.MaxDomains == 0 {
+		return nil
+	}
+	if err := a.quota.repository.LockDomains(c, orgID); err != nil {
+		return err
+	}
+	count, err := a.quota.repository.CountDomains(c, orgID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(quota.MaxDomains) {
+		return internal_errors.ErrQuotaDomains.New(nil,
+			"the organization reached the maximum of %d domains", quota.MaxDomains)
+	}
+	return nil
+}
+
+// ensureServersQuota check that the domain does not exceed the
+// servers allowed for the organization; the servers are counted by
+// the handler of the domain type.
+// Return nil on success, else a 403 error.
+func ensureServersQuota(quota model.Quota, data *model.Domain) error {
+	if quota.MaxServersPerDomain == 0 {
+		return nil
+	}
+	if data == nil || data.Type == nil {
+		return internal_errors.NilArgError("data")
+	}
+	h, ok := domaintype.Lookup(*data.Type)
+	if !ok {
+		return fmt.Errorf("'Type' is invalid")
+	}
+	if count := h.ServerCount(data); count > quota.MaxServersPerDomain {
+		return internal_errors.ErrQuotaServers.New(nil,
+			"the domain has %d servers, but the maximum is %d", count, quota.MaxServersPerDomain)
+	}
+	return nil
+}
+
+// ensureHostConfQuota count the host-conf request for the current
+// day (UTC) when it does not exceed the quota. The usage is counted
+// with a single statement out of the transaction of the request, so
+// the requests of the organization do not wait for each other; the
+// caller undo it with releaseHostConfQuota when the request fails.
+// c is the request context with the db connector, not the
+// transaction.
+// Return nil on success, else a 429 error with the Retry-After
+// header set to the start of the next day, or the database error.
+func (a *application) ensureHostConfQuota(ctx echo.Context, c context.Context, orgID string, quota model.Quota, now time.Time) error {
+	if quota.MaxHostConfPerDay == 0 {
+		return nil
+	}
+	counted, err := a.quota.repository.IncrementHostConf(c, orgID, now, quota.MaxHostConfPerDay)
+	if err != nil {
+		return err
+	}
+	if !counted {
+		now = now.UTC()
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		retryAfter := int(math.Ceil(tomorrow.Sub(now).Seconds()))
+		ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return internal_errors.ErrQuotaHostConf.New(nil,
+			"the organization reached the maximum of %d host-conf requests per day", quota.MaxHostConfPerDay)
+	}
+	return nil
+}
+
+// releaseHostConfQuota undo the count of a host-conf request by
+// ensureHostConfQuota, so the failed requests are not counted; a
+// failure is only logged.
+// c is the request context with the db connector, not the
+// transaction.
+func (a *application) releaseHostConfQuota(c context.Context, orgID string, quota model.Quota, now time.Time) {
+	if quota.MaxHostConfPerDay == 0 {
+		return
+	}
+	if err := a.quota.repository.ReleaseHostConf(c, orgID, now); err != nil {
+		app_context.LogFromCtx(c).Warn("failed to release the host-conf usage",
+			slog.String("error", err.Error()))
+	}
+}
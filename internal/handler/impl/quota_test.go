@@ -0,0 +1,168 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	mock_entitlements "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/entitlements"
+	mock_repository "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+const testQuotaOrgID = "12345"
+
+func helperQuotaApplication(t *testing.T) (*application, *mock_repository.QuotaRepository, *mock_entitlements.Entitlements) {
+	repository := mock_repository.NewQuotaRepository(t)
+	entitlements := mock_entitlements.NewEntitlements(t)
+	cfg := &config.Config{}
+	cfg.Application.Quotas = config.Quotas{
+		MaxDomains:          2,
+		MaxServersPerDomain: 3,
+		MaxHostConfPerDay:   5,
+	}
+	return &application{
+		config:       cfg,
+		quota:        quotaComponent{repository: repository},
+		entitlements: entitlements,
+	}, repository, entitlements
+}
+
+func helperQuotaContext() (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+	req = req.WithContext(app_context.CtxWithLog(req.Context(), slog.Default()))
+	rec := httptest.NewRecorder()
+	return echo.New().NewContext(req, rec), rec
+}
+
+func TestEnsureEntitled(t *testing.T) {
+	app, _, entitlements := helperQuotaApplication(t)
+	ctx, _ := helperQuotaContext()
+	xrhid := &identity.XRHID{Identity: identity.Identity{OrgID: testQuotaOrgID}}
+
+	entitlements.On("IsEntitled", mock.Anything, xrhid).Return(false, errors.New("timeout")).Once()
+	err := app.ensureEntitled(ctx, xrhid)
+	assert.EqualError(t, err, "code=503, message=the entitlements could not be checked, internal=IDMSVC-ENTITLEMENTS-UNAVAILABLE: timeout")
+
+	entitlements.On("IsEntitled", mock.Anything, xrhid).Return(false, nil).Once()
+	err = app.ensureEntitled(ctx, xrhid)
+	assert.EqualError(t, err, "code=403, message=organization '12345' is not entitled to use Directory & Domain Services, internal=IDMSVC-NOT-ENTITLED")
+
+	entitlements.On("IsEntitled", mock.Anything, xrhid).Return(true, nil).Once()
+	assert.NoError(t, app.ensureEntitled(ctx, xrhid))
+}
+
+func TestQuotaFor(t *testing.T) {
+	app, repository, _ := helperQuotaApplication(t)
+	c := context.Background()
+
+	repository.On("FindByOrgID", c, testQuotaOrgID).Return(nil, errors.New("db error")).Once()
+	_, err := app.quotaFor(c, testQuotaOrgID)
+	assert.EqualError(t, err, "db error")
+
+	// No overrides
+	repository.On("FindByOrgID", c, testQuotaOrgID).Return(nil, nil).Once()
+	quota, err := app.quotaFor(c, testQuotaOrgID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Quota{MaxDomains: 2, MaxServersPerDomain: 3, MaxHostConfPerDay: 5}, quota)
+
+	// Overrides
+	repository.On("FindByOrgID", c, testQuotaOrgID).Return(&model.OrgQuota{
+		OrgID:      testQuotaOrgID,
+		MaxDomains: pointy.Int(0),
+	}, nil).Once()
+	quota, err = app.quotaFor(c, testQuotaOrgID)
+	require.NoError(t, err)
+	assert.Equal(t, model.Quota{MaxDomains: 0, MaxServersPerDomain: 3, MaxHostConfPerDay: 5}, quota)
+}
+
+func TestEnsureDomainsQuota(t *testing.T) {
+	app, repository, _ := helperQuotaApplication(t)
+	c := context.Background()
+	quota := model.Quota{MaxDomains: 2}
+
+	// Unlimited does not count the domains
+	assert.NoError(t, app.ensureDomainsQuota(c, testQuotaOrgID, model.Quota{}))
+
+	repository.On("LockDomains", c, testQuotaOrgID).Return(errors.New("lock error")).Once()
+	assert.EqualError(t, app.ensureDomainsQuota(c, testQuotaOrgID, quota), "lock error")
+
+	repository.On("LockDomains", c, testQuotaOrgID).Return(nil)
+	repository.On("CountDomains", c, testQuotaOrgID).Return(int64(0), errors.New("db error")).Once()
+	assert.EqualError(t, app.ensureDomainsQuota(c, testQuotaOrgID, quota), "db error")
+
+	repository.On("CountDomains", c, testQuotaOrgID).Return(int64(1), nil).Once()
+	assert.NoError(t, app.ensureDomainsQuota(c, testQuotaOrgID, quota))
+
+	repository.On("CountDomains", c, testQuotaOrgID).Return(int64(2), nil).Once()
+	assert.EqualError(t, app.ensureDomainsQuota(c, testQuotaOrgID, quota),
+		"code=403, message=the organization reached the maximum of 2 domains, internal=IDMSVC-QUOTA-DOMAINS")
+}
+
+func TestEnsureServersQuota(t *testing.T) {
+	data := &model.Domain{
+		Type:     pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{Servers: make([]ipa.IpaServer, 3)},
+	}
+	assert.NoError(t, ensureServersQuota(model.Quota{}, data))
+	assert.EqualError(t, ensureServersQuota(model.Quota{MaxServersPerDomain: 3}, &model.Domain{}),
+		"code=500, message='data' cannot be nil")
+	assert.EqualError(t, ensureServersQuota(model.Quota{MaxServersPerDomain: 3}, &model.Domain{Type: pointy.Uint(1000)}),
+		"'Type' is invalid")
+	assert.NoError(t, ensureServersQuota(model.Quota{MaxServersPerDomain: 3}, data))
+	assert.EqualError(t, ensureServersQuota(model.Quota{MaxServersPerDomain: 2}, data),
+		"code=403, message=the domain has 3 servers, but the maximum is 2, internal=IDMSVC-QUOTA-SERVERS")
+}
+
+func TestEnsureHostConfQuota(t *testing.T) {
+	app, repository, _ := helperQuotaApplication(t)
+	c := context.Background()
+	quota := model.Quota{MaxHostConfPerDay: 5}
+	now := time.Date(2026, 10, 19, 23, 59, 0, 500000000, time.UTC)
+
+	// Unlimited does not count the requests
+	ctx, _ := helperQuotaContext()
+	assert.NoError(t, app.ensureHostConfQuota(ctx, c, testQuotaOrgID, model.Quota{}, now))
+
+	repository.On("IncrementHostConf", c, testQuotaOrgID, now, 5).Return(false, errors.New("db error")).Once()
+	assert.EqualError(t, app.ensureHostConfQuota(ctx, c, testQuotaOrgID, quota, now), "db error")
+
+	repository.On("IncrementHostConf", c, testQuotaOrgID, now, 5).Return(true, nil).Once()
+	assert.NoError(t, app.ensureHostConfQuota(ctx, c, testQuotaOrgID, quota, now))
+
+	ctx, rec := helperQuotaContext()
+	repository.On("IncrementHostConf", c, testQuotaOrgID, now, 5).Return(false, nil).Once()
+	assert.EqualError(t, app.ensureHostConfQuota(ctx, c, testQuotaOrgID, quota, now),
+		"code=429, message=the organization reached the maximum of 5 host-conf requests per day, internal=IDMSVC-QUOTA-HOSTCONF")
+	assert.Equal(t, "60", rec.Header().Get(echo.HeaderRetryAfter))
+}
+
+func TestReleaseHostConfQuota(t *testing.T) {
+	app, repository, _ := helperQuotaApplication(t)
+	c := app_context.CtxWithLog(context.Background(), slog.Default())
+	quota := model.Quota{MaxHostConfPerDay: 5}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	// Unlimited does not count the requests
+	app.releaseHostConfQuota(c, testQuotaOrgID, model.Quota{}, now)
+
+	// A failure is only logged
+	repository.On("ReleaseHostConf", c, testQuotaOrgID, now).Return(errors.New("db error")).Once()
+	app.releaseHostConfQuota(c, testQuotaOrgID, quota, now)
+
+	repository.On("ReleaseHostConf", c, testQuotaOrgID, now).Return(nil).Once()
+	app.releaseHostConfQuota(c, testQuotaOrgID, quota, now)
+}
@@ -0,0 +1,48 @@
+package impl
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	api_event "github.com/podengo-project/idmsvc-backend/internal/api/event"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/event"
+	client_rbac "github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+)
+
+type rbacPermissionChangedEventHandler struct {
+	cache client_rbac.ACLInvalidator
+}
+
+// NewRbacPermissionChangedEventHandler create the handler which drop
+// the cached ACLs when the permissions of an organization or a
+// principal change.
+func NewRbacPermissionChangedEventHandler(cache client_rbac.ACLInvalidator) event.Eventable {
+	if cache == nil {
+		return nil
+	}
+	return &rbacPermissionChangedEventHandler{
+		cache: cache,
+	}
+}
+
+func (h *rbacPermissionChangedEventHandler) OnMessage(msg *kafka.Message) error {
+	var data api_event.RbacPermissionChangedEventJson
+	if msg == nil {
+		return fmt.Errorf("'msg' cannot be nil")
+	}
+	if err := json.Unmarshal(msg.Value, &data); err != nil {
+		return err
+	}
+	principal := ""
+	if data.Principal != nil {
+		principal = *data.Principal
+	}
+	slog.Debug("invalidating cached rbac ACLs",
+		slog.String("org_id", data.OrgId),
+		slog.String("principal", principal),
+	)
+	h.cache.Invalidate(data.OrgId, principal)
+	return nil
+}
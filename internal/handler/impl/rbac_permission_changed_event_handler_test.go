@@ -0,0 +1,68 @@
+package impl
+
+import (
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	mock_rbac "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRbacPermissionChangedEventHandler(t *testing.T) {
+	assert.Nil(t, NewRbacPermissionChangedEventHandler(nil))
+	assert.NotNil(t, NewRbacPermissionChangedEventHandler(mock_rbac.NewACLInvalidator(t)))
+}
+
+func TestRbacPermissionChangedEventHandlerOnMessage(t *testing.T) {
+	type TestCaseExpected struct {
+		Err       string
+		OrgID     string
+		Principal string
+	}
+	type TestCase struct {
+		Name     string
+		Given    *kafka.Message
+		Expected TestCaseExpected
+	}
+
+	testCases := []TestCase{
+		{
+			Name:     "nil message",
+			Given:    nil,
+			Expected: TestCaseExpected{Err: "'msg' cannot be nil"},
+		},
+		{
+			Name:     "missing org_id",
+			Given:    &kafka.Message{Value: []byte(`{"principal": "jdoe"}`)},
+			Expected: TestCaseExpected{Err: "field org_id in RbacPermissionChangedEventJson: required"},
+		},
+		{
+			Name:     "invalidate principal",
+			Given:    &kafka.Message{Value: []byte(`{"org_id": "12345", "principal": "jdoe"}`)},
+			Expected: TestCaseExpected{OrgID: "12345", Principal: "jdoe"},
+		},
+		{
+			Name:     "invalidate organization",
+			Given:    &kafka.Message{Value: []byte(`{"org_id": "12345"}`)},
+			Expected: TestCaseExpected{OrgID: "12345"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		cache := mock_rbac.NewACLInvalidator(t)
+		if testCase.Expected.Err == "" {
+			cache.On("Invalidate", testCase.Expected.OrgID, testCase.Expected.Principal).Return()
+		}
+		handler := NewRbacPermissionChangedEventHandler(cache)
+		require.NotNil(t, handler)
+		err := handler.OnMessage(testCase.Given)
+		if testCase.Expected.Err != "" {
+			assert.EqualError(t, err, testCase.Expected.Err)
+		} else {
+			assert.NoError(t, err)
+		}
+		cache.AssertExpectations(t)
+	}
+}
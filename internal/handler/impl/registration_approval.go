@@ -0,0 +1,72 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype"
+)
+
+// requireRegistrationApproval return true when the domains
+// registered by the organization wait for the approval of an
+// organization administrator, which is the configured default
+// with the setting of the organization.
+// c is the request context with the db transaction.
+func (a *application) requireRegistrationApproval(c context.Context, orgID string) (bool, error) {
+	settings, err := a.domain.repository.FindOrgSettings(c, orgID)
+	if err != nil {
+		return false, err
+	}
+	return settings.RequireRegistrationApproval(a.config.Application.RegistrationApproval), nil
+}
+
+// newDomainRegistration build the registration record of a domain
+// which is pending approval, so the organization administrator can
+// check the server which registered it.
+// subscriptionManagerID is the CN of the registering server.
+// clientVersion is the version of ipa-hcc on the registering server.
+// data is the registered domain; its type fills the fqdn of the
+// registering server and the CA certificates.
+// Return the registration and nil error on success, else nil and
+// a 400 error when the CN is not a subscription manager id.
+func newDomainRegistration(
+	subscriptionManagerID string,
+	clientVersion *header.XRHIDMVersion,
+	data *model.Domain,
+) (*model.DomainRegistration, error) {
+	if clientVersion == nil {
+		return nil, internal_errors.NilArgError("clientVersion")
+	}
+	if data == nil {
+		return nil, internal_errors.NilArgError("data")
+	}
+	if data.Type == nil {
+		return nil, internal_errors.NilArgError("data.Type")
+	}
+	h, ok := domaintype.Lookup(*data.Type)
+	if !ok {
+		return nil, fmt.Errorf("'Type' is invalid")
+	}
+	smID, err := uuid.Parse(subscriptionManagerID)
+	if err != nil {
+		return nil, internal_errors.NewHTTPErrorF(http.StatusBadRequest,
+			"'subscription_manager_id' is invalid")
+	}
+	registration := &model.DomainRegistration{
+		SubscriptionManagerID: smID,
+		IpaHccVersion:         clientVersion.IPAHCCVersion,
+		IpaVersion:            clientVersion.IPAVersion,
+		OsReleaseID:           clientVersion.OSReleaseID,
+		OsReleaseVersionID:    clientVersion.OSReleaseVersionID,
+		CaCerts:               []model.RegistrationCert{},
+	}
+	if err = h.FillRegistration(subscriptionManagerID, data, registration); err != nil {
+		return nil, err
+	}
+	return registration, nil
+}
@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	mock_repository "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+func TestRequireRegistrationApproval(t *testing.T) {
+	repository := mock_repository.NewDomainRepository(t)
+	app := &application{
+		config: &config.Config{},
+		domain: domainComponent{repository: repository},
+	}
+	c := context.Background()
+
+	repository.On("FindOrgSettings", c, testQuotaOrgID).Return(nil, errors.New("db error")).Once()
+	_, err := app.requireRegistrationApproval(c, testQuotaOrgID)
+	assert.EqualError(t, err, "db error")
+
+	// No settings use the default
+	repository.On("FindOrgSettings", c, testQuotaOrgID).Return(nil, nil).Once()
+	approval, err := app.requireRegistrationApproval(c, testQuotaOrgID)
+	require.NoError(t, err)
+	assert.False(t, approval)
+
+	app.config.Application.RegistrationApproval = true
+	repository.On("FindOrgSettings", c, testQuotaOrgID).Return(&model.OrgSettings{OrgID: testQuotaOrgID}, nil).Once()
+	approval, err = app.requireRegistrationApproval(c, testQuotaOrgID)
+	require.NoError(t, err)
+	assert.True(t, approval)
+
+	// The setting of the organization override the default
+	repository.On("FindOrgSettings", c, testQuotaOrgID).Return(&model.OrgSettings{
+		OrgID:                testQuotaOrgID,
+		RegistrationApproval: pointy.Bool(false),
+	}, nil).Once()
+	approval, err = app.requireRegistrationApproval(c, testQuotaOrgID)
+	require.NoError(t, err)
+	assert.False(t, approval)
+}
+
+func TestNewDomainRegistration(t *testing.T) {
+	smID := "547ce70c-9eb5-4783-a619-086aa26f88e5"
+	clientVersion := header.NewXRHIDMVersion("0.12", "4.11.0", "rhel", "9.4")
+	notAfter := time.Date(2036, 10, 19, 0, 0, 0, 0, time.UTC)
+	data := &model.Domain{
+		Type: pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
+			Servers: []ipa.IpaServer{
+				{FQDN: "replica.mydomain.example", RHSMId: pointy.String("cd1fe30c-6ab0-4d3f-a0d6-34b8a1c29d44")},
+				{FQDN: "server.mydomain.example", RHSMId: pointy.String(smID)},
+			},
+			CaCerts: []ipa.IpaCert{
+				{Nickname: "MYDOMAIN.EXAMPLE IPA CA", NotAfter: notAfter, Pem: "-----BEGIN CERTIFICATE-----"},
+			},
+		},
+	}
+
+	_, err := newDomainRegistration(smID, nil, data)
+	assert.EqualError(t, err, "code=500, message='clientVersion' cannot be nil")
+
+	_, err = newDomainRegistration(smID, clientVersion, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	_, err = newDomainRegistration(smID, clientVersion, &model.Domain{})
+	assert.EqualError(t, err, "code=500, message='data.Type' cannot be nil")
+
+	_, err = newDomainRegistration(smID, clientVersion, &model.Domain{Type: pointy.Uint(model.DomainTypeUndefined)})
+	assert.EqualError(t, err, "'Type' is invalid")
+
+	_, err = newDomainRegistration("server.mydomain.example", clientVersion, data)
+	assert.EqualError(t, err, "code=400, message='subscription_manager_id' is invalid")
+
+	registration, err := newDomainRegistration(smID, clientVersion, data)
+	require.NoError(t, err)
+	assert.Equal(t, &model.DomainRegistration{
+		SubscriptionManagerID: uuid.MustParse(smID),
+		FQDN:                  pointy.String("server.mydomain.example"),
+		IpaHccVersion:         "0.12",
+		IpaVersion:            "4.11.0",
+		OsReleaseID:           "rhel",
+		OsReleaseVersionID:    "9.4",
+		CaCerts: []model.RegistrationCert{
+			{Nickname: "MYDOMAIN.EXAMPLE IPA CA", NotAfter: notAfter, Pem: "-----BEGIN CERTIFICATE-----"},
+		},
+	}, registration)
+}
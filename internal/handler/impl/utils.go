@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/client/pendo"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 )
@@ -48,3 +50,18 @@ func sendPendoTrackEvent(ctx echo.Context, client pendo.Pendo, event string) {
 		logger.Warn(err.Error())
 	}
 }
+
+// allowedDomainIDs return the domains of resources, or nil when
+// the relation is granted for every domain into the organization.
+func allowedDomainIDs(resources *authz.ResourceSet) []uuid.UUID {
+	if resources == nil || resources.All {
+		return nil
+	}
+	domainIDs := make([]uuid.UUID, 0, len(resources.IDs))
+	for _, id := range resources.IDs {
+		if domainID, err := uuid.Parse(id); err == nil {
+			domainIDs = append(domainIDs, domainID)
+		}
+	}
+	return domainIDs
+}
@@ -0,0 +1,29 @@
+package impl
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedDomainIDs(t *testing.T) {
+	domainID := uuid.MustParse("8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55")
+
+	// No restriction when there is no allowed set
+	assert.Nil(t, allowedDomainIDs(nil))
+
+	// No restriction when the permission is granted for every domain
+	assert.Nil(t, allowedDomainIDs(&authz.ResourceSet{All: true}))
+
+	// The invalid ids are ignored
+	assert.Equal(t, []uuid.UUID{domainID}, allowedDomainIDs(&authz.ResourceSet{
+		IDs: []string{domainID.String(), "not-a-uuid"},
+	}))
+
+	// Empty, but not nil, when no id is valid
+	assert.Equal(t, []uuid.UUID{}, allowedDomainIDs(&authz.ResourceSet{
+		IDs: []string{"not-a-uuid"},
+	}))
+}
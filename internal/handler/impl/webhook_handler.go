@@ -0,0 +1,378 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/webhook"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+// ListWebhooks retrieve the webhooks of the organization.
+// (GET /webhooks)
+func (a *application) ListWebhooks(ctx echo.Context, params public.ListWebhooksParams) error {
+	var (
+		err    error
+		data   []model.Webhook
+		output *public.ListWebhooksResponse
+		orgID  string
+		tx     *gorm.DB
+		xrhid  *identity.XRHID
+	)
+	handlerName := "ListWebhooks"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(slog.String("handler", handlerName))
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if orgID, err = a.webhook.interactor.List(xrhid, &params); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if data, err = a.webhook.repository.List(c, orgID); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.webhook.presenter.List(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
+
+// CreateWebhook register a webhook for the organization; the response
+// include the secret to verify the signature of the deliveries, which
+// is not returned again.
+// (POST /webhooks)
+func (a *application) CreateWebhook(ctx echo.Context, params public.CreateWebhookParams) error {
+	var (
+		err      error
+		input    public.CreateWebhookRequest
+		data     *model.Webhook
+		webhooks []model.Webhook
+		output   *public.WebhookResponse
+		orgID    string
+		tx       *gorm.DB
+		xrhid    *identity.XRHID
+	)
+	handlerName := "CreateWebhook"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(slog.String("handler", handlerName))
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if err = ctx.Bind(&input); err != nil {
+		logger.Error(errUnserializing)
+		return err
+	}
+	if orgID, data, err = a.webhook.interactor.Create(xrhid, &params, &input); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if webhooks, err = a.webhook.repository.List(c, orgID); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if maxWebhooks := a.config.Application.Quotas.MaxWebhooks; maxWebhooks != 0 && len(webhooks) >= maxWebhooks {
+		logger.Warn("webhooks quota exceeded")
+		return internal_errors.ErrQuotaWebhooks.New(nil,
+			"the organization reached the maximum of %d webhooks", maxWebhooks)
+	}
+	if err = a.webhook.repository.Create(c, orgID, data); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	logger.Info("webhook created", slog.String("uuid", data.WebhookUuid.String()))
+	if output, err = a.webhook.presenter.Create(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusCreated, *output)
+}
+
+// ReadWebhook retrieve a webhook of the organization.
+// (GET /webhooks/{uuid})
+func (a *application) ReadWebhook(ctx echo.Context, UUID uuid.UUID, params public.ReadWebhookParams) error {
+	var (
+		err    error
+		data   *model.Webhook
+		output *public.WebhookResponse
+		orgID  string
+		tx     *gorm.DB
+		xrhid  *identity.XRHID
+	)
+	handlerName := "ReadWebhook"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if orgID, err = a.webhook.interactor.GetByID(xrhid, UUID, &params); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if data, err = a.webhook.repository.FindByID(c, orgID, UUID); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.webhook.presenter.Get(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
+
+// UpdateWebhook change the url, the events or the state of a webhook
+// of the organization; enabling a webhook reset its failed deliveries.
+// (PATCH /webhooks/{uuid})
+func (a *application) UpdateWebhook(ctx echo.Context, UUID uuid.UUID, params public.UpdateWebhookParams) error {
+	var (
+		err         error
+		input       public.UpdateWebhookRequest
+		data        *model.Webhook
+		currentData *model.Webhook
+		output      *public.WebhookResponse
+		orgID       string
+		tx          *gorm.DB
+		xrhid       *identity.XRHID
+	)
+	handlerName := "UpdateWebhook"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if err = ctx.Bind(&input); err != nil {
+		logger.Error(errUnserializing)
+		return err
+	}
+	if orgID, data, err = a.webhook.interactor.Update(xrhid, UUID, &params, &input); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if currentData, err = a.webhook.repository.FindByID(c, orgID, UUID); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	fillWebhook(currentData, data)
+	if err = a.webhook.repository.Update(c, orgID, currentData); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.webhook.presenter.Get(currentData); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
+
+// DeleteWebhook remove a webhook of the organization and its
+// deliveries.
+// (DELETE /webhooks/{uuid})
+func (a *application) DeleteWebhook(ctx echo.Context, UUID uuid.UUID, params public.DeleteWebhookParams) error {
+	var (
+		err   error
+		orgID string
+		tx    *gorm.DB
+		xrhid *identity.XRHID
+	)
+	handlerName := "DeleteWebhook"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if orgID, err = a.webhook.interactor.Delete(xrhid, UUID, &params); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if err = a.webhook.repository.DeleteById(c, orgID, UUID); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries retrieve the most recent deliveries of a
+// webhook of the organization, the newest first.
+// (GET /webhooks/{uuid}/deliveries)
+func (a *application) ListWebhookDeliveries(ctx echo.Context, UUID uuid.UUID, params public.ListWebhookDeliveriesParams) error {
+	var (
+		err    error
+		data   []model.WebhookDelivery
+		output *public.WebhookDeliveriesResponse
+		orgID  string
+		limit  int
+		tx     *gorm.DB
+		xrhid  *identity.XRHID
+	)
+	handlerName := "ListWebhookDeliveries"
+	logger := app_context.LogFromCtx(ctx.Request().Context())
+	logger = logger.With(
+		slog.String("handler", handlerName),
+		slog.String("uuid", UUID.String()),
+	)
+	if xrhid, err = getXRHID(ctx); err != nil {
+		logger.Error(errXRHIDIsNil)
+		return err
+	}
+
+	if orgID, limit, err = a.webhook.interactor.ListDeliveries(xrhid, UUID, &params); err != nil {
+		logger.Error(errInputAdapter)
+		return err
+	}
+	if tx = a.db.Begin(); tx.Error != nil {
+		logger.Error(errDBTXBegin)
+		return tx.Error
+	}
+	defer tx.Rollback()
+	c := app_context.CtxWithDB(ctx.Request().Context(), tx)
+	if data, err = a.webhook.repository.ListDeliveries(c, orgID, UUID, limit); err != nil {
+		logger.Error(errDBGeneralError)
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		logger.Error(errDBTXCommit)
+		return err
+	}
+	if output, err = a.webhook.presenter.Deliveries(data); err != nil {
+		logger.Error(errOutputAdapter)
+		return err
+	}
+	return ctx.JSON(http.StatusOK, *output)
+}
+
+// fillWebhook copy the changes of source into target; enabling a
+// disabled webhook reset its failed deliveries.
+func fillWebhook(target *model.Webhook, source *model.Webhook) {
+	if source.URL != "" {
+		target.URL = source.URL
+	}
+	if source.Events != nil {
+		target.Events = source.Events
+	}
+	if source.Enabled != nil {
+		if *source.Enabled && !target.IsEnabled() {
+			target.ConsecutiveFailures = 0
+			target.DisabledAt = nil
+		}
+		target.Enabled = pointy.Bool(*source.Enabled)
+	}
+}
+
+// enqueueDomainEvent add the deliveries of a domain event for the
+// webhooks of the organization. The deliveries are part of the
+// transaction of the change, so they are only delivered when it is
+// committed.
+// c is the request context with the db transaction.
+// domain is the changed domain; only its uuid is used for the
+// domain.deleted and host.configured events.
+// host is the configured host of a host.configured event, else nil.
+func (a *application) enqueueDomainEvent(
+	c context.Context,
+	event public.WebhookEvent,
+	orgID string,
+	domain *model.Domain,
+	host *webhook.Host,
+) error {
+	payload := &webhook.Event{
+		Event:      event,
+		OrgId:      orgID,
+		OccurredAt: time.Now().UTC(),
+		DomainId:   domain.DomainUuid,
+		Host:       host,
+	}
+	if event == public.DomainCreated || event == public.DomainUpdated ||
+		event == public.DomainApproved || event == public.DomainRejected {
+		output, err := a.domain.presenter.Get(domain)
+		if err != nil {
+			return err
+		}
+		payload.Domain = output
+	}
+	body, err := payload.Payload()
+	if err != nil {
+		return err
+	}
+	_, err = a.webhook.repository.Enqueue(c, orgID, string(event), body, payload.OccurredAt)
+	return err
+}
@@ -1,9 +1,10 @@
-package context
+package context_test
 
 import (
 	"context"
 	"testing"
 
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,35 +12,35 @@ import (
 
 func TestCtxWithDB(t *testing.T) {
 	require.PanicsWithValue(t, "'ctx' is nil", func() {
-		_ = CtxWithDB(nil, nil)
+		_ = app_context.CtxWithDB(nil, nil)
 	})
 
 	ctx := context.TODO()
 	require.PanicsWithValue(t, "'db' is nil", func() {
-		_ = CtxWithDB(ctx, nil)
+		_ = app_context.CtxWithDB(ctx, nil)
 	})
 
 	_, dbMock, err := test.NewSqlMock(nil)
 	require.NoError(t, err)
 	assert.NotPanics(t, func() {
-		ctx = CtxWithDB(ctx, dbMock)
+		ctx = app_context.CtxWithDB(ctx, dbMock)
 	})
 }
 
 func TestDBFromCtx(t *testing.T) {
 	require.PanicsWithValue(t, "'ctx' is nil", func() {
-		_ = DBFromCtx(nil)
+		_ = app_context.DBFromCtx(nil)
 	})
 
 	ctx := context.TODO()
 	assert.PanicsWithValue(t, "'db' could not be read", func() {
-		_ = DBFromCtx(ctx)
+		_ = app_context.DBFromCtx(ctx)
 	})
 
 	_, dbMock, err := test.NewSqlMock(nil)
 	require.NoError(t, err)
-	ctx = CtxWithDB(ctx, dbMock)
+	ctx = app_context.CtxWithDB(ctx, dbMock)
 
-	db := DBFromCtx(ctx)
+	db := app_context.DBFromCtx(ctx)
 	require.NotNil(t, db)
 }
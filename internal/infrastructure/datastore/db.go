@@ -11,6 +11,7 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/logger"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
 	pg "gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -40,7 +41,7 @@ func NewDB(cfg *config.Config) (db *gorm.DB) {
 
 	if db, err = gorm.Open(pg.Open(dbUrl),
 		&gorm.Config{
-			Logger:                 logger.NewGormLog(true),
+			Logger:                 logger.NewGormLog(true, cfg.Database.SlowQueryThreshold),
 			SkipDefaultTransaction: true,
 			// CreateBatchSize:        50,
 			TranslateError: true,
@@ -48,6 +49,10 @@ func NewDB(cfg *config.Config) (db *gorm.DB) {
 		slog.Error("Error creating database connector", slog.Any("error", err))
 		return nil
 	}
+	if err = db.Use(tracing.NewGormPlugin()); err != nil {
+		slog.Error("Error adding the tracing plugin", slog.Any("error", err))
+		return nil
+	}
 	sqlDb, err := db.DB()
 	if err != nil {
 		slog.Error("Error getting sql driver", slog.String("error", err.Error()))
@@ -0,0 +1,231 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	interface_interactor "github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	interface_presenter "github.com/podengo-project/idmsvc-backend/internal/interface/presenter"
+	interface_repository "github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/presenter"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+type DomainExportDb struct {
+	cfg        *config.Config
+	interactor interface_interactor.DomainInteractor
+	presenter  interface_presenter.DomainPresenter
+	repository interface_repository.DomainRepository
+	log        *slog.Logger
+}
+
+// NewDomainExportDb Create new DomainExportDb
+func NewDomainExportDb(cfg *config.Config, log *slog.Logger) *DomainExportDb {
+	return &DomainExportDb{
+		cfg:        cfg,
+		interactor: interactor.NewDomainInteractor(),
+		presenter:  presenter.NewDomainPresenter(cfg),
+		repository: repository.NewDomainRepository(),
+		log:        log,
+	}
+}
+
+// Export write the document with all the domains of an organization.
+// orgID is the organization to export.
+// w is where the document is written.
+func (r *DomainExportDb) Export(orgID string, w io.Writer) (err error) {
+	var (
+		db      *gorm.DB
+		tx      *gorm.DB
+		data    []model.Domain
+		subnets map[uuid.UUID][]model.DomainLocationSubnet
+		doc     *public.ExportDomainsResponse
+	)
+	db = NewDB(r.cfg)
+	defer Close(db)
+
+	if tx = db.Begin(); tx.Error != nil {
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	ctx := app_context.CtxWithDB(app_context.CtxWithLog(context.Background(), r.log), tx)
+	if data, subnets, err = domainexport.Collect(ctx, r.repository, orgID, nil); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	if doc, err = r.presenter.Export(orgID, time.Now().UTC().Truncate(time.Second), data, subnets); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(doc); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	r.log.Info("Domains exported",
+		slog.String("org_id", orgID),
+		slog.Int("domains", len(doc.Domains)),
+		slog.String("checksum", doc.Checksum),
+	)
+	return nil
+}
+
+// Import read a document and register its domains in an organization.
+// The domain uuids are preserved, unless the uuid is used by other
+// organization, and then the domain is imported with a new uuid. The
+// active domains are imported pending approval when the organization
+// requires the approval of the registrations.
+// orgID is the organization where the domains are imported.
+// reader is where the document is read.
+// dryRun log the changes but does not apply them.
+// strategy is how to solve the conflict with the domains which already
+// exist in the organization.
+func (r *DomainExportDb) Import(orgID string, reader io.Reader, dryRun bool, strategy domainexport.Strategy) (err error) {
+	var (
+		db       *gorm.DB
+		tx       *gorm.DB
+		doc      public.DomainExport
+		data     []model.Domain
+		subnets  map[uuid.UUID][]model.DomainLocationSubnet
+		settings *model.OrgSettings
+	)
+	if err = json.NewDecoder(reader).Decode(&doc); err != nil {
+		r.log.Error("unserializing the document")
+		return err
+	}
+	if data, subnets, err = r.interactor.Import(orgID, &doc); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	if doc.OrgId != orgID {
+		r.log.Warn("The document was exported from other organization",
+			slog.String("from_org_id", doc.OrgId),
+			slog.String("org_id", orgID),
+		)
+	}
+
+	db = NewDB(r.cfg)
+	defer Close(db)
+
+	if tx = db.Begin(); tx.Error != nil {
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	ctx := app_context.CtxWithDB(app_context.CtxWithLog(context.Background(), r.log), tx)
+	if settings, err = r.repository.FindOrgSettings(ctx, orgID); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	requireApproval := settings.RequireRegistrationApproval(r.cfg.Application.RegistrationApproval)
+	for idx := range data {
+		domainID := data[idx].DomainUuid
+		if requireApproval {
+			r.requireApproval(&data[idx])
+		}
+		if err = r.importDomain(ctx, tx, orgID, &data[idx], subnets[domainID], strategy); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		r.log.Info("Dry run, no change was applied", slog.Int("domains", len(data)))
+		return nil
+	}
+	if err = tx.Commit().Error; err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	r.log.Info("Domains imported", slog.Int("domains", len(data)))
+	return nil
+}
+
+// requireApproval set an active domain pending approval, so an
+// administrator of the organization approves it again; the decision
+// recorded by the exporting environment is cleared.
+func (r *DomainExportDb) requireApproval(data *model.Domain) {
+	if data.RegistrationState != model.DomainRegistrationActive {
+		return
+	}
+	r.log.Info("Import domain pending approval, the organization requires the approval of the registrations",
+		slog.String("uuid", data.DomainUuid.String()),
+	)
+	data.RegistrationState = model.DomainRegistrationPendingApproval
+	if data.Registration != nil {
+		data.Registration.DecidedBy = nil
+		data.Registration.DecidedAt = nil
+		data.Registration.Reason = nil
+	}
+}
+
+// importDomain register one domain of a document and its subnet to
+// location mappings, solving the conflicts with strategy.
+func (r *DomainExportDb) importDomain(
+	ctx context.Context,
+	tx *gorm.DB,
+	orgID string,
+	data *model.Domain,
+	subnets []model.DomainLocationSubnet,
+	strategy domainexport.Strategy,
+) (err error) {
+	var owner []string
+	log := r.log.With(
+		slog.String("uuid", data.DomainUuid.String()),
+		slog.String("domain_name", pointy.StringValue(data.DomainName, "")),
+	)
+	if err = tx.Unscoped().Model(&model.Domain{}).
+		Where("domain_uuid = ?", data.DomainUuid).
+		Pluck("org_id", &owner).
+		Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	switch {
+	case len(owner) == 0:
+		log.Info("Create domain")
+	case owner[0] != orgID:
+		data.DomainUuid = uuid.New()
+		log.Info("Create domain with a new uuid, the uuid is used by other organization",
+			slog.String("new_uuid", data.DomainUuid.String()),
+		)
+	case strategy == domainexport.StrategySkip:
+		log.Info("Skip domain, it already exists")
+		return nil
+	case strategy == domainexport.StrategyOverwrite:
+		log.Info("Overwrite domain, it already exists")
+		if err = r.repository.DeleteById(ctx, orgID, data.DomainUuid); err != nil {
+			log.Error(err.Error())
+			return err
+		}
+	default:
+		err = fmt.Errorf("domain '%s' already exists in the organization", data.DomainUuid.String())
+		log.Error(err.Error())
+		return err
+	}
+
+	if err = r.repository.Register(ctx, orgID, data); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if err = r.repository.UpdateLocationSubnets(ctx, orgID, data.DomainUuid, subnets); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package datastore
+
+import (
+	"context"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
+	"gorm.io/gorm"
+)
+
+// HealthCheckName is the name of the database check.
+const HealthCheckName = "database"
+
+// NewHealthCheck create the critical check of the database, which
+// ping the database and, unless the schema check policy is 'warn',
+// verify the migration version is compatible with the binary.
+// db is the database connector.
+// cfg is the database configuration.
+func NewHealthCheck(db *gorm.DB, cfg *config.Database) health.Check {
+	if db == nil {
+		panic("'db' is nil")
+	}
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	expected, expectedErr := ExpectedSchemaVersion()
+	return health.Check{
+		Name:     HealthCheckName,
+		Severity: health.Critical,
+		Probe: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			if err = sqlDB.PingContext(ctx); err != nil {
+				return err
+			}
+			if cfg.SchemaCheck == config.SchemaCheckWarn {
+				return nil
+			}
+			if expectedErr != nil {
+				return expectedErr
+			}
+			return CheckSchemaVersion(db.WithContext(ctx), cfg, expected)
+		},
+	}
+}
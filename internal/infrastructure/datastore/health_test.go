@@ -0,0 +1,69 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHealthCheck(t *testing.T) {
+	cfg := &config.Database{SchemaCheck: config.SchemaCheckNotReady}
+	mock, db, err := test.NewSqlMock(nil)
+	require.NoError(t, err)
+	assert.PanicsWithValue(t, "'db' is nil", func() {
+		NewHealthCheck(nil, cfg)
+	})
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		NewHealthCheck(db, nil)
+	})
+
+	expected, err := ExpectedSchemaVersion()
+	require.NoError(t, err)
+	check := NewHealthCheck(db, cfg)
+	assert.Equal(t, HealthCheckName, check.Name)
+	assert.Equal(t, health.Critical, check.Severity)
+	assert.False(t, check.Liveness)
+
+	const query = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	columns := []string{"version", "dirty"}
+
+	// Migrations applied
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(expected, false))
+	assert.NoError(t, check.Probe(context.Background()))
+
+	// Schema not migrated yet
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(20240101000000, false))
+	assert.ErrorContains(t, check.Probe(context.Background()),
+		"migration version 20240101000000 is not compatible with the binary")
+
+	// Dirty migration
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(20240101000000, true))
+	assert.EqualError(t, check.Probe(context.Background()), "migration version 20240101000000 is dirty")
+
+	// No migrations
+	mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(columns))
+	assert.EqualError(t, check.Probe(context.Background()), "no migration was applied")
+
+	// Database error
+	mock.ExpectQuery(query).
+		WillReturnError(errors.New(`relation "schema_migrations" does not exist`))
+	assert.EqualError(t, check.Probe(context.Background()),
+		`reading the migration version: relation "schema_migrations" does not exist`)
+
+	// The warn policy does not check the schema
+	cfg.SchemaCheck = config.SchemaCheckWarn
+	assert.NoError(t, check.Probe(context.Background()))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -70,8 +70,8 @@ func (r *HostconfJwkDb) Refresh() (err error) {
 	}
 
 	// Create new JWK unless there is one or more JWK that is not expired,
-	// not revoked, encrypted with current app secret, and which does expires
-	// after renewal threshold.
+	// not revoked, encrypted with the current or a previous app secret, and
+	// which does expires after renewal threshold.
 	create := true
 	valid := 0
 	revoked := 0
@@ -257,3 +257,61 @@ func (r *HostconfJwkDb) ListKeys() (err error) {
 	}
 	return nil
 }
+
+// Reencrypt the private JWKs encrypted with a previous main secret with
+// the current main secret, so the previous main secret can be removed
+// from the configuration.
+// dryRun log the changes but does not apply them.
+func (r *HostconfJwkDb) Reencrypt(dryRun bool) (err error) {
+	var (
+		db          *gorm.DB
+		tx          *gorm.DB
+		reencrypted []model.HostconfJwk
+		skipped     []model.HostconfJwk
+	)
+	db = NewDB(r.cfg)
+	defer Close(db)
+
+	if tx = db.Begin(); tx.Error != nil {
+		r.log.Error(tx.Error.Error())
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	ctx := app_context.CtxWithDB(app_context.CtxWithLog(context.Background(), r.log), tx)
+	if reencrypted, skipped, err = r.repository.ReencryptJWKs(ctx); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	for _, hcjwk := range reencrypted {
+		r.log.Info(
+			"Reencrypted JWK",
+			slog.String("kid", hcjwk.KeyId),
+			slog.String("encryptionId", hcjwk.EncryptionId),
+			slog.Time("expires", hcjwk.ExpiresAt),
+		)
+	}
+	for _, hcjwk := range skipped {
+		r.log.Warn(
+			"JWK is encrypted with an unknown main secret",
+			slog.String("kid", hcjwk.KeyId),
+			slog.String("encryptionId", hcjwk.EncryptionId),
+			slog.Time("expires", hcjwk.ExpiresAt),
+		)
+	}
+
+	if dryRun {
+		r.log.Info("Dry run, no change was applied", slog.Int("reencrypted", len(reencrypted)))
+		return nil
+	}
+	if err = tx.Commit().Error; err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	r.log.Info(
+		"JWKs reencrypted",
+		slog.Int("reencrypted", len(reencrypted)),
+		slog.Int("skipped", len(skipped)),
+	)
+	return nil
+}
@@ -0,0 +1,101 @@
+package datastore
+
+import (
+	"errors"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"gorm.io/gorm"
+)
+
+const (
+	metricsPluginName = "idmsvc:metrics"
+	metricsStartKey   = "idmsvc:metrics_start"
+	// metricsNoTable is the table label for the statements without
+	// a model, as the raw queries.
+	metricsNoTable = "none"
+)
+
+type metricsPlugin struct {
+	metrics *metrics.Metrics
+}
+
+// NewMetricsPlugin create a gorm plugin which observe the duration
+// of every statement by operation and table.
+// m is the metrics where the durations are observed.
+func NewMetricsPlugin(m *metrics.Metrics) gorm.Plugin {
+	if m == nil {
+		panic("'m' is nil")
+	}
+	return &metricsPlugin{metrics: m}
+}
+
+func (p *metricsPlugin) Name() string {
+	return metricsPluginName
+}
+
+func (p *metricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	errs := []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", p.before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", p.after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", p.before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", p.after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", p.before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", p.after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", p.after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", p.before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", p.after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", p.after("raw")),
+	}
+	return errors.Join(errs...)
+}
+
+func (p *metricsPlugin) before(db *gorm.DB) {
+	db.InstanceSet(metricsStartKey, time.Now())
+}
+
+func (p *metricsPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = metricsNoTable
+		}
+		p.metrics.DBQueryDuration.
+			WithLabelValues(operation, table).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// InstrumentDB expose into the metrics the statistics of the
+// connection pool and the duration of the statements of db.
+// db is the database connector.
+// name is the value for the db_name label of the pool metrics.
+// m is the application metrics.
+// Return nil on success, else an error.
+func InstrumentDB(db *gorm.DB, name string, m *metrics.Metrics) error {
+	if db == nil {
+		panic("'db' is nil")
+	}
+	if m == nil {
+		panic("'m' is nil")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err = m.RegisterDBStats(sqlDB, name); err != nil {
+		return err
+	}
+	return db.Use(NewMetricsPlugin(m))
+}
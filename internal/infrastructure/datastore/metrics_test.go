@@ -0,0 +1,71 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricsPlugin(t *testing.T) {
+	assert.PanicsWithValue(t, "'m' is nil", func() {
+		NewMetricsPlugin(nil)
+	})
+	plugin := NewMetricsPlugin(metrics.NewMetrics(prometheus.NewRegistry()))
+	assert.Equal(t, metricsPluginName, plugin.Name())
+}
+
+func TestInstrumentDB(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.NewMetrics(reg)
+	mock, db, err := test.NewSqlMock(nil)
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, "'db' is nil", func() {
+		_ = InstrumentDB(nil, "idmsvc", m)
+	})
+	assert.PanicsWithValue(t, "'m' is nil", func() {
+		_ = InstrumentDB(db, "idmsvc", nil)
+	})
+
+	require.NoError(t, InstrumentDB(db, "idmsvc", m))
+	// The pool statistics can not be registered twice
+	assert.Error(t, InstrumentDB(db, "idmsvc", m))
+
+	count, err := testutil.GatherAndCount(reg, "go_sql_open_connections", "go_sql_wait_count_total")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// Statements with and without table
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "domains"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	var n int64
+	require.NoError(t, db.Table("domains").Count(&n).Error)
+	mock.ExpectExec(`DELETE FROM host_conf_usage`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, db.Exec(`DELETE FROM host_conf_usage`).Error)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	labels := []string{}
+	for _, family := range families {
+		if family.GetName() != "idmsvc_db_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			values := map[string]string{}
+			for _, pair := range metric.GetLabel() {
+				values[pair.GetName()] = pair.GetValue()
+			}
+			labels = append(labels, values["operation"]+"/"+values["table"])
+			assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+		}
+	}
+	assert.ElementsMatch(t, []string{"query/domains", "raw/" + metricsNoTable}, labels)
+}
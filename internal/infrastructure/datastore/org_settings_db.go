@@ -0,0 +1,74 @@
+package datastore
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	interface_repository "github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
+	"gorm.io/gorm"
+)
+
+type OrgSettingsDb struct {
+	cfg        *config.Config
+	repository interface_repository.DomainRepository
+	log        *slog.Logger
+}
+
+// NewOrgSettingsDb Create new OrgSettingsDb
+func NewOrgSettingsDb(cfg *config.Config, log *slog.Logger) *OrgSettingsDb {
+	return &OrgSettingsDb{
+		cfg:        cfg,
+		repository: repository.NewDomainRepository(),
+		log:        log,
+	}
+}
+
+// SetRegistrationApproval store whether the domains registered in an
+// organization need the approval of an organization administrator.
+// orgID is the organization to update.
+// value is the setting, nil to use the default of the configuration.
+func (r *OrgSettingsDb) SetRegistrationApproval(orgID string, value *bool) (err error) {
+	var (
+		db *gorm.DB
+		tx *gorm.DB
+	)
+	db = NewDB(r.cfg)
+	defer Close(db)
+
+	if tx = db.Begin(); tx.Error != nil {
+		r.log.Error(tx.Error.Error())
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	ctx := app_context.CtxWithDB(app_context.CtxWithLog(context.Background(), r.log), tx)
+	data := &model.OrgSettings{
+		OrgID:                orgID,
+		RegistrationApproval: value,
+	}
+	if err = r.repository.SaveOrgSettings(ctx, data); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	if err = tx.Commit().Error; err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+
+	setting := "default"
+	if value != nil {
+		setting = strconv.FormatBool(*value)
+	}
+	r.log.Info(
+		"Registration approval updated",
+		slog.String("org_id", orgID),
+		slog.String("registration_approval", setting),
+		slog.Bool("required", data.RequireRegistrationApproval(r.cfg.Application.RegistrationApproval)),
+	)
+	return nil
+}
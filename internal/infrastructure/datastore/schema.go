@@ -0,0 +1,131 @@
+package datastore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/scripts/db/migrations"
+	"gorm.io/gorm"
+)
+
+// SchemaVersion is the migration version of the database.
+type SchemaVersion struct {
+	Version uint64
+	Dirty   bool
+}
+
+// ExpectedSchemaVersion return the version of the latest migration
+// embedded into the binary.
+func ExpectedSchemaVersion() (uint64, error) {
+	return latestMigrationVersion(migrations.FS)
+}
+
+func latestMigrationVersion(fsys fs.FS) (uint64, error) {
+	names, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+	var latest uint64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parsing the version of the migration '%s': %w", name, err)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	if latest == 0 {
+		return 0, errors.New("no migration was found")
+	}
+	return latest, nil
+}
+
+// ReadSchemaVersion read the migration version of the database.
+// Return nil and an error when the schema_migrations table can not
+// be read or no migration was applied.
+func ReadSchemaVersion(db *gorm.DB) (*SchemaVersion, error) {
+	var version SchemaVersion
+	res := db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version)
+	if res.Error != nil {
+		return nil, fmt.Errorf("reading the migration version: %w", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, errors.New("no migration was applied")
+	}
+	return &version, nil
+}
+
+// CheckSchemaVersion verify the migration version of the database is
+// not dirty and it is into the range compatible with the binary.
+// db is the database connector.
+// cfg is the database configuration with the compatible range.
+// expected is the version of the latest migration of the binary.
+// Return nil when the schema is compatible, else an error.
+func CheckSchemaVersion(db *gorm.DB, cfg *config.Database, expected uint64) error {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	current, err := ReadSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current.Dirty {
+		return fmt.Errorf("migration version %d is dirty", current.Version)
+	}
+	minVersion := cfg.SchemaMinVersion
+	if minVersion == 0 {
+		minVersion = expected
+	}
+	if current.Version < minVersion {
+		return fmt.Errorf("migration version %d is not compatible with the binary; it expects from %d",
+			current.Version, minVersion)
+	}
+	// Any newer version is compatible by default, so the running
+	// replicas keep serving while a rolling deployment applies the
+	// migrations of the new version
+	maxVersion := cfg.SchemaMaxVersion
+	if maxVersion != 0 && current.Version > maxVersion {
+		return fmt.Errorf("migration version %d is not compatible with the binary; it expects from %d to %d",
+			current.Version, minVersion, maxVersion)
+	}
+	return nil
+}
+
+// VerifySchemaOnStartup check the schema version when the service
+// starts. The mismatches are logged, and an error is only returned
+// when the 'fail' policy is configured.
+// cfg is the application configuration.
+// db is the database connector.
+func VerifySchemaOnStartup(cfg *config.Config, db *gorm.DB) error {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if db == nil {
+		panic("'db' is nil")
+	}
+	expected, err := ExpectedSchemaVersion()
+	if err == nil {
+		err = CheckSchemaVersion(db, &cfg.Database, expected)
+	}
+	if err == nil {
+		slog.Info("Database schema version is compatible", slog.Uint64("expected", expected))
+		return nil
+	}
+	switch cfg.Database.SchemaCheck {
+	case config.SchemaCheckFail:
+		slog.Error("Database schema version is not compatible", slog.Any("error", err))
+		return err
+	case config.SchemaCheckWarn:
+		slog.Warn("Database schema version is not compatible", slog.Any("error", err))
+	default:
+		slog.Error("Database schema version is not compatible; the service is not ready", slog.Any("error", err))
+	}
+	return nil
+}
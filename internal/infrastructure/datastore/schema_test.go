@@ -0,0 +1,125 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schemaVersionQuery = `SELECT version, dirty FROM schema_migrations LIMIT 1`
+
+var schemaVersionColumns = []string{"version", "dirty"}
+
+func TestExpectedSchemaVersion(t *testing.T) {
+	version, err := ExpectedSchemaVersion()
+	require.NoError(t, err)
+	assert.Greater(t, version, uint64(20230101000000))
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	version, err := latestMigrationVersion(fstest.MapFS{
+		"20240101000000_init.up.sql":    {},
+		"20240101000000_init.down.sql":  {},
+		"20240301000000_hosts.up.sql":   {},
+		"20240301000000_hosts.down.sql": {},
+		"20240201000000_keys.up.sql":    {},
+		"migrations.go":                 {},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20240301000000), version)
+
+	_, err = latestMigrationVersion(fstest.MapFS{})
+	assert.EqualError(t, err, "no migration was found")
+
+	_, err = latestMigrationVersion(fstest.MapFS{"init_tables.up.sql": {}})
+	assert.ErrorContains(t, err, "parsing the version of the migration 'init_tables.up.sql'")
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	const expected = uint64(20240301000000)
+	mock, db, err := test.NewSqlMock(nil)
+	require.NoError(t, err)
+	expectVersion := func(version uint64, dirty bool) {
+		mock.ExpectQuery(schemaVersionQuery).
+			WillReturnRows(sqlmock.NewRows(schemaVersionColumns).AddRow(version, dirty))
+	}
+
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		_ = CheckSchemaVersion(db, nil, expected)
+	})
+
+	// The latest migration or newer by default
+	cfg := &config.Database{}
+	expectVersion(expected, false)
+	assert.NoError(t, CheckSchemaVersion(db, cfg, expected))
+
+	expectVersion(expected, true)
+	assert.EqualError(t, CheckSchemaVersion(db, cfg, expected), "migration version 20240301000000 is dirty")
+
+	expectVersion(20240201000000, false)
+	assert.EqualError(t, CheckSchemaVersion(db, cfg, expected),
+		"migration version 20240201000000 is not compatible with the binary; it expects from 20240301000000")
+
+	expectVersion(20240401000000, false)
+	assert.NoError(t, CheckSchemaVersion(db, cfg, expected))
+
+	// Compatible range for rolling deployments
+	cfg = &config.Database{SchemaMinVersion: 20240201000000, SchemaMaxVersion: 20240401000000}
+	expectVersion(20240201000000, false)
+	assert.NoError(t, CheckSchemaVersion(db, cfg, expected))
+	expectVersion(20240401000000, false)
+	assert.NoError(t, CheckSchemaVersion(db, cfg, expected))
+	expectVersion(20240101000000, false)
+	assert.Error(t, CheckSchemaVersion(db, cfg, expected))
+	expectVersion(20240501000000, false)
+	assert.EqualError(t, CheckSchemaVersion(db, cfg, expected),
+		"migration version 20240501000000 is not compatible with the binary; it expects from 20240201000000 to 20240401000000")
+
+	// No migrations
+	mock.ExpectQuery(schemaVersionQuery).
+		WillReturnRows(sqlmock.NewRows(schemaVersionColumns))
+	assert.EqualError(t, CheckSchemaVersion(db, cfg, expected), "no migration was applied")
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestVerifySchemaOnStartup(t *testing.T) {
+	mock, db, err := test.NewSqlMock(nil)
+	require.NoError(t, err)
+	expected, err := ExpectedSchemaVersion()
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		_ = VerifySchemaOnStartup(nil, db)
+	})
+	assert.PanicsWithValue(t, "'db' is nil", func() {
+		_ = VerifySchemaOnStartup(&config.Config{}, nil)
+	})
+
+	cfg := &config.Config{}
+	for _, policy := range []string{config.SchemaCheckFail, config.SchemaCheckNotReady, config.SchemaCheckWarn} {
+		cfg.Database.SchemaCheck = policy
+		mock.ExpectQuery(schemaVersionQuery).
+			WillReturnRows(sqlmock.NewRows(schemaVersionColumns).AddRow(expected, false))
+		assert.NoError(t, VerifySchemaOnStartup(cfg, db), policy)
+	}
+
+	// Only the fail policy stops the service
+	mock.ExpectQuery(schemaVersionQuery).WillReturnError(errors.New("connection refused"))
+	cfg.Database.SchemaCheck = config.SchemaCheckFail
+	assert.EqualError(t, VerifySchemaOnStartup(cfg, db), "reading the migration version: connection refused")
+	for _, policy := range []string{config.SchemaCheckNotReady, config.SchemaCheckWarn} {
+		cfg.Database.SchemaCheck = policy
+		mock.ExpectQuery(schemaVersionQuery).
+			WillReturnRows(sqlmock.NewRows(schemaVersionColumns).AddRow(expected, true))
+		assert.NoError(t, VerifySchemaOnStartup(cfg, db), policy)
+	}
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,167 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	interface_repository "github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/webhook"
+	"gorm.io/gorm"
+)
+
+type WebhookDb struct {
+	cfg        *config.Config
+	repository interface_repository.WebhookRepository
+	log        *slog.Logger
+}
+
+// NewWebhookDb Create new WebhookDb
+func NewWebhookDb(cfg *config.Config, log *slog.Logger) *WebhookDb {
+	return &WebhookDb{
+		cfg:        cfg,
+		repository: repository.NewWebhookRepository(),
+		log:        log,
+	}
+}
+
+// Reencrypt the signing secrets of the webhooks encrypted with a
+// previous main secret with the current main secret, so the previous
+// main secret can be removed from the configuration. The webhooks
+// created before the secrets were stored get their derived secret
+// stored, so it does not change when the main secret is rotated.
+// dryRun log the changes but does not apply them.
+func (r *WebhookDb) Reencrypt(dryRun bool) (err error) {
+	var (
+		db          *gorm.DB
+		tx          *gorm.DB
+		webhooks    []model.Webhook
+		reencrypted int
+		skipped     int
+	)
+	db = NewDB(r.cfg)
+	defer Close(db)
+
+	if tx = db.Begin(); tx.Error != nil {
+		r.log.Error(tx.Error.Error())
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	ctx := app_context.CtxWithDB(app_context.CtxWithLog(context.Background(), r.log), tx)
+	if webhooks, err = r.repository.ListSecretsToReencrypt(ctx, r.cfg.Secrets.HostconfEncryptionId); err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	for idx := range webhooks {
+		data := &webhooks[idx]
+		encryptionId := ""
+		if data.EncryptionId != nil {
+			encryptionId = *data.EncryptionId
+		}
+		if err = webhook.ReencryptSecret(&r.cfg.Secrets, data); err != nil {
+			if errors.Is(err, webhook.ErrSecretDecryptionFailed) {
+				r.log.Warn(
+					"Webhook secret is encrypted with an unknown main secret",
+					slog.String("uuid", data.WebhookUuid.String()),
+					slog.String("encryptionId", encryptionId),
+				)
+				skipped++
+				continue
+			}
+			r.log.Error(err.Error())
+			return err
+		}
+		if err = r.repository.UpdateSecret(ctx, data); err != nil {
+			r.log.Error(err.Error())
+			return err
+		}
+		r.log.Info(
+			"Reencrypted webhook secret",
+			slog.String("uuid", data.WebhookUuid.String()),
+			slog.String("encryptionId", encryptionId),
+		)
+		reencrypted++
+	}
+
+	if dryRun {
+		r.log.Info("Dry run, no change was applied", slog.Int("reencrypted", reencrypted))
+		return nil
+	}
+	if err = tx.Commit().Error; err != nil {
+		r.log.Error(err.Error())
+		return err
+	}
+	r.log.Info(
+		"Webhook secrets reencrypted",
+		slog.Int("reencrypted", reencrypted),
+		slog.Int("skipped", skipped),
+	)
+	return nil
+}
+
+// StoreLegacyWebhookSecretsOnStartup store the signing secret of the
+// webhooks created before the secrets were stored, when the service
+// starts. Their secret is derived from the current main secret, so
+// it must be stored before the first rotation; the service refuses
+// to start when such webhooks exist and app.previous_secrets is set,
+// as the derived secret would silently change.
+// cfg is the application configuration.
+// db is the database connector.
+// Return nil on success, else an error.
+func StoreLegacyWebhookSecretsOnStartup(cfg *config.Config, db *gorm.DB) (err error) {
+	var (
+		tx       *gorm.DB
+		webhooks []model.Webhook
+	)
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if db == nil {
+		panic("'db' is nil")
+	}
+	if tx = db.Begin(); tx.Error != nil {
+		slog.Error(tx.Error.Error())
+		return tx.Error
+	}
+	defer tx.Rollback()
+
+	r := repository.NewWebhookRepository()
+	ctx := app_context.CtxWithDB(app_context.CtxWithLog(context.Background(), slog.Default()), tx)
+	if webhooks, err = r.ListLegacySecrets(ctx); err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	if len(cfg.Application.PreviousSecrets) != 0 {
+		err = fmt.Errorf(
+			"%d webhooks have no stored secret and 'app.previous_secrets' is set; "+
+				"run 'db-tool secrets reencrypt' with the main secret they were created with",
+			len(webhooks),
+		)
+		slog.Error(err.Error())
+		return err
+	}
+	for idx := range webhooks {
+		data := &webhooks[idx]
+		if err = webhook.ReencryptSecret(&cfg.Secrets, data); err != nil {
+			slog.Error(err.Error())
+			return err
+		}
+		if err = r.UpdateSecret(ctx, data); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit().Error; err != nil {
+		slog.Error(err.Error())
+		return err
+	}
+	slog.Info("Legacy webhook secrets stored", slog.Int("stored", len(webhooks)))
+	return nil
+}
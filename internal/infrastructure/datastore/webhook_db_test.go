@@ -0,0 +1,74 @@
+package datastore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	legacySecretsQuery = `SELECT \* FROM "webhooks" WHERE encryption_id IS NULL ORDER BY id FOR UPDATE`
+	updateSecretQuery  = `UPDATE "webhooks" SET "updated_at"=\$1,"encrypted_secret"=\$2,"encryption_id"=\$3 WHERE id = \$4`
+)
+
+var legacySecretsColumns = []string{"id", "created_at", "updated_at", "org_id", "webhook_uuid", "url"}
+
+func TestStoreLegacyWebhookSecretsOnStartup(t *testing.T) {
+	mock, db, err := test.NewSqlMock(nil)
+	require.NoError(t, err)
+	sec, err := secrets.NewAppSecrets("3cBBUQSnlKHQO7-5hyxJRQ")
+	require.NoError(t, err)
+	cfg := &config.Config{Secrets: *sec}
+	now := time.Now()
+	legacyRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(legacySecretsColumns).
+			AddRow(1, now, now, "12345", uuid.New(), "https://itsm.example.com/hook")
+	}
+
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		_ = StoreLegacyWebhookSecretsOnStartup(nil, db)
+	})
+	assert.PanicsWithValue(t, "'db' is nil", func() {
+		_ = StoreLegacyWebhookSecretsOnStartup(cfg, nil)
+	})
+
+	// Error at reading the webhooks
+	mock.ExpectBegin()
+	mock.ExpectQuery(legacySecretsQuery).WillReturnError(errors.New("connection refused"))
+	mock.ExpectRollback()
+	assert.EqualError(t, StoreLegacyWebhookSecretsOnStartup(cfg, db), "connection refused")
+
+	// No legacy webhook
+	mock.ExpectBegin()
+	mock.ExpectQuery(legacySecretsQuery).WillReturnRows(sqlmock.NewRows(legacySecretsColumns))
+	mock.ExpectRollback()
+	assert.NoError(t, StoreLegacyWebhookSecretsOnStartup(cfg, db))
+
+	// The secret of the legacy webhooks is stored
+	mock.ExpectBegin()
+	mock.ExpectQuery(legacySecretsQuery).WillReturnRows(legacyRows())
+	mock.ExpectExec(updateSecretQuery).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sec.HostconfEncryptionId, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, StoreLegacyWebhookSecretsOnStartup(cfg, db))
+
+	// The legacy webhooks refuse the previous secrets
+	cfg.Application.PreviousSecrets = []string{"MLaVBnV5kadqAasiUmtEwg"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(legacySecretsQuery).WillReturnRows(legacyRows())
+	mock.ExpectRollback()
+	assert.EqualError(t, StoreLegacyWebhookSecretsOnStartup(cfg, db),
+		"1 webhooks have no stored secret and 'app.previous_secrets' is set; "+
+			"run 'db-tool secrets reencrypt' with the main secret they were created with")
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -10,6 +10,11 @@ import (
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	schema "github.com/podengo-project/idmsvc-backend/internal/api/event"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md
@@ -138,9 +143,7 @@ func NewConsumerEventLoop(ctx context.Context, consumer *kafka.Consumer, handler
 	}
 }
 
-func processConsumedMessage(schemas schema.TopicSchema, msg *kafka.Message, handler Eventable) error {
-	var err error
-
+func processConsumedMessage(schemas schema.TopicSchema, msg *kafka.Message, handler Eventable) (err error) {
 	if schemas == nil || msg == nil || handler == nil {
 		return fmt.Errorf("schemas, msg or handler is nil")
 	}
@@ -148,6 +151,23 @@ func processConsumedMessage(schemas schema.TopicSchema, msg *kafka.Message, hand
 		return fmt.Errorf("Topic cannot be nil")
 	}
 
+	// Continue the trace of the producer
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), tracing.NewKafkaHeadersCarrier(&msg.Headers))
+	_, span := tracing.Tracer().Start(ctx, *msg.TopicPartition.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", *msg.TopicPartition.Topic),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
 	internalTopic := config.TopicTranslationConfig.GetInternal(*msg.TopicPartition.Topic)
 	if internalTopic == "" {
 		return fmt.Errorf("Topic mapping not found for: %s", *msg.TopicPartition.Topic)
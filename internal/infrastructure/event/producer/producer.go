@@ -1,6 +1,7 @@
 package producer
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -8,7 +9,12 @@ import (
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
 	"go.openly.dev/pointy"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // See: https://github.com/edenhill/librdkafka/blob/master/CONFIGURATION.md
@@ -70,10 +76,29 @@ func NewProducer(config *config.Kafka) (*kafka.Producer, error) {
 // happens before register the message to be produced, an error is returned
 // with information about the situation.
 func Produce(producer *kafka.Producer, topic string, key string, value interface{}, headers ...kafka.Header) error {
-	var (
-		err             error
-		marshalledValue []byte
+	return ProduceWithContext(context.Background(), producer, topic, key, value, headers...)
+}
+
+// ProduceWithContext is like Produce, but the message is produced
+// in a span child of the span in ctx, and the trace context is
+// propagated into the message headers.
+func ProduceWithContext(ctx context.Context, producer *kafka.Producer, topic string, key string, value interface{}, headers ...kafka.Header) (err error) {
+	var marshalledValue []byte
+
+	ctx, span := tracing.Tracer().Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", topic),
+		),
 	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 
 	if producer == nil {
 		return fmt.Errorf("producer cannot be nil")
@@ -113,6 +138,7 @@ func Produce(producer *kafka.Producer, topic string, key string, value interface
 	}
 
 	msg.Headers = append(msg.Headers, headers...)
+	otel.GetTextMapPropagator().Inject(ctx, tracing.NewKafkaHeadersCarrier(&msg.Headers))
 
 	// logEventMessageInfo(msg, "Producing message")
 	return producer.Produce(msg, nil)
@@ -0,0 +1,301 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Severity indicates how a failed check affects the readiness of
+// the service.
+type Severity string
+
+const (
+	// Critical checks make the service not ready when they fail.
+	Critical Severity = "critical"
+	// NonCritical checks only degrade the service when they fail.
+	NonCritical Severity = "non-critical"
+)
+
+// Status is the state of a check or of a report.
+type Status string
+
+const (
+	// StatusOk means the check succeeded, or every check of a report
+	// succeeded.
+	StatusOk Status = "ok"
+	// StatusDegraded means some non-critical check of a report
+	// failed.
+	StatusDegraded Status = "degraded"
+	// StatusFailed means the check failed, or some critical check of
+	// a report failed.
+	StatusFailed Status = "failed"
+)
+
+// Probe verify a dependency; it return nil when the dependency is
+// healthy. The probe must honor the deadline of ctx.
+type Probe func(ctx context.Context) error
+
+// Check describe a dependency registered into the Registry.
+type Check struct {
+	// Name identify the check into the reports; it must be unique.
+	Name string
+	// Severity indicates if a failure makes the service not ready.
+	Severity Severity
+	// Liveness indicates the check is run by the liveness probe
+	// too; only the failures that a restart could fix should be
+	// checked by the liveness probe.
+	Liveness bool
+	// Timeout is the max duration of the probe; zero means the
+	// timeout of the registry.
+	Timeout time.Duration
+	// Probe verify the dependency.
+	Probe Probe
+	// State describe the state of the dependency when the probe
+	// succeeded, for instance a circuit breaker which is open; it is
+	// reported as the message of the result. nil or an empty string
+	// report nothing.
+	State func() string
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name      string
+	Severity  Severity
+	Status    Status
+	Message   string
+	CheckedAt time.Time
+	Duration  time.Duration
+	// RetryAfter is the time until the dependency could be
+	// available again; zero when it is unknown.
+	RetryAfter time.Duration
+}
+
+// Report gather the results of the checks.
+type Report struct {
+	Status Status
+	Checks []Result
+}
+
+// RetryAfter return the max time until the failed critical checks
+// could succeed again, or zero when it is unknown.
+func (r *Report) RetryAfter() time.Duration {
+	var retryAfter time.Duration
+	for i := range r.Checks {
+		if r.Checks[i].Status == StatusFailed &&
+			r.Checks[i].Severity == Critical &&
+			r.Checks[i].RetryAfter > retryAfter {
+			retryAfter = r.Checks[i].RetryAfter
+		}
+	}
+	return retryAfter
+}
+
+// UnavailableError can be returned by a probe to indicate when the
+// dependency could be available again.
+type UnavailableError struct {
+	// RetryAfter is the time until the dependency could be
+	// available again.
+	RetryAfter time.Duration
+	// Err is the cause.
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return "unavailable"
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+type entry struct {
+	check  Check
+	mutex  sync.Mutex
+	result *Result
+}
+
+// Registry run the checks registered by the services and cache their
+// results, so frequent probes do not load the dependencies.
+type Registry struct {
+	cacheTTL time.Duration
+	timeout  time.Duration
+	mutex    sync.RWMutex
+	entries  []*entry
+	now      func() time.Time
+}
+
+// NewRegistry create an empty registry.
+// cacheTTL is the time the result of a check is reused; zero run
+// the checks on every probe.
+// timeout is the default max duration of the checks.
+func NewRegistry(cacheTTL, timeout time.Duration) *Registry {
+	return &Registry{
+		cacheTTL: cacheTTL,
+		timeout:  timeout,
+		now:      time.Now,
+	}
+}
+
+// Register add a check to the registry. It panics when the check
+// has no name or probe, or when the name is already registered.
+func (r *Registry) Register(check Check) {
+	if check.Name == "" {
+		panic("'check.Name' is empty")
+	}
+	if check.Probe == nil {
+		panic("'check.Probe' is nil")
+	}
+	if check.Severity == "" {
+		check.Severity = Critical
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, e := range r.entries {
+		if e.check.Name == check.Name {
+			panic(fmt.Sprintf("health check '%s' is already registered", check.Name))
+		}
+	}
+	r.entries = append(r.entries, &entry{check: check})
+}
+
+// Readiness run every registered check.
+func (r *Registry) Readiness(ctx context.Context) *Report {
+	return r.run(ctx, func(Check) bool { return true })
+}
+
+// Liveness run the checks registered for the liveness probe.
+func (r *Registry) Liveness(ctx context.Context) *Report {
+	return r.run(ctx, func(c Check) bool { return c.Liveness })
+}
+
+func (r *Registry) run(ctx context.Context, filter func(Check) bool) *Report {
+	r.mutex.RLock()
+	entries := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		if filter(e.check) {
+			entries = append(entries, e)
+		}
+	}
+	r.mutex.RUnlock()
+
+	results := make([]Result, len(entries))
+	wg := sync.WaitGroup{}
+	wg.Add(len(entries))
+	for i := range entries {
+		go func(i int) {
+			defer wg.Done()
+			results[i] = r.result(ctx, entries[i])
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	report := &Report{Status: StatusOk, Checks: results}
+	for i := range results {
+		if results[i].Status != StatusFailed {
+			continue
+		}
+		if results[i].Severity == Critical {
+			report.Status = StatusFailed
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	return report
+}
+
+// result return the cached result of the check, or run the probe
+// when it expired.
+func (r *Registry) result(ctx context.Context, e *entry) Result {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	now := r.now()
+	if e.result != nil && now.Sub(e.result.CheckedAt) < r.cacheTTL {
+		return *e.result
+	}
+	result := r.probe(ctx, e.check)
+	result.CheckedAt = now
+	e.result = &result
+	return result
+}
+
+// probe run the probe of check with its timeout. The probe is not
+// cancelled with ctx, so a client that goes away does not leave a
+// failure into the cache.
+func (r *Registry) probe(ctx context.Context, check Check) Result {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = r.timeout
+	}
+	ctx = context.WithoutCancel(ctx)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Probe(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timeout after %s", timeout)
+	}
+
+	result := Result{
+		Name:     check.Name,
+		Severity: check.Severity,
+		Status:   StatusOk,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Message = err.Error()
+		var unavailable *UnavailableError
+		if errors.As(err, &unavailable) {
+			result.RetryAfter = unavailable.RetryAfter
+		}
+	} else if check.State != nil {
+		result.Message = check.State()
+	}
+	return result
+}
+
+// NewHTTPProbe create a probe that check the service at url is
+// reachable; any response but a server error is considered healthy.
+// client is the http client used for the request; nil means
+// http.DefaultClient.
+func NewHTTPProbe(client *http.Client, url string) Probe {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+		if err != nil {
+			return err
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status code %d", res.StatusCode)
+		}
+		return nil
+	}
+}
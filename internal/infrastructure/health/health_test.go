@@ -0,0 +1,171 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	r := NewRegistry(0, time.Second)
+
+	assert.PanicsWithValue(t, "'check.Name' is empty", func() {
+		r.Register(Check{Probe: ok})
+	})
+	assert.PanicsWithValue(t, "'check.Probe' is nil", func() {
+		r.Register(Check{Name: "db"})
+	})
+	assert.NotPanics(t, func() {
+		r.Register(Check{Name: "db", Probe: ok})
+	})
+	assert.PanicsWithValue(t, "health check 'db' is already registered", func() {
+		r.Register(Check{Name: "db", Probe: ok})
+	})
+
+	// Severity is critical by default
+	report := r.Readiness(context.Background())
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, Critical, report.Checks[0].Severity)
+}
+
+func TestReadiness(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	// No checks
+	report := NewRegistry(0, time.Second).Readiness(context.Background())
+	assert.Equal(t, StatusOk, report.Status)
+	assert.Empty(t, report.Checks)
+
+	// Every check succeed
+	r := NewRegistry(0, time.Second)
+	r.Register(Check{Name: "pendo", Severity: NonCritical, Probe: ok})
+	r.Register(Check{Name: "database", Severity: Critical, Probe: ok})
+	report = r.Readiness(context.Background())
+	assert.Equal(t, StatusOk, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "database", report.Checks[0].Name)
+	assert.Equal(t, "pendo", report.Checks[1].Name)
+	assert.Equal(t, StatusOk, report.Checks[0].Status)
+	assert.Empty(t, report.Checks[0].Message)
+
+	// A non-critical check fails
+	r = NewRegistry(0, time.Second)
+	r.Register(Check{Name: "database", Severity: Critical, Probe: ok})
+	r.Register(Check{Name: "pendo", Severity: NonCritical, Probe: fail})
+	report = r.Readiness(context.Background())
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusFailed, report.Checks[1].Status)
+	assert.Equal(t, "connection refused", report.Checks[1].Message)
+	assert.Equal(t, time.Duration(0), report.RetryAfter())
+
+	// A critical check fails
+	r = NewRegistry(0, time.Second)
+	r.Register(Check{Name: "database", Severity: Critical, Probe: fail})
+	r.Register(Check{Name: "pendo", Severity: NonCritical, Probe: fail})
+	r.Register(Check{Name: "rbac", Severity: Critical, Probe: func(ctx context.Context) error {
+		return &UnavailableError{RetryAfter: 10 * time.Second, Err: errors.New("circuit breaker is open")}
+	}})
+	report = r.Readiness(context.Background())
+	assert.Equal(t, StatusFailed, report.Status)
+	assert.Equal(t, "circuit breaker is open", report.Checks[2].Message)
+	assert.Equal(t, 10*time.Second, report.RetryAfter())
+
+	// The state is reported when the check succeeds
+	state := "circuit breaker is open"
+	r = NewRegistry(0, time.Second)
+	r.Register(Check{Name: "rbac", Severity: Critical, Probe: ok, State: func() string { return state }})
+	report = r.Readiness(context.Background())
+	assert.Equal(t, StatusOk, report.Status)
+	assert.Equal(t, StatusOk, report.Checks[0].Status)
+	assert.Equal(t, "circuit breaker is open", report.Checks[0].Message)
+	state = ""
+	report = r.Readiness(context.Background())
+	assert.Empty(t, report.Checks[0].Message)
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	block := func(ctx context.Context) error {
+		select {}
+	}
+	r := NewRegistry(0, time.Hour)
+	r.Register(Check{Name: "slow", Timeout: 10 * time.Millisecond, Probe: block})
+	report := r.Readiness(context.Background())
+	assert.Equal(t, StatusFailed, report.Status)
+	assert.Equal(t, "timeout after 10ms", report.Checks[0].Message)
+
+	// A cancelled request does not fail the checks
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r = NewRegistry(0, time.Second)
+	r.Register(Check{Name: "database", Probe: func(ctx context.Context) error {
+		return ctx.Err()
+	}})
+	report = r.Readiness(ctx)
+	assert.Equal(t, StatusOk, report.Status)
+}
+
+func TestReadinessCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	r := NewRegistry(5*time.Second, time.Second)
+	r.now = func() time.Time { return now }
+	r.Register(Check{Name: "database", Probe: func(ctx context.Context) error {
+		calls++
+		return nil
+	}})
+
+	report := r.Readiness(context.Background())
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, now, report.Checks[0].CheckedAt)
+
+	// The cached result is reused
+	now = now.Add(4 * time.Second)
+	report = r.Readiness(context.Background())
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, now.Add(-4*time.Second), report.Checks[0].CheckedAt)
+
+	// The expired result is refreshed
+	now = now.Add(time.Second)
+	report = r.Readiness(context.Background())
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, now, report.Checks[0].CheckedAt)
+}
+
+func TestLiveness(t *testing.T) {
+	fail := func(ctx context.Context) error { return errors.New("stopped") }
+	r := NewRegistry(0, time.Second)
+	r.Register(Check{Name: "database", Probe: fail})
+	r.Register(Check{Name: "kafka-consumer", Liveness: true, Probe: fail})
+
+	report := r.Liveness(context.Background())
+	assert.Equal(t, StatusFailed, report.Status)
+	require.Len(t, report.Checks, 1)
+	assert.Equal(t, "kafka-consumer", report.Checks[0].Name)
+}
+
+func TestNewHTTPProbe(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	probe := NewHTTPProbe(nil, srv.URL)
+
+	assert.NoError(t, probe(context.Background()))
+
+	status = http.StatusUnauthorized
+	assert.NoError(t, probe(context.Background()))
+
+	status = http.StatusBadGateway
+	assert.EqualError(t, probe(context.Background()), "unexpected status code 502")
+
+	assert.Error(t, NewHTTPProbe(srv.Client(), "http://[::1]:namedport")(context.Background()))
+}
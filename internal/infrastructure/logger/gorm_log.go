@@ -16,6 +16,9 @@ import (
 type gormLogger struct {
 	slogger                   *slog.Logger
 	IgnoreRecordNotFoundError bool
+	// SlowThreshold is the duration from which the statements are
+	// logged as slow queries; 0 disable it.
+	SlowThreshold time.Duration
 }
 
 // _LogCommon This function creates slog messages with correct source code locations
@@ -94,6 +97,18 @@ func (l *gormLogger) Trace(
 			slog.Duration("elapsed", elapsedTime),
 			slog.Int64("rows", rows),
 		)
+	} else if l.SlowThreshold > 0 && elapsedTime > l.SlowThreshold {
+		sql, rows := fc()
+
+		l._Log(
+			ctx,
+			slog.LevelWarn,
+			"SQL slow query",
+			slog.String("query", sql),
+			slog.Duration("elapsed", elapsedTime),
+			slog.Duration("threshold", l.SlowThreshold),
+			slog.Int64("rows", rows),
+		)
 	} else {
 		sql, rows := fc()
 
@@ -108,9 +123,14 @@ func (l *gormLogger) Trace(
 	}
 }
 
-func NewGormLog(ignoreRecordNotFound bool) logger.Interface {
+// NewGormLog create the logger for gorm.
+// ignoreRecordNotFound skip the record not found errors.
+// slowThreshold is the duration from which the statements are
+// logged as slow queries with warning level; 0 disable it.
+func NewGormLog(ignoreRecordNotFound bool, slowThreshold time.Duration) logger.Interface {
 	return &gormLogger{
 		slogger:                   slog.Default(),
 		IgnoreRecordNotFoundError: true,
+		SlowThreshold:             slowThreshold,
 	}
 }
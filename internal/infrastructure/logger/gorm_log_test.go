@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormLogTraceSlowQuery(t *testing.T) {
+	var buf bytes.Buffer
+	l := &gormLogger{
+		slogger:       slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: LevelTrace})),
+		SlowThreshold: 100 * time.Millisecond,
+	}
+	fc := func() (string, int64) { return "SELECT 1", 1 }
+	ctx := context.Background()
+
+	// Fast query is traced
+	l.Trace(ctx, time.Now(), fc, nil)
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "SQL query executed", record["msg"])
+
+	// Slow query is a warning
+	buf.Reset()
+	l.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+	record = map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "SQL slow query", record["msg"])
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "SELECT 1", record["query"])
+	assert.Equal(t, float64(100*time.Millisecond), record["threshold"])
+
+	// Slow query log disabled
+	buf.Reset()
+	l.SlowThreshold = 0
+	l.Trace(ctx, time.Now().Add(-time.Second), fc, nil)
+	record = map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "SQL query executed", record["msg"])
+}
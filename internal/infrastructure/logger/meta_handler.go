@@ -3,6 +3,15 @@ package logger
 import (
 	"context"
 	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TraceIDKey is the attribute key for the trace id of the active span.
+	TraceIDKey = "trace_id"
+	// SpanIDKey is the attribute key for the id of the active span.
+	SpanIDKey = "span_id"
 )
 
 // type SlogMetaHandler slog.Handler
@@ -13,6 +22,8 @@ type SlogMetaHandler interface {
 
 type slogMetaHandler struct {
 	handlers []slog.Handler
+	// traced is true when the trace attributes were added by WithAttrs
+	traced bool
 }
 
 func NewSlogMetaHandler() SlogMetaHandler {
@@ -41,7 +52,10 @@ func (h *slogMetaHandler) Enabled(ctx context.Context, level slog.Level) bool {
 // WithAttrs returns a new MetaHandler whose attributes consists
 // of h's attributes followed by attrs.
 func (h *slogMetaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	newMeta := &slogMetaHandler{handlers: make([]slog.Handler, len(h.handlers))}
+	newMeta := &slogMetaHandler{
+		handlers: make([]slog.Handler, len(h.handlers)),
+		traced:   h.traced || hasTraceAttr(attrs),
+	}
 	for i := range h.handlers {
 		newMeta.handlers[i] = h.handlers[i].WithAttrs(attrs)
 	}
@@ -50,7 +64,10 @@ func (h *slogMetaHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 
 // WithGroup add a new group to the structured log.
 func (h *slogMetaHandler) WithGroup(name string) slog.Handler {
-	newMeta := &slogMetaHandler{handlers: make([]slog.Handler, len(h.handlers))}
+	newMeta := &slogMetaHandler{
+		handlers: make([]slog.Handler, len(h.handlers)),
+		traced:   h.traced,
+	}
 	for i := range h.handlers {
 		newMeta.handlers[i] = h.handlers[i].WithGroup(name)
 	}
@@ -58,9 +75,19 @@ func (h *slogMetaHandler) WithGroup(name string) slog.Handler {
 }
 
 // Handle formats its argument Record as a single line of space-separated
-// key=value items.
+// key=value items. The trace and span ids of the span in ctx are
+// added to the record, unless they were already added by WithAttrs.
 func (h *slogMetaHandler) Handle(ctx context.Context, r slog.Record) error {
 	var err error
+	if !h.traced && ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r = r.Clone()
+			r.AddAttrs(
+				slog.String(TraceIDKey, sc.TraceID().String()),
+				slog.String(SpanIDKey, sc.SpanID().String()),
+			)
+		}
+	}
 	for i := range h.handlers {
 		if err2 := h.handlers[i].Handle(ctx, r); err2 != nil {
 			err = err2
@@ -68,3 +95,12 @@ func (h *slogMetaHandler) Handle(ctx context.Context, r slog.Record) error {
 	}
 	return err
 }
+
+func hasTraceAttr(attrs []slog.Attr) bool {
+	for i := range attrs {
+		if attrs[i].Key == TraceIDKey {
+			return true
+		}
+	}
+	return false
+}
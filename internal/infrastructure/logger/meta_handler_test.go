@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestNewSlogMetaHandler(t *testing.T) {
@@ -135,3 +136,41 @@ func TestTestNewSlogMetaHandler_Log(t *testing.T) {
 	assert.Equal(t, expected_log, w1.String())
 	assert.Equal(t, expected_log, w2.String())
 }
+
+func TestTestNewSlogMetaHandler_TraceIDs(t *testing.T) {
+	mh := &slogMetaHandler{
+		handlers: []slog.Handler{},
+	}
+
+	w := &stringWriter{buffer: &bytes.Buffer{}}
+	slogMetaHandlerCreate(mh, w)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01},
+		SpanID:     trace.SpanID{0x02},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	logger := slog.New(mh)
+	logger.ErrorContext(ctx, "z")
+	assert.Equal(t, "time=NODATE level=ERROR msg=z"+
+		" trace_id=01000000000000000000000000000000"+
+		" span_id=0200000000000000\n", w.String())
+
+	// Attributes already added by the logger are not duplicated
+	w.buffer.Reset()
+	logger = logger.With(
+		slog.String(TraceIDKey, sc.TraceID().String()),
+		slog.String(SpanIDKey, sc.SpanID().String()),
+	)
+	logger.ErrorContext(ctx, "z")
+	assert.Equal(t, "time=NODATE level=ERROR msg=z"+
+		" trace_id=01000000000000000000000000000000"+
+		" span_id=0200000000000000\n", w.String())
+
+	// No span in the context
+	w.buffer.Reset()
+	slog.New(mh).ErrorContext(context.Background(), "z")
+	assert.Equal(t, "time=NODATE level=ERROR msg=z\n", w.String())
+}
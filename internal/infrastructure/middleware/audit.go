@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+)
+
+const auditMessage = "audit"
+
+// AuditConfig hold the configuration for the audit middleware.
+type AuditConfig struct {
+	// Skipper function to skip the requests which are not audited
+	Skipper middleware.Skipper
+}
+
+// AuditWithConfig create a middleware which write an audit record
+// into the log for every request which is not skipped, once it is
+// processed. The record has the identity of the request when it
+// was parsed, the route and the response status.
+// cfg is the configuration for the middleware.
+// Return the initialized middleware or panic if cfg is nil.
+func AuditWithConfig(cfg *AuditConfig) echo.MiddlewareFunc {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+
+			err := next(c)
+
+			// The logger is read after processing the request, so it
+			// has the identity added by the middlewares
+			logger := app_context.LogFromCtx(c.Request().Context())
+			Audit(logger, c.Request().Method, c.Path(), c.Request().RequestURI, auditStatus(c, err))
+			return err
+		}
+	}
+}
+
+// Audit write an audit record into the log; it is used by the
+// handlers which run several operations in one request, so every
+// operation has the record it would have on its own endpoint.
+// logger is the logger with the identity of the request.
+// method, route and uri identify the operation.
+// status is the http status of the operation.
+// attrs are additional attributes for the record.
+func Audit(logger *slog.Logger, method, route, uri string, status int, attrs ...any) {
+	args := append([]any{
+		slog.Bool("audit", true),
+		slog.String("method", method),
+		slog.String("route", route),
+		slog.String("uri", uri),
+		slog.Int("status", status),
+	}, attrs...)
+	logger.Info(auditMessage, args...)
+}
+
+// auditStatus return the status of the response, or the status
+// which the error will produce.
+func auditStatus(c echo.Context, err error) int {
+	var httpErr *echo.HTTPError
+	if err == nil {
+		return c.Response().Status
+	}
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return http.StatusInternalServerError
+}
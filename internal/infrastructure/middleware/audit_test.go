@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperAuditRequest(t *testing.T, cfg *AuditConfig, h echo.HandlerFunc) map[string]any {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := app_context.CtxWithLog(c.Request().Context(), logger)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	})
+	e.Use(AuditWithConfig(cfg))
+	e.DELETE("/domains/:uuid", h)
+
+	req := httptest.NewRequest(http.MethodDelete, "/domains/1", http.NoBody)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if buf.Len() == 0 {
+		return nil
+	}
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	return record
+}
+
+func TestAuditWithConfig(t *testing.T) {
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		AuditWithConfig(nil)
+	})
+
+	// Success request
+	record := helperAuditRequest(t, &AuditConfig{}, func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	require.NotNil(t, record)
+	assert.Equal(t, auditMessage, record["msg"])
+	assert.Equal(t, true, record["audit"])
+	assert.Equal(t, http.MethodDelete, record["method"])
+	assert.Equal(t, "/domains/:uuid", record["route"])
+	assert.Equal(t, "/domains/1", record["uri"])
+	assert.Equal(t, float64(http.StatusNoContent), record["status"])
+
+	// Request with http error
+	record = helperAuditRequest(t, &AuditConfig{}, func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden, "forbidden")
+	})
+	require.NotNil(t, record)
+	assert.Equal(t, float64(http.StatusForbidden), record["status"])
+
+	// Request with other error
+	record = helperAuditRequest(t, &AuditConfig{}, func(c echo.Context) error {
+		return errors.New("unexpected")
+	})
+	require.NotNil(t, record)
+	assert.Equal(t, float64(http.StatusInternalServerError), record["status"])
+
+	// Skipped request
+	record = helperAuditRequest(t, &AuditConfig{
+		Skipper: func(c echo.Context) bool { return true },
+	}, func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+	assert.Nil(t, record)
+}
+
+func TestAudit(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	Audit(logger, http.MethodPatch, "/domains/:uuid", "/domains/1", http.StatusOK,
+		slog.Int("batch_index", 2))
+
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, auditMessage, record["msg"])
+	assert.Equal(t, true, record["audit"])
+	assert.Equal(t, http.MethodPatch, record["method"])
+	assert.Equal(t, "/domains/:uuid", record["route"])
+	assert.Equal(t, "/domains/1", record["uri"])
+	assert.Equal(t, float64(http.StatusOK), record["status"])
+	assert.Equal(t, float64(2), record["batch_index"])
+}
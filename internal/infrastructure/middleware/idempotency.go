@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echo_middleware "github.com/labstack/echo/v4/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+)
+
+// idempotencyKeyMaxLength is the max length of the Idempotency-Key
+// header.
+const idempotencyKeyMaxLength = 255
+
+const (
+	idempotencyResultStored     = "stored"
+	idempotencyResultReplayed   = "replayed"
+	idempotencyResultMismatch   = "mismatch"
+	idempotencyResultInProgress = "in_progress"
+	idempotencyResultError      = "error"
+)
+
+// IdempotencyConfig hold the configuration for the idempotency
+// middleware.
+type IdempotencyConfig struct {
+	// Skipper function to skip for some request if necessary
+	Skipper echo_middleware.Skipper
+	// Store keep the responses for the idempotency keys.
+	Store idempotency.Store
+	// TTL is the time the response for a key is replayed.
+	TTL time.Duration
+	// LockTimeout is the time a key is locked by the request in
+	// progress; a retry after it takes the key over, so a key is not
+	// blocked until TTL by a request which was lost.
+	LockTimeout time.Duration
+	// Metrics to count the requests with an idempotency key.
+	Metrics *metrics.Metrics
+}
+
+// idempotencyWriter copy the body of the response, so it can be
+// stored for the idempotency key.
+type idempotencyWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// IdempotencyWithConfig create a middleware which store the response
+// for the Idempotency-Key header of the request, scoped by
+// organization, and replay it unchanged when the request is retried
+// with the same key. A request with the same key but a different
+// method, path or body is rejected with 422; a retry while the first
+// request is in progress is rejected with 409. The responses with a
+// server error are not stored, so the request can be retried. It
+// must be wired after the identity is parsed; when the store fails,
+// the request is processed without idempotency.
+// cfg is the configuration for the middleware.
+// Return the initialized middleware or panic if some guard condition
+// is matched.
+func IdempotencyWithConfig(cfg *IdempotencyConfig) echo.MiddlewareFunc {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if cfg.Store == nil {
+		panic("'Store' is nil")
+	}
+	if cfg.TTL <= 0 {
+		panic("'TTL' is not positive")
+	}
+	if cfg.LockTimeout <= 0 {
+		panic("'LockTimeout' is not positive")
+	}
+	if cfg.Metrics == nil {
+		panic("'Metrics' is nil")
+	}
+	if cfg.Skipper == nil {
+		cfg.Skipper = echo_middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(header.HeaderIdempotencyKey)
+			if key == "" || cfg.Skipper(c) {
+				return next(c)
+			}
+			cc, ok := c.(DomainContextInterface)
+			if !ok || cc.XRHID() == nil {
+				return next(c)
+			}
+			if !isValidIdempotencyKey(key) {
+				return internal_errors.ErrIdempotencyKeyInvalid.New(nil,
+					"%s must have from 1 to %d visible ASCII characters",
+					header.HeaderIdempotencyKey, idempotencyKeyMaxLength)
+			}
+			fingerprint, err := idempotencyFingerprint(c.Request())
+			if err != nil {
+				return err
+			}
+
+			ctx := c.Request().Context()
+			scope := cc.XRHID().Identity.OrgID
+			now := time.Now()
+			record, err := cfg.Store.Begin(ctx, scope, key, fingerprint, now.Add(cfg.LockTimeout), now.Add(cfg.TTL))
+			if err != nil {
+				app_context.LogFromCtx(ctx).Warn("idempotency key could not be checked", "error", err.Error())
+				cfg.Metrics.IdempotencyKeys.WithLabelValues(idempotencyResultError).Inc()
+				return next(c)
+			}
+			if record != nil {
+				return replayIdempotency(c, cfg, record, fingerprint)
+			}
+			return storeIdempotency(c, cfg, next, scope, key, fingerprint)
+		}
+	}
+}
+
+// replayIdempotency write the response stored by the first request
+// with the same key.
+func replayIdempotency(c echo.Context, cfg *IdempotencyConfig, record *idempotency.Record, fingerprint string) error {
+	logger := app_context.LogFromCtx(c.Request().Context())
+	if record.Fingerprint != fingerprint {
+		logger.Info("idempotency key used for a different request")
+		cfg.Metrics.IdempotencyKeys.WithLabelValues(idempotencyResultMismatch).Inc()
+		return internal_errors.ErrIdempotencyKeyMismatch.New(nil,
+			"%s was used for a different request", header.HeaderIdempotencyKey)
+	}
+	if !record.Completed() {
+		logger.Info("idempotency key in use by a request in progress")
+		cfg.Metrics.IdempotencyKeys.WithLabelValues(idempotencyResultInProgress).Inc()
+		c.Response().Header().Set(headerRetryAfter, "1")
+		return internal_errors.ErrIdempotencyKeyInProgress.New(nil,
+			"A request with the same %s is in progress", header.HeaderIdempotencyKey)
+	}
+	logger.Debug("replaying the response for the idempotency key")
+	cfg.Metrics.IdempotencyKeys.WithLabelValues(idempotencyResultReplayed).Inc()
+	c.Response().Header().Set(header.HeaderIdempotentReplayed, "true")
+	if record.ContentType == "" {
+		return c.NoContent(record.StatusCode)
+	}
+	return c.Blob(record.StatusCode, record.ContentType, record.Body)
+}
+
+// storeIdempotency process the request and store its response for
+// the key reserved; the key is released when the response is not
+// stored.
+func storeIdempotency(c echo.Context, cfg *IdempotencyConfig, next echo.HandlerFunc, scope, key, fingerprint string) error {
+	ctx := context.WithoutCancel(c.Request().Context())
+	logger := app_context.LogFromCtx(ctx)
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := cfg.Store.Release(ctx, scope, key); err != nil {
+			logger.Warn("idempotency key could not be released", "error", err.Error())
+		}
+	}()
+
+	writer := &idempotencyWriter{ResponseWriter: c.Response().Writer}
+	c.Response().Writer = writer
+	err := next(c)
+	if err != nil {
+		// Write the error response to store it; the error is
+		// returned for the other middlewares, and the error handler
+		// skip the committed response.
+		c.Error(err)
+	}
+	c.Response().Writer = writer.ResponseWriter
+
+	status := c.Response().Status
+	if !c.Response().Committed || status >= http.StatusInternalServerError {
+		return err
+	}
+	record := &idempotency.Record{
+		Fingerprint: fingerprint,
+		StatusCode:  status,
+		ContentType: c.Response().Header().Get(echo.HeaderContentType),
+		Body:        writer.body.Bytes(),
+	}
+	if errStore := cfg.Store.Complete(ctx, scope, key, record); errStore != nil {
+		logger.Warn("idempotency key response could not be stored", "error", errStore.Error())
+		cfg.Metrics.IdempotencyKeys.WithLabelValues(idempotencyResultError).Inc()
+		return err
+	}
+	completed = true
+	cfg.Metrics.IdempotencyKeys.WithLabelValues(idempotencyResultStored).Inc()
+	return err
+}
+
+// isValidIdempotencyKey check the key is not too long and only has
+// visible ASCII characters.
+func isValidIdempotencyKey(key string) bool {
+	if len(key) > idempotencyKeyMaxLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < '!' || key[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// idempotencyFingerprint return the hash of the method, the path and
+// the body of the request; the body is restored to be read again.
+func idempotencyFingerprint(req *http.Request) (string, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + "\n" + req.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
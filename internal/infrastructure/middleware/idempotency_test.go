@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	api_builder "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	mock_idempotency "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/idempotency"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIdempotencyOrgID = "12345"
+	testIdempotencyKey   = "6f0d7a1c-4d4f-4b3e-9d0e-3c1b2a1f0e9d"
+	testIdempotencyBody  = `{"domain_name":"mydomain.example"}`
+)
+
+func helperIdempotencyConfig(t *testing.T) (*IdempotencyConfig, *mock_idempotency.Store) {
+	store := mock_idempotency.NewStore(t)
+	return &IdempotencyConfig{
+		Store:       store,
+		TTL:         time.Hour,
+		LockTimeout: 3 * time.Second,
+		Metrics:     metrics.NewMetrics(prometheus.NewRegistry()),
+	}, store
+}
+
+// helperIdempotencyRequest send a request with the key to a handler
+// which return handlerErr, or 201 and a json body.
+// Return the response and the number of times the handler was called.
+func helperIdempotencyRequest(cfg *IdempotencyConfig, key string, handlerErr error) (*httptest.ResponseRecorder, int) {
+	calls := 0
+	e := echo.New()
+	e.Use(ContextLogConfig(&LogConfig{}))
+	e.Use(CreateContext())
+	e.Use(ParseXRHIDMiddlewareWithConfig(&ParseXRHIDMiddlewareConfig{}))
+	e.Use(IdempotencyWithConfig(cfg))
+	e.POST("/domains", func(c echo.Context) error {
+		calls++
+		if handlerErr != nil {
+			return handlerErr
+		}
+		return c.JSONBlob(http.StatusCreated, []byte(`{"domain_id":"c5d2c9c4-e4a3-4bf6-b9ae-0b4bf5a4b8a6"}`))
+	})
+	xrhid := api_builder.NewSystemXRHID().
+		WithOrgID(testIdempotencyOrgID).
+		Build()
+	req := httptest.NewRequest(http.MethodPost, "/domains", strings.NewReader(testIdempotencyBody))
+	req.Header.Set(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+	if key != "" {
+		req.Header.Set(header.HeaderIdempotencyKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec, calls
+}
+
+func helperIdempotencyFingerprint(t *testing.T, body string) string {
+	req := httptest.NewRequest(http.MethodPost, "/domains", strings.NewReader(body))
+	fingerprint, err := idempotencyFingerprint(req)
+	require.NoError(t, err)
+	return fingerprint
+}
+
+func TestIdempotencyWithConfigGuards(t *testing.T) {
+	cfg, _ := helperIdempotencyConfig(t)
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		IdempotencyWithConfig(nil)
+	})
+	assert.PanicsWithValue(t, "'Store' is nil", func() {
+		IdempotencyWithConfig(&IdempotencyConfig{})
+	})
+	assert.PanicsWithValue(t, "'TTL' is not positive", func() {
+		IdempotencyWithConfig(&IdempotencyConfig{Store: cfg.Store})
+	})
+	assert.PanicsWithValue(t, "'LockTimeout' is not positive", func() {
+		IdempotencyWithConfig(&IdempotencyConfig{Store: cfg.Store, TTL: cfg.TTL})
+	})
+	assert.PanicsWithValue(t, "'Metrics' is nil", func() {
+		IdempotencyWithConfig(&IdempotencyConfig{Store: cfg.Store, TTL: cfg.TTL, LockTimeout: cfg.LockTimeout})
+	})
+	assert.NotPanics(t, func() {
+		IdempotencyWithConfig(cfg)
+	})
+}
+
+func TestIdempotencyWithConfigNoKey(t *testing.T) {
+	cfg, _ := helperIdempotencyConfig(t)
+	rec, calls := helperIdempotencyRequest(cfg, "", nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyWithConfigInvalidKey(t *testing.T) {
+	cfg, _ := helperIdempotencyConfig(t)
+	for _, key := range []string{"with space", strings.Repeat("a", idempotencyKeyMaxLength+1)} {
+		rec, calls := helperIdempotencyRequest(cfg, key, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Zero(t, calls)
+	}
+}
+
+func TestIdempotencyWithConfigStored(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, fingerprint, mock.Anything, mock.Anything).
+		Return(nil, nil)
+	store.On("Complete", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, &idempotency.Record{
+		Fingerprint: fingerprint,
+		StatusCode:  http.StatusCreated,
+		ContentType: echo.MIMEApplicationJSON,
+		Body:        []byte(`{"domain_id":"c5d2c9c4-e4a3-4bf6-b9ae-0b4bf5a4b8a6"}`),
+	}).Return(nil)
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, rec.Header().Get(header.HeaderIdempotentReplayed))
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.IdempotencyKeys.WithLabelValues("stored")))
+}
+
+func TestIdempotencyWithConfigClientErrorIsStored(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, fingerprint, mock.Anything, mock.Anything).
+		Return(nil, nil)
+	store.On("Complete", mock.Anything, testIdempotencyOrgID, testIdempotencyKey,
+		mock.MatchedBy(func(record *idempotency.Record) bool {
+			return record.StatusCode == http.StatusConflict &&
+				strings.Contains(string(record.Body), "already registered")
+		})).Return(nil)
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey,
+		echo.NewHTTPError(http.StatusConflict, "already registered"))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyWithConfigServerErrorIsReleased(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, fingerprint, mock.Anything, mock.Anything).
+		Return(nil, nil)
+	store.On("Release", mock.Anything, testIdempotencyOrgID, testIdempotencyKey).
+		Return(nil)
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey, fmt.Errorf("connection refused"))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyWithConfigReplayed(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, fingerprint, mock.Anything, mock.Anything).
+		Return(&idempotency.Record{
+			Fingerprint: fingerprint,
+			StatusCode:  http.StatusCreated,
+			ContentType: echo.MIMEApplicationJSON,
+			Body:        []byte(`{"domain_id":"first"}`),
+		}, nil)
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Zero(t, calls)
+	assert.Equal(t, `{"domain_id":"first"}`, rec.Body.String())
+	assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "true", rec.Header().Get(header.HeaderIdempotentReplayed))
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.IdempotencyKeys.WithLabelValues("replayed")))
+}
+
+func TestIdempotencyWithConfigMismatch(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, fingerprint, mock.Anything, mock.Anything).
+		Return(&idempotency.Record{
+			Fingerprint: helperIdempotencyFingerprint(t, `{"domain_name":"other.example"}`),
+			StatusCode:  http.StatusCreated,
+		}, nil)
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Zero(t, calls)
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.IdempotencyKeys.WithLabelValues("mismatch")))
+}
+
+func TestIdempotencyWithConfigInProgress(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, fingerprint, mock.Anything, mock.Anything).
+		Return(&idempotency.Record{Fingerprint: fingerprint}, nil)
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey, nil)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(headerRetryAfter))
+	assert.Zero(t, calls)
+}
+
+func TestIdempotencyWithConfigStoreError(t *testing.T) {
+	cfg, store := helperIdempotencyConfig(t)
+	store.On("Begin", mock.Anything, testIdempotencyOrgID, testIdempotencyKey, mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("connection refused"))
+
+	rec, calls := helperIdempotencyRequest(cfg, testIdempotencyKey, nil)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.IdempotencyKeys.WithLabelValues("error")))
+}
+
+func TestIdempotencyFingerprint(t *testing.T) {
+	fingerprint := helperIdempotencyFingerprint(t, testIdempotencyBody)
+	assert.Len(t, fingerprint, 64)
+	assert.Equal(t, fingerprint, helperIdempotencyFingerprint(t, testIdempotencyBody))
+	assert.NotEqual(t, fingerprint, helperIdempotencyFingerprint(t, `{}`))
+
+	req := httptest.NewRequest(http.MethodPut, "/domains", strings.NewReader(testIdempotencyBody))
+	other, err := idempotencyFingerprint(req)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, other)
+}
@@ -0,0 +1,317 @@
+package policy
+
+// Example of the security policy file:
+//
+// ---
+// version: "1.0"
+// prefix: "/api/idmsvc/v1"
+// operations:
+//   ListDomains:
+//     identities: [user, service-account]
+//     permission: "idmsvc:domains:list"
+//     rate: read
+//   RegisterDomain:
+//     identities: [system]
+//     rate: agent
+//     audit: true
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	rbac_data "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/rbac-data"
+	"gopkg.in/yaml.v3"
+)
+
+// Identity is the type of identity allowed for an operation.
+type Identity string
+
+const (
+	// IdentityUser is a console user.
+	IdentityUser Identity = "user"
+	// IdentityServiceAccount is a service account.
+	IdentityServiceAccount Identity = "service-account"
+	// IdentitySystem is a host with a rhsm certificate.
+	IdentitySystem Identity = "system"
+	// IdentityAny does not enforce the identity type; it is
+	// used for the public operations.
+	IdentityAny Identity = "any"
+)
+
+// RateClass group the operations which share the same rate limits.
+type RateClass string
+
+const (
+	// RateClassRead is for the operations which only read data.
+	RateClassRead RateClass = "read"
+	// RateClassWrite is for the operations which modify data on
+	// behalf of a user.
+	RateClassWrite RateClass = "write"
+	// RateClassAgent is for the operations called by the ipa-hcc
+	// agents from the domain servers and the hosts.
+	RateClassAgent RateClass = "agent"
+)
+
+const policyFileVersion = "1.0"
+
+// Policy is the security policy of one operation.
+type Policy struct {
+	// Identities is the list of identity types allowed.
+	Identities []Identity `yaml:"identities"`
+	// Permission is the rbac permission checked for user and
+	// service account identities; empty when no permission is
+	// checked.
+	Permission string `yaml:"permission"`
+	// Rate is the rate class of the operation.
+	Rate RateClass `yaml:"rate"`
+	// Audit is true when the operation is written into the audit log.
+	Audit bool `yaml:"audit"`
+	// Idempotent is true when the operation accept the
+	// Idempotency-Key header.
+	Idempotent bool `yaml:"idempotent"`
+}
+
+// Operation is an operation exposed by the API.
+type Operation struct {
+	// ID is the operationId in the openapi specification.
+	ID string
+	// Method is the http method.
+	Method string
+	// Path is the echo route relative to the API prefix, for
+	// instance '/domains/:uuid'.
+	Path string
+}
+
+type policyFile struct {
+	Version    string             `yaml:"version"`
+	Prefix     string             `yaml:"prefix"`
+	Operations map[string]*Policy `yaml:"operations"`
+}
+
+type route struct {
+	method string
+	path   string
+}
+
+// SecurityPolicy hold the policies for every operation of the API.
+type SecurityPolicy struct {
+	prefix     string
+	operations map[string]*Policy
+	routes     map[route]*Policy
+}
+
+// Load parse the security policy file.
+// data is the yaml content of the file.
+// Return the security policy and nil on success, else nil and
+// an error.
+func Load(data []byte) (*SecurityPolicy, error) {
+	var file policyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Version != policyFileVersion {
+		return nil, fmt.Errorf("it was expected security policy version '%s', but '%s' was found", policyFileVersion, file.Version)
+	}
+	if file.Prefix == "" {
+		return nil, errors.New("security policy prefix is an empty string")
+	}
+	if len(file.Operations) == 0 {
+		return nil, errors.New("security policy has no operations")
+	}
+	for id, p := range file.Operations {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("security policy for '%s': %w", id, err)
+		}
+	}
+	return &SecurityPolicy{
+		prefix:     file.Prefix,
+		operations: file.Operations,
+		routes:     map[route]*Policy{},
+	}, nil
+}
+
+func (p *Policy) validate() error {
+	if p == nil {
+		return errors.New("policy is empty")
+	}
+	if len(p.Identities) == 0 {
+		return errors.New("identities is empty")
+	}
+	for _, identity := range p.Identities {
+		switch identity {
+		case IdentityUser, IdentityServiceAccount, IdentitySystem:
+		case IdentityAny:
+			if len(p.Identities) > 1 {
+				return fmt.Errorf("identity '%s' can not be combined", identity)
+			}
+		default:
+			return fmt.Errorf("identity '%s' is not supported", identity)
+		}
+	}
+	if p.Permission != "" {
+		if parts := strings.Split(p.Permission, ":"); len(parts) != 3 || slices.Contains(parts, "") {
+			return fmt.Errorf("permission '%s' does not match 'service:resource:verb'", p.Permission)
+		}
+		if !p.Allows(IdentityUser) && !p.Allows(IdentityServiceAccount) {
+			return fmt.Errorf("permission '%s' is set but no user nor service-account identity is allowed", p.Permission)
+		}
+	}
+	switch p.Rate {
+	case RateClassRead, RateClassWrite, RateClassAgent:
+	case "":
+		return errors.New("rate is empty")
+	default:
+		return fmt.Errorf("rate '%s' is not supported", p.Rate)
+	}
+	return nil
+}
+
+// Allows check if the identity type is allowed by the policy.
+// Return true if the identity is allowed, else false.
+func (p *Policy) Allows(identity Identity) bool {
+	if p == nil {
+		return false
+	}
+	for _, item := range p.Identities {
+		if item == identity || item == IdentityAny {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefix return the path prefix of the API.
+func (s *SecurityPolicy) Prefix() string {
+	return s.prefix
+}
+
+// Bind check that every operation has a policy and every policy
+// has an operation, and index the policies by the operation routes.
+// operations is the list of operations served by the API.
+// Return nil on success, else an error which list every operation
+// without policy, every policy without operation and every
+// idempotent policy for a safe method.
+func (s *SecurityPolicy) Bind(operations []Operation) error {
+	var uncovered, unknown, safe []string
+	routes := make(map[route]*Policy, len(operations))
+	ids := make(map[string]struct{}, len(operations))
+	for _, op := range operations {
+		ids[op.ID] = struct{}{}
+		p, ok := s.operations[op.ID]
+		if !ok {
+			uncovered = append(uncovered, fmt.Sprintf("%s (%s %s)", op.ID, op.Method, op.Path))
+			continue
+		}
+		if p.Idempotent && isSafeMethod(op.Method) {
+			safe = append(safe, fmt.Sprintf("%s (%s %s)", op.ID, op.Method, op.Path))
+		}
+		routes[route{method: op.Method, path: op.Path}] = p
+	}
+	for id := range s.operations {
+		if _, ok := ids[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	sort.Strings(uncovered)
+	sort.Strings(unknown)
+	sort.Strings(safe)
+	var errs []error
+	if len(uncovered) > 0 {
+		errs = append(errs, fmt.Errorf("operations without security policy: %s", strings.Join(uncovered, ", ")))
+	}
+	if len(unknown) > 0 {
+		errs = append(errs, fmt.Errorf("security policies without operation: %s", strings.Join(unknown, ", ")))
+	}
+	if len(safe) > 0 {
+		errs = append(errs, fmt.Errorf("idempotent policies for a safe method: %s", strings.Join(safe, ", ")))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	s.routes = routes
+	return nil
+}
+
+// isSafeMethod return true for the http methods which do not
+// modify data.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// Find return the policy for a route.
+// method is the http method.
+// path is the echo route relative to the API prefix.
+// Return the policy or nil if the route is unknown.
+func (s *SecurityPolicy) Find(method, path string) *Policy {
+	return s.routes[route{method: method, path: path}]
+}
+
+// PermissionMap generate the rbac permission map for the
+// operations with a permission.
+func (s *SecurityPolicy) PermissionMap() rbac_data.RBACMap {
+	builder := rbac_data.NewRBACMapBuilder()
+	for r, p := range s.routes {
+		if p.Permission == "" {
+			continue
+		}
+		builder.Add(rbac_data.Route(r.path), rbac_data.Method(r.method), rbac_data.RBACPermission(p.Permission))
+	}
+	return builder.Build()
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{([^}]+)\}`)
+
+// OperationsFromSpec return the operations defined into the
+// openapi specification, with the paths translated to echo routes;
+// a literal colon is escaped, as echo would read it as a parameter,
+// for instance '/domains:batch' is the route '/domains\:batch'.
+func OperationsFromSpec(spec *openapi3.T) []Operation {
+	if spec == nil || spec.Paths == nil {
+		return nil
+	}
+	operations := []Operation{}
+	for path, item := range spec.Paths.Map() {
+		route := strings.ReplaceAll(path, ":", "\\:")
+		route = pathParamRegexp.ReplaceAllString(route, ":$1")
+		for method, op := range item.Operations() {
+			operations = append(operations, Operation{
+				ID:     op.OperationID,
+				Method: strings.ToUpper(method),
+				Path:   route,
+			})
+		}
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].ID < operations[j].ID
+	})
+	return operations
+}
+
+// NewOperation is a helper to declare the operations which are
+// not in the openapi specification.
+func NewOperation(id, method, path string) Operation {
+	if id == "" {
+		panic("id is an empty string")
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		panic(fmt.Sprintf("method '%s' is not supported", method))
+	}
+	if !strings.HasPrefix(path, "/") {
+		panic("path must start with '/'")
+	}
+	return Operation{ID: id, Method: method, Path: path}
+}
@@ -0,0 +1,216 @@
+package policy
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	rbac_data "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/rbac-data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `---
+version: "1.0"
+prefix: "/api/idmsvc/v1"
+operations:
+  ListDomains:
+    identities: [user, service-account]
+    permission: "idmsvc:domains:list"
+    rate: read
+  ReadDomain:
+    identities: [user, service-account, system]
+    permission: "idmsvc:domains:read"
+    rate: read
+  RegisterDomain:
+    identities: [system]
+    rate: agent
+    audit: true
+    idempotent: true
+  GetOpenapi:
+    identities: [any]
+    rate: read
+`
+
+var testOperations = []Operation{
+	{ID: "ListDomains", Method: http.MethodGet, Path: "/domains"},
+	{ID: "ReadDomain", Method: http.MethodGet, Path: "/domains/:uuid"},
+	{ID: "RegisterDomain", Method: http.MethodPost, Path: "/domains"},
+	{ID: "GetOpenapi", Method: http.MethodGet, Path: "/openapi.json"},
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Given    string
+		Expected string
+	}{
+		{
+			Name:     "wrong yaml",
+			Given:    `???`,
+			Expected: "yaml: unmarshal errors:\n  line 1: cannot unmarshal !!str `???` into policy.policyFile",
+		},
+		{
+			Name:     "wrong version",
+			Given:    `version: "0.0"`,
+			Expected: "it was expected security policy version '1.0', but '0.0' was found",
+		},
+		{
+			Name:     "no prefix",
+			Given:    `version: "1.0"`,
+			Expected: "security policy prefix is an empty string",
+		},
+		{
+			Name:     "no operations",
+			Given:    "version: \"1.0\"\nprefix: /api",
+			Expected: "security policy has no operations",
+		},
+		{
+			Name:     "empty policy",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n",
+			Expected: "security policy for 'ListDomains': policy is empty",
+		},
+		{
+			Name:     "no identities",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    rate: read\n",
+			Expected: "security policy for 'ListDomains': identities is empty",
+		},
+		{
+			Name:     "unknown identity",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    identities: [robot]\n    rate: read\n",
+			Expected: "security policy for 'ListDomains': identity 'robot' is not supported",
+		},
+		{
+			Name:     "any combined",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    identities: [user, any]\n    rate: read\n",
+			Expected: "security policy for 'ListDomains': identity 'any' can not be combined",
+		},
+		{
+			Name:     "wrong permission",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    identities: [user]\n    permission: idmsvc:domains\n    rate: read\n",
+			Expected: "security policy for 'ListDomains': permission 'idmsvc:domains' does not match 'service:resource:verb'",
+		},
+		{
+			Name:     "permission for system",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    identities: [system]\n    permission: idmsvc:domains:list\n    rate: read\n",
+			Expected: "security policy for 'ListDomains': permission 'idmsvc:domains:list' is set but no user nor service-account identity is allowed",
+		},
+		{
+			Name:     "no rate",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    identities: [user]\n",
+			Expected: "security policy for 'ListDomains': rate is empty",
+		},
+		{
+			Name:     "unknown rate",
+			Given:    "version: \"1.0\"\nprefix: /api\noperations:\n  ListDomains:\n    identities: [user]\n    rate: burst\n",
+			Expected: "security policy for 'ListDomains': rate 'burst' is not supported",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		result, err := Load([]byte(testCase.Given))
+		assert.EqualError(t, err, testCase.Expected)
+		assert.Nil(t, result)
+	}
+}
+
+func TestBind(t *testing.T) {
+	securityPolicy, err := Load([]byte(testPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/idmsvc/v1", securityPolicy.Prefix())
+
+	// Uncovered operation and policy without operation
+	err = securityPolicy.Bind([]Operation{
+		testOperations[0],
+		testOperations[1],
+		testOperations[2],
+		{ID: "DeleteDomain", Method: http.MethodDelete, Path: "/domains/:uuid"},
+	})
+	assert.EqualError(t, err, "operations without security policy: DeleteDomain (DELETE /domains/:uuid)\n"+
+		"security policies without operation: GetOpenapi")
+	assert.Nil(t, securityPolicy.Find(http.MethodGet, "/domains"))
+
+	require.NoError(t, securityPolicy.Bind(testOperations))
+	p := securityPolicy.Find(http.MethodGet, "/domains")
+	require.NotNil(t, p)
+	assert.Equal(t, "idmsvc:domains:list", p.Permission)
+	assert.Equal(t, RateClassRead, p.Rate)
+	assert.False(t, p.Audit)
+	assert.False(t, p.Idempotent)
+	p = securityPolicy.Find(http.MethodPost, "/domains")
+	require.NotNil(t, p)
+	assert.True(t, p.Audit)
+	assert.True(t, p.Idempotent)
+	assert.Nil(t, securityPolicy.Find(http.MethodDelete, "/domains"))
+
+	// Idempotent policy for a safe method
+	securityPolicy, err = Load([]byte("version: \"1.0\"\nprefix: /api\noperations:\n" +
+		"  ListDomains:\n    identities: [user]\n    rate: read\n    idempotent: true\n"))
+	require.NoError(t, err)
+	err = securityPolicy.Bind(testOperations[:1])
+	assert.EqualError(t, err, "idempotent policies for a safe method: ListDomains (GET /domains)")
+}
+
+func TestAllows(t *testing.T) {
+	var p *Policy
+	assert.False(t, p.Allows(IdentityUser))
+
+	p = &Policy{Identities: []Identity{IdentityUser, IdentityServiceAccount}}
+	assert.True(t, p.Allows(IdentityUser))
+	assert.True(t, p.Allows(IdentityServiceAccount))
+	assert.False(t, p.Allows(IdentitySystem))
+
+	p = &Policy{Identities: []Identity{IdentityAny}}
+	assert.True(t, p.Allows(IdentityUser))
+	assert.True(t, p.Allows(IdentitySystem))
+}
+
+func TestPermissionMap(t *testing.T) {
+	securityPolicy, err := Load([]byte(testPolicy))
+	require.NoError(t, err)
+	require.NoError(t, securityPolicy.Bind(testOperations))
+
+	expected := rbac_data.NewRBACMapBuilder().
+		Add("/domains", http.MethodGet, "idmsvc:domains:list").
+		Add("/domains/:uuid", http.MethodGet, "idmsvc:domains:read").
+		Build()
+	assert.Equal(t, expected, securityPolicy.PermissionMap())
+}
+
+func TestOperationsFromSpec(t *testing.T) {
+	assert.Nil(t, OperationsFromSpec(nil))
+
+	spec := &openapi3.T{Paths: openapi3.NewPaths(
+		openapi3.WithPath("/domains", &openapi3.PathItem{
+			Get:  &openapi3.Operation{OperationID: "ListDomains"},
+			Post: &openapi3.Operation{OperationID: "RegisterDomain"},
+		}),
+		openapi3.WithPath("/domains:batch", &openapi3.PathItem{
+			Post: &openapi3.Operation{OperationID: "BatchDomains"},
+		}),
+		openapi3.WithPath("/host-conf/{inventory_id}/{fqdn}", &openapi3.PathItem{
+			Post: &openapi3.Operation{OperationID: "HostConf"},
+		}),
+	)}
+	assert.Equal(t, []Operation{
+		{ID: "BatchDomains", Method: http.MethodPost, Path: "/domains\\:batch"},
+		{ID: "HostConf", Method: http.MethodPost, Path: "/host-conf/:inventory_id/:fqdn"},
+		{ID: "ListDomains", Method: http.MethodGet, Path: "/domains"},
+		{ID: "RegisterDomain", Method: http.MethodPost, Path: "/domains"},
+	}, OperationsFromSpec(spec))
+}
+
+func TestNewOperation(t *testing.T) {
+	assert.PanicsWithValue(t, "id is an empty string", func() {
+		NewOperation("", http.MethodGet, "/openapi.json")
+	})
+	assert.PanicsWithValue(t, "method 'FETCH' is not supported", func() {
+		NewOperation("GetOpenapi", "FETCH", "/openapi.json")
+	})
+	assert.PanicsWithValue(t, "path must start with '/'", func() {
+		NewOperation("GetOpenapi", http.MethodGet, "openapi.json")
+	})
+	assert.Equal(t,
+		Operation{ID: "GetOpenapi", Method: http.MethodGet, Path: "/openapi.json"},
+		NewOperation("GetOpenapi", http.MethodGet, "/openapi.json"))
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echo_middleware "github.com/labstack/echo/v4/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+)
+
+const (
+	rateLimitScopeOrg      = "org"
+	rateLimitScopeIdentity = "identity"
+)
+
+// RateLimits hold the limits for a rate class.
+type RateLimits struct {
+	// Org is the limit shared by every identity of an organization.
+	Org ratelimit.Limit
+	// Identity is the limit for every user, service account or
+	// system of an organization.
+	Identity ratelimit.Limit
+}
+
+// RateLimitConfig hold the configuration for the rate limit middleware.
+type RateLimitConfig struct {
+	// Skipper function to skip for some request if necessary
+	Skipper echo_middleware.Skipper
+	// Limiter keep the token buckets.
+	Limiter ratelimit.Limiter
+	// Class return the rate class of the request; an empty string
+	// means the request is not limited.
+	Class func(c echo.Context) string
+	// Limits map the rate classes to their limits.
+	Limits map[string]RateLimits
+	// Metrics to count the throttled requests.
+	Metrics *metrics.Metrics
+}
+
+// RateLimitWithConfig create a middleware which limit the requests
+// with token buckets for the organization and for the identity of
+// the request, by rate class. It must be wired after the identity
+// is parsed. When a bucket is empty, the request is rejected with
+// 429 and the Retry-After header; when the limiter fails, the request
+// is not rejected.
+// cfg is the configuration for the middleware.
+// Return the initialized middleware or panic if some guard condition
+// is matched.
+func RateLimitWithConfig(cfg *RateLimitConfig) echo.MiddlewareFunc {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if cfg.Limiter == nil {
+		panic("'Limiter' is nil")
+	}
+	if cfg.Class == nil {
+		panic("'Class' is nil")
+	}
+	if cfg.Metrics == nil {
+		panic("'Metrics' is nil")
+	}
+	if cfg.Skipper == nil {
+		cfg.Skipper = echo_middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+			class := cfg.Class(c)
+			limits, ok := cfg.Limits[class]
+			if !ok {
+				return next(c)
+			}
+			cc, ok := c.(DomainContextInterface)
+			if !ok || cc.XRHID() == nil {
+				return next(c)
+			}
+			xrhid := cc.XRHID()
+			orgID := xrhid.Identity.OrgID
+			identityKey := class + "/" + xrhid.Identity.Type + "/" + orgID + "/" + header.GetPrincipal(xrhid)
+			orgKey := class + "/org/" + orgID
+
+			// No token is taken unless both buckets have one, so a
+			// throttled identity does not consume the tokens of its
+			// organization, nor the other way around
+			if err := takeRateLimit(c, cfg, class, []rateLimitBucket{
+				{scope: rateLimitScopeIdentity, Bucket: ratelimit.Bucket{Key: identityKey, Limit: limits.Identity}},
+				{scope: rateLimitScopeOrg, Bucket: ratelimit.Bucket{Key: orgKey, Limit: limits.Org}},
+			}); err != nil {
+				return err
+			}
+			return next(c)
+		}
+	}
+}
+
+// rateLimitBucket is a bucket checked by the middleware, with the
+// scope reported when it throttles the request.
+type rateLimitBucket struct {
+	ratelimit.Bucket
+	scope string
+}
+
+// takeRateLimit take a token from every bucket.
+// Return nil when the request is allowed, else the http error.
+func takeRateLimit(c echo.Context, cfg *RateLimitConfig, class string, items []rateLimitBucket) error {
+	buckets := make([]ratelimit.Bucket, 0, len(items))
+	for i := range items {
+		buckets = append(buckets, items[i].Bucket)
+	}
+	ctx := c.Request().Context()
+	logger := app_context.LogFromCtx(ctx)
+	denied, retryAfter, err := cfg.Limiter.Take(ctx, buckets)
+	if err != nil {
+		logger.Warn("rate limit could not be checked", "error", err.Error())
+		cfg.Metrics.RateLimitErrors.Inc()
+		return nil
+	}
+	if denied < 0 {
+		return nil
+	}
+	scope := items[denied].scope
+	cfg.Metrics.RateLimitThrottled.WithLabelValues(class, scope).Inc()
+	logger.Info("rate limit exceeded", "class", class, "scope", scope)
+	c.Response().Header().Set(headerRetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+	return internal_errors.ErrRateLimited.New(nil, "Too many requests")
+}
+
+// retryAfterSeconds round up the duration to seconds, with a
+// minimum of one second.
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
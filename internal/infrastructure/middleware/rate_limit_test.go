@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	api_builder "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	mock_ratelimit "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	testRateLimitOrg      = ratelimit.Limit{Rate: 10, Burst: 20}
+	testRateLimitIdentity = ratelimit.Limit{Rate: 1, Burst: 5}
+)
+
+const (
+	testRateLimitOrgKey      = "agent/org/12345"
+	testRateLimitIdentityKey = "agent/System/12345/6f324116-b3d2-11ed-8a37-482ae3863d30"
+)
+
+var testRateLimitBuckets = []ratelimit.Bucket{
+	{Key: testRateLimitIdentityKey, Limit: testRateLimitIdentity},
+	{Key: testRateLimitOrgKey, Limit: testRateLimitOrg},
+}
+
+func helperRateLimitConfig(t *testing.T) (*RateLimitConfig, *mock_ratelimit.Limiter) {
+	limiter := mock_ratelimit.NewLimiter(t)
+	return &RateLimitConfig{
+		Limiter: limiter,
+		Class: func(c echo.Context) string {
+			if c.Path() == "/domains" {
+				return "agent"
+			}
+			return ""
+		},
+		Limits: map[string]RateLimits{
+			"agent": {Org: testRateLimitOrg, Identity: testRateLimitIdentity},
+		},
+		Metrics: metrics.NewMetrics(prometheus.NewRegistry()),
+	}, limiter
+}
+
+func helperRateLimitRequest(cfg *RateLimitConfig, path string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(ContextLogConfig(&LogConfig{}))
+	e.Use(CreateContext())
+	e.Use(ParseXRHIDMiddlewareWithConfig(&ParseXRHIDMiddlewareConfig{}))
+	e.Use(RateLimitWithConfig(cfg))
+	e.POST(path, func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	xrhid := api_builder.NewSystemXRHID().
+		WithOrgID("12345").
+		WithCommonName("6f324116-b3d2-11ed-8a37-482ae3863d30").
+		Build()
+	req := httptest.NewRequest(http.MethodPost, path, http.NoBody)
+	req.Header.Set(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitWithConfigGuards(t *testing.T) {
+	cfg, _ := helperRateLimitConfig(t)
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		RateLimitWithConfig(nil)
+	})
+	assert.PanicsWithValue(t, "'Limiter' is nil", func() {
+		RateLimitWithConfig(&RateLimitConfig{})
+	})
+	assert.PanicsWithValue(t, "'Class' is nil", func() {
+		RateLimitWithConfig(&RateLimitConfig{Limiter: cfg.Limiter})
+	})
+	assert.PanicsWithValue(t, "'Metrics' is nil", func() {
+		RateLimitWithConfig(&RateLimitConfig{Limiter: cfg.Limiter, Class: cfg.Class})
+	})
+	assert.NotPanics(t, func() {
+		RateLimitWithConfig(cfg)
+	})
+}
+
+func TestRateLimitWithConfigAllowed(t *testing.T) {
+	cfg, limiter := helperRateLimitConfig(t)
+	limiter.On("Take", mock.Anything, testRateLimitBuckets).Return(-1, time.Duration(0), nil)
+
+	rec := helperRateLimitRequest(cfg, "/domains")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(headerRetryAfter))
+}
+
+func TestRateLimitWithConfigNotLimited(t *testing.T) {
+	cfg, _ := helperRateLimitConfig(t)
+
+	// No rate class for the route
+	rec := helperRateLimitRequest(cfg, "/signing_keys")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Skipped route
+	cfg.Skipper = func(c echo.Context) bool { return true }
+	rec = helperRateLimitRequest(cfg, "/domains")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRateLimitWithConfigThrottled(t *testing.T) {
+	// Identity throttled
+	cfg, limiter := helperRateLimitConfig(t)
+	limiter.On("Take", mock.Anything, testRateLimitBuckets).Return(0, 1500*time.Millisecond, nil)
+
+	rec := helperRateLimitRequest(cfg, "/domains")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(headerRetryAfter))
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.RateLimitThrottled.WithLabelValues("agent", rateLimitScopeIdentity)))
+
+	// Organization throttled
+	cfg, limiter = helperRateLimitConfig(t)
+	limiter.On("Take", mock.Anything, testRateLimitBuckets).Return(1, 10*time.Millisecond, nil)
+
+	rec = helperRateLimitRequest(cfg, "/domains")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get(headerRetryAfter))
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.RateLimitThrottled.WithLabelValues("agent", rateLimitScopeOrg)))
+}
+
+func TestRateLimitWithConfigLimiterError(t *testing.T) {
+	// The request is not rejected when the limiter fails
+	cfg, limiter := helperRateLimitConfig(t)
+	limiter.On("Take", mock.Anything, testRateLimitBuckets).Return(-1, time.Duration(0), fmt.Errorf("connection refused"))
+
+	rec := helperRateLimitRequest(cfg, "/domains")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, float64(1), testutil.ToFloat64(cfg.Metrics.RateLimitErrors))
+}
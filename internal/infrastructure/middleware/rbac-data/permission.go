@@ -124,6 +124,9 @@ const (
 	RbacVerbUnlink    RBACVerb = "unlink"
 	RbacVerbOrder     RBACVerb = "order"
 	RbacVerbExecute   RBACVerb = "execute"
+	// RbacVerbApprove is the decision about the domains pending
+	// approval; it is granted for the organization.
+	RbacVerbApprove RBACVerb = "approve"
 )
 
 type RbacVerbValidator func(v RBACVerb) RBACVerb
@@ -142,6 +145,7 @@ func DefaultRbacVerbValidator(v RBACVerb) RBACVerb {
 	case RbacVerbUnlink:
 	case RbacVerbOrder:
 	case RbacVerbExecute:
+	case RbacVerbApprove:
 	default:
 		panic(fmt.Sprintf("Verb '%s' is not supported", v))
 	}
@@ -168,6 +172,27 @@ func NewRbacPermission(service RBACService, resource RBACResource, verb RBACVerb
 	return RBACPermission(p)
 }
 
+// Resource return the resource of the permission, or
+// RBACResourceUndefined if it does not follow the
+// 'service:resource:verb' format.
+func (p RBACPermission) Resource() RBACResource {
+	parts := strings.Split(string(p), ":")
+	if len(parts) != 3 {
+		return RBACResourceUndefined
+	}
+	return RBACResource(parts[1])
+}
+
+// Verb return the verb of the permission, or RbacVerbUndefined
+// if it does not follow the 'service:resource:verb' format.
+func (p RBACPermission) Verb() RBACVerb {
+	parts := strings.Split(string(p), ":")
+	if len(parts) != 3 {
+		return RbacVerbUndefined
+	}
+	return RBACVerb(parts[2])
+}
+
 // RBACMap mapping type
 type RBACMap map[Route]map[Method]RBACPermission
 
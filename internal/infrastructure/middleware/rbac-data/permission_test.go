@@ -88,6 +88,7 @@ func TestDefaultRbacVerbValidator(t *testing.T) {
 		RbacVerbUnlink,
 		RbacVerbOrder,
 		RbacVerbExecute,
+		RbacVerbApprove,
 	}
 	for _, verb := range validverbs {
 		var result RBACVerb
@@ -154,6 +155,20 @@ func TestNewRbacPermission(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestRBACPermissionResource(t *testing.T) {
+	assert.Equal(t, RBACResource("domains"), RBACPermission("idmsvc:domains:read").Resource())
+	assert.Equal(t, RBACResource("webhooks"), RBACPermission("idmsvc:webhooks:update").Resource())
+	assert.Equal(t, RBACResourceUndefined, RBACPermission("").Resource())
+	assert.Equal(t, RBACResourceUndefined, RBACPermission("idmsvc:domains").Resource())
+}
+
+func TestRBACPermissionVerb(t *testing.T) {
+	assert.Equal(t, RbacVerbRead, RBACPermission("idmsvc:domains:read").Verb())
+	assert.Equal(t, RbacVerbApprove, RBACPermission("idmsvc:domains:approve").Verb())
+	assert.Equal(t, RbacVerbUndefined, RBACPermission("").Verb())
+	assert.Equal(t, RbacVerbUndefined, RBACPermission("idmsvc:domains").Verb())
+}
+
 func TestGetPermissionGuards(t *testing.T) {
 	var err error
 	service := RBACService("idmsvc")
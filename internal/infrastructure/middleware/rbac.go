@@ -1,19 +1,45 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
+	"slices"
+	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	echo_middleware "github.com/labstack/echo/v4/middleware"
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	rbac_data "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/rbac-data"
-	rbac_client "github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 )
 
+// rbacDomainParam is the route parameter with the domain id, used
+// to check the resource-level permissions.
+const rbacDomainParam = "uuid"
+
+// rbacDomainResource is the resource of the permissions checked for
+// a domain; the other resources, such as the webhooks, are checked
+// for the organization.
+const rbacDomainResource = rbac_data.RBACResource("domains")
+
+// rbacOrgVerbs are the verbs of the domain permissions which are
+// checked for the organization, so a permission restricted to some
+// domains does not grant them, even for the requested domain.
+var rbacOrgVerbs = []rbac_data.RBACVerb{rbac_data.RbacVerbApprove}
+
+// headerRetryAfter is the header which indicate when to retry
+// a request that failed with 503.
+const headerRetryAfter = "Retry-After"
+
 // RBACConfig hold the skipper, route prefix, the rbac permissions
-// mapping for each authorized public route, and the client to
-// reach out the rbac micro-service.
+// mapping for each authorized public route, and the authorizer
+// which check them.
 type RBACConfig struct {
 	// Skipper function to skip for some request if necessary
 	Skipper echo_middleware.Skipper
@@ -21,12 +47,16 @@ type RBACConfig struct {
 	Prefix string
 	// PermissionMap has the mapping between {route,method}=>permission
 	PermissionMap rbac_data.RBACMap
-	// Client for rbac access
-	Client rbac_client.Rbac
+	// Client is the authorizer which check the permissions
+	Client authz.Authorizer
+	// Metrics count the denied requests; it can be nil
+	Metrics *metrics.Metrics
 }
 
 // RBACWithConfig create a middleware for authorizing requests by using
-// the intgration with rbac micro-service
+// the configured authorizer. The routes with an uuid parameter are
+// checked for that domain, and the rest for the domains collection
+// of the organization.
 // rbacConfig provide the skipper, prefix, permission map and client
 // for the configuration.
 // Return the initialized middleware or panic if some guard condition
@@ -47,7 +77,8 @@ func RBACWithConfig(rbacConfig *RBACConfig) echo.MiddlewareFunc {
 				err        error
 				permission rbac_data.RBACPermission
 				xrhid      string
-				isAllowed  bool
+				subject    *authz.Subject
+				allowed    bool
 			)
 			logger := app_context.LogFromCtx(c.Request().Context())
 
@@ -68,16 +99,33 @@ func RBACWithConfig(rbacConfig *RBACConfig) echo.MiddlewareFunc {
 			// This if statement is only possible if no enforce middleware
 			// is executed for the public API.
 			if xrhid = c.Request().Header.Get(header.HeaderXRHID); xrhid == "" {
-				return echo.NewHTTPError(http.StatusBadRequest, header.HeaderXRHID+" is missed")
+				return internal_errors.ErrIdentityMissing.New(nil, header.HeaderXRHID+" is missed")
+			}
+
+			if subject, err = authz.NewSubject(xrhid); err != nil {
+				logger.Error(err.Error())
+				return internal_errors.ErrIdentityInvalid.New(err, header.HeaderXRHID+" is invalid")
 			}
 
-			// Get User permissions
-			context := c.Request().Context()
-			if isAllowed, err = rbacConfig.Client.IsAllowed(context, xrhid, string(permission)); !isAllowed {
-				if err != nil {
-					return err
+			// Check the permission for the requested domain, or for
+			// the domains of the organization
+			resource := &authz.Resource{Type: authz.ResourceTypeDomain}
+			if domainID := c.Param(rbacDomainParam); domainID != "" && permission.Resource() == rbacDomainResource {
+				resource.ID = canonicalDomainID(domainID)
+			}
+			if slices.Contains(rbacOrgVerbs, permission.Verb()) && permission.Resource() == rbacDomainResource {
+				allowed, err = checkOrganization(c.Request().Context(), rbacConfig.Client, subject, permission)
+			} else {
+				allowed, err = rbacConfig.Client.Check(c.Request().Context(), subject, string(permission), resource)
+			}
+			if err != nil {
+				return AuthorizerError(c, err)
+			}
+			if !allowed {
+				if rbacConfig.Metrics != nil {
+					rbacConfig.Metrics.RbacDenials.WithLabelValues(string(permission)).Inc()
 				}
-				logger.Error("unauthorized", "permission", permission)
+				logger.Error("unauthorized", "permission", permission, "uuid", resource.ID)
 				return echo.NewHTTPError(http.StatusUnauthorized, "Unauthorized")
 			}
 
@@ -86,3 +134,40 @@ func RBACWithConfig(rbacConfig *RBACConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// checkOrganization return true when the permission is granted for
+// every domain into the organization of subject, so it is not
+// granted by a role restricted to some domains.
+func checkOrganization(ctx context.Context, client authz.Authorizer, subject *authz.Subject, permission rbac_data.RBACPermission) (bool, error) {
+	resources, err := client.LookupResources(ctx, subject, string(permission), authz.ResourceTypeDomain)
+	if err != nil {
+		return false, err
+	}
+	return resources != nil && resources.All, nil
+}
+
+// AuthorizerError translate an error returned by the authorizer
+// into the http error; when the authorization backend is unavailable
+// it is a 503 error, with the Retry-After header if it is known.
+func AuthorizerError(c echo.Context, err error) error {
+	var unavailable *authz.UnavailableError
+	if !errors.As(err, &unavailable) {
+		return err
+	}
+	app_context.LogFromCtx(c.Request().Context()).Warn(err.Error())
+	if unavailable.RetryAfter > 0 {
+		seconds := int(math.Ceil(unavailable.RetryAfter.Seconds()))
+		c.Response().Header().Set(headerRetryAfter, strconv.Itoa(seconds))
+	}
+	return internal_errors.ErrAuthzUnavailable.New(err, "Authorization service is unavailable")
+}
+
+// canonicalDomainID return the domain id in the form used by the
+// authorizers, or the given value if it is not a uuid.
+func canonicalDomainID(value string) string {
+	domainID, err := uuid.Parse(value)
+	if err != nil {
+		return value
+	}
+	return domainID.String()
+}
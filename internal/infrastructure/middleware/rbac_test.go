@@ -1,16 +1,23 @@
 package middleware
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	echo_middleware "github.com/labstack/echo/v4/middleware"
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	rbac_data "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/rbac-data"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 	api_builder "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
-	client_rbac "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/rbac"
+	client_authz "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/authz"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -29,29 +36,40 @@ func helperRbacSetupEcho(config *RBACConfig, method, path string, status int) *e
 	return e
 }
 
+func helperRbacSubject(xrhid *identity.XRHID) *authz.Subject {
+	return &authz.Subject{
+		OrgID:     xrhid.Identity.OrgID,
+		Principal: xrhid.Identity.User.UserID,
+		XRHID:     header.EncodeXRHID(xrhid),
+	}
+}
+
 func helperRbacCreateMapping() rbac_data.RBACMap {
 	service := rbac_data.RBACService("idmsvc")
 	resourceToken := rbac_data.RBACResource("token")
 	resourceDomains := rbac_data.RBACResource("domains")
-	// This map fit the one at internal/infrastructure/router/rbac.yaml
+	resourceWebhooks := rbac_data.RBACResource("webhooks")
+	// This map fit the permissions at internal/infrastructure/router/policy.yaml
 	data := rbac_data.NewRBACMapBuilder().
 		Add("/domains/token", http.MethodPost, rbac_data.NewRbacPermission(service, resourceToken, rbac_data.RbacVerbCreate)).
 		Add("/domains", http.MethodGet, rbac_data.NewRbacPermission(service, resourceDomains, rbac_data.RbacVerbRead)).
 		Add("/domains/:uuid", http.MethodGet, rbac_data.NewRbacPermission(service, resourceDomains, rbac_data.RbacVerbRead)).
 		Add("/domains/:uuid", http.MethodPatch, rbac_data.NewRbacPermission(service, resourceDomains, rbac_data.RbacVerbUpdate)).
 		Add("/domains/:uuid", http.MethodDelete, rbac_data.NewRbacPermission(service, resourceDomains, rbac_data.RbacVerbDelete)).
+		Add("/domains/:uuid/approve", http.MethodPost, rbac_data.NewRbacPermission(service, resourceDomains, rbac_data.RbacVerbApprove)).
+		Add("/webhooks/:uuid", http.MethodGet, rbac_data.NewRbacPermission(service, resourceWebhooks, rbac_data.RbacVerbRead)).
 		Build()
 	return data
 }
 
 func helperRbacSetup(t *testing.T, prefix string, skipper echo_middleware.Skipper) (RBACConfig, identity.XRHID, bool) {
 	rbacMap := helperRbacCreateMapping()
-	rbacClientMock := client_rbac.NewRbac(t)
+	authorizerMock := client_authz.NewAuthorizer(t)
 	rbacConfig := RBACConfig{
 		Skipper:       skipper,
 		Prefix:        prefix,
 		PermissionMap: rbacMap,
-		Client:        rbacClientMock,
+		Client:        authorizerMock,
 	}
 	xrhid := api_builder.NewUserXRHID().
 		WithOrgID("12345").
@@ -111,11 +129,11 @@ func TestRBACWithConfigSkipper(t *testing.T) {
 func TestRBACWithConfigFailGetPermission(t *testing.T) {
 	prefix := "/api/idmsvc/v1"
 	rbacMap := helperRbacCreateMapping()
-	rbacClientMock := client_rbac.NewRbac(t)
+	authorizerMock := client_authz.NewAuthorizer(t)
 	rbacConfig := RBACConfig{
 		Prefix:        prefix,
 		PermissionMap: rbacMap,
-		Client:        rbacClientMock,
+		Client:        authorizerMock,
 	}
 	xrhid := api_builder.NewUserXRHID().
 		WithOrgID("12345").
@@ -135,11 +153,11 @@ func TestRBACWithConfigFailGetPermission(t *testing.T) {
 func TestRBACWithConfigFailRbacClient(t *testing.T) {
 	prefix := "/api/idmsvc/v1"
 	rbacMap := helperRbacCreateMapping()
-	rbacClientMock := client_rbac.NewRbac(t)
+	authorizerMock := client_authz.NewAuthorizer(t)
 	rbacConfig := RBACConfig{
 		Prefix:        prefix,
 		PermissionMap: rbacMap,
-		Client:        rbacClientMock,
+		Client:        authorizerMock,
 	}
 	xrhid := api_builder.NewUserXRHID().
 		WithOrgID("12345").
@@ -153,7 +171,7 @@ func TestRBACWithConfigFailRbacClient(t *testing.T) {
 	req := httptest.NewRequest(method, "http://localhost:8000"+path, http.NoBody)
 	req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
 	permission := rbac_data.NewRbacPermission("idmsvc", "domains", rbac_data.RbacVerbRead)
-	rbacClientMock.On("IsAllowed", mock.Anything, string(header.EncodeXRHID(&xrhid)), string(permission)).Return(false, echo.NewHTTPError(http.StatusNotFound))
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain}).Return(false, echo.NewHTTPError(http.StatusNotFound))
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, statusExpected, rec.Code)
 }
@@ -161,11 +179,13 @@ func TestRBACWithConfigFailRbacClient(t *testing.T) {
 func TestRBACWithConfigUnauthorized(t *testing.T) {
 	prefix := "/api/idmsvc/v1"
 	rbacMap := helperRbacCreateMapping()
-	rbacClientMock := client_rbac.NewRbac(t)
+	authorizerMock := client_authz.NewAuthorizer(t)
+	m := metrics.NewMetrics(prometheus.NewRegistry())
 	rbacConfig := RBACConfig{
 		Prefix:        prefix,
 		PermissionMap: rbacMap,
-		Client:        rbacClientMock,
+		Client:        authorizerMock,
+		Metrics:       m,
 	}
 	xrhid := api_builder.NewUserXRHID().
 		WithOrgID("12345").
@@ -179,19 +199,20 @@ func TestRBACWithConfigUnauthorized(t *testing.T) {
 	req := httptest.NewRequest(method, "http://localhost:8000"+path, http.NoBody)
 	req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
 	permission := rbac_data.NewRbacPermission("idmsvc", "domains", rbac_data.RbacVerbRead)
-	rbacClientMock.On("IsAllowed", mock.Anything, string(header.EncodeXRHID(&xrhid)), string(permission)).Return(false, nil)
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain}).Return(false, nil)
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, statusExpected, rec.Code)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.RbacDenials.WithLabelValues(string(permission))))
 }
 
 func TestRBACWithConfigGrantedPermission(t *testing.T) {
 	prefix := "/api/idmsvc/v1"
 	rbacMap := helperRbacCreateMapping()
-	rbacClientMock := client_rbac.NewRbac(t)
+	authorizerMock := client_authz.NewAuthorizer(t)
 	rbacConfig := RBACConfig{
 		Prefix:        prefix,
 		PermissionMap: rbacMap,
-		Client:        rbacClientMock,
+		Client:        authorizerMock,
 	}
 	xrhid := api_builder.NewUserXRHID().
 		WithOrgID("12345").
@@ -205,7 +226,7 @@ func TestRBACWithConfigGrantedPermission(t *testing.T) {
 	req := httptest.NewRequest(method, "http://localhost:8000"+path, http.NoBody)
 	req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
 	permission := rbac_data.NewRbacPermission("idmsvc", "domains", rbac_data.RbacVerbRead)
-	rbacClientMock.On("IsAllowed", mock.Anything, string(header.EncodeXRHID(&xrhid)), string(permission)).Return(true, nil)
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain}).Return(true, nil)
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, statusExpected, rec.Code)
 }
@@ -213,11 +234,11 @@ func TestRBACWithConfigGrantedPermission(t *testing.T) {
 func TestRBACWithConfigFailGettingUserPermissions(t *testing.T) {
 	prefix := "/api/idmsvc/v1"
 	rbacMap := helperRbacCreateMapping()
-	rbacClientMock := client_rbac.NewRbac(t)
+	authorizerMock := client_authz.NewAuthorizer(t)
 	rbacConfig := RBACConfig{
 		Prefix:        prefix,
 		PermissionMap: rbacMap,
-		Client:        rbacClientMock,
+		Client:        authorizerMock,
 	}
 	xrhid := api_builder.NewUserXRHID().
 		WithOrgID("12345").
@@ -233,3 +254,173 @@ func TestRBACWithConfigFailGettingUserPermissions(t *testing.T) {
 	e.ServeHTTP(rec, req)
 	assert.Equal(t, statusExpected, rec.Code)
 }
+
+func TestRBACWithConfigDomainPermission(t *testing.T) {
+	const (
+		allowedDomain = "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"
+		otherDomain   = "0b1f4a52-7a5c-4b6e-8c3e-5f5d2a9e1c77"
+	)
+	prefix := "/api/idmsvc/v1"
+	authorizerMock := client_authz.NewAuthorizer(t)
+	rbacConfig := RBACConfig{
+		Prefix:        prefix,
+		PermissionMap: helperRbacCreateMapping(),
+		Client:        authorizerMock,
+	}
+	xrhid := api_builder.NewUserXRHID().
+		WithOrgID("12345").
+		WithUserID("test").
+		Build()
+	permission := rbac_data.NewRbacPermission("idmsvc", "domains", rbac_data.RbacVerbUpdate)
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain, ID: allowedDomain}).Return(true, nil)
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain, ID: otherDomain}).Return(false, nil)
+
+	e := echo.New()
+	e.Use(ContextLogConfig(&LogConfig{}))
+	e.Use(CreateContext())
+	e.Use(ParseXRHIDMiddlewareWithConfig(&ParseXRHIDMiddlewareConfig{}))
+	e.Use(EnforceIdentityWithConfig(&IdentityConfig{}))
+	e.Use(RBACWithConfig(&rbacConfig))
+	e.PATCH(prefix+"/domains/:uuid", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	type TestCase struct {
+		Name     string
+		Given    string
+		Expected int
+	}
+	testCases := []TestCase{
+		{Name: "allowed domain", Given: allowedDomain, Expected: http.StatusOK},
+		{Name: "allowed domain in upper case", Given: strings.ToUpper(allowedDomain), Expected: http.StatusOK},
+		{Name: "other domain", Given: otherDomain, Expected: http.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "http://localhost:8000"+prefix+"/domains/"+testCase.Given, http.NoBody)
+		req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, testCase.Expected, rec.Code)
+	}
+}
+
+func TestRBACWithConfigApprovePermission(t *testing.T) {
+	const domainID = "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"
+	prefix := "/api/idmsvc/v1"
+	xrhid := api_builder.NewUserXRHID().
+		WithOrgID("12345").
+		WithUserID("test").
+		Build()
+	// The approval is checked for the organization, so a permission
+	// restricted to the requested domain does not grant it
+	permission := rbac_data.NewRbacPermission("idmsvc", "domains", rbac_data.RbacVerbApprove)
+
+	type TestCase struct {
+		Name     string
+		Given    *authz.ResourceSet
+		Expected int
+	}
+	testCases := []TestCase{
+		{Name: "granted for the organization", Given: &authz.ResourceSet{All: true}, Expected: http.StatusOK},
+		{Name: "granted for the domain", Given: &authz.ResourceSet{IDs: []string{domainID}}, Expected: http.StatusUnauthorized},
+		{Name: "not granted", Given: &authz.ResourceSet{}, Expected: http.StatusUnauthorized},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		authorizerMock := client_authz.NewAuthorizer(t)
+		rbacConfig := RBACConfig{
+			Prefix:        prefix,
+			PermissionMap: helperRbacCreateMapping(),
+			Client:        authorizerMock,
+		}
+		authorizerMock.On("LookupResources", mock.Anything, helperRbacSubject(&xrhid), string(permission), authz.ResourceTypeDomain).Return(testCase.Given, nil)
+		e := helperRbacSetupEcho(&rbacConfig, http.MethodPost, prefix+"/domains/:uuid/approve", http.StatusOK)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:8000"+prefix+"/domains/"+domainID+"/approve", http.NoBody)
+		req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, testCase.Expected, rec.Code)
+	}
+}
+
+func TestRBACWithConfigWebhookPermission(t *testing.T) {
+	prefix := "/api/idmsvc/v1"
+	authorizerMock := client_authz.NewAuthorizer(t)
+	rbacConfig := RBACConfig{
+		Prefix:        prefix,
+		PermissionMap: helperRbacCreateMapping(),
+		Client:        authorizerMock,
+	}
+	xrhid := api_builder.NewUserXRHID().
+		WithOrgID("12345").
+		WithUserID("test").
+		Build()
+	// The webhooks are checked for the organization, so the uuid
+	// of the route is not taken as a domain
+	permission := rbac_data.NewRbacPermission("idmsvc", "webhooks", rbac_data.RbacVerbRead)
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain}).Return(true, nil)
+
+	e := echo.New()
+	e.Use(ContextLogConfig(&LogConfig{}))
+	e.Use(CreateContext())
+	e.Use(ParseXRHIDMiddlewareWithConfig(&ParseXRHIDMiddlewareConfig{}))
+	e.Use(EnforceIdentityWithConfig(&IdentityConfig{}))
+	e.Use(RBACWithConfig(&rbacConfig))
+	e.GET(prefix+"/webhooks/:uuid", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:8000"+prefix+"/webhooks/5b5fb0a6-26f8-4a76-8c5f-1d3a06a6a8b1", http.NoBody)
+	req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRBACWithConfigInvalidIdentity(t *testing.T) {
+	prefix := "/api/idmsvc/v1"
+	rbacConfig := RBACConfig{
+		Prefix:        prefix,
+		PermissionMap: helperRbacCreateMapping(),
+		Client:        client_authz.NewAuthorizer(t),
+	}
+	e := echo.New()
+	e.Use(ContextLogConfig(&LogConfig{}))
+	e.Use(RBACWithConfig(&rbacConfig))
+	e.GET(prefix+"/domains", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:8000"+prefix+"/domains", http.NoBody)
+	req.Header.Add(header.HeaderXRHID, "not base64")
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRBACWithConfigUnavailable(t *testing.T) {
+	prefix := "/api/idmsvc/v1"
+	rbacConfig, xrhid, _ := helperRbacSetup(t, prefix, nil)
+	authorizerMock := rbacConfig.Client.(*client_authz.Authorizer)
+	permission := rbac_data.NewRbacPermission("idmsvc", "domains", rbac_data.RbacVerbRead)
+	e := helperRbacSetupEcho(&rbacConfig, http.MethodGet, prefix+"/domains", http.StatusOK)
+
+	// Retry-After is rounded up to seconds
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain}).
+		Return(false, &authz.UnavailableError{RetryAfter: 1500 * time.Millisecond}).Once()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:8000"+prefix+"/domains", http.NoBody)
+	req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// No Retry-After when it is unknown
+	authorizerMock.On("Check", mock.Anything, helperRbacSubject(&xrhid), string(permission), &authz.Resource{Type: authz.ResourceTypeDomain}).
+		Return(false, fmt.Errorf("wrapped: %w", &authz.UnavailableError{})).Once()
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "http://localhost:8000"+prefix+"/domains", http.NoBody)
+	req.Header.Add(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "", rec.Header().Get("Retry-After"))
+}
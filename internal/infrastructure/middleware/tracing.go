@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/logger"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingConfig hold the configuration for the tracing middleware.
+type TracingConfig struct {
+	// Skipper function to skip the requests which are not traced
+	Skipper middleware.Skipper
+}
+
+// TracingWithConfig create a middleware which start a server span
+// for every request which is not skipped, child of the trace
+// context received in the request headers. The span and the
+// request logger with the trace ids are stored in the request
+// context. It must be added after ContextLogConfig.
+// cfg is the configuration for the middleware.
+// Return the initialized middleware or panic if cfg is nil.
+func TracingWithConfig(cfg *TracingConfig) echo.MiddlewareFunc {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if cfg.Skipper == nil {
+		cfg.Skipper = middleware.DefaultSkipper
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.Skipper(c) {
+				return next(c)
+			}
+
+			req := c.Request()
+			ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+			ctx, span := tracing.Tracer().Start(ctx, req.Method+" "+c.Path(),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", req.Method),
+					attribute.String("http.route", c.Path()),
+					attribute.String("url.path", req.URL.Path),
+				),
+			)
+			defer span.End()
+
+			// Most of the handlers log without the context, so the
+			// trace ids are added to the request logger
+			if sc := span.SpanContext(); sc.IsValid() {
+				log := app_context.LogFromCtx(ctx).With(
+					slog.String(logger.TraceIDKey, sc.TraceID().String()),
+					slog.String(logger.SpanIDKey, sc.SpanID().String()),
+				)
+				ctx = app_context.CtxWithLog(ctx, log)
+			}
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+
+			status := auditStatus(c, err)
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if err != nil {
+				span.RecordError(err)
+			}
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func helperTracingRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func helperTracingRequest(cfg *TracingConfig, h echo.HandlerFunc, headers map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := app_context.CtxWithLog(c.Request().Context(), logger)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	})
+	e.Use(TracingWithConfig(cfg))
+	e.GET("/domains/:uuid", h)
+
+	req := httptest.NewRequest(http.MethodGet, "/domains/1", http.NoBody)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return &buf
+}
+
+func TestTracingWithConfig(t *testing.T) {
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		TracingWithConfig(nil)
+	})
+
+	recorder := helperTracingRecorder(t)
+
+	// Success request, continuing the trace of the caller; the
+	// request logger has the trace ids
+	parent := "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01"
+	buf := helperTracingRequest(&TracingConfig{}, func(c echo.Context) error {
+		app_context.LogFromCtx(c.Request().Context()).Info("handler")
+		return c.NoContent(http.StatusNoContent)
+	}, map[string]string{"traceparent": parent})
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "GET /domains/:uuid", span.Name())
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", span.SpanContext().TraceID().String())
+	assert.Equal(t, "0102030405060708", span.Parent().SpanID().String())
+	assert.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", http.StatusNoContent))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	record := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, span.SpanContext().TraceID().String(), record["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record["span_id"])
+
+	// Request with a server error
+	helperTracingRequest(&TracingConfig{}, func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadGateway, "bad gateway")
+	}, nil)
+	spans = recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Contains(t, spans[1].Attributes(), attribute.Int("http.response.status_code", http.StatusBadGateway))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+	// Skipped request
+	helperTracingRequest(&TracingConfig{
+		Skipper: func(c echo.Context) bool { return true },
+	}, func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, nil)
+	assert.Len(t, recorder.Ended(), 2)
+}
@@ -280,7 +280,7 @@ func RequestResponseValidatorWithConfig(config *RequestResponseValidatorConfig)
 					requestValidationInput,
 				)
 				if err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+					return internal_errors.ErrRequestInvalid.New(err, "%s", err.Error())
 				}
 			}
 
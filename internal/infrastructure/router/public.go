@@ -4,6 +4,9 @@ import (
 	_ "embed"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,41 +17,21 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/handler"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
-	rbac_data "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/rbac-data"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/policy"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
 	"github.com/podengo-project/idmsvc-backend/internal/metrics"
-	"github.com/podengo-project/idmsvc-backend/internal/usecase/client/rbac"
+	usecase_authz "github.com/podengo-project/idmsvc-backend/internal/usecase/client/authz"
+	usecase_ratelimit "github.com/podengo-project/idmsvc-backend/internal/usecase/ratelimit"
 )
 
-// skipperValidate is an alias to represent skipper for API validation middleware
-// FIXME Once the openapi specification is propery defined, remove this skipper
-// to validate every request
-var skipperValidate echo_middleware.Skipper = skipperUserPredicate
+//go:embed policy.yaml
+var securityPolicyBytes []byte
 
-type enforceRoute struct {
-	Method string
-	Path   string
-}
-
-var userEnforceRoutes = []enforceRoute{
-	{"POST", "/api/idmsvc/v1/domains/token"},
-	{"GET", "/api/idmsvc/v1/domains"},
-	{"PATCH", "/api/idmsvc/v1/domains/:uuid"},
-	{"DELETE", "/api/idmsvc/v1/domains/:uuid"},
-}
-
-var systemEnforceRoutes = []enforceRoute{
-	{"POST", "/api/idmsvc/v1/domains"},
-	{"PUT", "/api/idmsvc/v1/domains/:uuid"},
-	{"POST", "/api/idmsvc/v1/host-conf/:inventory_id/:fqdn"},
-	{"GET", "/api/idmsvc/v1/signing_keys"},
-}
-
-var mixedEnforceRoutes = []enforceRoute{
-	{"GET", "/api/idmsvc/v1/domains/:uuid"},
-}
-
-//go:embed rbac.yaml
-var rbacMapBytes []byte
+// operationGetOpenapi is the route for the openapi specification,
+// which is not part of the specification itself.
+var operationGetOpenapi = policy.NewOperation("GetOpenapi", http.MethodGet, "/openapi.json")
 
 func getOpenapiPaths(cfg *config.Config, version string) func() []string {
 	if cfg == nil {
@@ -69,21 +52,57 @@ func getOpenapiPaths(cfg *config.Config, version string) func() []string {
 	}
 }
 
-func newRbacSkipper(service string) echo_middleware.Skipper {
-	if service == "" {
-		panic("service is an empty string")
+// publicOperations return the operations served by the public API.
+func publicOperations() []policy.Operation {
+	spec, err := public.GetSwagger()
+	if err != nil {
+		panic(err)
+	}
+	return append(policy.OperationsFromSpec(spec), operationGetOpenapi)
+}
+
+// loadSecurityPolicy load the security policy and check it covers
+// every operation of the public API.
+// data is the content of the security policy file.
+// operations is the list of operations served by the public API.
+// Return the security policy, or panic so the service refuses to
+// start when some operation is not covered.
+func loadSecurityPolicy(data []byte, operations []policy.Operation) *policy.SecurityPolicy {
+	securityPolicy, err := policy.Load(data)
+	if err != nil {
+		panic(err)
+	}
+	if err = securityPolicy.Bind(operations); err != nil {
+		panic(err)
+	}
+	return securityPolicy
+}
+
+// routePolicy return the policy for the route which matched the
+// request, or nil if the route is unknown. The version of the route
+// is ignored, so the routes under /v1 and /v1.0 share the policy.
+func routePolicy(securityPolicy *policy.SecurityPolicy, c echo.Context) *policy.Policy {
+	route := strings.TrimPrefix(c.Path(), trimVersionFromPathPrefix(securityPolicy.Prefix()))
+	_, route, ok := strings.Cut(strings.TrimPrefix(route, "/"), "/")
+	if !ok {
+		return nil
+	}
+	return securityPolicy.Find(c.Request().Method, "/"+route)
+}
+
+func newRbacSkipper(securityPolicy *policy.SecurityPolicy) echo_middleware.Skipper {
+	if securityPolicy == nil {
+		panic("securityPolicy is nil")
 	}
 	return func(c echo.Context) bool {
 		var (
 			cc middleware.DomainContextInterface
 			ok bool
 		)
-		openapiPath := "/api/" + service + "/v1/openapi.json"
 		ctx := c.Request().Context()
-		routePath := c.Path()
-		// The access to the openapi specification is public
-		if routePath == openapiPath {
-			slog.DebugContext(ctx, "route is '"+openapiPath+"'")
+		// The routes without permission are not checked against rbac
+		if p := routePolicy(securityPolicy, c); p == nil || p.Permission == "" {
+			slog.DebugContext(ctx, "route '"+c.Path()+"' has no rbac permission")
 			return true
 		}
 		if cc, ok = c.(middleware.DomainContextInterface); !ok {
@@ -97,38 +116,171 @@ func newRbacSkipper(service string) echo_middleware.Skipper {
 	}
 }
 
-func initRbacMiddleware(cfg *config.Config) echo.MiddlewareFunc {
+func initRbacMiddleware(cfg *config.Config, securityPolicy *policy.SecurityPolicy, metrics *metrics.Metrics, authorizer authz.Authorizer) echo.MiddlewareFunc {
 	if !cfg.Application.EnableRBAC {
 		return middleware.DefaultNooperation
 	}
 
-	service := rbac_data.RBACService("idmsvc")
-	rbac_data.SetRbacServiceValidator(rbac_data.NewRbacServiceValidator(service))
-	rbac_data.SetRbacResourceValidator(
-		rbac_data.NewRbacResourceValidator(
-			rbac_data.RBACResource("token"),
-			rbac_data.RBACResource("domains"),
-			rbac_data.RBACResource("host_conf"),
-			rbac_data.RBACResource("signing_keys"),
-		),
-	)
-	prefix, rbacMap := rbac_data.RBACMapLoad(rbacMapBytes)
-	base := strings.TrimSuffix(cfg.Clients.RbacBaseURL, "/")
-	client, err := rbac.NewClientWithResponses(base)
-	if err != nil {
-		panic(fmt.Errorf("error creating rbac client: %w", err))
+	if authorizer == nil {
+		authorizer = usecase_authz.New(cfg, nil)
 	}
 	rbacMiddleware := middleware.RBACWithConfig(
 		&middleware.RBACConfig{
-			Skipper:       newRbacSkipper("idmsvc"),
-			Prefix:        prefix,
-			PermissionMap: rbacMap,
-			Client:        rbac.New("idmsvc", client),
+			Skipper:       newRbacSkipper(securityPolicy),
+			Prefix:        securityPolicy.Prefix(),
+			PermissionMap: securityPolicy.PermissionMap(),
+			Client:        authorizer,
+			Metrics:       metrics,
 		},
 	)
 	return rbacMiddleware
 }
 
+func initRateLimitMiddleware(cfg *config.Config, securityPolicy *policy.SecurityPolicy, metrics *metrics.Metrics, limiter ratelimit.Limiter) echo.MiddlewareFunc {
+	rateLimit := cfg.Application.RateLimit
+	if !rateLimit.Enabled {
+		return middleware.DefaultNooperation
+	}
+
+	if limiter == nil {
+		limiter = usecase_ratelimit.New(cfg, nil)
+	}
+	return middleware.RateLimitWithConfig(
+		&middleware.RateLimitConfig{
+			Limiter: limiter,
+			Class: func(c echo.Context) string {
+				if p := routePolicy(securityPolicy, c); p != nil {
+					return string(p.Rate)
+				}
+				return ""
+			},
+			Limits: map[string]middleware.RateLimits{
+				string(policy.RateClassRead):  newRateLimits(rateLimit.Read),
+				string(policy.RateClassWrite): newRateLimits(rateLimit.Write),
+				string(policy.RateClassAgent): newRateLimits(rateLimit.Agent),
+			},
+			Metrics: metrics,
+		},
+	)
+}
+
+func initIdempotencyMiddleware(cfg *config.Config, securityPolicy *policy.SecurityPolicy, metrics *metrics.Metrics, store idempotency.Store) echo.MiddlewareFunc {
+	if !cfg.Application.Idempotency.Enabled || cfg.Application.Idempotency.TTL == 0 || store == nil {
+		return middleware.DefaultNooperation
+	}
+	return middleware.IdempotencyWithConfig(
+		&middleware.IdempotencyConfig{
+			Skipper: func(c echo.Context) bool {
+				p := routePolicy(securityPolicy, c)
+				return p == nil || !p.Idempotent
+			},
+			Store: store,
+			TTL:   cfg.Application.Idempotency.TTL,
+			// The response can not be written after the write
+			// timeout, so the client retries the request
+			LockTimeout: cfg.Application.WriteTimeout,
+			Metrics:     metrics,
+		},
+	)
+}
+
+func newRateLimits(class config.RateLimitClass) middleware.RateLimits {
+	return middleware.RateLimits{
+		Org:      ratelimit.Limit{Rate: class.OrgRate, Burst: class.OrgBurst},
+		Identity: ratelimit.Limit{Rate: class.IdentityRate, Burst: class.IdentityBurst},
+	}
+}
+
+// identityPredicates map the identity types of the security policy
+// to the predicates which enforce them.
+var identityPredicates = map[policy.Identity]middleware.IdentityPredicate{
+	policy.IdentityUser:           middleware.EnforceUserPredicate,
+	policy.IdentityServiceAccount: middleware.EnforceServiceAccountPredicate,
+	policy.IdentitySystem:         middleware.EnforceSystemPredicate,
+}
+
+// newEnforceIdentityMiddlewares generate the middlewares which enforce
+// the identity types allowed by the security policy; there is one
+// middleware for every set of identities, which only apply to the
+// routes with that set.
+func newEnforceIdentityMiddlewares(securityPolicy *policy.SecurityPolicy, operations []policy.Operation) []echo.MiddlewareFunc {
+	identitySets := map[string][]policy.Identity{}
+	for _, op := range operations {
+		p := securityPolicy.Find(op.Method, op.Path)
+		if p == nil || p.Allows(policy.IdentityAny) {
+			continue
+		}
+		identitySets[identitySetName(p.Identities)] = p.Identities
+	}
+	names := make([]string, 0, len(identitySets))
+	for name := range identitySets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	middlewares := make([]echo.MiddlewareFunc, 0, len(names))
+	for _, name := range names {
+		predicates := make([]middleware.IdentityPredicate, 0, len(identitySets[name]))
+		for _, identity := range identitySets[name] {
+			predicates = append(predicates, identityPredicates[identity])
+		}
+		middlewares = append(middlewares, middleware.EnforceIdentityWithConfig(
+			&middleware.IdentityConfig{
+				Skipper: newSkipperIdentitySet(securityPolicy, name),
+				Predicates: []middleware.IdentityPredicateEntry{
+					{
+						Name:      name,
+						Predicate: middleware.NewEnforceOr(predicates...),
+					},
+				},
+			},
+		))
+	}
+	return middlewares
+}
+
+// identitySetName return the name for a set of identity types.
+func identitySetName(identities []policy.Identity) string {
+	items := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		items = append(items, string(identity))
+	}
+	sort.Strings(items)
+	return strings.Join(items, "-or-") + "-identity"
+}
+
+// newSkipperIdentitySet skip the routes whose policy does not allow
+// exactly the set of identities with the given name.
+func newSkipperIdentitySet(securityPolicy *policy.SecurityPolicy, name string) echo_middleware.Skipper {
+	return func(c echo.Context) bool {
+		p := routePolicy(securityPolicy, c)
+		if p == nil || p.Allows(policy.IdentityAny) {
+			return true
+		}
+		return identitySetName(p.Identities) != name
+	}
+}
+
+// newSkipperSystemOnly skip the routes which allow other identities
+// than system.
+func newSkipperSystemOnly(securityPolicy *policy.SecurityPolicy) echo_middleware.Skipper {
+	return func(c echo.Context) bool {
+		p := routePolicy(securityPolicy, c)
+		if p == nil || !p.Allows(policy.IdentitySystem) {
+			return true
+		}
+		return p.Allows(policy.IdentityUser) || p.Allows(policy.IdentityServiceAccount)
+	}
+}
+
+// newSkipperAudit skip the routes which are not audited.
+func newSkipperAudit(securityPolicy *policy.SecurityPolicy) echo_middleware.Skipper {
+	return func(c echo.Context) bool {
+		p := routePolicy(securityPolicy, c)
+		return p == nil || !p.Audit
+	}
+}
+
 func guardNewGroupPublic(e *echo.Group, cfg *config.Config, app handler.Application, metrics *metrics.Metrics) {
 	if e == nil {
 		panic("'e' is nil")
@@ -144,14 +296,17 @@ func guardNewGroupPublic(e *echo.Group, cfg *config.Config, app handler.Applicat
 	}
 }
 
-func newGroupPublic(e *echo.Group, cfg *config.Config, app handler.Application, metrics *metrics.Metrics) *echo.Group {
+func newGroupPublic(e *echo.Group, cfg *config.Config, app handler.Application, metrics *metrics.Metrics, authorizer authz.Authorizer, limiter ratelimit.Limiter, store idempotency.Store) *echo.Group {
 	guardNewGroupPublic(e, cfg, app, metrics)
+	operations := publicOperations()
+	securityPolicy := loadSecurityPolicy(securityPolicyBytes, operations)
+
 	// Initialize middlewares
 	fakeIdentityMiddleware := middleware.DefaultNooperation
 	if cfg.Application.AcceptXRHFakeIdentity {
 		fakeIdentityMiddleware = middleware.FakeIdentityWithConfig(
 			&middleware.FakeIdentityConfig{
-				Skipper: skipperSystemPredicate,
+				Skipper: newSkipperSystemOnly(securityPolicy),
 			},
 		)
 	}
@@ -160,49 +315,17 @@ func newGroupPublic(e *echo.Group, cfg *config.Config, app handler.Application,
 		&middleware.ParseXRHIDMiddlewareConfig{},
 	)
 
-	mixedIdentityMiddleware := middleware.EnforceIdentityWithConfig(
-		&middleware.IdentityConfig{
-			Skipper: skipperMixedPredicate,
-			Predicates: []middleware.IdentityPredicateEntry{
-				{
-					Name: "mixed-identity",
-					Predicate: middleware.NewEnforceOr(
-						middleware.EnforceSystemPredicate,
-						middleware.EnforceUserPredicate,
-						middleware.EnforceServiceAccountPredicate,
-					),
-				},
-			},
-		},
-	)
-	systemIdentityMiddleware := middleware.EnforceIdentityWithConfig(
-		&middleware.IdentityConfig{
-			Skipper: skipperSystemPredicate,
-			Predicates: []middleware.IdentityPredicateEntry{
-				{
-					Name:      "system-identity",
-					Predicate: middleware.EnforceSystemPredicate,
-				},
-			},
-		},
-	)
-	userAndSAIdentityMiddleware := middleware.EnforceIdentityWithConfig(
-		&middleware.IdentityConfig{
-			Skipper: skipperUserPredicate,
-			Predicates: []middleware.IdentityPredicateEntry{
-				{
-					Name: "user-and-sa-identity",
-					Predicate: middleware.NewEnforceOr(
-						middleware.EnforceUserPredicate,
-						middleware.EnforceServiceAccountPredicate,
-					),
-				},
-			},
+	enforceIdentityMiddlewares := newEnforceIdentityMiddlewares(securityPolicy, operations)
+	auditMiddleware := middleware.AuditWithConfig(
+		&middleware.AuditConfig{
+			Skipper: newSkipperAudit(securityPolicy),
 		},
 	)
 
 	// FIXME Refactor to inject the config.Config dependency
-	rbacMiddleware := initRbacMiddleware(cfg)
+	rbacMiddleware := initRbacMiddleware(cfg, securityPolicy, metrics, authorizer)
+	rateLimitMiddleware := initRateLimitMiddleware(cfg, securityPolicy, metrics, limiter)
+	idempotencyMiddleware := initIdempotencyMiddleware(cfg, securityPolicy, metrics, store)
 	bodyLimit := echo_middleware.BodyLimit(strconv.Itoa(cfg.Application.SizeLimitRequestBody))
 
 	metricsMiddleware := middleware.MetricsMiddlewareWithConfig(
@@ -229,9 +352,13 @@ func newGroupPublic(e *echo.Group, cfg *config.Config, app handler.Application,
 		metricsMiddleware,
 		fakeIdentityMiddleware,
 		parseXRHIDMiddleware,
-		mixedIdentityMiddleware,
-		systemIdentityMiddleware,
-		userAndSAIdentityMiddleware,
+		auditMiddleware,
+	)
+	e.Use(enforceIdentityMiddlewares...)
+	e.Use(
+		// rateLimit should be before rbac, to limit the requests
+		// to the authorization service too.
+		rateLimitMiddleware,
 		rbacMiddleware,
 		echo_middleware.Secure(),
 		// TODO Check if this is made by 3scale
@@ -241,72 +368,65 @@ func newGroupPublic(e *echo.Group, cfg *config.Config, app handler.Application,
 		// will read the whole request body to validate the input.
 		bodyLimit,
 		validateAPI,
+		// idempotency should be the last one, so the replayed
+		// responses pass every check as the first request.
+		idempotencyMiddleware,
 	)
 
 	// Setup routes
-	public.RegisterHandlersWithBaseURL(e, app, "")
+	public.RegisterHandlersWithBaseURL(escapeColonRouter{e}, app, "")
 	openapi.RegisterHandlersWithBaseURL(e, app, "")
 	return e
 }
 
-// skipperUserPredicate applied when using EnforceUserPredicate.
-// ctx is the request context.
-// Return true if enforce identity is skipped, else false.
-func skipperUserPredicate(ctx echo.Context) bool {
-	var r enforceRoute
-	path := ctx.Path()
-	method := ctx.Request().Method
-	// it is not expected a big number of routes, but if that were
-	// the case into the future, it is more efficient to check
-	// directly against a hashmap instead of traversing the slice
-	for i := range userEnforceRoutes {
-		r = userEnforceRoutes[i]
-		if method == r.Method && path == r.Path {
-			return false
-		}
+var literalColonRegexp = regexp.MustCompile(`([^/]):`)
 
-	}
-	return true
+// escapeColonRouter registers the generated routes escaping the
+// literal colons, as echo would read them as a parameter; for
+// instance '/domains:batch' is registered as '/domains\:batch',
+// the same route that policy.OperationsFromSpec return.
+type escapeColonRouter struct {
+	*echo.Group
 }
 
-// skipperSystemPredicate applied when using EnforceSystemPredicate.
-// ctx is the request context.
-// Return true if enforce identity is skipped, else false.
-func skipperSystemPredicate(ctx echo.Context) bool {
-	// Read the route path __pattern__ that matched this request
-	var r enforceRoute
-	path := ctx.Path()
-	method := ctx.Request().Method
-	// it is not expected a big number of routes, but if that were
-	// the case into the future, it is more efficient to check
-	// directly against a hashmap instead of traversing the slice
-	for i := range systemEnforceRoutes {
-		r = systemEnforceRoutes[i]
-		if method == r.Method && path == r.Path {
-			return false
-		}
-	}
-	return true
+func (r escapeColonRouter) add(method, path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.Group.Add(method, literalColonRegexp.ReplaceAllString(path, `$1\:`), h, m...)
 }
 
-// skipperMixedPredicate applied for specific routes.
-// ctx is the request context.
-// Return true if enforce identity is skipped, else false.
-func skipperMixedPredicate(ctx echo.Context) bool {
-	// Read the route path __pattern__ that matched this request
-	var r enforceRoute
-	path := ctx.Path()
-	method := ctx.Request().Method
-	// it is not expected a big number of routes, but if that were
-	// the case into the future, it is more efficient to check
-	// directly against a hashmap instead of traversing the slice
-	for i := range mixedEnforceRoutes {
-		r = mixedEnforceRoutes[i]
-		if method == r.Method && path == r.Path {
-			return false
-		}
-	}
-	return true
+func (r escapeColonRouter) CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodConnect, path, h, m...)
+}
+
+func (r escapeColonRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodDelete, path, h, m...)
+}
+
+func (r escapeColonRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodGet, path, h, m...)
+}
+
+func (r escapeColonRouter) HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodHead, path, h, m...)
+}
+
+func (r escapeColonRouter) OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodOptions, path, h, m...)
+}
+
+func (r escapeColonRouter) PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPatch, path, h, m...)
+}
+
+func (r escapeColonRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPost, path, h, m...)
+}
+
+func (r escapeColonRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodPut, path, h, m...)
+}
+
+func (r escapeColonRouter) TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.add(http.MethodTrace, path, h, m...)
 }
 
 // newSkipperOpenapi skip /api/idmsvc/v*/openapi.json path
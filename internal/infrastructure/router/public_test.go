@@ -11,13 +11,19 @@ import (
 
 	"github.com/labstack/echo/v4"
 	echo_middleware "github.com/labstack/echo/v4/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware/policy"
 	mock_rbac "github.com/podengo-project/idmsvc-backend/internal/infrastructure/service/impl/mock/rbac/impl"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
 	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
+	api_builder "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
 	"github.com/podengo-project/idmsvc-backend/internal/test/mock/handler"
+	usecase_authz "github.com/podengo-project/idmsvc-backend/internal/usecase/client/authz"
 	client_rbac "github.com/podengo-project/idmsvc-backend/internal/usecase/client/rbac"
+	usecase_ratelimit "github.com/podengo-project/idmsvc-backend/internal/usecase/ratelimit"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,7 +75,7 @@ func TestNewGroupPublicNotPanic(t *testing.T) {
 	require.NotNil(t, e)
 
 	assert.NotPanics(t, func() {
-		newGroupPublic(e.Group(appPrefix+"/"+version), cfg, app, metrics)
+		newGroupPublic(e.Group(appPrefix+"/"+version), cfg, app, metrics, nil, nil, nil)
 	})
 	app.AssertExpectations(t)
 }
@@ -104,6 +110,10 @@ func TestNewGroupPublic(t *testing.T) {
 			"POST": empty,
 		},
 
+		appPrefix + appName + versionFull + "/domains/export": {
+			"GET": empty,
+		},
+
 		appPrefix + appName + versionFull + "/domains/:uuid": {
 			"GET":    empty,
 			"PUT":    empty,
@@ -111,10 +121,42 @@ func TestNewGroupPublic(t *testing.T) {
 			"DELETE": empty,
 		},
 
+		appPrefix + appName + versionFull + "/domains/:uuid/location-subnets": {
+			"GET": empty,
+			"PUT": empty,
+		},
+
+		appPrefix + appName + versionFull + "/domains\\:batch": {
+			"POST": empty,
+		},
+
 		appPrefix + appName + versionFull + "/host-conf/:inventory_id/:fqdn": {
 			"POST": empty,
 		},
 
+		appPrefix + appName + versionFull + "/domains/:uuid/approve": {
+			"POST": empty,
+		},
+
+		appPrefix + appName + versionFull + "/domains/:uuid/reject": {
+			"POST": empty,
+		},
+
+		appPrefix + appName + versionFull + "/webhooks": {
+			"GET":  empty,
+			"POST": empty,
+		},
+
+		appPrefix + appName + versionFull + "/webhooks/:uuid": {
+			"GET":    empty,
+			"PATCH":  empty,
+			"DELETE": empty,
+		},
+
+		appPrefix + appName + versionFull + "/webhooks/:uuid/deliveries": {
+			"GET": empty,
+		},
+
 		appPrefix + appName + versionFull + "/signing_keys": {
 			"GET": empty,
 		},
@@ -140,7 +182,7 @@ func TestNewGroupPublic(t *testing.T) {
 	require.NotNil(t, e)
 	version := "1.0"
 	pathPrefix := trimVersionFromPathPrefix(cfg.Application.PathPrefix)
-	group := newGroupPublic(e.Group(pathPrefix+"/v"+version), cfg, app, metrics)
+	group := newGroupPublic(e.Group(pathPrefix+"/v"+version), cfg, app, metrics, nil, nil, nil)
 	require.NotNil(t, group)
 
 	// Match Routes in expected
@@ -164,7 +206,10 @@ func TestNewGroupPublic(t *testing.T) {
 		e.Group(pathPrefix+"/v"+version),
 		cfg,
 		app,
-		metrics)
+		metrics,
+		usecase_authz.NewAllowAllAuthorizer(),
+		usecase_ratelimit.NewMemoryLimiter(),
+		nil)
 	require.NotNil(t, group)
 	for _, route := range e.Routes() {
 		t.Logf("Method=%s Path=%s Name=%s", route.Method, route.Path, route.Name)
@@ -181,87 +226,6 @@ func TestNewGroupPublic(t *testing.T) {
 	}
 }
 
-func TestSkipperUser(t *testing.T) {
-	type TestCase struct {
-		Name     string
-		Given    enforceRoute
-		Expected bool
-	}
-	testCases := []TestCase{}
-	for i := range userEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("No skip userEnforceRoutes[%d]: %v", i, userEnforceRoutes[i]),
-			Given:    userEnforceRoutes[i],
-			Expected: false,
-		})
-	}
-	for i := range systemEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("Skip systemEnforceRoutes[%d]: %v", i, systemEnforceRoutes[i]),
-			Given:    systemEnforceRoutes[i],
-			Expected: true,
-		})
-	}
-	for i := range mixedEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("Skip mixedEnforceRoutes[%d]: %v", i, mixedEnforceRoutes[i]),
-			Given:    mixedEnforceRoutes[i],
-			Expected: true,
-		})
-	}
-	for _, testCase := range testCases {
-		t.Log(testCase.Name)
-		ctx := helperNewContextForSkipper(
-			testCase.Given.Path,
-			testCase.Given.Method,
-			testCase.Given.Path,
-			nil)
-		result := skipperUserPredicate(ctx)
-		assert.Equal(t, testCase.Expected, result)
-	}
-}
-
-func TestSkipperSystem(t *testing.T) {
-	type TestCase struct {
-		Name     string
-		Given    enforceRoute
-		Expected bool
-	}
-	testCases := []TestCase{}
-	for i := range systemEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("No skip systemEnforceRoutes[%d]: %v", i, systemEnforceRoutes[i]),
-			Given:    systemEnforceRoutes[i],
-			Expected: false,
-		})
-	}
-	for i := range userEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("Skip userEnforceRoutes[%d]: %v", i, userEnforceRoutes[i]),
-			Given:    userEnforceRoutes[i],
-			Expected: true,
-		})
-	}
-	for i := range mixedEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("Skip mixedEnforceRoutes[%d]: %v", i, mixedEnforceRoutes[i]),
-			Given:    mixedEnforceRoutes[i],
-			Expected: true,
-		})
-	}
-	for _, testCase := range testCases {
-		t.Log(testCase.Name)
-		ctx := helperNewContextForSkipper(
-			testCase.Given.Path,
-			testCase.Given.Method,
-			testCase.Given.Path,
-			nil,
-		)
-		result := skipperSystemPredicate(ctx)
-		assert.Equal(t, testCase.Expected, result)
-	}
-}
-
 func TestGetOpenapiPaths(t *testing.T) {
 
 	assert.PanicsWithValue(t, "'cfg' is nil", func() {
@@ -310,17 +274,131 @@ func TestNewSkipperOpenapi(t *testing.T) {
 	assert.False(t, skipper(ctx))
 }
 
+func helperSecurityPolicy(t *testing.T) *policy.SecurityPolicy {
+	var securityPolicy *policy.SecurityPolicy
+	require.NotPanics(t, func() {
+		securityPolicy = loadSecurityPolicy(securityPolicyBytes, publicOperations())
+	})
+	return securityPolicy
+}
+
+func TestLoadSecurityPolicy(t *testing.T) {
+	operations := publicOperations()
+	assert.Contains(t, operations, operationGetOpenapi)
+
+	// The embedded policy cover every public operation
+	securityPolicy := helperSecurityPolicy(t)
+	for _, op := range operations {
+		assert.NotNilf(t, securityPolicy.Find(op.Method, op.Path), "operation %s has no policy", op.ID)
+	}
+
+	assert.PanicsWithError(t, "it was expected security policy version '1.0', but '' was found", func() {
+		loadSecurityPolicy([]byte(`---`), operations)
+	})
+
+	// A new operation without policy make the service refuse to start
+	operations = append(operations, policy.NewOperation("DeleteSigningKeys", http.MethodDelete, "/signing_keys"))
+	assert.PanicsWithError(t, "operations without security policy: DeleteSigningKeys (DELETE /signing_keys)", func() {
+		loadSecurityPolicy(securityPolicyBytes, operations)
+	})
+}
+
+func TestRoutePolicy(t *testing.T) {
+	securityPolicy := helperSecurityPolicy(t)
+	testCases := []struct {
+		Name       string
+		Method     string
+		Route      string
+		Identities []policy.Identity
+	}{
+		{"major version", http.MethodGet, "/api/idmsvc/v1/domains", []policy.Identity{policy.IdentityUser, policy.IdentityServiceAccount}},
+		{"full version", http.MethodPost, "/api/idmsvc/v1.0/domains", []policy.Identity{policy.IdentitySystem}},
+		{"openapi", http.MethodGet, "/api/idmsvc/v1/openapi.json", []policy.Identity{policy.IdentityAny}},
+		{"escaped colon", http.MethodPost, "/api/idmsvc/v1/domains\\:batch", []policy.Identity{policy.IdentityUser, policy.IdentityServiceAccount}},
+		{"unknown method", http.MethodPut, "/api/idmsvc/v1/domains", nil},
+		{"unknown route", http.MethodGet, "/api/idmsvc/v1", nil},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		ctx := helperNewContextForSkipper(testCase.Route, testCase.Method, testCase.Route, nil)
+		p := routePolicy(securityPolicy, ctx)
+		if testCase.Identities == nil {
+			assert.Nil(t, p)
+			continue
+		}
+		require.NotNil(t, p)
+		assert.Equal(t, testCase.Identities, p.Identities)
+	}
+}
+
+func TestNewSkipperIdentitySet(t *testing.T) {
+	securityPolicy := helperSecurityPolicy(t)
+	userSkipper := newSkipperIdentitySet(securityPolicy, "service-account-or-user-identity")
+	systemSkipper := newSkipperIdentitySet(securityPolicy, "system-identity")
+	mixedSkipper := newSkipperIdentitySet(securityPolicy, "service-account-or-system-or-user-identity")
+	systemOnlySkipper := newSkipperSystemOnly(securityPolicy)
+	testCases := []struct {
+		Method     string
+		Route      string
+		User       bool
+		System     bool
+		Mixed      bool
+		SystemOnly bool
+	}{
+		{http.MethodPost, "/api/idmsvc/v1/domains/token", false, true, true, true},
+		{http.MethodGet, "/api/idmsvc/v1/domains", false, true, true, true},
+		{http.MethodPatch, "/api/idmsvc/v1/domains/:uuid", false, true, true, true},
+		{http.MethodDelete, "/api/idmsvc/v1/domains/:uuid", false, true, true, true},
+		{http.MethodGet, "/api/idmsvc/v1/domains/:uuid/location-subnets", false, true, true, true},
+		{http.MethodPut, "/api/idmsvc/v1/domains/:uuid/location-subnets", false, true, true, true},
+		{http.MethodPost, "/api/idmsvc/v1/domains", true, false, true, false},
+		{http.MethodPut, "/api/idmsvc/v1/domains/:uuid", true, false, true, false},
+		{http.MethodPost, "/api/idmsvc/v1/host-conf/:inventory_id/:fqdn", true, false, true, false},
+		{http.MethodGet, "/api/idmsvc/v1/signing_keys", true, false, true, false},
+		{http.MethodGet, "/api/idmsvc/v1/domains/:uuid", true, true, false, true},
+		{http.MethodGet, "/api/idmsvc/v1/openapi.json", true, true, true, true},
+		{http.MethodGet, "/api/idmsvc/v1/unknown", true, true, true, true},
+	}
+	for _, testCase := range testCases {
+		t.Logf("%s %s", testCase.Method, testCase.Route)
+		ctx := helperNewContextForSkipper(testCase.Route, testCase.Method, testCase.Route, nil)
+		assert.Equal(t, testCase.User, userSkipper(ctx), "user")
+		assert.Equal(t, testCase.System, systemSkipper(ctx), "system")
+		assert.Equal(t, testCase.Mixed, mixedSkipper(ctx), "mixed")
+		assert.Equal(t, testCase.SystemOnly, systemOnlySkipper(ctx), "system only")
+	}
+}
+
+func TestNewEnforceIdentityMiddlewares(t *testing.T) {
+	securityPolicy := helperSecurityPolicy(t)
+	middlewares := newEnforceIdentityMiddlewares(securityPolicy, publicOperations())
+	// user or service account, system, and the three of them
+	assert.Len(t, middlewares, 3)
+}
+
+func TestNewSkipperAudit(t *testing.T) {
+	skipper := newSkipperAudit(helperSecurityPolicy(t))
+	assert.False(t, skipper(helperNewContextForSkipper("/api/idmsvc/v1/domains/:uuid", http.MethodDelete, "/api/idmsvc/v1/domains/1", nil)))
+	assert.True(t, skipper(helperNewContextForSkipper("/api/idmsvc/v1/domains/:uuid", http.MethodGet, "/api/idmsvc/v1/domains/1", nil)))
+	assert.True(t, skipper(helperNewContextForSkipper("/api/idmsvc/v1/unknown", http.MethodGet, "/api/idmsvc/v1/unknown", nil)))
+}
+
 func TestNewRbacSkipper(t *testing.T) {
 	var skipper echo_middleware.Skipper
 
-	require.PanicsWithValue(t, "service is an empty string", func() {
-		skipper = newRbacSkipper("")
-	}, "newRbacSkipper panics on empty service")
+	require.PanicsWithValue(t, "securityPolicy is nil", func() {
+		skipper = newRbacSkipper(nil)
+	}, "newRbacSkipper panics on nil securityPolicy")
 
 	require.NotPanics(t, func() {
-		skipper = newRbacSkipper("idmsvc")
-	}, "no panics for idmsvc")
-	skipper(echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/idmsvc/v1/openapi.json", http.NoBody), httptest.NewRecorder()))
+		skipper = newRbacSkipper(helperSecurityPolicy(t))
+	}, "no panics for the embedded security policy")
+
+	// The routes without permission are skipped
+	assert.True(t, skipper(helperNewContextForSkipper("/api/idmsvc/v1/openapi.json", http.MethodGet, "/api/idmsvc/v1/openapi.json", nil)))
+	assert.True(t, skipper(helperNewContextForSkipper("/api/idmsvc/v1/domains", http.MethodPost, "/api/idmsvc/v1/domains", nil)))
+	// No DomainContextInterface is not skipped
+	assert.False(t, skipper(helperNewContextForSkipper("/api/idmsvc/v1/domains", http.MethodGet, "/api/idmsvc/v1/domains", nil)))
 }
 
 func TestInitRbacMiddleware(t *testing.T) {
@@ -330,7 +408,7 @@ func TestInitRbacMiddleware(t *testing.T) {
 			Application: config.Application{
 				EnableRBAC: false,
 			},
-		})
+		}, nil, nil, nil)
 	}, "Return DefaultNooperation")
 	assert.NotNil(t, result)
 
@@ -342,49 +420,45 @@ func TestInitRbacMiddleware(t *testing.T) {
 			Clients: config.Clients{
 				RbacBaseURL: "http://rbac:8000",
 			},
-		})
+		}, helperSecurityPolicy(t), nil, nil)
 	}, "Initialize the rbac middleware")
 	assert.NotNil(t, result)
 }
 
-func TestSkipperMixedPredicate(t *testing.T) {
-	type TestCase struct {
-		Name     string
-		Given    enforceRoute
-		Expected bool
-	}
-	testCases := []TestCase{}
-	for i := range userEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("Skip userEnforceRoutes[%d]: %v", i, userEnforceRoutes[i]),
-			Given:    userEnforceRoutes[i],
-			Expected: true,
-		})
-	}
-	for i := range systemEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("Skip systemEnforceRoutes[%d]: %v", i, systemEnforceRoutes[i]),
-			Given:    systemEnforceRoutes[i],
-			Expected: true,
-		})
-	}
-	for i := range mixedEnforceRoutes {
-		testCases = append(testCases, TestCase{
-			Name:     fmt.Sprintf("No skip mixedEnforceRoutes[%d]: %v", i, mixedEnforceRoutes[i]),
-			Given:    mixedEnforceRoutes[i],
-			Expected: false,
-		})
-	}
-	for _, testCase := range testCases {
-		t.Log(testCase.Name)
-		ctx := helperNewContextForSkipper(
-			testCase.Given.Path,
-			testCase.Given.Method,
-			testCase.Given.Path,
-			nil)
-		result := skipperMixedPredicate(ctx)
-		assert.Equal(t, testCase.Expected, result)
+func TestInitRateLimitMiddleware(t *testing.T) {
+	securityPolicy := helperSecurityPolicy(t)
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	cfg := &config.Config{}
+
+	assert.NotNil(t, initRateLimitMiddleware(cfg, securityPolicy, m, nil), "Return DefaultNooperation")
+
+	cfg.Application.RateLimit.Enabled = true
+	cfg.Application.RateLimit.Agent = config.RateLimitClass{IdentityRate: 1, IdentityBurst: 1}
+	rateLimit := initRateLimitMiddleware(cfg, securityPolicy, m, nil)
+	require.NotNil(t, rateLimit)
+
+	// The rate class is read from the security policy
+	e := echo.New()
+	e.Use(middleware.ContextLogConfig(&middleware.LogConfig{}))
+	e.Use(middleware.CreateContext())
+	e.Use(middleware.ParseXRHIDMiddlewareWithConfig(&middleware.ParseXRHIDMiddlewareConfig{}))
+	e.Use(rateLimit)
+	handler := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/api/idmsvc/v1/signing_keys", handler)
+	e.GET("/api/idmsvc/v1/domains", handler)
+	xrhid := api_builder.NewSystemXRHID().WithOrgID("12345").Build()
+	status := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set(header.HeaderXRHID, header.EncodeXRHID(&xrhid))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
 	}
+	assert.Equal(t, http.StatusOK, status("/api/idmsvc/v1/signing_keys"))
+	assert.Equal(t, http.StatusTooManyRequests, status("/api/idmsvc/v1/signing_keys"))
+	// The read class has no limits
+	assert.Equal(t, http.StatusOK, status("/api/idmsvc/v1/domains"))
+	assert.Equal(t, http.StatusOK, status("/api/idmsvc/v1/domains"))
 }
 
 func TestGuardNewGroupPublic(t *testing.T) {
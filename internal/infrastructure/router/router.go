@@ -10,7 +10,11 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/handler"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/logger"
 	app_middleware "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
 	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	usecase_ratelimit "github.com/podengo-project/idmsvc-backend/internal/usecase/ratelimit"
 )
 
 func getMajorVersion(version string) string {
@@ -46,6 +50,9 @@ func configCommonMiddlewares(e *echo.Echo, cfg *config.Config) {
 	e.Use(app_middleware.ContextLogConfig(&app_middleware.LogConfig{
 		Skipper: loggerSkipperWithPaths(skipperPaths...),
 	}))
+	e.Use(app_middleware.TracingWithConfig(&app_middleware.TracingConfig{
+		Skipper: loggerSkipperWithPaths(skipperPaths...),
+	}))
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
 		// Request logger values for middleware.RequestLoggerValues
 		LogError:  true,
@@ -73,8 +80,14 @@ func configCommonMiddlewares(e *echo.Echo, cfg *config.Config) {
 // e is the echo instance where to add the routes.
 // c is the router configuration.
 // metrics is the reference to the metrics storage.
+// authorizer check the permissions for the public routes; when nil
+// it is created from the configuration.
+// limiter keep the rate limit buckets for the public routes; when
+// nil, the buckets are kept in memory.
+// store keep the responses for the idempotency keys; when nil, the
+// Idempotency-Key header is ignored.
 // Return the echo instance set up; is something fails it panics.
-func NewRouterWithConfig(e *echo.Echo, cfg *config.Config, app handler.Application, metrics *metrics.Metrics) *echo.Echo {
+func NewRouterWithConfig(e *echo.Echo, cfg *config.Config, app handler.Application, metrics *metrics.Metrics, authorizer authz.Authorizer, limiter ratelimit.Limiter, store idempotency.Store) *echo.Echo {
 	guardNewRouterWithConfig(e, cfg, app, metrics)
 	// TODO Add version to the configuration, an set it from config.example.yaml
 	// or clowder.yaml deployment descriptor
@@ -84,9 +97,14 @@ func NewRouterWithConfig(e *echo.Echo, cfg *config.Config, app handler.Applicati
 
 	configCommonMiddlewares(e, cfg)
 
+	// Both versions of the public API share the rate limits
+	if limiter == nil {
+		limiter = usecase_ratelimit.NewMemoryLimiter()
+	}
+
 	newGroupPrivate(e.Group(privatePath), app)
-	newGroupPublic(e.Group(publicPath+"/v"+version), cfg, app, metrics)
-	newGroupPublic(e.Group(publicPath+"/v"+getMajorVersion(version)), cfg, app, metrics)
+	newGroupPublic(e.Group(publicPath+"/v"+version), cfg, app, metrics, authorizer, limiter, store)
+	newGroupPublic(e.Group(publicPath+"/v"+getMajorVersion(version)), cfg, app, metrics, authorizer, limiter, store)
 	return e
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/test"
 	api_metrics "github.com/podengo-project/idmsvc-backend/internal/test/mock/api/metrics"
 	"github.com/podengo-project/idmsvc-backend/internal/test/mock/handler"
+	usecase_authz "github.com/podengo-project/idmsvc-backend/internal/usecase/client/authz"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 )
@@ -102,7 +103,7 @@ func TestNewRouterWithConfig(t *testing.T) {
 	app := handler.NewApplication(t)
 
 	assert.NotPanics(t, func() {
-		e = NewRouterWithConfig(e, cfg, app, metrics)
+		e = NewRouterWithConfig(e, cfg, app, metrics, usecase_authz.NewAllowAllAuthorizer(), nil, nil)
 	})
 	app.AssertExpectations(t)
 }
@@ -11,6 +11,14 @@ type AppSecrets struct {
 	DomainRegKey          []byte
 	HostconfEncryptionId  string
 	HostConfEncryptionKey []byte
+	WebhookKey            []byte
+	// WebhookEncryptionKey encrypt the signing secrets of the
+	// webhooks; HostconfEncryptionId identify its generation too.
+	WebhookEncryptionKey []byte
+	// Previous are the secrets derived from the previous main secrets,
+	// from the newest to the oldest; they are only used to verify
+	// and decrypt the data created before the main secret rotation.
+	Previous []AppSecrets
 }
 
 const (
@@ -27,7 +35,81 @@ func GenerateRandomMainSecret() string {
 }
 
 // Parse main secret and get sub secrets
-func NewAppSecrets(mainSecret string) (sec *AppSecrets, err error) {
+// mainSecret is the current main secret.
+// previousSecrets are the previous main secrets, from the newest to the
+// oldest, which are kept after a rotation.
+func NewAppSecrets(mainSecret string, previousSecrets ...string) (sec *AppSecrets, err error) {
+	if sec, err = newGeneration(mainSecret); err != nil {
+		return nil, err
+	}
+	encryptionIds := map[string]bool{sec.HostconfEncryptionId: true}
+	for idx, previousSecret := range previousSecrets {
+		var previous *AppSecrets
+		if previous, err = newGeneration(previousSecret); err != nil {
+			return nil, fmt.Errorf("Previous main secret %d: %w", idx, err)
+		}
+		if encryptionIds[previous.HostconfEncryptionId] {
+			return nil, fmt.Errorf("Previous main secret %d is duplicated", idx)
+		}
+		encryptionIds[previous.HostconfEncryptionId] = true
+		sec.Previous = append(sec.Previous, *previous)
+	}
+	return sec, nil
+}
+
+// DomainRegKeys return the domain registration keys of every generation,
+// starting by the current one.
+func (s *AppSecrets) DomainRegKeys() [][]byte {
+	keys := make([][]byte, 0, len(s.Previous)+1)
+	keys = append(keys, s.DomainRegKey)
+	for idx := range s.Previous {
+		keys = append(keys, s.Previous[idx].DomainRegKey)
+	}
+	return keys
+}
+
+// HostconfEncryptionIds return the encryption ids of every generation,
+// starting by the current one.
+func (s *AppSecrets) HostconfEncryptionIds() []string {
+	ids := make([]string, 0, len(s.Previous)+1)
+	ids = append(ids, s.HostconfEncryptionId)
+	for idx := range s.Previous {
+		ids = append(ids, s.Previous[idx].HostconfEncryptionId)
+	}
+	return ids
+}
+
+// HostconfEncryptionKeyFor return the encryption key of the generation
+// identified by encryptionId, and false when no generation matches.
+func (s *AppSecrets) HostconfEncryptionKeyFor(encryptionId string) ([]byte, bool) {
+	if encryptionId == s.HostconfEncryptionId {
+		return s.HostConfEncryptionKey, true
+	}
+	for idx := range s.Previous {
+		if encryptionId == s.Previous[idx].HostconfEncryptionId {
+			return s.Previous[idx].HostConfEncryptionKey, true
+		}
+	}
+	return nil, false
+}
+
+// WebhookEncryptionKeyFor return the webhook secret encryption key of
+// the generation identified by encryptionId, and false when no
+// generation matches.
+func (s *AppSecrets) WebhookEncryptionKeyFor(encryptionId string) ([]byte, bool) {
+	if encryptionId == s.HostconfEncryptionId {
+		return s.WebhookEncryptionKey, true
+	}
+	for idx := range s.Previous {
+		if encryptionId == s.Previous[idx].HostconfEncryptionId {
+			return s.Previous[idx].WebhookEncryptionKey, true
+		}
+	}
+	return nil, false
+}
+
+// newGeneration derive the sub secrets of a main secret.
+func newGeneration(mainSecret string) (sec *AppSecrets, err error) {
 	var secret []byte
 
 	if mainSecret == "random" {
@@ -52,6 +134,14 @@ func NewAppSecrets(mainSecret string) (sec *AppSecrets, err error) {
 	if err != nil {
 		return nil, err
 	}
+	sec.WebhookKey, err = HkdfExpand(prk, WebhookKeyInfo)
+	if err != nil {
+		return nil, err
+	}
+	sec.WebhookEncryptionKey, err = HkdfExpand(prk, WebhookEncryptionKeyInfo)
+	if err != nil {
+		return nil, err
+	}
 	encid, err := HkdfExpand(prk, HostconfEncryptionIdInfo)
 	if err != nil {
 		return nil, err
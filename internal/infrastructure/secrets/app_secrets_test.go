@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewAppSecret(t *testing.T) {
@@ -15,9 +16,66 @@ func TestNewAppSecret(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, sec.DomainRegKey)
 	assert.NotNil(t, sec.HostConfEncryptionKey)
+	assert.NotNil(t, sec.WebhookKey)
+	assert.Len(t, sec.WebhookEncryptionKey, 16)
 	assert.NotEmpty(t, sec.HostconfEncryptionId)
 
 	sec, err = NewAppSecrets("short")
 	assert.Nil(t, sec)
 	assert.Error(t, err)
 }
+
+func TestNewAppSecretPrevious(t *testing.T) {
+	current := GenerateRandomMainSecret()
+	previous := GenerateRandomMainSecret()
+	oldest := GenerateRandomMainSecret()
+
+	sec, err := NewAppSecrets(current, previous, oldest)
+	require.NoError(t, err)
+	require.Len(t, sec.Previous, 2)
+	secPrevious, err := NewAppSecrets(previous)
+	require.NoError(t, err)
+	secOldest, err := NewAppSecrets(oldest)
+	require.NoError(t, err)
+	assert.Equal(t, *secPrevious, sec.Previous[0])
+	assert.Equal(t, *secOldest, sec.Previous[1])
+
+	assert.Equal(t, [][]byte{
+		sec.DomainRegKey,
+		secPrevious.DomainRegKey,
+		secOldest.DomainRegKey,
+	}, sec.DomainRegKeys())
+	assert.Equal(t, []string{
+		sec.HostconfEncryptionId,
+		secPrevious.HostconfEncryptionId,
+		secOldest.HostconfEncryptionId,
+	}, sec.HostconfEncryptionIds())
+
+	key, ok := sec.HostconfEncryptionKeyFor(sec.HostconfEncryptionId)
+	assert.True(t, ok)
+	assert.Equal(t, sec.HostConfEncryptionKey, key)
+	key, ok = sec.HostconfEncryptionKeyFor(secOldest.HostconfEncryptionId)
+	assert.True(t, ok)
+	assert.Equal(t, secOldest.HostConfEncryptionKey, key)
+	key, ok = sec.HostconfEncryptionKeyFor("0011223344556677")
+	assert.False(t, ok)
+	assert.Nil(t, key)
+
+	key, ok = sec.WebhookEncryptionKeyFor(sec.HostconfEncryptionId)
+	assert.True(t, ok)
+	assert.Equal(t, sec.WebhookEncryptionKey, key)
+	key, ok = sec.WebhookEncryptionKeyFor(secPrevious.HostconfEncryptionId)
+	assert.True(t, ok)
+	assert.Equal(t, secPrevious.WebhookEncryptionKey, key)
+	key, ok = sec.WebhookEncryptionKeyFor("0011223344556677")
+	assert.False(t, ok)
+	assert.Nil(t, key)
+
+	sec, err = NewAppSecrets(current, "c2hvcnQ")
+	assert.Nil(t, sec)
+	assert.EqualError(t, err, "Previous main secret 0: Main secret is too short, expected at least 16 bytes.")
+
+	sec, err = NewAppSecrets(current, previous, current)
+	assert.Nil(t, sec)
+	assert.EqualError(t, err, "Previous main secret 1 is duplicated")
+}
@@ -25,6 +25,10 @@ var (
 	HostconfEncryptionIdInfo = HkdfInfo{[]byte("hostconf JWK encryption id"), 8}
 	// AES-GCM encryption keys for private JWKs
 	HostconfEncryptionKeyInfo = HkdfInfo{[]byte("hostconf JWK encryption key"), 16}
+	// MAC key to derive the signing key of every webhook
+	WebhookKeyInfo = HkdfInfo{[]byte("webhook signing key"), 32}
+	// AES-GCM encryption keys for the signing secrets of the webhooks
+	WebhookEncryptionKeyInfo = HkdfInfo{[]byte("webhook secret encryption key"), 16}
 )
 
 // Extract pseudo random key from a secret
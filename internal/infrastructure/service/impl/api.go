@@ -13,6 +13,9 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/handler"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/router"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/service"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
 	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 )
 
@@ -25,7 +28,7 @@ type apiService struct {
 	echo *echo.Echo
 }
 
-func NewApi(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, app handler.Application, metrics *metrics.Metrics) service.ApplicationService {
+func NewApi(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, app handler.Application, metrics *metrics.Metrics, authorizer authz.Authorizer, limiter ratelimit.Limiter, store idempotency.Store) service.ApplicationService {
 	if cfg == nil {
 		panic("config is nil")
 	}
@@ -42,6 +45,9 @@ func NewApi(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, app han
 		cfg,
 		app,
 		metrics,
+		authorizer,
+		limiter,
+		store,
 	)
 	result.echo.HideBanner = true
 	result.echo.HTTPErrorHandler = echo_error.DefaultErrorHandler
@@ -6,10 +6,15 @@ import (
 
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	handler_impl "github.com/podengo-project/idmsvc-backend/internal/handler/impl"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/service"
 	client_pendo "github.com/podengo-project/idmsvc-backend/internal/interface/client/pendo"
-	client_rbac "github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
 	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	usecase_authz "github.com/podengo-project/idmsvc-backend/internal/usecase/client/authz"
+	usecase_entitlements "github.com/podengo-project/idmsvc-backend/internal/usecase/client/entitlements"
+	usecase_rbac "github.com/podengo-project/idmsvc-backend/internal/usecase/client/rbac"
+	usecase_idempotency "github.com/podengo-project/idmsvc-backend/internal/usecase/idempotency"
+	usecase_ratelimit "github.com/podengo-project/idmsvc-backend/internal/usecase/ratelimit"
 	"github.com/prometheus/client_golang/prometheus"
 	"gorm.io/gorm"
 )
@@ -22,11 +27,13 @@ type svcApplication struct {
 	Api       service.ApplicationService
 	Kafka     service.ApplicationService
 	Metrics   service.ApplicationService
+	Stats     service.ApplicationService
+	Webhooks  service.ApplicationService
 	MockRbac  service.ApplicationService
 	// AdditionalService service.ApplicationService
 }
 
-func guardNewApplication(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, rbac client_rbac.Rbac, pendo client_pendo.Pendo) {
+func guardNewApplication(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, pendo client_pendo.Pendo) {
 	if ctx == nil {
 		panic("'ctx' is nil")
 	}
@@ -39,16 +46,13 @@ func guardNewApplication(ctx context.Context, wg *sync.WaitGroup, cfg *config.Co
 	if db == nil {
 		panic("'db' is nil")
 	}
-	if rbac == nil {
-		panic("'rbac' is nil")
-	}
 	if pendo == nil {
 		panic("'pendo' is nil")
 	}
 }
 
-func NewApplication(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, rbac client_rbac.Rbac, pendo client_pendo.Pendo) service.ApplicationService {
-	guardNewApplication(ctx, wg, cfg, db, rbac, pendo)
+func NewApplication(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, pendo client_pendo.Pendo) service.ApplicationService {
+	guardNewApplication(ctx, wg, cfg, db, pendo)
 	s := &svcApplication{}
 	s.Config = cfg
 	s.Context, s.Cancel = context.WithCancel(ctx)
@@ -56,26 +60,49 @@ func NewApplication(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config,
 
 	reg := prometheus.NewRegistry()
 	metrics := metrics.NewMetrics(reg)
+	if err := datastore.InstrumentDB(db, cfg.Database.Name, metrics); err != nil {
+		panic(err)
+	}
+	aclCache := usecase_rbac.NewACLCache(cfg.Clients.RbacCacheTTL, metrics)
+	breaker := usecase_rbac.NewCircuitBreaker(cfg.Clients.RbacBreakerThreshold, cfg.Clients.RbacBreakerOpenTimeout, metrics)
+	authorizer := usecase_authz.New(cfg, &usecase_rbac.WrapperConfig{
+		Cache:        aclCache,
+		Breaker:      breaker,
+		Retries:      cfg.Clients.RbacRetries,
+		RetryBackoff: cfg.Clients.RbacRetryBackoff,
+		Metrics:      metrics,
+	})
+
+	limiter := usecase_ratelimit.New(cfg, db)
+	idempotencyStore := usecase_idempotency.NewPostgresStore(db)
+	entitlements := usecase_entitlements.New(cfg)
+	registry := newHealthRegistry(cfg, db, breaker)
 
 	// Create application handlers
-	handler := handler_impl.NewHandler(s.Config, db, metrics, rbac, pendo)
+	handler := handler_impl.NewHandler(s.Config, db, metrics, authorizer, registry, pendo, entitlements)
 
 	// Create Metrics service
 	s.Metrics = NewMetrics(s.Context, s.WaitGroup, s.Config, handler)
 
+	// Create the domain statistics service
+	s.Stats = NewDomainStats(s.Context, s.WaitGroup, s.Config, db, metrics)
+
+	// Create the webhook deliveries service
+	s.Webhooks = NewWebhooks(s.Context, s.WaitGroup, s.Config, db, metrics)
+
 	// Create Api service
-	s.Api = NewApi(s.Context, s.WaitGroup, s.Config, handler, metrics)
+	s.Api = NewApi(s.Context, s.WaitGroup, s.Config, handler, metrics, authorizer, limiter, idempotencyStore)
 
 	// Create kafka consumer service
 	// TODO Uncomment or clean-up when we know if we use kafka
-	// s.Kafka = NewKafkaConsumer(s.Context, s.WaitGroup, s.Config, db)
+	// s.Kafka = NewKafkaConsumer(s.Context, s.WaitGroup, s.Config, db, aclCache, registry)
 
 	return s
 }
 
 func (svc *svcApplication) Start() error {
-	// svc.WaitGroup.Add(3)
-	svc.WaitGroup.Add(2)
+	// svc.WaitGroup.Add(5)
+	svc.WaitGroup.Add(4)
 	go func() {
 		defer svc.WaitGroup.Done()
 		defer svc.Cancel()
@@ -102,6 +129,24 @@ func (svc *svcApplication) Start() error {
 		}
 		<-svc.Context.Done()
 	}()
+
+	go func() {
+		defer svc.WaitGroup.Done()
+		defer svc.Cancel()
+		if err := svc.Stats.Start(); err != nil {
+			panic(err)
+		}
+		<-svc.Context.Done()
+	}()
+
+	go func() {
+		defer svc.WaitGroup.Done()
+		defer svc.Cancel()
+		if err := svc.Webhooks.Start(); err != nil {
+			panic(err)
+		}
+		<-svc.Context.Done()
+	}()
 	return nil
 }
 
@@ -0,0 +1,96 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/service"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	usecase_repository "github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
+	"gorm.io/gorm"
+)
+
+type domainStatsService struct {
+	context    context.Context
+	cancel     context.CancelFunc
+	waitGroup  *sync.WaitGroup
+	config     *config.Config
+	db         *gorm.DB
+	metrics    *metrics.Metrics
+	repository repository.StatsRepository
+}
+
+// NewDomainStats create the service which refresh periodically the
+// domain statistics exposed by the metrics.
+func NewDomainStats(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, m *metrics.Metrics) service.ApplicationService {
+	if cfg == nil {
+		panic("config is nil")
+	}
+	if wg == nil {
+		panic("wg is nil")
+	}
+	if db == nil {
+		panic("db is nil")
+	}
+	if m == nil {
+		panic("metrics is nil")
+	}
+
+	result := &domainStatsService{}
+	result.context, result.cancel = context.WithCancel(ctx)
+	result.waitGroup = wg
+	result.config = cfg
+	result.db = db
+	result.metrics = m
+	result.repository = usecase_repository.NewStatsRepository()
+	return result
+}
+
+func (srv *domainStatsService) Start() error {
+	srv.waitGroup.Add(1)
+	go func() {
+		defer srv.waitGroup.Done()
+		interval := srv.config.Metrics.StatsInterval
+		if interval <= 0 {
+			interval = config.DefaultMetricsStatsInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			srv.refresh()
+			select {
+			case <-srv.context.Done():
+				slog.Info("Shutting down domainStatsService")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+func (srv *domainStatsService) Stop() error {
+	srv.cancel()
+	return nil
+}
+
+// refresh read the domain statistics and update the metrics; on
+// failure the previous statistics are kept.
+func (srv *domainStatsService) refresh() {
+	logger := slog.Default().With(slog.String("service", "domain_stats"))
+	ctx := app_context.CtxWithLog(
+		app_context.CtxWithDB(srv.context, srv.db.WithContext(srv.context)),
+		logger,
+	)
+	stats, err := srv.repository.DomainStats(ctx, time.Now())
+	if err != nil {
+		logger.Error("failed to refresh the domain statistics")
+		return
+	}
+	srv.metrics.SetDomainStats(stats)
+}
@@ -0,0 +1,100 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
+	client_rbac "github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+	usecase_repository "github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
+	"gorm.io/gorm"
+)
+
+// newHealthRegistry create the registry of health checks with the
+// dependencies shared by the services; the services register their
+// own checks when they are created.
+func newHealthRegistry(cfg *config.Config, db *gorm.DB, breaker client_rbac.CircuitBreaker) *health.Registry {
+	registry := health.NewRegistry(cfg.Application.Health.CacheTTL, cfg.Application.Health.Timeout)
+	client := &http.Client{}
+
+	registry.Register(datastore.NewHealthCheck(db, &cfg.Database))
+	registry.Register(health.Check{
+		Name:     "jwk",
+		Severity: health.Critical,
+		Probe:    newJwkProbe(cfg, db),
+	})
+	if cfg.Application.EnableRBAC {
+		registry.Register(health.Check{
+			Name:     "authz",
+			Severity: health.NonCritical,
+			Probe:    newAuthzProbe(cfg, client, breaker),
+			State:    newBreakerState(breaker),
+		})
+	}
+	if cfg.Clients.PendoBaseURL != "" {
+		registry.Register(health.Check{
+			Name:     "pendo",
+			Severity: health.NonCritical,
+			Probe:    health.NewHTTPProbe(client, cfg.Clients.PendoBaseURL),
+		})
+	}
+	return registry
+}
+
+// newJwkProbe check there is at least one unexpired private key to
+// sign the host registration tokens.
+func newJwkProbe(cfg *config.Config, db *gorm.DB) health.Probe {
+	repository := usecase_repository.NewHostconfJwkRepository(cfg)
+	return func(ctx context.Context) error {
+		ctx = app_context.CtxWithLog(
+			app_context.CtxWithDB(ctx, db.WithContext(ctx)),
+			slog.Default().With(slog.String("health", "jwk")),
+		)
+		keys, err := repository.GetPrivateSigningKeys(ctx)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			return errors.New("no valid private signing key")
+		}
+		return nil
+	}
+}
+
+// newAuthzProbe check the authorization service is reachable. While
+// the circuit breaker of rbac is open the requests do not reach rbac,
+// so the probe is skipped instead of making the service not ready;
+// the state is reported by newBreakerState.
+func newAuthzProbe(cfg *config.Config, client *http.Client, breaker client_rbac.CircuitBreaker) health.Probe {
+	baseURL := cfg.Clients.RbacBaseURL
+	if cfg.Clients.AuthzProvider == config.AuthzProviderRelations {
+		baseURL = cfg.Clients.RelationsBaseURL
+	}
+	reachable := health.NewHTTPProbe(client, baseURL)
+	return func(ctx context.Context) error {
+		if breaker != nil && breaker.RetryAfter() > 0 {
+			return nil
+		}
+		return reachable(ctx)
+	}
+}
+
+// newBreakerState report the circuit breaker of rbac while it is open.
+func newBreakerState(breaker client_rbac.CircuitBreaker) func() string {
+	return func() string {
+		if breaker == nil {
+			return ""
+		}
+		if retryAfter := breaker.RetryAfter(); retryAfter > 0 {
+			return fmt.Sprintf("rbac circuit breaker is open, retry after %s", retryAfter.Round(time.Second))
+		}
+		return ""
+	}
+}
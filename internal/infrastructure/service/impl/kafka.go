@@ -2,37 +2,72 @@ package impl
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/confluentinc/confluent-kafka-go/kafka"
 	api_event "github.com/podengo-project/idmsvc-backend/internal/api/event"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/handler/impl"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/event"
 	event_handler "github.com/podengo-project/idmsvc-backend/internal/infrastructure/event/handler"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/event/producer"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/health"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/service"
+	client_rbac "github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
 	"gorm.io/gorm"
 )
 
+// kafkaProducerProbeTimeout is the time to wait for the metadata of
+// the brokers when the probe has no deadline.
+const kafkaProducerProbeTimeout = 5 * time.Second
+
 type kafkaConsumer struct {
 	context   context.Context
 	cancel    context.CancelFunc
 	waitGroup *sync.WaitGroup
 	config    *config.Config
 
-	db *gorm.DB
+	db       *gorm.DB
+	aclCache client_rbac.ACLInvalidator
+	running  atomic.Bool
+
+	producerMutex sync.Mutex
+	producer      *kafka.Producer
 }
 
-func NewKafkaConsumer(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB) service.ApplicationService {
+// NewKafkaConsumer create the service which consume the kafka
+// events. The consumer registers into registry a check which fails
+// when the consumer loop is not running, and a non-critical check
+// which fails when the brokers can not be reached by a producer.
+func NewKafkaConsumer(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, aclCache client_rbac.ACLInvalidator, registry *health.Registry) service.ApplicationService {
 	ctx, cancel := context.WithCancel(ctx)
-	return &kafkaConsumer{
+	s := &kafkaConsumer{
 		context:   ctx,
 		cancel:    cancel,
 		waitGroup: wg,
 		config:    cfg,
 
-		db: db,
+		db:       db,
+		aclCache: aclCache,
 	}
+	if registry != nil {
+		registry.Register(health.Check{
+			Name:     "kafka-consumer",
+			Severity: health.Critical,
+			Liveness: true,
+			Probe:    s.probe,
+		})
+		registry.Register(health.Check{
+			Name:     "kafka-producer",
+			Severity: health.NonCritical,
+			Probe:    s.probeProducer,
+		})
+	}
+	return s
 }
 
 func (s *kafkaConsumer) Start() error {
@@ -43,8 +78,11 @@ func (s *kafkaConsumer) Start() error {
 		// Create event router
 		eventRouter := event_handler.NewRouter()
 		eventRouter.Add(api_event.TopicTodoCreated, impl.NewTodoCreatedEventHandler(s.db))
+		eventRouter.Add(api_event.TopicRbacPermissionChanged, impl.NewRbacPermissionChangedEventHandler(s.aclCache))
 
 		// Start service
+		s.running.Store(true)
+		defer s.running.Store(false)
 		event.Start(s.context, &s.config.Kafka, eventRouter)
 		slog.Info("kafkaConsumer stopped")
 	}()
@@ -53,5 +91,45 @@ func (s *kafkaConsumer) Start() error {
 
 func (s *kafkaConsumer) Stop() error {
 	s.cancel()
+	s.producerMutex.Lock()
+	defer s.producerMutex.Unlock()
+	if s.producer != nil {
+		s.producer.Close()
+		s.producer = nil
+	}
+	return nil
+}
+
+// probe fails when the consumer loop is not running.
+func (s *kafkaConsumer) probe(ctx context.Context) error {
+	if !s.running.Load() {
+		return errors.New("kafka consumer is not running")
+	}
+	return nil
+}
+
+// probeProducer fails when the metadata of the brokers can not be
+// read by a producer. The producer is created on the first probe and
+// closed on Stop.
+func (s *kafkaConsumer) probeProducer(ctx context.Context) error {
+	s.producerMutex.Lock()
+	defer s.producerMutex.Unlock()
+	if s.context.Err() != nil {
+		return errors.New("kafka service is stopped")
+	}
+	if s.producer == nil {
+		p, err := producer.NewProducer(&s.config.Kafka)
+		if err != nil {
+			return err
+		}
+		s.producer = p
+	}
+	timeout := kafkaProducerProbeTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if _, err := s.producer.GetMetadata(nil, false, int(timeout.Milliseconds())); err != nil {
+		return err
+	}
 	return nil
 }
@@ -71,6 +71,9 @@ type Permission struct {
 
 type MockRbac interface {
 	SetPermissions(data []string)
+	SetDomainPermissions(data []string, domainIDs []string)
+	SetLatency(latency time.Duration)
+	SetErrors(status int, count int)
 	GetBaseURL() string
 	WaitAddress(timeout time.Duration) error
 }
@@ -85,6 +88,13 @@ type mockRbac struct {
 	port       string
 	appName    string
 	data       []Permission
+	// latency delay every response
+	latency time.Duration
+	// errorStatus is the status returned instead of the ACL for the
+	// next errorCount requests, or for all of them when errorCount
+	// is negative.
+	errorStatus int
+	errorCount  int
 }
 
 // LoadProfile unmarshall a yaml content with a list
@@ -223,6 +233,75 @@ func (m *mockRbac) SetPermissions(data []string) {
 	m.data = newData
 }
 
+// SetDomainPermissions assign the list of permissions restricted
+// to some domains, by the resourceDefinitions filter on the
+// 'idmsvc.domains.id' key.
+// data contains the permissions to be returned.
+// domainIDs are the domains where the permissions are granted.
+func (m *mockRbac) SetDomainPermissions(data []string, domainIDs []string) {
+	newData := make([]Permission, len(data))
+	for i := range data {
+		newData[i] = Permission{
+			Permission: data[i],
+			ResourceDefinitions: []any{
+				map[string]any{
+					"attributeFilter": map[string]any{
+						"key":       "idmsvc.domains.id",
+						"operation": "in",
+						"value":     domainIDs,
+					},
+				},
+			},
+		}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.data = newData
+}
+
+// SetLatency delay every response of the mock by latency, to
+// simulate a slow rbac service.
+func (m *mockRbac) SetLatency(latency time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.latency = latency
+}
+
+// SetErrors make the mock to fail with the http status for the
+// next count requests; a zero count fail every request, and a
+// zero status stop failing.
+func (m *mockRbac) SetErrors(status int, count int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.errorStatus = status
+	m.errorCount = count
+	if count == 0 {
+		m.errorCount = -1
+	}
+}
+
+// injectFault apply the latency and return the status to fail
+// the current request with, or zero when it should succeed.
+func (m *mockRbac) injectFault(c echo.Context) int {
+	m.lock.Lock()
+	latency := m.latency
+	status := m.errorStatus
+	if status != 0 && m.errorCount > 0 {
+		m.errorCount--
+		if m.errorCount == 0 {
+			m.errorStatus = 0
+		}
+	}
+	m.lock.Unlock()
+	if latency > 0 {
+		select {
+		case <-c.Request().Context().Done():
+		case <-time.After(latency):
+		}
+	}
+	return status
+}
+
 // WaitAddress is a naive implementation to await the rbac
 // mock has an address assigned.
 func (m *mockRbac) WaitAddress(timeout time.Duration) error {
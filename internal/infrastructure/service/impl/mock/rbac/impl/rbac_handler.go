@@ -37,6 +37,9 @@ func (m *mockRbac) accessHandler(c echo.Context) error {
 		u      *url.URL
 		q      url.Values
 	)
+	if status := m.injectFault(c); status != 0 {
+		return echo.NewHTTPError(status, "injected error")
+	}
 	if err = c.Bind(&params); err != nil {
 		slog.Error(err.Error())
 		return err
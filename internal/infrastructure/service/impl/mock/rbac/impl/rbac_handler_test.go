@@ -50,7 +50,7 @@ func TestAccessHandler(t *testing.T) {
 	require.NoError(t, err)
 	expected := &Page{
 		Meta: map[string]any{
-			"count":  float64(6),
+			"count":  float64(11),
 			"limit":  float64(100),
 			"offset": float64(0),
 		},
@@ -75,6 +75,10 @@ func TestAccessHandler(t *testing.T) {
 				Permission:          "idmsvc:domains:update",
 				ResourceDefinitions: []any{},
 			},
+			{
+				Permission:          "idmsvc:domains:approve",
+				ResourceDefinitions: []any{},
+			},
 			{
 				Permission:          "idmsvc:domains:delete",
 				ResourceDefinitions: []any{},
@@ -83,7 +87,101 @@ func TestAccessHandler(t *testing.T) {
 				Permission:          "idmsvc:domains:read",
 				ResourceDefinitions: []any{},
 			},
+			{
+				Permission:          "idmsvc:webhooks:create",
+				ResourceDefinitions: []any{},
+			},
+			{
+				Permission:          "idmsvc:webhooks:read",
+				ResourceDefinitions: []any{},
+			},
+			{
+				Permission:          "idmsvc:webhooks:update",
+				ResourceDefinitions: []any{},
+			},
+			{
+				Permission:          "idmsvc:webhooks:delete",
+				ResourceDefinitions: []any{},
+			},
 		},
 	}
 	assert.Equal(t, expected, dataPage)
 }
+
+func TestAccessHandlerFaults(t *testing.T) {
+	cfg := helperConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv, mockRbac := NewRbacMock(ctx, cfg)
+	err := srv.Start()
+	if err == nil {
+		defer srv.Stop()
+	}
+	require.NoError(t, err)
+	require.NoError(t, mockRbac.WaitAddress(5*time.Second))
+	mockRbac.SetPermissions(Profiles[ProfileDomainAdmin])
+	u := mockRbac.GetBaseURL() + "/access/?application=idmsvc"
+
+	get := func() int {
+		res, err := http.Get(u)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		return res.StatusCode
+	}
+
+	// Fail the next two requests
+	mockRbac.SetErrors(http.StatusServiceUnavailable, 2)
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+	assert.Equal(t, http.StatusServiceUnavailable, get())
+	assert.Equal(t, http.StatusOK, get())
+
+	// Fail every request until it is cleared
+	mockRbac.SetErrors(http.StatusInternalServerError, 0)
+	assert.Equal(t, http.StatusInternalServerError, get())
+	assert.Equal(t, http.StatusInternalServerError, get())
+	mockRbac.SetErrors(0, 0)
+	assert.Equal(t, http.StatusOK, get())
+
+	// Delay the responses
+	mockRbac.SetLatency(200 * time.Millisecond)
+	start := time.Now()
+	assert.Equal(t, http.StatusOK, get())
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	mockRbac.SetLatency(0)
+}
+
+func TestAccessHandlerDomainPermissions(t *testing.T) {
+	cfg := helperConfig()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv, mockRbac := NewRbacMock(ctx, cfg)
+	err := srv.Start()
+	if err == nil {
+		defer srv.Stop()
+	}
+	require.NoError(t, err)
+	require.NoError(t, mockRbac.WaitAddress(5*time.Second))
+	domainID := "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"
+	mockRbac.SetDomainPermissions([]string{"idmsvc:domains:update"}, []string{domainID})
+
+	res, err := http.Get(mockRbac.GetBaseURL() + "/access/?application=idmsvc")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	dataPage := &Page{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(dataPage))
+	assert.Equal(t, []Permission{
+		{
+			Permission: "idmsvc:domains:update",
+			ResourceDefinitions: []any{
+				map[string]any{
+					"attributeFilter": map[string]any{
+						"key":       "idmsvc.domains.id",
+						"operation": "in",
+						"value":     []any{domainID},
+					},
+				},
+			},
+		},
+	}, dataPage.Data)
+}
@@ -0,0 +1,220 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	api_relations "github.com/podengo-project/idmsvc-backend/internal/api/relations"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/logger"
+	app_middleware "github.com/podengo-project/idmsvc-backend/internal/infrastructure/middleware"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/service"
+)
+
+var (
+	errRelationsMockAwaitTimeout = errors.New("timeout awaiting relations mock to be ready")
+	errRelationsMockUnknown      = errors.New("unknown error happened on relations mock")
+)
+
+// Relationship is a tuple which grant relation on resource
+// to subject.
+type Relationship struct {
+	Subject  api_relations.ObjectReference
+	Relation string
+	Resource api_relations.ObjectReference
+}
+
+// MockRelations is a local stand-in of the relationship-based
+// check API; it only resolves the relationships added directly.
+type MockRelations interface {
+	AddRelationship(r Relationship)
+	Reset()
+	Handler() http.Handler
+	GetBaseURL() string
+	WaitAddress(timeout time.Duration) error
+}
+
+type mockRelations struct {
+	echo          *echo.Echo
+	lock          sync.Mutex
+	context       context.Context
+	cancelFunc    context.CancelFunc
+	address       string
+	waitGroup     *sync.WaitGroup
+	relationships map[Relationship]struct{}
+}
+
+func newRelationsMockGuards(ctx context.Context, cfg *config.Config) {
+	if ctx == nil {
+		panic("ctx is nil")
+	}
+	if cfg == nil {
+		panic("cfg is nil")
+	}
+	if cfg.Clients.RelationsBaseURL == "" {
+		panic("Config.Clients.RelationsBaseURL is an empty string")
+	}
+}
+
+// NewRelationsMock return a new relations mock service for testing.
+func NewRelationsMock(ctx context.Context, cfg *config.Config) (service.ApplicationService, MockRelations) {
+	var cancelFunc context.CancelFunc
+	newRelationsMockGuards(ctx, cfg)
+	urlData, err := url.Parse(cfg.Clients.RelationsBaseURL)
+	if err != nil {
+		panic(fmt.Sprintf("error parsing relations client url: %s", err.Error()))
+	}
+	address := fmt.Sprintf("%s:%s", urlData.Hostname(), urlData.Port())
+	ctx, cancelFunc = context.WithCancel(ctx)
+	e := echo.New()
+	e.Pre(middleware.RemoveTrailingSlash())
+	e.Use(app_middleware.ContextLogConfig(&app_middleware.LogConfig{}))
+	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogError:      true,
+		LogMethod:     true,
+		LogStatus:     true,
+		LogURI:        true,
+		HandleError:   true,
+		LogValuesFunc: logger.MiddlewareLogValues,
+	}))
+	m := &mockRelations{
+		address:       address,
+		echo:          e,
+		context:       ctx,
+		cancelFunc:    cancelFunc,
+		waitGroup:     &sync.WaitGroup{},
+		relationships: map[Relationship]struct{}{},
+	}
+	e.POST(urlData.Path+api_relations.PathCheck, m.checkHandler)
+	e.POST(urlData.Path+api_relations.PathLookupResources, m.lookupResourcesHandler)
+	return m, m
+}
+
+func (m *mockRelations) Start() error {
+	m.echo.HideBanner = true
+	m.echo.Debug = false
+	m.echo.HidePort = false
+	m.waitGroup.Add(2)
+	go func() {
+		defer m.waitGroup.Done()
+		slog.Info("mock relations service starting")
+		if err := m.echo.Start(m.address); err != nil {
+			if err != http.ErrServerClosed {
+				slog.Error(err.Error())
+			} else {
+				slog.Info("Service relations mock closed")
+			}
+			return
+		}
+	}()
+	go func() {
+		defer m.waitGroup.Done()
+		defer m.cancelFunc()
+		<-m.context.Done()
+		if err := m.echo.Shutdown(m.context); err != nil {
+			slog.Error(err.Error())
+			return
+		}
+	}()
+	return nil
+}
+
+func (m *mockRelations) Stop() error {
+	slog.Info("mock relations service stopping")
+	defer m.waitGroup.Wait()
+	m.cancelFunc()
+	return nil
+}
+
+// AddRelationship grant the relation described by r.
+func (m *mockRelations) AddRelationship(r Relationship) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.relationships[r] = struct{}{}
+}
+
+// Reset remove all the relationships.
+func (m *mockRelations) Reset() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.relationships = map[Relationship]struct{}{}
+}
+
+// Handler return the http handler of the mock, so it can
+// be served by httptest without starting the service.
+func (m *mockRelations) Handler() http.Handler {
+	return m.echo
+}
+
+// WaitAddress is a naive implementation to await the relations
+// mock has an address assigned.
+func (m *mockRelations) WaitAddress(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Compare(deadline) < 0 {
+		if m.echo.Listener != nil && m.echo.Listener.Addr().String() != "" {
+			slog.Info(fmt.Sprintf("relations mock listening at: %s", m.echo.Listener.Addr().String()))
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if time.Now().Compare(deadline) >= 0 {
+		return errRelationsMockAwaitTimeout
+	}
+	return errRelationsMockUnknown
+}
+
+// GetBaseURL retrieve the base URL to reach out the
+// relations mock.
+// Return empty string if the listener is not yet assigned;
+// see WaitAddress method.
+func (m *mockRelations) GetBaseURL() string {
+	if m.echo.Listener != nil {
+		if addr := m.echo.Listener.Addr().String(); addr != "" {
+			return fmt.Sprintf("http://%s/api/relations/v1", addr)
+		}
+	}
+	return ""
+}
+
+func (m *mockRelations) checkHandler(c echo.Context) error {
+	var input api_relations.CheckRequest
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	m.lock.Lock()
+	_, allowed := m.relationships[Relationship{
+		Subject:  input.Subject,
+		Relation: input.Relation,
+		Resource: input.Resource,
+	}]
+	m.lock.Unlock()
+	return c.JSON(http.StatusOK, &api_relations.CheckResponse{Allowed: allowed})
+}
+
+func (m *mockRelations) lookupResourcesHandler(c echo.Context) error {
+	var input api_relations.LookupResourcesRequest
+	if err := c.Bind(&input); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	output := api_relations.LookupResourcesResponse{
+		Resources: []api_relations.ObjectReference{},
+	}
+	m.lock.Lock()
+	for r := range m.relationships {
+		if r.Subject == input.Subject &&
+			r.Relation == input.Relation &&
+			r.Resource.Type == input.ResourceType {
+			output.Resources = append(output.Resources, r.Resource)
+		}
+	}
+	m.lock.Unlock()
+	return c.JSON(http.StatusOK, &output)
+}
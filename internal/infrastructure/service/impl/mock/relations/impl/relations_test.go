@@ -0,0 +1,114 @@
+package impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api_relations "github.com/podengo-project/idmsvc-backend/internal/api/relations"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBaseURL = "http://localhost:8021/api/relations/v1"
+
+func helperConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Clients.RelationsBaseURL = testBaseURL
+	return cfg
+}
+
+func helperPost(t *testing.T, h http.Handler, path string, input any, output any) int {
+	body, err := json.Marshal(input)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, testBaseURL+path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), output))
+	}
+	return rec.Code
+}
+
+func TestNewRelationsMockGuards(t *testing.T) {
+	assert.PanicsWithValue(t, "ctx is nil", func() {
+		newRelationsMockGuards(nil, nil)
+	})
+	ctx := context.Background()
+	assert.PanicsWithValue(t, "cfg is nil", func() {
+		newRelationsMockGuards(ctx, nil)
+	})
+	assert.PanicsWithValue(t, "Config.Clients.RelationsBaseURL is an empty string", func() {
+		newRelationsMockGuards(ctx, &config.Config{})
+	})
+
+	cfg := &config.Config{}
+	cfg.Clients.RelationsBaseURL = "\n"
+	assert.PanicsWithValue(t, "error parsing relations client url: parse \"\\n\": net/url: invalid control character in URL", func() {
+		NewRelationsMock(ctx, cfg)
+	})
+}
+
+func TestRelationsMockCheck(t *testing.T) {
+	var output api_relations.CheckResponse
+	_, m := NewRelationsMock(context.Background(), helperConfig())
+	principal := api_relations.ObjectReference{Type: api_relations.TypePrincipal, ID: "jdoe"}
+	domain := api_relations.ObjectReference{Type: api_relations.TypeDomain, ID: "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"}
+	input := api_relations.CheckRequest{
+		Subject:  principal,
+		Relation: "idmsvc_domains_read",
+		Resource: domain,
+	}
+
+	require.Equal(t, http.StatusOK, helperPost(t, m.Handler(), api_relations.PathCheck, &input, &output))
+	assert.False(t, output.Allowed)
+
+	m.AddRelationship(Relationship{Subject: principal, Relation: "idmsvc_domains_read", Resource: domain})
+	require.Equal(t, http.StatusOK, helperPost(t, m.Handler(), api_relations.PathCheck, &input, &output))
+	assert.True(t, output.Allowed)
+
+	m.Reset()
+	require.Equal(t, http.StatusOK, helperPost(t, m.Handler(), api_relations.PathCheck, &input, &output))
+	assert.False(t, output.Allowed)
+}
+
+func TestRelationsMockLookupResources(t *testing.T) {
+	var output api_relations.LookupResourcesResponse
+	_, m := NewRelationsMock(context.Background(), helperConfig())
+	principal := api_relations.ObjectReference{Type: api_relations.TypePrincipal, ID: "jdoe"}
+	domain := api_relations.ObjectReference{Type: api_relations.TypeDomain, ID: "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"}
+	m.AddRelationship(Relationship{Subject: principal, Relation: "idmsvc_domains_read", Resource: domain})
+	m.AddRelationship(Relationship{Subject: principal, Relation: "idmsvc_domains_update", Resource: domain})
+	m.AddRelationship(Relationship{
+		Subject:  principal,
+		Relation: "idmsvc_domains_read",
+		Resource: api_relations.ObjectReference{Type: api_relations.TypeWorkspace, ID: "12345"},
+	})
+
+	input := api_relations.LookupResourcesRequest{
+		Subject:      principal,
+		Relation:     "idmsvc_domains_read",
+		ResourceType: api_relations.TypeDomain,
+	}
+	require.Equal(t, http.StatusOK, helperPost(t, m.Handler(), api_relations.PathLookupResources, &input, &output))
+	assert.Equal(t, []api_relations.ObjectReference{domain}, output.Resources)
+}
+
+func TestRelationsMockStartStop(t *testing.T) {
+	cfg := helperConfig()
+	cfg.Clients.RelationsBaseURL = "http://localhost:0/api/relations/v1"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv, m := NewRelationsMock(ctx, cfg)
+	assert.Equal(t, "", m.GetBaseURL())
+	require.NoError(t, srv.Start())
+	defer srv.Stop()
+	require.NoError(t, m.WaitAddress(3*time.Second))
+	assert.NotEqual(t, "", m.GetBaseURL())
+}
@@ -0,0 +1,107 @@
+package impl
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/service"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	usecase_repository "github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
+	usecase_webhook "github.com/podengo-project/idmsvc-backend/internal/usecase/webhook"
+	"gorm.io/gorm"
+)
+
+type webhooksService struct {
+	context    context.Context
+	cancel     context.CancelFunc
+	waitGroup  *sync.WaitGroup
+	config     *config.Config
+	db         *gorm.DB
+	dispatcher *usecase_webhook.Dispatcher
+}
+
+// NewWebhooks create the service which deliver periodically the
+// pending webhook deliveries.
+func NewWebhooks(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, db *gorm.DB, m *metrics.Metrics) service.ApplicationService {
+	if cfg == nil {
+		panic("config is nil")
+	}
+	if wg == nil {
+		panic("wg is nil")
+	}
+	if db == nil {
+		panic("db is nil")
+	}
+	if m == nil {
+		panic("metrics is nil")
+	}
+
+	result := &webhooksService{}
+	result.context, result.cancel = context.WithCancel(ctx)
+	result.waitGroup = wg
+	result.config = cfg
+	result.db = db
+	result.dispatcher = usecase_webhook.NewDispatcher(cfg, usecase_repository.NewWebhookRepository(), m)
+	return result
+}
+
+func (srv *webhooksService) Start() error {
+	if !srv.config.Application.Webhooks.Enabled {
+		slog.Info("Webhook deliveries are disabled")
+		return nil
+	}
+	srv.waitGroup.Add(1)
+	go func() {
+		defer srv.waitGroup.Done()
+		interval := srv.config.Application.Webhooks.Interval
+		if interval <= 0 {
+			interval = config.DefaultWebhooksInterval
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			srv.deliver()
+			select {
+			case <-srv.context.Done():
+				slog.Info("Shutting down webhooksService")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+func (srv *webhooksService) Stop() error {
+	srv.cancel()
+	return nil
+}
+
+// deliver attempt the pending deliveries which are due, one batch
+// at a time, until there is none left or the service is
+// stopped.
+func (srv *webhooksService) deliver() {
+	logger := slog.Default().With(slog.String("service", "webhooks"))
+	for srv.context.Err() == nil {
+		count, err := srv.dispatch(logger)
+		if err != nil {
+			logger.Error("failed to deliver the webhook events", slog.String("error", err.Error()))
+			return
+		}
+		if count == 0 {
+			return
+		}
+	}
+}
+
+// dispatch attempt one batch of deliveries; the dispatcher claims
+// them in a transaction, which keeps them from the other instances,
+// and saves every attempt in its own transaction.
+func (srv *webhooksService) dispatch(logger *slog.Logger) (count int, err error) {
+	ctx := app_context.CtxWithLog(app_context.CtxWithDB(srv.context, srv.db.WithContext(srv.context)), logger)
+	return srv.dispatcher.Dispatch(ctx)
+}
@@ -0,0 +1,98 @@
+package domain_token
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DomainNameMaxLength is the max length of the domain name claim.
+	DomainNameMaxLength = 253
+	// LabelMaxLength is the max number of characters of the label claim.
+	LabelMaxLength = 64
+)
+
+// Tags of the claims; the claims are encoded in the order of their tags.
+const (
+	claimDomainName            byte = 1
+	claimSubscriptionManagerId byte = 2
+	claimLabel                 byte = 3
+)
+
+// Claims are the optional restrictions bound to a token; the zero value
+// does not restrict the registration.
+type Claims struct {
+	// DomainName is the only domain name the token can register.
+	DomainName string
+	// SubscriptionManagerId is the only server that can register the
+	// domain with the token.
+	SubscriptionManagerId uuid.UUID
+	// Label is a note set by the user who requested the token.
+	Label string
+}
+
+// IsEmpty return true when no claim is set.
+func (c *Claims) IsEmpty() bool {
+	return *c == Claims{}
+}
+
+// encode the claims as a sequence of tag, length and value; the length
+// is an unsigned varint.
+func (c *Claims) encode() (data []byte, err error) {
+	if len(c.DomainName) > DomainNameMaxLength {
+		return nil, fmt.Errorf("Domain name claim exceeds %d bytes", DomainNameMaxLength)
+	}
+	if utf8.RuneCountInString(c.Label) > LabelMaxLength {
+		return nil, fmt.Errorf("Label claim exceeds %d characters", LabelMaxLength)
+	}
+	if c.DomainName != "" {
+		data = appendClaim(data, claimDomainName, []byte(c.DomainName))
+	}
+	if c.SubscriptionManagerId != uuid.Nil {
+		data = appendClaim(data, claimSubscriptionManagerId, c.SubscriptionManagerId[:])
+	}
+	if c.Label != "" {
+		data = appendClaim(data, claimLabel, []byte(c.Label))
+	}
+	return data, nil
+}
+
+// appendClaim append the tag, the length and the value of a claim.
+func appendClaim(data []byte, tag byte, value []byte) []byte {
+	data = append(data, tag)
+	data = binary.AppendUvarint(data, uint64(len(value)))
+	return append(data, value...)
+}
+
+// decodeClaims parse the claims of a token; every tag is expected once,
+// in ascending order.
+func decodeClaims(data []byte) (claims Claims, err error) {
+	var last byte
+	for len(data) > 0 {
+		tag := data[0]
+		length, n := binary.Uvarint(data[1:])
+		if n <= 0 || tag <= last || length > uint64(len(data)-1-n) {
+			return Claims{}, fmt.Errorf("Invalid token claims")
+		}
+		data = data[1+n:]
+		value := data[:length]
+		switch tag {
+		case claimDomainName:
+			claims.DomainName = string(value)
+		case claimSubscriptionManagerId:
+			if claims.SubscriptionManagerId, err = uuid.FromBytes(value); err != nil {
+				return Claims{}, fmt.Errorf("Invalid token claims")
+			}
+		case claimLabel:
+			claims.Label = string(value)
+		default:
+			return Claims{}, fmt.Errorf("Unknown token claim %d", tag)
+		}
+		last = tag
+		data = data[length:]
+	}
+	return claims, nil
+}
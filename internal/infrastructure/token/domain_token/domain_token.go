@@ -9,6 +9,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -18,10 +19,34 @@ import (
 
 type DomainRegistrationToken string
 
+const (
+	// TokenVersion1 is the format of the tokens without claims, whose
+	// payload is only the expiration time stamp.
+	TokenVersion1 = 1
+	// TokenVersion2 starts the payload with the version, followed by
+	// the expiration time stamp and the claims.
+	TokenVersion2 = 2
+	// TokenMaxLength is the max length of a token with every claim:
+	// a payload of 542 bytes and a signature of 32 bytes, in base64.
+	TokenMaxLength = 768
+	// expirationLength is the length of the expiration time stamp,
+	// which is the whole payload of the version 1 format.
+	expirationLength = 8
+)
+
 var (
 	// uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://console.redhat.com/api/idmsvc"))
 	NamespaceIDMSVC           = uuid.MustParse("2978cc95-31c8-503d-ba8f-581911b6bea0")
 	RegisterDomainPersonality = []byte("register domain")
+	// RegisterDomainV2Personality binds the MAC of the version 2
+	// tokens, so a payload cannot be read as the other version.
+	RegisterDomainV2Personality = []byte("register domain v2")
+	// ErrTokenExpired is returned when the token is valid but it
+	// has expired.
+	ErrTokenExpired = errors.New("Token has expired")
+	// ErrSignatureMismatch is returned when the token was not signed
+	// by the key.
+	ErrSignatureMismatch = errors.New("Signature mismatch")
 )
 
 // Derive domain id from token string
@@ -34,25 +59,60 @@ func TokenDomainId(token DomainRegistrationToken) uuid.UUID {
 // now + validity duration.
 func NewDomainRegistrationToken(
 	key []byte, domainType string, orgID string, validity time.Duration,
+) (token DomainRegistrationToken, expireNS uint64, err error) {
+	return NewDomainRegistrationTokenWithClaims(key, domainType, orgID, validity, Claims{})
+}
+
+// Create a new domain registration token restricted by *claims*
+// The token is signed by *key*, bound to *orgID*, and validate until
+// now + validity duration. The token has the version 2 format when
+// there is any claim, else the version 1 format.
+func NewDomainRegistrationTokenWithClaims(
+	key []byte, domainType string, orgID string, validity time.Duration, claims Claims,
 ) (token DomainRegistrationToken, expireNS uint64, err error) {
 	expireNS = uint64(time.Now().UnixNano() + validity.Nanoseconds())
-	tok, err := newDomainRegistrationTokenAt(key, domainType, orgID, uint64(expireNS))
-	return tok, expireNS, err
+	if claims.IsEmpty() {
+		// The version 1 tokens are accepted by the releases which
+		// do not know the version 2 yet.
+		tok, err := newDomainRegistrationTokenAt(key, domainType, orgID, expireNS)
+		if err != nil {
+			return "", 0, err
+		}
+		return tok, expireNS, nil
+	}
+	tok, err := newDomainRegistrationTokenV2At(key, domainType, orgID, expireNS, claims)
+	if err != nil {
+		return "", 0, err
+	}
+	return tok, expireNS, nil
 }
 
-// Create a domain registration token that expires at *expireNS* nanoseconds
-// after Unix epoch.
+// Create a version 1 domain registration token, without claims, that
+// expires at *expireNS* nanoseconds after Unix epoch.
 func newDomainRegistrationTokenAt(
 	key []byte, domainType string, orgID string, expireNS uint64,
 ) (token DomainRegistrationToken, err error) {
-	payload_bytes := make([]byte, 8)
+	payload_bytes := make([]byte, expirationLength)
 	binary.BigEndian.PutUint64(payload_bytes, expireNS)
-	payload_b64 := base64.RawURLEncoding.EncodeToString(payload_bytes)
-
-	sig := mac_digest(key, domainType, orgID, payload_bytes)
-	sig_b64 := base64.RawURLEncoding.EncodeToString(sig)
+	sig := mac_digest(RegisterDomainPersonality, key, domainType, orgID, payload_bytes)
+	return encodeToken(payload_bytes, sig), nil
+}
 
-	return DomainRegistrationToken(fmt.Sprintf("%s.%s", payload_b64, sig_b64)), nil
+// Create a version 2 domain registration token that expires at
+// *expireNS* nanoseconds after Unix epoch, restricted by *claims*.
+func newDomainRegistrationTokenV2At(
+	key []byte, domainType string, orgID string, expireNS uint64, claims Claims,
+) (token DomainRegistrationToken, err error) {
+	var claims_bytes []byte
+	if claims_bytes, err = claims.encode(); err != nil {
+		return "", err
+	}
+	payload_bytes := make([]byte, 1+expirationLength, 1+expirationLength+len(claims_bytes))
+	payload_bytes[0] = TokenVersion2
+	binary.BigEndian.PutUint64(payload_bytes[1:], expireNS)
+	payload_bytes = append(payload_bytes, claims_bytes...)
+	sig := mac_digest(RegisterDomainV2Personality, key, domainType, orgID, payload_bytes)
+	return encodeToken(payload_bytes, sig), nil
 }
 
 // Verify signature, *orgID* binding, and expiration time stamp of a token.
@@ -60,50 +120,110 @@ func newDomainRegistrationTokenAt(
 func VerifyDomainRegistrationToken(
 	key []byte, domainType string, orgID string, token DomainRegistrationToken,
 ) (domainId uuid.UUID, err error) {
+	domainId, _, err = verifyDomainRegistrationToken(key, domainType, orgID, token)
+	return domainId, err
+}
+
+// Verify a token with every generation of the key, from the newest to
+// the oldest, so the tokens signed before a rotation of the main secret
+// are still accepted.
+// Returns the domain UUID and the claims of the token on success; the
+// claims of a version 1 token are empty.
+func VerifyDomainRegistrationTokenKeys(
+	keys [][]byte, domainType string, orgID string, token DomainRegistrationToken,
+) (domainId uuid.UUID, claims Claims, err error) {
+	if len(keys) == 0 {
+		return uuid.Nil, Claims{}, fmt.Errorf("No key to verify the token")
+	}
+	for _, key := range keys {
+		domainId, claims, err = verifyDomainRegistrationToken(key, domainType, orgID, token)
+		if !errors.Is(err, ErrSignatureMismatch) {
+			return domainId, claims, err
+		}
+	}
+	return uuid.Nil, Claims{}, err
+}
+
+// Verify a token and return the domain UUID and its claims.
+func verifyDomainRegistrationToken(
+	key []byte, domainType string, orgID string, token DomainRegistrationToken,
+) (domainId uuid.UUID, claims Claims, err error) {
 	var expireNS uint64
-	if expireNS, err = parseDomainRegistrationToken(key, domainType, orgID, token); err != nil {
-		return uuid.Nil, err
+	if expireNS, claims, err = parseToken(key, domainType, orgID, token); err != nil {
+		return uuid.Nil, Claims{}, err
 	}
 	var now uint64 = uint64(time.Now().UnixNano())
 	if now > expireNS {
-		return uuid.Nil, fmt.Errorf("Token has expired: %d > %d", now, expireNS)
+		return uuid.Nil, Claims{}, fmt.Errorf("%w: %d > %d", ErrTokenExpired, now, expireNS)
 	}
-	return TokenDomainId(token), nil
+	return TokenDomainId(token), claims, nil
 }
 
 // Parse and check signature of token
 func parseDomainRegistrationToken(
 	key []byte, domainType string, orgID string, token DomainRegistrationToken,
 ) (expireNS uint64, err error) {
+	expireNS, _, err = parseToken(key, domainType, orgID, token)
+	return expireNS, err
+}
+
+// Parse and check signature of a token of any version, and return its
+// expiration and claims.
+func parseToken(
+	key []byte, domainType string, orgID string, token DomainRegistrationToken,
+) (expireNS uint64, claims Claims, err error) {
 	var (
 		payload_bytes []byte
 		sig           []byte
 	)
-	if len(token) > 100 {
-		return 0, fmt.Errorf("Token length exceeded")
+	if len(token) > TokenMaxLength {
+		return 0, Claims{}, fmt.Errorf("Token length exceeded")
 	}
 	parts := strings.Split(string(token), ".")
 	if len(parts) != 2 {
-		return 0, fmt.Errorf("Invalid token")
+		return 0, Claims{}, fmt.Errorf("Invalid token")
 	}
 	if payload_bytes, err = base64.RawURLEncoding.DecodeString(parts[0]); err != nil {
-		return 0, err
+		return 0, Claims{}, err
 	}
 	if sig, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
-		return 0, err
+		return 0, Claims{}, err
 	}
-	expected_sig := mac_digest(key, domainType, orgID, payload_bytes)
-	if !hmac.Equal(sig, expected_sig) {
-		return 0, fmt.Errorf("Signature mismatch")
+
+	switch {
+	case len(payload_bytes) == expirationLength:
+		// version 1 format, without version
+		expected_sig := mac_digest(RegisterDomainPersonality, key, domainType, orgID, payload_bytes)
+		if !hmac.Equal(sig, expected_sig) {
+			return 0, Claims{}, ErrSignatureMismatch
+		}
+		return binary.BigEndian.Uint64(payload_bytes), Claims{}, nil
+	case len(payload_bytes) > expirationLength && payload_bytes[0] == TokenVersion2:
+		expected_sig := mac_digest(RegisterDomainV2Personality, key, domainType, orgID, payload_bytes)
+		if !hmac.Equal(sig, expected_sig) {
+			return 0, Claims{}, ErrSignatureMismatch
+		}
+		if claims, err = decodeClaims(payload_bytes[1+expirationLength:]); err != nil {
+			return 0, Claims{}, err
+		}
+		return binary.BigEndian.Uint64(payload_bytes[1:]), claims, nil
+	default:
+		return 0, Claims{}, fmt.Errorf("Invalid token")
 	}
-	return binary.BigEndian.Uint64(payload_bytes), nil
 }
 
-// Calculate keyed MAC digest from orgID and payload
-func mac_digest(key []byte, domainType string, orgID string, payload []byte) []byte {
+// Encode the payload and the signature of a token
+func encodeToken(payload []byte, sig []byte) DomainRegistrationToken {
+	payload_b64 := base64.RawURLEncoding.EncodeToString(payload)
+	sig_b64 := base64.RawURLEncoding.EncodeToString(sig)
+	return DomainRegistrationToken(fmt.Sprintf("%s.%s", payload_b64, sig_b64))
+}
+
+// Calculate keyed MAC digest from personality, orgID and payload
+func mac_digest(personality []byte, key []byte, domainType string, orgID string, payload []byte) []byte {
 	mac := hmac.New(sha256.New, key)
 	// Hash.Write() never returns an error
-	mac.Write(RegisterDomainPersonality)
+	mac.Write(personality)
 	mac.Write([]byte(domainType))
 	mac.Write([]byte(orgID))
 	mac.Write(payload)
@@ -1,11 +1,14 @@
 package domain_token
 
 import (
+	"encoding/base64"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNamespaceIDMSVC(t *testing.T) {
@@ -35,8 +38,19 @@ func TestNewDomainRegistrationToken(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, token, knownToken)
 
-	_, _, err = NewDomainRegistrationToken(key, domainType, orgId, time.Hour)
+	// the tokens without claims keep the version 1 format
+	token, expireNS, err := NewDomainRegistrationToken(key, domainType, orgId, time.Hour)
 	assert.NoError(t, err)
+	assert.Equal(t, mustNewTokenAt(t, key, domainType, orgId, expireNS), token)
+	token, expireNS, err = NewDomainRegistrationTokenWithClaims(key, domainType, orgId, time.Hour, Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, mustNewTokenAt(t, key, domainType, orgId, expireNS), token)
+}
+
+func mustNewTokenAt(t *testing.T, key []byte, domainType string, orgId string, expireNS uint64) DomainRegistrationToken {
+	token, err := newDomainRegistrationTokenAt(key, domainType, orgId, expireNS)
+	require.NoError(t, err)
+	return token
 }
 
 func TestVerifyDomainRegistrationToken(t *testing.T) {
@@ -89,3 +103,161 @@ func TestVerifyDomainRegistrationToken(t *testing.T) {
 	domainId, err = VerifyDomainRegistrationToken(key, domainType, orgId, token)
 	assert.Error(t, err)
 }
+
+func TestVerifyDomainRegistrationTokenKeys(t *testing.T) {
+	var (
+		domainType string = "rhel-idm"
+		orgId      string = "123456"
+		current    []byte = []byte("currentkey")
+		previous   []byte = []byte("previouskey")
+		other      []byte = []byte("otherkey")
+	)
+	token, _, err := NewDomainRegistrationToken(previous, domainType, orgId, time.Hour)
+	assert.NoError(t, err)
+
+	domainId, _, err := VerifyDomainRegistrationTokenKeys([][]byte{current, previous}, domainType, orgId, token)
+	assert.NoError(t, err)
+	assert.Equal(t, TokenDomainId(token), domainId)
+
+	domainId, _, err = VerifyDomainRegistrationTokenKeys([][]byte{current, other}, domainType, orgId, token)
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+	assert.Equal(t, uuid.Nil, domainId)
+
+	domainId, _, err = VerifyDomainRegistrationTokenKeys(nil, domainType, orgId, token)
+	assert.EqualError(t, err, "No key to verify the token")
+	assert.Equal(t, uuid.Nil, domainId)
+
+	domainId, _, err = VerifyDomainRegistrationTokenKeys([][]byte{current, previous}, domainType, orgId, "invalid")
+	assert.EqualError(t, err, "Invalid token")
+	assert.Equal(t, uuid.Nil, domainId)
+
+	// an expired token is not verified with the older keys
+	token, _, err = NewDomainRegistrationToken(current, domainType, orgId, 0)
+	assert.NoError(t, err)
+	_, _, err = VerifyDomainRegistrationTokenKeys([][]byte{current, previous}, domainType, orgId, token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestDomainRegistrationTokenVersions(t *testing.T) {
+	var (
+		domainType string = "rhel-idm"
+		orgId      string = "123456"
+		key        []byte = []byte("secretkey")
+		expireNS   uint64 = uint64(time.Now().Add(time.Hour).UnixNano())
+	)
+
+	// legacy tokens are still accepted, without claims
+	legacy, err := newDomainRegistrationTokenAt(key, domainType, orgId, expireNS)
+	require.NoError(t, err)
+	domainId, claims, err := VerifyDomainRegistrationTokenKeys([][]byte{key}, domainType, orgId, legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, TokenDomainId(legacy), domainId)
+	assert.Equal(t, Claims{}, claims)
+
+	// version 2 without claims is compact
+	token, err := newDomainRegistrationTokenV2At(key, domainType, orgId, expireNS, Claims{})
+	require.NoError(t, err)
+	assert.Len(t, token, len(legacy)+1)
+	exp, err := parseDomainRegistrationToken(key, domainType, orgId, token)
+	assert.NoError(t, err)
+	assert.Equal(t, expireNS, exp)
+
+	// the payload of a version is not valid for the other
+	legacyParts := strings.Split(string(legacy), ".")
+	parts := strings.Split(string(token), ".")
+	_, err = VerifyDomainRegistrationToken(key, domainType, orgId, DomainRegistrationToken(legacyParts[0]+"."+parts[1]))
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+	_, err = VerifyDomainRegistrationToken(key, domainType, orgId, DomainRegistrationToken(parts[0]+"."+legacyParts[1]))
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	// unknown version
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	payload[0] = 3
+	_, err = VerifyDomainRegistrationToken(key, domainType, orgId, DomainRegistrationToken(base64.RawURLEncoding.EncodeToString(payload)+"."+parts[1]))
+	assert.EqualError(t, err, "Invalid token")
+}
+
+func TestDomainRegistrationTokenClaims(t *testing.T) {
+	var (
+		domainType string = "rhel-idm"
+		orgId      string = "123456"
+		key        []byte = []byte("secretkey")
+		rhsmId            = uuid.MustParse("c4e6a9a2-2b0e-4c87-a1cb-24a6d0d6c5f1")
+	)
+	expected := Claims{
+		DomainName:            "mydomain.example",
+		SubscriptionManagerId: rhsmId,
+		Label:                 "production realm",
+	}
+	token, _, err := NewDomainRegistrationTokenWithClaims(key, domainType, orgId, time.Hour, expected)
+	require.NoError(t, err)
+	domainId, claims, err := VerifyDomainRegistrationTokenKeys([][]byte{key}, domainType, orgId, token)
+	assert.NoError(t, err)
+	assert.Equal(t, TokenDomainId(token), domainId)
+	assert.Equal(t, expected, claims)
+
+	// every claim is optional
+	token, _, err = NewDomainRegistrationTokenWithClaims(key, domainType, orgId, time.Hour, Claims{SubscriptionManagerId: rhsmId})
+	require.NoError(t, err)
+	_, claims, err = VerifyDomainRegistrationTokenKeys([][]byte{key}, domainType, orgId, token)
+	assert.NoError(t, err)
+	assert.Equal(t, Claims{SubscriptionManagerId: rhsmId}, claims)
+
+	// the claims are covered by the signature
+	parts := strings.Split(string(token), ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	require.NoError(t, err)
+	payload[len(payload)-1] ^= 0xff
+	_, err = VerifyDomainRegistrationToken(key, domainType, orgId, DomainRegistrationToken(base64.RawURLEncoding.EncodeToString(payload)+"."+parts[1]))
+	assert.ErrorIs(t, err, ErrSignatureMismatch)
+
+	// the token with the longest claims fits in the max length; the
+	// label is limited in characters, up to 4 bytes each
+	longest := Claims{
+		DomainName:            strings.Repeat("a", DomainNameMaxLength),
+		SubscriptionManagerId: rhsmId,
+		Label:                 strings.Repeat("\U0001F510", LabelMaxLength),
+	}
+	token, _, err = NewDomainRegistrationTokenWithClaims(key, domainType, orgId, time.Hour, longest)
+	require.NoError(t, err)
+	assert.Len(t, token, TokenMaxLength-1)
+	_, claims, err = VerifyDomainRegistrationTokenKeys([][]byte{key}, domainType, orgId, token)
+	assert.NoError(t, err)
+	assert.Equal(t, longest, claims)
+
+	_, _, err = NewDomainRegistrationTokenWithClaims(key, domainType, orgId, time.Hour, Claims{DomainName: strings.Repeat("a", DomainNameMaxLength+1)})
+	assert.EqualError(t, err, "Domain name claim exceeds 253 bytes")
+	_, _, err = NewDomainRegistrationTokenWithClaims(key, domainType, orgId, time.Hour, Claims{Label: strings.Repeat("b", LabelMaxLength+1)})
+	assert.EqualError(t, err, "Label claim exceeds 64 characters")
+}
+
+func TestDecodeClaims(t *testing.T) {
+	claims, err := decodeClaims(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Claims{}, claims)
+
+	claims, err = decodeClaims([]byte{claimDomainName, 3, 'a', '.', 'b', claimLabel, 1, 'x'})
+	assert.NoError(t, err)
+	assert.Equal(t, Claims{DomainName: "a.b", Label: "x"}, claims)
+
+	// the lengths from 128 bytes take two bytes
+	name := strings.Repeat("a", 200)
+	claims, err = decodeClaims(append([]byte{claimDomainName, 0xc8, 0x01}, name...))
+	assert.NoError(t, err)
+	assert.Equal(t, Claims{DomainName: name}, claims)
+
+	for name, data := range map[string][]byte{
+		"truncated header": {claimDomainName},
+		"truncated length": {claimDomainName, 0x80},
+		"truncated value":  {claimDomainName, 5, 'a'},
+		"wrong order":      {claimLabel, 1, 'x', claimDomainName, 1, 'a'},
+		"duplicated":       {claimLabel, 1, 'x', claimLabel, 1, 'y'},
+		"invalid rhsm id":  {claimSubscriptionManagerId, 2, 1, 2},
+	} {
+		_, err = decodeClaims(data)
+		assert.EqualError(t, err, "Invalid token claims", name)
+	}
+	_, err = decodeClaims([]byte{9, 0})
+	assert.EqualError(t, err, "Unknown token claim 9")
+}
@@ -90,10 +90,12 @@ func (hc *HostconfJwk) GetPublicJWK() (pubkey jwk.Key, state hostconf_jwk.KeySta
 }
 
 // Get private key state (invalid, expired, revoked, mismatch, valid)
+// The private key is valid when it is encrypted with any generation of
+// the secrets.
 func (hc *HostconfJwk) GetPrivateKeyState(secrets secrets.AppSecrets) (state hostconf_jwk.KeyState, err error) {
 	state, err = hc.GetPublicKeyState()
 	if state == hostconf_jwk.ValidKey {
-		if hc.EncryptionId != secrets.HostconfEncryptionId {
+		if _, ok := secrets.HostconfEncryptionKeyFor(hc.EncryptionId); !ok {
 			return hostconf_jwk.EncryptionIdMismatch, ErrKeyDecryptionFailed
 		}
 	}
@@ -101,17 +103,47 @@ func (hc *HostconfJwk) GetPrivateKeyState(secrets secrets.AppSecrets) (state hos
 }
 
 // Decrypt and return private jwk.Key from entry
-// Fails if key is invalid, expired, revoked, or not encrypted with secret.
+// The encryption key is picked by the encryption id of the entry.
+// Fails if key is invalid, expired, revoked, or not encrypted with any
+// generation of the secrets.
 func (hc *HostconfJwk) GetPrivateJWK(secrets secrets.AppSecrets) (privkey jwk.Key, state hostconf_jwk.KeyState, err error) {
 	if state, err = hc.GetPrivateKeyState(secrets); err != nil {
 		return nil, state, err
 	}
-	if privkey, err = hostconf_jwk.DecryptJWK(secrets.HostConfEncryptionKey, hc.EncryptedJwk); err != nil {
+	key, _ := secrets.HostconfEncryptionKeyFor(hc.EncryptionId)
+	if privkey, err = hostconf_jwk.DecryptJWK(key, hc.EncryptedJwk); err != nil {
 		return nil, hostconf_jwk.KeyDecryptionFailed, err
 	}
 	return privkey, state, err
 }
 
+// Reencrypt decrypts the private key with the generation of the secrets
+// it was encrypted with, and encrypts it with the current one.
+// Fails if key is revoked, or not encrypted with any generation of the
+// secrets.
+func (hc *HostconfJwk) Reencrypt(secrets secrets.AppSecrets) (err error) {
+	var (
+		encryptedJwk []byte
+		privkey      jwk.Key
+	)
+	if hc.EncryptedJwk == nil {
+		return ErrRevokedKey
+	}
+	key, ok := secrets.HostconfEncryptionKeyFor(hc.EncryptionId)
+	if !ok {
+		return ErrKeyDecryptionFailed
+	}
+	if privkey, err = hostconf_jwk.DecryptJWK(key, hc.EncryptedJwk); err != nil {
+		return err
+	}
+	if encryptedJwk, err = hostconf_jwk.EncryptJWK(secrets.HostConfEncryptionKey, privkey); err != nil {
+		return err
+	}
+	hc.EncryptedJwk = encryptedJwk
+	hc.EncryptionId = secrets.HostconfEncryptionId
+	return nil
+}
+
 // Revoke sets the encrypted private key to nil and marks the hostconf JWK
 // as revoked.
 func (hc *HostconfJwk) Revoke() (err error) {
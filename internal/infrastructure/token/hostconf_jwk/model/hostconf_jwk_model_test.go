@@ -86,6 +86,10 @@ func TestHostconfJwkMethods(t *testing.T) {
 	sec2, err := secrets.NewAppSecrets("MLaVBnV5kadqAasiUmtEwg")
 	assert.Nil(t, err)
 
+	// rotated secret, sec is the previous generation
+	sec3, err := secrets.NewAppSecrets("MLaVBnV5kadqAasiUmtEwg", "3cBBUQSnlKHQO7-5hyxJRQ")
+	assert.Nil(t, err)
+
 	privkey, err := hostconf_jwk.GeneratePrivateJWK(expiresFuture)
 	assert.Nil(t, err)
 	encryptedJwk, err := hostconf_jwk.EncryptJWK(sec.HostConfEncryptionKey, privkey)
@@ -232,6 +236,25 @@ func TestHostconfJwkMethods(t *testing.T) {
 				PrivError: ErrKeyDecryptionFailed,
 			},
 		},
+		{
+			Name: "Private key encrypted with a previous secret",
+			Given: TestCaseGiven{
+				HC: &HostconfJwk{
+					KeyId:        kid,
+					ExpiresAt:    expiresFuture,
+					PublicJwk:    string(pubkeybytes),
+					EncryptionId: sec.HostconfEncryptionId,
+					EncryptedJwk: encryptedJwk,
+				},
+				Secret: sec3,
+			},
+			Expected: TestCaseExpected{
+				PubState:  hostconf_jwk.ValidKey,
+				PubError:  nil,
+				PrivState: hostconf_jwk.ValidKey,
+				PrivError: nil,
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Log(testCase.Name)
@@ -266,3 +289,34 @@ func TestHostconfJwkMethods(t *testing.T) {
 		}
 	}
 }
+
+func TestHostconfJwkReencrypt(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	previous, err := secrets.NewAppSecrets("3cBBUQSnlKHQO7-5hyxJRQ")
+	require.NoError(t, err)
+	current, err := secrets.NewAppSecrets("MLaVBnV5kadqAasiUmtEwg", "3cBBUQSnlKHQO7-5hyxJRQ")
+	require.NoError(t, err)
+	other, err := secrets.NewAppSecrets("Y2HhBTSqfDz4W5nkyU7d2A")
+	require.NoError(t, err)
+
+	hc, err := NewHostconfJwk(*previous, expiresAt)
+	require.NoError(t, err)
+	privkey, _, err := hc.GetPrivateJWK(*previous)
+	require.NoError(t, err)
+
+	// unknown generation
+	assert.Equal(t, ErrKeyDecryptionFailed, hc.Reencrypt(*other))
+
+	require.NoError(t, hc.Reencrypt(*current))
+	assert.Equal(t, current.HostconfEncryptionId, hc.EncryptionId)
+	reencrypted, state, err := hc.GetPrivateJWK(*current)
+	require.NoError(t, err)
+	assert.Equal(t, hostconf_jwk.ValidKey, state)
+	assert.Equal(t, privkey, reencrypted)
+	_, state, err = hc.GetPrivateJWK(*previous)
+	assert.Equal(t, hostconf_jwk.EncryptionIdMismatch, state)
+	assert.Equal(t, ErrKeyDecryptionFailed, err)
+
+	require.NoError(t, hc.Revoke())
+	assert.Equal(t, ErrRevokedKey, hc.Reencrypt(*current))
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGormPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+	})
+
+	mock, db, err := test.NewSqlMock(nil)
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewGormPlugin()))
+	assert.Equal(t, gormPluginName, NewGormPlugin().Name())
+
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+
+	// Successful query is a child of the span in the context
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "domains"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	var count int64
+	require.NoError(t, db.WithContext(ctx).Table("domains").Count(&count).Error)
+
+	// Failed statement set the error status
+	mock.ExpectExec(`DELETE FROM "domains"`).
+		WillReturnError(errors.New("boom"))
+	err = db.WithContext(ctx).Exec(`DELETE FROM "domains"`).Error
+	require.EqualError(t, err, "boom")
+	parent.End()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	assert.Equal(t, "gorm.query", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.sql.table", "domains"))
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "gorm.raw", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,21 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// NewTransport wrap base so every outbound request has a client
+// span and propagates the trace context to the remote service.
+// base is the transport to wrap; nil uses http.DefaultTransport.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "HTTP " + r.Method + " " + r.URL.Host
+		}),
+	)
+}
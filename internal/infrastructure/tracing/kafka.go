@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// KafkaHeadersCarrier adapt the headers of a kafka message to
+// inject and extract the trace context.
+type KafkaHeadersCarrier struct {
+	headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = (*KafkaHeadersCarrier)(nil)
+
+// NewKafkaHeadersCarrier create a carrier for the headers of a
+// kafka message.
+// headers is the reference to the headers of the message, which
+// are updated when the trace context is injected.
+func NewKafkaHeadersCarrier(headers *[]kafka.Header) *KafkaHeadersCarrier {
+	if headers == nil {
+		panic("'headers' is nil")
+	}
+	return &KafkaHeadersCarrier{headers: headers}
+}
+
+// Get return the value for key, or an empty string.
+func (c *KafkaHeadersCarrier) Get(key string) string {
+	for _, header := range *c.headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set replace the value for key, or add the header.
+func (c *KafkaHeadersCarrier) Set(key string, value string) {
+	for i := range *c.headers {
+		if (*c.headers)[i].Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys return the keys of the headers.
+func (c *KafkaHeadersCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, header := range *c.headers {
+		keys = append(keys, header.Key)
+	}
+	return keys
+}
@@ -0,0 +1,28 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaHeadersCarrier(t *testing.T) {
+	assert.PanicsWithValue(t, "'headers' is nil", func() {
+		NewKafkaHeadersCarrier(nil)
+	})
+
+	headers := []kafka.Header{{Key: "x-key", Value: []byte("value")}}
+	carrier := NewKafkaHeadersCarrier(&headers)
+
+	assert.Equal(t, "value", carrier.Get("x-key"))
+	assert.Equal(t, "", carrier.Get("traceparent"))
+
+	carrier.Set("traceparent", "parent-1")
+	assert.Equal(t, "parent-1", carrier.Get("traceparent"))
+	carrier.Set("traceparent", "parent-2")
+	assert.Equal(t, "parent-2", carrier.Get("traceparent"))
+
+	assert.Equal(t, []string{"x-key", "traceparent"}, carrier.Keys())
+	assert.Len(t, headers, 2)
+}
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation name for the spans created by
+// the service.
+const TracerName = "github.com/podengo-project/idmsvc-backend"
+
+// Tracer return the tracer for the spans created by the service.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Init set up the global tracer provider and the propagator for the
+// trace context. The trace context is propagated even when the
+// exporter is 'none', so the service does not break the traces of
+// its callers.
+// ctx is the context used to create the exporter.
+// cfg is the application configuration.
+// Return a function to flush and stop the tracer provider, and nil
+// on success, else nil and an error.
+func Init(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	exporter, err := newExporter(ctx, &cfg.Tracing)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(attribute.String("service.name", cfg.Application.Name)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// newExporter create the span exporter selected at cfg.Exporter.
+// Return nil and nil error when the tracing is disabled.
+func newExporter(ctx context.Context, cfg *config.Tracing) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case config.TracingExporterOTLP:
+		options := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.Endpoint),
+		}
+		if cfg.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, options...)
+	case config.TracingExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case config.TracingExporterNone, "":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("tracing exporter '%s' is not supported", cfg.Exporter)
+	}
+}
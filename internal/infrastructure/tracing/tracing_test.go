@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestInit(t *testing.T) {
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	ctx := context.Background()
+
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		_, _ = Init(ctx, nil)
+	})
+
+	// Tracing disabled keeps the current provider
+	cfg := &config.Config{}
+	cfg.Application.Name = "idmsvc"
+	cfg.Tracing.Exporter = config.TracingExporterNone
+	shutdown, err := Init(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, shutdown)
+	assert.NoError(t, shutdown(ctx))
+	assert.Equal(t, previousProvider, otel.GetTracerProvider())
+	assert.ElementsMatch(t,
+		[]string{"traceparent", "tracestate", "baggage"},
+		otel.GetTextMapPropagator().Fields())
+
+	// Unsupported exporter
+	cfg.Tracing.Exporter = "unknown"
+	shutdown, err = Init(ctx, cfg)
+	assert.EqualError(t, err, "tracing exporter 'unknown' is not supported")
+	assert.Nil(t, shutdown)
+
+	// Stdout exporter
+	cfg.Tracing.Exporter = config.TracingExporterStdout
+	cfg.Tracing.SampleRatio = 1.0
+	shutdown, err = Init(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, shutdown)
+	assert.NotEqual(t, previousProvider, otel.GetTracerProvider())
+	assert.NoError(t, shutdown(ctx))
+
+	// OTLP exporter does not connect until spans are exported
+	cfg.Tracing.Exporter = config.TracingExporterOTLP
+	cfg.Tracing.Endpoint = "localhost:4318"
+	cfg.Tracing.Insecure = true
+	shutdown, err = Init(ctx, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, shutdown)
+	assert.NoError(t, shutdown(ctx))
+}
@@ -0,0 +1,114 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+// ResourceTypeDomain is the type of the domain resources.
+const ResourceTypeDomain = "domain"
+
+// Subject is the identity which request an operation.
+type Subject struct {
+	// OrgID is the organization of the identity.
+	OrgID string
+	// Principal is the user id, service account client id, or
+	// the system common name of the identity.
+	Principal string
+	// XRHID is the raw X-Rh-Identity header, for the providers
+	// which forward it.
+	XRHID string
+}
+
+// Resource is the object of an operation.
+type Resource struct {
+	// Type of the resource, for instance ResourceTypeDomain.
+	Type string
+	// ID of the resource; an empty string refer to the resources
+	// of Type into the organization of the subject, for instance
+	// when creating or listing them.
+	ID string
+}
+
+// ResourceSet is the set of resources where a relation is granted.
+type ResourceSet struct {
+	// All is true when the relation is granted for every resource
+	// into the organization.
+	All bool
+	// IDs are the resources where the relation is granted when
+	// All is false.
+	IDs []string
+}
+
+// Authorizer check the relations of a subject with the resources.
+// The relation is expressed as the permission 'service:resource:verb'
+// which the providers translate to their own model.
+type Authorizer interface {
+	// Check return true when subject has relation on resource.
+	Check(ctx context.Context, subject *Subject, relation string, resource *Resource) (bool, error)
+	// LookupResources return the resources of resourceType where
+	// subject has relation.
+	LookupResources(ctx context.Context, subject *Subject, relation string, resourceType string) (*ResourceSet, error)
+}
+
+// UnavailableError is returned by the providers when the
+// authorization backend is temporarily unavailable, so the request
+// can be retried later.
+type UnavailableError struct {
+	// RetryAfter is the time after which the request could succeed;
+	// zero when it is unknown.
+	RetryAfter time.Duration
+	// Err is the cause, if any.
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Err != nil {
+		return "authorization service is unavailable: " + e.Err.Error()
+	}
+	return "authorization service is unavailable"
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// NewSubject build the subject for a raw X-Rh-Identity header.
+func NewSubject(xrhid string) (*Subject, error) {
+	decoded, err := header.DecodeXRHID(xrhid)
+	if err != nil {
+		return nil, fmt.Errorf("decoding the identity for the authorization: %w", err)
+	}
+	subject := NewSubjectFromIdentity(decoded)
+	subject.XRHID = xrhid
+	return subject, nil
+}
+
+// NewSubjectFromIdentity build the subject for an identity already
+// decoded, as the handlers get it from the request context.
+func NewSubjectFromIdentity(xrhid *identity.XRHID) *Subject {
+	return &Subject{
+		OrgID:     xrhid.Identity.OrgID,
+		Principal: header.GetPrincipal(xrhid),
+		XRHID:     header.EncodeXRHID(xrhid),
+	}
+}
+
+// IsEmpty return true when the relation is not granted for
+// any resource.
+func (s *ResourceSet) IsEmpty() bool {
+	return s == nil || (!s.All && len(s.IDs) == 0)
+}
+
+// Contains return true when the relation is granted for id.
+func (s *ResourceSet) Contains(id string) bool {
+	if s == nil {
+		return false
+	}
+	return s.All || slices.Contains(s.IDs, id)
+}
@@ -0,0 +1,51 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	api_builder "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSubject(t *testing.T) {
+	subject, err := NewSubject("not base64")
+	assert.ErrorContains(t, err, "decoding the identity for the authorization")
+	assert.Nil(t, subject)
+
+	xrhid := api_builder.NewUserXRHID().
+		WithOrgID("12345").
+		WithUserID("jdoe").
+		Build()
+	raw := header.EncodeXRHID(&xrhid)
+	subject, err = NewSubject(raw)
+	require.NoError(t, err)
+	assert.Equal(t, &Subject{
+		OrgID:     "12345",
+		Principal: "jdoe",
+		XRHID:     raw,
+	}, subject)
+	assert.Equal(t, subject, NewSubjectFromIdentity(&xrhid))
+}
+
+func TestResourceSet(t *testing.T) {
+	const domainID = "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"
+	var resources *ResourceSet
+
+	assert.True(t, resources.IsEmpty())
+	assert.False(t, resources.Contains(domainID))
+
+	resources = &ResourceSet{}
+	assert.True(t, resources.IsEmpty())
+	assert.False(t, resources.Contains(domainID))
+
+	resources = &ResourceSet{IDs: []string{domainID}}
+	assert.False(t, resources.IsEmpty())
+	assert.True(t, resources.Contains(domainID))
+	assert.False(t, resources.Contains("other"))
+
+	resources = &ResourceSet{All: true}
+	assert.False(t, resources.IsEmpty())
+	assert.True(t, resources.Contains(domainID))
+}
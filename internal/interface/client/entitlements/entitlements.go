@@ -0,0 +1,15 @@
+package entitlements
+
+import (
+	"context"
+
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+// Entitlements check if an organization can use Directory & Domain
+// Services.
+type Entitlements interface {
+	// IsEntitled return true when the organization of xrhid is
+	// entitled.
+	IsEntitled(ctx context.Context, xrhid *identity.XRHID) (bool, error)
+}
@@ -2,8 +2,35 @@ package rbac
 
 import (
 	"context"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
 )
 
+// ResourceDomainID is the attribute filter key used into the rbac
+// resourceDefinitions to restrict a permission to some domains.
+const ResourceDomainID = "idmsvc.domains.id"
+
 type Rbac interface {
 	IsAllowed(ctx context.Context, xrhid, permission string) (bool, error)
+	// AllowedResources return the set of resources where permission
+	// is granted for the xrhid identity.
+	AllowedResources(ctx context.Context, xrhid, permission string) (*authz.ResourceSet, error)
+}
+
+// ACLInvalidator drop the cached access control lists, so the
+// next permission check retrieve them again from rbac.
+type ACLInvalidator interface {
+	// Invalidate drop the ACL cached for principal into orgID.
+	// When principal is an empty string, the ACLs of every
+	// principal into orgID are dropped.
+	Invalidate(orgID, principal string)
+}
+
+// CircuitBreaker report the state of the circuit breaker which
+// protect the requests to rbac.
+type CircuitBreaker interface {
+	// RetryAfter return the time until the requests to rbac are
+	// allowed again, or zero when they are allowed.
+	RetryAfter() time.Duration
 }
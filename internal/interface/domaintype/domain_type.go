@@ -0,0 +1,73 @@
+package domaintype
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"gorm.io/gorm"
+)
+
+// DomainTypeHandler is implemented by every domain type supported by
+// the service. The repository, interactor, presenter and handler
+// layers look it up at internal/usecase/domaintype and dispatch the
+// type specific work through it, so adding a new type does not
+// require touching those layers.
+//
+// The model hooks (preload and after create) are registered apart
+// with model.RegisterDomainType, as the model package cannot depend
+// on the api types.
+type DomainTypeHandler interface {
+	// Type return the value stored at domains.type.
+	Type() uint
+	// Name return the domain_type used by the API.
+	Name() public.DomainType
+
+	// Create store the type specific records of a new domain.
+	// The domain record already exists, so domain.ID is filled.
+	Create(log *slog.Logger, db *gorm.DB, domain *model.Domain) error
+	// Update replace the type specific records of an existing domain.
+	Update(log *slog.Logger, db *gorm.DB, domain *model.Domain) error
+
+	// TranslateRegister fill the type specific information of
+	// domain from a register request.
+	TranslateRegister(body *public.Domain, domain *model.Domain) error
+	// TranslateUpdateAgent fill the type specific information of
+	// domain from an agent update request.
+	TranslateUpdateAgent(body *public.UpdateDomainAgentRequest, domain *model.Domain) error
+
+	// Fill copy the type specific information from source to target.
+	Fill(target *model.Domain, source *model.Domain) error
+	// ValidateUpdate check that data does not modify the immutable
+	// type specific fields of current. Return a BadRequest error
+	// if it does.
+	ValidateUpdate(current *model.Domain, data *model.Domain) error
+	// EnsureUpdateAgentEnabled check that the system identified by
+	// subscriptionManagerID is an update agent in the provided data.
+	// Return a BadRequest error if it is not.
+	EnsureUpdateAgentEnabled(ctx context.Context, subscriptionManagerID string, data *model.Domain) error
+	// EnsureUpdateAgentAuthorized check that the system identified by
+	// subscriptionManagerID is an update agent of the stored domain.
+	// Return a Forbidden error if it is not.
+	EnsureUpdateAgentAuthorized(ctx context.Context, subscriptionManagerID string, current *model.Domain) error
+
+	// FillRegistration fill the type specific information of the
+	// registration record of a domain pending approval: the fqdn of
+	// the registering server and the CA certificates of the domain,
+	// when the type provides them.
+	// subscriptionManagerID is the id of the registering server.
+	FillRegistration(subscriptionManagerID string, domain *model.Domain, registration *model.DomainRegistration) error
+
+	// ServerCount return the number of servers of the domain, which
+	// is limited by the quota of the organization.
+	ServerCount(domain *model.Domain) int
+
+	// PresentDomain fill the type specific information of the
+	// domain resource.
+	PresentDomain(domain *model.Domain, output *public.Domain) error
+	// PresentHostConf fill the type specific information of the
+	// host-conf response. When location is not nil, the servers are
+	// selected with model.SelectLocation.
+	PresentHostConf(domain *model.Domain, location *model.HostLocation, output *public.HostConfResponse) error
+}
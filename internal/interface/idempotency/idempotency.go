@@ -0,0 +1,43 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is the request and the response stored for an idempotency
+// key.
+type Record struct {
+	// Fingerprint identify the request which used the key first.
+	Fingerprint string
+	// StatusCode is the status of the response; zero while the
+	// first request is in progress.
+	StatusCode int
+	// ContentType is the content type of the response.
+	ContentType string
+	// Body is the body of the response.
+	Body []byte
+}
+
+// Completed return true when the response of the first request was
+// stored.
+func (r *Record) Completed() bool {
+	return r != nil && r.StatusCode != 0
+}
+
+// Store keep the responses for the idempotency keys.
+type Store interface {
+	// Begin reserve the key for a request with fingerprint, until
+	// expiresAt. While the request is in progress the key is locked
+	// until lockedUntil; after it, the request is assumed to be lost
+	// and a retry takes the key over.
+	// Return nil when the key was reserved by this request, else
+	// the record stored by the first request; an error is returned
+	// when the backend failed.
+	Begin(ctx context.Context, scope, key, fingerprint string, lockedUntil, expiresAt time.Time) (*Record, error)
+	// Complete store the response for a key reserved by Begin.
+	Complete(ctx context.Context, scope, key string, record *Record) error
+	// Release delete a key reserved by Begin, so the request can
+	// be retried.
+	Release(ctx context.Context, scope, key string) error
+}
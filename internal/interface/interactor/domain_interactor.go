@@ -6,6 +6,7 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 )
 
@@ -13,8 +14,15 @@ type DomainInteractor interface {
 	Delete(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.DeleteDomainParams) (string, uuid.UUID, error)
 	List(xrhid *identity.XRHID, params *api_public.ListDomainsParams) (orgID string, offset, limit int, err error)
 	GetByID(xrhid *identity.XRHID, params *public.ReadDomainParams) (orgID string, err error)
-	Register(domainRegKey []byte, xrhid *identity.XRHID, params *api_public.RegisterDomainParams, body *api_public.Domain) (string, *header.XRHIDMVersion, *model.Domain, error)
+	Register(domainRegKeys [][]byte, xrhid *identity.XRHID, params *api_public.RegisterDomainParams, body *api_public.Domain) (string, *header.XRHIDMVersion, *model.Domain, error)
 	UpdateAgent(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.UpdateDomainAgentParams, body *api_public.UpdateDomainAgentRequest) (string, *header.XRHIDMVersion, *model.Domain, error)
 	UpdateUser(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.UpdateDomainUserParams, body *api_public.UpdateDomainUserRequest) (string, *model.Domain, error)
-	CreateDomainToken(xrhid *identity.XRHID, params *api_public.CreateDomainTokenParams, body *api_public.DomainRegTokenRequest) (orgID string, domainType public.DomainType, err error)
+	CreateDomainToken(xrhid *identity.XRHID, params *api_public.CreateDomainTokenParams, body *api_public.DomainRegTokenRequest) (orgID string, domainType public.DomainType, claims domain_token.Claims, err error)
+	ReadLocationSubnets(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.ReadDomainLocationSubnetsParams) (orgID string, err error)
+	UpdateLocationSubnets(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.UpdateDomainLocationSubnetsParams, body *api_public.LocationSubnets) (orgID string, data []model.DomainLocationSubnet, err error)
+	Export(xrhid *identity.XRHID, params *api_public.ExportDomainsParams) (orgID string, err error)
+	Import(orgID string, doc *api_public.DomainExport) (data []model.Domain, subnets map[uuid.UUID][]model.DomainLocationSubnet, err error)
+	Batch(xrhid *identity.XRHID, params *api_public.BatchDomainsParams, body *api_public.BatchDomainsRequest) (orgID string, atomic bool, data []*model.Domain, err error)
+	ApproveDomain(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.ApproveDomainParams, body *api_public.DomainDecisionRequest) (orgID string, decision *model.DomainRegistration, err error)
+	RejectDomain(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.RejectDomainParams, body *api_public.DomainDecisionRequest) (orgID string, decision *model.DomainRegistration, err error)
 }
@@ -1,6 +1,8 @@
 package interactor
 
 import (
+	"net/netip"
+
 	"github.com/google/uuid"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
@@ -15,6 +17,14 @@ type HostConfOptions struct {
 	DomainId    *uuid.UUID
 	DomainName  *string
 	DomainType  *api_public.DomainType
+	// Location is the location requested by the host.
+	Location *string
+	// ClientSubnet is the address or subnet of the host, used to
+	// find its location when Location is nil.
+	ClientSubnet *netip.Prefix
+	// RestrictLocation only list the servers in the location of
+	// the host, instead of listing them first.
+	RestrictLocation bool
 }
 
 type HostInteractor interface {
@@ -0,0 +1,17 @@
+package interactor
+
+import (
+	"github.com/google/uuid"
+	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+type WebhookInteractor interface {
+	List(xrhid *identity.XRHID, params *api_public.ListWebhooksParams) (orgID string, err error)
+	Create(xrhid *identity.XRHID, params *api_public.CreateWebhookParams, body *api_public.CreateWebhookRequest) (orgID string, data *model.Webhook, err error)
+	GetByID(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.ReadWebhookParams) (orgID string, err error)
+	Update(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.UpdateWebhookParams, body *api_public.UpdateWebhookRequest) (orgID string, data *model.Webhook, err error)
+	Delete(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.DeleteWebhookParams) (orgID string, err error)
+	ListDeliveries(xrhid *identity.XRHID, UUID uuid.UUID, params *api_public.ListWebhookDeliveriesParams) (orgID string, limit int, err error)
+}
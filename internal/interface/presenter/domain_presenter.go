@@ -1,6 +1,9 @@
 package presenter
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
@@ -15,4 +18,6 @@ type DomainPresenter interface {
 	UpdateAgent(domain *model.Domain) (*public.UpdateDomainAgentResponse, error)
 	UpdateUser(domain *model.Domain) (*public.UpdateDomainUserResponse, error)
 	CreateDomainToken(token *repository.DomainRegToken) (*public.DomainRegToken, error)
+	LocationSubnets(data []model.DomainLocationSubnet) (*public.LocationSubnetsResponse, error)
+	Export(orgID string, exportedAt time.Time, data []model.Domain, subnets map[uuid.UUID][]model.DomainLocationSubnet) (*public.ExportDomainsResponse, error)
 }
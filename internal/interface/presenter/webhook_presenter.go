@@ -0,0 +1,13 @@
+package presenter
+
+import (
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+)
+
+type WebhookPresenter interface {
+	List(data []model.Webhook) (*public.ListWebhooksResponse, error)
+	Get(data *model.Webhook) (*public.WebhookResponse, error)
+	Create(data *model.Webhook) (*public.WebhookResponse, error)
+	Deliveries(data []model.WebhookDelivery) (*public.WebhookDeliveriesResponse, error)
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit is the configuration of a token bucket.
+type Limit struct {
+	// Rate is the number of tokens added to the bucket every second.
+	Rate float64
+	// Burst is the capacity of the bucket.
+	Burst int
+}
+
+// IsZero return true when the limit is disabled.
+func (l Limit) IsZero() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Bucket identify a token bucket and its limit.
+type Bucket struct {
+	// Key identify the bucket.
+	Key string
+	// Limit is the configuration of the bucket.
+	Limit Limit
+}
+
+// Limiter keep the token buckets used to limit the requests.
+type Limiter interface {
+	// Take a token from every bucket, which is created full when it
+	// does not exist. The tokens are taken only when every bucket
+	// has one, so a rejected request does not consume the tokens of
+	// the other buckets; the buckets with a zero limit are ignored.
+	// Return -1 and zero when the tokens were taken, or the index of
+	// the first empty bucket and the time until every bucket has a
+	// token; an error is returned when the backend failed.
+	Take(ctx context.Context, buckets []Bucket) (int, time.Duration, error)
+}
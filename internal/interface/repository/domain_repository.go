@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 )
 
 type DomainRegToken struct {
@@ -14,11 +15,12 @@ type DomainRegToken struct {
 	DomainToken  string
 	DomainType   public.DomainType
 	ExpirationNS uint64
+	Claims       domain_token.Claims
 }
 
 // DomainRepository interface
 type DomainRepository interface {
-	List(ctx context.Context, orgID string, offset, limit int) (output []model.Domain, count int64, err error)
+	List(ctx context.Context, orgID string, domainIDs []uuid.UUID, offset, limit int) (output []model.Domain, count int64, err error)
 	// PartialUpdate(ctx context.Context, orgId string, data *model.Domain) (output model.Domain, err error)
 	// Update(ctx context.Context, orgId string, data *model.Domain) (output model.Domain, err error)
 	FindByID(ctx context.Context, orgID string, UUID uuid.UUID) (output *model.Domain, err error)
@@ -26,5 +28,11 @@ type DomainRepository interface {
 	Register(ctx context.Context, orgID string, data *model.Domain) (err error)
 	UpdateAgent(ctx context.Context, orgID string, data *model.Domain) (err error)
 	UpdateUser(ctx context.Context, orgID string, data *model.Domain) (err error)
-	CreateDomainToken(ctx context.Context, key []byte, validity time.Duration, orgID string, domainType public.DomainType) (token *DomainRegToken, err error)
+	CreateDomainToken(ctx context.Context, key []byte, validity time.Duration, orgID string, domainType public.DomainType, claims domain_token.Claims) (token *DomainRegToken, err error)
+	ListLocationSubnets(ctx context.Context, orgID string, UUID uuid.UUID) (output []model.DomainLocationSubnet, err error)
+	UpdateLocationSubnets(ctx context.Context, orgID string, UUID uuid.UUID, data []model.DomainLocationSubnet) (err error)
+	FindOrgSettings(ctx context.Context, orgID string) (output *model.OrgSettings, err error)
+	SaveOrgSettings(ctx context.Context, data *model.OrgSettings) (err error)
+	FindRegistration(ctx context.Context, orgID string, data *model.Domain) (err error)
+	DecideRegistration(ctx context.Context, orgID string, data *model.Domain) (err error)
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
@@ -9,9 +10,15 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
 )
 
+// ErrAutoEnrollmentDisabled is wrapped by the error returned from
+// MatchDomain when some domains match the host, but none of them
+// has the auto-enrollment enabled.
+var ErrAutoEnrollmentDisabled = errors.New("auto-enrollment is disabled for the matching domains")
+
 // HostRepository interface
 type HostRepository interface {
 	MatchDomain(ctx context.Context, options *interactor.HostConfOptions) (output *model.Domain, err error)
+	MatchLocation(ctx context.Context, options *interactor.HostConfOptions, domain *model.Domain) (location *string, err error)
 	// TODO: hack, actual implementation will take gorm.DB argument
 	SignHostConfToken(ctx context.Context, privs []jwk.Key, options *interactor.HostConfOptions, domain *model.Domain) (hctoken public.HostToken, err error)
 }
@@ -15,4 +15,5 @@ type HostconfJwkRepository interface {
 	GetPublicKeyArray(ctx context.Context) (pubkeys, revokedKids []string, err error)
 	// TODO: refactor code to use jwk.Set instead of []jwk.Key
 	GetPrivateSigningKeys(ctx context.Context) (privkeys []jwk.Key, err error)
+	ReencryptJWKs(ctx context.Context) (reencrypted, skipped []model.HostconfJwk, err error)
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+)
+
+// QuotaRepository interface
+type QuotaRepository interface {
+	// FindByOrgID return the quota overrides of the organization,
+	// or nil when it has no overrides.
+	FindByOrgID(ctx context.Context, orgID string) (output *model.OrgQuota, err error)
+	// LockDomains lock the domains count of the organization until
+	// the end of the transaction.
+	LockDomains(ctx context.Context, orgID string) error
+	// CountDomains return the number of domains of the organization.
+	CountDomains(ctx context.Context, orgID string) (count int64, err error)
+	// IncrementHostConf increase the host-conf usage of the
+	// organization for the day when it is under limit, and return
+	// true when it was increased.
+	IncrementHostConf(ctx context.Context, orgID string, day time.Time, limit int) (counted bool, err error)
+	// ReleaseHostConf decrease the host-conf usage of the
+	// organization for the day.
+	ReleaseHostConf(ctx context.Context, orgID string, day time.Time) error
+}
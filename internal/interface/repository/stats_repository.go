@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+)
+
+// StatsRepository interface
+type StatsRepository interface {
+	// DomainStats return the statistics of the domains of all the
+	// organizations at the time now.
+	DomainStats(ctx context.Context, now time.Time) (output *model.DomainStats, err error)
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+)
+
+// WebhookRepository interface
+type WebhookRepository interface {
+	// List return the webhooks of the organization.
+	List(ctx context.Context, orgID string) (output []model.Webhook, err error)
+	// Create add a webhook to the organization.
+	Create(ctx context.Context, orgID string, data *model.Webhook) (err error)
+	// FindByID return a webhook of the organization.
+	FindByID(ctx context.Context, orgID string, UUID uuid.UUID) (output *model.Webhook, err error)
+	// Update save the url, the events and the state of a webhook.
+	Update(ctx context.Context, orgID string, data *model.Webhook) (err error)
+	// DeleteById remove a webhook and its deliveries.
+	DeleteById(ctx context.Context, orgID string, UUID uuid.UUID) (err error)
+	// ListDeliveries return the most recent deliveries of a webhook.
+	ListDeliveries(ctx context.Context, orgID string, UUID uuid.UUID, limit int) (output []model.WebhookDelivery, err error)
+	// Enqueue add a pending delivery of the event for every webhook
+	// of the organization subscribed to it, and return how many.
+	Enqueue(ctx context.Context, orgID string, event string, payload []byte, now time.Time) (count int, err error)
+	// ClaimPending lock and return the pending deliveries which next
+	// attempt is due, with their webhook, and postpone their next
+	// attempt until leaseUntil.
+	ClaimPending(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) (output []model.WebhookDelivery, err error)
+	// RecordAttempt save the result of a delivery attempt into the
+	// consecutive failures and the state of its webhook.
+	RecordAttempt(ctx context.Context, data *model.Webhook, succeeded bool, threshold int, now time.Time) (err error)
+	// UpdateDelivery save the state of a delivery.
+	UpdateDelivery(ctx context.Context, data *model.WebhookDelivery) (err error)
+	// ListSecretsToReencrypt lock and return the webhooks which secret
+	// is not encrypted with the main secret generation encryptionId.
+	ListSecretsToReencrypt(ctx context.Context, encryptionId string) (output []model.Webhook, err error)
+	// ListLegacySecrets lock and return the webhooks without an
+	// encrypted secret, which sign with a secret derived from the
+	// main secret.
+	ListLegacySecrets(ctx context.Context) (output []model.Webhook, err error)
+	// UpdateSecret save the encrypted secret of a webhook.
+	UpdateSecret(ctx context.Context, data *model.Webhook) (err error)
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// domainServersBuckets are the upper bounds for the histogram of
+// servers per domain.
+var domainServersBuckets = []float64{1, 2, 3, 5, 10, 20, 50}
+
+// domainStatsCollector expose the last snapshot of the domain
+// statistics; the snapshot is refreshed periodically out of the
+// scrape, so the scrapes do not query the database.
+type domainStatsCollector struct {
+	mutex sync.RWMutex
+	stats *model.DomainStats
+
+	domains       *prometheus.Desc
+	domainServers *prometheus.Desc
+	certsExpiring *prometheus.Desc
+}
+
+func newDomainStatsCollector() *domainStatsCollector {
+	return &domainStatsCollector{
+		domains: prometheus.NewDesc(
+			prometheus.BuildFQName(NameSpace, "", "domains"),
+			"Number of registered domains",
+			nil, nil,
+		),
+		domainServers: prometheus.NewDesc(
+			prometheus.BuildFQName(NameSpace, "", "domain_servers"),
+			"Distribution of the number of servers per domain",
+			nil, nil,
+		),
+		certsExpiring: prometheus.NewDesc(
+			prometheus.BuildFQName(NameSpace, "", "ca_certs_expiring"),
+			"Number of CA certificates expired or expiring within the period",
+			[]string{"within"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *domainStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.domains
+	ch <- c.domainServers
+	ch <- c.certsExpiring
+}
+
+// Collect implements prometheus.Collector; nothing is collected
+// until the first snapshot is set.
+func (c *domainStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	stats := c.stats
+	c.mutex.RUnlock()
+	if stats == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.domains, prometheus.GaugeValue, float64(stats.Domains))
+
+	buckets := make(map[float64]uint64, len(domainServersBuckets))
+	var sum float64
+	for _, servers := range stats.ServersPerDomain {
+		sum += float64(servers)
+		for _, bound := range domainServersBuckets {
+			if float64(servers) <= bound {
+				buckets[bound]++
+			}
+		}
+	}
+	ch <- prometheus.MustNewConstHistogram(c.domainServers,
+		uint64(len(stats.ServersPerDomain)), sum, buckets)
+
+	ch <- prometheus.MustNewConstMetric(c.certsExpiring, prometheus.GaugeValue,
+		float64(stats.CertsExpiringIn7Days), "7d")
+	ch <- prometheus.MustNewConstMetric(c.certsExpiring, prometheus.GaugeValue,
+		float64(stats.CertsExpiringIn30Days), "30d")
+}
+
+func (c *domainStatsCollector) set(stats *model.DomainStats) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stats = stats
+}
+
+// SetDomainStats replace the snapshot of the domain statistics
+// exposed by the metrics.
+func (m *Metrics) SetDomainStats(stats *model.DomainStats) {
+	m.domainStats.set(stats)
+}
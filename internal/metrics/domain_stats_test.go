@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDomainStats(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics(reg)
+	names := []string{
+		"idmsvc_domains",
+		"idmsvc_domain_servers",
+		"idmsvc_ca_certs_expiring",
+	}
+
+	// Nothing is collected before the first snapshot
+	count, err := testutil.GatherAndCount(reg, names...)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	m.SetDomainStats(&model.DomainStats{
+		Domains:               3,
+		ServersPerDomain:      []int64{1, 4, 30},
+		CertsExpiringIn7Days:  1,
+		CertsExpiringIn30Days: 2,
+	})
+	expected := `
+# HELP idmsvc_ca_certs_expiring Number of CA certificates expired or expiring within the period
+# TYPE idmsvc_ca_certs_expiring gauge
+idmsvc_ca_certs_expiring{within="30d"} 2
+idmsvc_ca_certs_expiring{within="7d"} 1
+# HELP idmsvc_domain_servers Distribution of the number of servers per domain
+# TYPE idmsvc_domain_servers histogram
+idmsvc_domain_servers_bucket{le="1"} 1
+idmsvc_domain_servers_bucket{le="2"} 1
+idmsvc_domain_servers_bucket{le="3"} 1
+idmsvc_domain_servers_bucket{le="5"} 2
+idmsvc_domain_servers_bucket{le="10"} 2
+idmsvc_domain_servers_bucket{le="20"} 2
+idmsvc_domain_servers_bucket{le="50"} 3
+idmsvc_domain_servers_bucket{le="+Inf"} 3
+idmsvc_domain_servers_sum 35
+idmsvc_domain_servers_count 3
+# HELP idmsvc_domains Number of registered domains
+# TYPE idmsvc_domains gauge
+idmsvc_domains 3
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), names...))
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"database/sql"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -19,8 +21,56 @@ type Metrics struct {
 	HTTPRequestHeaderSize *prometheus.HistogramVec
 	// HTTPRequestBodySize is a histogram that measures the size of the HTTP request bodys.
 	HTTPRequestBodySize *prometheus.HistogramVec
+	// RbacCacheHits is a counter of the permission checks resolved with
+	// a cached ACL.
+	RbacCacheHits prometheus.Counter
+	// RbacCacheMisses is a counter of the permission checks that needed
+	// to retrieve the ACL from rbac.
+	RbacCacheMisses prometheus.Counter
+	// RbacRetries is a counter of the requests to rbac that were
+	// retried after a failure.
+	RbacRetries prometheus.Counter
+	// RbacCircuitBreakerState is the state of the rbac circuit
+	// breaker; 0 closed, 1 half-open and 2 open.
+	RbacCircuitBreakerState prometheus.Gauge
+	// RbacCircuitBreakerRejections is a counter of the permission
+	// checks rejected because the rbac circuit breaker is open.
+	RbacCircuitBreakerRejections prometheus.Counter
+	// RateLimitThrottled is a counter of the requests rejected by
+	// the rate limits, by rate class and scope (org or identity).
+	RateLimitThrottled *prometheus.CounterVec
+	// RateLimitErrors is a counter of the requests which could not
+	// be checked against the rate limits because the backend failed;
+	// they are not rejected.
+	RateLimitErrors prometheus.Counter
+	// IdempotencyKeys is a counter of the requests with an
+	// idempotency key, by result (stored, replayed, mismatch,
+	// in_progress, error).
+	IdempotencyKeys *prometheus.CounterVec
+	// DomainRegistrations is a counter of the domain registrations,
+	// by outcome (success or failure) and ipa-hcc agent version.
+	DomainRegistrations *prometheus.CounterVec
+	// DomainAgentUpdates is a counter of the domain updates from the
+	// ipa-hcc agent, by outcome and agent version.
+	DomainAgentUpdates *prometheus.CounterVec
+	// DomainTokens is a counter of the registration tokens created,
+	// by domain type.
+	DomainTokens *prometheus.CounterVec
+	// HostConfs is a counter of the host-conf requests, by result
+	// (issued or refused) and the reason of the refusal.
+	HostConfs *prometheus.CounterVec
+	// RbacDenials is a counter of the requests denied because the
+	// identity has not the permission, by permission.
+	RbacDenials *prometheus.CounterVec
+	// DBQueryDuration is a histogram that measures the duration of
+	// the database statements, by operation and table.
+	DBQueryDuration *prometheus.HistogramVec
+	// WebhookDeliveries is a counter of the delivery attempts to the
+	// webhooks, by result (succeeded, retried, failed or disabled).
+	WebhookDeliveries *prometheus.CounterVec
 
-	reg *prometheus.Registry
+	domainStats *domainStatsCollector
+	reg         *prometheus.Registry
 }
 
 // See: https://prometheus.io/docs/instrumenting/writing_exporters/#naming
@@ -55,13 +105,104 @@ func NewMetrics(reg *prometheus.Registry) *Metrics {
 			// Bucket limited to 128KB
 			Buckets: []float64{1024, 2 * 1024, 4 * 1024, 8 * 1024, 16 * 1024, 32 * 1024, 64 * 1024, 128 * 1024},
 		}, []string{"status", "method", "path"}),
+		RbacCacheHits: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rbac_cache_hits_total",
+			Help:      "Number of permission checks resolved with a cached ACL",
+		}),
+		RbacCacheMisses: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rbac_cache_misses_total",
+			Help:      "Number of permission checks that retrieved the ACL from rbac",
+		}),
+		RbacRetries: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rbac_retries_total",
+			Help:      "Number of requests to rbac retried after a failure",
+		}),
+		RbacCircuitBreakerState: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: NameSpace,
+			Name:      "rbac_circuit_breaker_state",
+			Help:      "State of the rbac circuit breaker: 0 closed, 1 half-open, 2 open",
+		}),
+		RbacCircuitBreakerRejections: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rbac_circuit_breaker_rejections_total",
+			Help:      "Number of permission checks rejected while the rbac circuit breaker is open",
+		}),
+		RateLimitThrottled: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rate_limit_throttled_total",
+			Help:      "Number of requests rejected by the rate limits",
+		}, []string{"class", "scope"}),
+		RateLimitErrors: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rate_limit_errors_total",
+			Help:      "Number of requests not checked against the rate limits because the backend failed",
+		}),
+		IdempotencyKeys: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "idempotency_keys_total",
+			Help:      "Number of requests with an idempotency key",
+		}, []string{"result"}),
+		DomainRegistrations: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "domain_registrations_total",
+			Help:      "Number of domain registrations",
+		}, []string{"outcome", "agent_version"}),
+		DomainAgentUpdates: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "domain_agent_updates_total",
+			Help:      "Number of domain updates from the ipa-hcc agent",
+		}, []string{"outcome", "agent_version"}),
+		DomainTokens: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "domain_tokens_total",
+			Help:      "Number of domain registration tokens created",
+		}, []string{"domain_type"}),
+		HostConfs: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "host_conf_total",
+			Help:      "Number of host-conf requests, issued or refused",
+		}, []string{"result", "reason"}),
+		RbacDenials: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "rbac_denials_total",
+			Help:      "Number of requests denied for missing the rbac permission",
+		}, []string{"permission"}),
+		DBQueryDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: NameSpace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Duration of the database statements",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, []string{"operation", "table"}),
+		WebhookDeliveries: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: NameSpace,
+			Name:      "webhook_deliveries_total",
+			Help:      "Number of delivery attempts to the webhooks",
+		}, []string{"result"}),
+		domainStats: newDomainStatsCollector(),
 	}
 
 	reg.MustRegister(collectors.NewBuildInfoCollector())
+	reg.MustRegister(metrics.domainStats)
 
 	return metrics
 }
 
+// RegisterDBStats expose the statistics of the connection pool of
+// db: open, in use and idle connections, and the waits for a
+// connection.
+// db is the database handle.
+// name is the value for the db_name label.
+// Return nil on success, else an error.
+func (m *Metrics) RegisterDBStats(db *sql.DB, name string) error {
+	if db == nil {
+		panic("'db' is nil")
+	}
+	return m.reg.Register(collectors.NewDBStatsCollector(db, name))
+}
+
 func (m Metrics) Registry() *prometheus.Registry {
 	return m.reg
 }
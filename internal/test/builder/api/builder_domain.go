@@ -14,6 +14,7 @@ type Domain interface {
 	WithDescription(value *string) Domain
 	WithTitle(value *string) Domain
 	WithRhelIdm(value *public.DomainIpa) Domain
+	WithActiveDirectory(value *public.DomainActiveDirectory) Domain
 }
 
 type domain public.Domain
@@ -59,3 +60,10 @@ func (b *domain) WithRhelIdm(value *public.DomainIpa) Domain {
 	b.RhelIdm = value
 	return b
 }
+
+func (b *domain) WithActiveDirectory(value *public.DomainActiveDirectory) Domain {
+	b.DomainType = public.ActiveDirectory
+	b.RhelIdm = nil
+	b.ActiveDirectory = value
+	return b
+}
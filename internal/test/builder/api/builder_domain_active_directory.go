@@ -0,0 +1,84 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	builder_helper "github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"go.openly.dev/pointy"
+)
+
+type ActiveDirectoryDomain interface {
+	Build() *public.DomainActiveDirectory
+	WithForestName(value string) ActiveDirectoryDomain
+	WithDomainControllers(values []public.DomainActiveDirectoryController) ActiveDirectoryDomain
+	AddDomainController(value public.DomainActiveDirectoryController) ActiveDirectoryDomain
+	WithSites(values []public.Location) ActiveDirectoryDomain
+	AddSite(value public.Location) ActiveDirectoryDomain
+	WithUpdateAgents(values []uuid.UUID) ActiveDirectoryDomain
+	AddUpdateAgent(value uuid.UUID) ActiveDirectoryDomain
+}
+
+type activeDirectoryDomain public.DomainActiveDirectory
+
+func NewActiveDirectoryDomain(domainName string) ActiveDirectoryDomain {
+	siteLabel := builder_helper.GenRandLocationLabel()
+	return &activeDirectoryDomain{
+		RealmName:  strings.ToUpper(domainName),
+		ForestName: domainName,
+		DomainControllers: []public.DomainActiveDirectoryController{
+			{
+				Fqdn:          builder_helper.GenRandFQDNWithDomain(domainName),
+				GlobalCatalog: true,
+				Site:          pointy.String(siteLabel),
+			},
+		},
+		Sites: []public.Location{
+			NewLocation().
+				WithName(siteLabel).
+				WithDescription(siteLabel).
+				Build(),
+		},
+		UpdateAgents: []uuid.UUID{uuid.New()},
+	}
+}
+
+func (b *activeDirectoryDomain) Build() *public.DomainActiveDirectory {
+	return (*public.DomainActiveDirectory)(b)
+}
+
+func (b *activeDirectoryDomain) WithForestName(value string) ActiveDirectoryDomain {
+	b.ForestName = value
+	return b
+}
+
+func (b *activeDirectoryDomain) WithDomainControllers(values []public.DomainActiveDirectoryController) ActiveDirectoryDomain {
+	b.DomainControllers = values
+	return b
+}
+
+func (b *activeDirectoryDomain) AddDomainController(value public.DomainActiveDirectoryController) ActiveDirectoryDomain {
+	b.DomainControllers = append(b.DomainControllers, value)
+	return b
+}
+
+func (b *activeDirectoryDomain) WithSites(values []public.Location) ActiveDirectoryDomain {
+	b.Sites = values
+	return b
+}
+
+func (b *activeDirectoryDomain) AddSite(value public.Location) ActiveDirectoryDomain {
+	b.Sites = append(b.Sites, value)
+	return b
+}
+
+func (b *activeDirectoryDomain) WithUpdateAgents(values []uuid.UUID) ActiveDirectoryDomain {
+	b.UpdateAgents = values
+	return b
+}
+
+func (b *activeDirectoryDomain) AddUpdateAgent(value uuid.UUID) ActiveDirectoryDomain {
+	b.UpdateAgents = append(b.UpdateAgents, value)
+	return b
+}
@@ -19,6 +19,10 @@ type RhelIdmDomain interface {
 	AddServer(value public.DomainIpaServer) RhelIdmDomain
 	WithAutomounLocations(values *[]string) RhelIdmDomain
 	AddAutomountLocation(value string) RhelIdmDomain
+	WithTrusts(values *[]public.DomainIpaTrust) RhelIdmDomain
+	AddTrust(value public.DomainIpaTrust) RhelIdmDomain
+	WithIdRanges(values *[]public.DomainIpaIdRange) RhelIdmDomain
+	AddIdRange(value public.DomainIpaIdRange) RhelIdmDomain
 }
 
 type rhelIdmDomain public.DomainIpa
@@ -108,3 +112,29 @@ func (b *rhelIdmDomain) AddAutomountLocation(value string) RhelIdmDomain {
 	*b.AutomountLocations = append(*b.AutomountLocations, value)
 	return b
 }
+
+func (b *rhelIdmDomain) WithTrusts(values *[]public.DomainIpaTrust) RhelIdmDomain {
+	b.Trusts = values
+	return b
+}
+
+func (b *rhelIdmDomain) AddTrust(value public.DomainIpaTrust) RhelIdmDomain {
+	if b.Trusts == nil {
+		b.Trusts = &[]public.DomainIpaTrust{}
+	}
+	*b.Trusts = append(*b.Trusts, value)
+	return b
+}
+
+func (b *rhelIdmDomain) WithIdRanges(values *[]public.DomainIpaIdRange) RhelIdmDomain {
+	b.IdRanges = values
+	return b
+}
+
+func (b *rhelIdmDomain) AddIdRange(value public.DomainIpaIdRange) RhelIdmDomain {
+	if b.IdRanges == nil {
+		b.IdRanges = &[]public.DomainIpaIdRange{}
+	}
+	*b.IdRanges = append(*b.IdRanges, value)
+	return b
+}
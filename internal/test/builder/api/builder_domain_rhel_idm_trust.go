@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	builder_helper "github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"go.openly.dev/pointy"
+)
+
+type DomainIpaTrust interface {
+	Build() public.DomainIpaTrust
+	WithDirection(value public.TrustDirection) DomainIpaTrust
+	WithSid(value string) DomainIpaTrust
+	WithDomains(values []public.DomainIpaTrustDomain) DomainIpaTrust
+	AddDomain(value public.DomainIpaTrustDomain) DomainIpaTrust
+	WithIdRanges(values []public.DomainIpaTrustIdRange) DomainIpaTrust
+	AddIdRange(value public.DomainIpaTrustIdRange) DomainIpaTrust
+}
+
+type domainIpaTrust public.DomainIpaTrust
+
+// NewDomainIpaTrust create a two-way trust with the forest, which
+// contains only the forest root domain and one ID range.
+func NewDomainIpaTrust(forestName string) DomainIpaTrust {
+	flatName := strings.ToUpper(strings.Split(forestName, ".")[0])
+	if len(flatName) > 15 {
+		flatName = flatName[:15]
+	}
+	sid := builder_helper.GenRandDomainSID()
+	return &domainIpaTrust{
+		ForestName: forestName,
+		FlatName:   pointy.String(flatName),
+		Sid:        sid,
+		Direction:  public.TwoWay,
+		Domains: []public.DomainIpaTrustDomain{
+			{
+				Name:     forestName,
+				FlatName: pointy.String(flatName),
+				Sid:      sid,
+			},
+		},
+		IdRanges: []public.DomainIpaTrustIdRange{
+			{
+				Name:      fmt.Sprintf("%s_id_range", strings.ToUpper(forestName)),
+				BaseId:    builder_helper.GenRandNum(1, 2000) * 200000,
+				RangeSize: 200000,
+				Type:      public.IpaAdTrust,
+			},
+		},
+	}
+}
+
+func (b *domainIpaTrust) Build() public.DomainIpaTrust {
+	return public.DomainIpaTrust(*b)
+}
+
+func (b *domainIpaTrust) WithDirection(value public.TrustDirection) DomainIpaTrust {
+	b.Direction = value
+	return b
+}
+
+func (b *domainIpaTrust) WithSid(value string) DomainIpaTrust {
+	b.Sid = value
+	return b
+}
+
+func (b *domainIpaTrust) WithDomains(values []public.DomainIpaTrustDomain) DomainIpaTrust {
+	b.Domains = values
+	return b
+}
+
+func (b *domainIpaTrust) AddDomain(value public.DomainIpaTrustDomain) DomainIpaTrust {
+	b.Domains = append(b.Domains, value)
+	return b
+}
+
+func (b *domainIpaTrust) WithIdRanges(values []public.DomainIpaTrustIdRange) DomainIpaTrust {
+	b.IdRanges = values
+	return b
+}
+
+func (b *domainIpaTrust) AddIdRange(value public.DomainIpaTrustIdRange) DomainIpaTrust {
+	b.IdRanges = append(b.IdRanges, value)
+	return b
+}
@@ -10,6 +10,7 @@ type UpdateDomainAgent interface {
 	WithDomainName(value string) UpdateDomainAgent
 	WithDomainType(value public.DomainType) UpdateDomainAgent
 	WithDomainRhelIdm(value public.DomainIpa) UpdateDomainAgent
+	WithDomainActiveDirectory(value public.DomainActiveDirectory) UpdateDomainAgent
 	WithSubscriptionManagerID(value string) UpdateDomainAgent
 	WithHCCUpdate(value bool) UpdateDomainAgent
 }
@@ -20,7 +21,7 @@ func NewUpdateDomainAgent(domainName string) UpdateDomainAgent {
 	return &updateDomainAgentRequest{
 		DomainName: domainName,
 		DomainType: public.RhelIdm,
-		RhelIdm:    *NewRhelIdmDomain(domainName).Build(),
+		RhelIdm:    NewRhelIdmDomain(domainName).Build(),
 	}
 }
 
@@ -39,19 +40,26 @@ func (b *updateDomainAgentRequest) WithDomainType(value public.DomainType) Updat
 }
 
 func (b *updateDomainAgentRequest) WithDomainRhelIdm(value public.DomainIpa) UpdateDomainAgent {
-	b.RhelIdm = value
+	b.RhelIdm = &value
+	return b
+}
+
+func (b *updateDomainAgentRequest) WithDomainActiveDirectory(value public.DomainActiveDirectory) UpdateDomainAgent {
+	b.DomainType = public.ActiveDirectory
+	b.RhelIdm = nil
+	b.ActiveDirectory = &value
 	return b
 }
 
 func (b *updateDomainAgentRequest) WithSubscriptionManagerID(value string) UpdateDomainAgent {
-	if len(b.RhelIdm.Servers) > 0 {
+	if b.RhelIdm != nil && len(b.RhelIdm.Servers) > 0 {
 		*b.RhelIdm.Servers[0].SubscriptionManagerId = uuid.MustParse(value)
 	}
 	return b
 }
 
 func (b *updateDomainAgentRequest) WithHCCUpdate(value bool) UpdateDomainAgent {
-	if len(b.RhelIdm.Servers) > 0 {
+	if b.RhelIdm != nil && len(b.RhelIdm.Servers) > 0 {
 		b.RhelIdm.Servers[0].HccUpdateServer = value
 	}
 	return b
@@ -5,18 +5,18 @@ import (
 )
 
 type ErrorResponse interface {
-	Build() *public.ErrorResponse
+	Build() *public.ErrorResponseApplicationJSON
 	Add(err public.ErrorInfo) ErrorResponse
 }
 
-type errorResponse public.ErrorResponse
+type errorResponse public.ErrorResponseApplicationJSON
 
 func NewErrorResponse() ErrorResponse {
 	return &errorResponse{}
 }
 
-func (b *errorResponse) Build() *public.ErrorResponse {
-	return (*public.ErrorResponse)(b)
+func (b *errorResponse) Build() *public.ErrorResponseApplicationJSON {
+	return (*public.ErrorResponseApplicationJSON)(b)
 }
 
 func (b *errorResponse) Add(err public.ErrorInfo) ErrorResponse {
@@ -294,3 +294,13 @@ func GenSubjectWithRealm(subject string, realm string) string {
 	// TODO To be reviewed
 	return GenIssuerWithRealm(subject, realm)
 }
+
+// GenRandDomainSID generate a random domain security identifier.
+// Return a string such as "S-1-5-21-1234567890-1234567890-1234567890".
+func GenRandDomainSID() string {
+	return fmt.Sprintf("S-1-5-21-%d-%d-%d",
+		GenRandNum(1, 4294967295),
+		GenRandNum(1, 4294967295),
+		GenRandNum(1, 4294967295),
+	)
+}
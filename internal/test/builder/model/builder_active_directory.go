@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	builder_helper "github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/activedirectory"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+type ActiveDirectoryDomain interface {
+	Build() *activedirectory.ActiveDirectory
+	WithModel(value gorm.Model) ActiveDirectoryDomain
+	WithDomainControllers(value []activedirectory.ActiveDirectoryDomainController) ActiveDirectoryDomain
+	WithSites(value []activedirectory.ActiveDirectorySite) ActiveDirectoryDomain
+	WithRealmName(value *string) ActiveDirectoryDomain
+	WithForestName(value *string) ActiveDirectoryDomain
+	WithUpdateAgents(value pq.StringArray) ActiveDirectoryDomain
+}
+
+type activeDirectoryDomain struct {
+	ActiveDirectoryDomain *activedirectory.ActiveDirectory
+}
+
+func NewActiveDirectoryDomain() ActiveDirectoryDomain {
+	forestName := builder_helper.GenRandDomainName(2)
+	siteName := builder_helper.GenRandLocationLabel()
+	return &activeDirectoryDomain{
+		ActiveDirectoryDomain: &activedirectory.ActiveDirectory{
+			Model:      NewModel().Build(),
+			RealmName:  pointy.String(strings.ToUpper(forestName)),
+			ForestName: pointy.String(forestName),
+			DomainControllers: []activedirectory.ActiveDirectoryDomainController{
+				{
+					FQDN:          builder_helper.GenRandFQDNWithDomain(forestName),
+					Site:          pointy.String(siteName),
+					GlobalCatalog: true,
+				},
+			},
+			Sites: []activedirectory.ActiveDirectorySite{
+				{
+					Name:        siteName,
+					Description: pointy.String(builder_helper.GenRandLocationDescription()),
+				},
+			},
+			UpdateAgents: pq.StringArray{uuid.NewString()},
+		},
+	}
+}
+
+func (b *activeDirectoryDomain) Build() *activedirectory.ActiveDirectory {
+	return b.ActiveDirectoryDomain
+}
+
+func (b *activeDirectoryDomain) WithModel(value gorm.Model) ActiveDirectoryDomain {
+	b.ActiveDirectoryDomain.Model = value
+	return b
+}
+
+func (b *activeDirectoryDomain) WithDomainControllers(value []activedirectory.ActiveDirectoryDomainController) ActiveDirectoryDomain {
+	b.ActiveDirectoryDomain.DomainControllers = value
+	return b
+}
+
+func (b *activeDirectoryDomain) WithSites(value []activedirectory.ActiveDirectorySite) ActiveDirectoryDomain {
+	b.ActiveDirectoryDomain.Sites = value
+	return b
+}
+
+func (b *activeDirectoryDomain) WithRealmName(value *string) ActiveDirectoryDomain {
+	b.ActiveDirectoryDomain.RealmName = value
+	return b
+}
+
+func (b *activeDirectoryDomain) WithForestName(value *string) ActiveDirectoryDomain {
+	b.ActiveDirectoryDomain.ForestName = value
+	return b
+}
+
+func (b *activeDirectoryDomain) WithUpdateAgents(value pq.StringArray) ActiveDirectoryDomain {
+	b.ActiveDirectoryDomain.UpdateAgents = value
+	return b
+}
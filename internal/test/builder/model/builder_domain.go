@@ -6,6 +6,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	builder_helper "github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/activedirectory"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"go.openly.dev/pointy"
 	"gorm.io/gorm"
 )
@@ -28,7 +30,8 @@ type Domain interface {
 	WithTitle(value *string) Domain
 	WithDescription(value *string) Domain
 	WithAutoEnrollmentEnabled(value *bool) Domain
-	WithIpaDomain(value *model.Ipa) Domain
+	WithIpaDomain(value *ipa.Ipa) Domain
+	WithActiveDirectoryDomain(value *activedirectory.ActiveDirectory) Domain
 }
 
 // domain is the specific builder implementation
@@ -78,8 +81,8 @@ func NewDomain(gormModel gorm.Model) Domain {
 		AutoEnrollmentEnabled: autoEnrollmentEnabled,
 		Title:                 title,
 		Description:           description,
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain:             NewIpaDomain().WithModel(gormModel).Build(),
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData:              NewIpaDomain().WithModel(gormModel).Build(),
 	}
 }
 
@@ -120,9 +123,15 @@ func (b *domain) WithDescription(value *string) Domain {
 	return b
 }
 
-func (b *domain) WithIpaDomain(value *model.Ipa) Domain {
-	b.Type = pointy.Uint(model.DomainTypeIpa)
-	b.IpaDomain = value
+func (b *domain) WithIpaDomain(value *ipa.Ipa) Domain {
+	b.Type = pointy.Uint(ipa.TypeID)
+	b.TypeData = value
+	return b
+}
+
+func (b *domain) WithActiveDirectoryDomain(value *activedirectory.ActiveDirectory) Domain {
+	b.Type = pointy.Uint(activedirectory.TypeID)
+	b.TypeData = value
 	return b
 }
 
@@ -2,8 +2,8 @@ package model
 
 import (
 	"github.com/google/uuid"
-	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"gorm.io/gorm"
 )
 
@@ -17,5 +17,5 @@ type HostConfOptions interface {
 	WithTitle(value *string) HostConfOptions
 	WithDescription(value *string) HostConfOptions
 	WithAutoEnrollmentEnabled(value *bool) HostConfOptions
-	WithIpaDomain(value *model.Ipa) HostConfOptions
+	WithIpaDomain(value *ipa.Ipa) HostConfOptions
 }
@@ -4,31 +4,32 @@ import (
 	"strings"
 
 	"github.com/lib/pq"
-	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	builder_helper "github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"go.openly.dev/pointy"
 	"gorm.io/gorm"
 )
 
 type IpaDomain interface {
-	Build() *model.Ipa
+	Build() *ipa.Ipa
 	WithModel(value gorm.Model) IpaDomain
-	WithCaCerts(value []model.IpaCert) IpaDomain
-	WithServers(value []model.IpaServer) IpaDomain
-	WithLocations(value []model.IpaLocation) IpaDomain
+	WithCaCerts(value []ipa.IpaCert) IpaDomain
+	WithServers(value []ipa.IpaServer) IpaDomain
+	WithLocations(value []ipa.IpaLocation) IpaDomain
+	WithTrusts(value []ipa.IpaTrust) IpaDomain
 	WithRealmName(value *string) IpaDomain
 	WithRealmDomains(value pq.StringArray) IpaDomain
 }
 
 type ipaDomain struct {
-	IpaDomain *model.Ipa
+	IpaDomain *ipa.Ipa
 }
 
 func NewIpaDomain() IpaDomain {
 	var (
-		certs        []model.IpaCert
-		locations    []model.IpaLocation
-		servers      []model.IpaServer
+		certs        []ipa.IpaCert
+		locations    []ipa.IpaLocation
+		servers      []ipa.IpaServer
 		realmName    *string
 		realmDomains pq.StringArray = pq.StringArray{}
 	)
@@ -37,7 +38,7 @@ func NewIpaDomain() IpaDomain {
 		realmDomains = pq.StringArray{*realmName}
 	}
 	return &ipaDomain{
-		IpaDomain: &model.Ipa{
+		IpaDomain: &ipa.Ipa{
 			Model:        NewModel().Build(),
 			CaCerts:      certs,
 			Servers:      servers,
@@ -48,7 +49,7 @@ func NewIpaDomain() IpaDomain {
 	}
 }
 
-func (b *ipaDomain) Build() *model.Ipa {
+func (b *ipaDomain) Build() *ipa.Ipa {
 	return b.IpaDomain
 }
 
@@ -56,21 +57,26 @@ func (b *ipaDomain) WithModel(value gorm.Model) IpaDomain {
 	b.IpaDomain.Model = value
 	return b
 }
-func (b *ipaDomain) WithCaCerts(value []model.IpaCert) IpaDomain {
+func (b *ipaDomain) WithCaCerts(value []ipa.IpaCert) IpaDomain {
 	b.IpaDomain.CaCerts = value
 	return b
 }
 
-func (b *ipaDomain) WithServers(value []model.IpaServer) IpaDomain {
+func (b *ipaDomain) WithServers(value []ipa.IpaServer) IpaDomain {
 	b.IpaDomain.Servers = value
 	return b
 }
 
-func (b *ipaDomain) WithLocations(value []model.IpaLocation) IpaDomain {
+func (b *ipaDomain) WithLocations(value []ipa.IpaLocation) IpaDomain {
 	b.IpaDomain.Locations = value
 	return b
 }
 
+func (b *ipaDomain) WithTrusts(value []ipa.IpaTrust) IpaDomain {
+	b.IpaDomain.Trusts = value
+	return b
+}
+
 func (b *ipaDomain) WithRealmName(value *string) IpaDomain {
 	b.IpaDomain.RealmName = value
 	return b
@@ -4,13 +4,13 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"gorm.io/gorm"
 )
 
 type IpaCert interface {
-	Build() model.IpaCert
+	Build() ipa.IpaCert
 	WithIpaID(value uint) IpaCert
 	WithModel(value gorm.Model) IpaCert
 	WithIssuer(value string) IpaCert
@@ -23,12 +23,12 @@ type IpaCert interface {
 }
 
 type ipaCert struct {
-	IpaCert model.IpaCert
+	IpaCert ipa.IpaCert
 }
 
 func NewIpaCert(gormModel gorm.Model, realm string) IpaCert {
 	return &ipaCert{
-		IpaCert: model.IpaCert{
+		IpaCert: ipa.IpaCert{
 			Model:        gormModel,
 			IpaID:        uint(helper.GenRandNum(1, 999999)),
 			Issuer:       helper.GenIssuerWithRealm(helper.GenRandDomainLabel(), realm),
@@ -42,7 +42,7 @@ func NewIpaCert(gormModel gorm.Model, realm string) IpaCert {
 	}
 }
 
-func (b *ipaCert) Build() model.IpaCert {
+func (b *ipaCert) Build() ipa.IpaCert {
 	return b.IpaCert
 }
 
@@ -1,14 +1,14 @@
 package model
 
 import (
-	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"go.openly.dev/pointy"
 	"gorm.io/gorm"
 )
 
 type IpaLocation interface {
-	Build() model.IpaLocation
+	Build() ipa.IpaLocation
 	WithIpaID(value uint) IpaLocation
 	WithModel(value gorm.Model) IpaLocation
 	WithName(value string) IpaLocation
@@ -16,12 +16,12 @@ type IpaLocation interface {
 }
 
 type ipaLocation struct {
-	IpaLocation model.IpaLocation
+	IpaLocation ipa.IpaLocation
 }
 
 func NewIpaLocation(gormModel gorm.Model) IpaLocation {
 	return &ipaLocation{
-		IpaLocation: model.IpaLocation{
+		IpaLocation: ipa.IpaLocation{
 			Model:       gormModel,
 			Name:        helper.GenRandLocationLabel(),
 			Description: pointy.String(helper.GenRandLocationDescription()),
@@ -29,7 +29,7 @@ func NewIpaLocation(gormModel gorm.Model) IpaLocation {
 	}
 }
 
-func (b *ipaLocation) Build() model.IpaLocation {
+func (b *ipaLocation) Build() ipa.IpaLocation {
 	return b.IpaLocation
 }
 
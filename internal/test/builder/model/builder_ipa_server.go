@@ -2,14 +2,14 @@ package model
 
 import (
 	"github.com/google/uuid"
-	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	builder_helper "github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"go.openly.dev/pointy"
 	"gorm.io/gorm"
 )
 
 type IpaServer interface {
-	Build() model.IpaServer
+	Build() ipa.IpaServer
 	WithIpaID(value uint) IpaServer
 	WithFQDN(value string) IpaServer
 	WithRHSMID(value *string) IpaServer
@@ -29,7 +29,7 @@ type IpaServer interface {
 }
 
 type ipaServer struct {
-	IpaServer model.IpaServer
+	IpaServer ipa.IpaServer
 }
 
 func NewIpaServer(gormModel gorm.Model) IpaServer {
@@ -44,7 +44,7 @@ func NewIpaServer(gormModel gorm.Model) IpaServer {
 		location = pointy.String(builder_helper.GenRandDomainLabel())
 	}
 	return &ipaServer{
-		IpaServer: model.IpaServer{
+		IpaServer: ipa.IpaServer{
 			Model:               gormModel,
 			IpaID:               0,
 			FQDN:                builder_helper.GenRandFQDN(),
@@ -58,7 +58,7 @@ func NewIpaServer(gormModel gorm.Model) IpaServer {
 	}
 }
 
-func (b *ipaServer) Build() model.IpaServer {
+func (b *ipaServer) Build() ipa.IpaServer {
 	return b.IpaServer
 }
 
@@ -20,7 +20,7 @@ func GetTestConfig() (cfg *config.Config) {
 		panic(fmt.Errorf("Invalid configuration: %w", err))
 	}
 
-	sec, err := secrets.NewAppSecrets(cfg.Application.MainSecret)
+	sec, err := secrets.NewAppSecrets(cfg.Application.MainSecret, cfg.Application.PreviousSecrets...)
 	if err != nil {
 		panic(err)
 	}
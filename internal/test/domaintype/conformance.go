@@ -0,0 +1,190 @@
+// Package domaintype provides the conformance suite that every
+// implementation of domaintype.DomainTypeHandler is expected to pass.
+package domaintype
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/domaintype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+// Fixture provides the valid requests for a domain type that the
+// conformance suite uses to exercise its handler.
+type Fixture struct {
+	// Register is a valid register request for the domain type.
+	Register *public.Domain
+	// UpdateAgent is a valid agent update request for the domain type.
+	UpdateAgent *public.UpdateDomainAgentRequest
+	// SubscriptionManagerID is an update agent listed in both requests.
+	SubscriptionManagerID string
+	// RegistrationFQDN is the fqdn of SubscriptionManagerID recorded
+	// by FillRegistration, or nil if the type does not know it.
+	RegistrationFQDN *string
+	// RegistrationCaCerts is the number of CA certificates recorded
+	// by FillRegistration.
+	RegistrationCaCerts int
+}
+
+// RunConformanceSuite check that h follows the contract expected by
+// the repository, interactor, presenter and handler layers.
+func RunConformanceSuite(t *testing.T, h domaintype.DomainTypeHandler, fixture Fixture) {
+	require.NotNil(t, h)
+	require.NotNil(t, fixture.Register)
+	require.NotNil(t, fixture.UpdateAgent)
+	require.NotEmpty(t, fixture.SubscriptionManagerID)
+
+	var logBuffer bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&logBuffer, nil))
+	ctx := app_context.CtxWithLog(context.Background(), log)
+
+	translate := func(t *testing.T) *model.Domain {
+		domain := &model.Domain{DomainName: pointy.String(fixture.Register.DomainName)}
+		require.NoError(t, h.TranslateRegister(fixture.Register, domain))
+		return domain
+	}
+	present := func(t *testing.T, domain *model.Domain) *public.Domain {
+		output := &public.Domain{}
+		require.NoError(t, h.PresentDomain(domain, output))
+		return output
+	}
+
+	t.Run("Identity", func(t *testing.T) {
+		assert.NotEqual(t, model.DomainTypeUndefined, h.Type())
+		assert.NotEmpty(t, h.Name())
+		assert.Equal(t, string(h.Name()), model.DomainTypeString(h.Type()))
+		assert.Equal(t, h.Type(), model.DomainTypeUint(string(h.Name())))
+		assert.Equal(t, h.Name(), fixture.Register.DomainType)
+		assert.Equal(t, h.Name(), fixture.UpdateAgent.DomainType)
+	})
+
+	t.Run("TranslateRegister", func(t *testing.T) {
+		domain := translate(t)
+		require.NotNil(t, domain.Type)
+		assert.Equal(t, h.Type(), *domain.Type)
+
+		assert.Error(t, h.TranslateRegister(nil, &model.Domain{}))
+		assert.Error(t, h.TranslateRegister(&public.Domain{DomainType: h.Name()}, &model.Domain{}))
+	})
+
+	t.Run("TranslateUpdateAgent", func(t *testing.T) {
+		domain := &model.Domain{}
+		require.NoError(t, h.TranslateUpdateAgent(fixture.UpdateAgent, domain))
+		require.NotNil(t, domain.Type)
+		assert.Equal(t, h.Type(), *domain.Type)
+
+		assert.Error(t, h.TranslateUpdateAgent(nil, &model.Domain{}))
+		assert.Error(t, h.TranslateUpdateAgent(&public.UpdateDomainAgentRequest{DomainType: h.Name()}, &model.Domain{}))
+	})
+
+	t.Run("PresentDomain", func(t *testing.T) {
+		output := present(t, translate(t))
+
+		// What is presented can be registered again without losses;
+		// domain_type is filled by the shared presenter.
+		output.DomainType = h.Name()
+		again := &model.Domain{}
+		require.NoError(t, h.TranslateRegister(output, again))
+		outputAgain := present(t, again)
+		outputAgain.DomainType = h.Name()
+		assert.Equal(t, output, outputAgain)
+
+		assert.Error(t, h.PresentDomain(&model.Domain{Type: pointy.Uint(h.Type())}, &public.Domain{}))
+	})
+
+	t.Run("PresentHostConf", func(t *testing.T) {
+		response := &public.HostConfResponse{}
+		require.NoError(t, h.PresentHostConf(translate(t), nil, response))
+
+		// A location that matches no server keeps them all as fallback.
+		located := &public.HostConfResponse{}
+		require.NoError(t, h.PresentHostConf(translate(t), &model.HostLocation{Name: "nowhere"}, located))
+		assert.Equal(t, response, located)
+		restricted := &public.HostConfResponse{}
+		require.NoError(t, h.PresentHostConf(translate(t), &model.HostLocation{Name: "nowhere", Restrict: true}, restricted))
+		assert.Equal(t, response, restricted)
+
+		domain := &model.Domain{
+			DomainName: pointy.String(fixture.Register.DomainName),
+			Type:       pointy.Uint(h.Type()),
+		}
+		assert.Error(t, h.PresentHostConf(domain, nil, &public.HostConfResponse{}))
+	})
+
+	t.Run("Fill", func(t *testing.T) {
+		source := translate(t)
+		target := &model.Domain{Type: pointy.Uint(h.Type())}
+		require.NoError(t, h.Fill(target, source))
+		assert.Equal(t, present(t, source), present(t, target))
+
+		assert.Error(t, h.Fill(&model.Domain{}, &model.Domain{Type: pointy.Uint(h.Type())}))
+	})
+
+	t.Run("FillRegistration", func(t *testing.T) {
+		registration := &model.DomainRegistration{CaCerts: []model.RegistrationCert{}}
+		require.NoError(t, h.FillRegistration(fixture.SubscriptionManagerID, translate(t), registration))
+		assert.Equal(t, fixture.RegistrationFQDN, registration.FQDN)
+		assert.Len(t, registration.CaCerts, fixture.RegistrationCaCerts)
+
+		// An unknown server records no fqdn
+		registration = &model.DomainRegistration{CaCerts: []model.RegistrationCert{}}
+		require.NoError(t, h.FillRegistration(uuid.NewString(), translate(t), registration))
+		assert.Nil(t, registration.FQDN)
+
+		assert.Error(t, h.FillRegistration(fixture.SubscriptionManagerID, translate(t), nil))
+		assert.Error(t, h.FillRegistration(fixture.SubscriptionManagerID,
+			&model.Domain{Type: pointy.Uint(h.Type())}, &model.DomainRegistration{}))
+	})
+
+	t.Run("ServerCount", func(t *testing.T) {
+		assert.Positive(t, h.ServerCount(translate(t)))
+		assert.Equal(t, 0, h.ServerCount(&model.Domain{Type: pointy.Uint(h.Type())}))
+	})
+
+	t.Run("ValidateUpdate", func(t *testing.T) {
+		current := translate(t)
+		assert.NoError(t, h.ValidateUpdate(current, translate(t)))
+	})
+
+	t.Run("EnsureUpdateAgentEnabled", func(t *testing.T) {
+		domain := translate(t)
+		assert.NoError(t, h.EnsureUpdateAgentEnabled(ctx, fixture.SubscriptionManagerID, domain))
+		assert.Error(t, h.EnsureUpdateAgentEnabled(ctx, "", domain))
+		assertHTTPCode(t, http.StatusBadRequest,
+			h.EnsureUpdateAgentEnabled(ctx, uuid.NewString(), domain))
+	})
+
+	t.Run("EnsureUpdateAgentAuthorized", func(t *testing.T) {
+		domain := translate(t)
+		assert.NoError(t, h.EnsureUpdateAgentAuthorized(ctx, fixture.SubscriptionManagerID, domain))
+		assert.Error(t, h.EnsureUpdateAgentAuthorized(ctx, "", domain))
+		assertHTTPCode(t, http.StatusForbidden,
+			h.EnsureUpdateAgentAuthorized(ctx, uuid.NewString(), domain))
+	})
+
+	t.Run("CreateAndUpdateWithoutTypeData", func(t *testing.T) {
+		domain := &model.Domain{Type: pointy.Uint(h.Type())}
+		domain.ID = 1
+		assert.Error(t, h.Create(log, nil, domain))
+		assert.Error(t, h.Update(log, nil, domain))
+	})
+}
+
+func assertHTTPCode(t *testing.T, code int, err error) {
+	var httpErr *echo.HTTPError
+	require.Error(t, err)
+	require.True(t, errors.As(err, &httpErr), "expected *echo.HTTPError but got %T", err)
+	assert.Equal(t, code, httpErr.Code)
+}
@@ -16,6 +16,42 @@ type ServerInterface struct {
 	mock.Mock
 }
 
+// ApproveDomain provides a mock function with given fields: ctx, _a1, params
+func (_m *ServerInterface) ApproveDomain(ctx echo.Context, _a1 uuid.UUID, params public.ApproveDomainParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveDomain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ApproveDomainParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BatchDomains provides a mock function with given fields: ctx, params
+func (_m *ServerInterface) BatchDomains(ctx echo.Context, params public.BatchDomainsParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchDomains")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.BatchDomainsParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateDomainToken provides a mock function with given fields: ctx, params
 func (_m *ServerInterface) CreateDomainToken(ctx echo.Context, params public.CreateDomainTokenParams) error {
 	ret := _m.Called(ctx, params)
@@ -34,6 +70,24 @@ func (_m *ServerInterface) CreateDomainToken(ctx echo.Context, params public.Cre
 	return r0
 }
 
+// CreateWebhook provides a mock function with given fields: ctx, params
+func (_m *ServerInterface) CreateWebhook(ctx echo.Context, params public.CreateWebhookParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.CreateWebhookParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteDomain provides a mock function with given fields: ctx, _a1, params
 func (_m *ServerInterface) DeleteDomain(ctx echo.Context, _a1 uuid.UUID, params public.DeleteDomainParams) error {
 	ret := _m.Called(ctx, _a1, params)
@@ -52,6 +106,42 @@ func (_m *ServerInterface) DeleteDomain(ctx echo.Context, _a1 uuid.UUID, params
 	return r0
 }
 
+// DeleteWebhook provides a mock function with given fields: ctx, _a1, params
+func (_m *ServerInterface) DeleteWebhook(ctx echo.Context, _a1 uuid.UUID, params public.DeleteWebhookParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.DeleteWebhookParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportDomains provides a mock function with given fields: ctx, params
+func (_m *ServerInterface) ExportDomains(ctx echo.Context, params public.ExportDomainsParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportDomains")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.ExportDomainsParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetSigningKeys provides a mock function with given fields: ctx, params
 func (_m *ServerInterface) GetSigningKeys(ctx echo.Context, params public.GetSigningKeysParams) error {
 	ret := _m.Called(ctx, params)
@@ -106,6 +196,42 @@ func (_m *ServerInterface) ListDomains(ctx echo.Context, params public.ListDomai
 	return r0
 }
 
+// ListWebhookDeliveries provides a mock function with given fields: ctx, _a1, params
+func (_m *ServerInterface) ListWebhookDeliveries(ctx echo.Context, _a1 uuid.UUID, params public.ListWebhookDeliveriesParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhookDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ListWebhookDeliveriesParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListWebhooks provides a mock function with given fields: ctx, params
+func (_m *ServerInterface) ListWebhooks(ctx echo.Context, params public.ListWebhooksParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhooks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.ListWebhooksParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReadDomain provides a mock function with given fields: ctx, _a1, params
 func (_m *ServerInterface) ReadDomain(ctx echo.Context, _a1 uuid.UUID, params public.ReadDomainParams) error {
 	ret := _m.Called(ctx, _a1, params)
@@ -124,6 +250,42 @@ func (_m *ServerInterface) ReadDomain(ctx echo.Context, _a1 uuid.UUID, params pu
 	return r0
 }
 
+// ReadWebhook provides a mock function with given fields: ctx, _a1, params
+func (_m *ServerInterface) ReadWebhook(ctx echo.Context, _a1 uuid.UUID, params public.ReadWebhookParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ReadWebhookParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RejectDomain provides a mock function with given fields: ctx, _a1, params
+func (_m *ServerInterface) RejectDomain(ctx echo.Context, _a1 uuid.UUID, params public.RejectDomainParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectDomain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.RejectDomainParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RegisterDomain provides a mock function with given fields: ctx, params
 func (_m *ServerInterface) RegisterDomain(ctx echo.Context, params public.RegisterDomainParams) error {
 	ret := _m.Called(ctx, params)
@@ -178,6 +340,24 @@ func (_m *ServerInterface) UpdateDomainUser(ctx echo.Context, _a1 uuid.UUID, par
 	return r0
 }
 
+// UpdateWebhook provides a mock function with given fields: ctx, _a1, params
+func (_m *ServerInterface) UpdateWebhook(ctx echo.Context, _a1 uuid.UUID, params public.UpdateWebhookParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.UpdateWebhookParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewServerInterface creates a new instance of ServerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewServerInterface(t interface {
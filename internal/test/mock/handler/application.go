@@ -17,6 +17,42 @@ type Application struct {
 	mock.Mock
 }
 
+// ApproveDomain provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) ApproveDomain(ctx echo.Context, _a1 uuid.UUID, params public.ApproveDomainParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveDomain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ApproveDomainParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BatchDomains provides a mock function with given fields: ctx, params
+func (_m *Application) BatchDomains(ctx echo.Context, params public.BatchDomainsParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchDomains")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.BatchDomainsParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateDomainToken provides a mock function with given fields: ctx, params
 func (_m *Application) CreateDomainToken(ctx echo.Context, params public.CreateDomainTokenParams) error {
 	ret := _m.Called(ctx, params)
@@ -35,6 +71,24 @@ func (_m *Application) CreateDomainToken(ctx echo.Context, params public.CreateD
 	return r0
 }
 
+// CreateWebhook provides a mock function with given fields: ctx, params
+func (_m *Application) CreateWebhook(ctx echo.Context, params public.CreateWebhookParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.CreateWebhookParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteDomain provides a mock function with given fields: ctx, _a1, params
 func (_m *Application) DeleteDomain(ctx echo.Context, _a1 uuid.UUID, params public.DeleteDomainParams) error {
 	ret := _m.Called(ctx, _a1, params)
@@ -53,6 +107,42 @@ func (_m *Application) DeleteDomain(ctx echo.Context, _a1 uuid.UUID, params publ
 	return r0
 }
 
+// DeleteWebhook provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) DeleteWebhook(ctx echo.Context, _a1 uuid.UUID, params public.DeleteWebhookParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.DeleteWebhookParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportDomains provides a mock function with given fields: ctx, params
+func (_m *Application) ExportDomains(ctx echo.Context, params public.ExportDomainsParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportDomains")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.ExportDomainsParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetLivez provides a mock function with given fields: ctx
 func (_m *Application) GetLivez(ctx echo.Context) error {
 	ret := _m.Called(ctx)
@@ -179,6 +269,42 @@ func (_m *Application) ListDomains(ctx echo.Context, params public.ListDomainsPa
 	return r0
 }
 
+// ListWebhookDeliveries provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) ListWebhookDeliveries(ctx echo.Context, _a1 uuid.UUID, params public.ListWebhookDeliveriesParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhookDeliveries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ListWebhookDeliveriesParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListWebhooks provides a mock function with given fields: ctx, params
+func (_m *Application) ListWebhooks(ctx echo.Context, params public.ListWebhooksParams) error {
+	ret := _m.Called(ctx, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhooks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, public.ListWebhooksParams) error); ok {
+		r0 = rf(ctx, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReadDomain provides a mock function with given fields: ctx, _a1, params
 func (_m *Application) ReadDomain(ctx echo.Context, _a1 uuid.UUID, params public.ReadDomainParams) error {
 	ret := _m.Called(ctx, _a1, params)
@@ -197,6 +323,60 @@ func (_m *Application) ReadDomain(ctx echo.Context, _a1 uuid.UUID, params public
 	return r0
 }
 
+// ReadDomainLocationSubnets provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) ReadDomainLocationSubnets(ctx echo.Context, _a1 uuid.UUID, params public.ReadDomainLocationSubnetsParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadDomainLocationSubnets")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ReadDomainLocationSubnetsParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadWebhook provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) ReadWebhook(ctx echo.Context, _a1 uuid.UUID, params public.ReadWebhookParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.ReadWebhookParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RejectDomain provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) RejectDomain(ctx echo.Context, _a1 uuid.UUID, params public.RejectDomainParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectDomain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.RejectDomainParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RegisterDomain provides a mock function with given fields: ctx, params
 func (_m *Application) RegisterDomain(ctx echo.Context, params public.RegisterDomainParams) error {
 	ret := _m.Called(ctx, params)
@@ -233,6 +413,24 @@ func (_m *Application) UpdateDomainAgent(ctx echo.Context, _a1 uuid.UUID, params
 	return r0
 }
 
+// UpdateDomainLocationSubnets provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) UpdateDomainLocationSubnets(ctx echo.Context, _a1 uuid.UUID, params public.UpdateDomainLocationSubnetsParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDomainLocationSubnets")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.UpdateDomainLocationSubnetsParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateDomainUser provides a mock function with given fields: ctx, _a1, params
 func (_m *Application) UpdateDomainUser(ctx echo.Context, _a1 uuid.UUID, params public.UpdateDomainUserParams) error {
 	ret := _m.Called(ctx, _a1, params)
@@ -251,6 +449,24 @@ func (_m *Application) UpdateDomainUser(ctx echo.Context, _a1 uuid.UUID, params
 	return r0
 }
 
+// UpdateWebhook provides a mock function with given fields: ctx, _a1, params
+func (_m *Application) UpdateWebhook(ctx echo.Context, _a1 uuid.UUID, params public.UpdateWebhookParams) error {
+	ret := _m.Called(ctx, _a1, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(echo.Context, uuid.UUID, public.UpdateWebhookParams) error); ok {
+		r0 = rf(ctx, _a1, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewApplication creates a new instance of Application. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewApplication(t interface {
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	authz "github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
 )
 
 // Rbac is an autogenerated mock type for the Rbac type
@@ -13,6 +15,36 @@ type Rbac struct {
 	mock.Mock
 }
 
+// AllowedResources provides a mock function with given fields: ctx, xrhid, permission
+func (_m *Rbac) AllowedResources(ctx context.Context, xrhid string, permission string) (*authz.ResourceSet, error) {
+	ret := _m.Called(ctx, xrhid, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowedResources")
+	}
+
+	var r0 *authz.ResourceSet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*authz.ResourceSet, error)); ok {
+		return rf(ctx, xrhid, permission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *authz.ResourceSet); ok {
+		r0 = rf(ctx, xrhid, permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*authz.ResourceSet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, xrhid, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsAllowed provides a mock function with given fields: ctx, xrhid, permission
 func (_m *Rbac) IsAllowed(ctx context.Context, xrhid string, permission string) (bool, error) {
 	ret := _m.Called(ctx, xrhid, permission)
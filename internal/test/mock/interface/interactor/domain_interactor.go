@@ -13,6 +13,8 @@ import (
 	public "github.com/podengo-project/idmsvc-backend/internal/api/public"
 
 	uuid "github.com/google/uuid"
+
+	domain_token "github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 )
 
 // DomainInteractor is an autogenerated mock type for the DomainInteractor type
@@ -20,8 +22,89 @@ type DomainInteractor struct {
 	mock.Mock
 }
 
+// ApproveDomain provides a mock function with given fields: xrhid, UUID, params, body
+func (_m *DomainInteractor) ApproveDomain(xrhid *identity.XRHID, UUID uuid.UUID, params *public.ApproveDomainParams, body *public.DomainDecisionRequest) (string, *model.DomainRegistration, error) {
+	ret := _m.Called(xrhid, UUID, params, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveDomain")
+	}
+
+	var r0 string
+	var r1 *model.DomainRegistration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.ApproveDomainParams, *public.DomainDecisionRequest) (string, *model.DomainRegistration, error)); ok {
+		return rf(xrhid, UUID, params, body)
+	}
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.ApproveDomainParams, *public.DomainDecisionRequest) string); ok {
+		r0 = rf(xrhid, UUID, params, body)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*identity.XRHID, uuid.UUID, *public.ApproveDomainParams, *public.DomainDecisionRequest) *model.DomainRegistration); ok {
+		r1 = rf(xrhid, UUID, params, body)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.DomainRegistration)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*identity.XRHID, uuid.UUID, *public.ApproveDomainParams, *public.DomainDecisionRequest) error); ok {
+		r2 = rf(xrhid, UUID, params, body)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Batch provides a mock function with given fields: xrhid, params, body
+func (_m *DomainInteractor) Batch(xrhid *identity.XRHID, params *public.BatchDomainsParams, body *public.BatchDomainsRequest) (string, bool, []*model.Domain, error) {
+	ret := _m.Called(xrhid, params, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 string
+	var r1 bool
+	var r2 []*model.Domain
+	var r3 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.BatchDomainsParams, *public.BatchDomainsRequest) (string, bool, []*model.Domain, error)); ok {
+		return rf(xrhid, params, body)
+	}
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.BatchDomainsParams, *public.BatchDomainsRequest) string); ok {
+		r0 = rf(xrhid, params, body)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*identity.XRHID, *public.BatchDomainsParams, *public.BatchDomainsRequest) bool); ok {
+		r1 = rf(xrhid, params, body)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(*identity.XRHID, *public.BatchDomainsParams, *public.BatchDomainsRequest) []*model.Domain); ok {
+		r2 = rf(xrhid, params, body)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).([]*model.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(*identity.XRHID, *public.BatchDomainsParams, *public.BatchDomainsRequest) error); ok {
+		r3 = rf(xrhid, params, body)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
 // CreateDomainToken provides a mock function with given fields: xrhid, params, body
-func (_m *DomainInteractor) CreateDomainToken(xrhid *identity.XRHID, params *public.CreateDomainTokenParams, body *public.DomainRegTokenRequest) (string, public.DomainType, error) {
+func (_m *DomainInteractor) CreateDomainToken(xrhid *identity.XRHID, params *public.CreateDomainTokenParams, body *public.DomainRegTokenRequest) (string, public.DomainType, domain_token.Claims, error) {
 	ret := _m.Called(xrhid, params, body)
 
 	if len(ret) == 0 {
@@ -30,8 +113,9 @@ func (_m *DomainInteractor) CreateDomainToken(xrhid *identity.XRHID, params *pub
 
 	var r0 string
 	var r1 public.DomainType
-	var r2 error
-	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.CreateDomainTokenParams, *public.DomainRegTokenRequest) (string, public.DomainType, error)); ok {
+	var r2 domain_token.Claims
+	var r3 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.CreateDomainTokenParams, *public.DomainRegTokenRequest) (string, public.DomainType, domain_token.Claims, error)); ok {
 		return rf(xrhid, params, body)
 	}
 	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.CreateDomainTokenParams, *public.DomainRegTokenRequest) string); ok {
@@ -46,13 +130,19 @@ func (_m *DomainInteractor) CreateDomainToken(xrhid *identity.XRHID, params *pub
 		r1 = ret.Get(1).(public.DomainType)
 	}
 
-	if rf, ok := ret.Get(2).(func(*identity.XRHID, *public.CreateDomainTokenParams, *public.DomainRegTokenRequest) error); ok {
+	if rf, ok := ret.Get(2).(func(*identity.XRHID, *public.CreateDomainTokenParams, *public.DomainRegTokenRequest) domain_token.Claims); ok {
 		r2 = rf(xrhid, params, body)
 	} else {
-		r2 = ret.Error(2)
+		r2 = ret.Get(2).(domain_token.Claims)
 	}
 
-	return r0, r1, r2
+	if rf, ok := ret.Get(3).(func(*identity.XRHID, *public.CreateDomainTokenParams, *public.DomainRegTokenRequest) error); ok {
+		r3 = rf(xrhid, params, body)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
 }
 
 // Delete provides a mock function with given fields: xrhid, UUID, params
@@ -92,6 +182,34 @@ func (_m *DomainInteractor) Delete(xrhid *identity.XRHID, UUID uuid.UUID, params
 	return r0, r1, r2
 }
 
+// Export provides a mock function with given fields: xrhid, params
+func (_m *DomainInteractor) Export(xrhid *identity.XRHID, params *public.ExportDomainsParams) (string, error) {
+	ret := _m.Called(xrhid, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.ExportDomainsParams) (string, error)); ok {
+		return rf(xrhid, params)
+	}
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, *public.ExportDomainsParams) string); ok {
+		r0 = rf(xrhid, params)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*identity.XRHID, *public.ExportDomainsParams) error); ok {
+		r1 = rf(xrhid, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: xrhid, params
 func (_m *DomainInteractor) GetByID(xrhid *identity.XRHID, params *public.ReadDomainParams) (string, error) {
 	ret := _m.Called(xrhid, params)
@@ -120,6 +238,45 @@ func (_m *DomainInteractor) GetByID(xrhid *identity.XRHID, params *public.ReadDo
 	return r0, r1
 }
 
+// Import provides a mock function with given fields: orgID, doc
+func (_m *DomainInteractor) Import(orgID string, doc *public.DomainExport) ([]model.Domain, map[uuid.UUID][]model.DomainLocationSubnet, error) {
+	ret := _m.Called(orgID, doc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 []model.Domain
+	var r1 map[uuid.UUID][]model.DomainLocationSubnet
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, *public.DomainExport) ([]model.Domain, map[uuid.UUID][]model.DomainLocationSubnet, error)); ok {
+		return rf(orgID, doc)
+	}
+	if rf, ok := ret.Get(0).(func(string, *public.DomainExport) []model.Domain); ok {
+		r0 = rf(orgID, doc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Domain)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, *public.DomainExport) map[uuid.UUID][]model.DomainLocationSubnet); ok {
+		r1 = rf(orgID, doc)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(map[uuid.UUID][]model.DomainLocationSubnet)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string, *public.DomainExport) error); ok {
+		r2 = rf(orgID, doc)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // List provides a mock function with given fields: xrhid, params
 func (_m *DomainInteractor) List(xrhid *identity.XRHID, params *public.ListDomainsParams) (string, int, int, error) {
 	ret := _m.Called(xrhid, params)
@@ -162,9 +319,74 @@ func (_m *DomainInteractor) List(xrhid *identity.XRHID, params *public.ListDomai
 	return r0, r1, r2, r3
 }
 
-// Register provides a mock function with given fields: domainRegKey, xrhid, params, body
-func (_m *DomainInteractor) Register(domainRegKey []byte, xrhid *identity.XRHID, params *public.RegisterDomainParams, body *public.Domain) (string, *header.XRHIDMVersion, *model.Domain, error) {
-	ret := _m.Called(domainRegKey, xrhid, params, body)
+// ReadLocationSubnets provides a mock function with given fields: xrhid, UUID, params
+func (_m *DomainInteractor) ReadLocationSubnets(xrhid *identity.XRHID, UUID uuid.UUID, params *public.ReadDomainLocationSubnetsParams) (string, error) {
+	ret := _m.Called(xrhid, UUID, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadLocationSubnets")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.ReadDomainLocationSubnetsParams) (string, error)); ok {
+		return rf(xrhid, UUID, params)
+	}
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.ReadDomainLocationSubnetsParams) string); ok {
+		r0 = rf(xrhid, UUID, params)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*identity.XRHID, uuid.UUID, *public.ReadDomainLocationSubnetsParams) error); ok {
+		r1 = rf(xrhid, UUID, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectDomain provides a mock function with given fields: xrhid, UUID, params, body
+func (_m *DomainInteractor) RejectDomain(xrhid *identity.XRHID, UUID uuid.UUID, params *public.RejectDomainParams, body *public.DomainDecisionRequest) (string, *model.DomainRegistration, error) {
+	ret := _m.Called(xrhid, UUID, params, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectDomain")
+	}
+
+	var r0 string
+	var r1 *model.DomainRegistration
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.RejectDomainParams, *public.DomainDecisionRequest) (string, *model.DomainRegistration, error)); ok {
+		return rf(xrhid, UUID, params, body)
+	}
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.RejectDomainParams, *public.DomainDecisionRequest) string); ok {
+		r0 = rf(xrhid, UUID, params, body)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*identity.XRHID, uuid.UUID, *public.RejectDomainParams, *public.DomainDecisionRequest) *model.DomainRegistration); ok {
+		r1 = rf(xrhid, UUID, params, body)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*model.DomainRegistration)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*identity.XRHID, uuid.UUID, *public.RejectDomainParams, *public.DomainDecisionRequest) error); ok {
+		r2 = rf(xrhid, UUID, params, body)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Register provides a mock function with given fields: domainRegKeys, xrhid, params, body
+func (_m *DomainInteractor) Register(domainRegKeys [][]byte, xrhid *identity.XRHID, params *public.RegisterDomainParams, body *public.Domain) (string, *header.XRHIDMVersion, *model.Domain, error) {
+	ret := _m.Called(domainRegKeys, xrhid, params, body)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Register")
@@ -174,33 +396,33 @@ func (_m *DomainInteractor) Register(domainRegKey []byte, xrhid *identity.XRHID,
 	var r1 *header.XRHIDMVersion
 	var r2 *model.Domain
 	var r3 error
-	if rf, ok := ret.Get(0).(func([]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) (string, *header.XRHIDMVersion, *model.Domain, error)); ok {
-		return rf(domainRegKey, xrhid, params, body)
+	if rf, ok := ret.Get(0).(func([][]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) (string, *header.XRHIDMVersion, *model.Domain, error)); ok {
+		return rf(domainRegKeys, xrhid, params, body)
 	}
-	if rf, ok := ret.Get(0).(func([]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) string); ok {
-		r0 = rf(domainRegKey, xrhid, params, body)
+	if rf, ok := ret.Get(0).(func([][]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) string); ok {
+		r0 = rf(domainRegKeys, xrhid, params, body)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func([]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) *header.XRHIDMVersion); ok {
-		r1 = rf(domainRegKey, xrhid, params, body)
+	if rf, ok := ret.Get(1).(func([][]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) *header.XRHIDMVersion); ok {
+		r1 = rf(domainRegKeys, xrhid, params, body)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*header.XRHIDMVersion)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func([]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) *model.Domain); ok {
-		r2 = rf(domainRegKey, xrhid, params, body)
+	if rf, ok := ret.Get(2).(func([][]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) *model.Domain); ok {
+		r2 = rf(domainRegKeys, xrhid, params, body)
 	} else {
 		if ret.Get(2) != nil {
 			r2 = ret.Get(2).(*model.Domain)
 		}
 	}
 
-	if rf, ok := ret.Get(3).(func([]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) error); ok {
-		r3 = rf(domainRegKey, xrhid, params, body)
+	if rf, ok := ret.Get(3).(func([][]byte, *identity.XRHID, *public.RegisterDomainParams, *public.Domain) error); ok {
+		r3 = rf(domainRegKeys, xrhid, params, body)
 	} else {
 		r3 = ret.Error(3)
 	}
@@ -254,6 +476,43 @@ func (_m *DomainInteractor) UpdateAgent(xrhid *identity.XRHID, UUID uuid.UUID, p
 	return r0, r1, r2, r3
 }
 
+// UpdateLocationSubnets provides a mock function with given fields: xrhid, UUID, params, body
+func (_m *DomainInteractor) UpdateLocationSubnets(xrhid *identity.XRHID, UUID uuid.UUID, params *public.UpdateDomainLocationSubnetsParams, body *public.LocationSubnets) (string, []model.DomainLocationSubnet, error) {
+	ret := _m.Called(xrhid, UUID, params, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLocationSubnets")
+	}
+
+	var r0 string
+	var r1 []model.DomainLocationSubnet
+	var r2 error
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.UpdateDomainLocationSubnetsParams, *public.LocationSubnets) (string, []model.DomainLocationSubnet, error)); ok {
+		return rf(xrhid, UUID, params, body)
+	}
+	if rf, ok := ret.Get(0).(func(*identity.XRHID, uuid.UUID, *public.UpdateDomainLocationSubnetsParams, *public.LocationSubnets) string); ok {
+		r0 = rf(xrhid, UUID, params, body)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*identity.XRHID, uuid.UUID, *public.UpdateDomainLocationSubnetsParams, *public.LocationSubnets) []model.DomainLocationSubnet); ok {
+		r1 = rf(xrhid, UUID, params, body)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]model.DomainLocationSubnet)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(*identity.XRHID, uuid.UUID, *public.UpdateDomainLocationSubnetsParams, *public.LocationSubnets) error); ok {
+		r2 = rf(xrhid, UUID, params, body)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // UpdateUser provides a mock function with given fields: xrhid, UUID, params, body
 func (_m *DomainInteractor) UpdateUser(xrhid *identity.XRHID, UUID uuid.UUID, params *public.UpdateDomainUserParams, body *public.UpdateDomainUserRequest) (string, *model.Domain, error) {
 	ret := _m.Called(xrhid, UUID, params, body)
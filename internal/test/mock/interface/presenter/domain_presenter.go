@@ -9,6 +9,10 @@ import (
 	public "github.com/podengo-project/idmsvc-backend/internal/api/public"
 
 	repository "github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
 )
 
 // DomainPresenter is an autogenerated mock type for the DomainPresenter type
@@ -46,6 +50,36 @@ func (_m *DomainPresenter) CreateDomainToken(token *repository.DomainRegToken) (
 	return r0, r1
 }
 
+// Export provides a mock function with given fields: orgID, exportedAt, data, subnets
+func (_m *DomainPresenter) Export(orgID string, exportedAt time.Time, data []model.Domain, subnets map[uuid.UUID][]model.DomainLocationSubnet) (*public.DomainExport, error) {
+	ret := _m.Called(orgID, exportedAt, data, subnets)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 *public.DomainExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, []model.Domain, map[uuid.UUID][]model.DomainLocationSubnet) (*public.DomainExport, error)); ok {
+		return rf(orgID, exportedAt, data, subnets)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, []model.Domain, map[uuid.UUID][]model.DomainLocationSubnet) *public.DomainExport); ok {
+		r0 = rf(orgID, exportedAt, data, subnets)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*public.DomainExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, []model.Domain, map[uuid.UUID][]model.DomainLocationSubnet) error); ok {
+		r1 = rf(orgID, exportedAt, data, subnets)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: domain
 func (_m *DomainPresenter) Get(domain *model.Domain) (*public.Domain, error) {
 	ret := _m.Called(domain)
@@ -106,6 +140,36 @@ func (_m *DomainPresenter) List(count int64, offset int, limit int, data []model
 	return r0, r1
 }
 
+// LocationSubnets provides a mock function with given fields: data
+func (_m *DomainPresenter) LocationSubnets(data []model.DomainLocationSubnet) (*public.LocationSubnets, error) {
+	ret := _m.Called(data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LocationSubnets")
+	}
+
+	var r0 *public.LocationSubnets
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]model.DomainLocationSubnet) (*public.LocationSubnets, error)); ok {
+		return rf(data)
+	}
+	if rf, ok := ret.Get(0).(func([]model.DomainLocationSubnet) *public.LocationSubnets); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*public.LocationSubnets)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]model.DomainLocationSubnet) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Register provides a mock function with given fields: domain
 func (_m *DomainPresenter) Register(domain *model.Domain) (*public.Domain, error) {
 	ret := _m.Called(domain)
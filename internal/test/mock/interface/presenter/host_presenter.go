@@ -14,9 +14,9 @@ type HostPresenter struct {
 	mock.Mock
 }
 
-// HostConf provides a mock function with given fields: domain, token
-func (_m *HostPresenter) HostConf(domain *model.Domain, token string) (*public.HostConfResponseSchema, error) {
-	ret := _m.Called(domain, token)
+// HostConf provides a mock function with given fields: domain, location, token
+func (_m *HostPresenter) HostConf(domain *model.Domain, location *model.HostLocation, token string) (*public.HostConfResponseSchema, error) {
+	ret := _m.Called(domain, location, token)
 
 	if len(ret) == 0 {
 		panic("no return value specified for HostConf")
@@ -24,19 +24,19 @@ func (_m *HostPresenter) HostConf(domain *model.Domain, token string) (*public.H
 
 	var r0 *public.HostConfResponseSchema
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.Domain, string) (*public.HostConfResponseSchema, error)); ok {
-		return rf(domain, token)
+	if rf, ok := ret.Get(0).(func(*model.Domain, *model.HostLocation, string) (*public.HostConfResponseSchema, error)); ok {
+		return rf(domain, location, token)
 	}
-	if rf, ok := ret.Get(0).(func(*model.Domain, string) *public.HostConfResponseSchema); ok {
-		r0 = rf(domain, token)
+	if rf, ok := ret.Get(0).(func(*model.Domain, *model.HostLocation, string) *public.HostConfResponseSchema); ok {
+		r0 = rf(domain, location, token)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*public.HostConfResponseSchema)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.Domain, string) error); ok {
-		r1 = rf(domain, token)
+	if rf, ok := ret.Get(1).(func(*model.Domain, *model.HostLocation, string) error); ok {
+		r1 = rf(domain, location, token)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -5,6 +5,8 @@ package repository
 import (
 	context "context"
 
+	domain_token "github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
+
 	model "github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	mock "github.com/stretchr/testify/mock"
 
@@ -22,9 +24,9 @@ type DomainRepository struct {
 	mock.Mock
 }
 
-// CreateDomainToken provides a mock function with given fields: ctx, key, validity, orgID, domainType
-func (_m *DomainRepository) CreateDomainToken(ctx context.Context, key []byte, validity time.Duration, orgID string, domainType public.DomainType) (*repository.DomainRegToken, error) {
-	ret := _m.Called(ctx, key, validity, orgID, domainType)
+// CreateDomainToken provides a mock function with given fields: ctx, key, validity, orgID, domainType, claims
+func (_m *DomainRepository) CreateDomainToken(ctx context.Context, key []byte, validity time.Duration, orgID string, domainType public.DomainType, claims domain_token.Claims) (*repository.DomainRegToken, error) {
+	ret := _m.Called(ctx, key, validity, orgID, domainType, claims)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateDomainToken")
@@ -32,19 +34,19 @@ func (_m *DomainRepository) CreateDomainToken(ctx context.Context, key []byte, v
 
 	var r0 *repository.DomainRegToken
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []byte, time.Duration, string, public.DomainType) (*repository.DomainRegToken, error)); ok {
-		return rf(ctx, key, validity, orgID, domainType)
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, time.Duration, string, public.DomainType, domain_token.Claims) (*repository.DomainRegToken, error)); ok {
+		return rf(ctx, key, validity, orgID, domainType, claims)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []byte, time.Duration, string, public.DomainType) *repository.DomainRegToken); ok {
-		r0 = rf(ctx, key, validity, orgID, domainType)
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, time.Duration, string, public.DomainType, domain_token.Claims) *repository.DomainRegToken); ok {
+		r0 = rf(ctx, key, validity, orgID, domainType, claims)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*repository.DomainRegToken)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []byte, time.Duration, string, public.DomainType) error); ok {
-		r1 = rf(ctx, key, validity, orgID, domainType)
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, time.Duration, string, public.DomainType, domain_token.Claims) error); ok {
+		r1 = rf(ctx, key, validity, orgID, domainType, claims)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -52,6 +54,24 @@ func (_m *DomainRepository) CreateDomainToken(ctx context.Context, key []byte, v
 	return r0, r1
 }
 
+// DecideRegistration provides a mock function with given fields: ctx, orgID, data
+func (_m *DomainRepository) DecideRegistration(ctx context.Context, orgID string, data *model.Domain) error {
+	ret := _m.Called(ctx, orgID, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecideRegistration")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.Domain) error); ok {
+		r0 = rf(ctx, orgID, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteById provides a mock function with given fields: ctx, orgID, UUID
 func (_m *DomainRepository) DeleteById(ctx context.Context, orgID string, UUID uuid.UUID) error {
 	ret := _m.Called(ctx, orgID, UUID)
@@ -100,9 +120,57 @@ func (_m *DomainRepository) FindByID(ctx context.Context, orgID string, UUID uui
 	return r0, r1
 }
 
-// List provides a mock function with given fields: ctx, orgID, offset, limit
-func (_m *DomainRepository) List(ctx context.Context, orgID string, offset int, limit int) ([]model.Domain, int64, error) {
-	ret := _m.Called(ctx, orgID, offset, limit)
+// FindOrgSettings provides a mock function with given fields: ctx, orgID
+func (_m *DomainRepository) FindOrgSettings(ctx context.Context, orgID string) (*model.OrgSettings, error) {
+	ret := _m.Called(ctx, orgID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOrgSettings")
+	}
+
+	var r0 *model.OrgSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.OrgSettings, error)); ok {
+		return rf(ctx, orgID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.OrgSettings); ok {
+		r0 = rf(ctx, orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OrgSettings)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindRegistration provides a mock function with given fields: ctx, orgID, data
+func (_m *DomainRepository) FindRegistration(ctx context.Context, orgID string, data *model.Domain) error {
+	ret := _m.Called(ctx, orgID, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindRegistration")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.Domain) error); ok {
+		r0 = rf(ctx, orgID, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: ctx, orgID, domainIDs, offset, limit
+func (_m *DomainRepository) List(ctx context.Context, orgID string, domainIDs []uuid.UUID, offset int, limit int) ([]model.Domain, int64, error) {
+	ret := _m.Called(ctx, orgID, domainIDs, offset, limit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
@@ -111,25 +179,25 @@ func (_m *DomainRepository) List(ctx context.Context, orgID string, offset int,
 	var r0 []model.Domain
 	var r1 int64
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]model.Domain, int64, error)); ok {
-		return rf(ctx, orgID, offset, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID, int, int) ([]model.Domain, int64, error)); ok {
+		return rf(ctx, orgID, domainIDs, offset, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []model.Domain); ok {
-		r0 = rf(ctx, orgID, offset, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []uuid.UUID, int, int) []model.Domain); ok {
+		r0 = rf(ctx, orgID, domainIDs, offset, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Domain)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int64); ok {
-		r1 = rf(ctx, orgID, offset, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []uuid.UUID, int, int) int64); ok {
+		r1 = rf(ctx, orgID, domainIDs, offset, limit)
 	} else {
 		r1 = ret.Get(1).(int64)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
-		r2 = rf(ctx, orgID, offset, limit)
+	if rf, ok := ret.Get(2).(func(context.Context, string, []uuid.UUID, int, int) error); ok {
+		r2 = rf(ctx, orgID, domainIDs, offset, limit)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -137,6 +205,36 @@ func (_m *DomainRepository) List(ctx context.Context, orgID string, offset int,
 	return r0, r1, r2
 }
 
+// ListLocationSubnets provides a mock function with given fields: ctx, orgID, UUID
+func (_m *DomainRepository) ListLocationSubnets(ctx context.Context, orgID string, UUID uuid.UUID) ([]model.DomainLocationSubnet, error) {
+	ret := _m.Called(ctx, orgID, UUID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLocationSubnets")
+	}
+
+	var r0 []model.DomainLocationSubnet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) ([]model.DomainLocationSubnet, error)); ok {
+		return rf(ctx, orgID, UUID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) []model.DomainLocationSubnet); ok {
+		r0 = rf(ctx, orgID, UUID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.DomainLocationSubnet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, orgID, UUID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Register provides a mock function with given fields: ctx, orgID, data
 func (_m *DomainRepository) Register(ctx context.Context, orgID string, data *model.Domain) error {
 	ret := _m.Called(ctx, orgID, data)
@@ -155,6 +253,24 @@ func (_m *DomainRepository) Register(ctx context.Context, orgID string, data *mo
 	return r0
 }
 
+// SaveOrgSettings provides a mock function with given fields: ctx, data
+func (_m *DomainRepository) SaveOrgSettings(ctx context.Context, data *model.OrgSettings) error {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveOrgSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.OrgSettings) error); ok {
+		r0 = rf(ctx, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateAgent provides a mock function with given fields: ctx, orgID, data
 func (_m *DomainRepository) UpdateAgent(ctx context.Context, orgID string, data *model.Domain) error {
 	ret := _m.Called(ctx, orgID, data)
@@ -173,6 +289,24 @@ func (_m *DomainRepository) UpdateAgent(ctx context.Context, orgID string, data
 	return r0
 }
 
+// UpdateLocationSubnets provides a mock function with given fields: ctx, orgID, UUID, data
+func (_m *DomainRepository) UpdateLocationSubnets(ctx context.Context, orgID string, UUID uuid.UUID, data []model.DomainLocationSubnet) error {
+	ret := _m.Called(ctx, orgID, UUID, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLocationSubnets")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, []model.DomainLocationSubnet) error); ok {
+		r0 = rf(ctx, orgID, UUID, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUser provides a mock function with given fields: ctx, orgID, data
 func (_m *DomainRepository) UpdateUser(ctx context.Context, orgID string, data *model.Domain) error {
 	ret := _m.Called(ctx, orgID, data)
@@ -48,6 +48,36 @@ func (_m *HostRepository) MatchDomain(ctx context.Context, options *interactor.H
 	return r0, r1
 }
 
+// MatchLocation provides a mock function with given fields: ctx, options, domain
+func (_m *HostRepository) MatchLocation(ctx context.Context, options *interactor.HostConfOptions, domain *model.Domain) (*string, error) {
+	ret := _m.Called(ctx, options, domain)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MatchLocation")
+	}
+
+	var r0 *string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *interactor.HostConfOptions, *model.Domain) (*string, error)); ok {
+		return rf(ctx, options, domain)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *interactor.HostConfOptions, *model.Domain) *string); ok {
+		r0 = rf(ctx, options, domain)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *interactor.HostConfOptions, *model.Domain) error); ok {
+		r1 = rf(ctx, options, domain)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SignHostConfToken provides a mock function with given fields: ctx, privs, options, domain
 func (_m *HostRepository) SignHostConfToken(ctx context.Context, privs []jwk.Key, options *interactor.HostConfOptions, domain *model.Domain) (string, error) {
 	ret := _m.Called(ctx, privs, options, domain)
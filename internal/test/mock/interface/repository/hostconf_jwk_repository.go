@@ -163,6 +163,45 @@ func (_m *HostconfJwkRepository) PurgeExpiredJWKs(ctx context.Context) ([]model.
 	return r0, r1
 }
 
+// ReencryptJWKs provides a mock function with given fields: ctx
+func (_m *HostconfJwkRepository) ReencryptJWKs(ctx context.Context) ([]model.HostconfJwk, []model.HostconfJwk, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReencryptJWKs")
+	}
+
+	var r0 []model.HostconfJwk
+	var r1 []model.HostconfJwk
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.HostconfJwk, []model.HostconfJwk, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.HostconfJwk); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.HostconfJwk)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) []model.HostconfJwk); ok {
+		r1 = rf(ctx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]model.HostconfJwk)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // RevokeJWK provides a mock function with given fields: ctx, kid
 func (_m *HostconfJwkRepository) RevokeJWK(ctx context.Context, kid string) (*model.HostconfJwk, error) {
 	ret := _m.Called(ctx, kid)
@@ -12,6 +12,7 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/datastore"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	repository_impl "github.com/podengo-project/idmsvc-backend/internal/usecase/repository"
 	"go.openly.dev/pointy"
 	"gorm.io/gorm"
@@ -76,7 +77,7 @@ func createIPADomainData(orgNumber int) *model.Domain {
 	domainName := orgID + ".example.test"
 
 	currentTime := time.Now()
-	testIpaCert := model.IpaCert{
+	testIpaCert := ipa.IpaCert{
 		IpaID:        uint(orgNumber),
 		Nickname:     "IPA.TEST IPA CA",
 		Issuer:       "CN=Certificate Authority,O=IPA.TEST",
@@ -86,7 +87,7 @@ func createIPADomainData(orgNumber int) *model.Domain {
 		NotAfter:     currentTime,
 		Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
 	}
-	testIpaServer := model.IpaServer{
+	testIpaServer := ipa.IpaServer{
 		IpaID:               uint(orgNumber),
 		FQDN:                "server1.ipa.test",
 		RHSMId:              pointy.String(uuid.NewString()),
@@ -104,12 +105,12 @@ func createIPADomainData(orgNumber int) *model.Domain {
 		Title:                 pointy.String(domainName),
 		Description:           pointy.String(""),
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain: &model.Ipa{
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
 			RealmName:    pointy.String(""),
-			CaCerts:      []model.IpaCert{testIpaCert},
-			Servers:      []model.IpaServer{testIpaServer},
-			Locations:    []model.IpaLocation{},
+			CaCerts:      []ipa.IpaCert{testIpaCert},
+			Servers:      []ipa.IpaServer{testIpaServer},
+			Locations:    []ipa.IpaLocation{},
 			RealmDomains: pq.StringArray{domainName},
 		},
 	}
@@ -127,7 +128,7 @@ func createDomain(
 
 	// Delete existing domains to ensure only one domain per organization
 	// E.g. when re-running the test after some cleanup issues.
-	domains, _, err := domainRepository.List(ctx, domain.OrgId, 0, 10)
+	domains, _, err := domainRepository.List(ctx, domain.OrgId, nil, 0, 10)
 	if err != nil {
 		return nil, err
 	}
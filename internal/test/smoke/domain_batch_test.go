@@ -0,0 +1,214 @@
+package smoke
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	mock_rbac "github.com/podengo-project/idmsvc-backend/internal/infrastructure/service/impl/mock/rbac/impl"
+	builder_api "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+// SuiteDomainBatch is the suite to validate the smoke test when running operations on several domains at POST /api/idmsvc/v1/domains:batch
+type SuiteDomainBatch struct {
+	SuiteBaseWithDomain
+	audit *auditRecorder
+}
+
+// auditRecorder is a slog.Handler which keep the audit records
+// written by the service, and forward every record to next.
+type auditRecorder struct {
+	next    slog.Handler
+	mutex   *sync.Mutex
+	records *[]map[string]any
+}
+
+func newAuditRecorder(next slog.Handler) *auditRecorder {
+	return &auditRecorder{
+		next:    next,
+		mutex:   &sync.Mutex{},
+		records: &[]map[string]any{},
+	}
+}
+
+func (h *auditRecorder) Enabled(ctx context.Context, level slog.Level) bool {
+	return level == slog.LevelInfo || h.next.Enabled(ctx, level)
+}
+
+func (h *auditRecorder) Handle(ctx context.Context, r slog.Record) error {
+	if r.Message == "audit" {
+		record := map[string]any{}
+		r.Attrs(func(attr slog.Attr) bool {
+			record[attr.Key] = attr.Value.Any()
+			return true
+		})
+		h.mutex.Lock()
+		*h.records = append(*h.records, record)
+		h.mutex.Unlock()
+	}
+	if !h.next.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *auditRecorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &auditRecorder{next: h.next.WithAttrs(attrs), mutex: h.mutex, records: h.records}
+}
+
+func (h *auditRecorder) WithGroup(name string) slog.Handler {
+	return &auditRecorder{next: h.next.WithGroup(name), mutex: h.mutex, records: h.records}
+}
+
+// Records return the audit records written for the batch route.
+func (h *auditRecorder) Records() []map[string]any {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	result := []map[string]any{}
+	for _, record := range *h.records {
+		if _, ok := record["batch_route"]; ok {
+			result = append(result, record)
+		}
+	}
+	return result
+}
+
+func (s *SuiteDomainBatch) SetupTest() {
+	s.SuiteBaseWithDomain.SetupTest()
+
+	// Domain 2 in OrgID1
+	s.As(RBACAdmin, XRHIDUser)
+	token, err := s.CreateToken()
+	if err != nil {
+		s.FailNow("error creating token")
+	}
+	domainRequest := builder_api.NewDomain("domain1.test").Build()
+	setFirstServerRHSMId(s.T(), domainRequest, s.systemXRHID)
+	setFirstAsUpdateServer(domainRequest)
+	s.As(XRHIDSystem)
+	domain, err := s.RegisterIpaDomain(token.DomainToken, domainRequest)
+	if err != nil {
+		s.FailNow("error creating rhel-idm domain")
+	}
+	s.Domains = append(s.Domains, domain)
+
+	// The logger middleware read the default logger for every request
+	s.audit = newAuditRecorder(slog.Default().Handler())
+	slog.SetDefault(slog.New(s.audit))
+}
+
+func (s *SuiteDomainBatch) TearDownTest() {
+	slog.SetDefault(slog.New(s.audit.next))
+	s.audit = nil
+	s.SuiteBaseWithDomain.TearDownTest()
+}
+
+func (s *SuiteDomainBatch) updateTitle(domainID uuid.UUID, title string) public.BatchDomainOperation {
+	return public.BatchDomainOperation{
+		DomainId: domainID,
+		Op:       public.Update,
+		Update: builder_api.NewUpdateDomainUserRequest().
+			WithTitle(pointy.String(title)).
+			Build(),
+	}
+}
+
+func (s *SuiteDomainBatch) TestBatchDomainsRbacDenial() {
+	t := s.T()
+	domain0, domain1 := *s.Domains[0].DomainId, *s.Domains[1].DomainId
+
+	// GIVEN the permissions are granted only for the first domain
+	s.As(XRHIDUser)
+	s.RbacMock.SetDomainPermissions(
+		mock_rbac.Profiles[string(RBACAdmin)],
+		[]string{domain0.String()},
+	)
+
+	// WHEN
+	result, err := s.BatchDomains(&public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{
+			s.updateTitle(domain0, "batch title 0"),
+			s.updateTitle(domain1, "batch title 1"),
+		},
+	})
+
+	// THEN only the operation on the allowed domain is applied
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.Atomic)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, http.StatusOK, result.Results[0].Status)
+	assert.Nil(t, result.Results[0].Error)
+	assert.Equal(t, http.StatusForbidden, result.Results[1].Status)
+	require.NotNil(t, result.Results[1].Error)
+	assert.Nil(t, result.Results[1].Domain)
+
+	s.As(RBACAdmin)
+	domain, err := s.ReadDomain(domain1)
+	require.NoError(t, err)
+	assert.NotEqual(t, pointy.String("batch title 1"), domain.Title)
+}
+
+func (s *SuiteDomainBatch) TestBatchDomainsAtomicRollback() {
+	t := s.T()
+	domain0, domain1 := *s.Domains[0].DomainId, *s.Domains[1].DomainId
+	s.As(RBACAdmin, XRHIDUser)
+	before, err := s.ReadDomain(domain0)
+	require.NoError(t, err)
+
+	// WHEN the last operation fails on an atomic batch
+	res, err := s.BatchDomainsWithResponse(&public.BatchDomainsRequest{
+		Atomic: pointy.Bool(true),
+		Operations: []public.BatchDomainOperation{
+			s.updateTitle(domain0, "batch title 0"),
+			{DomainId: domain1, Op: public.Delete},
+			{DomainId: uuid.New(), Op: public.Delete},
+		},
+	})
+
+	// THEN no operation is applied
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	after, err := s.ReadDomain(domain0)
+	require.NoError(t, err)
+	assert.Equal(t, before.Title, after.Title)
+	_, err = s.ReadDomain(domain1)
+	assert.NoError(t, err)
+}
+
+func (s *SuiteDomainBatch) TestBatchDomainsAudit() {
+	t := s.T()
+	domain0, domain1 := *s.Domains[0].DomainId, *s.Domains[1].DomainId
+	s.As(RBACAdmin, XRHIDUser)
+
+	// WHEN
+	result, err := s.BatchDomains(&public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{
+			s.updateTitle(domain0, "batch title 0"),
+			{DomainId: domain1, Op: public.Delete},
+		},
+	})
+
+	// THEN every operation has the audit record of its own endpoint
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Len(t, result.Results, 2)
+	records := s.audit.Records()
+	require.Len(t, records, 2)
+	assert.Equal(t, http.MethodPatch, records[0]["method"])
+	assert.Equal(t, "/api/idmsvc/v1/domains/"+domain0.String(), records[0]["uri"])
+	assert.Equal(t, int64(http.StatusOK), records[0]["status"])
+	assert.Equal(t, int64(0), records[0]["batch_index"])
+	assert.Equal(t, http.MethodDelete, records[1]["method"])
+	assert.Equal(t, "/api/idmsvc/v1/domains/"+domain1.String(), records[1]["uri"])
+	assert.Equal(t, int64(http.StatusNoContent), records[1]["status"])
+	assert.Equal(t, int64(1), records[1]["batch_index"])
+}
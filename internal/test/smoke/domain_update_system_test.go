@@ -78,7 +78,7 @@ func (s *SuiteDomainUpdateAgent) TestUpdateDomain() {
 	okRequest := s.buildUpdateAgentRequest(domainName)
 
 	expectedResponse := s.Domains[0]
-	expectedResponse.RhelIdm = &okRequest.RhelIdm
+	expectedResponse.RhelIdm = okRequest.RhelIdm
 
 	test_header := http.Header{
 		header.HeaderXRequestID: {"test_domain_update"},
@@ -107,7 +107,7 @@ func (s *SuiteDomainUpdateAgent) TestUpdateDomain() {
 			},
 			Expected: TestCaseExpect{
 				StatusCode: http.StatusBadRequest,
-				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponse) error {
+				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponseApplicationJSON) error {
 					assert.Equal(t, builder_api.NewErrorResponse().
 						Add(*builder_api.NewErrorInfo(http.StatusBadRequest).
 							WithTitle("'domain_name' may not be changed").
@@ -128,7 +128,7 @@ func (s *SuiteDomainUpdateAgent) TestUpdateDomain() {
 			},
 			Expected: TestCaseExpect{
 				StatusCode: http.StatusBadRequest,
-				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponse) error {
+				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponseApplicationJSON) error {
 					assert.Equal(t, builder_api.NewErrorResponse().
 						Add(*builder_api.NewErrorInfo(http.StatusBadRequest).
 							WithTitle("'realm_name' may not be changed").
@@ -149,7 +149,7 @@ func (s *SuiteDomainUpdateAgent) TestUpdateDomain() {
 			},
 			Expected: TestCaseExpect{
 				StatusCode: http.StatusBadRequest,
-				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponse) error {
+				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponseApplicationJSON) error {
 					assert.Equal(t, builder_api.NewErrorResponse().
 						Add(*builder_api.NewErrorInfo(http.StatusBadRequest).
 							WithTitle("update server's 'Subscription Manager ID' not found in the authorized list of rhel-idm servers").
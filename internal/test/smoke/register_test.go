@@ -112,7 +112,7 @@ func (s *SuiteRegisterDomain) TestRegisterDomain() {
 			},
 			Expected: TestCaseExpect{
 				StatusCode: http.StatusBadRequest,
-				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponse) error {
+				BodyFunc: WrapBodyFuncErrorResponse(func(t *testing.T, body *public.ErrorResponseApplicationJSON) error {
 					assert.Equal(t, builder_api.NewErrorResponse().
 						Add(*builder_api.NewErrorInfo(http.StatusBadRequest).
 							WithTitle("update server's 'Subscription Manager ID' not found in the authorized list of rhel-idm servers").
@@ -95,7 +95,7 @@ func (s *SuiteLimitSize) TestReadTimeout() {
 	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
 	errCurrentBytes, err := io.ReadAll(resp.Body)
 	require.NoError(t, err)
-	errCurrent := &public.ErrorResponse{}
+	errCurrent := &public.ErrorResponseApplicationJSON{}
 	err = json.Unmarshal(errCurrentBytes, errCurrent)
 	require.NoError(t, err)
 	require.NotNil(t, errCurrent.Errors)
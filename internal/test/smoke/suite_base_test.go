@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -35,7 +36,6 @@ import (
 
 	service_impl "github.com/podengo-project/idmsvc-backend/internal/infrastructure/service/impl"
 	client_pendo "github.com/podengo-project/idmsvc-backend/internal/usecase/client/pendo"
-	client_rbac "github.com/podengo-project/idmsvc-backend/internal/usecase/client/rbac"
 )
 
 type XRHIDProfile string
@@ -124,6 +124,8 @@ func (s *SuiteBase) SetupTest() {
 	logger.InitLogger(s.Config, "test-suite")
 	s.db = datastore.NewDB(s.Config)
 	require.NotNil(t, s.db)
+	// The readiness probe checks there is a valid signing key
+	require.NoError(t, datastore.NewHostconfJwkDb(s.Config, slog.Default()).Refresh())
 
 	ctx, cancel := StartSignalHandler(context.Background())
 	require.NotNil(t, ctx)
@@ -135,15 +137,10 @@ func (s *SuiteBase) SetupTest() {
 	require.NoError(t, s.svcRbac.Start())
 	require.NoError(t, s.RbacMock.WaitAddress(3*time.Second))
 	s.As(RBACSuperAdmin)
-	rbacClient, err := client_rbac.NewClient("idmsvc", client_rbac.WithBaseURL(s.Config.Clients.RbacBaseURL))
-	if err != nil {
-		panic(err)
-	}
-	rbac := client_rbac.New(s.Config.Clients.RbacBaseURL, rbacClient)
 	if s.PendoClient == nil {
 		s.PendoClient = client_pendo.NewClient(s.Config)
 	}
-	s.svc = service_impl.NewApplication(ctx, s.wg, s.Config, s.db, rbac, s.PendoClient)
+	s.svc = service_impl.NewApplication(ctx, s.wg, s.Config, s.db, s.PendoClient)
 	go func() {
 		if e := s.svc.Start(); e != nil {
 			panic(e)
@@ -565,6 +562,47 @@ func (s *SuiteBase) PatchDomain(domainID string, domain *public.UpdateDomainUser
 	return result, nil
 }
 
+func (s *SuiteBase) BatchDomainsWithResponse(batch *public.BatchDomainsRequest) (*http.Response, error) {
+	hdr := http.Header{}
+	url := s.DefaultPublicBaseURL() + "/domains:batch"
+	method := http.MethodPost
+	s.addRequestID(&hdr, "test_batch_domains")
+	resp, err := s.DoRequest(
+		method,
+		url,
+		hdr,
+		batch,
+	)
+	return resp, err
+}
+
+// BatchDomains run a list of operations on the domains.
+// batch is the list of operations.
+// Return the results of the operations, or a filled error when
+// the request does not succeed.
+func (s *SuiteBase) BatchDomains(batch *public.BatchDomainsRequest) (*public.BatchDomainsResponse, error) {
+	url := s.DefaultPublicBaseURL() + "/domains:batch"
+	resp, err := s.BatchDomainsWithResponse(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failure when POST %s: expected '%d' but got '%d'", url, http.StatusOK, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	result := &public.BatchDomainsResponse{}
+	if err = json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (s *SuiteBase) HostConfWithResponse(inventoryID string, fqdn string, hostconf *public.HostConf) (*http.Response, error) {
 	hdr := http.Header{}
 	url := s.DefaultPublicBaseURL() + "/host-conf/" + inventoryID + "/" + fqdn
@@ -875,7 +913,7 @@ func TearDownSignalHandler() {
 	signal.Reset(syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 }
 
-type BodyFuncErrorResponse func(t *testing.T, body *public.ErrorResponse) error
+type BodyFuncErrorResponse func(t *testing.T, body *public.ErrorResponseApplicationJSON) error
 
 func WrapBodyFuncErrorResponse(predicate BodyFuncErrorResponse) BodyFunc {
 	if predicate == nil {
@@ -884,7 +922,7 @@ func WrapBodyFuncErrorResponse(predicate BodyFuncErrorResponse) BodyFunc {
 		}
 	}
 	return func(t *testing.T, body []byte) bool {
-		var data public.ErrorResponse
+		var data public.ErrorResponseApplicationJSON
 		if err := json.Unmarshal(body, &data); err != nil {
 			require.Fail(t, fmt.Errorf("Error unmarshalling body: %w", err).Error())
 			return false
@@ -907,6 +945,7 @@ func TestSuite(t *testing.T) {
 	suite.Run(t, new(SuiteDomainUpdateAgent))
 	suite.Run(t, new(SuiteListDomains))
 	suite.Run(t, new(SuiteDeleteDomain))
+	suite.Run(t, new(SuiteDomainBatch))
 	suite.Run(t, new(SuiteRbacPermission))
 	suite.Run(t, new(SuiteSystemEndpoints))
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"gorm.io/gorm"
 )
 
@@ -30,7 +31,7 @@ func PrepSqlSelectDomainsByID(mock sqlmock.Sqlmock, withError bool, expectedErr
 
 			"org_id", "domain_uuid", "domain_name",
 			"title", "description", "type",
-			"auto_enrollment_enabled",
+			"auto_enrollment_enabled", "registration_state",
 		}).
 			AddRow(
 				domainID,
@@ -45,6 +46,7 @@ func PrepSqlSelectDomainsByID(mock sqlmock.Sqlmock, withError bool, expectedErr
 				data.Description,
 				data.Type,
 				autoenrollment,
+				data.RegistrationState,
 			))
 	}
 }
@@ -174,7 +176,7 @@ func UpdateUser(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID uin
 				FindByID(1, mock, expectedErr, domainID, data)
 			} else {
 				FindByID(1, mock, nil, domainID, data)
-				FindIpaByID(4, mock, nil, domainID, data)
+				FindIpaByID(6, mock, nil, domainID, data)
 			}
 		case 2: // Update
 			PrepSqlUpdateDomainsForUser(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
@@ -185,7 +187,7 @@ func UpdateUser(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID uin
 }
 
 func PrepSqlInsertIntoDomains(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Domain) {
-	expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "domains" ("created_at","updated_at","deleted_at","org_id","domain_uuid","domain_name","title","description","type","auto_enrollment_enabled","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING "id"`)).
+	expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "domains" ("created_at","updated_at","deleted_at","org_id","domain_uuid","domain_name","title","description","type","auto_enrollment_enabled","registration_state","id") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING "id"`)).
 		WithArgs(
 			data.Model.CreatedAt,
 			data.Model.UpdatedAt,
@@ -198,6 +200,7 @@ func PrepSqlInsertIntoDomains(mock sqlmock.Sqlmock, withError bool, expectedErr
 			data.Description,
 			data.Type,
 			data.AutoEnrollmentEnabled,
+			registrationState(data),
 
 			data.Model.ID,
 		)
@@ -208,6 +211,15 @@ func PrepSqlInsertIntoDomains(mock sqlmock.Sqlmock, withError bool, expectedErr
 	}
 }
 
+// registrationState return the state stored for a new domain, which
+// is active when it is empty.
+func registrationState(data *model.Domain) string {
+	if data.RegistrationState == "" {
+		return model.DomainRegistrationActive
+	}
+	return data.RegistrationState
+}
+
 func Register(stage int, mock sqlmock.Sqlmock, expectedErr error, data *model.Domain) {
 	for i := 1; i <= stage; i++ {
 		switch i {
@@ -220,7 +232,7 @@ func Register(stage int, mock sqlmock.Sqlmock, expectedErr error, data *model.Do
 }
 
 func PrepSqlSelectFromDomainsFilterMatchDomain(mock sqlmock.Sqlmock, withError bool, expectedErr error, options *interactor.HostConfOptions, domains []model.Domain) {
-	expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`SELECT "domains"."id","domains"."created_at","domains"."updated_at","domains"."deleted_at","domains"."org_id","domains"."domain_uuid","domains"."domain_name","domains"."title","domains"."description","domains"."type","domains"."auto_enrollment_enabled" FROM "domains" left join ipas on domains.id = ipas.id WHERE domains.org_id = $1 AND domains.domain_uuid = $2 AND domains.domain_name = $3 AND domains.type = $4 AND "domains"."deleted_at" IS NULL`)).
+	expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`SELECT "domains"."id","domains"."created_at","domains"."updated_at","domains"."deleted_at","domains"."org_id","domains"."domain_uuid","domains"."domain_name","domains"."title","domains"."description","domains"."type","domains"."auto_enrollment_enabled","domains"."registration_state" FROM "domains" left join ipas on domains.id = ipas.id WHERE domains.org_id = $1 AND domains.domain_uuid = $2 AND domains.domain_name = $3 AND domains.type = $4 AND "domains"."deleted_at" IS NULL`)).
 		WithArgs(
 			options.OrgId,
 			options.DomainId,
@@ -235,7 +247,7 @@ func PrepSqlSelectFromDomainsFilterMatchDomain(mock sqlmock.Sqlmock, withError b
 
 			"org_id", "domain_uuid", "domain_name",
 			"title", "description", "type",
-			"auto_enrollment_enabled",
+			"auto_enrollment_enabled", "registration_state",
 		})
 		for j := range domains {
 			rows.AddRow(
@@ -251,6 +263,7 @@ func PrepSqlSelectFromDomainsFilterMatchDomain(mock sqlmock.Sqlmock, withError b
 				domains[j].Description,
 				domains[j].Type,
 				domains[j].AutoEnrollmentEnabled,
+				domains[j].RegistrationState,
 			)
 		}
 		expectQuery = expectQuery.WillReturnRows(rows)
@@ -266,7 +279,7 @@ func MatchDomain(stage int, mock sqlmock.Sqlmock, expectedErr error, options *in
 			if len(domains) == 0 {
 				FindIpaByID(1, mock, expectedErr, domains[0].ID, &domains[0])
 			}
-			FindIpaByID(4, mock, expectedErr, domains[0].ID, &domains[0])
+			FindIpaByID(6, mock, expectedErr, domains[0].ID, &domains[0])
 		default:
 			panic(fmt.Sprintf("scenario %d/%d is not supported", i, stage))
 		}
@@ -281,14 +294,14 @@ func UpdateAgent(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID ui
 				FindByID(1, mock, expectedErr, domainID, data)
 			} else {
 				FindByID(1, mock, nil, domainID, data)
-				FindIpaByID(4, mock, nil, domainID, data)
+				FindIpaByID(6, mock, nil, domainID, data)
 			}
 		case 2:
 			PrepSqlUpdateDomainsForAgent(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
 		case 3:
 			PrepSqlDeleteFromIpas(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID)
 		case 4:
-			CreateIpaDomain(4, mock, expectedErr, domainID, data.IpaDomain)
+			CreateIpaDomain(4, mock, expectedErr, domainID, ipa.Data(data))
 		default:
 			panic(fmt.Sprintf("scenario %d/%d is not supported", i, stage))
 		}
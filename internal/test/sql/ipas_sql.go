@@ -7,6 +7,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 )
 
 func PrepSqlSelectIpas(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Domain) {
@@ -28,8 +29,8 @@ func PrepSqlSelectIpas(mock sqlmock.Sqlmock, withError bool, expectedErr error,
 			data.Model.UpdatedAt,
 			data.Model.DeletedAt,
 
-			data.IpaDomain.RealmName,
-			data.IpaDomain.RealmDomains,
+			ipa.Data(data).RealmName,
+			ipa.Data(data).RealmDomains,
 		))
 	}
 }
@@ -47,21 +48,21 @@ func PrepSqlSelectIpaCerts(mock sqlmock.Sqlmock, withError bool, expectedErr err
 			"not_after", "not_before", "serial_number",
 			"subject", "pem",
 		})
-		for j := range data.IpaDomain.CaCerts {
+		for j := range ipa.Data(data).CaCerts {
 			rows.AddRow(
 				domainID+uint(j)+1,
-				data.IpaDomain.CaCerts[j].Model.CreatedAt,
-				data.IpaDomain.CaCerts[j].Model.UpdatedAt,
-				data.IpaDomain.CaCerts[j].Model.DeletedAt,
+				ipa.Data(data).CaCerts[j].Model.CreatedAt,
+				ipa.Data(data).CaCerts[j].Model.UpdatedAt,
+				ipa.Data(data).CaCerts[j].Model.DeletedAt,
 
 				domainID,
-				data.IpaDomain.CaCerts[j].Issuer,
-				data.IpaDomain.CaCerts[j].Nickname,
-				data.IpaDomain.CaCerts[j].NotAfter,
-				data.IpaDomain.CaCerts[j].NotBefore,
-				data.IpaDomain.CaCerts[j].SerialNumber,
-				data.IpaDomain.CaCerts[j].Subject,
-				data.IpaDomain.CaCerts[j].Pem,
+				ipa.Data(data).CaCerts[j].Issuer,
+				ipa.Data(data).CaCerts[j].Nickname,
+				ipa.Data(data).CaCerts[j].NotAfter,
+				ipa.Data(data).CaCerts[j].NotBefore,
+				ipa.Data(data).CaCerts[j].SerialNumber,
+				ipa.Data(data).CaCerts[j].Subject,
+				ipa.Data(data).CaCerts[j].Pem,
 			)
 		}
 		expectedQuery.WillReturnRows(rows)
@@ -80,16 +81,48 @@ func PrepSqlSelectIpaLocations(mock sqlmock.Sqlmock, withError bool, expectedErr
 			"ipa_id",
 			"name", "description",
 		})
-		for j := range data.IpaDomain.Locations {
+		for j := range ipa.Data(data).Locations {
 			rows.AddRow(
 				domainID+uint(j)+1,
-				data.IpaDomain.Locations[j].Model.CreatedAt,
-				data.IpaDomain.Locations[j].Model.UpdatedAt,
-				data.IpaDomain.Locations[j].Model.DeletedAt,
+				ipa.Data(data).Locations[j].Model.CreatedAt,
+				ipa.Data(data).Locations[j].Model.UpdatedAt,
+				ipa.Data(data).Locations[j].Model.DeletedAt,
 
 				domainID,
-				data.IpaDomain.Locations[j].Name,
-				data.IpaDomain.Locations[j].Description,
+				ipa.Data(data).Locations[j].Name,
+				ipa.Data(data).Locations[j].Description,
+			)
+		}
+		expectedQuery.WillReturnRows(rows)
+	}
+}
+
+func PrepSqlSelectIpaIDRanges(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Domain) {
+	expectedQuery := mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ipa_id_ranges" WHERE "ipa_id_ranges"."ipa_id" = $1 AND "ipa_id_ranges"."deleted_at" IS NULL`)).
+		WithArgs(domainID)
+	if withError {
+		expectedQuery.WillReturnError(expectedErr)
+	} else {
+		rows := sqlmock.NewRows([]string{
+			"id", "created_at", "updated_at", "deleted_at",
+
+			"ipa_id", "name", "base_id", "range_size", "type",
+			"base_rid", "secondary_base_rid",
+		})
+		for j := range ipa.Data(data).IDRanges {
+			rows.AddRow(
+				domainID+uint(j)+1,
+				ipa.Data(data).IDRanges[j].Model.CreatedAt,
+				ipa.Data(data).IDRanges[j].Model.UpdatedAt,
+				ipa.Data(data).IDRanges[j].Model.DeletedAt,
+
+				domainID,
+				ipa.Data(data).IDRanges[j].Name,
+				ipa.Data(data).IDRanges[j].BaseID,
+				ipa.Data(data).IDRanges[j].RangeSize,
+				ipa.Data(data).IDRanges[j].Type,
+				ipa.Data(data).IDRanges[j].BaseRID,
+				ipa.Data(data).IDRanges[j].SecondaryBaseRID,
 			)
 		}
 		expectedQuery.WillReturnRows(rows)
@@ -109,27 +142,93 @@ func PrepSqlSelectIpaServers(mock sqlmock.Sqlmock, withError bool, expectedErr e
 			"ca_server", "hcc_enrollment_server", "hcc_update_server",
 			"pk_init_server",
 		})
-		for j := range data.IpaDomain.Servers {
+		for j := range ipa.Data(data).Servers {
 			rows.AddRow(
 				domainID+uint(j)+1,
-				data.IpaDomain.Servers[j].Model.CreatedAt,
-				data.IpaDomain.Servers[j].Model.UpdatedAt,
-				data.IpaDomain.Servers[j].Model.DeletedAt,
+				ipa.Data(data).Servers[j].Model.CreatedAt,
+				ipa.Data(data).Servers[j].Model.UpdatedAt,
+				ipa.Data(data).Servers[j].Model.DeletedAt,
 
 				domainID,
-				data.IpaDomain.Servers[j].FQDN,
-				data.IpaDomain.Servers[j].RHSMId,
-				data.IpaDomain.Servers[j].Location,
-				data.IpaDomain.Servers[j].CaServer,
-				data.IpaDomain.Servers[j].HCCEnrollmentServer,
-				data.IpaDomain.Servers[j].HCCUpdateServer,
-				data.IpaDomain.Servers[j].PKInitServer,
+				ipa.Data(data).Servers[j].FQDN,
+				ipa.Data(data).Servers[j].RHSMId,
+				ipa.Data(data).Servers[j].Location,
+				ipa.Data(data).Servers[j].CaServer,
+				ipa.Data(data).Servers[j].HCCEnrollmentServer,
+				ipa.Data(data).Servers[j].HCCUpdateServer,
+				ipa.Data(data).Servers[j].PKInitServer,
 			)
 		}
 		expectedQuery.WillReturnRows(rows)
 	}
 }
 
+// PrepSqlSelectIpaTrusts prepares the preload of the trusts and,
+// when there is any, the preload of their domains and ID ranges.
+func PrepSqlSelectIpaTrusts(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Domain) {
+	expectedQuery := mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ipa_trusts" WHERE "ipa_trusts"."ipa_id" = $1 AND "ipa_trusts"."deleted_at" IS NULL`)).
+		WithArgs(domainID)
+	if withError {
+		expectedQuery.WillReturnError(expectedErr)
+		return
+	}
+	rows := sqlmock.NewRows([]string{
+		"id", "created_at", "updated_at", "deleted_at",
+
+		"ipa_id", "forest_name", "flat_name", "sid", "direction",
+	})
+	trusts := ipa.Data(data).Trusts
+	trustIDs := make([]driver.Value, len(trusts))
+	for j := range trusts {
+		trustIDs[j] = domainID + uint(j) + 1
+		rows.AddRow(
+			trustIDs[j],
+			trusts[j].Model.CreatedAt,
+			trusts[j].Model.UpdatedAt,
+			trusts[j].Model.DeletedAt,
+
+			domainID,
+			trusts[j].ForestName,
+			trusts[j].FlatName,
+			trusts[j].SID,
+			trusts[j].Direction,
+		)
+	}
+	expectedQuery.WillReturnRows(rows)
+	if len(trusts) == 0 {
+		return
+	}
+
+	domainRows := sqlmock.NewRows([]string{
+		"id", "created_at", "updated_at", "deleted_at",
+
+		"ipa_trust_id", "name", "flat_name", "sid",
+	})
+	idRangeRows := sqlmock.NewRows([]string{
+		"id", "created_at", "updated_at", "deleted_at",
+
+		"ipa_trust_id", "name", "base_id", "range_size", "type",
+	})
+	for j := range trusts {
+		for k, domain := range trusts[j].Domains {
+			domainRows.AddRow(
+				k+1, domain.CreatedAt, domain.UpdatedAt, domain.DeletedAt,
+				trustIDs[j], domain.Name, domain.FlatName, domain.SID,
+			)
+		}
+		for k, idRange := range trusts[j].IDRanges {
+			idRangeRows.AddRow(
+				k+1, idRange.CreatedAt, idRange.UpdatedAt, idRange.DeletedAt,
+				trustIDs[j], idRange.Name, idRange.BaseID, idRange.RangeSize, idRange.Type,
+			)
+		}
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ipa_trust_domains" WHERE "ipa_trust_domains"."ipa_trust_id" `)).
+		WillReturnRows(domainRows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "ipa_trust_id_ranges" WHERE "ipa_trust_id_ranges"."ipa_trust_id" `)).
+		WillReturnRows(idRangeRows)
+}
+
 func FindIpaByID(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID uint, data *model.Domain) {
 	for i := 1; i <= stage; i++ {
 		switch i {
@@ -138,16 +237,20 @@ func FindIpaByID(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID ui
 		case 2:
 			PrepSqlSelectIpaCerts(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
 		case 3:
-			PrepSqlSelectIpaLocations(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
+			PrepSqlSelectIpaIDRanges(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
 		case 4:
+			PrepSqlSelectIpaLocations(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
+		case 5:
 			PrepSqlSelectIpaServers(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
+		case 6:
+			PrepSqlSelectIpaTrusts(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data)
 		default:
 			panic(fmt.Sprintf("scenario %d/%d is not supported", i, stage))
 		}
 	}
 }
 
-func PrepSqlInsertIntoIpas(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Ipa) {
+func PrepSqlInsertIntoIpas(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *ipa.Ipa) {
 	expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ipas" ("created_at","updated_at","deleted_at","realm_name","realm_domains","id") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
 		WithArgs(
 			data.Model.CreatedAt,
@@ -166,7 +269,7 @@ func PrepSqlInsertIntoIpas(mock sqlmock.Sqlmock, withError bool, expectedErr err
 	}
 }
 
-func PrepSqlInsertIntoIpaCerts(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Ipa) {
+func PrepSqlInsertIntoIpaCerts(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *ipa.Ipa) {
 	for j := range data.CaCerts {
 		expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ipa_certs" ("created_at","updated_at","deleted_at","ipa_id","issuer","nickname","not_after","not_before","pem","serial_number","subject") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING "id"`)).
 			WithArgs(
@@ -192,7 +295,7 @@ func PrepSqlInsertIntoIpaCerts(mock sqlmock.Sqlmock, withError bool, expectedErr
 	}
 }
 
-func PrepSqlInsertIntoIpaServers(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Ipa) {
+func PrepSqlInsertIntoIpaServers(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *ipa.Ipa) {
 	for j := range data.Servers {
 		expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ipa_servers" ("created_at","updated_at","deleted_at","ipa_id","fqdn","rhsm_id","location","ca_server","hcc_enrollment_server","hcc_update_server","pk_init_server") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING "id"`)).
 			WithArgs(
@@ -218,7 +321,7 @@ func PrepSqlInsertIntoIpaServers(mock sqlmock.Sqlmock, withError bool, expectedE
 	}
 }
 
-func PrepSqlInsertIntoIpaLocations(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *model.Ipa) {
+func PrepSqlInsertIntoIpaLocations(mock sqlmock.Sqlmock, withError bool, expectedErr error, domainID uint, data *ipa.Ipa) {
 	for j := range data.Locations {
 		expectQuery := mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "ipa_locations" ("created_at","updated_at","deleted_at","ipa_id","name","description") VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`)).
 			WithArgs(
@@ -239,7 +342,7 @@ func PrepSqlInsertIntoIpaLocations(mock sqlmock.Sqlmock, withError bool, expecte
 	}
 }
 
-func CreateIpaDomain(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID uint, data *model.Ipa) {
+func CreateIpaDomain(stage int, mock sqlmock.Sqlmock, expectedErr error, domainID uint, data *ipa.Ipa) {
 	for i := 1; i <= stage; i++ {
 		switch i {
 		case 1:
@@ -287,13 +390,13 @@ func UpdateIpaDomain(stage int, mock sqlmock.Sqlmock, expectedErr error, domainI
 		case 1:
 			PrepSqlDeleteFromIpas(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID)
 		case 2:
-			PrepSqlInsertIntoIpas(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data.IpaDomain)
+			PrepSqlInsertIntoIpas(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, ipa.Data(data))
 		case 3:
-			PrepSqlInsertIntoIpaCerts(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data.IpaDomain)
+			PrepSqlInsertIntoIpaCerts(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, ipa.Data(data))
 		case 4:
-			PrepSqlInsertIntoIpaServers(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data.IpaDomain)
+			PrepSqlInsertIntoIpaServers(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, ipa.Data(data))
 		case 5:
-			PrepSqlInsertIntoIpaLocations(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, data.IpaDomain)
+			PrepSqlInsertIntoIpaLocations(mock, WithPredicateExpectedError(i, stage, expectedErr), expectedErr, domainID, ipa.Data(data))
 		default:
 			panic(fmt.Sprintf("scenario %d/%d is not supported", i, stage))
 		}
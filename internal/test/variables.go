@@ -10,6 +10,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"go.openly.dev/pointy"
 	"gorm.io/gorm"
@@ -47,7 +48,7 @@ var (
 		NotBefore:    time.Date(2023, 3, 21, 5, 38, 9, 0, time.UTC),
 		NotAfter:     time.Date(2043, 3, 21, 5, 38, 9, 0, time.UTC),
 	}
-	IpaCaModelCert = model.IpaCert{
+	IpaCaModelCert = ipa.IpaCert{
 		Nickname:     IpaCaPublicCert.Nickname,
 		Issuer:       IpaCaPublicCert.Issuer,
 		Subject:      IpaCaPublicCert.Subject,
@@ -160,15 +161,15 @@ func BuildDomainModel(orgID string, domainID uint) *model.Domain {
 		Title:                 pointy.String("My Domain Example"),
 		Description:           pointy.String("Description of My Domain Example"),
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain: &model.Ipa{
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
 			Model: gorm.Model{
 				CreatedAt: currentTime,
 				UpdatedAt: currentTime,
 				DeletedAt: gorm.DeletedAt{},
 			},
 			RealmName: pointy.String(RealmName),
-			CaCerts: []model.IpaCert{
+			CaCerts: []ipa.IpaCert{
 				{
 					Model: gorm.Model{
 						CreatedAt: currentTime,
@@ -185,7 +186,7 @@ func BuildDomainModel(orgID string, domainID uint) *model.Domain {
 					Pem:          IpaCaPublicCert.Pem,
 				},
 			},
-			Servers: []model.IpaServer{
+			Servers: []ipa.IpaServer{
 				{
 					Model: gorm.Model{
 						CreatedAt: currentTime,
@@ -202,7 +203,7 @@ func BuildDomainModel(orgID string, domainID uint) *model.Domain {
 					PKInitServer:        true,
 				},
 			},
-			Locations: []model.IpaLocation{
+			Locations: []ipa.IpaLocation{
 				{
 					Model: gorm.Model{
 						CreatedAt: currentTime,
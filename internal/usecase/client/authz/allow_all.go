@@ -0,0 +1,23 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+)
+
+type allowAllAuthorizer struct{}
+
+// NewAllowAllAuthorizer create an authorizer which grant every
+// relation; it is used when the authorization is disabled.
+func NewAllowAllAuthorizer() authz.Authorizer {
+	return allowAllAuthorizer{}
+}
+
+func (allowAllAuthorizer) Check(ctx context.Context, subject *authz.Subject, relation string, resource *authz.Resource) (bool, error) {
+	return true, nil
+}
+
+func (allowAllAuthorizer) LookupResources(ctx context.Context, subject *authz.Subject, relation string, resourceType string) (*authz.ResourceSet, error) {
+	return &authz.ResourceSet{All: true}, nil
+}
@@ -0,0 +1,53 @@
+package authz
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	usecase_rbac "github.com/podengo-project/idmsvc-backend/internal/usecase/client/rbac"
+)
+
+// New create the authorizer for the provider selected at
+// cfg.Clients.AuthzProvider; every relation is granted when
+// cfg.Application.EnableRBAC is false.
+// cfg is the application configuration.
+// rbacConfig provide the cache, retries and circuit breaker for the
+// rbac provider; nil disables them.
+// Return the authorizer.
+func New(cfg *config.Config, rbacConfig *usecase_rbac.WrapperConfig) authz.Authorizer {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if !cfg.Application.EnableRBAC {
+		return NewAllowAllAuthorizer()
+	}
+	switch cfg.Clients.AuthzProvider {
+	case config.AuthzProviderRelations:
+		return NewRelationsAuthorizer(cfg.Clients.RelationsBaseURL, &http.Client{
+			Timeout:   cfg.Clients.RbacTimeout,
+			Transport: tracing.NewTransport(nil),
+		})
+	case config.AuthzProviderRbac, "":
+		if rbacConfig == nil {
+			rbacConfig = &usecase_rbac.WrapperConfig{}
+		}
+		base := strings.TrimSuffix(cfg.Clients.RbacBaseURL, "/")
+		client, err := usecase_rbac.NewClientWithResponses(
+			base,
+			usecase_rbac.WithHTTPClient(&http.Client{
+				Timeout:   cfg.Clients.RbacTimeout,
+				Transport: tracing.NewTransport(nil),
+			}),
+		)
+		if err != nil {
+			panic(fmt.Errorf("error creating rbac client: %w", err))
+		}
+		return NewRbacAuthorizer(usecase_rbac.NewWithConfig("idmsvc", client, rbacConfig))
+	default:
+		panic(fmt.Sprintf("authz provider '%s' is not supported", cfg.Clients.AuthzProvider))
+	}
+}
@@ -0,0 +1,46 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		New(nil, nil)
+	})
+
+	cfg := &config.Config{}
+	assert.IsType(t, allowAllAuthorizer{}, New(cfg, nil))
+
+	cfg.Application.EnableRBAC = true
+	cfg.Clients.RbacBaseURL = "http://localhost:8020/api/rbac/v1/"
+	assert.IsType(t, &rbacAuthorizer{}, New(cfg, nil))
+
+	cfg.Clients.AuthzProvider = config.AuthzProviderRelations
+	cfg.Clients.RelationsBaseURL = "http://localhost:8021/api/relations/v1"
+	assert.IsType(t, &relationsAuthorizer{}, New(cfg, nil))
+
+	cfg.Clients.AuthzProvider = "unknown"
+	assert.PanicsWithValue(t, "authz provider 'unknown' is not supported", func() {
+		New(cfg, nil)
+	})
+}
+
+func TestAllowAllAuthorizer(t *testing.T) {
+	ctx := context.Background()
+	a := NewAllowAllAuthorizer()
+
+	allowed, err := a.Check(ctx, helperSubject(), testPermission, &authz.Resource{Type: authz.ResourceTypeDomain, ID: testDomainID})
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	resources, err := a.LookupResources(ctx, helperSubject(), testPermission, authz.ResourceTypeDomain)
+	require.NoError(t, err)
+	assert.Equal(t, &authz.ResourceSet{All: true}, resources)
+}
@@ -0,0 +1,55 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+)
+
+type rbacAuthorizer struct {
+	client rbac.Rbac
+}
+
+// NewRbacAuthorizer create an authorizer backed by rbac v1, where
+// the relation is the rbac permission and the domains are filtered
+// by the resourceDefinitions of the ACL.
+func NewRbacAuthorizer(client rbac.Rbac) authz.Authorizer {
+	if client == nil {
+		panic("'client' is nil")
+	}
+	return &rbacAuthorizer{
+		client: client,
+	}
+}
+
+func (a *rbacAuthorizer) Check(ctx context.Context, subject *authz.Subject, relation string, resource *authz.Resource) (bool, error) {
+	if subject == nil {
+		return false, fmt.Errorf("'subject' is nil")
+	}
+	if resource == nil {
+		return false, fmt.Errorf("'resource' is nil")
+	}
+	if resource.Type != authz.ResourceTypeDomain {
+		return false, fmt.Errorf("resource type '%s' is not supported", resource.Type)
+	}
+	resources, err := a.client.AllowedResources(ctx, subject.XRHID, relation)
+	if err != nil {
+		return false, err
+	}
+	if resource.ID == "" {
+		return !resources.IsEmpty(), nil
+	}
+	return resources.Contains(resource.ID), nil
+}
+
+func (a *rbacAuthorizer) LookupResources(ctx context.Context, subject *authz.Subject, relation string, resourceType string) (*authz.ResourceSet, error) {
+	if subject == nil {
+		return nil, fmt.Errorf("'subject' is nil")
+	}
+	if resourceType != authz.ResourceTypeDomain {
+		return nil, fmt.Errorf("resource type '%s' is not supported", resourceType)
+	}
+	return a.client.AllowedResources(ctx, subject.XRHID, relation)
+}
@@ -0,0 +1,85 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	mock_rbac "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/client/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDomainID   = "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"
+	testOtherID    = "0b1f4a52-7a5c-4b6e-8c3e-5f5d2a9e1c77"
+	testPermission = "idmsvc:domains:update"
+)
+
+func helperSubject() *authz.Subject {
+	return &authz.Subject{
+		OrgID:     "12345",
+		Principal: "jdoe",
+		XRHID:     "xrhid",
+	}
+}
+
+func TestNewRbacAuthorizer(t *testing.T) {
+	assert.PanicsWithValue(t, "'client' is nil", func() {
+		NewRbacAuthorizer(nil)
+	})
+	assert.NotNil(t, NewRbacAuthorizer(mock_rbac.NewRbac(t)))
+}
+
+func TestRbacAuthorizerCheck(t *testing.T) {
+	ctx := context.Background()
+	subject := helperSubject()
+	collection := &authz.Resource{Type: authz.ResourceTypeDomain}
+	domain := &authz.Resource{Type: authz.ResourceTypeDomain, ID: testDomainID}
+	other := &authz.Resource{Type: authz.ResourceTypeDomain, ID: testOtherID}
+
+	client := mock_rbac.NewRbac(t)
+	a := NewRbacAuthorizer(client)
+
+	_, err := a.Check(ctx, nil, testPermission, domain)
+	assert.EqualError(t, err, "'subject' is nil")
+	_, err = a.Check(ctx, subject, testPermission, nil)
+	assert.EqualError(t, err, "'resource' is nil")
+	_, err = a.Check(ctx, subject, testPermission, &authz.Resource{Type: "host"})
+	assert.EqualError(t, err, "resource type 'host' is not supported")
+
+	client.On("AllowedResources", ctx, "xrhid", testPermission).Return(nil, fmt.Errorf("rbac is down")).Once()
+	_, err = a.Check(ctx, subject, testPermission, domain)
+	assert.EqualError(t, err, "rbac is down")
+
+	// Permission restricted to one domain
+	client.On("AllowedResources", ctx, "xrhid", testPermission).Return(&authz.ResourceSet{IDs: []string{testDomainID}}, nil)
+	allowed, err := a.Check(ctx, subject, testPermission, collection)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = a.Check(ctx, subject, testPermission, domain)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = a.Check(ctx, subject, testPermission, other)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestRbacAuthorizerLookupResources(t *testing.T) {
+	ctx := context.Background()
+	subject := helperSubject()
+	client := mock_rbac.NewRbac(t)
+	a := NewRbacAuthorizer(client)
+
+	_, err := a.LookupResources(ctx, nil, testPermission, authz.ResourceTypeDomain)
+	assert.EqualError(t, err, "'subject' is nil")
+	_, err = a.LookupResources(ctx, subject, testPermission, "host")
+	assert.EqualError(t, err, "resource type 'host' is not supported")
+
+	expected := &authz.ResourceSet{All: true}
+	client.On("AllowedResources", ctx, "xrhid", testPermission).Return(expected, nil)
+	resources, err := a.LookupResources(ctx, subject, testPermission, authz.ResourceTypeDomain)
+	require.NoError(t, err)
+	assert.Equal(t, expected, resources)
+}
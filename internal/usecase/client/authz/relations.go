@@ -0,0 +1,137 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	api_relations "github.com/podengo-project/idmsvc-backend/internal/api/relations"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+)
+
+type relationsAuthorizer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewRelationsAuthorizer create an authorizer backed by a
+// relationship-based check API.
+// A relation granted on the workspace of the organization is
+// granted for all its domains; else it is checked for the domain.
+// baseURL is the endpoint of the check API.
+// client is the http client used to reach it out.
+func NewRelationsAuthorizer(baseURL string, client *http.Client) authz.Authorizer {
+	if baseURL == "" {
+		panic("'baseURL' is an empty string")
+	}
+	if client == nil {
+		panic("'client' is nil")
+	}
+	return &relationsAuthorizer{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}
+}
+
+func (a *relationsAuthorizer) Check(ctx context.Context, subject *authz.Subject, relation string, resource *authz.Resource) (bool, error) {
+	if subject == nil {
+		return false, fmt.Errorf("'subject' is nil")
+	}
+	if resource == nil {
+		return false, fmt.Errorf("'resource' is nil")
+	}
+	if resource.Type != authz.ResourceTypeDomain {
+		return false, fmt.Errorf("resource type '%s' is not supported", resource.Type)
+	}
+	allowed, err := a.check(ctx, subject, relation, workspaceReference(subject))
+	if err != nil || allowed || resource.ID == "" {
+		return allowed, err
+	}
+	return a.check(ctx, subject, relation, api_relations.ObjectReference{
+		Type: api_relations.TypeDomain,
+		ID:   resource.ID,
+	})
+}
+
+func (a *relationsAuthorizer) LookupResources(ctx context.Context, subject *authz.Subject, relation string, resourceType string) (*authz.ResourceSet, error) {
+	var output api_relations.LookupResourcesResponse
+	if subject == nil {
+		return nil, fmt.Errorf("'subject' is nil")
+	}
+	if resourceType != authz.ResourceTypeDomain {
+		return nil, fmt.Errorf("resource type '%s' is not supported", resourceType)
+	}
+	allowed, err := a.check(ctx, subject, relation, workspaceReference(subject))
+	if err != nil {
+		return nil, err
+	}
+	if allowed {
+		return &authz.ResourceSet{All: true}, nil
+	}
+	if err = a.post(ctx, api_relations.PathLookupResources, &api_relations.LookupResourcesRequest{
+		Subject:      principalReference(subject),
+		Relation:     api_relations.RelationFromPermission(relation),
+		ResourceType: api_relations.TypeDomain,
+	}, &output); err != nil {
+		return nil, err
+	}
+	resources := &authz.ResourceSet{IDs: make([]string, 0, len(output.Resources))}
+	for i := range output.Resources {
+		resources.IDs = append(resources.IDs, output.Resources[i].ID)
+	}
+	return resources, nil
+}
+
+func (a *relationsAuthorizer) check(ctx context.Context, subject *authz.Subject, relation string, resource api_relations.ObjectReference) (bool, error) {
+	var output api_relations.CheckResponse
+	if err := a.post(ctx, api_relations.PathCheck, &api_relations.CheckRequest{
+		Subject:  principalReference(subject),
+		Relation: api_relations.RelationFromPermission(relation),
+		Resource: resource,
+	}, &output); err != nil {
+		return false, err
+	}
+	return output.Allowed, nil
+}
+
+func (a *relationsAuthorizer) post(ctx context.Context, path string, input any, output any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return &authz.UnavailableError{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("relations api '%s' returned status %d", path, resp.StatusCode)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return &authz.UnavailableError{Err: err}
+		}
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(output)
+}
+
+func principalReference(subject *authz.Subject) api_relations.ObjectReference {
+	return api_relations.ObjectReference{
+		Type: api_relations.TypePrincipal,
+		ID:   subject.Principal,
+	}
+}
+
+func workspaceReference(subject *authz.Subject) api_relations.ObjectReference {
+	return api_relations.ObjectReference{
+		Type: api_relations.TypeWorkspace,
+		ID:   subject.OrgID,
+	}
+}
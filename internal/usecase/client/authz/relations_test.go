@@ -0,0 +1,115 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api_relations "github.com/podengo-project/idmsvc-backend/internal/api/relations"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	mock_relations "github.com/podengo-project/idmsvc-backend/internal/infrastructure/service/impl/mock/relations/impl"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperRelationsServer(t *testing.T) (mock_relations.MockRelations, string) {
+	cfg := &config.Config{}
+	cfg.Clients.RelationsBaseURL = "http://localhost:8021/api/relations/v1"
+	_, m := mock_relations.NewRelationsMock(context.Background(), cfg)
+	srv := httptest.NewServer(m.Handler())
+	t.Cleanup(srv.Close)
+	return m, srv.URL + "/api/relations/v1"
+}
+
+func helperRelationship(subject *authz.Subject, resourceType, resourceID string) mock_relations.Relationship {
+	return mock_relations.Relationship{
+		Subject:  api_relations.ObjectReference{Type: api_relations.TypePrincipal, ID: subject.Principal},
+		Relation: api_relations.RelationFromPermission(testPermission),
+		Resource: api_relations.ObjectReference{Type: resourceType, ID: resourceID},
+	}
+}
+
+func TestNewRelationsAuthorizer(t *testing.T) {
+	assert.PanicsWithValue(t, "'baseURL' is an empty string", func() {
+		NewRelationsAuthorizer("", http.DefaultClient)
+	})
+	assert.PanicsWithValue(t, "'client' is nil", func() {
+		NewRelationsAuthorizer("http://localhost:8021", nil)
+	})
+}
+
+func TestRelationsAuthorizerCheck(t *testing.T) {
+	ctx := context.Background()
+	subject := helperSubject()
+	collection := &authz.Resource{Type: authz.ResourceTypeDomain}
+	domain := &authz.Resource{Type: authz.ResourceTypeDomain, ID: testDomainID}
+	other := &authz.Resource{Type: authz.ResourceTypeDomain, ID: testOtherID}
+	m, baseURL := helperRelationsServer(t)
+	a := NewRelationsAuthorizer(baseURL, http.DefaultClient)
+
+	_, err := a.Check(ctx, nil, testPermission, domain)
+	assert.EqualError(t, err, "'subject' is nil")
+	_, err = a.Check(ctx, subject, testPermission, nil)
+	assert.EqualError(t, err, "'resource' is nil")
+	_, err = a.Check(ctx, subject, testPermission, &authz.Resource{Type: "host"})
+	assert.EqualError(t, err, "resource type 'host' is not supported")
+
+	// No relationship
+	allowed, err := a.Check(ctx, subject, testPermission, domain)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	// Relationship with one domain
+	m.AddRelationship(helperRelationship(subject, api_relations.TypeDomain, testDomainID))
+	allowed, err = a.Check(ctx, subject, testPermission, domain)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = a.Check(ctx, subject, testPermission, other)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	allowed, err = a.Check(ctx, subject, testPermission, collection)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	// Relationship with the workspace
+	m.Reset()
+	m.AddRelationship(helperRelationship(subject, api_relations.TypeWorkspace, subject.OrgID))
+	for _, resource := range []*authz.Resource{collection, domain, other} {
+		allowed, err = a.Check(ctx, subject, testPermission, resource)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	// Errors from the api
+	a = NewRelationsAuthorizer(baseURL+"/missing", http.DefaultClient)
+	_, err = a.Check(ctx, subject, testPermission, domain)
+	assert.EqualError(t, err, "relations api '/check' returned status 404")
+}
+
+func TestRelationsAuthorizerLookupResources(t *testing.T) {
+	ctx := context.Background()
+	subject := helperSubject()
+	m, baseURL := helperRelationsServer(t)
+	a := NewRelationsAuthorizer(baseURL, http.DefaultClient)
+
+	_, err := a.LookupResources(ctx, nil, testPermission, authz.ResourceTypeDomain)
+	assert.EqualError(t, err, "'subject' is nil")
+	_, err = a.LookupResources(ctx, subject, testPermission, "host")
+	assert.EqualError(t, err, "resource type 'host' is not supported")
+
+	resources, err := a.LookupResources(ctx, subject, testPermission, authz.ResourceTypeDomain)
+	require.NoError(t, err)
+	assert.True(t, resources.IsEmpty())
+
+	m.AddRelationship(helperRelationship(subject, api_relations.TypeDomain, testDomainID))
+	resources, err = a.LookupResources(ctx, subject, testPermission, authz.ResourceTypeDomain)
+	require.NoError(t, err)
+	assert.Equal(t, &authz.ResourceSet{IDs: []string{testDomainID}}, resources)
+
+	m.AddRelationship(helperRelationship(subject, api_relations.TypeWorkspace, subject.OrgID))
+	resources, err = a.LookupResources(ctx, subject, testPermission, authz.ResourceTypeDomain)
+	require.NoError(t, err)
+	assert.Equal(t, &authz.ResourceSet{All: true}, resources)
+}
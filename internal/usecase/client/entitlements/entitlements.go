@@ -0,0 +1,33 @@
+package entitlements
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/entitlements"
+)
+
+// New create the entitlements source selected at
+// cfg.Clients.EntitlementsProvider.
+// cfg is the application configuration.
+// Return the entitlements source.
+func New(cfg *config.Config) entitlements.Entitlements {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	switch cfg.Clients.EntitlementsProvider {
+	case config.EntitlementsProviderHTTP:
+		return NewHTTPEntitlements(
+			cfg.Clients.EntitlementsBaseURL,
+			cfg.Clients.EntitlementsBundle,
+			cfg.Clients.EntitlementsCacheTTL,
+			&http.Client{Timeout: cfg.Clients.EntitlementsTimeout, Transport: tracing.NewTransport(nil)},
+		)
+	case config.EntitlementsProviderStub, "":
+		return NewStubEntitlements()
+	default:
+		panic(fmt.Sprintf("entitlements provider '%s' is not supported", cfg.Clients.EntitlementsProvider))
+	}
+}
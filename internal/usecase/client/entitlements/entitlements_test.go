@@ -0,0 +1,38 @@
+package entitlements
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		New(nil)
+	})
+
+	cfg := &config.Config{}
+	assert.IsType(t, stubEntitlements{}, New(cfg))
+
+	cfg.Clients.EntitlementsProvider = config.EntitlementsProviderHTTP
+	cfg.Clients.EntitlementsBaseURL = "http://localhost:8023/api/entitlements/v1"
+	cfg.Clients.EntitlementsBundle = config.DefaultEntitlementsBundle
+	cfg.Clients.EntitlementsTimeout = 2 * time.Second
+	require.IsType(t, &httpEntitlements{}, New(cfg))
+	assert.Equal(t, 2*time.Second, New(cfg).(*httpEntitlements).client.Timeout)
+
+	cfg.Clients.EntitlementsProvider = "unknown"
+	assert.PanicsWithValue(t, "entitlements provider 'unknown' is not supported", func() {
+		New(cfg)
+	})
+}
+
+func TestStubEntitlements(t *testing.T) {
+	entitled, err := NewStubEntitlements().IsEntitled(context.Background(), helperXRHID("12345"))
+	require.NoError(t, err)
+	assert.True(t, entitled)
+}
@@ -0,0 +1,139 @@
+package entitlements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/entitlements"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+const pathServices = "/services"
+
+// service is an item of the response of the services endpoint.
+type service struct {
+	IsEntitled bool `json:"is_entitled"`
+	IsTrial    bool `json:"is_trial"`
+}
+
+type cacheEntry struct {
+	entitled bool
+	expires  time.Time
+}
+
+type httpEntitlements struct {
+	baseURL string
+	bundle  string
+	ttl     time.Duration
+	client  *http.Client
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// NewHTTPEntitlements create an entitlements source backed by the
+// entitlements service. The answer for an organization is cached
+// for ttl.
+// baseURL is the endpoint of the entitlements service.
+// bundle is the bundle which entitle an organization.
+// ttl is the time an answer is cached; zero disables the cache.
+// client is the http client used to reach it out.
+func NewHTTPEntitlements(baseURL string, bundle string, ttl time.Duration, client *http.Client) entitlements.Entitlements {
+	if baseURL == "" {
+		panic("'baseURL' is an empty string")
+	}
+	if bundle == "" {
+		panic("'bundle' is an empty string")
+	}
+	if ttl < 0 {
+		panic("'ttl' is negative")
+	}
+	if client == nil {
+		panic("'client' is nil")
+	}
+	return &httpEntitlements{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		bundle:  bundle,
+		ttl:     ttl,
+		client:  client,
+		now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+func (e *httpEntitlements) IsEntitled(ctx context.Context, xrhid *identity.XRHID) (bool, error) {
+	if xrhid == nil {
+		return false, fmt.Errorf("'xrhid' is nil")
+	}
+	orgID := xrhid.Identity.OrgID
+	if entitled, ok := e.lookup(orgID); ok {
+		return entitled, nil
+	}
+	services, err := e.services(ctx, xrhid)
+	if err != nil {
+		return false, err
+	}
+	entitled := services[e.bundle].IsEntitled
+	e.store(orgID, entitled)
+	return entitled, nil
+}
+
+func (e *httpEntitlements) services(ctx context.Context, xrhid *identity.XRHID) (map[string]service, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+pathServices, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(header.HeaderXRHID, header.EncodeXRHID(xrhid))
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting the entitlements: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("entitlements api '%s' returned status %d", pathServices, resp.StatusCode)
+	}
+	output := map[string]service{}
+	if err = json.NewDecoder(resp.Body).Decode(&output); err != nil {
+		return nil, fmt.Errorf("decoding the entitlements: %w", err)
+	}
+	return output, nil
+}
+
+func (e *httpEntitlements) lookup(orgID string) (bool, bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	entry, ok := e.entries[orgID]
+	if !ok {
+		return false, false
+	}
+	if !e.now().Before(entry.expires) {
+		delete(e.entries, orgID)
+		return false, false
+	}
+	return entry.entitled, true
+}
+
+func (e *httpEntitlements) store(orgID string, entitled bool) {
+	if e.ttl == 0 {
+		return
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	now := e.now()
+	for key, entry := range e.entries {
+		if !now.Before(entry.expires) {
+			delete(e.entries, key)
+		}
+	}
+	e.entries[orgID] = cacheEntry{
+		entitled: entitled,
+		expires:  now.Add(e.ttl),
+	}
+}
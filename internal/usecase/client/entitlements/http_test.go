@@ -0,0 +1,114 @@
+package entitlements
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func helperXRHID(orgID string) *identity.XRHID {
+	return &identity.XRHID{
+		Identity: identity.Identity{
+			OrgID: orgID,
+			Type:  "User",
+			User: &identity.User{
+				UserID: "1",
+			},
+		},
+	}
+}
+
+func helperEntitlementsServer(t *testing.T, calls *int, status int, body string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/entitlements/v1/services", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get(header.HeaderXRHID))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewHTTPEntitlements(t *testing.T) {
+	assert.PanicsWithValue(t, "'baseURL' is an empty string", func() {
+		NewHTTPEntitlements("", "rhel", time.Minute, http.DefaultClient)
+	})
+	assert.PanicsWithValue(t, "'bundle' is an empty string", func() {
+		NewHTTPEntitlements("http://localhost", "", time.Minute, http.DefaultClient)
+	})
+	assert.PanicsWithValue(t, "'ttl' is negative", func() {
+		NewHTTPEntitlements("http://localhost", "rhel", -time.Minute, http.DefaultClient)
+	})
+	assert.PanicsWithValue(t, "'client' is nil", func() {
+		NewHTTPEntitlements("http://localhost", "rhel", time.Minute, nil)
+	})
+}
+
+func TestHTTPEntitlementsIsEntitled(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	srv := helperEntitlementsServer(t, &calls, http.StatusOK,
+		`{"rhel": {"is_entitled": true, "is_trial": false}, "ansible": {"is_entitled": false}}`)
+	e := NewHTTPEntitlements(srv.URL+"/api/entitlements/v1/", "rhel", time.Minute, srv.Client()).(*httpEntitlements)
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+
+	_, err := e.IsEntitled(ctx, nil)
+	assert.EqualError(t, err, "'xrhid' is nil")
+
+	// The second call is served from the cache
+	for i := 0; i < 2; i++ {
+		entitled, err := e.IsEntitled(ctx, helperXRHID("12345"))
+		require.NoError(t, err)
+		assert.True(t, entitled)
+	}
+	assert.Equal(t, 1, calls)
+
+	// The cached answer expires
+	now = now.Add(time.Minute)
+	entitled, err := e.IsEntitled(ctx, helperXRHID("12345"))
+	require.NoError(t, err)
+	assert.True(t, entitled)
+	assert.Equal(t, 2, calls)
+
+	// Bundle missing from the response
+	e.bundle = "unknown"
+	entitled, err = e.IsEntitled(ctx, helperXRHID("67890"))
+	require.NoError(t, err)
+	assert.False(t, entitled)
+	assert.Equal(t, 3, calls)
+}
+
+func TestHTTPEntitlementsIsEntitledErrors(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+
+	srv := helperEntitlementsServer(t, &calls, http.StatusServiceUnavailable, `{}`)
+	e := NewHTTPEntitlements(srv.URL+"/api/entitlements/v1", "rhel", 0, srv.Client())
+	entitled, err := e.IsEntitled(ctx, helperXRHID("12345"))
+	assert.EqualError(t, err, "entitlements api '/services' returned status 503")
+	assert.False(t, entitled)
+
+	srv = helperEntitlementsServer(t, &calls, http.StatusOK, `[]`)
+	e = NewHTTPEntitlements(srv.URL+"/api/entitlements/v1", "rhel", 0, srv.Client())
+	entitled, err = e.IsEntitled(ctx, helperXRHID("12345"))
+	assert.ErrorContains(t, err, "decoding the entitlements: ")
+	assert.False(t, entitled)
+
+	// Errors are not cached
+	assert.Equal(t, 2, calls)
+	srv.Close()
+	entitled, err = e.IsEntitled(ctx, helperXRHID("12345"))
+	assert.ErrorContains(t, err, "requesting the entitlements: ")
+	assert.False(t, entitled)
+}
@@ -0,0 +1,21 @@
+package entitlements
+
+import (
+	"context"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/entitlements"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+type stubEntitlements struct{}
+
+// NewStubEntitlements create an entitlements source which entitle
+// every organization; it is used for local development and when
+// the entitlements service is not available in the environment.
+func NewStubEntitlements() entitlements.Entitlements {
+	return stubEntitlements{}
+}
+
+func (stubEntitlements) IsEntitled(ctx context.Context, xrhid *identity.XRHID) (bool, error) {
+	return true, nil
+}
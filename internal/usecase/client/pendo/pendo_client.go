@@ -14,6 +14,7 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/tracing"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/client/pendo"
 )
 
@@ -51,7 +52,7 @@ func (c *pendoClient) SetMetadata(ctx context.Context, kind pendo.Kind, group pe
 
 	// Prepare the request
 	url := c.Config.Clients.PendoBaseURL + "/api/v1/metadata/" + url.PathEscape(string(kind)) + "/" + url.PathEscape(string(group)) + "/value"
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(reqBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		logger.Error(err.Error())
 		return nil, fmt.Errorf("error making SetMetaData request: %w", err)
@@ -114,7 +115,7 @@ func (c *pendoClient) SendTrackEvent(ctx context.Context, track *pendo.TrackRequ
 
 	// Prepare the request
 	url := c.Config.Clients.PendoBaseURL + "/data/track"
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(reqBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		logger.Error(err.Error())
 		return fmt.Errorf("error preparing request for SendTrackEvent")
@@ -161,7 +162,8 @@ func newClient(cfg *config.Config) *pendoClient {
 		cfg.Clients.PendoRequestTimeoutSecs = 3
 	}
 	client := &http.Client{
-		Timeout: time.Duration(cfg.Clients.PendoRequestTimeoutSecs) * time.Second,
+		Timeout:   time.Duration(cfg.Clients.PendoRequestTimeoutSecs) * time.Second,
+		Transport: tracing.NewTransport(nil),
 	}
 	return &pendoClient{
 		Config: cfg,
@@ -0,0 +1,147 @@
+package rbac
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"golang.org/x/sync/singleflight"
+)
+
+// ACLCache keep in memory the ACL retrieved from rbac for every
+// organization and principal, for a limited time. Concurrent
+// lookups for the same principal are coalesced into one request
+// to rbac.
+type ACLCache struct {
+	ttl     time.Duration
+	metrics *metrics.Metrics
+	now     func() time.Time
+
+	mutex   sync.Mutex
+	entries map[aclCacheKey]aclCacheEntry
+	// generations is increased for an organization on every
+	// invalidation, so the lookups started before it do not store
+	// a stale ACL; the rest of organizations are not affected.
+	generations map[string]uint64
+	group       singleflight.Group
+}
+
+type aclCacheKey struct {
+	orgID     string
+	principal string
+}
+
+type aclCacheEntry struct {
+	acl     []Access
+	expires time.Time
+}
+
+// aclLoader retrieve the ACL from rbac.
+type aclLoader func(ctx context.Context) ([]Access, error)
+
+var _ rbac.ACLInvalidator = (*ACLCache)(nil)
+
+// NewACLCache create a cache for the rbac ACLs.
+// ttl is the time an ACL is kept into the cache; a zero value
+// disable the cache, but the concurrent lookups are coalesced yet.
+// m is the metrics where to count the cache hits and misses.
+// Return the initialized cache.
+func NewACLCache(ttl time.Duration, m *metrics.Metrics) *ACLCache {
+	if ttl < 0 {
+		panic("ttl is negative")
+	}
+	if m == nil {
+		panic("m is nil")
+	}
+	return &ACLCache{
+		ttl:         ttl,
+		metrics:     m,
+		now:         time.Now,
+		entries:     map[aclCacheKey]aclCacheEntry{},
+		generations: map[string]uint64{},
+	}
+}
+
+// Get return the ACL for principal into orgID, calling load when
+// it is not cached or it expired.
+func (c *ACLCache) Get(ctx context.Context, orgID, principal string, load aclLoader) ([]Access, error) {
+	key := aclCacheKey{orgID: orgID, principal: principal}
+
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.now().Before(entry.expires) {
+		c.mutex.Unlock()
+		c.metrics.RbacCacheHits.Inc()
+		return entry.acl, nil
+	}
+	if ok {
+		delete(c.entries, key)
+	}
+	generation := c.generations[orgID]
+	c.mutex.Unlock()
+	c.metrics.RbacCacheMisses.Inc()
+
+	// The lookup is shared with other requests, so it should not
+	// be cancelled when the request that started it finish.
+	ctx = context.WithoutCancel(ctx)
+	data, err, _ := c.group.Do(key.String(), func() (any, error) {
+		acl, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.store(key, acl, generation)
+		return acl, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.([]Access), nil
+}
+
+// Invalidate drop the ACL cached for principal into orgID, or
+// for every principal into orgID when principal is empty.
+func (c *ACLCache) Invalidate(orgID, principal string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.generations[orgID]++
+	for key := range c.entries {
+		if key.orgID != orgID {
+			continue
+		}
+		if principal != "" && key.principal != principal {
+			continue
+		}
+		delete(c.entries, key)
+		c.group.Forget(key.String())
+	}
+	if principal != "" {
+		c.group.Forget(aclCacheKey{orgID: orgID, principal: principal}.String())
+	}
+}
+
+func (c *ACLCache) store(key aclCacheKey, acl []Access, generation uint64) {
+	if c.ttl == 0 {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if generation != c.generations[key.orgID] {
+		return
+	}
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = aclCacheEntry{
+		acl:     acl,
+		expires: now.Add(c.ttl),
+	}
+}
+
+func (k aclCacheKey) String() string {
+	return k.orgID + "/" + k.principal
+}
@@ -0,0 +1,193 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestACLCache(t *testing.T, ttl time.Duration) (*ACLCache, *metrics.Metrics, *time.Time) {
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	cache := NewACLCache(ttl, m)
+	require.NotNil(t, cache)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+	return cache, m, &now
+}
+
+func newCountingLoader(calls *int32, acl []Access, err error) aclLoader {
+	return func(ctx context.Context) ([]Access, error) {
+		atomic.AddInt32(calls, 1)
+		return acl, err
+	}
+}
+
+func TestNewACLCache(t *testing.T) {
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	assert.PanicsWithValue(t, "ttl is negative", func() {
+		NewACLCache(-time.Second, m)
+	})
+	assert.PanicsWithValue(t, "m is nil", func() {
+		NewACLCache(time.Second, nil)
+	})
+	assert.NotPanics(t, func() {
+		NewACLCache(0, m)
+	})
+}
+
+func TestACLCacheGet(t *testing.T) {
+	var calls int32
+	acl := []Access{{Permission: "idmsvc:domains:read"}}
+	cache, m, now := newTestACLCache(t, time.Minute)
+	ctx := context.Background()
+	load := newCountingLoader(&calls, acl, nil)
+
+	// First lookup is a miss
+	result, err := cache.Get(ctx, "12345", "jdoe", load)
+	require.NoError(t, err)
+	assert.Equal(t, acl, result)
+	assert.Equal(t, int32(1), calls)
+
+	// Second lookup is a hit
+	result, err = cache.Get(ctx, "12345", "jdoe", load)
+	require.NoError(t, err)
+	assert.Equal(t, acl, result)
+	assert.Equal(t, int32(1), calls)
+
+	// Other principal is a miss
+	_, err = cache.Get(ctx, "12345", "other", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls)
+
+	// Expired entry is a miss
+	*now = now.Add(time.Minute)
+	_, err = cache.Get(ctx, "12345", "jdoe", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), calls)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RbacCacheHits))
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.RbacCacheMisses))
+}
+
+func TestACLCacheGetError(t *testing.T) {
+	var calls int32
+	cache, _, _ := newTestACLCache(t, time.Minute)
+	ctx := context.Background()
+
+	result, err := cache.Get(ctx, "12345", "jdoe", newCountingLoader(&calls, nil, fmt.Errorf("rbac is down")))
+	assert.EqualError(t, err, "rbac is down")
+	assert.Nil(t, result)
+
+	// Errors are not cached
+	_, err = cache.Get(ctx, "12345", "jdoe", newCountingLoader(&calls, []Access{}, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestACLCacheGetDisabled(t *testing.T) {
+	var calls int32
+	cache, _, _ := newTestACLCache(t, 0)
+	ctx := context.Background()
+	load := newCountingLoader(&calls, []Access{}, nil)
+
+	_, err := cache.Get(ctx, "12345", "jdoe", load)
+	require.NoError(t, err)
+	_, err = cache.Get(ctx, "12345", "jdoe", load)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestACLCacheGetCoalesce(t *testing.T) {
+	const concurrency = 10
+	var (
+		calls int32
+		wg    sync.WaitGroup
+	)
+	cache, _, _ := newTestACLCache(t, time.Minute)
+	ctx := context.Background()
+	release := make(chan struct{})
+	load := func(ctx context.Context) ([]Access, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []Access{{Permission: "idmsvc:*:*"}}, nil
+	}
+
+	results := make([][]Access, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = cache.Get(ctx, "12345", "jdoe", load)
+		}(i)
+	}
+	// Give the goroutines the chance to join the lookup in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls)
+	for i := range results {
+		assert.Equal(t, []Access{{Permission: "idmsvc:*:*"}}, results[i])
+	}
+}
+
+func TestACLCacheInvalidate(t *testing.T) {
+	var calls int32
+	cache, _, _ := newTestACLCache(t, time.Minute)
+	ctx := context.Background()
+	load := newCountingLoader(&calls, []Access{}, nil)
+
+	for _, key := range []aclCacheKey{
+		{"12345", "jdoe"},
+		{"12345", "other"},
+		{"67890", "jdoe"},
+	} {
+		_, err := cache.Get(ctx, key.orgID, key.principal, load)
+		require.NoError(t, err)
+	}
+	require.Len(t, cache.entries, 3)
+
+	cache.Invalidate("12345", "jdoe")
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, aclCacheKey{"12345", "jdoe"})
+
+	cache.Invalidate("12345", "")
+	assert.Len(t, cache.entries, 1)
+	assert.Contains(t, cache.entries, aclCacheKey{"67890", "jdoe"})
+}
+
+func TestACLCacheInvalidateInFlight(t *testing.T) {
+	cache, _, _ := newTestACLCache(t, time.Minute)
+	ctx := context.Background()
+
+	// An invalidation while the ACL is retrieved avoid to store it
+	_, err := cache.Get(ctx, "12345", "jdoe", func(ctx context.Context) ([]Access, error) {
+		cache.Invalidate("12345", "jdoe")
+		return []Access{}, nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, cache.entries)
+}
+
+func TestACLCacheInvalidateOtherOrganization(t *testing.T) {
+	cache, _, _ := newTestACLCache(t, time.Minute)
+	ctx := context.Background()
+
+	// An invalidation of other organization does not avoid to
+	// store the ACL retrieved meanwhile
+	_, err := cache.Get(ctx, "12345", "jdoe", func(ctx context.Context) ([]Access, error) {
+		cache.Invalidate("67890", "")
+		return []Access{}, nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, cache.entries, aclCacheKey{"12345", "jdoe"})
+}
@@ -0,0 +1,158 @@
+package rbac
+
+import (
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+)
+
+// BreakerState is the state of the circuit breaker; the values
+// are the ones reported by the metrics.
+type BreakerState int
+
+const (
+	// BreakerClosed let the requests go to rbac.
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen let one request go to rbac to probe if it
+	// recovered.
+	BreakerHalfOpen
+	// BreakerOpen reject the requests without reaching out rbac.
+	BreakerOpen
+)
+
+// CircuitBreaker stop reaching out rbac after a number of
+// consecutive failures, for a period of time. When the period
+// expires, one request probes rbac, closing the breaker when it
+// succeed or opening it again when it fails.
+type CircuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+	metrics     *metrics.Metrics
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+var _ rbac.CircuitBreaker = (*CircuitBreaker)(nil)
+
+// NewCircuitBreaker create a circuit breaker for the requests to
+// rbac.
+// threshold is the number of consecutive failures that open the
+// breaker; zero disables it.
+// openTimeout is the time the breaker keeps open.
+// m is the metrics where to report the breaker state.
+// Return the initialized circuit breaker.
+func NewCircuitBreaker(threshold int, openTimeout time.Duration, m *metrics.Metrics) *CircuitBreaker {
+	if threshold < 0 {
+		panic("threshold is negative")
+	}
+	if openTimeout < 0 {
+		panic("openTimeout is negative")
+	}
+	if m == nil {
+		panic("m is nil")
+	}
+	m.RbacCircuitBreakerState.Set(float64(BreakerClosed))
+	return &CircuitBreaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		metrics:     m,
+		now:         time.Now,
+		state:       BreakerClosed,
+	}
+}
+
+// Allow return an *authz.UnavailableError when the request cannot
+// reach out rbac; else the caller must report the result by
+// calling Done.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil || b.threshold == 0 {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if retryAfter := b.retryAfter(); retryAfter > 0 {
+			b.metrics.RbacCircuitBreakerRejections.Inc()
+			return &authz.UnavailableError{RetryAfter: retryAfter}
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			b.metrics.RbacCircuitBreakerRejections.Inc()
+			return &authz.UnavailableError{RetryAfter: time.Second}
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Done report the result of a request allowed by Allow; failed is
+// true when rbac was unavailable.
+func (b *CircuitBreaker) Done(failed bool) {
+	if b == nil || b.threshold == 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		b.setState(BreakerClosed)
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setState(BreakerOpen)
+	}
+}
+
+// State return the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.state
+}
+
+// RetryAfter return the time the breaker keeps open yet, or zero
+// when the requests are allowed.
+func (b *CircuitBreaker) RetryAfter() time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state != BreakerOpen {
+		return 0
+	}
+	return b.retryAfter()
+}
+
+func (b *CircuitBreaker) retryAfter() time.Duration {
+	remaining := b.openedAt.Add(b.openTimeout).Sub(b.now())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	b.state = state
+	b.metrics.RbacCircuitBreakerState.Set(float64(state))
+}
@@ -0,0 +1,98 @@
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCircuitBreaker(t *testing.T, threshold int) (*CircuitBreaker, *metrics.Metrics, *time.Time) {
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	breaker := NewCircuitBreaker(threshold, 30*time.Second, m)
+	require.NotNil(t, breaker)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	breaker.now = func() time.Time { return now }
+	return breaker, m, &now
+}
+
+func TestNewCircuitBreaker(t *testing.T) {
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	assert.PanicsWithValue(t, "threshold is negative", func() {
+		NewCircuitBreaker(-1, time.Second, m)
+	})
+	assert.PanicsWithValue(t, "openTimeout is negative", func() {
+		NewCircuitBreaker(1, -time.Second, m)
+	})
+	assert.PanicsWithValue(t, "m is nil", func() {
+		NewCircuitBreaker(1, time.Second, nil)
+	})
+}
+
+func TestCircuitBreakerNil(t *testing.T) {
+	var breaker *CircuitBreaker
+	assert.NoError(t, breaker.Allow())
+	assert.NotPanics(t, func() { breaker.Done(true) })
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.Equal(t, time.Duration(0), breaker.RetryAfter())
+}
+
+func TestCircuitBreakerDisabled(t *testing.T) {
+	breaker, _, _ := newTestCircuitBreaker(t, 0)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, breaker.Allow())
+		breaker.Done(true)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var unavailable *authz.UnavailableError
+	breaker, m, now := newTestCircuitBreaker(t, 2)
+
+	// A success reset the consecutive failures
+	require.NoError(t, breaker.Allow())
+	breaker.Done(true)
+	require.NoError(t, breaker.Allow())
+	breaker.Done(false)
+	require.NoError(t, breaker.Allow())
+	breaker.Done(true)
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	// Open on the threshold
+	require.NoError(t, breaker.Allow())
+	breaker.Done(true)
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.Equal(t, float64(BreakerOpen), testutil.ToFloat64(m.RbacCircuitBreakerState))
+	assert.Equal(t, 30*time.Second, breaker.RetryAfter())
+
+	// Reject while it is open
+	*now = now.Add(10 * time.Second)
+	err := breaker.Allow()
+	require.ErrorAs(t, err, &unavailable)
+	assert.Equal(t, 20*time.Second, unavailable.RetryAfter)
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RbacCircuitBreakerRejections))
+
+	// Half-open let only one probe
+	*now = now.Add(20 * time.Second)
+	require.NoError(t, breaker.Allow())
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.Equal(t, time.Duration(0), breaker.RetryAfter())
+	require.ErrorAs(t, breaker.Allow(), &unavailable)
+
+	// A failed probe open it again
+	breaker.Done(true)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	// A successful probe close it
+	*now = now.Add(30 * time.Second)
+	require.NoError(t, breaker.Allow())
+	breaker.Done(false)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.Equal(t, float64(BreakerClosed), testutil.ToFloat64(m.RbacCircuitBreakerState))
+}
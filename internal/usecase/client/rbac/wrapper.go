@@ -3,19 +3,46 @@ package rbac
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/client/rbac"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 )
 
+// WrapperConfig hold the optional collaborators of the rbac
+// wrapper; the zero value retrieve the ACL on every check, with
+// no retries nor circuit breaker.
+type WrapperConfig struct {
+	// Cache keep the retrieved ACLs.
+	Cache *ACLCache
+	// Breaker stop reaching out rbac while it is failing.
+	Breaker *CircuitBreaker
+	// Retries is the number of times a failed request is retried.
+	Retries int
+	// RetryBackoff is the base delay before the first retry; it is
+	// doubled on every retry and a random jitter is applied.
+	RetryBackoff time.Duration
+	// Metrics count the retries when it is not nil.
+	Metrics *metrics.Metrics
+}
+
 type rbacWrapper struct {
-	application string
-	client      ClientInterface
+	application  string
+	client       ClientInterface
+	cache        *ACLCache
+	breaker      *CircuitBreaker
+	retries      int
+	retryBackoff time.Duration
+	metrics      *metrics.Metrics
 }
 
 const (
@@ -27,47 +54,124 @@ const (
 // New create a rbac client to check if the required
 // permission is allowed.
 func New(application string, rbacClient ClientInterface) rbac.Rbac {
+	return NewWithCache(application, rbacClient, nil)
+}
+
+// NewWithCache create a rbac client to check if the required
+// permission is allowed, which keep the retrieved ACLs into
+// cache. When cache is nil, the ACL is retrieved on every check.
+func NewWithCache(application string, rbacClient ClientInterface, cache *ACLCache) rbac.Rbac {
+	return NewWithConfig(application, rbacClient, &WrapperConfig{Cache: cache})
+}
+
+// NewWithConfig create a rbac client to check if the required
+// permission is allowed, with the cache, retries and circuit
+// breaker provided by cfg.
+func NewWithConfig(application string, rbacClient ClientInterface, cfg *WrapperConfig) rbac.Rbac {
 	if application == "" {
 		panic("application is an empty string")
 	}
 	if rbacClient == nil {
 		panic("rbacClient is nil")
 	}
+	if cfg == nil {
+		panic("cfg is nil")
+	}
+	if cfg.Retries < 0 {
+		panic("cfg.Retries is negative")
+	}
 	return &rbacWrapper{
-		application: application,
-		client:      rbacClient,
+		application:  application,
+		client:       rbacClient,
+		cache:        cfg.Cache,
+		breaker:      cfg.Breaker,
+		retries:      cfg.Retries,
+		retryBackoff: cfg.RetryBackoff,
+		metrics:      cfg.Metrics,
 	}
 }
 
 func (c *rbacWrapper) IsAllowed(ctx context.Context, xrhid, permission string) (bool, error) {
+	resources, err := c.AllowedResources(ctx, xrhid, permission)
+	if err != nil {
+		return false, err
+	}
+	return !resources.IsEmpty(), nil
+}
+
+// AllowedResources return the domains where permission is granted,
+// by evaluating the resourceDefinitions of the matching ACL items.
+// An ACL item with no resourceDefinitions grant the permission for
+// every domain into the organization.
+func (c *rbacWrapper) AllowedResources(ctx context.Context, xrhid, permission string) (*authz.ResourceSet, error) {
 	var (
 		service  string
 		resource string
 		verb     string
 		err      error
-		listACL  []string
+		listACL  []Access
 	)
 	if ctx == nil {
 		slog.ErrorContext(ctx, "ctx is nil")
-		return false, fmt.Errorf("ctx is nil")
+		return nil, fmt.Errorf("ctx is nil")
 	}
 	if permission == "" {
 		slog.ErrorContext(ctx, "permission to check is an empty string")
-		return false, fmt.Errorf("permission to check is an empty string")
+		return nil, fmt.Errorf("permission to check is an empty string")
 	}
 	service, resource, verb = c.decomposePermission(permission)
 	ctx = ContextWithXRHIDRaw(ctx, xrhid)
-	if listACL, err = c.retrieveACL(ctx); err != nil {
+	if listACL, err = c.getACL(ctx, xrhid); err != nil {
 		slog.ErrorContext(ctx, err.Error())
-		return false, err
+		return nil, err
 	}
+	resources := &authz.ResourceSet{}
 	for i := range listACL {
-		if !c.matchPermission(service, resource, verb, listACL[i]) {
+		if !c.matchPermission(service, resource, verb, listACL[i].Permission) {
 			continue
 		}
-		return true, nil
+		if len(listACL[i].ResourceDefinitions) == 0 {
+			return &authz.ResourceSet{All: true}, nil
+		}
+		for j := range listACL[i].ResourceDefinitions {
+			for _, id := range c.resourceIDs(&listACL[i].ResourceDefinitions[j].AttributeFilter) {
+				// The domain ids are compared in their canonical form
+				resources.IDs = append(resources.IDs, strings.ToLower(strings.TrimSpace(id)))
+			}
+		}
+	}
+	return resources, nil
+}
+
+// resourceIDs return the domain ids that filter matches; a filter
+// for other attribute match no domain.
+func (c *rbacWrapper) resourceIDs(filter *ResourceDefinitionFilter) []string {
+	if filter.Key != rbac.ResourceDomainID {
+		return nil
+	}
+	switch filter.Operation {
+	case Equal:
+		value, err := filter.Value.AsResourceDefinitionFilterValue0()
+		if err != nil {
+			return nil
+		}
+		return []string{value}
+	case In:
+		if items, err := filter.Value.AsResourceDefinitionFilterValue1(); err == nil {
+			ids := make([]string, 0, len(items))
+			for i := range items {
+				if value, err := items[i].AsResourceDefinitionFilterValue10(); err == nil {
+					ids = append(ids, value)
+				}
+			}
+			return ids
+		}
+		// Older rbac versions provide a comma-separated list
+		if value, err := filter.Value.AsResourceDefinitionFilterValue0(); err == nil {
+			return strings.Split(value, ",")
+		}
 	}
-	return false, nil
+	return nil
 }
 
 func (c *rbacWrapper) matchPermission(service, resource, verb, aclItem string) bool {
@@ -108,7 +212,37 @@ func (c *rbacWrapper) addXRHID(ctx context.Context, req *http.Request) error {
 	return nil
 }
 
-func (c *rbacWrapper) retrieveACL(ctx context.Context) ([]string, error) {
+// getACL return the ACL for the xrhid identity, from the cache
+// when it is available.
+func (c *rbacWrapper) getACL(ctx context.Context, xrhid string) ([]Access, error) {
+	if c.cache == nil {
+		return c.retrieveACL(ctx)
+	}
+	identity, err := header.DecodeXRHID(xrhid)
+	if err != nil {
+		return nil, err
+	}
+	return c.cache.Get(
+		ctx,
+		identity.Identity.OrgID,
+		header.GetPrincipal(identity),
+		c.retrieveACL,
+	)
+}
+
+// retrieveACL retrieve the ACL from rbac when the circuit breaker
+// allow it, and report to it whether rbac was available.
+func (c *rbacWrapper) retrieveACL(ctx context.Context) ([]Access, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	acl, err := c.retrieveACLPages(ctx)
+	var unavailable *authz.UnavailableError
+	c.breaker.Done(errors.As(err, &unavailable))
+	return acl, err
+}
+
+func (c *rbacWrapper) retrieveACLPages(ctx context.Context) ([]Access, error) {
 	// Credits on RHEnvision: https://github.com/RHEnVision/provisioning-backend/blob/main/internal/clients/http/rbac/rbac_client.go#L83
 	// Credits on hmscontent-service: https://github.com/content-services/content-sources-backend/blob/main/pkg/rbac/client_wrapper.go
 	var (
@@ -120,31 +254,30 @@ func (c *rbacWrapper) retrieveACL(ctx context.Context) ([]string, error) {
 
 	limit = limitDefault
 	offset = 0
-	permissions := []string{}
+	permissions := []Access{}
 	for {
-		response, err := c.client.GetPrincipalAccess(
+		response, err := c.getPrincipalAccess(
 			ctx,
 			&GetPrincipalAccessParams{
 				Application: c.application,
 				Limit:       &limit,
 				Offset:      &offset,
 			},
-			c.addXRHID,
 		)
 		if err != nil {
-			return []string{}, err
+			return []Access{}, err
 		}
 		var dataBody []byte
-		if dataBody, err = io.ReadAll(response.Body); err != nil {
-			return []string{}, err
+		dataBody, err = io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return []Access{}, err
 		}
 		var dataACL AccessPagination
 		if err = json.Unmarshal(dataBody, &dataACL); err != nil {
-			return []string{}, err
-		}
-		for i := range dataACL.Data {
-			permissions = append(permissions, dataACL.Data[i].Permission)
+			return []Access{}, err
 		}
+		permissions = append(permissions, dataACL.Data...)
 		if *dataACL.Meta.Count == limitDefault {
 			offset += limitDefault
 			continue
@@ -153,6 +286,62 @@ func (c *rbacWrapper) retrieveACL(ctx context.Context) ([]string, error) {
 	}
 }
 
+// getPrincipalAccess request a page of the ACL, retrying it when
+// rbac is unavailable. A response with other status than 200 is
+// returned as an error, and as an *authz.UnavailableError when
+// rbac is unavailable.
+func (c *rbacWrapper) getPrincipalAccess(ctx context.Context, params *GetPrincipalAccessParams) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := c.client.GetPrincipalAccess(ctx, params, c.addXRHID)
+		if err == nil && response.StatusCode == http.StatusOK {
+			return response, nil
+		}
+		if response != nil {
+			response.Body.Close()
+		}
+		if err == nil {
+			err = fmt.Errorf("rbac returned status %d", response.StatusCode)
+		}
+		if !isUnavailable(response, err) {
+			return nil, err
+		}
+		if attempt >= c.retries {
+			return nil, &authz.UnavailableError{Err: err}
+		}
+		slog.WarnContext(ctx, "retrying the request to rbac", slog.Int("attempt", attempt+1), slog.String("error", err.Error()))
+		if c.metrics != nil {
+			c.metrics.RbacRetries.Inc()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, &authz.UnavailableError{Err: ctx.Err()}
+		case <-time.After(c.backoff(attempt)):
+		}
+	}
+}
+
+// backoff return the delay before the retry attempt, doubling the
+// base delay on every attempt and applying a random jitter between
+// the half and the full delay.
+func (c *rbacWrapper) backoff(attempt int) time.Duration {
+	delay := c.retryBackoff << attempt
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// isUnavailable return true when the request failed because rbac
+// is down or overloaded, and not because of the request itself.
+func isUnavailable(response *http.Response, err error) bool {
+	if response == nil {
+		return err != nil
+	}
+	return response.StatusCode >= http.StatusInternalServerError ||
+		response.StatusCode == http.StatusTooManyRequests
+}
+
 // XRHIDRawFromCtx read the contextKey entry from
 // a previously created context by ContextWithXRHID
 // Return the string with the raw string xrhid or
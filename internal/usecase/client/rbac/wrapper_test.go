@@ -2,12 +2,19 @@ package rbac
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/client/authz"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
 	builder_api "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -92,3 +99,207 @@ func TestAddXRHID(t *testing.T) {
 	}, "No panic on normal operation")
 	require.NoError(t, err)
 }
+
+func TestIsAllowedWithCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"count":1},"data":[{"permission":"idmsvc:domains:read","resourceDefinitions":[]}]}`))
+	}))
+	defer server.Close()
+	client, err := NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	cache := NewACLCache(time.Minute, metrics.NewMetrics(prometheus.NewRegistry()))
+	wrapper := NewWithCache("idmsvc", client, cache)
+
+	xrhid := builder_api.NewUserXRHID().Build()
+	xrhidRaw := header.EncodeXRHID(&xrhid)
+	allowed, err := wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:update")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, int32(1), calls)
+
+	// The ACL is retrieved again once invalidated
+	cache.Invalidate(xrhid.Identity.OrgID, header.GetPrincipal(&xrhid))
+	_, err = wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls)
+
+	// A wrong xrhid is an error
+	_, err = wrapper.IsAllowed(context.Background(), "wrong", "idmsvc:domains:read")
+	assert.Error(t, err)
+}
+
+func TestAllowedResources(t *testing.T) {
+	const (
+		domainA = "8e2b6d9a-3a44-4d8e-9a79-1d0a7b3a6c55"
+		domainB = "0b1f4a52-7a5c-4b6e-8c3e-5f5d2a9e1c77"
+		domainC = "3c9d8e7f-6a5b-4c3d-9e2f-1a0b9c8d7e6f"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"count":5},"data":[
+			{"permission":"idmsvc:domains:read","resourceDefinitions":[]},
+			{"permission":"idmsvc:domains:update","resourceDefinitions":[
+				{"attributeFilter":{"key":"idmsvc.domains.id","operation":"equal","value":"` + domainA + `"}}
+			]},
+			{"permission":"idmsvc:domains:*","resourceDefinitions":[
+				{"attributeFilter":{"key":"idmsvc.domains.id","operation":"in","value":["` + domainB + `"]}}
+			]},
+			{"permission":"idmsvc:domains:delete","resourceDefinitions":[
+				{"attributeFilter":{"key":"idmsvc.domains.id","operation":"in","value":"` + domainC + `, ` + domainA + `"}}
+			]},
+			{"permission":"idmsvc:token:create","resourceDefinitions":[
+				{"attributeFilter":{"key":"inventory.groups.id","operation":"equal","value":"` + domainA + `"}}
+			]}
+		]}`))
+	}))
+	defer server.Close()
+	client, err := NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	wrapper := New("idmsvc", client)
+	xrhid := builder_api.NewUserXRHID().Build()
+	xrhidRaw := header.EncodeXRHID(&xrhid)
+
+	type TestCase struct {
+		Name     string
+		Given    string
+		Expected *authz.ResourceSet
+	}
+	testCases := []TestCase{
+		{
+			Name:     "no resourceDefinitions grant every domain",
+			Given:    "idmsvc:domains:read",
+			Expected: &authz.ResourceSet{All: true},
+		},
+		{
+			Name:     "equal and in operations are merged",
+			Given:    "idmsvc:domains:update",
+			Expected: &authz.ResourceSet{IDs: []string{domainA, domainB}},
+		},
+		{
+			Name:     "in operation with comma-separated value",
+			Given:    "idmsvc:domains:delete",
+			Expected: &authz.ResourceSet{IDs: []string{domainB, domainC, domainA}},
+		},
+		{
+			Name:     "filters for other attributes grant nothing",
+			Given:    "idmsvc:token:create",
+			Expected: &authz.ResourceSet{},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		resources, err := wrapper.AllowedResources(context.Background(), xrhidRaw, testCase.Given)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.Expected, resources)
+	}
+
+	allowed, err := wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:token:create")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestNewWithConfig(t *testing.T) {
+	client, err := NewClientWithResponses("http://localhost:8000/api/rbac/v1")
+	require.NoError(t, err)
+	assert.PanicsWithValue(t, "cfg is nil", func() {
+		NewWithConfig("idmsvc", client, nil)
+	})
+	assert.PanicsWithValue(t, "cfg.Retries is negative", func() {
+		NewWithConfig("idmsvc", client, &WrapperConfig{Retries: -1})
+	})
+}
+
+func TestAllowedResourcesRetries(t *testing.T) {
+	var (
+		calls  int32
+		status int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first two requests
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(int(atomic.LoadInt32(&status)))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"count":1},"data":[{"permission":"idmsvc:domains:read","resourceDefinitions":[]}]}`))
+	}))
+	defer server.Close()
+	client, err := NewClientWithResponses(server.URL)
+	require.NoError(t, err)
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	xrhid := builder_api.NewUserXRHID().Build()
+	xrhidRaw := header.EncodeXRHID(&xrhid)
+
+	// Unavailable rbac is retried
+	atomic.StoreInt32(&status, http.StatusServiceUnavailable)
+	wrapper := NewWithConfig("idmsvc", client, &WrapperConfig{
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+		Metrics:      m,
+	})
+	allowed, err := wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.RbacRetries))
+
+	// Fail when the retries are exhausted
+	var unavailable *authz.UnavailableError
+	atomic.StoreInt32(&calls, 0)
+	wrapper = NewWithConfig("idmsvc", client, &WrapperConfig{
+		Retries:      1,
+		RetryBackoff: time.Millisecond,
+	})
+	_, err = wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+	require.ErrorAs(t, err, &unavailable)
+	assert.EqualError(t, err, "authorization service is unavailable: rbac returned status 503")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Client errors are not retried
+	atomic.StoreInt32(&calls, 0)
+	atomic.StoreInt32(&status, http.StatusForbidden)
+	_, err = wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+	assert.EqualError(t, err, "rbac returned status 403")
+	assert.False(t, errors.As(err, &unavailable))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestAllowedResourcesCircuitBreaker(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	// The client timeout is shorter than the rbac latency
+	client, err := NewClientWithResponses(server.URL, WithHTTPClient(&http.Client{
+		Timeout: 10 * time.Millisecond,
+	}))
+	require.NoError(t, err)
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	breaker := NewCircuitBreaker(2, time.Minute, m)
+	wrapper := NewWithConfig("idmsvc", client, &WrapperConfig{Breaker: breaker})
+	xrhid := builder_api.NewUserXRHID().Build()
+	xrhidRaw := header.EncodeXRHID(&xrhid)
+
+	var unavailable *authz.UnavailableError
+	for i := 0; i < 2; i++ {
+		_, err = wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+		require.ErrorAs(t, err, &unavailable)
+		assert.Equal(t, time.Duration(0), unavailable.RetryAfter)
+	}
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	// rbac is not reached out while the breaker is open
+	_, err = wrapper.IsAllowed(context.Background(), xrhidRaw, "idmsvc:domains:read")
+	require.ErrorAs(t, err, &unavailable)
+	assert.Greater(t, unavailable.RetryAfter, time.Duration(0))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
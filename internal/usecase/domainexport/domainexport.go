@@ -0,0 +1,150 @@
+// Package domainexport hold the format of the documents which export
+// the domains of an organization, so they can be backed up or moved
+// to other environment and imported later.
+package domainexport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+)
+
+// Version is the version of the document format written by the
+// service; the documents with other version are not imported.
+const Version = "1"
+
+// checksumPrefix is the prefix of the checksum, which name the hash
+// algorithm.
+const checksumPrefix = "sha256:"
+
+// Strategy is how an import solve the conflict with a domain which
+// already exist in the organization.
+type Strategy string
+
+const (
+	// StrategySkip keep the existing domain and ignore the imported one.
+	StrategySkip Strategy = "skip"
+	// StrategyOverwrite replace the existing domain by the imported one.
+	StrategyOverwrite Strategy = "overwrite"
+	// StrategyFail cancel the import.
+	StrategyFail Strategy = "fail"
+)
+
+// ParseStrategy return the strategy named by value, or an error if
+// the strategy is unknown.
+func ParseStrategy(value string) (Strategy, error) {
+	switch Strategy(value) {
+	case StrategySkip, StrategyOverwrite, StrategyFail:
+		return Strategy(value), nil
+	default:
+		return "", fmt.Errorf("strategy '%s' is unknown, it was expected one of '%s', '%s' or '%s'",
+			value, StrategySkip, StrategyOverwrite, StrategyFail)
+	}
+}
+
+// collectPageSize is the number of domains read on every page when
+// the domains of an organization are collected.
+const collectPageSize = 100
+
+// Checksum return the checksum of the domains of a document. It is
+// the hash of the compact JSON encoding of the domains, so the
+// document can be formatted without changing its checksum.
+// domains is the list of domains of the document.
+// Return the checksum with the algorithm prefix, or an error if the
+// domains can not be encoded.
+func Checksum(domains []public.DomainExportItem) (string, error) {
+	if domains == nil {
+		domains = []public.DomainExportItem{}
+	}
+	data, err := json.Marshal(domains)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return checksumPrefix + hex.EncodeToString(hash[:]), nil
+}
+
+// Verify check the version and the checksum of a document.
+// doc is the document to check.
+// Return nil if the document can be imported, else an error with
+// the details.
+func Verify(doc *public.DomainExport) error {
+	if doc == nil {
+		return internal_errors.NilArgError("doc")
+	}
+	if doc.Version != Version {
+		return fmt.Errorf("document version '%s' is not supported, it was expected '%s'", doc.Version, Version)
+	}
+	if !strings.HasPrefix(doc.Checksum, checksumPrefix) {
+		return fmt.Errorf("document checksum '%s' is not supported, it was expected a '%s' checksum", doc.Checksum, checksumPrefix)
+	}
+	checksum, err := Checksum(doc.Domains)
+	if err != nil {
+		return err
+	}
+	if doc.Checksum != checksum {
+		return fmt.Errorf("document checksum does not match, the document was modified or it is incomplete")
+	}
+	return nil
+}
+
+// Collect read the domains of an organization with all their
+// information, their registration record and their subnet to location
+// mappings.
+// ctx is the context with the db and slog instances.
+// r is the domain repository.
+// orgID is the organization which domains are collected.
+// domainIDs restrict the domains to collect; nil collect all of them.
+// Return the domains, the mappings indexed by the domain uuid, or an
+// error.
+func Collect(
+	ctx context.Context,
+	r repository.DomainRepository,
+	orgID string,
+	domainIDs []uuid.UUID,
+) (data []model.Domain, subnets map[uuid.UUID][]model.DomainLocationSubnet, err error) {
+	var (
+		page  []model.Domain
+		count int64
+		item  *model.Domain
+	)
+	if r == nil {
+		return nil, nil, internal_errors.NilArgError("r")
+	}
+	data = []model.Domain{}
+	subnets = map[uuid.UUID][]model.DomainLocationSubnet{}
+	for offset := 0; ; offset += collectPageSize {
+		if page, count, err = r.List(ctx, orgID, domainIDs, offset, collectPageSize); err != nil {
+			return nil, nil, err
+		}
+		for i := range page {
+			if item, err = r.FindByID(ctx, orgID, page[i].DomainUuid); err != nil {
+				return nil, nil, err
+			}
+			if item.Registration == nil {
+				// FindByID only read the registration of the
+				// domains which are not approved yet.
+				if err = r.FindRegistration(ctx, orgID, item); err != nil {
+					return nil, nil, err
+				}
+			}
+			if subnets[item.DomainUuid], err = r.ListLocationSubnets(ctx, orgID, item.DomainUuid); err != nil {
+				return nil, nil, err
+			}
+			data = append(data, *item)
+		}
+		if len(page) == 0 || int64(offset+len(page)) >= count {
+			break
+		}
+	}
+	return data, subnets, nil
+}
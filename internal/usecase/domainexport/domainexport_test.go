@@ -0,0 +1,159 @@
+package domainexport
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	repository "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+func helperDomainExport(t *testing.T) *public.DomainExport {
+	domainID := uuid.MustParse("c5d2c9c4-e4a3-4bf6-b9ae-0b4bf5a4b8a6")
+	doc := &public.DomainExport{
+		Version: Version,
+		OrgId:   "12345",
+		Domains: []public.DomainExportItem{
+			{
+				Domain: public.Domain{
+					DomainId:   &domainID,
+					DomainName: "mydomain.example",
+					DomainType: public.RhelIdm,
+					Title:      pointy.String("My domain"),
+				},
+				LocationSubnets: []public.LocationSubnet{
+					{Subnet: "192.0.2.0/24", Location: "boston"},
+				},
+			},
+		},
+	}
+	checksum, err := Checksum(doc.Domains)
+	require.NoError(t, err)
+	doc.Checksum = checksum
+	return doc
+}
+
+func TestChecksum(t *testing.T) {
+	empty, err := Checksum(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945", empty)
+	other, err := Checksum([]public.DomainExportItem{})
+	require.NoError(t, err)
+	assert.Equal(t, empty, other)
+
+	doc := helperDomainExport(t)
+	assert.Regexp(t, "^sha256:[0-9a-f]{64}$", doc.Checksum)
+	assert.NotEqual(t, empty, doc.Checksum)
+}
+
+func TestVerify(t *testing.T) {
+	assert.EqualError(t, Verify(nil), "code=500, message='doc' cannot be nil")
+
+	doc := helperDomainExport(t)
+	assert.NoError(t, Verify(doc))
+
+	doc.Version = "2"
+	assert.EqualError(t, Verify(doc), "document version '2' is not supported, it was expected '1'")
+
+	doc = helperDomainExport(t)
+	doc.Checksum = "md5:abc"
+	assert.EqualError(t, Verify(doc), "document checksum 'md5:abc' is not supported, it was expected a 'sha256:' checksum")
+
+	doc = helperDomainExport(t)
+	doc.Domains[0].Domain.Title = pointy.String("Modified")
+	assert.EqualError(t, Verify(doc), "document checksum does not match, the document was modified or it is incomplete")
+}
+
+func TestParseStrategy(t *testing.T) {
+	for _, value := range []string{"skip", "overwrite", "fail"} {
+		strategy, err := ParseStrategy(value)
+		assert.NoError(t, err)
+		assert.Equal(t, Strategy(value), strategy)
+	}
+	strategy, err := ParseStrategy("merge")
+	assert.EqualError(t, err, "strategy 'merge' is unknown, it was expected one of 'skip', 'overwrite' or 'fail'")
+	assert.Equal(t, Strategy(""), strategy)
+}
+
+func TestCollect(t *testing.T) {
+	ctx := context.Background()
+	orgID := "12345"
+	domainIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	page := []model.Domain{{OrgId: orgID, DomainUuid: domainIDs[0]}, {OrgId: orgID, DomainUuid: domainIDs[1]}}
+	subnets := []model.DomainLocationSubnet{{Subnet: "192.0.2.0/24", Location: "boston"}}
+	expectedErr := fmt.Errorf("database error")
+
+	data, mappings, err := Collect(ctx, nil, orgID, nil)
+	assert.EqualError(t, err, "code=500, message='r' cannot be nil")
+	assert.Nil(t, data)
+	assert.Nil(t, mappings)
+
+	r := repository.NewDomainRepository(t)
+	r.On("List", ctx, orgID, []uuid.UUID(nil), 0, collectPageSize).Return(nil, int64(0), expectedErr).Once()
+	data, mappings, err = Collect(ctx, r, orgID, nil)
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, data)
+	assert.Nil(t, mappings)
+
+	r = repository.NewDomainRepository(t)
+	r.On("List", ctx, orgID, domainIDs, 0, collectPageSize).Return(page, int64(2), nil).Once()
+	r.On("FindByID", ctx, orgID, domainIDs[0]).Return(nil, expectedErr).Once()
+	data, mappings, err = Collect(ctx, r, orgID, domainIDs)
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, data)
+	assert.Nil(t, mappings)
+
+	r = repository.NewDomainRepository(t)
+	r.On("List", ctx, orgID, domainIDs, 0, collectPageSize).Return(page, int64(2), nil).Once()
+	r.On("FindByID", ctx, orgID, domainIDs[0]).Return(&page[0], nil).Once()
+	r.On("FindRegistration", ctx, orgID, &page[0]).Return(expectedErr).Once()
+	data, mappings, err = Collect(ctx, r, orgID, domainIDs)
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, data)
+	assert.Nil(t, mappings)
+
+	// the registration read by FindByID is kept
+	page[0].Registration = &model.DomainRegistration{IpaHccVersion: "0.12"}
+	r = repository.NewDomainRepository(t)
+	r.On("List", ctx, orgID, domainIDs, 0, collectPageSize).Return(page, int64(2), nil).Once()
+	r.On("FindByID", ctx, orgID, domainIDs[0]).Return(&page[0], nil).Once()
+	r.On("ListLocationSubnets", ctx, orgID, domainIDs[0]).Return(nil, expectedErr).Once()
+	data, mappings, err = Collect(ctx, r, orgID, domainIDs)
+	assert.EqualError(t, err, "database error")
+	assert.Nil(t, data)
+	assert.Nil(t, mappings)
+
+	r = repository.NewDomainRepository(t)
+	r.On("List", ctx, orgID, []uuid.UUID(nil), 0, collectPageSize).Return([]model.Domain{}, int64(0), nil).Once()
+	data, mappings, err = Collect(ctx, r, orgID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Domain{}, data)
+	assert.Equal(t, map[uuid.UUID][]model.DomainLocationSubnet{}, mappings)
+
+	r = repository.NewDomainRepository(t)
+	r.On("List", ctx, orgID, domainIDs, 0, collectPageSize).Return(page[:1], int64(2), nil).Once()
+	r.On("List", ctx, orgID, domainIDs, collectPageSize, collectPageSize).Return(page[1:], int64(2), nil).Once()
+	r.On("FindByID", ctx, orgID, domainIDs[0]).Return(&page[0], nil).Once()
+	r.On("FindByID", ctx, orgID, domainIDs[1]).Return(&page[1], nil).Once()
+	r.On("FindRegistration", ctx, orgID, &page[1]).Run(func(args mock.Arguments) {
+		args.Get(2).(*model.Domain).Registration = &model.DomainRegistration{IpaHccVersion: "0.13"}
+	}).Return(nil).Once()
+	r.On("ListLocationSubnets", ctx, orgID, domainIDs[0]).Return(subnets, nil).Once()
+	r.On("ListLocationSubnets", ctx, orgID, domainIDs[1]).Return([]model.DomainLocationSubnet{}, nil).Once()
+	data, mappings, err = Collect(ctx, r, orgID, domainIDs)
+	assert.NoError(t, err)
+	assert.Equal(t, page, data)
+	assert.Equal(t, "0.12", data[0].Registration.IpaHccVersion)
+	assert.Equal(t, "0.13", data[1].Registration.IpaHccVersion)
+	assert.Equal(t, map[uuid.UUID][]model.DomainLocationSubnet{
+		domainIDs[0]: subnets,
+		domainIDs[1]: {},
+	}, mappings)
+}
@@ -0,0 +1,31 @@
+// Package activedirectory implements the active-directory domain type.
+package activedirectory
+
+import (
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/domaintype"
+)
+
+type domainType struct{}
+
+// New return the domaintype.DomainTypeHandler for active-directory
+// domains.
+func New() domaintype.DomainTypeHandler {
+	return domainType{}
+}
+
+func (domainType) Type() uint {
+	return TypeID
+}
+
+func (domainType) Name() public.DomainType {
+	return public.ActiveDirectory
+}
+
+func (domainType) ServerCount(domain *model.Domain) int {
+	if data := Data(domain); data != nil {
+		return len(data.DomainControllers)
+	}
+	return 0
+}
@@ -0,0 +1,26 @@
+package activedirectory
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	builder_api "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	test_domaintype "github.com/podengo-project/idmsvc-backend/internal/test/domaintype"
+)
+
+func TestConformance(t *testing.T) {
+	const domainName = "ad.example"
+	subscriptionManagerID := uuid.New()
+	activeDirectory := builder_api.NewActiveDirectoryDomain(domainName).
+		WithUpdateAgents([]uuid.UUID{subscriptionManagerID}).
+		Build()
+	test_domaintype.RunConformanceSuite(t, New(), test_domaintype.Fixture{
+		Register: builder_api.NewDomain(domainName).
+			WithActiveDirectory(activeDirectory).
+			Build(),
+		UpdateAgent: builder_api.NewUpdateDomainAgent(domainName).
+			WithDomainActiveDirectory(*activeDirectory).
+			Build(),
+		SubscriptionManagerID: subscriptionManagerID.String(),
+	})
+}
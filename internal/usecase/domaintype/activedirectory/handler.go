@@ -0,0 +1,126 @@
+package activedirectory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"go.openly.dev/pointy"
+)
+
+func (domainType) Fill(target *model.Domain, source *model.Domain) error {
+	sourceAD := Data(source)
+	if sourceAD == nil {
+		return internal_errors.NilArgError("source.TypeData")
+	}
+	targetAD := &ActiveDirectory{}
+	target.TypeData = targetAD
+	return fillDomainActiveDirectory(targetAD, sourceAD)
+}
+
+func (domainType) ValidateUpdate(current *model.Domain, data *model.Domain) error {
+	dataAD, currentAD := Data(data), Data(current)
+	if dataAD != nil && currentAD != nil &&
+		dataAD.RealmName != nil && currentAD.RealmName != nil &&
+		*dataAD.RealmName != *currentAD.RealmName {
+		return internal_errors.NewHTTPErrorF(
+			http.StatusBadRequest,
+			"'realm_name' may not be changed",
+		)
+	}
+	return nil
+}
+
+func (domainType) EnsureUpdateAgentEnabled(ctx context.Context, subscriptionManagerID string, data *model.Domain) error {
+	included, err := updateAgentIncluded(ctx, subscriptionManagerID, Data(data))
+	if err != nil {
+		return err
+	}
+	if !included {
+		return internal_errors.NewHTTPErrorF(
+			http.StatusBadRequest,
+			"update agent's 'Subscription Manager ID' not found in the authorized list of active-directory update agents",
+		)
+	}
+	return nil
+}
+
+func (domainType) EnsureUpdateAgentAuthorized(ctx context.Context, subscriptionManagerID string, current *model.Domain) error {
+	included, err := updateAgentIncluded(ctx, subscriptionManagerID, Data(current))
+	if err != nil {
+		return err
+	}
+	if !included {
+		return internal_errors.NewHTTPErrorF(
+			http.StatusForbidden,
+			"update agent is not authorized to update the domain",
+		)
+	}
+	return nil
+}
+
+// FillRegistration check the active-directory information of the
+// registration; the active-directory domains carry no CA certificates
+// and their update agents are not domain controllers, so neither the
+// certificates nor the fqdn of the registering server are known.
+func (domainType) FillRegistration(subscriptionManagerID string, domain *model.Domain, registration *model.DomainRegistration) error {
+	if Data(domain) == nil {
+		return internal_errors.NilArgError("domain.TypeData")
+	}
+	if registration == nil {
+		return internal_errors.NilArgError("registration")
+	}
+	return nil
+}
+
+// updateAgentIncluded checks if the subscription manager ID
+// belongs to the list of update agents of an active-directory domain.
+func updateAgentIncluded(
+	ctx context.Context,
+	subscriptionManagerID string,
+	domainAD *ActiveDirectory,
+) (bool, error) {
+	logger := app_context.LogFromCtx(ctx)
+	if subscriptionManagerID == "" {
+		logger.Error("'subscriptionManagerID' is an empty string")
+		return false, fmt.Errorf("'subscriptionManagerID' is empty")
+	}
+	if domainAD == nil {
+		logger.Error("'domainAD' is nil")
+		return false, internal_errors.NilArgError("domainAD")
+	}
+	for i := range domainAD.UpdateAgents {
+		if domainAD.UpdateAgents[i] == subscriptionManagerID {
+			return true, nil
+		}
+	}
+	logger.Debug("agent not found in the list of update agents",
+		slog.String("subscriptionManagerID", subscriptionManagerID),
+	)
+	return false, nil
+}
+
+func fillDomainActiveDirectory(target *ActiveDirectory, source *ActiveDirectory) error {
+	if source.RealmName != nil {
+		target.RealmName = pointy.String(*source.RealmName)
+	}
+	if source.ForestName != nil {
+		target.ForestName = pointy.String(*source.ForestName)
+	}
+	target.DomainControllers = make([]ActiveDirectoryDomainController, len(source.DomainControllers))
+	for i := range source.DomainControllers {
+		target.DomainControllers[i] = source.DomainControllers[i]
+		target.DomainControllers[i].ActiveDirectoryID = target.ID
+	}
+	target.Sites = make([]ActiveDirectorySite, len(source.Sites))
+	for i := range source.Sites {
+		target.Sites[i] = source.Sites[i]
+		target.Sites[i].ActiveDirectoryID = target.ID
+	}
+	target.UpdateAgents = source.UpdateAgents
+	return nil
+}
@@ -0,0 +1,58 @@
+package activedirectory
+
+import (
+	"github.com/lib/pq"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"go.openly.dev/pointy"
+)
+
+func (domainType) TranslateRegister(body *public.Domain, domain *model.Domain) error {
+	if body == nil {
+		return internal_errors.NilArgError("body")
+	}
+	domain.Type = pointy.Uint(TypeID)
+	domainAD := &ActiveDirectory{}
+	domain.TypeData = domainAD
+	return translateDomainActiveDirectory(body.ActiveDirectory, domainAD)
+}
+
+func (domainType) TranslateUpdateAgent(body *public.UpdateDomainAgentRequest, domain *model.Domain) error {
+	if body == nil {
+		return internal_errors.NilArgError("body")
+	}
+	domain.Type = pointy.Uint(TypeID)
+	domainAD := &ActiveDirectory{}
+	domain.TypeData = domainAD
+	return translateDomainActiveDirectory(body.ActiveDirectory, domainAD)
+}
+
+// translateDomainActiveDirectory translates the public.DomainActiveDirectory
+// to the ActiveDirectory
+func translateDomainActiveDirectory(body *public.DomainActiveDirectory, domainAD *ActiveDirectory) error {
+	if body == nil {
+		return internal_errors.NilArgError("active-directory")
+	}
+	domainAD.RealmName = pointy.String(body.RealmName)
+	domainAD.ForestName = pointy.String(body.ForestName)
+
+	domainAD.UpdateAgents = make(pq.StringArray, len(body.UpdateAgents))
+	for idx := range body.UpdateAgents {
+		domainAD.UpdateAgents[idx] = body.UpdateAgents[idx].String()
+	}
+
+	domainAD.DomainControllers = make([]ActiveDirectoryDomainController, len(body.DomainControllers))
+	for idx, dc := range body.DomainControllers {
+		domainAD.DomainControllers[idx].FQDN = dc.Fqdn
+		domainAD.DomainControllers[idx].Site = dc.Site
+		domainAD.DomainControllers[idx].GlobalCatalog = dc.GlobalCatalog
+	}
+
+	domainAD.Sites = make([]ActiveDirectorySite, len(body.Sites))
+	for idx, site := range body.Sites {
+		domainAD.Sites[idx].Name = site.Name
+		domainAD.Sites[idx].Description = site.Description
+	}
+	return nil
+}
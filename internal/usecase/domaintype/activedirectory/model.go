@@ -0,0 +1,90 @@
+package activedirectory
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"gorm.io/gorm"
+)
+
+// See: https://gorm.io/docs/models.html
+// See: https://gorm.io/docs/conventions.html
+
+const (
+	// TypeID is the value stored at domains.type for the
+	// active-directory domains.
+	TypeID uint = 2
+	// TypeName is the domain_type of the active-directory domains.
+	TypeName = "active-directory"
+)
+
+// ActiveDirectory represent the specific active-directory domain
+// information. It is stored at model.Domain.TypeData.
+type ActiveDirectory struct {
+	gorm.Model
+	DomainControllers []ActiveDirectoryDomainController
+	Sites             []ActiveDirectorySite
+	RealmName         *string
+	ForestName        *string
+	// UpdateAgents is the list of subscription manager ids of the
+	// RHEL hosts that are allowed to update the domain information.
+	UpdateAgents pq.StringArray `gorm:"type:text[]"`
+
+	Domain model.Domain `gorm:"foreignKey:ID;references:ID"`
+}
+
+func init() {
+	model.MustRegisterDomainType(TypeID, TypeName, modelHooks{})
+}
+
+// Data return the active-directory information of domain, or nil
+// when it is not filled.
+func Data(domain *model.Domain) *ActiveDirectory {
+	if domain == nil {
+		return nil
+	}
+	data, _ := domain.TypeData.(*ActiveDirectory)
+	return data
+}
+
+// modelHooks implements model.DomainTypeModel for active-directory
+// domains.
+type modelHooks struct{}
+
+func (modelHooks) AfterCreate(d *model.Domain) {
+	if data := Data(d); data != nil {
+		data.ID = d.ID
+	}
+}
+
+func (modelHooks) Preload(db *gorm.DB, d *model.Domain) error {
+	data := &ActiveDirectory{}
+	if err := db.
+		Model(&ActiveDirectory{}).
+		Preload("DomainControllers").
+		Preload("Sites").
+		First(data, "id = ?", d.ID).
+		Error; err != nil {
+		return err
+	}
+	d.TypeData = data
+	return nil
+}
+
+func (a *ActiveDirectory) AfterCreate(tx *gorm.DB) (err error) {
+	if a == nil {
+		return fmt.Errorf("'AfterCreate' cannot be invoked on nil")
+	}
+	if a.DomainControllers != nil {
+		for idx := range a.DomainControllers {
+			a.DomainControllers[idx].ActiveDirectoryID = a.ID
+		}
+	}
+	if a.Sites != nil {
+		for idx := range a.Sites {
+			a.Sites[idx].ActiveDirectoryID = a.ID
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package activedirectory
+
+import "gorm.io/gorm"
+
+// See: https://gorm.io/docs/models.html
+
+// ActiveDirectoryDomainController represent a domain controller
+// for an active-directory domain.
+type ActiveDirectoryDomainController struct {
+	gorm.Model
+	ActiveDirectoryID uint
+	FQDN              string
+	Site              *string
+	GlobalCatalog     bool
+}
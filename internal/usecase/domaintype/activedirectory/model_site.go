@@ -0,0 +1,12 @@
+package activedirectory
+
+import "gorm.io/gorm"
+
+// ActiveDirectorySite represent the possible sites for
+// an active-directory domain.
+type ActiveDirectorySite struct {
+	gorm.Model
+	ActiveDirectoryID uint
+	Name              string
+	Description       *string
+}
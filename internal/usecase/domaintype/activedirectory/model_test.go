@@ -0,0 +1,52 @@
+package activedirectory
+
+import (
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+func TestModelRegistration(t *testing.T) {
+	assert.Equal(t, TypeName, model.DomainTypeString(TypeID))
+	assert.Equal(t, TypeID, model.DomainTypeUint(TypeName))
+}
+
+func TestData(t *testing.T) {
+	assert.Nil(t, Data(nil))
+	assert.Nil(t, Data(&model.Domain{}))
+	assert.Nil(t, Data(&model.Domain{TypeData: "other"}))
+	data := &ActiveDirectory{}
+	assert.Same(t, data, Data(&model.Domain{TypeData: data}))
+}
+
+func TestDomainAfterCreate(t *testing.T) {
+	item := &model.Domain{
+		Model: gorm.Model{
+			ID: 2,
+		},
+		Type:     pointy.Uint(TypeID),
+		TypeData: &ActiveDirectory{},
+	}
+	require.NoError(t, item.AfterCreate(nil))
+	assert.Equal(t, item.ID, Data(item).ID)
+}
+
+func TestActiveDirectoryAfterCreate(t *testing.T) {
+	var item *ActiveDirectory
+	assert.EqualError(t, item.AfterCreate(nil), "'AfterCreate' cannot be invoked on nil")
+
+	item = &ActiveDirectory{
+		Model: gorm.Model{
+			ID: 1,
+		},
+		DomainControllers: []ActiveDirectoryDomainController{{}},
+		Sites:             []ActiveDirectorySite{{}},
+	}
+	require.NoError(t, item.AfterCreate(nil))
+	assert.Equal(t, item.ID, item.DomainControllers[0].ActiveDirectoryID)
+	assert.Equal(t, item.ID, item.Sites[0].ActiveDirectoryID)
+}
@@ -0,0 +1,93 @@
+package activedirectory
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+)
+
+func (domainType) PresentDomain(domain *model.Domain, output *public.Domain) error {
+	return sharedDomainFillActiveDirectory(domain, output)
+}
+
+func (domainType) PresentHostConf(domain *model.Domain, location *model.HostLocation, response *public.HostConfResponse) error {
+	domainAD := Data(domain)
+	if domainAD == nil {
+		return internal_errors.NilArgError("domain.TypeData")
+	}
+	controllers := make([]public.HostConfActiveDirectoryController, 0, len(domainAD.DomainControllers))
+	for _, dc := range domainAD.DomainControllers {
+		controllers = append(controllers, public.HostConfActiveDirectoryController{Fqdn: dc.FQDN, Site: dc.Site})
+	}
+	if len(controllers) == 0 {
+		return fmt.Errorf("domain '%s' has no domain controllers", *domain.DomainName)
+	}
+	controllers = model.SelectLocation(controllers, location, func(dc *public.HostConfActiveDirectoryController) *string {
+		return dc.Site
+	})
+	response.ActiveDirectory = &public.HostConfActiveDirectory{
+		RealmName:          *domainAD.RealmName,
+		DomainControllers:  controllers,
+		ClientSoftware:     "sssd",
+		MembershipSoftware: "adcli",
+	}
+	return nil
+}
+
+func sharedDomainFillActiveDirectory(
+	domain *model.Domain,
+	output *public.Domain,
+) (err error) {
+	if domain.Type != nil && *domain.Type != TypeID {
+		return fmt.Errorf(
+			"'domain.Type' is not '%s'",
+			TypeName,
+		)
+	}
+	source := Data(domain)
+	if source == nil {
+		return internal_errors.NilArgError("domain.TypeData")
+	}
+	if output == nil {
+		panic("'output' is nil")
+	}
+	output.ActiveDirectory = &public.DomainActiveDirectory{}
+	if source.RealmName != nil {
+		output.ActiveDirectory.RealmName = *source.RealmName
+	}
+	if source.ForestName != nil {
+		output.ActiveDirectory.ForestName = *source.ForestName
+	}
+
+	output.ActiveDirectory.UpdateAgents = make([]uuid.UUID, 0, len(source.UpdateAgents))
+	for i := range source.UpdateAgents {
+		agent, err := uuid.Parse(source.UpdateAgents[i])
+		if err != nil {
+			return fmt.Errorf("'update_agents' contains an invalid id: %w", err)
+		}
+		output.ActiveDirectory.UpdateAgents = append(output.ActiveDirectory.UpdateAgents, agent)
+	}
+
+	output.ActiveDirectory.DomainControllers = make(
+		[]public.DomainActiveDirectoryController,
+		len(source.DomainControllers),
+	)
+	for i := range source.DomainControllers {
+		output.ActiveDirectory.DomainControllers[i].Fqdn = source.DomainControllers[i].FQDN
+		output.ActiveDirectory.DomainControllers[i].Site = source.DomainControllers[i].Site
+		output.ActiveDirectory.DomainControllers[i].GlobalCatalog = source.DomainControllers[i].GlobalCatalog
+	}
+
+	output.ActiveDirectory.Sites = make(
+		[]public.Location,
+		len(source.Sites),
+	)
+	for i := range source.Sites {
+		output.ActiveDirectory.Sites[i].Name = source.Sites[i].Name
+		output.ActiveDirectory.Sites[i].Description = source.Sites[i].Description
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package activedirectory
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (domainType) Create(log *slog.Logger, db *gorm.DB, domain *model.Domain) error {
+	data := Data(domain)
+	if data == nil {
+		return internal_errors.NilArgError("TypeData")
+	}
+	return createActiveDirectoryDomain(log, db, domain.ID, data)
+}
+
+func (domainType) Update(log *slog.Logger, db *gorm.DB, domain *model.Domain) error {
+	data := Data(domain)
+	if data == nil {
+		return internal_errors.NilArgError("TypeData")
+	}
+	data.ID = domain.ID
+	return updateActiveDirectoryDomain(log, db, data)
+}
+
+func createActiveDirectoryDomain(
+	log *slog.Logger,
+	db *gorm.DB,
+	domainID uint,
+	data *ActiveDirectory,
+) (err error) {
+	if data == nil {
+		err = internal_errors.NilArgError("data")
+		log.Error(err.Error())
+		return err
+	}
+	data.Model.ID = domainID
+	if err = db.Omit(clause.Associations).Create(data).Error; err != nil {
+		log.Error("failed to create the active_directories record")
+		return err
+	}
+	for idx := range data.DomainControllers {
+		data.DomainControllers[idx].Model.ID = 0
+		data.DomainControllers[idx].ActiveDirectoryID = domainID
+		if err = db.Create(&data.DomainControllers[idx]).Error; err != nil {
+			log.Error("failed to create the active_directory_domain_controllers record",
+				slog.String("fqdn", data.DomainControllers[idx].FQDN),
+			)
+			return err
+		}
+	}
+	for idx := range data.Sites {
+		data.Sites[idx].Model.ID = 0
+		data.Sites[idx].ActiveDirectoryID = domainID
+		if err = db.Create(&data.Sites[idx]).Error; err != nil {
+			log.Error("failed to create the active_directory_sites record",
+				slog.String("name", data.Sites[idx].Name),
+			)
+			return err
+		}
+	}
+	return nil
+}
+
+func updateActiveDirectoryDomain(
+	log *slog.Logger,
+	db *gorm.DB,
+	dataAD *ActiveDirectory,
+) (err error) {
+	if log == nil {
+		err = internal_errors.NilArgError("log")
+		slog.Default().Error(err.Error())
+		return err
+	}
+	if db == nil {
+		err = internal_errors.NilArgError("db")
+		log.Error(err.Error())
+		return err
+	}
+	if dataAD == nil {
+		err = internal_errors.NilArgError("dataAD")
+		log.Error(err.Error())
+		return err
+	}
+	// Same as for the rhel-idm type, avoid deleting all the
+	// records when the ID is not set
+	if dataAD.Model.ID == 0 {
+		err = fmt.Errorf("dataAD.Model.ID cannot be 0")
+		log.Error(err.Error())
+		return err
+	}
+	domainID := dataAD.Model.ID
+	if err = db.Unscoped().
+		Delete(dataAD).Error; err != nil {
+		log.Error("updating active directory domain when deleting old record")
+		return err
+	}
+
+	if err = createActiveDirectoryDomain(log, db, domainID, dataAD); err != nil {
+		log.Error("updating active directory domain when creating new records")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package ipa
+
+// The repository tests live at package ipa_test because the
+// internal/test helpers they use import this package.
+var (
+	CheckIDRangesOverlap       = checkIDRangesOverlap
+	EnsureIDRangesDoNotOverlap = ensureIDRangesDoNotOverlap
+	CreateIpaDomain            = createIpaDomain
+	UpdateIpaDomain            = updateIpaDomain
+	CreateIpaTrusts            = createIpaTrusts
+)
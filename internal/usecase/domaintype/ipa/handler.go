@@ -0,0 +1,199 @@
+package ipa
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"go.openly.dev/pointy"
+)
+
+func (domainType) Fill(target *model.Domain, source *model.Domain) error {
+	sourceIpa := Data(source)
+	if sourceIpa == nil {
+		return internal_errors.NilArgError("source.TypeData")
+	}
+	targetIpa := &Ipa{}
+	target.TypeData = targetIpa
+	return fillDomainIpa(targetIpa, sourceIpa)
+}
+
+func (domainType) ValidateUpdate(current *model.Domain, data *model.Domain) error {
+	dataIpa, currentIpa := Data(data), Data(current)
+	if dataIpa != nil && currentIpa != nil &&
+		dataIpa.RealmName != nil && currentIpa.RealmName != nil &&
+		*dataIpa.RealmName != *currentIpa.RealmName {
+		return internal_errors.NewHTTPErrorF(
+			http.StatusBadRequest,
+			"'realm_name' may not be changed",
+		)
+	}
+	return nil
+}
+
+func (domainType) EnsureUpdateAgentEnabled(ctx context.Context, subscriptionManagerID string, data *model.Domain) error {
+	dataIpa := Data(data)
+	if dataIpa == nil {
+		return internal_errors.NilArgError("data.TypeData")
+	}
+	return ensureUpdateServerEnabledForUpdates(ctx, subscriptionManagerID, dataIpa.Servers)
+}
+
+func (domainType) EnsureUpdateAgentAuthorized(ctx context.Context, subscriptionManagerID string, current *model.Domain) error {
+	currentIpa := Data(current)
+	if currentIpa == nil {
+		return internal_errors.NilArgError("current.TypeData")
+	}
+	return ensureSubscriptionManagerIDAuthorizedToUpdate(ctx, subscriptionManagerID, currentIpa.Servers)
+}
+
+func (domainType) FillRegistration(subscriptionManagerID string, domain *model.Domain, registration *model.DomainRegistration) error {
+	domainIpa := Data(domain)
+	if domainIpa == nil {
+		return internal_errors.NilArgError("domain.TypeData")
+	}
+	if registration == nil {
+		return internal_errors.NilArgError("registration")
+	}
+	for i := range domainIpa.Servers {
+		server := &domainIpa.Servers[i]
+		if server.RHSMId != nil && *server.RHSMId == subscriptionManagerID {
+			registration.FQDN = pointy.String(server.FQDN)
+			break
+		}
+	}
+	for _, cert := range domainIpa.CaCerts {
+		registration.CaCerts = append(registration.CaCerts, model.RegistrationCert{
+			Issuer:       cert.Issuer,
+			Nickname:     cert.Nickname,
+			NotAfter:     cert.NotAfter,
+			NotBefore:    cert.NotBefore,
+			Pem:          cert.Pem,
+			SerialNumber: cert.SerialNumber,
+			Subject:      cert.Subject,
+		})
+	}
+	return nil
+}
+
+func subscriptionManagerIDIncluded(
+	ctx context.Context,
+	subscriptionManagerID string,
+	servers []IpaServer,
+) (bool, error) {
+	logger := app_context.LogFromCtx(ctx)
+	if subscriptionManagerID == "" {
+		logger.Error("'subscriptionManagerID' is an empty string")
+		return false, fmt.Errorf("'subscriptionManagerID' is empty")
+	}
+	if servers == nil {
+		logger.Error("'servers' is nil")
+		return false, internal_errors.NilArgError("servers")
+	}
+	for i := range servers {
+		rhsmid := "nil"
+		if servers[i].RHSMId != nil {
+			rhsmid = *servers[i].RHSMId
+		}
+
+		logger.Debug("Checking server",
+			slog.Bool("HCCUpdateServer", servers[i].HCCUpdateServer),
+			slog.String("RHSMId", rhsmid),
+			slog.String("subscriptionManagerID", subscriptionManagerID),
+		)
+		if servers[i].HCCUpdateServer &&
+			servers[i].RHSMId != nil &&
+			*servers[i].RHSMId == subscriptionManagerID {
+			logger.Debug("server found in the list of enabled servers",
+				slog.String("subscriptionManagerID", subscriptionManagerID),
+				slog.Bool("HCCUpdateServer", true),
+			)
+			return true, nil
+		}
+	}
+	logger.Debug("server not found in the list of enabled servers",
+		slog.String("subscriptionManagerID", subscriptionManagerID),
+	)
+	return false, nil
+}
+
+// ensureSubscriptionManagerIDAuthorizedToUpdate checks if a server with
+// the subscription manager ID is authorized to update the domain.
+// Returns a Forbidden error if it is not.
+func ensureSubscriptionManagerIDAuthorizedToUpdate(
+	ctx context.Context,
+	subscriptionManagerID string,
+	servers []IpaServer,
+) error {
+	included, err := subscriptionManagerIDIncluded(ctx, subscriptionManagerID, servers)
+	if err != nil {
+		return err
+	}
+	if included {
+		return nil
+	}
+	return internal_errors.NewHTTPErrorF(
+		http.StatusForbidden,
+		"update server is not authorized to update the domain",
+	)
+}
+
+// ensureUpdateServerEnabledForUpdates checks if the update server with the subscription
+// manager ID is included in the list of servers and is enabled for updates.
+// Returns a BadRequest error if it is not.
+func ensureUpdateServerEnabledForUpdates(
+	ctx context.Context,
+	subscriptionManagerID string,
+	servers []IpaServer,
+) error {
+	included, err := subscriptionManagerIDIncluded(ctx, subscriptionManagerID, servers)
+	if err != nil {
+		return err
+	}
+	if !included {
+		return internal_errors.NewHTTPErrorF(
+			http.StatusBadRequest,
+			"update server's 'Subscription Manager ID' not found in the authorized list of rhel-idm servers",
+		)
+	}
+	return nil
+}
+
+func fillDomainIpa(target *Ipa, source *Ipa) error {
+	if source.RealmName != nil {
+		target.RealmName = pointy.String(*source.RealmName)
+	}
+	target.CaCerts = make([]IpaCert, len(source.CaCerts))
+	for i := range source.CaCerts {
+		target.CaCerts[i] = source.CaCerts[i]
+		target.CaCerts[i].IpaID = target.ID
+	}
+	target.Servers = make([]IpaServer, len(source.Servers))
+	for i := range source.Servers {
+		target.Servers[i] = source.Servers[i]
+		target.Servers[i].IpaID = target.ID
+	}
+	target.Locations = make([]IpaLocation, len(source.Locations))
+	for i := range source.Locations {
+		target.Locations[i] = source.Locations[i]
+		target.Locations[i].IpaID = target.ID
+	}
+	target.Trusts = make([]IpaTrust, len(source.Trusts))
+	for i := range source.Trusts {
+		target.Trusts[i] = source.Trusts[i]
+		target.Trusts[i].IpaID = target.ID
+		target.Trusts[i].Domains = append([]IpaTrustDomain{}, source.Trusts[i].Domains...)
+		target.Trusts[i].IDRanges = append([]IpaTrustIDRange{}, source.Trusts[i].IDRanges...)
+	}
+	target.IDRanges = make([]IpaIDRange, len(source.IDRanges))
+	for i := range source.IDRanges {
+		target.IDRanges[i] = source.IDRanges[i]
+		target.IDRanges[i].IpaID = target.ID
+	}
+	target.RealmDomains = source.RealmDomains
+	return nil
+}
@@ -0,0 +1,151 @@
+package ipa
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"gorm.io/gorm"
+)
+
+// orgIDRange is an ID range stored for another domain of the
+// organization.
+type orgIDRange struct {
+	IpaIDRange
+	DomainName *string
+}
+
+// createIpaIDRanges creates the ipa_id_ranges records for the
+// ipas record ipaID.
+func createIpaIDRanges(
+	log *slog.Logger,
+	db *gorm.DB,
+	ipaID uint,
+	idRanges []IpaIDRange,
+) (err error) {
+	for i := range idRanges {
+		idRanges[i].Model.ID = 0
+		idRanges[i].IpaID = ipaID
+		if err = db.Create(&idRanges[i]).Error; err != nil {
+			log.Error("failed to create the ipa_id_ranges record",
+				slog.String("name", idRanges[i].Name),
+			)
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIDRangesOverlap check that the ID ranges of a domain do not
+// overlap between them.
+// Return nil if they do not overlap, else a BadRequest error
+// describing every overlap.
+func checkIDRangesOverlap(idRanges []IpaIDRange) error {
+	var overlaps []string
+	for i := range idRanges {
+		for j := i + 1; j < len(idRanges); j++ {
+			if idRanges[i].Overlaps(&idRanges[j]) {
+				overlaps = append(overlaps, fmt.Sprintf(
+					"ID range '%s' overlaps with ID range '%s'",
+					idRanges[i].Name, idRanges[j].Name,
+				))
+			}
+		}
+	}
+	return overlapsError(overlaps)
+}
+
+// ensureIDRangesDoNotOverlap check that the ID ranges of domain do not
+// overlap between them nor with the ID ranges of the other domains
+// of the same organization. The subordinate ID ranges are only
+// checked inside the domain, as every rhel-idm deployment uses the
+// same subordinate ID range by default, and the subordinate IDs are
+// not shared between realms. It takes the advisory lock on the domains
+// of the organization, the same one the quota of domains uses, so the
+// concurrent registrations and updates of the organization check
+// their ID ranges one after other.
+// Return nil if they do not overlap, else a BadRequest error
+// describing every overlap.
+func ensureIDRangesDoNotOverlap(
+	log *slog.Logger,
+	db *gorm.DB,
+	domain *model.Domain,
+) (err error) {
+	data := Data(domain)
+	if data == nil {
+		err = internal_errors.NilArgError("domain.TypeData")
+		log.Error(err.Error())
+		return err
+	}
+	idRanges := data.IDRanges
+	if len(idRanges) == 0 {
+		return nil
+	}
+	if err = checkIDRangesOverlap(idRanges); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if db == nil {
+		err = internal_errors.NilArgError("db")
+		log.Error(err.Error())
+		return err
+	}
+
+	if err = db.Exec(
+		"SELECT pg_advisory_xact_lock(hashtext(?))",
+		"domains/"+domain.OrgId,
+	).Error; err != nil {
+		log.Error("failed to lock the domains of the organization")
+		return err
+	}
+
+	var orgIDRanges []orgIDRange
+	if err = db.Table("ipa_id_ranges").
+		Select("ipa_id_ranges.*, domains.domain_name").
+		Joins("INNER JOIN domains ON domains.id = ipa_id_ranges.ipa_id").
+		Where("domains.org_id = ? AND domains.id <> ? AND domains.deleted_at IS NULL", domain.OrgId, domain.ID).
+		Where("ipa_id_ranges.deleted_at IS NULL AND ipa_id_ranges.type <> ?", IpaIDRangeTypeLocalSubID).
+		Scan(&orgIDRanges).
+		Error; err != nil {
+		log.Error("failed to read the ID ranges of the organization")
+		return err
+	}
+
+	var overlaps []string
+	for i := range idRanges {
+		if idRanges[i].Type == IpaIDRangeTypeLocalSubID {
+			continue
+		}
+		for j := range orgIDRanges {
+			if idRanges[i].Overlaps(&orgIDRanges[j].IpaIDRange) {
+				domainName := "<unknown>"
+				if orgIDRanges[j].DomainName != nil {
+					domainName = *orgIDRanges[j].DomainName
+				}
+				overlaps = append(overlaps, fmt.Sprintf(
+					"ID range '%s' overlaps with ID range '%s' of domain '%s'",
+					idRanges[i].Name, orgIDRanges[j].Name, domainName,
+				))
+			}
+		}
+	}
+	if err = overlapsError(overlaps); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func overlapsError(overlaps []string) error {
+	if len(overlaps) == 0 {
+		return nil
+	}
+	return internal_errors.NewHTTPErrorF(
+		http.StatusBadRequest,
+		"'id_ranges' overlap: %s",
+		strings.Join(overlaps, "; "),
+	)
+}
@@ -0,0 +1,101 @@
+package ipa_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/labstack/echo/v4"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+func (s *RepositorySuite) TestCheckIDRangesOverlap() {
+	t := s.Suite.T()
+	assert.NoError(t, ipa.CheckIDRangesOverlap(nil))
+	assert.NoError(t, ipa.CheckIDRangesOverlap([]ipa.IpaIDRange{
+		{Name: "local", BaseID: 1000, RangeSize: 1000, Type: ipa.IpaIDRangeTypeLocal},
+		{Name: "trust", BaseID: 2000, RangeSize: 1000, Type: ipa.IpaIDRangeTypeADTrust},
+	}))
+
+	err := ipa.CheckIDRangesOverlap([]ipa.IpaIDRange{
+		{Name: "local", BaseID: 1000, RangeSize: 1000, Type: ipa.IpaIDRangeTypeLocal},
+		{Name: "trust", BaseID: 1999, RangeSize: 1000, Type: ipa.IpaIDRangeTypeADTrust},
+	})
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, "'id_ranges' overlap: ID range 'local' overlaps with ID range 'trust'", httpErr.Message)
+}
+
+func (s *RepositorySuite) TestEnsureIDRangesDoNotOverlap() {
+	t := s.Suite.T()
+	domain := &model.Domain{
+		Model: gorm.Model{ID: 1},
+		OrgId: "12345",
+		TypeData: &ipa.Ipa{
+			IDRanges: []ipa.IpaIDRange{
+				{Name: "local", BaseID: 1000, RangeSize: 1000, Type: ipa.IpaIDRangeTypeLocal},
+				{Name: "subid", BaseID: 2147483648, RangeSize: 2147352576, Type: ipa.IpaIDRangeTypeLocalSubID},
+			},
+		},
+	}
+	expectLock := func() *sqlmock.ExpectedExec {
+		return s.mock.ExpectExec(regexp.QuoteMeta(
+			`SELECT pg_advisory_xact_lock(hashtext($1))`,
+		)).WithArgs("domains/12345")
+	}
+	expectQuery := func() *sqlmock.ExpectedQuery {
+		expectLock().WillReturnResult(sqlmock.NewResult(0, 0))
+		return s.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT ipa_id_ranges.*, domains.domain_name FROM "ipa_id_ranges" `+
+				`INNER JOIN domains ON domains.id = ipa_id_ranges.ipa_id `+
+				`WHERE (domains.org_id = $1 AND domains.id <> $2 AND domains.deleted_at IS NULL) `+
+				`AND (ipa_id_ranges.deleted_at IS NULL AND ipa_id_ranges.type <> $3)`,
+		)).WithArgs("12345", uint(1), ipa.IpaIDRangeTypeLocalSubID)
+	}
+	columns := []string{"id", "ipa_id", "name", "base_id", "range_size", "type", "domain_name"}
+
+	// No ID ranges does not query the database
+	require.NoError(t, ipa.EnsureIDRangesDoNotOverlap(s.Log, nil, &model.Domain{TypeData: &ipa.Ipa{}}))
+
+	// Lock error
+	expectedErr := fmt.Errorf("database error")
+	expectLock().WillReturnError(expectedErr)
+	require.EqualError(t, ipa.EnsureIDRangesDoNotOverlap(s.Log, s.DB, domain), expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Database error
+	expectQuery().WillReturnError(expectedErr)
+	require.EqualError(t, ipa.EnsureIDRangesDoNotOverlap(s.Log, s.DB, domain), expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// No overlap with other domains
+	expectQuery().WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(10, 2, "other", 2000, 1000, ipa.IpaIDRangeTypeLocal, pointy.String("other.example")))
+	require.NoError(t, ipa.EnsureIDRangesDoNotOverlap(s.Log, s.DB, domain))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Overlap with other domain
+	expectQuery().WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(10, 2, "other", 1500, 1000, ipa.IpaIDRangeTypeLocal, pointy.String("other.example")))
+	err := ipa.EnsureIDRangesDoNotOverlap(s.Log, s.DB, domain)
+	require.Error(t, err)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, "'id_ranges' overlap: ID range 'local' overlaps with ID range 'other' of domain 'other.example'", httpErr.Message)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Overlap inside the domain does not query the database
+	ipa.Data(domain).IDRanges = append(ipa.Data(domain).IDRanges,
+		ipa.IpaIDRange{Name: "dup", BaseID: 1500, RangeSize: 10, Type: ipa.IpaIDRangeTypeLocal})
+	require.Error(t, ipa.EnsureIDRangesDoNotOverlap(s.Log, s.DB, domain))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
@@ -0,0 +1,172 @@
+package ipa
+
+import (
+	"github.com/lib/pq"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"go.openly.dev/pointy"
+)
+
+func (domainType) TranslateRegister(body *public.Domain, domain *model.Domain) error {
+	if body == nil {
+		return internal_errors.NilArgError("body")
+	}
+	domain.Type = pointy.Uint(TypeID)
+	data := &Ipa{}
+	domain.TypeData = data
+	return translateDomainIpa(body.RhelIdm, data)
+}
+
+func (domainType) TranslateUpdateAgent(body *public.UpdateDomainAgentRequest, domain *model.Domain) error {
+	if body == nil {
+		return internal_errors.NilArgError("body")
+	}
+	domain.Type = pointy.Uint(TypeID)
+	data := &Ipa{}
+	domain.TypeData = data
+	return translateDomainIpa(body.RhelIdm, data)
+}
+
+// translateDomainIpa translates the public.DomainIpa to the Ipa
+func translateDomainIpa(body *public.DomainIpa, domainIpa *Ipa) error {
+	if body == nil {
+		return internal_errors.NilArgError("rhel-idm")
+	}
+	domainIpa.RealmName = pointy.String(body.RealmName)
+
+	// Translate realm domains
+	translateRealmDomains(body, domainIpa)
+
+	// Certificate list
+	translateIdmCaCerts(body, domainIpa)
+
+	// Server list
+	translateIdmServers(body, domainIpa)
+
+	// Location list
+	translateIdmLocations(body, domainIpa)
+
+	// Trust list
+	translateIdmTrusts(body, domainIpa)
+
+	// ID range list
+	translateIdmIDRanges(body, domainIpa)
+
+	return nil
+}
+
+func translateRealmDomains(body *public.DomainIpa, domainIpa *Ipa) {
+	if body.RealmDomains == nil {
+		domainIpa.RealmDomains = pq.StringArray{}
+		return
+	}
+	domainIpa.RealmDomains = make(pq.StringArray, 0)
+	domainIpa.RealmDomains = append(
+		domainIpa.RealmDomains,
+		body.RealmDomains...,
+	)
+}
+
+func translateIdmCaCerts(body *public.DomainIpa, domainIpa *Ipa) {
+	if body.CaCerts == nil {
+		domainIpa.CaCerts = []IpaCert{}
+		return
+	}
+	domainIpa.CaCerts = make([]IpaCert, len(body.CaCerts))
+	for idx := range body.CaCerts {
+		translateIdmCaCert(&domainIpa.CaCerts[idx], &body.CaCerts[idx])
+	}
+}
+
+func translateIdmCaCert(caCert *IpaCert, cert *public.Certificate) {
+	caCert.Nickname = cert.Nickname
+	caCert.Issuer = cert.Issuer
+	caCert.Subject = cert.Subject
+	caCert.SerialNumber = cert.SerialNumber
+	caCert.NotBefore = cert.NotBefore
+	caCert.NotAfter = cert.NotAfter
+	caCert.Pem = cert.Pem
+}
+
+func translateIdmServers(body *public.DomainIpa, domainIpa *Ipa) {
+	if body.Servers == nil {
+		domainIpa.Servers = []IpaServer{}
+		return
+	}
+	domainIpa.Servers = make([]IpaServer, len(body.Servers))
+	for idx, server := range body.Servers {
+		domainIpa.Servers[idx].FQDN = server.Fqdn
+		if server.SubscriptionManagerId != nil {
+			domainIpa.Servers[idx].RHSMId = pointy.String(server.SubscriptionManagerId.String())
+		}
+		domainIpa.Servers[idx].Location = server.Location
+		domainIpa.Servers[idx].PKInitServer = server.PkinitServer
+		domainIpa.Servers[idx].CaServer = server.CaServer
+		domainIpa.Servers[idx].HCCEnrollmentServer = server.HccEnrollmentServer
+		domainIpa.Servers[idx].HCCUpdateServer = server.HccUpdateServer
+	}
+}
+
+func translateIdmLocations(body *public.DomainIpa, domainIpa *Ipa) {
+	if body.Locations == nil {
+		domainIpa.Locations = []IpaLocation{}
+		return
+	}
+	domainIpa.Locations = make([]IpaLocation, len(body.Locations))
+	for idx, location := range body.Locations {
+		domainIpa.Locations[idx].Name = location.Name
+		domainIpa.Locations[idx].Description = location.Description
+	}
+}
+
+func translateIdmTrusts(body *public.DomainIpa, domainIpa *Ipa) {
+	if body.Trusts == nil {
+		// trusts are optional, for agents that do not report them
+		domainIpa.Trusts = nil
+		return
+	}
+	trusts := *body.Trusts
+	domainIpa.Trusts = make([]IpaTrust, len(trusts))
+	for idx := range trusts {
+		translateIdmTrust(&domainIpa.Trusts[idx], &trusts[idx])
+	}
+}
+
+func translateIdmTrust(target *IpaTrust, trust *public.DomainIpaTrust) {
+	target.ForestName = trust.ForestName
+	target.FlatName = trust.FlatName
+	target.SID = trust.Sid
+	target.Direction = string(trust.Direction)
+	target.Domains = make([]IpaTrustDomain, len(trust.Domains))
+	for idx, domain := range trust.Domains {
+		target.Domains[idx].Name = domain.Name
+		target.Domains[idx].FlatName = domain.FlatName
+		target.Domains[idx].SID = domain.Sid
+	}
+	target.IDRanges = make([]IpaTrustIDRange, len(trust.IdRanges))
+	for idx, idRange := range trust.IdRanges {
+		target.IDRanges[idx].Name = idRange.Name
+		target.IDRanges[idx].BaseID = idRange.BaseId
+		target.IDRanges[idx].RangeSize = idRange.RangeSize
+		target.IDRanges[idx].Type = string(idRange.Type)
+	}
+}
+
+func translateIdmIDRanges(body *public.DomainIpa, domainIpa *Ipa) {
+	if body.IdRanges == nil {
+		// ID ranges are optional, for agents that do not report them
+		domainIpa.IDRanges = nil
+		return
+	}
+	idRanges := *body.IdRanges
+	domainIpa.IDRanges = make([]IpaIDRange, len(idRanges))
+	for idx, idRange := range idRanges {
+		domainIpa.IDRanges[idx].Name = idRange.Name
+		domainIpa.IDRanges[idx].BaseID = idRange.BaseId
+		domainIpa.IDRanges[idx].RangeSize = idRange.RangeSize
+		domainIpa.IDRanges[idx].Type = string(idRange.Type)
+		domainIpa.IDRanges[idx].BaseRID = idRange.BaseRid
+		domainIpa.IDRanges[idx].SecondaryBaseRID = idRange.SecondaryBaseRid
+	}
+}
@@ -0,0 +1,141 @@
+package ipa
+
+import (
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/stretchr/testify/assert"
+	"go.openly.dev/pointy"
+)
+
+func TestRegisterOrUpdateRhelIdmLocations(t *testing.T) {
+	type TestCase struct {
+		Name     string
+		Given    *public.Domain
+		Expected *Ipa
+	}
+	testCases := []TestCase{
+		{
+			Name: "nil Locations",
+			Given: &public.Domain{
+				RhelIdm: &public.DomainIpa{
+					Locations: nil,
+				},
+			},
+			Expected: &Ipa{
+				Locations: []IpaLocation{},
+			},
+		},
+		{
+			Name: "Empty Locations slice",
+			Given: &public.Domain{
+				RhelIdm: &public.DomainIpa{
+					Locations: []public.Location{},
+				},
+			},
+			Expected: &Ipa{
+				Locations: []IpaLocation{},
+			},
+		},
+		{
+			Name: "Location without description",
+			Given: &public.Domain{
+				RhelIdm: &public.DomainIpa{
+					Locations: []public.Location{
+						{
+							Name:        "boston",
+							Description: nil,
+						},
+					},
+				},
+			},
+			Expected: &Ipa{
+				Locations: []IpaLocation{
+					{
+						Name:        "boston",
+						Description: nil,
+					},
+				},
+			},
+		},
+		{
+			Name: "Location with description",
+			Given: &public.Domain{
+				RhelIdm: &public.DomainIpa{
+					Locations: []public.Location{
+						{
+							Name:        "boston",
+							Description: pointy.String("Boston data center"),
+						},
+					},
+				},
+			},
+			Expected: &Ipa{
+				Locations: []IpaLocation{
+					{
+						Name:        "boston",
+						Description: pointy.String("Boston data center"),
+					},
+				},
+			},
+		},
+	}
+	for _, item := range testCases {
+		t.Log(item.Name)
+		ipa := &Ipa{}
+		translateIdmLocations(item.Given.RhelIdm, ipa)
+		assert.Equal(t, item.Expected, ipa)
+	}
+}
+
+func TestTranslateIdmTrusts(t *testing.T) {
+	domainIpa := &Ipa{}
+	translateIdmTrusts(&public.DomainIpa{}, domainIpa)
+	assert.Nil(t, domainIpa.Trusts)
+
+	translateIdmTrusts(&public.DomainIpa{
+		Trusts: &[]public.DomainIpaTrust{
+			{
+				ForestName: "ad.example",
+				Sid:        "S-1-5-21-1-2-3",
+				Direction:  public.TwoWay,
+				Domains: []public.DomainIpaTrustDomain{
+					{Name: "ad.example", FlatName: pointy.String("AD"), Sid: "S-1-5-21-1-2-3"},
+				},
+				IdRanges: []public.DomainIpaTrustIdRange{
+					{Name: "AD.EXAMPLE_id_range", BaseId: 1000000000, RangeSize: 200000, Type: public.IpaAdTrustPosix},
+				},
+			},
+		},
+	}, domainIpa)
+	assert.Equal(t, []IpaTrust{
+		{
+			ForestName: "ad.example",
+			SID:        "S-1-5-21-1-2-3",
+			Direction:  "two-way",
+			Domains: []IpaTrustDomain{
+				{Name: "ad.example", FlatName: pointy.String("AD"), SID: "S-1-5-21-1-2-3"},
+			},
+			IDRanges: []IpaTrustIDRange{
+				{Name: "AD.EXAMPLE_id_range", BaseID: 1000000000, RangeSize: 200000, Type: "ipa-ad-trust-posix"},
+			},
+		},
+	}, domainIpa.Trusts)
+}
+
+func TestTranslateIdmIDRanges(t *testing.T) {
+	domainIpa := &Ipa{}
+	translateIdmIDRanges(&public.DomainIpa{}, domainIpa)
+	assert.Nil(t, domainIpa.IDRanges)
+
+	translateIdmIDRanges(&public.DomainIpa{
+		IdRanges: &[]public.DomainIpaIdRange{
+			{Name: "local", BaseId: 1000, RangeSize: 200000, Type: public.IpaLocal, BaseRid: pointy.Int64(1000), SecondaryBaseRid: pointy.Int64(100000000)},
+			{Name: "subid", BaseId: 2147483648, RangeSize: 2147352576, Type: public.IpaLocalSubid},
+		},
+	}, domainIpa)
+	assert.Equal(t, []IpaIDRange{
+		{Name: "local", BaseID: 1000, RangeSize: 200000, Type: IpaIDRangeTypeLocal, BaseRID: pointy.Int64(1000), SecondaryBaseRID: pointy.Int64(100000000)},
+		{Name: "subid", BaseID: 2147483648, RangeSize: 2147352576, Type: IpaIDRangeTypeLocalSubID},
+	}, domainIpa.IDRanges)
+}
@@ -0,0 +1,30 @@
+// Package ipa implements the rhel-idm domain type.
+package ipa
+
+import (
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/domaintype"
+)
+
+type domainType struct{}
+
+// New return the domaintype.DomainTypeHandler for rhel-idm domains.
+func New() domaintype.DomainTypeHandler {
+	return domainType{}
+}
+
+func (domainType) Type() uint {
+	return TypeID
+}
+
+func (domainType) Name() public.DomainType {
+	return public.RhelIdm
+}
+
+func (domainType) ServerCount(domain *model.Domain) int {
+	if data := Data(domain); data != nil {
+		return len(data.Servers)
+	}
+	return 0
+}
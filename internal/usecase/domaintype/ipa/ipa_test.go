@@ -0,0 +1,43 @@
+package ipa
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	builder_api "github.com/podengo-project/idmsvc-backend/internal/test/builder/api"
+	test_domaintype "github.com/podengo-project/idmsvc-backend/internal/test/domaintype"
+	"go.openly.dev/pointy"
+)
+
+func TestConformance(t *testing.T) {
+	const domainName = "mydomain.example"
+	subscriptionManagerID := uuid.NewString()
+	server := builder_api.NewDomainIpaServer("server1." + domainName).
+		WithHccUpdateServer(true).
+		WithHccEnrollmentServer(true).
+		WithSubscriptionManagerId(subscriptionManagerID).
+		Build()
+	rhelIdm := builder_api.NewRhelIdmDomain(domainName).
+		WithServers([]public.DomainIpaServer{server}).
+		AddTrust(builder_api.NewDomainIpaTrust("ad.example").Build()).
+		AddIdRange(public.DomainIpaIdRange{
+			Name:      "MYDOMAIN.EXAMPLE_id_range",
+			BaseId:    1234000000,
+			RangeSize: 200000,
+			Type:      public.IpaLocal,
+			BaseRid:   pointy.Int64(1000),
+		}).
+		Build()
+	test_domaintype.RunConformanceSuite(t, New(), test_domaintype.Fixture{
+		Register: builder_api.NewDomain(domainName).
+			WithRhelIdm(rhelIdm).
+			Build(),
+		UpdateAgent: builder_api.NewUpdateDomainAgent(domainName).
+			WithDomainRhelIdm(*rhelIdm).
+			Build(),
+		SubscriptionManagerID: subscriptionManagerID,
+		RegistrationFQDN:      pointy.String("server1." + domainName),
+		RegistrationCaCerts:   len(rhelIdm.CaCerts),
+	})
+}
@@ -0,0 +1,109 @@
+package ipa
+
+import (
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"gorm.io/gorm"
+)
+
+// See: https://gorm.io/docs/models.html
+// See: https://gorm.io/docs/conventions.html
+
+const (
+	// TypeID is the value stored at domains.type for the
+	// rhel-idm domains.
+	TypeID uint = 1
+	// TypeName is the domain_type of the rhel-idm domains.
+	TypeName = "rhel-idm"
+)
+
+// Ipa represent the specific rhel-idm domain information. It is
+// stored at model.Domain.TypeData.
+type Ipa struct {
+	gorm.Model
+	CaCerts      []IpaCert
+	Servers      []IpaServer
+	Locations    []IpaLocation
+	Trusts       []IpaTrust
+	IDRanges     []IpaIDRange
+	RealmName    *string
+	RealmDomains pq.StringArray `gorm:"type:text[]"`
+
+	Domain model.Domain `gorm:"foreignKey:ID;references:ID"`
+}
+
+func init() {
+	model.MustRegisterDomainType(TypeID, TypeName, modelHooks{})
+}
+
+// Data return the rhel-idm information of domain, or nil when it
+// is not filled.
+func Data(domain *model.Domain) *Ipa {
+	if domain == nil {
+		return nil
+	}
+	data, _ := domain.TypeData.(*Ipa)
+	return data
+}
+
+// modelHooks implements model.DomainTypeModel for rhel-idm domains.
+type modelHooks struct{}
+
+func (modelHooks) AfterCreate(d *model.Domain) {
+	if data := Data(d); data != nil {
+		data.ID = d.ID
+	}
+}
+
+func (modelHooks) Preload(db *gorm.DB, d *model.Domain) error {
+	data := &Ipa{}
+	if err := db.
+		Model(&Ipa{}).
+		Preload("CaCerts").
+		Preload("Servers").
+		Preload("Locations").
+		Preload("IDRanges").
+		Preload("Trusts").
+		Preload("Trusts.Domains").
+		Preload("Trusts.IDRanges").
+		First(data, "id = ?", d.ID).
+		Error; err != nil {
+		return err
+	}
+	d.TypeData = data
+	return nil
+}
+
+func (i *Ipa) AfterCreate(tx *gorm.DB) (err error) {
+	if i == nil {
+		return fmt.Errorf("'AfterCreate' cannot be invoked on nil")
+	}
+	if i.CaCerts != nil {
+		for idx := range i.CaCerts {
+			i.CaCerts[idx].IpaID = i.ID
+		}
+	}
+	if i.Servers != nil {
+		for idx := range i.Servers {
+			i.Servers[idx].IpaID = i.ID
+		}
+	}
+	if i.Locations != nil {
+		for idx := range i.Locations {
+			i.Locations[idx].IpaID = i.ID
+		}
+	}
+	if i.Trusts != nil {
+		for idx := range i.Trusts {
+			i.Trusts[idx].IpaID = i.ID
+		}
+	}
+	if i.IDRanges != nil {
+		for idx := range i.IDRanges {
+			i.IDRanges[idx].IpaID = i.ID
+		}
+	}
+	return nil
+}
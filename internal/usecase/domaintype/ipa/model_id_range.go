@@ -0,0 +1,30 @@
+package ipa
+
+import "gorm.io/gorm"
+
+// Types of ID ranges for a rhel-idm domain.
+const (
+	IpaIDRangeTypeLocal        = "ipa-local"
+	IpaIDRangeTypeLocalSubID   = "ipa-local-subid"
+	IpaIDRangeTypeADTrust      = "ipa-ad-trust"
+	IpaIDRangeTypeADTrustPosix = "ipa-ad-trust-posix"
+)
+
+// IpaIDRange represent an ID range of a rhel-idm domain,
+// including the subordinate ID ranges.
+type IpaIDRange struct {
+	gorm.Model
+	IpaID            uint
+	Name             string
+	BaseID           int64
+	RangeSize        int64
+	Type             string
+	BaseRID          *int64 `gorm:"column:base_rid"`
+	SecondaryBaseRID *int64 `gorm:"column:secondary_base_rid"`
+}
+
+// Overlaps check if the POSIX IDs of r and other intersect.
+func (r *IpaIDRange) Overlaps(other *IpaIDRange) bool {
+	return r.BaseID < other.BaseID+other.RangeSize &&
+		other.BaseID < r.BaseID+r.RangeSize
+}
@@ -1,4 +1,4 @@
-package model
+package ipa
 
 import "gorm.io/gorm"
 
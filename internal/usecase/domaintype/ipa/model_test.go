@@ -1,4 +1,4 @@
-package model
+package ipa
 
 import (
 	"testing"
@@ -57,3 +57,34 @@ func TestAfterCreate(t *testing.T) {
 	assert.Equal(t, uint(1), entity.CaCerts[0].IpaID)
 	assert.Equal(t, uint(1), entity.Servers[0].IpaID)
 }
+
+func TestIpaTrustAfterCreate(t *testing.T) {
+	var entity *IpaTrust
+	assert.EqualError(t, entity.AfterCreate(nil), "'AfterCreate' cannot be invoked on nil")
+
+	ipa := &Ipa{
+		Model:  gorm.Model{ID: 1},
+		Trusts: []IpaTrust{{ForestName: "ad.example"}},
+	}
+	require.NoError(t, ipa.AfterCreate(nil))
+	assert.Equal(t, uint(1), ipa.Trusts[0].IpaID)
+
+	entity = &IpaTrust{
+		Model:    gorm.Model{ID: 2},
+		Domains:  []IpaTrustDomain{{Name: "ad.example"}},
+		IDRanges: []IpaTrustIDRange{{Name: "AD.EXAMPLE_id_range"}},
+	}
+	require.NoError(t, entity.AfterCreate(nil))
+	assert.Equal(t, uint(2), entity.Domains[0].IpaTrustID)
+	assert.Equal(t, uint(2), entity.IDRanges[0].IpaTrustID)
+}
+
+func TestIpaIDRangeOverlaps(t *testing.T) {
+	r := &IpaIDRange{BaseID: 1000, RangeSize: 100}
+	assert.True(t, r.Overlaps(&IpaIDRange{BaseID: 1000, RangeSize: 100}))
+	assert.True(t, r.Overlaps(&IpaIDRange{BaseID: 1099, RangeSize: 1}))
+	assert.True(t, r.Overlaps(&IpaIDRange{BaseID: 900, RangeSize: 101}))
+	assert.True(t, r.Overlaps(&IpaIDRange{BaseID: 0, RangeSize: 5000}))
+	assert.False(t, r.Overlaps(&IpaIDRange{BaseID: 1100, RangeSize: 100}))
+	assert.False(t, r.Overlaps(&IpaIDRange{BaseID: 900, RangeSize: 100}))
+}
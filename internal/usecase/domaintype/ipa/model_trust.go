@@ -0,0 +1,55 @@
+package ipa
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// IpaTrust represent a trust between a rhel-idm domain
+// and an AD forest.
+type IpaTrust struct {
+	gorm.Model
+	IpaID      uint
+	ForestName string
+	FlatName   *string
+	SID        string `gorm:"column:sid"`
+	// Direction is one of 'inbound', 'outbound' or 'two-way'.
+	Direction string
+	Domains   []IpaTrustDomain
+	IDRanges  []IpaTrustIDRange
+}
+
+// IpaTrustDomain represent a domain of a trusted AD forest.
+type IpaTrustDomain struct {
+	gorm.Model
+	IpaTrustID uint
+	Name       string
+	FlatName   *string
+	SID        string `gorm:"column:sid"`
+}
+
+// IpaTrustIDRange represent an ID range that maps the
+// identities of a trusted AD forest.
+type IpaTrustIDRange struct {
+	gorm.Model
+	IpaTrustID uint
+	Name       string
+	BaseID     int64
+	RangeSize  int64
+	// Type is one of 'ipa-ad-trust' or 'ipa-ad-trust-posix'.
+	Type string
+}
+
+func (t *IpaTrust) AfterCreate(tx *gorm.DB) (err error) {
+	if t == nil {
+		return fmt.Errorf("'AfterCreate' cannot be invoked on nil")
+	}
+	for idx := range t.Domains {
+		t.Domains[idx].IpaTrustID = t.ID
+	}
+	for idx := range t.IDRanges {
+		t.IDRanges[idx].IpaTrustID = t.ID
+	}
+	return nil
+}
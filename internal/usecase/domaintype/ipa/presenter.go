@@ -0,0 +1,264 @@
+package ipa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"go.openly.dev/pointy"
+)
+
+func (domainType) PresentDomain(domain *model.Domain, output *public.Domain) error {
+	return sharedDomainFillRhelIdm(domain, output)
+}
+
+func (domainType) PresentHostConf(domain *model.Domain, location *model.HostLocation, response *public.HostConfResponse) error {
+	domainIpa := Data(domain)
+	if domainIpa == nil {
+		return internal_errors.NilArgError("domain.TypeData")
+	}
+	// concatenate PEM certs
+	if len(domainIpa.CaCerts) == 0 {
+		return fmt.Errorf("domain '%s' has no CA certificates", *domain.DomainName)
+	}
+	var sb strings.Builder
+	for _, ca_cert := range domainIpa.CaCerts {
+		sb.WriteString(ca_cert.Pem)
+		// ensure PEM blocks are separated by newline
+		if !strings.HasSuffix(ca_cert.Pem, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+
+	// create array of servers with HCC enrollment role
+	var servers []public.HostConfIpaServer
+	for _, ipa_server := range domainIpa.Servers {
+		if ipa_server.HCCEnrollmentServer {
+			servers = append(servers, public.HostConfIpaServer{Fqdn: ipa_server.FQDN, Location: ipa_server.Location})
+		}
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("domain '%s' has no enrollment servers", *domain.DomainName)
+	}
+	servers = model.SelectLocation(servers, location, func(server *public.HostConfIpaServer) *string {
+		return server.Location
+	})
+	response.RhelIdm = &public.HostConfIpa{
+		Cabundle:          sb.String(),
+		EnrollmentServers: servers,
+		RealmName:         *domainIpa.RealmName,
+		// TODO: hard-coded value for testing and demonstration
+		IpaClientInstallArgs: &[]string{"--mkhomedir", "--subid"},
+		AutomountLocation:    pointy.String("default"),
+		Trusts:               hostConfTrusts(domainIpa.Trusts),
+	}
+	return nil
+}
+
+// hostConfTrusts return the external realms that an enrolled host
+// can expect from each trusted forest, or nil if there is no trust.
+func hostConfTrusts(trusts []IpaTrust) *[]public.HostConfIpaTrust {
+	if len(trusts) == 0 {
+		return nil
+	}
+	output := make([]public.HostConfIpaTrust, len(trusts))
+	for i := range trusts {
+		output[i].ForestName = trusts[i].ForestName
+		output[i].Direction = public.TrustDirection(trusts[i].Direction)
+		output[i].Realms = make([]public.RealmName, len(trusts[i].Domains))
+		for j := range trusts[i].Domains {
+			output[i].Realms[j] = strings.ToUpper(trusts[i].Domains[j].Name)
+		}
+	}
+	return &output
+}
+
+func sharedDomainFillRhelIdm(
+	domain *model.Domain,
+	output *public.Domain,
+) (err error) {
+	domainIpa := Data(domain)
+	if domain.Type != nil && *domain.Type != TypeID {
+		return fmt.Errorf(
+			"'domain.Type' is not '%s'",
+			TypeName,
+		)
+	}
+	if domainIpa == nil {
+		return internal_errors.NilArgError("domain.TypeData")
+	}
+	if output == nil {
+		panic("'output' is nil")
+	}
+	output.RhelIdm = &public.DomainIpa{}
+	if domainIpa.RealmName != nil {
+		output.RhelIdm.RealmName = *domainIpa.RealmName
+	}
+
+	if domainIpa.RealmDomains != nil {
+		output.RhelIdm.RealmDomains = append(
+			[]string{},
+			domainIpa.RealmDomains...)
+	} else {
+		output.RhelIdm.RealmDomains = []string{}
+	}
+
+	fillRhelIdmCerts(output, domain)
+	fillRhelIdmServers(output, domain)
+	fillRhelIdmLocations(output, domain)
+	fillRhelIdmTrusts(output, domain)
+	fillRhelIdmIDRanges(output, domain)
+
+	return nil
+}
+
+func fillRhelIdmLocations(
+	target *public.Domain,
+	source *model.Domain,
+) {
+	sourceIpa := Data(source)
+	if target == nil || target.RhelIdm == nil {
+		panic("'target' or 'target.RhelIdm' are nil")
+	}
+	if source == nil || sourceIpa == nil {
+		panic("'source' or 'source.TypeData' are nil")
+	}
+	target.RhelIdm.Locations = make(
+		[]public.Location,
+		len(sourceIpa.Locations),
+	)
+	for idx := range sourceIpa.Locations {
+		target.RhelIdm.Locations[idx].Name = sourceIpa.Locations[idx].Name
+		target.RhelIdm.Locations[idx].Description = sourceIpa.Locations[idx].Description
+	}
+}
+
+func fillRhelIdmServers(
+	target *public.Domain,
+	source *model.Domain,
+) {
+	sourceIpa := Data(source)
+	if target == nil || source == nil {
+		return
+	}
+	if target.RhelIdm == nil || sourceIpa == nil {
+		return
+	}
+	target.RhelIdm.Servers = make(
+		[]public.DomainIpaServer,
+		len(sourceIpa.Servers),
+	)
+	for i := range sourceIpa.Servers {
+		target.RhelIdm.Servers[i].Fqdn =
+			sourceIpa.Servers[i].FQDN
+		var rhsmID *uuid.UUID = nil
+		if sourceIpa.Servers[i].RHSMId != nil {
+			rhsmID = &uuid.UUID{}
+			*rhsmID = uuid.MustParse(*sourceIpa.Servers[i].RHSMId)
+		}
+		target.RhelIdm.Servers[i].SubscriptionManagerId = rhsmID
+		target.RhelIdm.Servers[i].Location =
+			sourceIpa.Servers[i].Location
+		target.RhelIdm.Servers[i].CaServer =
+			sourceIpa.Servers[i].CaServer
+		target.RhelIdm.Servers[i].HccEnrollmentServer =
+			sourceIpa.Servers[i].HCCEnrollmentServer
+		target.RhelIdm.Servers[i].HccUpdateServer =
+			sourceIpa.Servers[i].HCCUpdateServer
+		target.RhelIdm.Servers[i].PkinitServer =
+			sourceIpa.Servers[i].PKInitServer
+	}
+}
+
+func fillRhelIdmCerts(
+	output *public.Domain,
+	domain *model.Domain,
+) {
+	domainIpa := Data(domain)
+	if output == nil || domain == nil || output.RhelIdm == nil || domainIpa == nil {
+		return
+	}
+	output.RhelIdm.CaCerts = make(
+		[]public.Certificate,
+		len(domainIpa.CaCerts),
+	)
+	for i := range domainIpa.CaCerts {
+		output.RhelIdm.CaCerts[i].Nickname =
+			domainIpa.CaCerts[i].Nickname
+		output.RhelIdm.CaCerts[i].Issuer =
+			domainIpa.CaCerts[i].Issuer
+		output.RhelIdm.CaCerts[i].NotAfter =
+			domainIpa.CaCerts[i].NotAfter
+		output.RhelIdm.CaCerts[i].NotBefore =
+			domainIpa.CaCerts[i].NotBefore
+		output.RhelIdm.CaCerts[i].SerialNumber =
+			domainIpa.CaCerts[i].SerialNumber
+		output.RhelIdm.CaCerts[i].Subject =
+			domainIpa.CaCerts[i].Subject
+		output.RhelIdm.CaCerts[i].Pem =
+			domainIpa.CaCerts[i].Pem
+	}
+}
+
+func fillRhelIdmTrusts(
+	output *public.Domain,
+	domain *model.Domain,
+) {
+	domainIpa := Data(domain)
+	if output == nil || domain == nil || output.RhelIdm == nil || domainIpa == nil {
+		return
+	}
+	if len(domainIpa.Trusts) == 0 {
+		output.RhelIdm.Trusts = nil
+		return
+	}
+	trusts := make([]public.DomainIpaTrust, len(domainIpa.Trusts))
+	for i := range domainIpa.Trusts {
+		source := &domainIpa.Trusts[i]
+		trusts[i].ForestName = source.ForestName
+		trusts[i].FlatName = source.FlatName
+		trusts[i].Sid = source.SID
+		trusts[i].Direction = public.TrustDirection(source.Direction)
+		trusts[i].Domains = make([]public.DomainIpaTrustDomain, len(source.Domains))
+		for j := range source.Domains {
+			trusts[i].Domains[j].Name = source.Domains[j].Name
+			trusts[i].Domains[j].FlatName = source.Domains[j].FlatName
+			trusts[i].Domains[j].Sid = source.Domains[j].SID
+		}
+		trusts[i].IdRanges = make([]public.DomainIpaTrustIdRange, len(source.IDRanges))
+		for j := range source.IDRanges {
+			trusts[i].IdRanges[j].Name = source.IDRanges[j].Name
+			trusts[i].IdRanges[j].BaseId = source.IDRanges[j].BaseID
+			trusts[i].IdRanges[j].RangeSize = source.IDRanges[j].RangeSize
+			trusts[i].IdRanges[j].Type = public.IdRangeType(source.IDRanges[j].Type)
+		}
+	}
+	output.RhelIdm.Trusts = &trusts
+}
+
+func fillRhelIdmIDRanges(
+	output *public.Domain,
+	domain *model.Domain,
+) {
+	domainIpa := Data(domain)
+	if output == nil || domain == nil || output.RhelIdm == nil || domainIpa == nil {
+		return
+	}
+	if len(domainIpa.IDRanges) == 0 {
+		output.RhelIdm.IdRanges = nil
+		return
+	}
+	idRanges := make([]public.DomainIpaIdRange, len(domainIpa.IDRanges))
+	for i, idRange := range domainIpa.IDRanges {
+		idRanges[i].Name = idRange.Name
+		idRanges[i].BaseId = idRange.BaseID
+		idRanges[i].RangeSize = idRange.RangeSize
+		idRanges[i].Type = public.IdRangeType(idRange.Type)
+		idRanges[i].BaseRid = idRange.BaseRID
+		idRanges[i].SecondaryBaseRid = idRange.SecondaryBaseRID
+	}
+	output.RhelIdm.IdRanges = &idRanges
+}
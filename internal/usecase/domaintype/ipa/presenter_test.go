@@ -0,0 +1,514 @@
+package ipa
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+func TestGuardsRegisterIpa(t *testing.T) {
+	var (
+		err error
+	)
+	assert.Panics(t, func() {
+		err = sharedDomainFillRhelIdm(nil, nil)
+	})
+
+	domain := &model.Domain{}
+	domain.Type = pointy.Uint(999)
+	err = sharedDomainFillRhelIdm(domain, nil)
+	assert.EqualError(t, err, fmt.Sprintf("'domain.Type' is not '%s'", TypeName))
+
+	*domain.Type = TypeID
+	err = sharedDomainFillRhelIdm(domain, nil)
+	assert.EqualError(t, err, "code=500, message='domain.TypeData' cannot be nil")
+
+	domain.TypeData = &Ipa{}
+	assert.Panics(t, func() {
+		err = sharedDomainFillRhelIdm(domain, nil)
+	})
+
+	output := &public.RegisterDomainResponse{}
+	err = sharedDomainFillRhelIdm(domain, output)
+	assert.NoError(t, err)
+
+	Data(domain).CaCerts = []IpaCert{}
+	assert.NotPanics(t, func() {
+		err = sharedDomainFillRhelIdm(domain, output)
+	})
+
+	output.DomainType = public.RhelIdm
+	output.RhelIdm = &public.DomainIpa{}
+	err = sharedDomainFillRhelIdm(domain, output)
+	assert.NoError(t, err)
+}
+
+func TestFillRhelIdmCerts(t *testing.T) {
+
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(nil, nil)
+	})
+
+	output := public.Domain{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(&output, nil)
+	})
+
+	domain := model.Domain{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(&output, &domain)
+	})
+
+	output.RhelIdm = &public.DomainIpa{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(&output, &domain)
+	})
+}
+
+func TestFillRhelIdmLocationsError(t *testing.T) {
+
+	assert.Panics(t, func() {
+		fillRhelIdmLocations(nil, nil)
+	}, "'target' or 'target.RhelIdm' are nil")
+
+	output := public.Domain{}
+	assert.Panics(t, func() {
+		fillRhelIdmLocations(&output, nil)
+	}, "'target' or 'target.RhelIdm' are nil")
+
+	domain := model.Domain{}
+	assert.Panics(t, func() {
+		fillRhelIdmLocations(&output, &domain)
+	}, "'target' or 'target.RhelIdm' are nil")
+
+}
+
+func TestFillRhelIdmLocations(t *testing.T) {
+	type TestCaseGiven struct {
+		To   *public.Domain
+		From *model.Domain
+	}
+	type TestCaseExpected struct {
+		Err error
+		To  *public.Domain
+	}
+	type TestCase struct {
+		Name     string
+		Given    TestCaseGiven
+		Expected TestCaseExpected
+	}
+	testSubscriptionManagerID := &uuid.UUID{}
+	*testSubscriptionManagerID = uuid.MustParse("547ce70c-9eb5-4783-a619-086aa26f88e5")
+	testCases := []TestCase{
+		{
+			Name: "Full success copy",
+			Given: TestCaseGiven{
+				To: &public.Domain{
+					RhelIdm: &public.DomainIpa{},
+				},
+				From: &model.Domain{
+					Type: pointy.Uint(TypeID),
+					TypeData: &Ipa{
+						Locations: []IpaLocation{
+							{
+								Name:        "boston",
+								Description: pointy.String("Boston data center"),
+							},
+						},
+					},
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				To: &public.Domain{
+					DomainType: public.RhelIdm,
+					RhelIdm: &public.DomainIpa{
+						Locations: []public.Location{
+							{
+								Name:        "boston",
+								Description: pointy.String("Boston data center"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		// I instantiate directly because the public methods
+		// are not part of the interface
+		if testCase.Expected.Err != nil {
+			assert.Panics(t, func() {
+				fillRhelIdmLocations(testCase.Given.To, testCase.Given.From)
+			})
+		} else {
+			assert.NotPanics(t, func() {
+				fillRhelIdmLocations(testCase.Given.To, testCase.Given.From)
+			})
+			require.NotNil(t, testCase.Expected.To)
+			require.NotNil(t, testCase.Expected.To.RhelIdm)
+			require.NotNil(t, testCase.Expected.To.RhelIdm.Locations)
+			require.NotNil(t, testCase.Given.To)
+			require.NotNil(t, testCase.Given.To.RhelIdm)
+			require.NotNil(t, testCase.Given.To.RhelIdm.Locations)
+			assert.Equal(t, testCase.Expected.To.RhelIdm.Locations, testCase.Given.To.RhelIdm.Locations)
+		}
+	}
+}
+
+func TestFillRhelIdmServersError(t *testing.T) {
+
+	assert.NotPanics(t, func() {
+		fillRhelIdmServers(nil, nil)
+	})
+
+	output := public.Domain{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmServers(&output, nil)
+	})
+
+	domain := model.Domain{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmServers(&output, &domain)
+	})
+}
+
+func TestFillRhelIdmServers(t *testing.T) {
+	type TestCaseGiven struct {
+		To   *public.Domain
+		From *model.Domain
+	}
+	type TestCaseExpected struct {
+		Err error
+		To  *public.Domain
+	}
+	type TestCase struct {
+		Name     string
+		Given    TestCaseGiven
+		Expected TestCaseExpected
+	}
+	testSubscriptionManagerID := &uuid.UUID{}
+	*testSubscriptionManagerID = uuid.MustParse("547ce70c-9eb5-4783-a619-086aa26f88e5")
+	testCases := []TestCase{
+		{
+			Name: "Full success copy",
+			Given: TestCaseGiven{
+				To: &public.Domain{
+					RhelIdm: &public.DomainIpa{},
+				},
+				From: &model.Domain{
+					Type: pointy.Uint(TypeID),
+					TypeData: &Ipa{
+						Servers: []IpaServer{
+							{
+								FQDN:                "server1.mydomain.example",
+								RHSMId:              pointy.String(testSubscriptionManagerID.String()),
+								CaServer:            true,
+								HCCEnrollmentServer: true,
+								HCCUpdateServer:     true,
+								PKInitServer:        true,
+							},
+						},
+					},
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				To: &public.Domain{
+					DomainType: public.RhelIdm,
+					RhelIdm: &public.DomainIpa{
+						Servers: []public.DomainIpaServer{
+							{
+								Fqdn:                  "server1.mydomain.example",
+								SubscriptionManagerId: testSubscriptionManagerID,
+								CaServer:              true,
+								HccEnrollmentServer:   true,
+								HccUpdateServer:       true,
+								PkinitServer:          true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		// I instantiate directly because the public methods
+		// are not part of the interface
+		if testCase.Expected.Err != nil {
+			// assert.EqualError(t, err, testCase.Expected.Err.Error())
+			assert.Panics(t, func() {
+				fillRhelIdmServers(testCase.Given.To, testCase.Given.From)
+			})
+		} else {
+			// assert.NoError(t, err)
+			assert.NotPanics(t, func() {
+				fillRhelIdmServers(testCase.Given.To, testCase.Given.From)
+			})
+			require.NotNil(t, testCase.Expected.To)
+			require.NotNil(t, testCase.Expected.To.RhelIdm)
+			require.NotNil(t, testCase.Expected.To.RhelIdm.Servers)
+			require.NotNil(t, testCase.Given.To)
+			require.NotNil(t, testCase.Given.To.RhelIdm)
+			require.NotNil(t, testCase.Given.To.RhelIdm.Servers)
+			assert.Equal(t, testCase.Expected.To.RhelIdm.Servers, testCase.Given.To.RhelIdm.Servers)
+		}
+	}
+}
+
+func TestFillRhelIdmCertsPanics(t *testing.T) {
+	var err error
+
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(nil, nil)
+	})
+
+	domain := &model.Domain{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(nil, domain)
+	})
+
+	domain.TypeData = &Ipa{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(nil, domain)
+	})
+
+	output := &public.Domain{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(output, domain)
+	})
+
+	Data(domain).CaCerts = []IpaCert{}
+	assert.NotPanics(t, func() {
+		fillRhelIdmCerts(output, domain)
+	})
+
+	// Minimal success
+	output.RhelIdm = &public.DomainIpa{}
+	fillRhelIdmCerts(output, domain)
+	assert.NoError(t, err)
+}
+
+func TestFillRhelIdmCertsCases(t *testing.T) {
+	NotBefore := time.Now()
+	NotAfter := NotBefore.Add(time.Hour * 24)
+	type TestCaseGiven struct {
+		To   *public.Domain
+		From *model.Domain
+	}
+	type TestCaseExpected struct {
+		Err error
+		To  *public.Domain
+	}
+	type TestCase struct {
+		Name     string
+		Given    TestCaseGiven
+		Expected TestCaseExpected
+	}
+	testCases := []TestCase{
+		{
+			Name: "Full success copy",
+			Given: TestCaseGiven{
+				To: &public.Domain{
+					DomainType: public.RhelIdm,
+					RhelIdm:    &public.DomainIpa{},
+				},
+				From: &model.Domain{
+					Type: pointy.Uint(TypeID),
+					TypeData: &Ipa{
+						CaCerts: []IpaCert{
+							{
+								Nickname:     "MYDOMAIN.EXAMPLE.IPA CA",
+								Issuer:       "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
+								NotBefore:    NotBefore,
+								NotAfter:     NotAfter,
+								SerialNumber: "1",
+								Subject:      "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
+								Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
+							},
+						},
+					},
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				To: &public.Domain{
+					DomainType: public.RhelIdm,
+					RhelIdm: &public.DomainIpa{
+						CaCerts: []public.Certificate{
+							{
+								Nickname:     "MYDOMAIN.EXAMPLE.IPA CA",
+								Issuer:       "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
+								NotBefore:    NotBefore,
+								NotAfter:     NotAfter,
+								SerialNumber: "1",
+								Subject:      "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
+								Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Log(testCase.Name)
+		// Instantiate directly to access the private methods
+		if testCase.Expected.Err != nil {
+			// assert.EqualError(t, err, testCase.Expected.Err.Error())
+			assert.Panics(t, func() {
+				fillRhelIdmCerts(testCase.Given.To, testCase.Given.From)
+			})
+		} else {
+			// assert.NoError(t, err)
+			assert.NotPanics(t, func() {
+				fillRhelIdmCerts(testCase.Given.To, testCase.Given.From)
+			})
+			require.NotNil(t, testCase.Expected.To)
+			require.NotNil(t, testCase.Expected.To.RhelIdm)
+			require.NotNil(t, testCase.Expected.To.RhelIdm.CaCerts)
+			require.NotNil(t, testCase.Given.To)
+			require.NotNil(t, testCase.Given.To.RhelIdm)
+			require.NotNil(t, testCase.Given.To.RhelIdm.CaCerts)
+			assert.Equal(t, testCase.Expected.To.RhelIdm.CaCerts, testCase.Given.To.RhelIdm.CaCerts)
+		}
+	}
+}
+
+func TestFillRhelIdmTrusts(t *testing.T) {
+	domain := &model.Domain{TypeData: &Ipa{}}
+	output := &public.Domain{RhelIdm: &public.DomainIpa{}}
+
+	assert.NotPanics(t, func() {
+		fillRhelIdmTrusts(nil, nil)
+		fillRhelIdmTrusts(&public.Domain{}, domain)
+	})
+
+	fillRhelIdmTrusts(output, domain)
+	assert.Nil(t, output.RhelIdm.Trusts)
+
+	Data(domain).Trusts = []IpaTrust{
+		{
+			ForestName: "ad.example",
+			FlatName:   pointy.String("AD"),
+			SID:        "S-1-5-21-1-2-3",
+			Direction:  "inbound",
+			Domains: []IpaTrustDomain{
+				{Name: "ad.example", FlatName: pointy.String("AD"), SID: "S-1-5-21-1-2-3"},
+				{Name: "child.ad.example", SID: "S-1-5-21-4-5-6"},
+			},
+			IDRanges: []IpaTrustIDRange{
+				{Name: "AD.EXAMPLE_id_range", BaseID: 1000000000, RangeSize: 200000, Type: "ipa-ad-trust"},
+			},
+		},
+	}
+	fillRhelIdmTrusts(output, domain)
+	require.NotNil(t, output.RhelIdm.Trusts)
+	assert.Equal(t, []public.DomainIpaTrust{
+		{
+			ForestName: "ad.example",
+			FlatName:   pointy.String("AD"),
+			Sid:        "S-1-5-21-1-2-3",
+			Direction:  public.Inbound,
+			Domains: []public.DomainIpaTrustDomain{
+				{Name: "ad.example", FlatName: pointy.String("AD"), Sid: "S-1-5-21-1-2-3"},
+				{Name: "child.ad.example", Sid: "S-1-5-21-4-5-6"},
+			},
+			IdRanges: []public.DomainIpaTrustIdRange{
+				{Name: "AD.EXAMPLE_id_range", BaseId: 1000000000, RangeSize: 200000, Type: public.IpaAdTrust},
+			},
+		},
+	}, *output.RhelIdm.Trusts)
+
+	trusts := hostConfTrusts(Data(domain).Trusts)
+	require.NotNil(t, trusts)
+	assert.Equal(t, []public.HostConfIpaTrust{
+		{
+			ForestName: "ad.example",
+			Direction:  public.Inbound,
+			Realms:     []string{"AD.EXAMPLE", "CHILD.AD.EXAMPLE"},
+		},
+	}, *trusts)
+	assert.Nil(t, hostConfTrusts(nil))
+}
+
+func TestFillRhelIdmIDRanges(t *testing.T) {
+	domain := &model.Domain{TypeData: &Ipa{}}
+	output := &public.Domain{RhelIdm: &public.DomainIpa{}}
+
+	assert.NotPanics(t, func() {
+		fillRhelIdmIDRanges(nil, nil)
+		fillRhelIdmIDRanges(&public.Domain{}, domain)
+	})
+
+	fillRhelIdmIDRanges(output, domain)
+	assert.Nil(t, output.RhelIdm.IdRanges)
+
+	Data(domain).IDRanges = []IpaIDRange{
+		{Name: "local", BaseID: 1000, RangeSize: 200000, Type: IpaIDRangeTypeLocal, BaseRID: pointy.Int64(1000)},
+	}
+	fillRhelIdmIDRanges(output, domain)
+	require.NotNil(t, output.RhelIdm.IdRanges)
+	assert.Equal(t, []public.DomainIpaIdRange{
+		{Name: "local", BaseId: 1000, RangeSize: 200000, Type: public.IpaLocal, BaseRid: pointy.Int64(1000)},
+	}, *output.RhelIdm.IdRanges)
+}
+
+func TestPresentHostConfLocation(t *testing.T) {
+	domain := &model.Domain{
+		DomainName: pointy.String("mydomain.example"),
+		Type:       pointy.Uint(TypeID),
+		TypeData: &Ipa{
+			RealmName: pointy.String("MYDOMAIN.EXAMPLE"),
+			CaCerts:   []IpaCert{{Pem: "-----BEGIN CERTIFICATE-----\n"}},
+			Servers: []IpaServer{
+				{FQDN: "server1.mydomain.example", Location: pointy.String("alpha"), HCCEnrollmentServer: true},
+				{FQDN: "server2.mydomain.example", HCCEnrollmentServer: true},
+				{FQDN: "server3.mydomain.example", Location: pointy.String("beta"), HCCEnrollmentServer: true},
+				{FQDN: "server4.mydomain.example", Location: pointy.String("beta"), HCCEnrollmentServer: false},
+				{FQDN: "server5.mydomain.example", Location: pointy.String("beta"), HCCEnrollmentServer: true},
+			},
+		},
+	}
+	h := New()
+
+	// Without location the stored order is kept
+	response := &public.HostConfResponse{}
+	require.NoError(t, h.PresentHostConf(domain, nil, response))
+	require.NotNil(t, response.RhelIdm)
+	assert.Equal(t, []public.HostConfIpaServer{
+		{Fqdn: "server1.mydomain.example", Location: pointy.String("alpha")},
+		{Fqdn: "server2.mydomain.example"},
+		{Fqdn: "server3.mydomain.example", Location: pointy.String("beta")},
+		{Fqdn: "server5.mydomain.example", Location: pointy.String("beta")},
+	}, response.RhelIdm.EnrollmentServers)
+
+	// Servers in the location come first, the rest are the fallback
+	response = &public.HostConfResponse{}
+	require.NoError(t, h.PresentHostConf(domain, &model.HostLocation{Name: "beta"}, response))
+	require.NotNil(t, response.RhelIdm)
+	assert.Equal(t, []public.HostConfIpaServer{
+		{Fqdn: "server3.mydomain.example", Location: pointy.String("beta")},
+		{Fqdn: "server5.mydomain.example", Location: pointy.String("beta")},
+		{Fqdn: "server1.mydomain.example", Location: pointy.String("alpha")},
+		{Fqdn: "server2.mydomain.example"},
+	}, response.RhelIdm.EnrollmentServers)
+
+	// Restricted to the location, only its servers are listed
+	response = &public.HostConfResponse{}
+	require.NoError(t, h.PresentHostConf(domain, &model.HostLocation{Name: "beta", Restrict: true}, response))
+	require.NotNil(t, response.RhelIdm)
+	assert.Equal(t, []public.HostConfIpaServer{
+		{Fqdn: "server3.mydomain.example", Location: pointy.String("beta")},
+		{Fqdn: "server5.mydomain.example", Location: pointy.String("beta")},
+	}, response.RhelIdm.EnrollmentServers)
+}
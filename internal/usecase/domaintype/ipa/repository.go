@@ -0,0 +1,212 @@
+package ipa
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (domainType) Create(log *slog.Logger, db *gorm.DB, domain *model.Domain) error {
+	data := Data(domain)
+	if data == nil {
+		return internal_errors.NilArgError("TypeData")
+	}
+	if err := ensureIDRangesDoNotOverlap(log, db, domain); err != nil {
+		return err
+	}
+	return createIpaDomain(log, db, domain.ID, data)
+}
+
+func (domainType) Update(log *slog.Logger, db *gorm.DB, domain *model.Domain) error {
+	data := Data(domain)
+	if data == nil {
+		return internal_errors.NilArgError("TypeData")
+	}
+	if err := ensureIDRangesDoNotOverlap(log, db, domain); err != nil {
+		return err
+	}
+	data.ID = domain.ID
+	return updateIpaDomain(log, db, data)
+}
+
+func createIpaDomain(
+	log *slog.Logger,
+	db *gorm.DB,
+	domainID uint,
+	data *Ipa,
+) (err error) {
+	if data == nil {
+		err = internal_errors.NilArgError("data")
+		log.Error(err.Error())
+		return err
+	}
+	data.Model.ID = domainID
+	if err = db.Omit(clause.Associations).Create(data).Error; err != nil {
+		log.Error("failed to create the ipas record")
+		return err
+	}
+	for idx := range data.CaCerts {
+		data.CaCerts[idx].Model.ID = 0
+		data.CaCerts[idx].IpaID = domainID
+		if err = db.Create(&data.CaCerts[idx]).Error; err != nil {
+			log.Error("failed to create the ipa_certs record",
+				slog.String("subject", data.CaCerts[idx].Subject),
+				slog.String("issuer", data.CaCerts[idx].Issuer),
+			)
+			return err
+		}
+	}
+	for idx := range data.Servers {
+		data.Servers[idx].Model.ID = 0
+		data.Servers[idx].IpaID = domainID
+		if err = db.Create(&data.Servers[idx]).Error; err != nil {
+			log.Error("failed to create the ipa_servers record",
+				slog.String("fqdn", data.Servers[idx].FQDN),
+			)
+			return err
+		}
+	}
+	for idx := range data.Locations {
+		data.Locations[idx].Model.ID = 0
+		data.Locations[idx].IpaID = domainID
+		if err = db.Create(&data.Locations[idx]).Error; err != nil {
+			log.Error("failed to create the ipa_locations record",
+				slog.String("name", data.Locations[idx].Name),
+			)
+			return err
+		}
+	}
+	if err = createIpaTrusts(log, db, domainID, data.Trusts); err != nil {
+		return err
+	}
+	return createIpaIDRanges(log, db, domainID, data.IDRanges)
+}
+
+func updateIpaDomain(
+	log *slog.Logger,
+	db *gorm.DB,
+	dataIPA *Ipa,
+) (err error) {
+	if log == nil {
+		err = internal_errors.NilArgError("log")
+		slog.Default().Error(err.Error())
+		return err
+	}
+	if db == nil {
+		err = internal_errors.NilArgError("db")
+		log.Error(err.Error())
+		return err
+	}
+	if dataIPA == nil {
+		err = internal_errors.NilArgError("dataIPA")
+		log.Error(err.Error())
+		return err
+	}
+	// This check avoid the Delete operation could
+	// delete all the records, to prevent that future
+	// changes could evoke not wished behaviors
+	if dataIPA.Model.ID == 0 {
+		err = fmt.Errorf("dataIPA.Model.ID cannot be 0")
+		log.Error(err.Error())
+		return err
+	}
+	domainID := dataIPA.Model.ID
+	if err = db.Unscoped().
+		Delete(dataIPA).Error; err != nil {
+		log.Error("updating ipa domain when deleting old record")
+		return err
+	}
+
+	// Being sure the same ID is used, as the relationship
+	// to domains entiry is 1-1
+	dataIPA.ID = domainID
+	if err = db.Omit(clause.Associations).
+		Create(dataIPA).
+		Error; err != nil {
+		log.Error("updating ipa domain when creating new ipa record")
+		return err
+	}
+
+	// CaCerts
+	for i := range dataIPA.CaCerts {
+		dataIPA.CaCerts[i].Model.ID = 0
+		dataIPA.CaCerts[i].IpaID = dataIPA.ID
+		if err = db.Create(&dataIPA.CaCerts[i]).Error; err != nil {
+			log.Error("updating ipa domain when creating new ipa certificate record")
+			return err
+		}
+	}
+
+	// Servers
+	for i := range dataIPA.Servers {
+		dataIPA.Servers[i].Model.ID = 0
+		dataIPA.Servers[i].IpaID = dataIPA.ID
+		if err = db.Create(&dataIPA.Servers[i]).Error; err != nil {
+			log.Error("updating ipa domain when creating new ipa server record")
+			return err
+		}
+	}
+
+	// Locations
+	for i := range dataIPA.Locations {
+		dataIPA.Locations[i].Model.ID = 0
+		dataIPA.Locations[i].IpaID = dataIPA.ID
+		if err = db.Create(&dataIPA.Locations[i]).Error; err != nil {
+			log.Error("updating ipa domain when creating new ipa location record")
+			return err
+		}
+	}
+
+	// Trusts
+	if err = createIpaTrusts(log, db, dataIPA.ID, dataIPA.Trusts); err != nil {
+		return err
+	}
+
+	// ID ranges
+	return createIpaIDRanges(log, db, dataIPA.ID, dataIPA.IDRanges)
+}
+
+// createIpaTrusts creates the ipa_trusts records and their
+// trusted domains and ID ranges for the ipas record ipaID.
+func createIpaTrusts(
+	log *slog.Logger,
+	db *gorm.DB,
+	ipaID uint,
+	trusts []IpaTrust,
+) (err error) {
+	for i := range trusts {
+		trusts[i].Model.ID = 0
+		trusts[i].IpaID = ipaID
+		if err = db.Omit(clause.Associations).Create(&trusts[i]).Error; err != nil {
+			log.Error("failed to create the ipa_trusts record",
+				slog.String("forest_name", trusts[i].ForestName),
+			)
+			return err
+		}
+		for j := range trusts[i].Domains {
+			trusts[i].Domains[j].Model.ID = 0
+			trusts[i].Domains[j].IpaTrustID = trusts[i].ID
+			if err = db.Create(&trusts[i].Domains[j]).Error; err != nil {
+				log.Error("failed to create the ipa_trust_domains record",
+					slog.String("name", trusts[i].Domains[j].Name),
+				)
+				return err
+			}
+		}
+		for j := range trusts[i].IDRanges {
+			trusts[i].IDRanges[j].Model.ID = 0
+			trusts[i].IDRanges[j].IpaTrustID = trusts[i].ID
+			if err = db.Create(&trusts[i].IDRanges[j]).Error; err != nil {
+				log.Error("failed to create the ipa_trust_id_ranges record",
+					slog.String("name", trusts[i].IDRanges[j].Name),
+				)
+				return err
+			}
+		}
+	}
+	return nil
+}
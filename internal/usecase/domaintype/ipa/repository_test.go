@@ -0,0 +1,247 @@
+package ipa_test
+
+// https://pkg.go.dev/github.com/stretchr/testify/suite
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	test_sql "github.com/podengo-project/idmsvc-backend/internal/test/sql"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type RepositorySuite struct {
+	suite.Suite
+	DB        *gorm.DB
+	LogBuffer bytes.Buffer
+	Log       *slog.Logger
+	mock      sqlmock.Sqlmock
+}
+
+// https://pkg.go.dev/github.com/stretchr/testify/suite#SetupTestSuite
+func (s *RepositorySuite) SetupTest() {
+	var err error
+	s.mock, s.DB, err = test.NewSqlMock(&gorm.Session{
+		SkipHooks: true,
+	})
+	if err != nil {
+		s.Suite.FailNow("Error calling gorm.Open: %s", err.Error())
+		return
+	}
+	s.LogBuffer.Reset()
+	s.Log = slog.New(slog.NewTextHandler(&s.LogBuffer, nil))
+}
+
+func (s *RepositorySuite) TestCreateIpaDomain() {
+	t := s.Suite.T()
+	domainID := uint(1)
+	data := test.BuildDomainModel(test.OrgId, domainID)
+	var (
+		err         error
+		expectedErr error
+	)
+
+	// Check nil
+	expectedErr = fmt.Errorf("code=500, message='data' cannot be nil")
+	err = ipa.CreateIpaDomain(s.Log, s.DB, domainID, nil)
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipas"
+	expectedErr = fmt.Errorf(`error at INSERT INTO "ipas"`)
+	test_sql.CreateIpaDomain(1, s.mock, expectedErr, domainID, ipa.Data(data))
+	err = ipa.CreateIpaDomain(s.Log, s.DB, domainID, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipa_certs"
+	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_certs"`)
+	test_sql.CreateIpaDomain(2, s.mock, expectedErr, domainID, ipa.Data(data))
+	err = ipa.CreateIpaDomain(s.Log, s.DB, domainID, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipa_servers"
+	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_servers"`)
+	test_sql.CreateIpaDomain(3, s.mock, expectedErr, domainID, ipa.Data(data))
+	err = ipa.CreateIpaDomain(s.Log, s.DB, domainID, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipa_locations"
+	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_locations"`)
+	test_sql.CreateIpaDomain(4, s.mock, expectedErr, domainID, ipa.Data(data))
+	err = ipa.CreateIpaDomain(s.Log, s.DB, domainID, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Success scenario
+	expectedErr = nil
+	test_sql.CreateIpaDomain(4, s.mock, nil, domainID, ipa.Data(data))
+	err = ipa.CreateIpaDomain(s.Log, s.DB, domainID, ipa.Data(data))
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *RepositorySuite) TestUpdateIpaDomain() {
+	var (
+		err         error
+		expectedErr error
+	)
+	t := s.Suite.T()
+	orgID := "11111"
+	domainID := uint(1)
+	data := test.BuildDomainModel(orgID, domainID)
+
+	// Wrong arguments: log is nil
+	expectedErr = internal_errors.NilArgError("log")
+	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
+	err = ipa.UpdateIpaDomain(nil, nil, nil)
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Wrong arguments: db is nil
+	expectedErr = internal_errors.NilArgError("db")
+	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
+	err = ipa.UpdateIpaDomain(s.Log, nil, nil)
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Wrong arguments: dataIPA is nil
+	expectedErr = internal_errors.NilArgError("dataIPA")
+	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, nil)
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Wrong arguments: Ipa.ID is 0 when trying to update ipa information
+	expectedErr = fmt.Errorf("dataIPA.Model.ID cannot be 0")
+	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
+	ipa.Data(data).Model.ID = 0
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error at DELETE FROM 'ipas'
+	expectedErr = fmt.Errorf("database error at DELETE FROM 'ipas'")
+	test_sql.UpdateIpaDomain(1, s.mock, expectedErr, domainID, data)
+	ipa.Data(data).Model.ID = domainID
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error at INSERT INTO 'ipas'
+	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipas'")
+	test_sql.UpdateIpaDomain(2, s.mock, expectedErr, domainID, data)
+	ipa.Data(data).Model.ID = domainID
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error at INSERT INTO 'ipa_certs'
+	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipa_certs'")
+	test_sql.UpdateIpaDomain(3, s.mock, expectedErr, domainID, data)
+	ipa.Data(data).Model.ID = domainID
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error at INSERT INTO 'ipa_servers'
+	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipa_servers'")
+	test_sql.UpdateIpaDomain(4, s.mock, expectedErr, domainID, data)
+	ipa.Data(data).Model.ID = domainID
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error at INSERT INTO 'ipa_locations'
+	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipa_locations'")
+	test_sql.UpdateIpaDomain(5, s.mock, expectedErr, domainID, data)
+	ipa.Data(data).Model.ID = domainID
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.EqualError(t, err, expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Success scenario
+	expectedErr = nil
+	test_sql.UpdateIpaDomain(5, s.mock, expectedErr, domainID, data)
+	err = ipa.UpdateIpaDomain(s.Log, s.DB, ipa.Data(data))
+	require.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+}
+
+func TestRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RepositorySuite))
+}
+
+func (s *RepositorySuite) TestCreateIpaTrusts() {
+	t := s.Suite.T()
+	ipaID := uint(1)
+	trusts := []ipa.IpaTrust{
+		{
+			ForestName: "ad.example",
+			SID:        "S-1-5-21-1-2-3",
+			Direction:  "two-way",
+			Domains: []ipa.IpaTrustDomain{
+				{Name: "ad.example", SID: "S-1-5-21-1-2-3"},
+			},
+			IDRanges: []ipa.IpaTrustIDRange{
+				{Name: "AD.EXAMPLE_id_range", BaseID: 1000000000, RangeSize: 200000, Type: "ipa-ad-trust"},
+			},
+		},
+	}
+	expectInsert := func(table string, id int, err error) {
+		expectQuery := s.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "` + table + `"`))
+		if err != nil {
+			expectQuery.WillReturnError(err)
+			return
+		}
+		expectQuery.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	}
+
+	// No trusts
+	require.NoError(t, ipa.CreateIpaTrusts(s.Log, s.DB, ipaID, nil))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipa_trusts"
+	expectedErr := fmt.Errorf(`error at INSERT INTO "ipa_trusts"`)
+	expectInsert("ipa_trusts", 0, expectedErr)
+	require.EqualError(t, ipa.CreateIpaTrusts(s.Log, s.DB, ipaID, trusts), expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipa_trust_domains"
+	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_trust_domains"`)
+	expectInsert("ipa_trusts", 3, nil)
+	expectInsert("ipa_trust_domains", 0, expectedErr)
+	require.EqualError(t, ipa.CreateIpaTrusts(s.Log, s.DB, ipaID, trusts), expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Error on INSERT INTO "ipa_trust_id_ranges"
+	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_trust_id_ranges"`)
+	expectInsert("ipa_trusts", 3, nil)
+	expectInsert("ipa_trust_domains", 4, nil)
+	expectInsert("ipa_trust_id_ranges", 0, expectedErr)
+	require.EqualError(t, ipa.CreateIpaTrusts(s.Log, s.DB, ipaID, trusts), expectedErr.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// Success scenario
+	expectInsert("ipa_trusts", 3, nil)
+	expectInsert("ipa_trust_domains", 4, nil)
+	expectInsert("ipa_trust_id_ranges", 5, nil)
+	require.NoError(t, ipa.CreateIpaTrusts(s.Log, s.DB, ipaID, trusts))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+	assert.Equal(t, ipaID, trusts[0].IpaID)
+	assert.Equal(t, uint(3), trusts[0].Domains[0].IpaTrustID)
+	assert.Equal(t, uint(3), trusts[0].IDRanges[0].IpaTrustID)
+}
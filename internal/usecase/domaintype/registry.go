@@ -0,0 +1,91 @@
+// Package domaintype is the registry of the domain types supported
+// by the service. The built-in types (rhel-idm and active-directory)
+// are registered on init; additional types register themselves with
+// Register from the init function of their own package, which must
+// also register its model hooks with model.RegisterDomainType.
+package domaintype
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/domaintype"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/activedirectory"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+)
+
+var (
+	lock     sync.RWMutex
+	handlers = map[uint]domaintype.DomainTypeHandler{}
+)
+
+func init() {
+	MustRegister(ipa.New())
+	MustRegister(activedirectory.New())
+}
+
+// Register add a domain type handler to the registry.
+// h is the handler to register; its model hooks must be
+// registered already, so that the type and the name match.
+// Return nil on success, else an error.
+func Register(h domaintype.DomainTypeHandler) error {
+	if h == nil {
+		return fmt.Errorf("'h' is nil")
+	}
+	if h.Type() == model.DomainTypeUndefined {
+		return fmt.Errorf("domain type '%s' is undefined", h.Name())
+	}
+	if name := model.DomainTypeString(h.Type()); name != string(h.Name()) {
+		return fmt.Errorf(
+			"domain type '%d' is registered at the model as '%s' instead of '%s'",
+			h.Type(), name, h.Name(),
+		)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := handlers[h.Type()]; ok {
+		return fmt.Errorf("domain type '%s' is already registered", h.Name())
+	}
+	handlers[h.Type()] = h
+	return nil
+}
+
+// MustRegister is like Register but it panics on error; to
+// be used from init functions.
+func MustRegister(h domaintype.DomainTypeHandler) {
+	if err := Register(h); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup return the handler for the domain type stored at
+// domains.type, and false if it is not registered.
+func Lookup(domainType uint) (domaintype.DomainTypeHandler, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	h, ok := handlers[domainType]
+	return h, ok
+}
+
+// LookupByName return the handler for the domain_type used by
+// the API, and false if it is not registered.
+func LookupByName(name public.DomainType) (domaintype.DomainTypeHandler, bool) {
+	return Lookup(model.DomainTypeUint(string(name)))
+}
+
+// List return the registered handlers ordered by type.
+func List() []domaintype.DomainTypeHandler {
+	lock.RLock()
+	defer lock.RUnlock()
+	output := make([]domaintype.DomainTypeHandler, 0, len(handlers))
+	for _, h := range handlers {
+		output = append(output, h)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Type() < output[j].Type()
+	})
+	return output
+}
@@ -0,0 +1,78 @@
+package domaintype
+
+import (
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/domaintype"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/activedirectory"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renamedHandler wraps a handler to report a different name.
+type renamedHandler struct {
+	domaintype.DomainTypeHandler
+	name public.DomainType
+}
+
+func (h renamedHandler) Name() public.DomainType { return h.name }
+
+func TestLookup(t *testing.T) {
+	h, ok := Lookup(ipa.TypeID)
+	require.True(t, ok)
+	assert.Equal(t, public.RhelIdm, h.Name())
+
+	h, ok = Lookup(activedirectory.TypeID)
+	require.True(t, ok)
+	assert.Equal(t, public.ActiveDirectory, h.Name())
+
+	h, ok = Lookup(model.DomainTypeUndefined)
+	assert.False(t, ok)
+	assert.Nil(t, h)
+
+	h, ok = Lookup(999)
+	assert.False(t, ok)
+	assert.Nil(t, h)
+}
+
+func TestLookupByName(t *testing.T) {
+	h, ok := LookupByName(public.RhelIdm)
+	require.True(t, ok)
+	assert.Equal(t, ipa.TypeID, h.Type())
+
+	h, ok = LookupByName(public.ActiveDirectory)
+	require.True(t, ok)
+	assert.Equal(t, activedirectory.TypeID, h.Type())
+
+	h, ok = LookupByName("unknown")
+	assert.False(t, ok)
+	assert.Nil(t, h)
+}
+
+func TestList(t *testing.T) {
+	handlers := List()
+	require.Len(t, handlers, 2)
+	assert.Equal(t, ipa.TypeID, handlers[0].Type())
+	assert.Equal(t, activedirectory.TypeID, handlers[1].Type())
+	assert.Equal(t, model.DomainTypes(), []uint{handlers[0].Type(), handlers[1].Type()})
+}
+
+func TestRegister(t *testing.T) {
+	assert.EqualError(t, Register(nil), "'h' is nil")
+	assert.EqualError(t, Register(ipa.New()),
+		"domain type 'rhel-idm' is already registered")
+	assert.EqualError(t, Register(activedirectory.New()),
+		"domain type 'active-directory' is already registered")
+	assert.EqualError(t, Register(renamedHandler{ipa.New(), "other"}),
+		"domain type '1' is registered at the model as 'rhel-idm' instead of 'other'")
+	assert.Len(t, List(), 2)
+}
+
+func TestMustRegister(t *testing.T) {
+	assert.Panics(t, func() {
+		MustRegister(nil)
+	})
+}
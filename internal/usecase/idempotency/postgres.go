@@ -0,0 +1,127 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"gorm.io/gorm"
+)
+
+// postgresPruneInterval is how often the expired keys are deleted
+// by every replica.
+const postgresPruneInterval = 10 * time.Minute
+
+// postgresRecord is a row of the idempotency_keys table.
+type postgresRecord struct {
+	Fingerprint  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+}
+
+// postgresStore keep the idempotency keys into the database, so they
+// are shared by every replica of the service.
+type postgresStore struct {
+	db  *gorm.DB
+	now func() time.Time
+
+	mutex    sync.Mutex
+	prunedAt time.Time
+}
+
+// NewPostgresStore create a store which keep the idempotency keys
+// into the idempotency_keys table.
+// db is the database connector.
+// Return the store or panic if db is nil.
+func NewPostgresStore(db *gorm.DB) idempotency.Store {
+	return newPostgresStore(db, time.Now)
+}
+
+func newPostgresStore(db *gorm.DB, now func() time.Time) *postgresStore {
+	if db == nil {
+		panic("'db' is nil")
+	}
+	return &postgresStore{
+		db:       db,
+		now:      now,
+		prunedAt: now(),
+	}
+}
+
+func (s *postgresStore) Begin(ctx context.Context, scope, key, fingerprint string, lockedUntil, expiresAt time.Time) (*idempotency.Record, error) {
+	now := s.now().UTC()
+	if err := s.prune(ctx, now); err != nil {
+		return nil, err
+	}
+	// The expired keys are reserved again, as they could be not
+	// pruned yet, and so are the keys in progress whose lock expired,
+	// as the request which reserved them was lost
+	result := s.db.WithContext(ctx).Exec(`INSERT INTO idempotency_keys `+
+		`(org_id, idempotency_key, fingerprint, status_code, content_type, response_body, created_at, expires_at, locked_until) `+
+		`VALUES (?, ?, ?, 0, '', NULL, ?, ?, ?) `+
+		`ON CONFLICT (org_id, idempotency_key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, `+
+		`status_code = 0, content_type = '', response_body = NULL, `+
+		`created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at, locked_until = EXCLUDED.locked_until `+
+		`WHERE idempotency_keys.expires_at < ? `+
+		`OR (idempotency_keys.status_code = 0 AND idempotency_keys.locked_until < ?)`,
+		scope, key, fingerprint, now, expiresAt.UTC(), lockedUntil.UTC(), now, now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected != 0 {
+		return nil, nil
+	}
+
+	var rows []postgresRecord
+	if err := s.db.WithContext(ctx).
+		Raw(`SELECT fingerprint, status_code, content_type, response_body FROM idempotency_keys `+
+			`WHERE org_id = ? AND idempotency_key = ?`, scope, key).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("idempotency key '%s' was released by a concurrent request", key)
+	}
+	return &idempotency.Record{
+		Fingerprint: rows[0].Fingerprint,
+		StatusCode:  rows[0].StatusCode,
+		ContentType: rows[0].ContentType,
+		Body:        rows[0].ResponseBody,
+	}, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, scope, key string, record *idempotency.Record) error {
+	if record == nil {
+		return internal_errors.NilArgError("record")
+	}
+	return s.db.WithContext(ctx).
+		Exec(`UPDATE idempotency_keys SET status_code = ?, content_type = ?, response_body = ?, locked_until = NULL `+
+			`WHERE org_id = ? AND idempotency_key = ?`,
+			record.StatusCode, record.ContentType, record.Body, scope, key).
+		Error
+}
+
+func (s *postgresStore) Release(ctx context.Context, scope, key string) error {
+	return s.db.WithContext(ctx).
+		Exec(`DELETE FROM idempotency_keys WHERE org_id = ? AND idempotency_key = ? AND status_code = 0`,
+			scope, key).
+		Error
+}
+
+// prune delete the expired keys.
+func (s *postgresStore) prune(ctx context.Context, now time.Time) error {
+	s.mutex.Lock()
+	if now.Sub(s.prunedAt) < postgresPruneInterval {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.prunedAt = now
+	s.mutex.Unlock()
+	return s.db.WithContext(ctx).
+		Exec(`DELETE FROM idempotency_keys WHERE expires_at < ?`, now).
+		Error
+}
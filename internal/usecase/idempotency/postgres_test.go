@@ -0,0 +1,173 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/idempotency"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	sqlInsertKey = `INSERT INTO idempotency_keys ` +
+		`(org_id, idempotency_key, fingerprint, status_code, content_type, response_body, created_at, expires_at, locked_until) ` +
+		`VALUES ($1, $2, $3, 0, '', NULL, $4, $5, $6) ` +
+		`ON CONFLICT (org_id, idempotency_key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, ` +
+		`status_code = 0, content_type = '', response_body = NULL, ` +
+		`created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at, locked_until = EXCLUDED.locked_until ` +
+		`WHERE idempotency_keys.expires_at < $7 ` +
+		`OR (idempotency_keys.status_code = 0 AND idempotency_keys.locked_until < $8)`
+	sqlSelectKey = `SELECT fingerprint, status_code, content_type, response_body FROM idempotency_keys ` +
+		`WHERE org_id = $1 AND idempotency_key = $2`
+	sqlCompleteKey = `UPDATE idempotency_keys SET status_code = $1, content_type = $2, response_body = $3, locked_until = NULL ` +
+		`WHERE org_id = $4 AND idempotency_key = $5`
+	sqlReleaseKey    = `DELETE FROM idempotency_keys WHERE org_id = $1 AND idempotency_key = $2 AND status_code = 0`
+	sqlDeleteExpired = `DELETE FROM idempotency_keys WHERE expires_at < $1`
+)
+
+const (
+	testOrgID            = "12345"
+	testKey              = "6f0d7a1c-4d4f-4b3e-9d0e-3c1b2a1f0e9d"
+	testFingerprint      = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	testOtherFingerprint = "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+)
+
+var recordColumns = []string{"fingerprint", "status_code", "content_type", "response_body"}
+
+func newTestPostgresStore(t *testing.T) (*postgresStore, sqlmock.Sqlmock, *time.Time) {
+	mock, db, err := test.NewSqlMock(&gorm.Session{SkipHooks: true})
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	store := newPostgresStore(db, func() time.Time { return now })
+	return store, mock, &now
+}
+
+func TestNewPostgresStore(t *testing.T) {
+	assert.PanicsWithValue(t, "'db' is nil", func() {
+		NewPostgresStore(nil)
+	})
+}
+
+func TestPostgresStoreBegin(t *testing.T) {
+	ctx := context.Background()
+	store, mock, now := newTestPostgresStore(t)
+	expiresAt := now.Add(24 * time.Hour)
+	lockedUntil := now.Add(3 * time.Second)
+
+	// The key is reserved
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertKey)).
+		WithArgs(testOrgID, testKey, testFingerprint, *now, expiresAt, lockedUntil, *now, *now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	record, err := store.Begin(ctx, testOrgID, testKey, testFingerprint, lockedUntil, expiresAt)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	// The key is in use by the first request
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertKey)).
+		WithArgs(testOrgID, testKey, testOtherFingerprint, *now, expiresAt, lockedUntil, *now, *now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlSelectKey)).
+		WithArgs(testOrgID, testKey).
+		WillReturnRows(sqlmock.NewRows(recordColumns).
+			AddRow(testFingerprint, 200, "application/json", []byte(`{}`)))
+	record, err = store.Begin(ctx, testOrgID, testKey, testOtherFingerprint, lockedUntil, expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, &idempotency.Record{
+		Fingerprint: testFingerprint,
+		StatusCode:  200,
+		ContentType: "application/json",
+		Body:        []byte(`{}`),
+	}, record)
+	assert.True(t, record.Completed())
+
+	// The key is locked by the first request in progress; once the
+	// lock expires the insert takes it over, as for the first case
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertKey)).
+		WithArgs(testOrgID, testKey, testFingerprint, *now, expiresAt, lockedUntil, *now, *now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlSelectKey)).
+		WithArgs(testOrgID, testKey).
+		WillReturnRows(sqlmock.NewRows(recordColumns).
+			AddRow(testFingerprint, 0, "", nil))
+	record, err = store.Begin(ctx, testOrgID, testKey, testFingerprint, lockedUntil, expiresAt)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.False(t, record.Completed())
+
+	// The key was released meanwhile
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertKey)).
+		WithArgs(testOrgID, testKey, testFingerprint, *now, expiresAt, lockedUntil, *now, *now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlSelectKey)).
+		WithArgs(testOrgID, testKey).
+		WillReturnRows(sqlmock.NewRows(recordColumns))
+	record, err = store.Begin(ctx, testOrgID, testKey, testFingerprint, lockedUntil, expiresAt)
+	assert.EqualError(t, err, "idempotency key '"+testKey+"' was released by a concurrent request")
+	assert.Nil(t, record)
+
+	// Database error
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertKey)).
+		WithArgs(testOrgID, testKey, testFingerprint, *now, expiresAt, lockedUntil, *now, *now).
+		WillReturnError(fmt.Errorf("connection refused"))
+	record, err = store.Begin(ctx, testOrgID, testKey, testFingerprint, lockedUntil, expiresAt)
+	assert.EqualError(t, err, "connection refused")
+	assert.Nil(t, record)
+
+	// Expired keys are deleted from time to time
+	*now = now.Add(postgresPruneInterval)
+	mock.ExpectExec(regexp.QuoteMeta(sqlDeleteExpired)).
+		WithArgs(*now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertKey)).
+		WithArgs(testOrgID, testKey, testFingerprint, *now, expiresAt, lockedUntil, *now, *now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	record, err = store.Begin(ctx, testOrgID, testKey, testFingerprint, lockedUntil, expiresAt)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStoreComplete(t *testing.T) {
+	ctx := context.Background()
+	store, mock, _ := newTestPostgresStore(t)
+
+	assert.EqualError(t, store.Complete(ctx, testOrgID, testKey, nil), "code=500, message='record' cannot be nil")
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlCompleteKey)).
+		WithArgs(201, "application/json", []byte(`{}`), testOrgID, testKey).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err := store.Complete(ctx, testOrgID, testKey, &idempotency.Record{
+		Fingerprint: testFingerprint,
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{}`),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStoreRelease(t *testing.T) {
+	ctx := context.Background()
+	store, mock, _ := newTestPostgresStore(t)
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlReleaseKey)).
+		WithArgs(testOrgID, testKey).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, store.Release(ctx, testOrgID, testKey))
+
+	mock.ExpectExec(regexp.QuoteMeta(sqlReleaseKey)).
+		WithArgs(testOrgID, testKey).
+		WillReturnError(fmt.Errorf("connection refused"))
+	assert.EqualError(t, store.Release(ctx, testOrgID, testKey), "connection refused")
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,12 +1,15 @@
 package interactor
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
-	"github.com/labstack/echo/v4"
-	"github.com/lib/pq"
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
@@ -14,6 +17,8 @@ import (
 	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"go.openly.dev/pointy"
 )
@@ -30,12 +35,11 @@ func NewDomainInteractor() interactor.DomainInteractor {
 // Return the uint representation or model.DomainTypeUndefined if it does not match
 // the current types.
 func helperDomainTypeToUint(domainType public.DomainType) uint {
-	switch domainType {
-	case api_public.RhelIdm:
-		return model.DomainTypeIpa
-	default:
+	h, ok := domaintype.LookupByName(domainType)
+	if !ok {
 		return model.DomainTypeUndefined
 	}
+	return h.Type()
 }
 
 // func (i domainInteractor) PartialUpdate(id public.Id, params *api_public.PartialUpdateTodoParams, in *api_public.Todo, out *model.Todo) error {
@@ -136,14 +140,17 @@ func (i domainInteractor) GetByID(xrhid *identity.XRHID, params *public.ReadDoma
 
 // Register translates the API input format into the business
 // data models for the PUT /domains/{uuid}/register endpoint.
+// domainRegKeys are the keys to verify the registration token, from the
+// current to the oldest generation of the main secret.
 // params contains the header parameters.
 // body contains the input payload.
 // Return the orgId and the business model for Ipa information,
 // when success translation, else it returns empty string for orgId,
 // nil for the Ipa data, and an error filled.
-func (i domainInteractor) Register(domainRegKey []byte, xrhid *identity.XRHID, params *api_public.RegisterDomainParams, body *public.RegisterDomainRequest) (string, *header.XRHIDMVersion, *model.Domain, error) {
+func (i domainInteractor) Register(domainRegKeys [][]byte, xrhid *identity.XRHID, params *api_public.RegisterDomainParams, body *public.RegisterDomainRequest) (string, *header.XRHIDMVersion, *model.Domain, error) {
 	var (
 		domainID uuid.UUID
+		claims   domain_token.Claims
 		domain   *model.Domain
 		err      error
 	)
@@ -159,14 +166,20 @@ func (i domainInteractor) Register(domainRegKey []byte, xrhid *identity.XRHID, p
 	}
 
 	// verify token
-	if domainID, err = domain_token.VerifyDomainRegistrationToken(
-		domainRegKey,
+	if domainID, claims, err = domain_token.VerifyDomainRegistrationTokenKeys(
+		domainRegKeys,
 		string(body.DomainType),
 		orgID,
 		domain_token.DomainRegistrationToken(params.XRhIdmRegistrationToken),
 	); err != nil {
-		msg := fmt.Sprintf("Domain registration token is invalid: %s", err)
-		return "", nil, nil, echo.NewHTTPError(http.StatusUnauthorized, msg)
+		definition := internal_errors.ErrTokenInvalid
+		if errors.Is(err, domain_token.ErrTokenExpired) {
+			definition = internal_errors.ErrTokenExpired
+		}
+		return "", nil, nil, definition.New(err, "Domain registration token is invalid: %s", err)
+	}
+	if err = i.guardTokenClaims(&claims, xrhid, body); err != nil {
+		return "", nil, nil, err
 	}
 
 	// Read the body payload
@@ -179,7 +192,7 @@ func (i domainInteractor) Register(domainRegKey []byte, xrhid *identity.XRHID, p
 		domain.Title = pointy.String(body.DomainName)
 	}
 	if domain.Description == nil {
-		domain.Description = pointy.String("")
+		domain.Description = pointy.String(claims.Label)
 	}
 	if domain.AutoEnrollmentEnabled == nil {
 		domain.AutoEnrollmentEnabled = pointy.Bool(false)
@@ -257,118 +270,299 @@ func (i domainInteractor) UpdateUser(
 // Create domain registration token /domains/token
 //
 // Verify input parameters and check for supported domain types.
+// Return the organization id, the domain type and the claims which
+// restrict the registration with the token.
 func (i domainInteractor) CreateDomainToken(
 	xrhid *identity.XRHID,
 	params *public.CreateDomainTokenParams,
 	body *public.DomainRegTokenRequest,
-) (orgID string, domainType public.DomainType, err error) {
+) (orgID string, domainType public.DomainType, claims domain_token.Claims, err error) {
 	if xrhid == nil {
-		return "", "", internal_errors.NilArgError("xrhid")
+		return "", "", domain_token.Claims{}, internal_errors.NilArgError("xrhid")
 	}
 	if params == nil {
-		return "", "", internal_errors.NilArgError("params")
+		return "", "", domain_token.Claims{}, internal_errors.NilArgError("params")
 	}
 	if body == nil {
-		return "", "", internal_errors.NilArgError("body")
+		return "", "", domain_token.Claims{}, internal_errors.NilArgError("body")
 	}
 
 	orgID = xrhid.Identity.OrgID
 
-	switch body.DomainType {
-	case api_public.DomainType(api_public.RhelIdm):
-		domainType = body.DomainType
-	default:
-		return "", "", fmt.Errorf("Unsupported domain_type='%s'", body.DomainType)
+	if _, ok := domaintype.LookupByName(body.DomainType); !ok {
+		return "", "", domain_token.Claims{}, fmt.Errorf("Unsupported domain_type='%s'", body.DomainType)
 	}
+	domainType = body.DomainType
 
-	return orgID, domainType, nil
-}
-
-// --------- Private methods -----------
-
-// translateIpaModel translates the public.DomainIpa to the model.Ipa
-func (i domainInteractor) translateDomainIpa(body *public.DomainIpa, domainIpa *model.Ipa) error {
-	domainIpa.RealmName = pointy.String(body.RealmName)
-
-	// Translate realm domains
-	i.translateRealmDomains(body, domainIpa)
-
-	// Certificate list
-	i.translateIdmCaCerts(body, domainIpa)
+	if claims, err = i.translateTokenClaims(body); err != nil {
+		return "", "", domain_token.Claims{}, err
+	}
 
-	// Server list
-	i.translateIdmServers(body, domainIpa)
+	return orgID, domainType, claims, nil
+}
 
-	// Location list
-	i.translateIdmLocations(body, domainIpa)
+// translateTokenClaims validates the optional restrictions of a
+// registration token; the domain name is stored in lower-case.
+func (i domainInteractor) translateTokenClaims(body *public.DomainRegTokenRequest) (claims domain_token.Claims, err error) {
+	if body.DomainName != nil {
+		claims.DomainName = strings.ToLower(*body.DomainName)
+		if claims.DomainName == "" || len(claims.DomainName) > domain_token.DomainNameMaxLength {
+			return domain_token.Claims{}, internal_errors.NewHTTPErrorF(http.StatusBadRequest, "'domain_name' is invalid")
+		}
+	}
+	if body.SubscriptionManagerId != nil {
+		if *body.SubscriptionManagerId == uuid.Nil {
+			return domain_token.Claims{}, internal_errors.NewHTTPErrorF(http.StatusBadRequest, "'subscription_manager_id' is invalid")
+		}
+		claims.SubscriptionManagerId = *body.SubscriptionManagerId
+	}
+	if body.Label != nil {
+		if utf8.RuneCountInString(*body.Label) > domain_token.LabelMaxLength {
+			return domain_token.Claims{}, internal_errors.NewHTTPErrorF(http.StatusBadRequest,
+				"'label' exceeds %d characters", domain_token.LabelMaxLength)
+		}
+		claims.Label = *body.Label
+	}
+	return claims, nil
+}
 
+// guardTokenClaims checks the registration against the restrictions
+// of the registration token.
+func (i domainInteractor) guardTokenClaims(claims *domain_token.Claims, xrhid *identity.XRHID, body *public.RegisterDomainRequest) error {
+	if claims.DomainName != "" && !strings.EqualFold(claims.DomainName, body.DomainName) {
+		return internal_errors.ErrTokenClaims.New(nil,
+			"Domain registration token is only valid for the domain '%s'", claims.DomainName)
+	}
+	if claims.SubscriptionManagerId != uuid.Nil {
+		var rhsmID uuid.UUID
+		if xrhid.Identity.System != nil {
+			rhsmID, _ = uuid.Parse(xrhid.Identity.System.CommonName)
+		}
+		if rhsmID != claims.SubscriptionManagerId {
+			return internal_errors.ErrTokenClaims.New(nil,
+				"Domain registration token is only valid for the server '%s'", claims.SubscriptionManagerId)
+		}
+	}
 	return nil
 }
 
-func (i domainInteractor) translateRealmDomains(body *public.DomainIpa, domainIpa *model.Ipa) {
-	if body.RealmDomains == nil {
-		domainIpa.RealmDomains = pq.StringArray{}
-		return
+// ReadLocationSubnets translate the input for the
+// GET /domains/:uuid/location-subnets endpoint.
+// xrhid is the unserialized identity structure stored into the request
+// context.
+// UUID is the domain uuid.
+// params is the endpoint parameters.
+// Return the organization id and nil error on success, else an
+// empty organization id and an error with the details.
+func (i domainInteractor) ReadLocationSubnets(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.ReadDomainLocationSubnetsParams,
+) (orgID string, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", err
 	}
-	domainIpa.RealmDomains = make(pq.StringArray, 0)
-	domainIpa.RealmDomains = append(
-		domainIpa.RealmDomains,
-		body.RealmDomains...,
-	)
+	if params == nil {
+		return "", internal_errors.NilArgError("params")
+	}
+	return xrhid.Identity.OrgID, nil
 }
 
-func (i domainInteractor) translateIdmCaCerts(body *public.DomainIpa, domainIpa *model.Ipa) {
-	if body.CaCerts == nil {
-		domainIpa.CaCerts = []model.IpaCert{}
-		return
+// UpdateLocationSubnets translate the input for the
+// PUT /domains/:uuid/location-subnets endpoint.
+// xrhid is the unserialized identity structure stored into the request
+// context.
+// UUID is the domain uuid.
+// params is the endpoint parameters.
+// body is the complete list of subnet to location mappings.
+// Return the organization id, the mappings with the subnets in
+// canonical form and nil error on success, else an error with the
+// details.
+func (i domainInteractor) UpdateLocationSubnets(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.UpdateDomainLocationSubnetsParams,
+	body *api_public.LocationSubnets,
+) (orgID string, data []model.DomainLocationSubnet, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", nil, err
 	}
-	domainIpa.CaCerts = make([]model.IpaCert, len(body.CaCerts))
-	for idx := range body.CaCerts {
-		i.translateIdmCaCert(&domainIpa.CaCerts[idx], &body.CaCerts[idx])
+	if params == nil {
+		return "", nil, internal_errors.NilArgError("params")
+	}
+	if body == nil {
+		return "", nil, internal_errors.NilArgError("body")
 	}
+	if data, err = i.translateLocationSubnets(body.Subnets); err != nil {
+		return "", nil, err
+	}
+	return xrhid.Identity.OrgID, data, nil
 }
 
-func (i domainInteractor) translateIdmCaCert(caCert *model.IpaCert, cert *api_public.Certificate) {
-	caCert.Nickname = cert.Nickname
-	caCert.Issuer = cert.Issuer
-	caCert.Subject = cert.Subject
-	caCert.SerialNumber = cert.SerialNumber
-	caCert.NotBefore = cert.NotBefore
-	caCert.NotAfter = cert.NotAfter
-	caCert.Pem = cert.Pem
+// Export translate the input for the GET /domains/export endpoint.
+// xrhid is the unserialized identity structure stored into the request
+// context.
+// params is the endpoint parameters.
+// Return the organization id and nil error on success, else an
+// empty organization id and an error with the details.
+func (i domainInteractor) Export(
+	xrhid *identity.XRHID,
+	params *api_public.ExportDomainsParams,
+) (orgID string, err error) {
+	if xrhid == nil {
+		return "", internal_errors.NilArgError("xrhid")
+	}
+	if params == nil {
+		return "", internal_errors.NilArgError("params")
+	}
+	return xrhid.Identity.OrgID, nil
 }
 
-func (i domainInteractor) translateIdmServers(body *public.DomainIpa, domainIpa *model.Ipa) {
-	if body.Servers == nil {
-		domainIpa.Servers = []model.IpaServer{}
-		return
+// Import translate an export document to the domains to import
+// into an organization; the domains keep the uuid, the registration
+// state and the registration record of the document.
+// orgID is the organization which import the domains, which could
+// be different from the organization of the document.
+// doc is the export document.
+// Return the domains, the subnet to location mappings by domain
+// uuid and nil error on success, else an error with the details.
+func (i domainInteractor) Import(
+	orgID string,
+	doc *api_public.DomainExport,
+) (data []model.Domain, subnets map[uuid.UUID][]model.DomainLocationSubnet, err error) {
+	if orgID == "" {
+		return nil, nil, internal_errors.EmptyArgError("orgID")
+	}
+	if err = domainexport.Verify(doc); err != nil {
+		return nil, nil, err
+	}
+	data = make([]model.Domain, 0, len(doc.Domains))
+	subnets = make(map[uuid.UUID][]model.DomainLocationSubnet, len(doc.Domains))
+	for idx := range doc.Domains {
+		var domain *model.Domain
+		item := &doc.Domains[idx]
+		if item.Domain.DomainId == nil || *item.Domain.DomainId == uuid.Nil {
+			return nil, nil, fmt.Errorf("'domains[%d].domain.domain_id' is invalid", idx)
+		}
+		domainID := *item.Domain.DomainId
+		if _, ok := subnets[domainID]; ok {
+			return nil, nil, fmt.Errorf("'domains[%d].domain.domain_id' duplicates domain '%s'", idx, domainID.String())
+		}
+		if domain, err = i.translateDomain(orgID, domainID, &item.Domain); err != nil {
+			return nil, nil, fmt.Errorf("'domains[%d].domain' is invalid: %w", idx, err)
+		}
+		if err = i.translateRegistration(&item.Domain, domain); err != nil {
+			return nil, nil, fmt.Errorf("'domains[%d].domain' is invalid: %w", idx, err)
+		}
+		if subnets[domainID], err = i.translateLocationSubnets(item.LocationSubnets); err != nil {
+			return nil, nil, fmt.Errorf("'domains[%d].location_subnets' is invalid: %w", idx, err)
+		}
+		data = append(data, *domain)
 	}
-	domainIpa.Servers = make([]model.IpaServer, len(body.Servers))
-	for idx, server := range body.Servers {
-		domainIpa.Servers[idx].FQDN = server.Fqdn
-		if server.SubscriptionManagerId != nil {
-			domainIpa.Servers[idx].RHSMId = pointy.String(server.SubscriptionManagerId.String())
+	return data, subnets, nil
+}
+
+// batchMaxOperations is the max number of operations of a
+// POST /domains:batch request.
+const batchMaxOperations = 100
+
+// Batch translate the input for the POST /domains:batch endpoint.
+// xrhid is the unserialized identity structure stored into the request
+// context.
+// params is the endpoint parameters.
+// body is the list of operations.
+// Return the organization id, true when the operations are run inside
+// one transaction, and for every operation the domain with the
+// fields to update, or only the domain uuid for a delete operation;
+// else an error with the details.
+func (i domainInteractor) Batch(
+	xrhid *identity.XRHID,
+	params *api_public.BatchDomainsParams,
+	body *api_public.BatchDomainsRequest,
+) (orgID string, atomic bool, data []*model.Domain, err error) {
+	if xrhid == nil {
+		return "", false, nil, internal_errors.NilArgError("xrhid")
+	}
+	if params == nil {
+		return "", false, nil, internal_errors.NilArgError("params")
+	}
+	if body == nil {
+		return "", false, nil, internal_errors.NilArgError("body")
+	}
+	if len(body.Operations) == 0 || len(body.Operations) > batchMaxOperations {
+		return "", false, nil, internal_errors.NewHTTPErrorF(
+			http.StatusBadRequest,
+			"'operations' must have from 1 to %d items",
+			batchMaxOperations,
+		)
+	}
+	orgID = xrhid.Identity.OrgID
+	data = make([]*model.Domain, 0, len(body.Operations))
+	for idx := range body.Operations {
+		var domain *model.Domain
+		if domain, err = i.translateBatchOperation(xrhid, &body.Operations[idx]); err != nil {
+			return "", false, nil, internal_errors.NewHTTPErrorF(
+				http.StatusBadRequest,
+				"'operations[%d]' is invalid: %s",
+				idx, internal_errors.PublicDetail(err),
+			)
 		}
-		domainIpa.Servers[idx].Location = server.Location
-		domainIpa.Servers[idx].PKInitServer = server.PkinitServer
-		domainIpa.Servers[idx].CaServer = server.CaServer
-		domainIpa.Servers[idx].HCCEnrollmentServer = server.HccEnrollmentServer
-		domainIpa.Servers[idx].HCCUpdateServer = server.HccUpdateServer
+		data = append(data, domain)
 	}
+	atomic = body.Atomic == nil || *body.Atomic
+	return orgID, atomic, data, nil
 }
 
-func (i domainInteractor) translateIdmLocations(body *public.DomainIpa, domainIpa *model.Ipa) {
-	if body.Locations == nil {
-		domainIpa.Locations = []model.IpaLocation{}
-		return
+// ApproveDomain translate the input for the
+// POST /domains/:uuid/approve endpoint.
+// xrhid is the unserialized identity structure stored into the request
+// context.
+// UUID is the domain uuid.
+// params is the endpoint parameters.
+// body is the optional reason of the decision.
+// Return the organization id, the decision and nil error on success,
+// else an error with the details.
+func (i domainInteractor) ApproveDomain(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.ApproveDomainParams,
+	body *api_public.DomainDecisionRequest,
+) (orgID string, decision *model.DomainRegistration, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", nil, err
+	}
+	if params == nil {
+		return "", nil, internal_errors.NilArgError("params")
+	}
+	return i.translateDecision(xrhid, body)
+}
+
+// RejectDomain translate the input for the
+// POST /domains/:uuid/reject endpoint.
+// xrhid is the unserialized identity structure stored into the request
+// context.
+// UUID is the domain uuid.
+// params is the endpoint parameters.
+// body is the optional reason of the decision.
+// Return the organization id, the decision and nil error on success,
+// else an error with the details.
+func (i domainInteractor) RejectDomain(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.RejectDomainParams,
+	body *api_public.DomainDecisionRequest,
+) (orgID string, decision *model.DomainRegistration, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", nil, err
 	}
-	domainIpa.Locations = make([]model.IpaLocation, len(body.Locations))
-	for idx, location := range body.Locations {
-		domainIpa.Locations[idx].Name = location.Name
-		domainIpa.Locations[idx].Description = location.Description
+	if params == nil {
+		return "", nil, internal_errors.NilArgError("params")
 	}
+	return i.translateDecision(xrhid, body)
 }
 
+// --------- Private methods -----------
+
 func (i domainInteractor) guardRegister(xrhid *identity.XRHID, params *api_public.RegisterDomainParams, body *public.Domain) (err error) {
 	if xrhid == nil {
 		return internal_errors.NilArgError("xrhid")
@@ -383,6 +577,42 @@ func (i domainInteractor) guardRegister(xrhid *identity.XRHID, params *api_publi
 	return nil
 }
 
+// translateLocationSubnets validates the subnets and returns them
+// in canonical form, so "192.0.2.1/24" is stored as "192.0.2.0/24".
+func (i domainInteractor) translateLocationSubnets(subnets []public.LocationSubnet) ([]model.DomainLocationSubnet, error) {
+	seen := make(map[netip.Prefix]struct{}, len(subnets))
+	data := make([]model.DomainLocationSubnet, 0, len(subnets))
+	for idx := range subnets {
+		prefix, err := netip.ParsePrefix(subnets[idx].Subnet)
+		if err != nil {
+			return nil, internal_errors.NewHTTPErrorF(
+				http.StatusBadRequest,
+				"'subnets[%d].subnet' is not a valid subnet: %s",
+				idx, err.Error(),
+			)
+		}
+		prefix = prefix.Masked()
+		if _, ok := seen[prefix]; ok {
+			return nil, internal_errors.NewHTTPErrorF(
+				http.StatusBadRequest,
+				"'subnets[%d].subnet' duplicates subnet '%s'",
+				idx, prefix.String(),
+			)
+		}
+		seen[prefix] = struct{}{}
+		if subnets[idx].Location == "" {
+			return nil, internal_errors.EmptyArgError(
+				fmt.Sprintf("subnets[%d].location", idx),
+			)
+		}
+		data = append(data, model.DomainLocationSubnet{
+			Subnet:   prefix.String(),
+			Location: subnets[idx].Location,
+		})
+	}
+	return data, nil
+}
+
 func (i domainInteractor) guardXrhidUUID(xrhid *identity.XRHID, UUID uuid.UUID) (err error) {
 	if xrhid == nil {
 		return internal_errors.NilArgError("xrhid")
@@ -393,6 +623,30 @@ func (i domainInteractor) guardXrhidUUID(xrhid *identity.XRHID, UUID uuid.UUID)
 	return nil
 }
 
+// translateDecision fill the decision about a domain registration
+// with the user or service account of the request.
+func (i domainInteractor) translateDecision(
+	xrhid *identity.XRHID,
+	body *api_public.DomainDecisionRequest,
+) (orgID string, decision *model.DomainRegistration, err error) {
+	var decidedBy string
+	switch {
+	case xrhid.Identity.User != nil && xrhid.Identity.User.Username != "":
+		decidedBy = xrhid.Identity.User.Username
+	case xrhid.Identity.ServiceAccount != nil && xrhid.Identity.ServiceAccount.Username != "":
+		decidedBy = xrhid.Identity.ServiceAccount.Username
+	default:
+		return "", nil, fmt.Errorf("'xrhid' has no user or service account")
+	}
+	var reason *string
+	if body != nil && body.Reason != nil && strings.TrimSpace(*body.Reason) != "" {
+		reason = pointy.String(strings.TrimSpace(*body.Reason))
+	}
+	decision = &model.DomainRegistration{}
+	decision.Decide(decidedBy, time.Now().UTC(), reason)
+	return xrhid.Identity.OrgID, decision, nil
+}
+
 func (i domainInteractor) guardUserUpdate(body *public.UpdateDomainUserRequest) (err error) {
 	if body == nil {
 		return internal_errors.NilArgError("body")
@@ -403,6 +657,43 @@ func (i domainInteractor) guardUserUpdate(body *public.UpdateDomainUserRequest)
 	return nil
 }
 
+// translateBatchOperation translates an operation of a batch request
+// to the domain with the fields to update.
+func (i domainInteractor) translateBatchOperation(
+	xrhid *identity.XRHID,
+	operation *public.BatchDomainOperation,
+) (domain *model.Domain, err error) {
+	if operation.DomainId == uuid.Nil {
+		return nil, internal_errors.NewHTTPErrorF(http.StatusBadRequest, "'domain_id' is invalid")
+	}
+	if operation.Op != public.Update && operation.Update != nil {
+		return nil, internal_errors.NewHTTPErrorF(http.StatusBadRequest,
+			"'update' is only allowed for the '%s' operation", public.Update)
+	}
+	switch operation.Op {
+	case public.Update:
+		if operation.Update == nil {
+			return nil, internal_errors.NewHTTPErrorF(http.StatusBadRequest,
+				"'update' is required for the '%s' operation", public.Update)
+		}
+		_, domain, err = i.UpdateUser(xrhid, operation.DomainId, nil, operation.Update)
+		return domain, err
+	case public.Enable, public.Disable:
+		_, domain, err = i.UpdateUser(xrhid, operation.DomainId, nil, &public.UpdateDomainUserRequest{
+			AutoEnrollmentEnabled: pointy.Bool(operation.Op == public.Enable),
+		})
+		return domain, err
+	case public.Delete:
+		return &model.Domain{
+			OrgId:      xrhid.Identity.OrgID,
+			DomainUuid: operation.DomainId,
+		}, nil
+	default:
+		return nil, internal_errors.NewHTTPErrorF(http.StatusBadRequest,
+			"'op' is not supported: '%s'", operation.Op)
+	}
+}
+
 // translateDomain translates the public.Domain to the model.Domain
 func (i domainInteractor) translateDomain(orgID string, UUID uuid.UUID, body *public.Domain) (domain *model.Domain, err error) {
 	domain = &model.Domain{}
@@ -412,35 +703,71 @@ func (i domainInteractor) translateDomain(orgID string, UUID uuid.UUID, body *pu
 	domain.Description = body.Description
 	domain.AutoEnrollmentEnabled = body.AutoEnrollmentEnabled
 	domain.DomainName = pointy.String(body.DomainName)
-	switch body.DomainType {
-	case api_public.DomainType(api_public.RhelIdm):
-		domain.Type = pointy.Uint(model.DomainTypeIpa)
-		domain.IpaDomain = &model.Ipa{}
-		err = i.translateDomainIpa(body.RhelIdm, domain.IpaDomain)
-	default:
-		err = fmt.Errorf("Unsupported domain_type='%s'", body.DomainType)
+	h, ok := domaintype.LookupByName(body.DomainType)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported domain_type='%s'", body.DomainType)
 	}
-	if err != nil {
+	if err = h.TranslateRegister(body, domain); err != nil {
 		return nil, err
 	}
 	return domain, nil
 }
 
+// translateRegistration translates the registration state and the
+// registration record of an exported domain; a domain without state
+// is active.
+func (i domainInteractor) translateRegistration(body *public.Domain, domain *model.Domain) error {
+	domain.RegistrationState = model.DomainRegistrationActive
+	if body.RegistrationState != nil {
+		switch *body.RegistrationState {
+		case public.Active, public.PendingApproval, public.Rejected:
+			domain.RegistrationState = string(*body.RegistrationState)
+		default:
+			return fmt.Errorf("'registration_state' is invalid")
+		}
+	}
+	if body.Registration == nil {
+		return nil
+	}
+	registration := body.Registration
+	domain.Registration = &model.DomainRegistration{
+		CreatedAt:             registration.RegisteredAt,
+		SubscriptionManagerID: registration.SubscriptionManagerId,
+		FQDN:                  registration.Fqdn,
+		IpaHccVersion:         registration.IpaHccVersion,
+		IpaVersion:            registration.IpaVersion,
+		OsReleaseID:           registration.OsReleaseId,
+		OsReleaseVersionID:    registration.OsReleaseVersionId,
+		CaCerts:               make([]model.RegistrationCert, 0, len(registration.CaCerts)),
+		DecidedBy:             registration.DecidedBy,
+		DecidedAt:             registration.DecidedAt,
+		Reason:                registration.Reason,
+	}
+	for _, cert := range registration.CaCerts {
+		domain.Registration.CaCerts = append(domain.Registration.CaCerts, model.RegistrationCert{
+			Issuer:       cert.Issuer,
+			Nickname:     cert.Nickname,
+			NotAfter:     cert.NotAfter,
+			NotBefore:    cert.NotBefore,
+			Pem:          cert.Pem,
+			SerialNumber: cert.SerialNumber,
+			Subject:      cert.Subject,
+		})
+	}
+	return nil
+}
+
 // translateUpdateDomainAgent translates the public.UpdateDomainAgentRequest to the model.Domain
 func (i domainInteractor) translateUpdateDomainAgent(orgID string, UUID uuid.UUID, body *public.UpdateDomainAgentRequest) (domain *model.Domain, err error) {
 	domain = &model.Domain{}
 	domain.OrgId = orgID
 	domain.DomainUuid = UUID
 	domain.DomainName = pointy.String(body.DomainName)
-	switch body.DomainType {
-	case api_public.DomainType(api_public.RhelIdm):
-		domain.Type = pointy.Uint(model.DomainTypeIpa)
-		domain.IpaDomain = &model.Ipa{}
-		err = i.translateDomainIpa(&body.RhelIdm, domain.IpaDomain)
-	default:
-		err = fmt.Errorf("Unsupported domain_type='%s'", body.DomainType)
+	h, ok := domaintype.LookupByName(body.DomainType)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported domain_type='%s'", body.DomainType)
 	}
-	if err != nil {
+	if err = h.TranslateUpdateAgent(body, domain); err != nil {
 		return nil, err
 	}
 	return domain, nil
@@ -1,13 +1,14 @@
 package interactor
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/labstack/echo/v4"
 	"github.com/lib/pq"
 	"github.com/podengo-project/idmsvc-backend/internal/api/header"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
@@ -17,6 +18,9 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/activedirectory"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,7 +44,7 @@ func TestHelperDomainTypeToUint(t *testing.T) {
 	assert.Equal(t, model.DomainTypeUndefined, result)
 
 	result = helperDomainTypeToUint(public.RhelIdm)
-	assert.Equal(t, model.DomainTypeIpa, result)
+	assert.Equal(t, ipa.TypeID, result)
 }
 
 func createFakeSystemIdentity(orgID string) identity.XRHID {
@@ -103,8 +107,8 @@ func TestRegisterIpa(t *testing.T) {
 		}
 		NotBefore          = time.Now().UTC()
 		NotAfter           = NotBefore.Add(24 * time.Hour)
-		ESignatureMismatch = echo.NewHTTPError(
-			http.StatusUnauthorized,
+		ESignatureMismatch = internal_errors.ErrTokenInvalid.New(
+			errors.New("Signature mismatch"),
 			"Domain registration token is invalid: Signature mismatch",
 		)
 	)
@@ -228,12 +232,12 @@ func TestRegisterIpa(t *testing.T) {
 					Title:                 pointy.String("mydomain.example"),
 					Description:           pointy.String(""),
 					AutoEnrollmentEnabled: pointy.Bool(false),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
+					Type:                  pointy.Uint(ipa.TypeID),
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String(""),
-						CaCerts:      []model.IpaCert{},
-						Servers:      []model.IpaServer{},
-						Locations:    []model.IpaLocation{},
+						CaCerts:      []ipa.IpaCert{},
+						Servers:      []ipa.IpaServer{},
+						Locations:    []ipa.IpaLocation{},
 						RealmDomains: pq.StringArray{},
 					},
 				},
@@ -265,12 +269,12 @@ func TestRegisterIpa(t *testing.T) {
 					Title:                 pointy.String("mydomain.example"),
 					Description:           pointy.String(""),
 					AutoEnrollmentEnabled: pointy.Bool(false),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
+					Type:                  pointy.Uint(ipa.TypeID),
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String("MYDOMAIN.EXAMPLE"),
-						CaCerts:      []model.IpaCert{},
-						Servers:      []model.IpaServer{},
-						Locations:    []model.IpaLocation{},
+						CaCerts:      []ipa.IpaCert{},
+						Servers:      []ipa.IpaServer{},
+						Locations:    []ipa.IpaLocation{},
 						RealmDomains: pq.StringArray{},
 					},
 				},
@@ -303,12 +307,12 @@ func TestRegisterIpa(t *testing.T) {
 					Title:                 pointy.String("mydomain.example"),
 					Description:           pointy.String(""),
 					AutoEnrollmentEnabled: pointy.Bool(false),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
+					Type:                  pointy.Uint(ipa.TypeID),
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String("MYDOMAIN.EXAMPLE"),
-						CaCerts:      []model.IpaCert{},
-						Servers:      []model.IpaServer{},
-						Locations:    []model.IpaLocation{},
+						CaCerts:      []ipa.IpaCert{},
+						Servers:      []ipa.IpaServer{},
+						Locations:    []ipa.IpaLocation{},
 						RealmDomains: pq.StringArray{"server.domain.example"},
 					},
 				},
@@ -351,10 +355,10 @@ func TestRegisterIpa(t *testing.T) {
 					Title:                 pointy.String("mydomain.example"),
 					Description:           pointy.String(""),
 					AutoEnrollmentEnabled: pointy.Bool(false),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
+					Type:                  pointy.Uint(ipa.TypeID),
+					TypeData: &ipa.Ipa{
 						RealmName: pointy.String("MYDOMAIN.EXAMPLE"),
-						CaCerts: []model.IpaCert{
+						CaCerts: []ipa.IpaCert{
 							{
 								Nickname:     "MYDOMAIN.EXAMPLE IPA CA",
 								SerialNumber: "1",
@@ -365,8 +369,8 @@ func TestRegisterIpa(t *testing.T) {
 								Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
 							},
 						},
-						Servers:      []model.IpaServer{},
-						Locations:    []model.IpaLocation{},
+						Servers:      []ipa.IpaServer{},
+						Locations:    []ipa.IpaLocation{},
 						RealmDomains: pq.StringArray{},
 					},
 				},
@@ -409,11 +413,11 @@ func TestRegisterIpa(t *testing.T) {
 					Title:                 pointy.String("mydomain.example"),
 					Description:           pointy.String(""),
 					AutoEnrollmentEnabled: pointy.Bool(false),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
+					Type:                  pointy.Uint(ipa.TypeID),
+					TypeData: &ipa.Ipa{
 						RealmName: pointy.String("MYDOMAIN.EXAMPLE"),
-						CaCerts:   []model.IpaCert{},
-						Servers: []model.IpaServer{
+						CaCerts:   []ipa.IpaCert{},
+						Servers: []ipa.IpaServer{
 							{
 								FQDN:                "server.mydomain.example",
 								RHSMId:              pointy.String(rhsmID.String()),
@@ -424,7 +428,7 @@ func TestRegisterIpa(t *testing.T) {
 								PKInitServer:        true,
 							},
 						},
-						Locations:    []model.IpaLocation{},
+						Locations:    []ipa.IpaLocation{},
 						RealmDomains: pq.StringArray{},
 					},
 				},
@@ -436,7 +440,7 @@ func TestRegisterIpa(t *testing.T) {
 		t.Run(testCase.Name, func(t *testing.T) {
 			i := domainInteractor{}
 			orgID, clientVersion, output, tErr := i.Register(
-				testCase.Given.Secret,
+				[][]byte{testCase.Given.Secret},
 				testCase.Given.XRHID,
 				testCase.Given.Params,
 				testCase.Given.Body,
@@ -549,7 +553,7 @@ func TestRegisterDefaultValues(t *testing.T) {
 
 			// When
 			_, _, output, tErr := i.Register(
-				secret,
+				[][]byte{secret},
 				&xrhidSystem,
 				params,
 				domainRequest,
@@ -576,6 +580,110 @@ func TestRegisterDefaultValues(t *testing.T) {
 	}
 }
 
+func TestRegisterPreviousKey(t *testing.T) {
+	const orgID = "12345"
+	current := []byte("current token secret")
+	previous := []byte("previous token secret")
+	tok, _, err := domain_token.NewDomainRegistrationToken(
+		previous,
+		string(api_public.RhelIdm),
+		orgID,
+		time.Hour,
+	)
+	require.NoError(t, err)
+
+	xrhidSystem := createFakeSystemIdentity(orgID)
+	rhsmID := uuid.MustParse(xrhidSystem.Identity.System.CommonName)
+	params := createFakeRegisterDomainsParams(tok, createFakeXRHIDMVersion())
+	domainRequest := &api_public.Domain{
+		DomainName: "mydomain.example",
+		DomainType: api_public.RhelIdm,
+		RhelIdm: &api_public.DomainIpa{
+			RealmName: "MYDOMAIN.EXAMPLE",
+			Servers: []api_public.DomainIpaServer{
+				{
+					Fqdn:                  "server.mydomain.example",
+					SubscriptionManagerId: &rhsmID,
+					HccEnrollmentServer:   true,
+					HccUpdateServer:       true,
+				},
+			},
+		},
+	}
+	i := domainInteractor{}
+
+	// The token signed before the rotation of the main secret is valid
+	_, _, output, err := i.Register([][]byte{current, previous}, &xrhidSystem, params, domainRequest)
+	require.NoError(t, err)
+	assert.Equal(t, domain_token.TokenDomainId(tok), output.DomainUuid)
+
+	// The token is not valid once the previous secret is removed
+	_, _, output, err = i.Register([][]byte{current}, &xrhidSystem, params, domainRequest)
+	assert.EqualError(t, err, "code=401, message=Domain registration token is invalid: Signature mismatch, internal=IDMSVC-TOKEN-INVALID: Signature mismatch")
+	assert.Nil(t, output)
+}
+
+func TestRegisterTokenClaims(t *testing.T) {
+	const orgID = "12345"
+	secret := []byte("token secret")
+	xrhidSystem := createFakeSystemIdentity(orgID)
+	rhsmID := uuid.MustParse(xrhidSystem.Identity.System.CommonName)
+	domainRequest := &api_public.Domain{
+		DomainName: "mydomain.example",
+		DomainType: api_public.RhelIdm,
+		RhelIdm: &api_public.DomainIpa{
+			RealmName: "MYDOMAIN.EXAMPLE",
+			Servers: []api_public.DomainIpaServer{
+				{
+					Fqdn:                  "server.mydomain.example",
+					SubscriptionManagerId: &rhsmID,
+					HccEnrollmentServer:   true,
+					HccUpdateServer:       true,
+				},
+			},
+		},
+	}
+	register := func(claims domain_token.Claims) (*model.Domain, error) {
+		tok, _, err := domain_token.NewDomainRegistrationTokenWithClaims(
+			secret,
+			string(api_public.RhelIdm),
+			orgID,
+			time.Hour,
+			claims,
+		)
+		require.NoError(t, err)
+		params := createFakeRegisterDomainsParams(tok, createFakeXRHIDMVersion())
+		_, _, output, err := domainInteractor{}.Register([][]byte{secret}, &xrhidSystem, params, domainRequest)
+		return output, err
+	}
+
+	// The claims match the registration
+	output, err := register(domain_token.Claims{
+		DomainName:            "MyDomain.Example",
+		SubscriptionManagerId: rhsmID,
+		Label:                 "ticket 1234",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ticket 1234", *output.Description)
+
+	// The token is bound to other domain
+	output, err = register(domain_token.Claims{DomainName: "other.example"})
+	assert.EqualError(t, err, "code=403, message=Domain registration token is only valid for the domain 'other.example', internal=IDMSVC-TOKEN-CLAIMS")
+	assert.Nil(t, output)
+
+	// The token is bound to other server
+	otherID := uuid.MustParse("bc0d7a1e-4c5b-4c3e-9a6d-7f0e8d2b1a3c")
+	output, err = register(domain_token.Claims{SubscriptionManagerId: otherID})
+	assert.EqualError(t, err, "code=403, message=Domain registration token is only valid for the server 'bc0d7a1e-4c5b-4c3e-9a6d-7f0e8d2b1a3c', internal=IDMSVC-TOKEN-CLAIMS")
+	assert.Nil(t, output)
+
+	// The description of the request takes precedence over the label
+	domainRequest.Description = pointy.String("My Domain Description")
+	output, err = register(domain_token.Claims{Label: "ticket 1234"})
+	require.NoError(t, err)
+	assert.Equal(t, "My Domain Description", *output.Description)
+}
+
 func TestUpdateAgent(t *testing.T) {
 	const testOrgID = "12345"
 	testID := uuid.MustParse("658700b8-005b-11ee-9e09-482ae3863d30")
@@ -610,7 +718,7 @@ func TestUpdateAgent(t *testing.T) {
 	testBody := api_public.UpdateDomainAgentRequest{
 		DomainName: "mydomain.example",
 		DomainType: api_public.RhelIdm,
-		RhelIdm: api_public.DomainIpa{
+		RhelIdm: &api_public.DomainIpa{
 			RealmName:    "mydomain.example",
 			RealmDomains: []string{"mydomain.example"},
 			CaCerts:      []api_public.Certificate{},
@@ -731,7 +839,7 @@ func TestUpdateUser(t *testing.T) {
 		// other model.Domain fields are not st
 		assert.Nil(t, domain.DomainName)
 		assert.Nil(t, domain.Type)
-		assert.Nil(t, domain.IpaDomain)
+		assert.Nil(t, ipa.Data(domain))
 	})
 }
 
@@ -889,7 +997,7 @@ func TestTranslateUpdateDomainAgent(t *testing.T) {
 	agentRequest := public.UpdateDomainAgentRequest{
 		DomainName: "mydomain.example",
 		DomainType: api_public.RhelIdm,
-		RhelIdm: public.DomainIpa{
+		RhelIdm: &public.DomainIpa{
 			RealmName:    "mydomain.example",
 			RealmDomains: []string{"mydomain.example"},
 			CaCerts:      []api_public.Certificate{},
@@ -911,9 +1019,9 @@ func TestTranslateUpdateDomainAgent(t *testing.T) {
 		assert.Equal(t, "mydomain.example", *domain.DomainName)
 
 		// The domain type matches input (RhelIdm) and respected domain field is set
-		assert.Equal(t, model.DomainTypeIpa, *domain.Type)
-		assert.NotNil(t, domain.IpaDomain)
-		assert.Equal(t, "mydomain.example", *domain.IpaDomain.RealmName)
+		assert.Equal(t, ipa.TypeID, *domain.Type)
+		assert.NotNil(t, ipa.Data(domain))
+		assert.Equal(t, "mydomain.example", *ipa.Data(domain).RealmName)
 	})
 
 	t.Run("Invalid domain type", func(t *testing.T) {
@@ -927,6 +1035,55 @@ func TestTranslateUpdateDomainAgent(t *testing.T) {
 		require.EqualError(t, err, "Unsupported domain_type='invalid'")
 		assert.Nil(t, domain)
 	})
+
+	t.Run("Active Directory input", func(t *testing.T) {
+		// Given an active-directory request
+		testAgent := uuid.MustParse("6f324116-b3d2-11ed-8a37-482ae3863d30")
+		adRequest := public.UpdateDomainAgentRequest{
+			DomainName: "ad.example",
+			DomainType: api_public.ActiveDirectory,
+			ActiveDirectory: &public.DomainActiveDirectory{
+				RealmName:  "AD.EXAMPLE",
+				ForestName: "ad.example",
+				DomainControllers: []public.DomainActiveDirectoryController{
+					{Fqdn: "dc1.ad.example", GlobalCatalog: true, Site: pointy.String("boston")},
+				},
+				Sites: []public.Location{
+					{Name: "boston"},
+				},
+				UpdateAgents: []uuid.UUID{testAgent},
+			},
+		}
+
+		// When translateUpdateDomainAgent is called
+		domain, err := i.translateUpdateDomainAgent(testOrgID, testUUID, &adRequest)
+
+		// Then the active-directory fields are translated
+		require.NoError(t, err)
+		require.NotNil(t, domain)
+		assert.Equal(t, activedirectory.TypeID, *domain.Type)
+		assert.Nil(t, ipa.Data(domain))
+		domainAD := activedirectory.Data(domain)
+		require.NotNil(t, domainAD)
+		assert.Equal(t, "AD.EXAMPLE", *domainAD.RealmName)
+		assert.Equal(t, "ad.example", *domainAD.ForestName)
+		assert.Equal(t, pq.StringArray{testAgent.String()}, domainAD.UpdateAgents)
+		require.Len(t, domainAD.DomainControllers, 1)
+		assert.Equal(t, "dc1.ad.example", domainAD.DomainControllers[0].FQDN)
+		assert.True(t, domainAD.DomainControllers[0].GlobalCatalog)
+		require.Len(t, domainAD.Sites, 1)
+		assert.Equal(t, "boston", domainAD.Sites[0].Name)
+	})
+
+	t.Run("Active Directory without payload", func(t *testing.T) {
+		adRequest := public.UpdateDomainAgentRequest{
+			DomainName: "ad.example",
+			DomainType: api_public.ActiveDirectory,
+		}
+		domain, err := i.translateUpdateDomainAgent(testOrgID, testUUID, &adRequest)
+		require.EqualError(t, err, "code=500, message='active-directory' cannot be nil")
+		assert.Nil(t, domain)
+	})
 }
 
 func TestGetByID(t *testing.T) {
@@ -958,85 +1115,377 @@ func TestGetByID(t *testing.T) {
 	assert.Equal(t, testOrgID, orgID)
 }
 
-func TestRegisterOrUpdateRhelIdmLocations(t *testing.T) {
-	type TestCase struct {
-		Name     string
-		Given    *api_public.Domain
-		Expected *model.Ipa
+func TestExport(t *testing.T) {
+	i := NewDomainInteractor()
+	xrhid := identity.XRHID{Identity: identity.Identity{OrgID: "12345"}}
+
+	orgID, err := i.Export(nil, nil)
+	assert.EqualError(t, err, "code=500, message='xrhid' cannot be nil")
+	assert.Equal(t, "", orgID)
+
+	_, err = i.Export(&xrhid, nil)
+	assert.EqualError(t, err, "code=500, message='params' cannot be nil")
+
+	orgID, err = i.Export(&xrhid, &public.ExportDomainsParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", orgID)
+}
+
+func helperDomainExport(t *testing.T, items ...public.DomainExportItem) *public.DomainExport {
+	doc := &public.DomainExport{
+		Version: domainexport.Version,
+		OrgId:   "54321",
+		Domains: items,
 	}
-	testCases := []TestCase{
-		{
-			Name: "nil Locations",
-			Given: &api_public.Domain{
-				RhelIdm: &api_public.DomainIpa{
-					Locations: nil,
-				},
-			},
-			Expected: &model.Ipa{
-				Locations: []model.IpaLocation{},
+	checksum, err := domainexport.Checksum(doc.Domains)
+	require.NoError(t, err)
+	doc.Checksum = checksum
+	return doc
+}
+
+func TestImport(t *testing.T) {
+	i := NewDomainInteractor()
+	domainID := uuid.MustParse("c5d2c9c4-e4a3-4bf6-b9ae-0b4bf5a4b8a6")
+	item := public.DomainExportItem{
+		Domain: public.Domain{
+			DomainId:              &domainID,
+			DomainName:            "mydomain.example",
+			DomainType:            public.RhelIdm,
+			Title:                 pointy.String("My domain"),
+			AutoEnrollmentEnabled: pointy.Bool(true),
+			RhelIdm: &public.DomainIpa{
+				RealmName:    "MYDOMAIN.EXAMPLE",
+				RealmDomains: []string{"mydomain.example"},
+				CaCerts:      []public.Certificate{},
+				Servers:      []public.DomainIpaServer{},
 			},
 		},
-		{
-			Name: "Empty Locations slice",
-			Given: &api_public.Domain{
-				RhelIdm: &api_public.DomainIpa{
-					Locations: []api_public.Location{},
-				},
-			},
-			Expected: &model.Ipa{
-				Locations: []model.IpaLocation{},
-			},
+		LocationSubnets: []public.LocationSubnet{
+			{Subnet: "192.0.2.10/24", Location: "boston"},
 		},
-		{
-			Name: "Location without description",
-			Given: &api_public.Domain{
-				RhelIdm: &api_public.DomainIpa{
-					Locations: []api_public.Location{
-						{
-							Name:        "boston",
-							Description: nil,
-						},
-					},
-				},
-			},
-			Expected: &model.Ipa{
-				Locations: []model.IpaLocation{
-					{
-						Name:        "boston",
-						Description: nil,
-					},
-				},
-			},
+	}
+
+	data, subnets, err := i.Import("", nil)
+	assert.EqualError(t, err, "code=400, message='orgID' cannot be empty")
+	assert.Nil(t, data)
+	assert.Nil(t, subnets)
+
+	_, _, err = i.Import("12345", nil)
+	assert.EqualError(t, err, "code=500, message='doc' cannot be nil")
+
+	doc := helperDomainExport(t, item)
+	doc.Checksum = "sha256:0"
+	_, _, err = i.Import("12345", doc)
+	assert.EqualError(t, err, "document checksum does not match, the document was modified or it is incomplete")
+
+	invalid := item
+	invalid.Domain.DomainId = nil
+	_, _, err = i.Import("12345", helperDomainExport(t, invalid))
+	assert.EqualError(t, err, "'domains[0].domain.domain_id' is invalid")
+
+	_, _, err = i.Import("12345", helperDomainExport(t, item, item))
+	assert.EqualError(t, err, "'domains[1].domain.domain_id' duplicates domain 'c5d2c9c4-e4a3-4bf6-b9ae-0b4bf5a4b8a6'")
+
+	invalid = item
+	invalid.Domain.DomainType = "unknown"
+	_, _, err = i.Import("12345", helperDomainExport(t, invalid))
+	assert.EqualError(t, err, "'domains[0].domain' is invalid: Unsupported domain_type='unknown'")
+
+	invalid = item
+	invalid.LocationSubnets = []public.LocationSubnet{{Subnet: "192.0.2.0/24"}}
+	_, _, err = i.Import("12345", helperDomainExport(t, invalid))
+	assert.EqualError(t, err, "'domains[0].location_subnets' is invalid: code=400, message='subnets[0].location' cannot be empty")
+
+	// Success; the domains are imported into the given organization
+	data, subnets, err = i.Import("12345", helperDomainExport(t, item))
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, "12345", data[0].OrgId)
+	assert.Equal(t, domainID, data[0].DomainUuid)
+	assert.Equal(t, pointy.String("mydomain.example"), data[0].DomainName)
+	assert.Equal(t, pointy.String("My domain"), data[0].Title)
+	assert.Equal(t, pointy.Bool(true), data[0].AutoEnrollmentEnabled)
+	assert.Equal(t, pointy.Uint(ipa.TypeID), data[0].Type)
+	require.NotNil(t, ipa.Data(&data[0]))
+	assert.Equal(t, pointy.String("MYDOMAIN.EXAMPLE"), ipa.Data(&data[0]).RealmName)
+	assert.Equal(t, map[uuid.UUID][]model.DomainLocationSubnet{
+		domainID: {{Subnet: "192.0.2.0/24", Location: "boston"}},
+	}, subnets)
+	assert.Equal(t, model.DomainRegistrationActive, data[0].RegistrationState)
+	assert.Nil(t, data[0].Registration)
+
+	invalid = item
+	invalid.Domain.RegistrationState = (*public.RegistrationState)(pointy.String("unknown"))
+	_, _, err = i.Import("12345", helperDomainExport(t, invalid))
+	assert.EqualError(t, err, "'domains[0].domain' is invalid: 'registration_state' is invalid")
+
+	// The registration state and record are kept
+	registeredAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rhsmID := uuid.New()
+	pending := item
+	pending.Domain.RegistrationState = (*public.RegistrationState)(pointy.String(string(public.PendingApproval)))
+	pending.Domain.Registration = &public.DomainRegistration{
+		SubscriptionManagerId: rhsmID,
+		Fqdn:                  pointy.String("server1.mydomain.example"),
+		IpaHccVersion:         "0.12",
+		IpaVersion:            "4.10.0",
+		OsReleaseId:           "rhel",
+		OsReleaseVersionId:    "9.2",
+		CaCerts: []public.Certificate{
+			{Nickname: "MYDOMAIN.EXAMPLE IPA CA", Pem: "-----BEGIN CERTIFICATE-----"},
 		},
-		{
-			Name: "Location with description",
-			Given: &api_public.Domain{
-				RhelIdm: &api_public.DomainIpa{
-					Locations: []api_public.Location{
-						{
-							Name:        "boston",
-							Description: pointy.String("Boston data center"),
-						},
-					},
-				},
-			},
-			Expected: &model.Ipa{
-				Locations: []model.IpaLocation{
-					{
-						Name:        "boston",
-						Description: pointy.String("Boston data center"),
-					},
-				},
+		RegisteredAt: registeredAt,
+	}
+	data, _, err = i.Import("12345", helperDomainExport(t, pending))
+	require.NoError(t, err)
+	require.Len(t, data, 1)
+	assert.Equal(t, model.DomainRegistrationPendingApproval, data[0].RegistrationState)
+	assert.Equal(t, &model.DomainRegistration{
+		CreatedAt:             registeredAt,
+		SubscriptionManagerID: rhsmID,
+		FQDN:                  pointy.String("server1.mydomain.example"),
+		IpaHccVersion:         "0.12",
+		IpaVersion:            "4.10.0",
+		OsReleaseID:           "rhel",
+		OsReleaseVersionID:    "9.2",
+		CaCerts: []model.RegistrationCert{
+			{Nickname: "MYDOMAIN.EXAMPLE IPA CA", Pem: "-----BEGIN CERTIFICATE-----"},
+		},
+	}, data[0].Registration)
+}
+
+func TestReadLocationSubnets(t *testing.T) {
+	i := NewDomainInteractor()
+	UUID := uuid.New()
+	xrhid := identity.XRHID{Identity: identity.Identity{OrgID: "12345"}}
+
+	orgID, err := i.ReadLocationSubnets(nil, UUID, nil)
+	assert.EqualError(t, err, "code=500, message='xrhid' cannot be nil")
+	assert.Equal(t, "", orgID)
+
+	orgID, err = i.ReadLocationSubnets(&xrhid, uuid.Nil, nil)
+	assert.EqualError(t, err, "'UUID' is invalid")
+	assert.Equal(t, "", orgID)
+
+	orgID, err = i.ReadLocationSubnets(&xrhid, UUID, nil)
+	assert.EqualError(t, err, "code=500, message='params' cannot be nil")
+	assert.Equal(t, "", orgID)
+
+	orgID, err = i.ReadLocationSubnets(&xrhid, UUID, &public.ReadDomainLocationSubnetsParams{})
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", orgID)
+}
+
+func TestUpdateLocationSubnets(t *testing.T) {
+	i := NewDomainInteractor()
+	UUID := uuid.New()
+	xrhid := identity.XRHID{Identity: identity.Identity{OrgID: "12345"}}
+	params := &public.UpdateDomainLocationSubnetsParams{}
+
+	orgID, data, err := i.UpdateLocationSubnets(nil, UUID, nil, nil)
+	assert.EqualError(t, err, "code=500, message='xrhid' cannot be nil")
+	assert.Equal(t, "", orgID)
+	assert.Nil(t, data)
+
+	_, _, err = i.UpdateLocationSubnets(&xrhid, UUID, nil, nil)
+	assert.EqualError(t, err, "code=500, message='params' cannot be nil")
+
+	_, _, err = i.UpdateLocationSubnets(&xrhid, UUID, params, nil)
+	assert.EqualError(t, err, "code=500, message='body' cannot be nil")
+
+	_, _, err = i.UpdateLocationSubnets(&xrhid, UUID, params, &public.LocationSubnets{
+		Subnets: []public.LocationSubnet{{Subnet: "192.0.2.0", Location: "alpha"}},
+	})
+	assert.EqualError(t, err, `code=400, message='subnets[0].subnet' is not a valid subnet: netip.ParsePrefix("192.0.2.0"): no '/'`)
+
+	_, _, err = i.UpdateLocationSubnets(&xrhid, UUID, params, &public.LocationSubnets{
+		Subnets: []public.LocationSubnet{
+			{Subnet: "192.0.2.0/24", Location: "alpha"},
+			{Subnet: "192.0.2.1/24", Location: "beta"},
+		},
+	})
+	assert.EqualError(t, err, "code=400, message='subnets[1].subnet' duplicates subnet '192.0.2.0/24'")
+
+	_, _, err = i.UpdateLocationSubnets(&xrhid, UUID, params, &public.LocationSubnets{
+		Subnets: []public.LocationSubnet{{Subnet: "192.0.2.0/24", Location: ""}},
+	})
+	assert.EqualError(t, err, "code=400, message='subnets[0].location' cannot be empty")
+
+	orgID, data, err = i.UpdateLocationSubnets(&xrhid, UUID, params, &public.LocationSubnets{
+		Subnets: []public.LocationSubnet{
+			{Subnet: "192.0.2.10/24", Location: "alpha"},
+			{Subnet: "2001:db8::/32", Location: "beta"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", orgID)
+	assert.Equal(t, []model.DomainLocationSubnet{
+		{Subnet: "192.0.2.0/24", Location: "alpha"},
+		{Subnet: "2001:db8::/32", Location: "beta"},
+	}, data)
+
+	// An empty list clears the mappings
+	_, data, err = i.UpdateLocationSubnets(&xrhid, UUID, params, &public.LocationSubnets{})
+	assert.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestApproveDomain(t *testing.T) {
+	i := NewDomainInteractor()
+	UUID := uuid.New()
+	xrhid := test.UserXRHID
+	params := &public.ApproveDomainParams{}
+
+	orgID, decision, err := i.ApproveDomain(nil, UUID, nil, nil)
+	assert.EqualError(t, err, "code=500, message='xrhid' cannot be nil")
+	assert.Equal(t, "", orgID)
+	assert.Nil(t, decision)
+
+	_, _, err = i.ApproveDomain(&xrhid, uuid.Nil, nil, nil)
+	assert.EqualError(t, err, "'UUID' is invalid")
+
+	_, _, err = i.ApproveDomain(&xrhid, UUID, nil, nil)
+	assert.EqualError(t, err, "code=500, message='params' cannot be nil")
+
+	_, _, err = i.ApproveDomain(&identity.XRHID{}, UUID, params, nil)
+	assert.EqualError(t, err, "'xrhid' has no user or service account")
+
+	orgID, decision, err = i.ApproveDomain(&xrhid, UUID, params, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, xrhid.Identity.OrgID, orgID)
+	require.NotNil(t, decision)
+	assert.Equal(t, pointy.String(xrhid.Identity.User.Username), decision.DecidedBy)
+	require.NotNil(t, decision.DecidedAt)
+	assert.WithinDuration(t, time.Now(), *decision.DecidedAt, time.Minute)
+	assert.Nil(t, decision.Reason)
+
+	_, decision, err = i.ApproveDomain(&xrhid, UUID, params, &public.DomainDecisionRequest{
+		Reason: pointy.String("  "),
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, decision.Reason)
+}
+
+func TestRejectDomain(t *testing.T) {
+	i := NewDomainInteractor()
+	UUID := uuid.New()
+	xrhid := identity.XRHID{Identity: identity.Identity{
+		OrgID: "12345",
+		ServiceAccount: &identity.ServiceAccount{
+			ClientId: "b69eaf9e-e6a6-4f9e-805e-02987daddfbd",
+			Username: "service-account-b69eaf9e-e6a6-4f9e-805e-02987daddfbd",
+		},
+	}}
+	params := &public.RejectDomainParams{}
+
+	orgID, decision, err := i.RejectDomain(nil, UUID, nil, nil)
+	assert.EqualError(t, err, "code=500, message='xrhid' cannot be nil")
+	assert.Equal(t, "", orgID)
+	assert.Nil(t, decision)
+
+	_, _, err = i.RejectDomain(&xrhid, UUID, nil, nil)
+	assert.EqualError(t, err, "code=500, message='params' cannot be nil")
+
+	orgID, decision, err = i.RejectDomain(&xrhid, UUID, params, &public.DomainDecisionRequest{
+		Reason: pointy.String(" unknown server "),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "12345", orgID)
+	require.NotNil(t, decision)
+	assert.Equal(t, pointy.String(xrhid.Identity.ServiceAccount.Username), decision.DecidedBy)
+	assert.NotNil(t, decision.DecidedAt)
+	assert.Equal(t, pointy.String("unknown server"), decision.Reason)
+}
+
+func TestBatch(t *testing.T) {
+	i := NewDomainInteractor()
+	UUID := uuid.New()
+	xrhid := identity.XRHID{Identity: identity.Identity{OrgID: "12345"}}
+	params := &public.BatchDomainsParams{}
+
+	orgID, atomic, data, err := i.Batch(nil, nil, nil)
+	assert.EqualError(t, err, "code=500, message='xrhid' cannot be nil")
+	assert.Equal(t, "", orgID)
+	assert.False(t, atomic)
+	assert.Nil(t, data)
+
+	_, _, _, err = i.Batch(&xrhid, nil, nil)
+	assert.EqualError(t, err, "code=500, message='params' cannot be nil")
+
+	_, _, _, err = i.Batch(&xrhid, params, nil)
+	assert.EqualError(t, err, "code=500, message='body' cannot be nil")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{})
+	assert.EqualError(t, err, "code=400, message='operations' must have from 1 to 100 items")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: make([]public.BatchDomainOperation, 101),
+	})
+	assert.EqualError(t, err, "code=400, message='operations' must have from 1 to 100 items")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{{Op: public.Delete}},
+	})
+	assert.EqualError(t, err, "code=400, message='operations[0]' is invalid: 'domain_id' is invalid")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{{Op: "rename", DomainId: UUID}},
+	})
+	assert.EqualError(t, err, "code=400, message='operations[0]' is invalid: 'op' is not supported: 'rename'")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{{Op: public.Update, DomainId: UUID}},
+	})
+	assert.EqualError(t, err, "code=400, message='operations[0]' is invalid: 'update' is required for the 'update' operation")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{{
+			Op:       public.Delete,
+			DomainId: UUID,
+			Update:   &public.UpdateDomainUserRequest{},
+		}},
+	})
+	assert.EqualError(t, err, "code=400, message='operations[0]' is invalid: 'update' is only allowed for the 'update' operation")
+
+	_, _, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{{
+			Op:       public.Update,
+			DomainId: UUID,
+			Update:   &public.UpdateDomainUserRequest{Title: pointy.String("")},
+		}},
+	})
+	assert.EqualError(t, err, "code=400, message='operations[0]' is invalid: 'title' cannot be empty")
+
+	// Success
+	orgID, atomic, data, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Atomic: pointy.Bool(false),
+		Operations: []public.BatchDomainOperation{
+			{
+				Op:       public.Update,
+				DomainId: UUID,
+				Update:   &public.UpdateDomainUserRequest{Title: pointy.String("My domain")},
 			},
+			{Op: public.Enable, DomainId: UUID},
+			{Op: public.Disable, DomainId: UUID},
+			{Op: public.Delete, DomainId: UUID},
 		},
-	}
-	i := domainInteractor{}
-	for _, item := range testCases {
-		t.Log(item.Name)
-		ipa := &model.Ipa{}
-		i.translateIdmLocations(item.Given.RhelIdm, ipa)
-		assert.Equal(t, item.Expected, ipa)
-	}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "12345", orgID)
+	assert.False(t, atomic)
+	assert.Equal(t, []*model.Domain{
+		{OrgId: "12345", DomainUuid: UUID, Title: pointy.String("My domain")},
+		{OrgId: "12345", DomainUuid: UUID, AutoEnrollmentEnabled: pointy.Bool(true)},
+		{OrgId: "12345", DomainUuid: UUID, AutoEnrollmentEnabled: pointy.Bool(false)},
+		{OrgId: "12345", DomainUuid: UUID},
+	}, data)
+
+	// The operations are atomic by default
+	_, atomic, _, err = i.Batch(&xrhid, params, &public.BatchDomainsRequest{
+		Operations: []public.BatchDomainOperation{{Op: public.Delete, DomainId: UUID}},
+	})
+	require.NoError(t, err)
+	assert.True(t, atomic)
 }
 
 func TestCreateDomainToken(t *testing.T) {
@@ -1064,6 +1513,7 @@ func TestCreateDomainToken(t *testing.T) {
 	type TestCaseExpected struct {
 		OrgID      string
 		DomainType public.DomainType
+		Claims     domain_token.Claims
 		Err        error
 	}
 	type TestCase struct {
@@ -1071,6 +1521,7 @@ func TestCreateDomainToken(t *testing.T) {
 		Given    TestCaseGiven
 		Expected TestCaseExpected
 	}
+	rhsmID := uuid.MustParse("bc0d7a1e-4c5b-4c3e-9a6d-7f0e8d2b1a3c")
 	testCases := []TestCase{
 		{
 			Name: "nil 'xrhid'",
@@ -1133,12 +1584,96 @@ func TestCreateDomainToken(t *testing.T) {
 				Err:        nil,
 			},
 		},
+		{
+			Name: "empty 'domain_name' claim",
+			Given: TestCaseGiven{
+				XRHID:  &xrhidUser,
+				Params: &api_public.CreateDomainTokenParams{},
+				Body: &api_public.DomainRegTokenRequest{
+					DomainType: api_public.RhelIdm,
+					DomainName: pointy.String(""),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: internal_errors.NewHTTPErrorF(http.StatusBadRequest, "'domain_name' is invalid"),
+			},
+		},
+		{
+			Name: "nil 'subscription_manager_id' claim",
+			Given: TestCaseGiven{
+				XRHID:  &xrhidUser,
+				Params: &api_public.CreateDomainTokenParams{},
+				Body: &api_public.DomainRegTokenRequest{
+					DomainType:            api_public.RhelIdm,
+					SubscriptionManagerId: &uuid.Nil,
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: internal_errors.NewHTTPErrorF(http.StatusBadRequest, "'subscription_manager_id' is invalid"),
+			},
+		},
+		{
+			Name: "too long 'label' claim",
+			Given: TestCaseGiven{
+				XRHID:  &xrhidUser,
+				Params: &api_public.CreateDomainTokenParams{},
+				Body: &api_public.DomainRegTokenRequest{
+					DomainType: api_public.RhelIdm,
+					Label:      pointy.String(strings.Repeat("x", domain_token.LabelMaxLength+1)),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: internal_errors.NewHTTPErrorF(http.StatusBadRequest, "'label' exceeds 64 characters"),
+			},
+		},
+		{
+			Name: "'label' claim with multi-byte characters",
+			Given: TestCaseGiven{
+				XRHID:  &xrhidUser,
+				Params: &api_public.CreateDomainTokenParams{},
+				Body: &api_public.DomainRegTokenRequest{
+					DomainType: api_public.RhelIdm,
+					Label:      pointy.String(strings.Repeat("ä", domain_token.LabelMaxLength)),
+				},
+			},
+			Expected: TestCaseExpected{
+				OrgID:      testOrgID,
+				DomainType: api_public.RhelIdm,
+				Claims: domain_token.Claims{
+					Label: strings.Repeat("ä", domain_token.LabelMaxLength),
+				},
+				Err: nil,
+			},
+		},
+		{
+			Name: "success case with claims",
+			Given: TestCaseGiven{
+				XRHID:  &xrhidUser,
+				Params: &api_public.CreateDomainTokenParams{},
+				Body: &api_public.DomainRegTokenRequest{
+					DomainType:            api_public.RhelIdm,
+					DomainName:            pointy.String("MyDomain.Example"),
+					SubscriptionManagerId: &rhsmID,
+					Label:                 pointy.String("ticket 1234"),
+				},
+			},
+			Expected: TestCaseExpected{
+				OrgID:      testOrgID,
+				DomainType: api_public.RhelIdm,
+				Claims: domain_token.Claims{
+					DomainName:            "mydomain.example",
+					SubscriptionManagerId: rhsmID,
+					Label:                 "ticket 1234",
+				},
+				Err: nil,
+			},
+		},
 	}
 
 	component := NewDomainInteractor()
 	for _, testCase := range testCases {
 		t.Log(testCase.Name)
-		orgID, domainType, err := component.CreateDomainToken(
+		orgID, domainType, claims, err := component.CreateDomainToken(
 			testCase.Given.XRHID,
 			testCase.Given.Params,
 			testCase.Given.Body,
@@ -1148,9 +1683,11 @@ func TestCreateDomainToken(t *testing.T) {
 			require.Equal(t, testCase.Expected.Err.Error(), err.Error())
 			assert.Equal(t, "", orgID)
 			assert.Equal(t, public.DomainType(""), domainType)
+			assert.Equal(t, domain_token.Claims{}, claims)
 		} else {
 			assert.Equal(t, testCase.Expected.OrgID, orgID)
 			assert.Equal(t, testCase.Expected.DomainType, domainType)
+			assert.Equal(t, testCase.Expected.Claims, claims)
 			assert.NoError(t, err)
 		}
 	}
@@ -2,6 +2,9 @@ package interactor
 
 import (
 	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
 
 	"github.com/google/uuid"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
@@ -51,6 +54,48 @@ func (i hostInteractor) HostConf(
 		DomainId:    body.DomainId,
 		DomainName:  body.DomainName,
 		DomainType:  body.DomainType,
+		Location:    body.Location,
+	}
+	if body.LocationPolicy != nil {
+		switch *body.LocationPolicy {
+		case api_public.Prefer:
+		case api_public.Restrict:
+			options.RestrictLocation = true
+		default:
+			return nil, internal_errors.NewHTTPErrorF(
+				http.StatusBadRequest,
+				"'location_policy' is invalid",
+			)
+		}
+	}
+	if body.ClientSubnet != nil {
+		clientSubnet, err := parseClientSubnet(*body.ClientSubnet)
+		if err != nil {
+			return nil, internal_errors.NewHTTPErrorF(
+				http.StatusBadRequest,
+				"'client_subnet' is not a valid address or subnet: %s",
+				err.Error(),
+			)
+		}
+		options.ClientSubnet = &clientSubnet
 	}
 	return options, nil
 }
+
+// parseClientSubnet accepts an IP address or a subnet in CIDR
+// notation; an address is handled as a single host subnet.
+func parseClientSubnet(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
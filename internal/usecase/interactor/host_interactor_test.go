@@ -2,6 +2,7 @@ package interactor
 
 import (
 	"fmt"
+	"net/netip"
 	"testing"
 
 	"github.com/google/uuid"
@@ -159,6 +160,127 @@ func TestHostConf(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "valid call with location hints",
+			Given: TestCaseGiven{
+				XRHID:       &test.Client1XRHID,
+				InventoryId: test.Client1.InventoryUUID,
+				Fqdn:        test.Client1.Fqdn,
+				Params:      &api_public.HostConfParams{},
+				Body: &api_public.HostConf{
+					Location:     pointy.String("alpha"),
+					ClientSubnet: pointy.String("192.0.2.10"),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				Out: &interactor.HostConfOptions{
+					OrgId:        test.OrgId,
+					CommonName:   test.Client1.CertUUID,
+					InventoryId:  test.Client1.InventoryUUID,
+					Fqdn:         test.Client1.Fqdn,
+					Location:     pointy.String("alpha"),
+					ClientSubnet: pointy.Pointer(netip.MustParsePrefix("192.0.2.10/32")),
+				},
+			},
+		},
+		{
+			Name: "valid call with client subnet",
+			Given: TestCaseGiven{
+				XRHID:       &test.Client1XRHID,
+				InventoryId: test.Client1.InventoryUUID,
+				Fqdn:        test.Client1.Fqdn,
+				Params:      &api_public.HostConfParams{},
+				Body: &api_public.HostConf{
+					ClientSubnet: pointy.String("2001:db8::1/64"),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				Out: &interactor.HostConfOptions{
+					OrgId:        test.OrgId,
+					CommonName:   test.Client1.CertUUID,
+					InventoryId:  test.Client1.InventoryUUID,
+					Fqdn:         test.Client1.Fqdn,
+					ClientSubnet: pointy.Pointer(netip.MustParsePrefix("2001:db8::/64")),
+				},
+			},
+		},
+		{
+			Name: "invalid client subnet",
+			Given: TestCaseGiven{
+				XRHID:       &test.Client1XRHID,
+				InventoryId: test.Client1.InventoryUUID,
+				Fqdn:        test.Client1.Fqdn,
+				Params:      &api_public.HostConfParams{},
+				Body: &api_public.HostConf{
+					ClientSubnet: pointy.String("192.0.2.300"),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: fmt.Errorf(`code=400, message='client_subnet' is not a valid address or subnet: ParseAddr("192.0.2.300"): IPv4 field has value >255`),
+				Out: nil,
+			},
+		},
+		{
+			Name: "valid call restricted to the location",
+			Given: TestCaseGiven{
+				XRHID:       &test.Client1XRHID,
+				InventoryId: test.Client1.InventoryUUID,
+				Fqdn:        test.Client1.Fqdn,
+				Params:      &api_public.HostConfParams{},
+				Body: &api_public.HostConf{
+					LocationPolicy: pointy.Pointer(api_public.Restrict),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				Out: &interactor.HostConfOptions{
+					OrgId:            test.OrgId,
+					CommonName:       test.Client1.CertUUID,
+					InventoryId:      test.Client1.InventoryUUID,
+					Fqdn:             test.Client1.Fqdn,
+					RestrictLocation: true,
+				},
+			},
+		},
+		{
+			Name: "valid call preferring the location",
+			Given: TestCaseGiven{
+				XRHID:       &test.Client1XRHID,
+				InventoryId: test.Client1.InventoryUUID,
+				Fqdn:        test.Client1.Fqdn,
+				Params:      &api_public.HostConfParams{},
+				Body: &api_public.HostConf{
+					LocationPolicy: pointy.Pointer(api_public.Prefer),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: nil,
+				Out: &interactor.HostConfOptions{
+					OrgId:       test.OrgId,
+					CommonName:  test.Client1.CertUUID,
+					InventoryId: test.Client1.InventoryUUID,
+					Fqdn:        test.Client1.Fqdn,
+				},
+			},
+		},
+		{
+			Name: "invalid location policy",
+			Given: TestCaseGiven{
+				XRHID:       &test.Client1XRHID,
+				InventoryId: test.Client1.InventoryUUID,
+				Fqdn:        test.Client1.Fqdn,
+				Params:      &api_public.HostConfParams{},
+				Body: &api_public.HostConf{
+					LocationPolicy: pointy.Pointer(api_public.LocationPolicy("nearby")),
+				},
+			},
+			Expected: TestCaseExpected{
+				Err: fmt.Errorf("code=400, message='location_policy' is invalid"),
+				Out: nil,
+			},
+		},
 	}
 
 	component := NewHostInteractor()
@@ -179,6 +301,9 @@ func TestHostConf(t *testing.T) {
 			require.Equal(t, testCase.Expected.Out.DomainId, options.DomainId)
 			require.Equal(t, testCase.Expected.Out.DomainName, options.DomainName)
 			require.Equal(t, testCase.Expected.Out.DomainType, options.DomainType)
+			require.Equal(t, testCase.Expected.Out.Location, options.Location)
+			require.Equal(t, testCase.Expected.Out.ClientSubnet, options.ClientSubnet)
+			require.Equal(t, testCase.Expected.Out.RestrictLocation, options.RestrictLocation)
 		}
 	}
 }
@@ -0,0 +1,253 @@
+package interactor
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/webhook"
+	identity "github.com/redhatinsights/platform-go-middlewares/v2/identity"
+	"go.openly.dev/pointy"
+)
+
+const (
+	// webhookDeliveriesDefaultLimit is the number of deliveries
+	// listed when the limit is not provided.
+	webhookDeliveriesDefaultLimit = 20
+	// webhookDeliveriesMaxLimit is the max number of deliveries
+	// listed.
+	webhookDeliveriesMaxLimit = 100
+)
+
+type webhookInteractor struct {
+	allowHTTP    bool
+	allowPrivate bool
+	secrets      *secrets.AppSecrets
+}
+
+// NewWebhookInteractor Create an interactor for the /webhooks endpoint
+// handlers.
+// cfg is the application configuration.
+// Return an initialized instance of interactor.WebhookInteractor
+func NewWebhookInteractor(cfg *config.Config) interactor.WebhookInteractor {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	return webhookInteractor{
+		allowHTTP:    cfg.Application.Webhooks.AllowHTTP,
+		allowPrivate: cfg.Application.Webhooks.AllowPrivateNetworks,
+		secrets:      &cfg.Secrets,
+	}
+}
+
+// List translate the input of the GET /webhooks endpoint.
+// xrhid is the identity of the request.
+// params is the endpoint parameters.
+// Return the organization id and nil error on success, else an empty
+// organization id and an error.
+func (i webhookInteractor) List(
+	xrhid *identity.XRHID,
+	params *api_public.ListWebhooksParams,
+) (orgID string, err error) {
+	if xrhid == nil {
+		return "", internal_errors.NilArgError("xrhid")
+	}
+	if params == nil {
+		return "", internal_errors.NilArgError("params")
+	}
+	return xrhid.Identity.OrgID, nil
+}
+
+// Create translate the input of the POST /webhooks endpoint into the
+// webhook to create, with a new signing secret.
+// xrhid is the identity of the request.
+// params is the endpoint parameters.
+// body is the webhook to create.
+// Return the organization id, the webhook and nil error on success,
+// else an empty organization id, nil and an error.
+func (i webhookInteractor) Create(
+	xrhid *identity.XRHID,
+	params *api_public.CreateWebhookParams,
+	body *api_public.CreateWebhookRequest,
+) (orgID string, data *model.Webhook, err error) {
+	if xrhid == nil {
+		return "", nil, internal_errors.NilArgError("xrhid")
+	}
+	if params == nil {
+		return "", nil, internal_errors.NilArgError("params")
+	}
+	if body == nil {
+		return "", nil, internal_errors.NilArgError("body")
+	}
+	if err = i.guardURL(body.Url); err != nil {
+		return "", nil, err
+	}
+	if len(body.Events) == 0 {
+		return "", nil, internal_errors.EmptyArgError("events")
+	}
+	orgID = xrhid.Identity.OrgID
+	data = &model.Webhook{
+		OrgId:   orgID,
+		URL:     body.Url,
+		Events:  i.events(body.Events),
+		Enabled: pointy.Bool(pointy.BoolValue(body.Enabled, true)),
+	}
+	if err = webhook.NewSecret(i.secrets, data); err != nil {
+		return "", nil, err
+	}
+	return orgID, data, nil
+}
+
+// GetByID translate the input of the GET /webhooks/{uuid} endpoint.
+// xrhid is the identity of the request.
+// UUID is the webhook uuid.
+// params is the endpoint parameters.
+// Return the organization id and nil error on success, else an empty
+// organization id and an error.
+func (i webhookInteractor) GetByID(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.ReadWebhookParams,
+) (orgID string, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", err
+	}
+	if params == nil {
+		return "", internal_errors.NilArgError("params")
+	}
+	return xrhid.Identity.OrgID, nil
+}
+
+// Update translate the input of the PATCH /webhooks/{uuid} endpoint
+// into the changes of the webhook; the fields which are not updated
+// are left empty.
+// xrhid is the identity of the request.
+// UUID is the webhook uuid.
+// params is the endpoint parameters.
+// body is the changes of the webhook.
+// Return the organization id, the changes and nil error on success,
+// else an empty organization id, nil and an error.
+func (i webhookInteractor) Update(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.UpdateWebhookParams,
+	body *api_public.UpdateWebhookRequest,
+) (orgID string, data *model.Webhook, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", nil, err
+	}
+	if params == nil {
+		return "", nil, internal_errors.NilArgError("params")
+	}
+	if body == nil {
+		return "", nil, internal_errors.NilArgError("body")
+	}
+	orgID = xrhid.Identity.OrgID
+	data = &model.Webhook{
+		OrgId:       orgID,
+		WebhookUuid: UUID,
+	}
+	if body.Url != nil {
+		if err = i.guardURL(*body.Url); err != nil {
+			return "", nil, err
+		}
+		data.URL = *body.Url
+	}
+	if body.Events != nil {
+		if len(*body.Events) == 0 {
+			return "", nil, internal_errors.EmptyArgError("events")
+		}
+		data.Events = i.events(*body.Events)
+	}
+	if body.Enabled != nil {
+		data.Enabled = pointy.Bool(*body.Enabled)
+	}
+	return orgID, data, nil
+}
+
+// Delete translate the input of the DELETE /webhooks/{uuid} endpoint.
+// xrhid is the identity of the request.
+// UUID is the webhook uuid.
+// params is the endpoint parameters.
+// Return the organization id and nil error on success, else an empty
+// organization id and an error.
+func (i webhookInteractor) Delete(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.DeleteWebhookParams,
+) (orgID string, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", err
+	}
+	if params == nil {
+		return "", internal_errors.NilArgError("params")
+	}
+	return xrhid.Identity.OrgID, nil
+}
+
+// ListDeliveries translate the input of the GET
+// /webhooks/{uuid}/deliveries endpoint.
+// xrhid is the identity of the request.
+// UUID is the webhook uuid.
+// params is the endpoint parameters.
+// Return the organization id, the number of deliveries to list and
+// nil error on success, else an empty organization id, zero and an
+// error.
+func (i webhookInteractor) ListDeliveries(
+	xrhid *identity.XRHID,
+	UUID uuid.UUID,
+	params *api_public.ListWebhookDeliveriesParams,
+) (orgID string, limit int, err error) {
+	if err = i.guardXrhidUUID(xrhid, UUID); err != nil {
+		return "", 0, err
+	}
+	if params == nil {
+		return "", 0, internal_errors.NilArgError("params")
+	}
+	limit = pointy.IntValue(params.Limit, webhookDeliveriesDefaultLimit)
+	if limit <= 0 {
+		limit = webhookDeliveriesDefaultLimit
+	}
+	if limit > webhookDeliveriesMaxLimit {
+		limit = webhookDeliveriesMaxLimit
+	}
+	return xrhid.Identity.OrgID, limit, nil
+}
+
+func (i webhookInteractor) guardXrhidUUID(xrhid *identity.XRHID, UUID uuid.UUID) error {
+	if xrhid == nil {
+		return internal_errors.NilArgError("xrhid")
+	}
+	if UUID == uuid.Nil {
+		return fmt.Errorf("'UUID' is invalid")
+	}
+	return nil
+}
+
+// guardURL check the webhook url, and return an
+// internal_errors.ErrWebhookURLInvalid error when it is not valid.
+func (i webhookInteractor) guardURL(rawURL string) error {
+	if err := webhook.ValidateURL(rawURL, i.allowHTTP, i.allowPrivate); err != nil {
+		return internal_errors.ErrWebhookURLInvalid.New(err, "%s", err.Error())
+	}
+	return nil
+}
+
+// events translate the subscribed events, discarding the duplicates.
+func (i webhookInteractor) events(events api_public.WebhookEvents) []string {
+	result := make([]string, 0, len(events))
+	seen := map[api_public.WebhookEvent]bool{}
+	for _, event := range events {
+		if seen[event] {
+			continue
+		}
+		seen[event] = true
+		result = append(result, string(event))
+	}
+	return result
+}
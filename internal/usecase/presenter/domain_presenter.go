@@ -8,12 +8,16 @@ package presenter
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/presenter"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	"go.openly.dev/pointy"
 )
 
 type domainPresenter struct {
@@ -120,6 +124,64 @@ func (p *domainPresenter) UpdateUser(
 // 	return p.sharedDomain(domain)
 // }
 
+// LocationSubnets translate the subnet to location mappings of a
+// domain to the API response.
+// data is the list of mappings.
+// Return a reference to public.LocationSubnetsResponse and nil error.
+func (p *domainPresenter) LocationSubnets(
+	data []model.DomainLocationSubnet,
+) (*public.LocationSubnetsResponse, error) {
+	output := &public.LocationSubnetsResponse{
+		Subnets: make([]public.LocationSubnet, len(data)),
+	}
+	for i := range data {
+		output.Subnets[i].Subnet = data[i].Subnet
+		output.Subnets[i].Location = data[i].Location
+	}
+	return output, nil
+}
+
+// Export translate the domains of an organization to the export
+// document, with its version and checksum.
+// orgID is the organization of the domains.
+// exportedAt is the time of the export.
+// data is the list of domains with their specific information.
+// subnets is the subnet to location mappings by domain uuid.
+// Return a reference to public.ExportDomainsResponse and nil error
+// on success, else nil and an error with the details.
+func (p *domainPresenter) Export(
+	orgID string,
+	exportedAt time.Time,
+	data []model.Domain,
+	subnets map[uuid.UUID][]model.DomainLocationSubnet,
+) (*public.ExportDomainsResponse, error) {
+	var err error
+	output := &public.ExportDomainsResponse{
+		Version:    domainexport.Version,
+		OrgId:      orgID,
+		ExportedAt: exportedAt,
+		Domains:    make([]public.DomainExportItem, len(data)),
+	}
+	for i := range data {
+		var (
+			domain          *public.Domain
+			locationSubnets *public.LocationSubnetsResponse
+		)
+		if domain, err = p.sharedDomain(&data[i]); err != nil {
+			return nil, err
+		}
+		if locationSubnets, err = p.LocationSubnets(subnets[data[i].DomainUuid]); err != nil {
+			return nil, err
+		}
+		output.Domains[i].Domain = *domain
+		output.Domains[i].LocationSubnets = locationSubnets.Subnets
+	}
+	if output.Checksum, err = domainexport.Checksum(output.Domains); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 // Create domain registration token
 // Translate the internal token represenatation to public API
 func (p *domainPresenter) CreateDomainToken(token *repository.DomainRegToken) (*public.DomainRegToken, error) {
@@ -129,5 +191,14 @@ func (p *domainPresenter) CreateDomainToken(token *repository.DomainRegToken) (*
 		DomainType:  token.DomainType,
 		Expiration:  int(token.ExpirationNS / 1_000_000_000),
 	}
+	if token.Claims.DomainName != "" {
+		drt.DomainName = pointy.String(token.Claims.DomainName)
+	}
+	if token.Claims.SubscriptionManagerId != uuid.Nil {
+		drt.SubscriptionManagerId = &token.Claims.SubscriptionManagerId
+	}
+	if token.Claims.Label != "" {
+		drt.Label = pointy.String(token.Claims.Label)
+	}
 	return drt, nil
 }
@@ -9,94 +9,10 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype"
 	"go.openly.dev/pointy"
 )
 
-func (p *domainPresenter) fillRhelIdmLocations(
-	target *public.Domain,
-	source *model.Domain,
-) {
-	if target == nil || target.RhelIdm == nil {
-		panic("'target' or 'target.RhelIdm' are nil")
-	}
-	if source == nil || source.IpaDomain == nil {
-		panic("'source' or 'source.IpaDomain' are nil")
-	}
-	target.RhelIdm.Locations = make(
-		[]public.Location,
-		len(source.IpaDomain.Locations),
-	)
-	for idx := range source.IpaDomain.Locations {
-		target.RhelIdm.Locations[idx].Name = source.IpaDomain.Locations[idx].Name
-		target.RhelIdm.Locations[idx].Description = source.IpaDomain.Locations[idx].Description
-	}
-}
-
-func (p *domainPresenter) fillRhelIdmServers(
-	target *public.Domain,
-	source *model.Domain,
-) {
-	if target == nil || source == nil {
-		return
-	}
-	if target.RhelIdm == nil || source.IpaDomain == nil {
-		return
-	}
-	target.RhelIdm.Servers = make(
-		[]public.DomainIpaServer,
-		len(source.IpaDomain.Servers),
-	)
-	for i := range source.IpaDomain.Servers {
-		target.RhelIdm.Servers[i].Fqdn =
-			source.IpaDomain.Servers[i].FQDN
-		var rhsmID *uuid.UUID = nil
-		if source.IpaDomain.Servers[i].RHSMId != nil {
-			rhsmID = &uuid.UUID{}
-			*rhsmID = uuid.MustParse(*source.IpaDomain.Servers[i].RHSMId)
-		}
-		target.RhelIdm.Servers[i].SubscriptionManagerId = rhsmID
-		target.RhelIdm.Servers[i].Location =
-			source.IpaDomain.Servers[i].Location
-		target.RhelIdm.Servers[i].CaServer =
-			source.IpaDomain.Servers[i].CaServer
-		target.RhelIdm.Servers[i].HccEnrollmentServer =
-			source.IpaDomain.Servers[i].HCCEnrollmentServer
-		target.RhelIdm.Servers[i].HccUpdateServer =
-			source.IpaDomain.Servers[i].HCCUpdateServer
-		target.RhelIdm.Servers[i].PkinitServer =
-			source.IpaDomain.Servers[i].PKInitServer
-	}
-}
-
-func (p *domainPresenter) fillRhelIdmCerts(
-	output *public.Domain,
-	domain *model.Domain,
-) {
-	if output == nil || domain == nil || output.RhelIdm == nil || domain.IpaDomain == nil {
-		return
-	}
-	output.RhelIdm.CaCerts = make(
-		[]public.Certificate,
-		len(domain.IpaDomain.CaCerts),
-	)
-	for i := range domain.IpaDomain.CaCerts {
-		output.RhelIdm.CaCerts[i].Nickname =
-			domain.IpaDomain.CaCerts[i].Nickname
-		output.RhelIdm.CaCerts[i].Issuer =
-			domain.IpaDomain.CaCerts[i].Issuer
-		output.RhelIdm.CaCerts[i].NotAfter =
-			domain.IpaDomain.CaCerts[i].NotAfter
-		output.RhelIdm.CaCerts[i].NotBefore =
-			domain.IpaDomain.CaCerts[i].NotBefore
-		output.RhelIdm.CaCerts[i].SerialNumber =
-			domain.IpaDomain.CaCerts[i].SerialNumber
-		output.RhelIdm.CaCerts[i].Subject =
-			domain.IpaDomain.CaCerts[i].Subject
-		output.RhelIdm.CaCerts[i].Pem =
-			domain.IpaDomain.CaCerts[i].Pem
-	}
-}
-
 func (p *domainPresenter) guardSharedDomain(
 	domain *model.Domain,
 ) error {
@@ -124,16 +40,13 @@ func (p *domainPresenter) sharedDomain(
 	output = &public.Domain{}
 	p.sharedDomainFill(domain, output)
 
-	switch *domain.Type {
-	case model.DomainTypeIpa:
-		// Specific rhel-idm domain code
-		output.DomainType = model.DomainTypeIpaString
-		output.RhelIdm = &public.DomainIpa{}
-		err = p.sharedDomainFillRhelIdm(domain, output)
-	default:
-		err = fmt.Errorf("'domain.DomainType=%d' is invalid", *domain.Type)
-	}
-	if err != nil {
+	// Specific domain type code
+	h, ok := domaintype.Lookup(*domain.Type)
+	if !ok {
+		return nil, fmt.Errorf("'domain.DomainType=%d' is invalid", *domain.Type)
+	}
+	output.DomainType = h.Name()
+	if err = h.PresentDomain(domain, output); err != nil {
 		return nil, err
 	}
 	return output, nil
@@ -160,42 +73,42 @@ func (p *domainPresenter) sharedDomainFill(
 	if domain.Description != nil {
 		output.Description = domain.Description
 	}
-}
-
-func (p *domainPresenter) sharedDomainFillRhelIdm(
-	domain *model.Domain,
-	output *public.Domain,
-) (err error) {
-	if domain.Type != nil && *domain.Type != model.DomainTypeIpa {
-		return fmt.Errorf(
-			"'domain.Type' is not '%s'",
-			model.DomainTypeIpaString,
-		)
-	}
-	if domain.IpaDomain == nil {
-		return internal_errors.NilArgError("domain.IpaDomain")
-	}
-	if output == nil {
-		panic("'output' is nil")
-	}
-	output.RhelIdm = &public.DomainIpa{}
-	if domain.IpaDomain.RealmName != nil {
-		output.RhelIdm.RealmName = *domain.IpaDomain.RealmName
+	if domain.RegistrationState != "" {
+		output.RegistrationState = (*public.RegistrationState)(pointy.String(domain.RegistrationState))
 	}
-
-	if domain.IpaDomain.RealmDomains != nil {
-		output.RhelIdm.RealmDomains = append(
-			[]string{},
-			domain.IpaDomain.RealmDomains...)
-	} else {
-		output.RhelIdm.RealmDomains = []string{}
+	if domain.Registration != nil {
+		output.Registration = p.sharedDomainRegistration(domain.Registration)
 	}
+}
 
-	p.fillRhelIdmCerts(output, domain)
-	p.fillRhelIdmServers(output, domain)
-	p.fillRhelIdmLocations(output, domain)
-
-	return nil
+func (p *domainPresenter) sharedDomainRegistration(
+	registration *model.DomainRegistration,
+) *public.DomainRegistration {
+	output := &public.DomainRegistration{
+		SubscriptionManagerId: registration.SubscriptionManagerID,
+		Fqdn:                  registration.FQDN,
+		IpaHccVersion:         registration.IpaHccVersion,
+		IpaVersion:            registration.IpaVersion,
+		OsReleaseId:           registration.OsReleaseID,
+		OsReleaseVersionId:    registration.OsReleaseVersionID,
+		CaCerts:               make([]public.Certificate, 0, len(registration.CaCerts)),
+		RegisteredAt:          registration.CreatedAt,
+		DecidedBy:             registration.DecidedBy,
+		DecidedAt:             registration.DecidedAt,
+		Reason:                registration.Reason,
+	}
+	for _, cert := range registration.CaCerts {
+		output.CaCerts = append(output.CaCerts, public.Certificate{
+			Issuer:       cert.Issuer,
+			Nickname:     cert.Nickname,
+			NotAfter:     cert.NotAfter,
+			NotBefore:    cert.NotBefore,
+			Pem:          cert.Pem,
+			SerialNumber: cert.SerialNumber,
+			Subject:      cert.Subject,
+		})
+	}
+	return output
 }
 
 func (p *domainPresenter) buildPaginationLink(offset int, limit int) string {
@@ -280,4 +193,7 @@ func (p *domainPresenter) listFillItem(output *public.ListDomainsData, domain *m
 	if domain.Description != nil {
 		output.Description = *domain.Description
 	}
+	if domain.RegistrationState != "" {
+		output.RegistrationState = (*public.RegistrationState)(pointy.String(domain.RegistrationState))
+	}
 }
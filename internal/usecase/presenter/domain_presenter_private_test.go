@@ -10,49 +10,12 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.openly.dev/pointy"
 )
 
-func TestGuardsRegisterIpa(t *testing.T) {
-	var (
-		err error
-	)
-	p := &domainPresenter{cfg: test.GetTestConfig()}
-	assert.Panics(t, func() {
-		err = p.sharedDomainFillRhelIdm(nil, nil)
-	})
-
-	domain := &model.Domain{}
-	domain.Type = pointy.Uint(999)
-	err = p.sharedDomainFillRhelIdm(domain, nil)
-	assert.EqualError(t, err, fmt.Sprintf("'domain.Type' is not '%s'", model.DomainTypeIpaString))
-
-	*domain.Type = model.DomainTypeIpa
-	err = p.sharedDomainFillRhelIdm(domain, nil)
-	assert.EqualError(t, err, "code=500, message='domain.IpaDomain' cannot be nil")
-
-	domain.IpaDomain = &model.Ipa{}
-	assert.Panics(t, func() {
-		err = p.sharedDomainFillRhelIdm(domain, nil)
-	})
-
-	output := &public.RegisterDomainResponse{}
-	err = p.sharedDomainFillRhelIdm(domain, output)
-	assert.NoError(t, err)
-
-	domain.IpaDomain.CaCerts = []model.IpaCert{}
-	assert.NotPanics(t, func() {
-		err = p.sharedDomainFillRhelIdm(domain, output)
-	})
-
-	output.DomainType = public.RhelIdm
-	output.RhelIdm = &public.DomainIpa{}
-	err = p.sharedDomainFillRhelIdm(domain, output)
-	assert.NoError(t, err)
-}
-
 func TestRegisterRhelIdm(t *testing.T) {
 	testSubscriptionManagerId := &uuid.UUID{}
 	*testSubscriptionManagerId = uuid.MustParse("71ad4978-c768-11ed-ad69-482ae3863d30")
@@ -69,11 +32,11 @@ func TestRegisterRhelIdm(t *testing.T) {
 		{
 			Name: "Success minimal rhel-idm content",
 			Given: &model.Domain{
-				Type: pointy.Uint(model.DomainTypeIpa),
-				IpaDomain: &model.Ipa{
+				Type: pointy.Uint(ipa.TypeID),
+				TypeData: &ipa.Ipa{
 					RealmName:    pointy.String(""),
-					CaCerts:      []model.IpaCert{},
-					Servers:      []model.IpaServer{},
+					CaCerts:      []ipa.IpaCert{},
+					Servers:      []ipa.IpaServer{},
 					RealmDomains: pq.StringArray{},
 				},
 			},
@@ -93,12 +56,12 @@ func TestRegisterRhelIdm(t *testing.T) {
 		{
 			Name: "Success full rhel-idm content",
 			Given: &model.Domain{
-				Type: pointy.Uint(model.DomainTypeIpa),
-				IpaDomain: &model.Ipa{
+				Type: pointy.Uint(ipa.TypeID),
+				TypeData: &ipa.Ipa{
 					RealmName:    pointy.String(test.RealmName),
 					RealmDomains: test.RealmDomains,
-					CaCerts:      []model.IpaCert{test.IpaCaModelCert},
-					Servers: []model.IpaServer{
+					CaCerts:      []ipa.IpaCert{test.IpaCaModelCert},
+					Servers: []ipa.IpaServer{
 						{
 							FQDN:                test.Server1.Fqdn,
 							RHSMId:              pointy.String(test.Server1.CertCN),
@@ -135,25 +98,25 @@ func TestRegisterRhelIdm(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Log(testCase.Name)
 		p := &domainPresenter{cfg: test.GetTestConfig()}
-		ipa, err := p.Register(testCase.Given)
+		output, err := p.Register(testCase.Given)
 		if testCase.Expected.Err != nil {
 			assert.EqualError(t, err, testCase.Expected.Err.Error())
-			assert.Nil(t, ipa)
+			assert.Nil(t, output)
 		} else {
 			assert.NoError(t, err)
-			require.NotNil(t, ipa)
-			assert.Equal(t, testCase.Expected.Domain.RhelIdm.RealmName, ipa.RhelIdm.RealmName)
-			require.Equal(t, len(testCase.Expected.Domain.RhelIdm.RealmDomains), len(ipa.RhelIdm.RealmDomains))
-			for i := range ipa.RhelIdm.RealmDomains {
-				assert.Equal(t, testCase.Expected.Domain.RhelIdm.RealmDomains[i], ipa.RhelIdm.RealmDomains[i])
+			require.NotNil(t, output)
+			assert.Equal(t, testCase.Expected.Domain.RhelIdm.RealmName, output.RhelIdm.RealmName)
+			require.Equal(t, len(testCase.Expected.Domain.RhelIdm.RealmDomains), len(output.RhelIdm.RealmDomains))
+			for i := range output.RhelIdm.RealmDomains {
+				assert.Equal(t, testCase.Expected.Domain.RhelIdm.RealmDomains[i], output.RhelIdm.RealmDomains[i])
 			}
-			require.Equal(t, len(testCase.Expected.Domain.RhelIdm.CaCerts), len(ipa.RhelIdm.CaCerts))
-			for i := range ipa.RhelIdm.CaCerts {
-				assert.Equal(t, testCase.Expected.Domain.RhelIdm.CaCerts[i], ipa.RhelIdm.CaCerts[i])
+			require.Equal(t, len(testCase.Expected.Domain.RhelIdm.CaCerts), len(output.RhelIdm.CaCerts))
+			for i := range output.RhelIdm.CaCerts {
+				assert.Equal(t, testCase.Expected.Domain.RhelIdm.CaCerts[i], output.RhelIdm.CaCerts[i])
 			}
-			require.Equal(t, len(testCase.Expected.Domain.RhelIdm.Servers), len(ipa.RhelIdm.Servers))
-			for i := range ipa.RhelIdm.Servers {
-				assert.Equal(t, testCase.Expected.Domain.RhelIdm.Servers[i], ipa.RhelIdm.Servers[i])
+			require.Equal(t, len(testCase.Expected.Domain.RhelIdm.Servers), len(output.RhelIdm.Servers))
+			for i := range output.RhelIdm.Servers {
+				assert.Equal(t, testCase.Expected.Domain.RhelIdm.Servers[i], output.RhelIdm.Servers[i])
 			}
 		}
 	}
@@ -186,29 +149,41 @@ func TestSharedDomainFill(t *testing.T) {
 	assert.Equal(t, testTitle, output.Title)
 	require.NotNil(t, output.Description)
 	assert.Equal(t, "My Domain Example Description", *output.Description)
-}
-
-func TestFillRhelIdmCerts(t *testing.T) {
-	p := &domainPresenter{cfg: test.GetTestConfig()}
-
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(nil, nil)
-	})
-
-	output := public.Domain{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(&output, nil)
-	})
-
-	domain := model.Domain{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(&output, &domain)
-	})
-
-	output.RhelIdm = &public.DomainIpa{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(&output, &domain)
-	})
+	assert.Nil(t, output.RegistrationState)
+	assert.Nil(t, output.Registration)
+
+	// pending approval
+	registeredAt := time.Date(2026, 10, 19, 17, 0, 0, 0, time.UTC)
+	smID := uuid.MustParse("547ce70c-9eb5-4783-a619-086aa26f88e5")
+	domain.RegistrationState = model.DomainRegistrationPendingApproval
+	domain.Registration = &model.DomainRegistration{
+		CreatedAt:             registeredAt,
+		SubscriptionManagerID: smID,
+		FQDN:                  pointy.String("server.mydomain.example"),
+		IpaHccVersion:         "0.12",
+		IpaVersion:            "4.11.0",
+		OsReleaseID:           "rhel",
+		OsReleaseVersionID:    "9.4",
+		CaCerts: []model.RegistrationCert{
+			{Nickname: "MYDOMAIN.EXAMPLE IPA CA", Pem: "-----BEGIN CERTIFICATE-----"},
+		},
+	}
+	output = public.RegisterDomainResponse{}
+	p.sharedDomainFill(domain, &output)
+	require.NotNil(t, output.RegistrationState)
+	assert.Equal(t, public.PendingApproval, *output.RegistrationState)
+	assert.Equal(t, &public.DomainRegistration{
+		SubscriptionManagerId: smID,
+		Fqdn:                  pointy.String("server.mydomain.example"),
+		IpaHccVersion:         "0.12",
+		IpaVersion:            "4.11.0",
+		OsReleaseId:           "rhel",
+		OsReleaseVersionId:    "9.4",
+		CaCerts: []public.Certificate{
+			{Nickname: "MYDOMAIN.EXAMPLE IPA CA", Pem: "-----BEGIN CERTIFICATE-----"},
+		},
+		RegisteredAt: registeredAt,
+	}, output.Registration)
 }
 
 func TestGuardSharedDomain(t *testing.T) {
@@ -225,7 +200,7 @@ func TestGuardSharedDomain(t *testing.T) {
 	err = p.guardSharedDomain(&domain)
 	assert.EqualError(t, err, "'domain.Type' is invalid")
 
-	*domain.Type = model.DomainTypeIpa
+	*domain.Type = ipa.TypeID
 	err = p.guardSharedDomain(&domain)
 	assert.NoError(t, err)
 }
@@ -249,10 +224,10 @@ func TestSharedDomain(t *testing.T) {
 	assert.EqualError(t, err, "code=500, message='domain.Type' cannot be nil")
 
 	// Fail nil IpaDomain
-	domain.Type = pointy.Uint(model.DomainTypeIpa)
+	domain.Type = pointy.Uint(ipa.TypeID)
 	output, err = p.sharedDomain(domain)
 	assert.Nil(t, output)
-	assert.EqualError(t, err, "code=500, message='domain.IpaDomain' cannot be nil")
+	assert.EqualError(t, err, "code=500, message='domain.TypeData' cannot be nil")
 
 	// Not valid Type
 	*domain.Type = 999
@@ -261,8 +236,8 @@ func TestSharedDomain(t *testing.T) {
 	assert.EqualError(t, err, "'domain.DomainType=999' is invalid")
 
 	// Success minimal values
-	*domain.Type = model.DomainTypeIpa
-	domain.IpaDomain = &model.Ipa{}
+	*domain.Type = ipa.TypeID
+	domain.TypeData = &ipa.Ipa{}
 	output, err = p.sharedDomain(domain)
 	expected := public.Domain{
 		AutoEnrollmentEnabled: nil,
@@ -283,7 +258,7 @@ func TestSharedDomain(t *testing.T) {
 	equalPresenterDomain(t, &expected, output)
 
 	// Success with full information
-	*domain.Type = model.DomainTypeIpa
+	*domain.Type = ipa.TypeID
 	testTitle := pointy.String("Test Title")
 	domain.Title = testTitle
 	domain.Description = pointy.String("Test Description")
@@ -297,9 +272,9 @@ func TestSharedDomain(t *testing.T) {
 	domain.AutoEnrollmentEnabled = pointy.Bool(true)
 	testNotBefore := time.Now()
 	testNotAfter := testNotBefore.Add(24 * time.Hour)
-	domain.IpaDomain.RealmDomains = pq.StringArray{"mydomain.example"}
-	domain.IpaDomain.RealmName = pointy.String("MYDOMAIN.EXAMPLE")
-	domain.IpaDomain.CaCerts = []model.IpaCert{
+	ipa.Data(domain).RealmDomains = pq.StringArray{"mydomain.example"}
+	ipa.Data(domain).RealmName = pointy.String("MYDOMAIN.EXAMPLE")
+	ipa.Data(domain).CaCerts = []ipa.IpaCert{
 		{
 			Issuer:       "Ca Cert Issuer test",
 			Nickname:     "Ca Cert Nickname test",
@@ -312,7 +287,7 @@ func TestSharedDomain(t *testing.T) {
 	}
 	testSubscriptionManagerID := &uuid.UUID{}
 	*testSubscriptionManagerID = uuid.MustParse("93a46bde-e760-11ed-9a5a-482ae3863d30")
-	domain.IpaDomain.Servers = []model.IpaServer{
+	ipa.Data(domain).Servers = []ipa.IpaServer{
 		{
 			FQDN:                "server1.mydomain.example",
 			RHSMId:              pointy.String(testSubscriptionManagerID.String()),
@@ -407,7 +382,7 @@ func equalPrensenterDomainRhelIdmServers(t *testing.T, expected []public.DomainI
 	}
 }
 
-// equalPresenterDomainRhelIdm compare expected public.DomainIpa with actual model.Ipa
+// equalPresenterDomainRhelIdm compare expected public.DomainIpa with actual ipa.Ipa
 func equalPresenterDomainRhelIdm(t *testing.T, expected *public.DomainIpa, actual *public.DomainIpa) {
 	if expected == nil && actual == nil {
 		return
@@ -442,203 +417,6 @@ func equalPresenterDomain(t *testing.T, expected *public.Domain, actual *public.
 	}
 }
 
-func TestFillRhelIdmLocationsError(t *testing.T) {
-	p := &domainPresenter{cfg: test.GetTestConfig()}
-
-	assert.Panics(t, func() {
-		p.fillRhelIdmLocations(nil, nil)
-	}, "'target' or 'target.RhelIdm' are nil")
-
-	output := public.Domain{}
-	assert.Panics(t, func() {
-		p.fillRhelIdmLocations(&output, nil)
-	}, "'target' or 'target.RhelIdm' are nil")
-
-	domain := model.Domain{}
-	assert.Panics(t, func() {
-		p.fillRhelIdmLocations(&output, &domain)
-	}, "'target' or 'target.RhelIdm' are nil")
-
-}
-
-func TestFillRhelIdmLocations(t *testing.T) {
-	type TestCaseGiven struct {
-		To   *public.Domain
-		From *model.Domain
-	}
-	type TestCaseExpected struct {
-		Err error
-		To  *public.Domain
-	}
-	type TestCase struct {
-		Name     string
-		Given    TestCaseGiven
-		Expected TestCaseExpected
-	}
-	testSubscriptionManagerID := &uuid.UUID{}
-	*testSubscriptionManagerID = uuid.MustParse("547ce70c-9eb5-4783-a619-086aa26f88e5")
-	testCases := []TestCase{
-		{
-			Name: "Full success copy",
-			Given: TestCaseGiven{
-				To: &public.Domain{
-					RhelIdm: &public.DomainIpa{},
-				},
-				From: &model.Domain{
-					Type: pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
-						Locations: []model.IpaLocation{
-							{
-								Name:        "boston",
-								Description: pointy.String("Boston data center"),
-							},
-						},
-					},
-				},
-			},
-			Expected: TestCaseExpected{
-				Err: nil,
-				To: &public.Domain{
-					DomainType: public.RhelIdm,
-					RhelIdm: &public.DomainIpa{
-						Locations: []public.Location{
-							{
-								Name:        "boston",
-								Description: pointy.String("Boston data center"),
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	for _, testCase := range testCases {
-		t.Log(testCase.Name)
-		// I instantiate directly because the public methods
-		// are not part of the interface
-		p := &domainPresenter{cfg: test.GetTestConfig()}
-		if testCase.Expected.Err != nil {
-			assert.Panics(t, func() {
-				p.fillRhelIdmLocations(testCase.Given.To, testCase.Given.From)
-			})
-		} else {
-			assert.NotPanics(t, func() {
-				p.fillRhelIdmLocations(testCase.Given.To, testCase.Given.From)
-			})
-			require.NotNil(t, testCase.Expected.To)
-			require.NotNil(t, testCase.Expected.To.RhelIdm)
-			require.NotNil(t, testCase.Expected.To.RhelIdm.Locations)
-			require.NotNil(t, testCase.Given.To)
-			require.NotNil(t, testCase.Given.To.RhelIdm)
-			require.NotNil(t, testCase.Given.To.RhelIdm.Locations)
-			assert.Equal(t, testCase.Expected.To.RhelIdm.Locations, testCase.Given.To.RhelIdm.Locations)
-		}
-	}
-}
-
-func TestFillRhelIdmServersError(t *testing.T) {
-	p := &domainPresenter{cfg: test.GetTestConfig()}
-
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmServers(nil, nil)
-	})
-
-	output := public.Domain{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmServers(&output, nil)
-	})
-
-	domain := model.Domain{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmServers(&output, &domain)
-	})
-}
-
-func TestFillRhelIdmServers(t *testing.T) {
-	type TestCaseGiven struct {
-		To   *public.Domain
-		From *model.Domain
-	}
-	type TestCaseExpected struct {
-		Err error
-		To  *public.Domain
-	}
-	type TestCase struct {
-		Name     string
-		Given    TestCaseGiven
-		Expected TestCaseExpected
-	}
-	testSubscriptionManagerID := &uuid.UUID{}
-	*testSubscriptionManagerID = uuid.MustParse("547ce70c-9eb5-4783-a619-086aa26f88e5")
-	testCases := []TestCase{
-		{
-			Name: "Full success copy",
-			Given: TestCaseGiven{
-				To: &public.Domain{
-					RhelIdm: &public.DomainIpa{},
-				},
-				From: &model.Domain{
-					Type: pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
-						Servers: []model.IpaServer{
-							{
-								FQDN:                "server1.mydomain.example",
-								RHSMId:              pointy.String(testSubscriptionManagerID.String()),
-								CaServer:            true,
-								HCCEnrollmentServer: true,
-								HCCUpdateServer:     true,
-								PKInitServer:        true,
-							},
-						},
-					},
-				},
-			},
-			Expected: TestCaseExpected{
-				Err: nil,
-				To: &public.Domain{
-					DomainType: public.RhelIdm,
-					RhelIdm: &public.DomainIpa{
-						Servers: []public.DomainIpaServer{
-							{
-								Fqdn:                  "server1.mydomain.example",
-								SubscriptionManagerId: testSubscriptionManagerID,
-								CaServer:              true,
-								HccEnrollmentServer:   true,
-								HccUpdateServer:       true,
-								PkinitServer:          true,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	for _, testCase := range testCases {
-		t.Log(testCase.Name)
-		// I instantiate directly because the public methods
-		// are not part of the interface
-		p := &domainPresenter{cfg: test.GetTestConfig()}
-		if testCase.Expected.Err != nil {
-			// assert.EqualError(t, err, testCase.Expected.Err.Error())
-			assert.Panics(t, func() {
-				p.fillRhelIdmServers(testCase.Given.To, testCase.Given.From)
-			})
-		} else {
-			// assert.NoError(t, err)
-			assert.NotPanics(t, func() {
-				p.fillRhelIdmServers(testCase.Given.To, testCase.Given.From)
-			})
-			require.NotNil(t, testCase.Expected.To)
-			require.NotNil(t, testCase.Expected.To.RhelIdm)
-			require.NotNil(t, testCase.Expected.To.RhelIdm.Servers)
-			require.NotNil(t, testCase.Given.To)
-			require.NotNil(t, testCase.Given.To.RhelIdm)
-			require.NotNil(t, testCase.Given.To.RhelIdm.Servers)
-			assert.Equal(t, testCase.Expected.To.RhelIdm.Servers, testCase.Given.To.RhelIdm.Servers)
-		}
-	}
-}
-
 func TestBuildPaginationLink(t *testing.T) {
 	p := &domainPresenter{cfg: test.GetTestConfig()}
 	prefix := p.cfg.Application.PathPrefix
@@ -758,7 +536,7 @@ func TestListFillItem(t *testing.T) {
 		DomainUuid:            uuid.MustParse("d89b6b9a-ecf4-11ed-9e6c-482ae3863d30"),
 		DomainName:            nil,
 		AutoEnrollmentEnabled: nil,
-		Type:                  pointy.Uint(model.DomainTypeIpa),
+		Type:                  pointy.Uint(ipa.TypeID),
 	}
 	p.listFillItem(&output, &domain)
 	assert.Nil(t, domain.AutoEnrollmentEnabled)
@@ -772,7 +550,7 @@ func TestListFillItem(t *testing.T) {
 		DomainUuid:            uuid.MustParse("d89b6b9a-ecf4-11ed-9e6c-482ae3863d30"),
 		DomainName:            pointy.String("mydomain.example"),
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
+		Type:                  pointy.Uint(ipa.TypeID),
 	}
 	p.listFillItem(&output, &domain)
 	require.NotNil(t, domain.AutoEnrollmentEnabled)
@@ -781,4 +559,10 @@ func TestListFillItem(t *testing.T) {
 	assert.Equal(t, "mydomain.example", *domain.DomainName)
 	require.NotNil(t, output.DomainType)
 	assert.Equal(t, string(public.RhelIdm), string(output.DomainType))
+
+	// path with a rejected registration
+	domain.RegistrationState = model.DomainRegistrationRejected
+	p.listFillItem(&output, &domain)
+	require.NotNil(t, output.RegistrationState)
+	assert.Equal(t, public.Rejected, *output.RegistrationState)
 }
@@ -9,8 +9,11 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domainexport"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.openly.dev/pointy"
@@ -62,12 +65,12 @@ func TestGet(t *testing.T) {
 					OrgId:                 "12345",
 					DomainUuid:            *testUUID,
 					DomainName:            pointy.String("domain.example"),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
+					Type:                  pointy.Uint(ipa.TypeID),
 					AutoEnrollmentEnabled: pointy.Bool(true),
-					IpaDomain: &model.Ipa{
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String("DOMAIN.EXAMPLE"),
-						CaCerts:      []model.IpaCert{},
-						Servers:      []model.IpaServer{},
+						CaCerts:      []ipa.IpaCert{},
+						Servers:      []ipa.IpaServer{},
 						RealmDomains: pq.StringArray{"domain.example"},
 					},
 				},
@@ -78,7 +81,7 @@ func TestGet(t *testing.T) {
 					AutoEnrollmentEnabled: pointy.Bool(true),
 					DomainId:              testUUID,
 					DomainName:            "domain.example",
-					DomainType:            public.DomainType(model.DomainTypeString(model.DomainTypeIpa)),
+					DomainType:            public.DomainType(model.DomainTypeString(ipa.TypeID)),
 					RhelIdm: &public.DomainIpa{
 						RealmName:    "DOMAIN.EXAMPLE",
 						CaCerts:      []public.Certificate{},
@@ -124,127 +127,6 @@ func TestGet(t *testing.T) {
 	}
 }
 
-func TestFillRhelmIdmCertsPanics(t *testing.T) {
-	var err error
-	p := &domainPresenter{cfg: test.GetTestConfig()}
-
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(nil, nil)
-	})
-
-	domain := &model.Domain{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(nil, domain)
-	})
-
-	domain.IpaDomain = &model.Ipa{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(nil, domain)
-	})
-
-	output := &public.Domain{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(output, domain)
-	})
-
-	domain.IpaDomain.CaCerts = []model.IpaCert{}
-	assert.NotPanics(t, func() {
-		p.fillRhelIdmCerts(output, domain)
-	})
-
-	// Minimal success
-	output.RhelIdm = &public.DomainIpa{}
-	p.fillRhelIdmCerts(output, domain)
-	assert.NoError(t, err)
-}
-
-func TestFillRhelmIdmCerts(t *testing.T) {
-	NotBefore := time.Now()
-	NotAfter := NotBefore.Add(time.Hour * 24)
-	type TestCaseGiven struct {
-		To   *public.Domain
-		From *model.Domain
-	}
-	type TestCaseExpected struct {
-		Err error
-		To  *public.Domain
-	}
-	type TestCase struct {
-		Name     string
-		Given    TestCaseGiven
-		Expected TestCaseExpected
-	}
-	testCases := []TestCase{
-		{
-			Name: "Full success copy",
-			Given: TestCaseGiven{
-				To: &public.Domain{
-					DomainType: public.RhelIdm,
-					RhelIdm:    &public.DomainIpa{},
-				},
-				From: &model.Domain{
-					Type: pointy.Uint(model.DomainTypeIpa),
-					IpaDomain: &model.Ipa{
-						CaCerts: []model.IpaCert{
-							{
-								Nickname:     "MYDOMAIN.EXAMPLE.IPA CA",
-								Issuer:       "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
-								NotBefore:    NotBefore,
-								NotAfter:     NotAfter,
-								SerialNumber: "1",
-								Subject:      "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
-								Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
-							},
-						},
-					},
-				},
-			},
-			Expected: TestCaseExpected{
-				Err: nil,
-				To: &public.Domain{
-					DomainType: public.RhelIdm,
-					RhelIdm: &public.DomainIpa{
-						CaCerts: []public.Certificate{
-							{
-								Nickname:     "MYDOMAIN.EXAMPLE.IPA CA",
-								Issuer:       "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
-								NotBefore:    NotBefore,
-								NotAfter:     NotAfter,
-								SerialNumber: "1",
-								Subject:      "CN=Certificate Authority,O=MYDOMAIN.EXAMPLE.COM",
-								Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	for _, testCase := range testCases {
-		t.Log(testCase.Name)
-		// Instantiate directly to access the private methods
-		p := &domainPresenter{cfg: test.GetTestConfig()}
-		if testCase.Expected.Err != nil {
-			// assert.EqualError(t, err, testCase.Expected.Err.Error())
-			assert.Panics(t, func() {
-				p.fillRhelIdmCerts(testCase.Given.To, testCase.Given.From)
-			})
-		} else {
-			// assert.NoError(t, err)
-			assert.NotPanics(t, func() {
-				p.fillRhelIdmCerts(testCase.Given.To, testCase.Given.From)
-			})
-			require.NotNil(t, testCase.Expected.To)
-			require.NotNil(t, testCase.Expected.To.RhelIdm)
-			require.NotNil(t, testCase.Expected.To.RhelIdm.CaCerts)
-			require.NotNil(t, testCase.Given.To)
-			require.NotNil(t, testCase.Given.To.RhelIdm)
-			require.NotNil(t, testCase.Given.To.RhelIdm.CaCerts)
-			assert.Equal(t, testCase.Expected.To.RhelIdm.CaCerts, testCase.Given.To.RhelIdm.CaCerts)
-		}
-	}
-}
-
 func TestRegister(t *testing.T) {
 	testUUID := uuid.MustParse("ebac2444-e51b-11ed-a7f5-482ae3863d30")
 	testDomainName := "mydomain.example"
@@ -256,13 +138,13 @@ func TestRegister(t *testing.T) {
 		Description:           pointy.String("My Example Domain Description"),
 		OrgId:                 "12345",
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain: &model.Ipa{
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
 			RealmName:    pointy.String(testDomainName),
 			RealmDomains: pq.StringArray{testDomainName},
-			CaCerts:      []model.IpaCert{},
-			Servers:      []model.IpaServer{},
-			Locations:    []model.IpaLocation{},
+			CaCerts:      []ipa.IpaCert{},
+			Servers:      []ipa.IpaServer{},
+			Locations:    []ipa.IpaLocation{},
 		},
 	}
 	testExpected := public.Domain{
@@ -305,12 +187,12 @@ func TestUpdate(t *testing.T) {
 		Description:           testDescription,
 		OrgId:                 "12345",
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain: &model.Ipa{
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
 			RealmName:    pointy.String(testDomainName),
 			RealmDomains: pq.StringArray{testDomainName},
-			CaCerts:      []model.IpaCert{},
-			Servers:      []model.IpaServer{},
+			CaCerts:      []ipa.IpaCert{},
+			Servers:      []ipa.IpaServer{},
 		},
 	}
 	testExpected := public.Domain{
@@ -437,7 +319,7 @@ func TestList(t *testing.T) {
 			AutoEnrollmentEnabled: pointy.Bool(true),
 			Title:                 pointy.String("mydomain1 example title"),
 			Description:           pointy.String("mydomain1.example located in Boston"),
-			Type:                  pointy.Uint(model.DomainTypeIpa),
+			Type:                  pointy.Uint(ipa.TypeID),
 		},
 		{
 			OrgId:                 testOrgID,
@@ -446,7 +328,7 @@ func TestList(t *testing.T) {
 			AutoEnrollmentEnabled: nil,
 			Title:                 pointy.String("mydomain2 example title"),
 			Description:           pointy.String("mydomain2.example located in Brno"),
-			Type:                  pointy.Uint(model.DomainTypeIpa),
+			Type:                  pointy.Uint(ipa.TypeID),
 		},
 	}
 	count = int64(len(data))
@@ -499,5 +381,85 @@ func TestCreateDomainToken(t *testing.T) {
 	assert.Equal(t, tok.DomainToken, newTok.DomainToken)
 	assert.Equal(t, tok.DomainId, newTok.DomainId)
 	assert.Equal(t, tok.DomainType, newTok.DomainType)
+	assert.Nil(t, newTok.DomainName)
+	assert.Nil(t, newTok.SubscriptionManagerId)
+	assert.Nil(t, newTok.Label)
 	assert.NoError(t, err)
+
+	tok.Claims = domain_token.Claims{
+		DomainName:            "mydomain.example",
+		SubscriptionManagerId: uuid.New(),
+		Label:                 "ticket 1234",
+	}
+	newTok, err = p.CreateDomainToken(tok)
+	assert.NoError(t, err)
+	assert.Equal(t, pointy.String("mydomain.example"), newTok.DomainName)
+	assert.Equal(t, &tok.Claims.SubscriptionManagerId, newTok.SubscriptionManagerId)
+	assert.Equal(t, pointy.String("ticket 1234"), newTok.Label)
+}
+
+func TestLocationSubnets(t *testing.T) {
+	p := &domainPresenter{cfg: test.GetTestConfig()}
+
+	output, err := p.LocationSubnets(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &public.LocationSubnetsResponse{Subnets: []public.LocationSubnet{}}, output)
+
+	output, err = p.LocationSubnets([]model.DomainLocationSubnet{
+		{DomainID: 1, Subnet: "192.0.2.0/24", Location: "alpha"},
+		{DomainID: 1, Subnet: "2001:db8::/32", Location: "beta"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &public.LocationSubnetsResponse{Subnets: []public.LocationSubnet{
+		{Subnet: "192.0.2.0/24", Location: "alpha"},
+		{Subnet: "2001:db8::/32", Location: "beta"},
+	}}, output)
+}
+
+func TestExport(t *testing.T) {
+	p := &domainPresenter{cfg: test.GetTestConfig()}
+	domainID := uuid.MustParse("188a62fc-0720-11ee-9dfd-482ae3863d30")
+	exportedAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	// Empty organization
+	output, err := p.Export("12345", exportedAt, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, domainexport.Version, output.Version)
+	assert.Equal(t, "12345", output.OrgId)
+	assert.Equal(t, exportedAt, output.ExportedAt)
+	assert.Empty(t, output.Domains)
+	assert.NoError(t, domainexport.Verify(output))
+
+	// Domain without type
+	_, err = p.Export("12345", exportedAt, []model.Domain{{DomainUuid: domainID}}, nil)
+	assert.EqualError(t, err, "code=500, message='domain.Type' cannot be nil")
+
+	// Success
+	output, err = p.Export("12345", exportedAt, []model.Domain{
+		{
+			Model:                 gorm.Model{ID: 1},
+			OrgId:                 "12345",
+			DomainUuid:            domainID,
+			DomainName:            pointy.String("domain.example"),
+			Type:                  pointy.Uint(ipa.TypeID),
+			AutoEnrollmentEnabled: pointy.Bool(true),
+			TypeData: &ipa.Ipa{
+				RealmName:    pointy.String("DOMAIN.EXAMPLE"),
+				RealmDomains: pq.StringArray{"domain.example"},
+			},
+		},
+	}, map[uuid.UUID][]model.DomainLocationSubnet{
+		domainID: {{DomainID: 1, Subnet: "192.0.2.0/24", Location: "boston"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, output.Domains, 1)
+	assert.Equal(t, &domainID, output.Domains[0].Domain.DomainId)
+	assert.Equal(t, "domain.example", output.Domains[0].Domain.DomainName)
+	assert.Equal(t, public.RhelIdm, output.Domains[0].Domain.DomainType)
+	assert.Equal(t, pointy.Bool(true), output.Domains[0].Domain.AutoEnrollmentEnabled)
+	require.NotNil(t, output.Domains[0].Domain.RhelIdm)
+	assert.Equal(t, "DOMAIN.EXAMPLE", output.Domains[0].Domain.RhelIdm.RealmName)
+	assert.Equal(t, []public.LocationSubnet{{Subnet: "192.0.2.0/24", Location: "boston"}},
+		output.Domains[0].LocationSubnets)
+	assert.NoError(t, domainexport.Verify(output))
 }
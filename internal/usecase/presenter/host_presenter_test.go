@@ -12,6 +12,8 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/activedirectory"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.openly.dev/pointy"
@@ -35,7 +37,7 @@ func TestHostConf(t *testing.T) {
 	testRHSMID := pointy.String("fe106208-dd32-11ed-aa87-482ae3863d30")
 	testDomain := "ipa.test"
 	testRealm := "IPA.TEST"
-	testIpaCert := model.IpaCert{
+	testIpaCert := ipa.IpaCert{
 		Model: gorm.Model{
 			ID:        1,
 			CreatedAt: currentTime,
@@ -50,7 +52,7 @@ func TestHostConf(t *testing.T) {
 		NotAfter:     currentTime,
 		Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
 	}
-	testIpaServer := model.IpaServer{
+	testIpaServer := ipa.IpaServer{
 		Model: gorm.Model{
 			ID:        1,
 			CreatedAt: currentTime,
@@ -117,12 +119,12 @@ func TestHostConf(t *testing.T) {
 					OrgId:                 "12345",
 					DomainUuid:            *testDomainID,
 					DomainName:            pointy.String(testDomain),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
+					Type:                  pointy.Uint(ipa.TypeID),
 					AutoEnrollmentEnabled: pointy.Bool(true),
-					IpaDomain: &model.Ipa{
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String(testRealm),
-						CaCerts:      []model.IpaCert{},
-						Servers:      []model.IpaServer{},
+						CaCerts:      []ipa.IpaCert{},
+						Servers:      []ipa.IpaServer{},
 						RealmDomains: pq.StringArray{testDomain},
 					},
 				},
@@ -140,12 +142,12 @@ func TestHostConf(t *testing.T) {
 					OrgId:                 "12345",
 					DomainUuid:            *testDomainID,
 					DomainName:            pointy.String(testDomain),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
+					Type:                  pointy.Uint(ipa.TypeID),
 					AutoEnrollmentEnabled: pointy.Bool(true),
-					IpaDomain: &model.Ipa{
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String(testRealm),
-						CaCerts:      []model.IpaCert{testIpaCert},
-						Servers:      []model.IpaServer{},
+						CaCerts:      []ipa.IpaCert{testIpaCert},
+						Servers:      []ipa.IpaServer{},
 						RealmDomains: pq.StringArray{testDomain},
 					},
 				},
@@ -163,12 +165,12 @@ func TestHostConf(t *testing.T) {
 					OrgId:                 "12345",
 					DomainUuid:            *testDomainID,
 					DomainName:            pointy.String(testDomain),
-					Type:                  pointy.Uint(model.DomainTypeIpa),
+					Type:                  pointy.Uint(ipa.TypeID),
 					AutoEnrollmentEnabled: pointy.Bool(true),
-					IpaDomain: &model.Ipa{
+					TypeData: &ipa.Ipa{
 						RealmName:    pointy.String(testRealm),
-						CaCerts:      []model.IpaCert{testIpaCert},
-						Servers:      []model.IpaServer{testIpaServer},
+						CaCerts:      []ipa.IpaCert{testIpaCert},
+						Servers:      []ipa.IpaServer{testIpaServer},
 						RealmDomains: pq.StringArray{testDomain},
 					},
 				},
@@ -178,10 +180,10 @@ func TestHostConf(t *testing.T) {
 				Err: nil,
 				Output: &public.HostConfResponse{
 					AutoEnrollmentEnabled: true,
-					DomainType:            model.DomainTypeIpaString,
+					DomainType:            ipa.TypeName,
 					DomainId:              *testDomainID,
 					DomainName:            testDomain,
-					RhelIdm: public.HostConfIpa{
+					RhelIdm: &public.HostConfIpa{
 						Cabundle: testIpaCert.Pem,
 						EnrollmentServers: []public.HostConfIpaServer{
 							{Fqdn: testIpaServer.FQDN, Location: pointy.String("europe")},
@@ -196,7 +198,7 @@ func TestHostConf(t *testing.T) {
 	for _, testCase := range testCases {
 		t.Log(testCase.Name)
 		obj := &hostPresenter{cfg: test.GetTestConfig()}
-		output, err := obj.HostConf(testCase.Given.Input, testCase.Given.Token)
+		output, err := obj.HostConf(testCase.Given.Input, nil, testCase.Given.Token)
 		if testCase.Expected.Err != nil {
 			require.Error(t, err)
 			assert.Equal(t, testCase.Expected.Err.Error(), err.Error())
@@ -230,3 +232,71 @@ func TestHostConf(t *testing.T) {
 		}
 	}
 }
+
+func TestHostConfActiveDirectory(t *testing.T) {
+	testDomainID := uuid.MustParse("188a62fc-0720-11ee-9dfd-482ae3863d30")
+	testDomain := "ad.test"
+	testRealm := "AD.TEST"
+	testDC := activedirectory.ActiveDirectoryDomainController{
+		Model:             gorm.Model{ID: 1},
+		ActiveDirectoryID: 1,
+		FQDN:              "dc1.ad.test",
+		Site:              pointy.String("europe"),
+		GlobalCatalog:     true,
+	}
+	domain := &model.Domain{
+		Model:                 gorm.Model{ID: 1},
+		OrgId:                 "12345",
+		DomainUuid:            testDomainID,
+		DomainName:            pointy.String(testDomain),
+		Type:                  pointy.Uint(activedirectory.TypeID),
+		AutoEnrollmentEnabled: pointy.Bool(true),
+		TypeData: &activedirectory.ActiveDirectory{
+			RealmName:         pointy.String(testRealm),
+			ForestName:        pointy.String(testDomain),
+			DomainControllers: []activedirectory.ActiveDirectoryDomainController{testDC},
+		},
+	}
+	obj := &hostPresenter{cfg: test.GetTestConfig()}
+
+	// Success case
+	output, err := obj.HostConf(domain, nil, "token")
+	require.NoError(t, err)
+	require.NotNil(t, output)
+	assert.Equal(t, public.ActiveDirectory, output.DomainType)
+	assert.Nil(t, output.RhelIdm)
+	require.NotNil(t, output.ActiveDirectory)
+	assert.Equal(t, testRealm, output.ActiveDirectory.RealmName)
+	assert.Equal(t, "sssd", output.ActiveDirectory.ClientSoftware)
+	assert.Equal(t, "adcli", output.ActiveDirectory.MembershipSoftware)
+	assert.Equal(t, []public.HostConfActiveDirectoryController{
+		{Fqdn: testDC.FQDN, Site: pointy.String("europe")},
+	}, output.ActiveDirectory.DomainControllers)
+
+	// Domain controllers in the location of the host come first
+	activedirectory.Data(domain).DomainControllers = []activedirectory.ActiveDirectoryDomainController{
+		testDC,
+		{ActiveDirectoryID: 1, FQDN: "dc2.ad.test"},
+		{ActiveDirectoryID: 1, FQDN: "dc3.ad.test", Site: pointy.String("america")},
+	}
+	output, err = obj.HostConf(domain, &model.HostLocation{Name: "america"}, "token")
+	require.NoError(t, err)
+	require.NotNil(t, output.ActiveDirectory)
+	assert.Equal(t, []public.HostConfActiveDirectoryController{
+		{Fqdn: "dc3.ad.test", Site: pointy.String("america")},
+		{Fqdn: testDC.FQDN, Site: pointy.String("europe")},
+		{Fqdn: "dc2.ad.test"},
+	}, output.ActiveDirectory.DomainControllers)
+
+	// No domain controllers
+	activedirectory.Data(domain).DomainControllers = []activedirectory.ActiveDirectoryDomainController{}
+	output, err = obj.HostConf(domain, nil, "token")
+	assert.EqualError(t, err, "domain 'ad.test' has no domain controllers")
+	assert.Nil(t, output)
+
+	// Missing active-directory information
+	domain.TypeData = nil
+	output, err = obj.HostConf(domain, nil, "token")
+	assert.EqualError(t, err, internal_errors.NilArgError("domain.TypeData").Error())
+	assert.Nil(t, output)
+}
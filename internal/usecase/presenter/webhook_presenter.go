@@ -0,0 +1,99 @@
+package presenter
+
+import (
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/presenter"
+	"go.openly.dev/pointy"
+)
+
+type webhookPresenter struct {
+	cfg *config.Config
+}
+
+// NewWebhookPresenter create a new WebhookPresenter instance
+// Return a new presenter.WebhookPresenter instance
+func NewWebhookPresenter(cfg *config.Config) presenter.WebhookPresenter {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	return &webhookPresenter{cfg}
+}
+
+// List is the output adapter to list the webhooks of an organization.
+// data is the slice with the webhooks.
+func (p *webhookPresenter) List(data []model.Webhook) (*public.ListWebhooksResponse, error) {
+	output := &public.ListWebhooksResponse{
+		Data: make([]public.Webhook, 0, len(data)),
+	}
+	for i := range data {
+		output.Data = append(output.Data, p.webhook(&data[i]))
+	}
+	return output, nil
+}
+
+// Get is the output adapter for a webhook; the secret is not
+// included.
+func (p *webhookPresenter) Get(data *model.Webhook) (*public.WebhookResponse, error) {
+	if data == nil {
+		return nil, internal_errors.NilArgError("data")
+	}
+	output := p.webhook(data)
+	return &output, nil
+}
+
+// Create is the output adapter for a new webhook, which include the
+// secret to verify the signature of its deliveries.
+func (p *webhookPresenter) Create(data *model.Webhook) (*public.WebhookResponse, error) {
+	if data == nil {
+		return nil, internal_errors.NilArgError("data")
+	}
+	if data.Secret == "" {
+		return nil, internal_errors.EmptyArgError("data.Secret")
+	}
+	output := p.webhook(data)
+	output.Secret = pointy.String(data.Secret)
+	return &output, nil
+}
+
+// Deliveries is the output adapter to list the deliveries of a
+// webhook.
+// data is the slice with the deliveries.
+func (p *webhookPresenter) Deliveries(data []model.WebhookDelivery) (*public.WebhookDeliveriesResponse, error) {
+	output := &public.WebhookDeliveriesResponse{
+		Data: make([]public.WebhookDelivery, 0, len(data)),
+	}
+	for i := range data {
+		output.Data = append(output.Data, public.WebhookDelivery{
+			DeliveryId:     data[i].DeliveryUuid,
+			Event:          public.WebhookEvent(data[i].Event),
+			Status:         public.WebhookDeliveryStatus(data[i].Status),
+			Attempts:       data[i].Attempts,
+			CreatedAt:      data[i].CreatedAt,
+			NextAttemptAt:  data[i].NextAttemptAt,
+			ResponseStatus: data[i].ResponseStatus,
+			Error:          data[i].Error,
+			DeliveredAt:    data[i].DeliveredAt,
+		})
+	}
+	return output, nil
+}
+
+func (p *webhookPresenter) webhook(data *model.Webhook) public.Webhook {
+	events := make(public.WebhookEvents, 0, len(data.Events))
+	for _, event := range data.Events {
+		events = append(events, public.WebhookEvent(event))
+	}
+	return public.Webhook{
+		WebhookId:           data.WebhookUuid,
+		Url:                 data.URL,
+		Events:              events,
+		Enabled:             data.IsEnabled(),
+		ConsecutiveFailures: data.ConsecutiveFailures,
+		DisabledAt:          data.DisabledAt,
+		CreatedAt:           data.CreatedAt,
+		UpdatedAt:           data.UpdatedAt,
+	}
+}
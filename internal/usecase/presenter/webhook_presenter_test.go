@@ -0,0 +1,136 @@
+package presenter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+func helperWebhookModel() *model.Webhook {
+	createdAt := time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC)
+	return &model.Webhook{
+		ID:                  1,
+		CreatedAt:           createdAt,
+		UpdatedAt:           createdAt.Add(time.Hour),
+		OrgId:               "12345",
+		WebhookUuid:         uuid.MustParse("5b5fb0a6-26f8-4a76-8c5f-1d3a06a6a8b1"),
+		URL:                 "https://itsm.example.com/hooks",
+		Events:              []string{"domain.created", "host.configured"},
+		Enabled:             pointy.Bool(true),
+		ConsecutiveFailures: 2,
+	}
+}
+
+func TestNewWebhookPresenter(t *testing.T) {
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		NewWebhookPresenter(nil)
+	})
+	assert.NotPanics(t, func() {
+		NewWebhookPresenter(test.GetTestConfig())
+	})
+}
+
+func TestWebhookPresenterGet(t *testing.T) {
+	p := NewWebhookPresenter(test.GetTestConfig())
+	output, err := p.Get(nil)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, internal_errors.NilArgError("data").Error())
+
+	data := helperWebhookModel()
+	output, err = p.Get(data)
+	require.NoError(t, err)
+	assert.Equal(t, &public.Webhook{
+		WebhookId:           data.WebhookUuid,
+		Url:                 "https://itsm.example.com/hooks",
+		Events:              public.WebhookEvents{public.DomainCreated, public.HostConfigured},
+		Enabled:             true,
+		ConsecutiveFailures: 2,
+		CreatedAt:           data.CreatedAt,
+		UpdatedAt:           data.UpdatedAt,
+	}, output)
+}
+
+func TestWebhookPresenterCreate(t *testing.T) {
+	cfg := test.GetTestConfig()
+	p := NewWebhookPresenter(cfg)
+	output, err := p.Create(nil)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, internal_errors.NilArgError("data").Error())
+
+	data := helperWebhookModel()
+	output, err = p.Create(data)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, internal_errors.EmptyArgError("data.Secret").Error())
+
+	require.NoError(t, webhook.NewSecret(&cfg.Secrets, data))
+	output, err = p.Create(data)
+	require.NoError(t, err)
+	require.NotNil(t, output.Secret)
+	assert.Equal(t, data.Secret, *output.Secret)
+	assert.Equal(t, data.WebhookUuid, output.WebhookId)
+}
+
+func TestWebhookPresenterList(t *testing.T) {
+	p := NewWebhookPresenter(test.GetTestConfig())
+	output, err := p.List(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &public.ListWebhooksResponse{Data: []public.Webhook{}}, output)
+
+	data := helperWebhookModel()
+	data.Enabled = pointy.Bool(false)
+	data.DisabledAt = &data.UpdatedAt
+	output, err = p.List([]model.Webhook{*data})
+	require.NoError(t, err)
+	require.Len(t, output.Data, 1)
+	assert.False(t, output.Data[0].Enabled)
+	assert.Equal(t, &data.UpdatedAt, output.Data[0].DisabledAt)
+	assert.Nil(t, output.Data[0].Secret)
+}
+
+func TestWebhookPresenterDeliveries(t *testing.T) {
+	p := NewWebhookPresenter(test.GetTestConfig())
+	output, err := p.Deliveries(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &public.WebhookDeliveriesResponse{Data: []public.WebhookDelivery{}}, output)
+
+	createdAt := time.Date(2026, 10, 19, 16, 0, 0, 0, time.UTC)
+	nextAttemptAt := createdAt.Add(time.Minute)
+	deliveryID := uuid.New()
+	output, err = p.Deliveries([]model.WebhookDelivery{
+		{
+			ID:             1,
+			CreatedAt:      createdAt,
+			DeliveryUuid:   deliveryID,
+			Event:          "domain.updated",
+			Status:         model.WebhookDeliveryPending,
+			Attempts:       1,
+			NextAttemptAt:  &nextAttemptAt,
+			ResponseStatus: pointy.Int(503),
+			Error:          pointy.String("webhook responded with status 503"),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &public.WebhookDeliveriesResponse{
+		Data: []public.WebhookDelivery{
+			{
+				DeliveryId:     deliveryID,
+				Event:          public.DomainUpdated,
+				Status:         public.Pending,
+				Attempts:       1,
+				CreatedAt:      createdAt,
+				NextAttemptAt:  &nextAttemptAt,
+				ResponseStatus: pointy.Int(503),
+				Error:          pointy.String("webhook responded with status 503"),
+			},
+		},
+	}, output)
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+)
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// newBucket return a full bucket for limit.
+func newBucket(limit ratelimit.Limit, now time.Time) bucket {
+	return bucket{
+		tokens:    float64(limit.Burst),
+		updatedAt: now,
+	}
+}
+
+// refill add the tokens for the time elapsed since the bucket was
+// updated.
+func (b *bucket) refill(limit ratelimit.Limit, now time.Time) {
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed.Seconds()*limit.Rate)
+	}
+	b.updatedAt = now
+}
+
+// wait return the time until the bucket has a token, or zero when
+// it has one.
+func (b *bucket) wait(limit ratelimit.Limit) time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	wait := (1 - b.tokens) / limit.Rate
+	return time.Duration(math.Ceil(wait * float64(time.Second)))
+}
+
+// isFull return true when the bucket is full at the given time, so
+// it can be dropped without changing the result of the next take.
+func (b *bucket) isFull(limit ratelimit.Limit, now time.Time) bool {
+	return b.tokens+now.Sub(b.updatedAt).Seconds()*limit.Rate >= float64(limit.Burst)
+}
+
+// takeAll refill the buckets, and take a token from every bucket
+// when all of them have one.
+// buckets and limits are indexed as the buckets requested to Take;
+// a nil bucket is ignored.
+// Return -1 and zero when the tokens were taken, else the index of
+// the first empty bucket and the time until every bucket has a token.
+func takeAll(buckets []*bucket, limits []ratelimit.Limit, now time.Time) (int, time.Duration) {
+	denied := -1
+	var retryAfter time.Duration
+	for i, b := range buckets {
+		if b == nil {
+			continue
+		}
+		b.refill(limits[i], now)
+		if wait := b.wait(limits[i]); wait > 0 {
+			if denied < 0 {
+				denied = i
+			}
+			retryAfter = max(retryAfter, wait)
+		}
+	}
+	if denied >= 0 {
+		return denied, retryAfter
+	}
+	for _, b := range buckets {
+		if b != nil {
+			b.tokens--
+		}
+	}
+	return -1, 0
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+)
+
+// memoryPruneInterval is how often the full buckets are dropped.
+const memoryPruneInterval = time.Minute
+
+type memoryEntry struct {
+	bucket bucket
+	limit  ratelimit.Limit
+}
+
+// memoryLimiter keep the token buckets in memory, so every replica
+// of the service has its own buckets.
+type memoryLimiter struct {
+	now func() time.Time
+
+	mutex    sync.Mutex
+	buckets  map[string]*memoryEntry
+	prunedAt time.Time
+}
+
+// NewMemoryLimiter create a limiter which keep the token buckets
+// in memory.
+func NewMemoryLimiter() ratelimit.Limiter {
+	return newMemoryLimiter(time.Now)
+}
+
+func newMemoryLimiter(now func() time.Time) *memoryLimiter {
+	return &memoryLimiter{
+		now:      now,
+		buckets:  map[string]*memoryEntry{},
+		prunedAt: now(),
+	}
+}
+
+func (l *memoryLimiter) Take(ctx context.Context, buckets []ratelimit.Bucket) (int, time.Duration, error) {
+	now := l.now()
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if now.Sub(l.prunedAt) >= memoryPruneInterval {
+		l.prune(now)
+	}
+	states := make([]*bucket, len(buckets))
+	limits := make([]ratelimit.Limit, len(buckets))
+	for i := range buckets {
+		if buckets[i].Limit.IsZero() {
+			continue
+		}
+		entry, ok := l.buckets[buckets[i].Key]
+		if !ok {
+			entry = &memoryEntry{bucket: newBucket(buckets[i].Limit, now)}
+			l.buckets[buckets[i].Key] = entry
+		}
+		entry.limit = buckets[i].Limit
+		states[i] = &entry.bucket
+		limits[i] = buckets[i].Limit
+	}
+	denied, retryAfter := takeAll(states, limits, now)
+	return denied, retryAfter, nil
+}
+
+// prune drop the buckets which are full, so the memory does not
+// grow with every organization and identity seen.
+func (l *memoryLimiter) prune(now time.Time) {
+	for key, entry := range l.buckets {
+		if entry.bucket.isFull(entry.limit, now) {
+			delete(l.buckets, key)
+		}
+	}
+	l.prunedAt = now
+}
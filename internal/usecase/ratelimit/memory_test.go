@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryLimiter() (*memoryLimiter, *time.Time) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	limiter := newMemoryLimiter(func() time.Time { return now })
+	return limiter, &now
+}
+
+func TestMemoryLimiterTake(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 2, Burst: 3}
+	org := []ratelimit.Bucket{{Key: "read/org/12345", Limit: limit}}
+	limiter, now := newTestMemoryLimiter()
+
+	// The burst is allowed at once
+	for i := 0; i < limit.Burst; i++ {
+		denied, retryAfter, err := limiter.Take(ctx, org)
+		require.NoError(t, err)
+		assert.Equal(t, -1, denied)
+		assert.Zero(t, retryAfter)
+	}
+	denied, retryAfter, err := limiter.Take(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, 0, denied)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Other key has its own bucket
+	denied, _, err = limiter.Take(ctx, []ratelimit.Bucket{{Key: "read/org/67890", Limit: limit}})
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+
+	// The bucket is refilled with the time
+	*now = now.Add(500 * time.Millisecond)
+	denied, _, err = limiter.Take(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+	denied, retryAfter, err = limiter.Take(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, 0, denied)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	// Zero limit is not limited
+	for i := 0; i < 10; i++ {
+		denied, _, err = limiter.Take(ctx, []ratelimit.Bucket{{Key: "read/org/12345"}})
+		require.NoError(t, err)
+		assert.Equal(t, -1, denied)
+	}
+}
+
+func TestMemoryLimiterTakeEveryBucket(t *testing.T) {
+	ctx := context.Background()
+	identityLimit := ratelimit.Limit{Rate: 1, Burst: 1}
+	orgLimit := ratelimit.Limit{Rate: 1, Burst: 2}
+	limiter, now := newTestMemoryLimiter()
+	buckets := func(identity string) []ratelimit.Bucket {
+		return []ratelimit.Bucket{
+			{Key: "write/User/12345/" + identity, Limit: identityLimit},
+			{Key: "write/org/12345", Limit: orgLimit},
+		}
+	}
+
+	denied, _, err := limiter.Take(ctx, buckets("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+
+	// The throttled identity does not consume the organization tokens
+	for i := 0; i < 3; i++ {
+		denied, retryAfter, err := limiter.Take(ctx, buckets("alice"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, denied)
+		assert.Equal(t, time.Second, retryAfter)
+	}
+	assert.Equal(t, float64(1), limiter.buckets["write/org/12345"].bucket.tokens)
+
+	// Other identity takes the last organization token
+	denied, _, err = limiter.Take(ctx, buckets("bob"))
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+
+	// The throttled organization does not consume the identity tokens
+	denied, retryAfter, err := limiter.Take(ctx, buckets("carol"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, denied)
+	assert.Equal(t, time.Second, retryAfter)
+	assert.Equal(t, float64(1), limiter.buckets["write/User/12345/carol"].bucket.tokens)
+
+	// Retry after is the time until every bucket has a token
+	*now = now.Add(500 * time.Millisecond)
+	denied, retryAfter, err = limiter.Take(ctx, []ratelimit.Bucket{
+		{Key: "write/User/12345/alice", Limit: identityLimit},
+		{Key: "write/org/12345", Limit: ratelimit.Limit{Rate: 0.25, Burst: 2}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, denied)
+	assert.Equal(t, 3500*time.Millisecond, retryAfter)
+}
+
+func TestMemoryLimiterPrune(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+	limiter, now := newTestMemoryLimiter()
+
+	_, _, err := limiter.Take(ctx, []ratelimit.Bucket{{Key: "write/org/12345", Limit: limit}})
+	require.NoError(t, err)
+	*now = now.Add(memoryPruneInterval / 2)
+	_, _, err = limiter.Take(ctx, []ratelimit.Bucket{{Key: "write/org/67890", Limit: ratelimit.Limit{Rate: 0.001, Burst: 2}}})
+	require.NoError(t, err)
+	require.Len(t, limiter.buckets, 2)
+
+	// The full bucket is dropped, and the other is kept
+	*now = now.Add(memoryPruneInterval / 2)
+	_, _, err = limiter.Take(ctx, []ratelimit.Bucket{{Key: "agent/org/12345", Limit: limit}})
+	require.NoError(t, err)
+	assert.Len(t, limiter.buckets, 2)
+	assert.NotContains(t, limiter.buckets, "write/org/12345")
+	assert.Contains(t, limiter.buckets, "write/org/67890")
+}
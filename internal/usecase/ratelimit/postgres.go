@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+	"gorm.io/gorm"
+)
+
+const (
+	// postgresPruneInterval is how often the stale buckets are
+	// deleted by every replica.
+	postgresPruneInterval = 10 * time.Minute
+	// postgresBucketTTL is the time without requests after a bucket
+	// is considered full, so it can be deleted.
+	postgresBucketTTL = time.Hour
+)
+
+// postgresBucket is a row of the rate_limit_buckets table.
+type postgresBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// postgresLimiter keep the token buckets into the database, so they
+// are shared by every replica of the service.
+type postgresLimiter struct {
+	db  *gorm.DB
+	now func() time.Time
+
+	mutex    sync.Mutex
+	prunedAt time.Time
+}
+
+// NewPostgresLimiter create a limiter which keep the token buckets
+// into the rate_limit_buckets table.
+// db is the database connector.
+// Return the limiter or panic if db is nil.
+func NewPostgresLimiter(db *gorm.DB) ratelimit.Limiter {
+	return newPostgresLimiter(db, time.Now)
+}
+
+func newPostgresLimiter(db *gorm.DB, now func() time.Time) *postgresLimiter {
+	if db == nil {
+		panic("'db' is nil")
+	}
+	return &postgresLimiter{
+		db:       db,
+		now:      now,
+		prunedAt: now(),
+	}
+}
+
+func (l *postgresLimiter) Take(ctx context.Context, buckets []ratelimit.Bucket) (int, time.Duration, error) {
+	var (
+		denied     int
+		retryAfter time.Duration
+	)
+	// The rows are locked in the order of the keys, so concurrent
+	// requests sharing some bucket do not deadlock
+	order := make([]int, 0, len(buckets))
+	for i := range buckets {
+		if !buckets[i].Limit.IsZero() {
+			order = append(order, i)
+		}
+	}
+	if len(order) == 0 {
+		return -1, 0, nil
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return buckets[order[i]].Key < buckets[order[j]].Key
+	})
+	now := l.now().UTC()
+	if err := l.prune(ctx, now); err != nil {
+		return -1, 0, err
+	}
+	err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		states := make([]*bucket, len(buckets))
+		limits := make([]ratelimit.Limit, len(buckets))
+		for _, i := range order {
+			b, err := l.lockBucket(tx, buckets[i], now)
+			if err != nil {
+				return err
+			}
+			states[i] = b
+			limits[i] = buckets[i].Limit
+		}
+		if denied, retryAfter = takeAll(states, limits, now); denied >= 0 {
+			// Nothing is taken, and the refill is computed again
+			// on the next request
+			return nil
+		}
+		for _, i := range order {
+			if err := tx.Exec(`UPDATE rate_limit_buckets SET tokens = ?, updated_at = ? WHERE bucket_key = ?`,
+				states[i].tokens, states[i].updatedAt, buckets[i].Key).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return -1, 0, err
+	}
+	return denied, retryAfter, nil
+}
+
+// lockBucket create the bucket full when it does not exist, and lock
+// its row until the end of the transaction; the insert goes first, so
+// concurrent requests for a new bucket wait for the same row instead
+// of both creating it.
+// Return the state of the bucket or an error.
+func (l *postgresLimiter) lockBucket(tx *gorm.DB, b ratelimit.Bucket, now time.Time) (*bucket, error) {
+	full := newBucket(b.Limit, now)
+	if err := tx.Exec(`INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at) VALUES (?, ?, ?) `+
+		`ON CONFLICT (bucket_key) DO NOTHING`,
+		b.Key, full.tokens, full.updatedAt).Error; err != nil {
+		return nil, err
+	}
+	var row postgresBucket
+	if err := tx.Raw(`SELECT tokens, updated_at FROM rate_limit_buckets WHERE bucket_key = ? FOR UPDATE`, b.Key).
+		Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	return &bucket{tokens: row.Tokens, updatedAt: row.UpdatedAt}, nil
+}
+
+// prune delete the buckets without requests for a while.
+func (l *postgresLimiter) prune(ctx context.Context, now time.Time) error {
+	l.mutex.Lock()
+	if now.Sub(l.prunedAt) < postgresPruneInterval {
+		l.mutex.Unlock()
+		return nil
+	}
+	l.prunedAt = now
+	l.mutex.Unlock()
+	return l.db.WithContext(ctx).
+		Exec(`DELETE FROM rate_limit_buckets WHERE updated_at < ?`, now.Add(-postgresBucketTTL)).
+		Error
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	sqlInsertBucket = `INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at) VALUES ($1, $2, $3) ` +
+		`ON CONFLICT (bucket_key) DO NOTHING`
+	sqlSelectBucket  = `SELECT tokens, updated_at FROM rate_limit_buckets WHERE bucket_key = $1 FOR UPDATE`
+	sqlUpdateBucket  = `UPDATE rate_limit_buckets SET tokens = $1, updated_at = $2 WHERE bucket_key = $3`
+	sqlDeleteBuckets = `DELETE FROM rate_limit_buckets WHERE updated_at < $1`
+)
+
+func newTestPostgresLimiter(t *testing.T) (*postgresLimiter, sqlmock.Sqlmock, *time.Time) {
+	mock, db, err := test.NewSqlMock(&gorm.Session{SkipHooks: true})
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	limiter := newPostgresLimiter(db, func() time.Time { return now })
+	return limiter, mock, &now
+}
+
+func TestNewPostgresLimiter(t *testing.T) {
+	assert.PanicsWithValue(t, "'db' is nil", func() {
+		NewPostgresLimiter(nil)
+	})
+}
+
+// expectLockBucket expect the bucket to be created full when it does
+// not exist, and locked, with the given state.
+func expectLockBucket(mock sqlmock.Sqlmock, key string, limit ratelimit.Limit, now time.Time, tokens float64, updatedAt time.Time) {
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertBucket)).
+		WithArgs(key, float64(limit.Burst), now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(sqlSelectBucket)).
+		WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "updated_at"}).
+			AddRow(tokens, updatedAt))
+}
+
+func TestPostgresLimiterTake(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+	org := []ratelimit.Bucket{{Key: "agent/org/12345", Limit: limit}}
+	limiter, mock, now := newTestPostgresLimiter(t)
+
+	// New bucket is created full
+	mock.ExpectBegin()
+	expectLockBucket(mock, "agent/org/12345", limit, *now, float64(2), *now)
+	mock.ExpectExec(regexp.QuoteMeta(sqlUpdateBucket)).
+		WithArgs(float64(1), *now, "agent/org/12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	denied, retryAfter, err := limiter.Take(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+	assert.Zero(t, retryAfter)
+
+	// Empty bucket is not updated
+	mock.ExpectBegin()
+	expectLockBucket(mock, "agent/org/12345", limit, *now, float64(0.25), *now)
+	mock.ExpectCommit()
+	denied, retryAfter, err = limiter.Take(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, 0, denied)
+	assert.Equal(t, 750*time.Millisecond, retryAfter)
+
+	// Database error
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsertBucket)).
+		WithArgs("agent/org/12345", float64(2), *now).
+		WillReturnError(fmt.Errorf("connection refused"))
+	mock.ExpectRollback()
+	denied, _, err = limiter.Take(ctx, org)
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, -1, denied)
+
+	// Zero limits do not reach the database
+	denied, _, err = limiter.Take(ctx, []ratelimit.Bucket{{Key: "agent/org/12345"}})
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+
+	// Stale buckets are deleted from time to time
+	*now = now.Add(postgresPruneInterval)
+	mock.ExpectExec(regexp.QuoteMeta(sqlDeleteBuckets)).
+		WithArgs(now.Add(-postgresBucketTTL)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectBegin()
+	expectLockBucket(mock, "agent/org/12345", limit, *now, float64(2), *now)
+	mock.ExpectExec(regexp.QuoteMeta(sqlUpdateBucket)).
+		WithArgs(float64(1), *now, "agent/org/12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	denied, _, err = limiter.Take(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresLimiterTakeEveryBucket(t *testing.T) {
+	ctx := context.Background()
+	identityLimit := ratelimit.Limit{Rate: 1, Burst: 5}
+	orgLimit := ratelimit.Limit{Rate: 10, Burst: 20}
+	buckets := []ratelimit.Bucket{
+		{Key: "agent/System/12345/6f324116", Limit: identityLimit},
+		{Key: "agent/org/12345", Limit: orgLimit},
+	}
+	limiter, mock, now := newTestPostgresLimiter(t)
+
+	// The rows are locked in the order of the keys, and both are debited
+	mock.ExpectBegin()
+	expectLockBucket(mock, "agent/System/12345/6f324116", identityLimit, *now, float64(3), *now)
+	expectLockBucket(mock, "agent/org/12345", orgLimit, *now, float64(10), *now)
+	mock.ExpectExec(regexp.QuoteMeta(sqlUpdateBucket)).
+		WithArgs(float64(2), *now, "agent/System/12345/6f324116").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(sqlUpdateBucket)).
+		WithArgs(float64(9), *now, "agent/org/12345").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	denied, _, err := limiter.Take(ctx, buckets)
+	require.NoError(t, err)
+	assert.Equal(t, -1, denied)
+
+	// The empty organization bucket leaves the identity bucket as is
+	mock.ExpectBegin()
+	expectLockBucket(mock, "agent/System/12345/6f324116", identityLimit, *now, float64(3), *now)
+	expectLockBucket(mock, "agent/org/12345", orgLimit, *now, float64(0.5), *now)
+	mock.ExpectCommit()
+	denied, retryAfter, err := limiter.Take(ctx, buckets)
+	require.NoError(t, err)
+	assert.Equal(t, 1, denied)
+	assert.Equal(t, 50*time.Millisecond, retryAfter)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
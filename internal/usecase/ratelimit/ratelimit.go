@@ -0,0 +1,27 @@
+package ratelimit
+
+import (
+	"fmt"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/ratelimit"
+	"gorm.io/gorm"
+)
+
+// New create the limiter for the backend set into the configuration.
+// cfg is the application configuration.
+// db is the database connector, used by the postgres backend.
+// Return the limiter or panic if the backend is not supported.
+func New(cfg *config.Config, db *gorm.DB) ratelimit.Limiter {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	switch cfg.Application.RateLimit.Backend {
+	case config.RateLimitBackendMemory, "":
+		return NewMemoryLimiter()
+	case config.RateLimitBackendPostgres:
+		return NewPostgresLimiter(db)
+	default:
+		panic(fmt.Sprintf("rate limit backend '%s' is not supported", cfg.Application.RateLimit.Backend))
+	}
+}
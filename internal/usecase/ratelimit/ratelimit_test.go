@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNew(t *testing.T) {
+	assert.PanicsWithValue(t, "'cfg' is nil", func() {
+		New(nil, nil)
+	})
+
+	cfg := &config.Config{}
+	assert.IsType(t, &memoryLimiter{}, New(cfg, nil))
+
+	cfg.Application.RateLimit.Backend = config.RateLimitBackendMemory
+	assert.IsType(t, &memoryLimiter{}, New(cfg, nil))
+
+	_, db, err := test.NewSqlMock(&gorm.Session{SkipHooks: true})
+	require.NoError(t, err)
+	cfg.Application.RateLimit.Backend = config.RateLimitBackendPostgres
+	assert.IsType(t, &postgresLimiter{}, New(cfg, db))
+
+	cfg.Application.RateLimit.Backend = "redis"
+	assert.PanicsWithValue(t, "rate limit backend 'redis' is not supported", func() {
+		New(cfg, nil)
+	})
+}
@@ -5,17 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/labstack/echo/v4"
 	"github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
 	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/domain_token"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -33,6 +32,8 @@ func NewDomainRepository() repository.DomainRepository {
 // the pagination info.
 // ctx is the current request context with db and slog instances.
 // orgID is the organization id that we belongs.
+// domainIDs restrict the result to the given domains; nil means
+// no restriction.
 // offset is the starting record for the given ordered result.
 // limit is the number of items for the current requested page.
 // Return the list of items for the current page, the total number
@@ -42,6 +43,7 @@ func NewDomainRepository() repository.DomainRepository {
 func (r *domainRepository) List(
 	ctx context.Context,
 	orgID string,
+	domainIDs []uuid.UUID,
 	offset int,
 	limit int,
 ) (output []model.Domain, count int64, err error) {
@@ -50,9 +52,13 @@ func (r *domainRepository) List(
 		log.ErrorContext(ctx, err.Error())
 		return nil, 0, err
 	}
-	if err = db.
+	db = db.
 		Table("domains").
-		Where("org_id = ?", orgID).
+		Where("org_id = ?", orgID)
+	if domainIDs != nil {
+		db = db.Where("domain_uuid IN ?", domainIDs)
+	}
+	if err = db.
 		Count(&count).
 		Offset(int(offset)).
 		Limit(limit).
@@ -81,12 +87,15 @@ func (r *domainRepository) Register(
 		log.Error(err.Error())
 		return err
 	}
+	if data.RegistrationState == "" {
+		data.RegistrationState = model.DomainRegistrationActive
+	}
 
 	if err = db.Omit(clause.Associations).
 		Create(data).Error; err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			msg := fmt.Sprintf("domain id '%s' is already registered.", data.DomainUuid.String())
-			err = echo.NewHTTPError(http.StatusConflict, msg)
+			err = internal_errors.ErrDomainAlreadyExists.New(nil,
+				"domain id '%s' is already registered.", data.DomainUuid.String())
 			log.Error(err.Error())
 			return err
 		} else {
@@ -96,28 +105,24 @@ func (r *domainRepository) Register(
 	}
 
 	// Specific
-	switch *data.Type {
-	case model.DomainTypeIpa:
-		if data.IpaDomain == nil {
-			err = internal_errors.NilArgError("IpaDomain")
-			log.Error(err.Error())
-			return err
-		}
-		if err = r.createIpaDomain(
-			log,
-			db,
-			data.ID,
-			data.IpaDomain,
-		); err != nil {
-			log.Error(err.Error())
-			return err
-		}
-		return nil
-	default:
+	h, ok := domaintype.Lookup(*data.Type)
+	if !ok {
 		err = fmt.Errorf("'Type' is invalid")
 		log.Error(err.Error())
 		return err
 	}
+	if err = h.Create(log, db, data); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if data.Registration != nil {
+		data.Registration.ID = data.ID
+		if err = db.Create(data.Registration).Error; err != nil {
+			log.Error(err.Error())
+			return err
+		}
+	}
+	return nil
 }
 
 // func (r *domainRepository) PartialUpdate(db *gorm.DB, orgId string, data *model.Domain) (output model.Domain, err error) {
@@ -178,20 +183,17 @@ func (r *domainRepository) UpdateAgent(
 	}
 
 	// Specific
-	switch *data.Type {
-	case model.DomainTypeIpa:
-		if data.IpaDomain == nil {
-			err = internal_errors.NilArgError("IpaDomain")
-			log.Error(err.Error())
-			return err
-		}
-		data.IpaDomain.ID = data.ID
-		return r.updateIpaDomain(log, db, data.IpaDomain)
-	default:
+	h, ok := domaintype.Lookup(*data.Type)
+	if !ok {
 		err = fmt.Errorf("'Type' is invalid")
 		log.Error(err.Error())
 		return err
 	}
+	if err = h.Update(log, db, data); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
 }
 
 // prepareUpdateUser fill the hashmap with the not nil
@@ -294,6 +296,12 @@ func (r *domainRepository) FindByID(
 		log.Error(err.Error())
 		return nil, err
 	}
+	if !output.IsRegistrationApproved() {
+		if output.Registration, err = r.findRegistration(db, output.ID); err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+	}
 	return output, nil
 }
 
@@ -341,8 +349,9 @@ func (r *domainRepository) DeleteById(
 // key
 // validity is the duration of the token.
 // orgID the organization id.
-// domainType the type of the domain that allow the creation, currently
-// only "rhel-idm" is supported.
+// domainType the type of the domain that allow the creation, any of
+// the registered domain types.
+// claims are the restrictions bound to the token.
 // Return nil on success, else an error instance.
 func (r *domainRepository) CreateDomainToken(
 	ctx context.Context,
@@ -350,9 +359,10 @@ func (r *domainRepository) CreateDomainToken(
 	validity time.Duration,
 	orgID string,
 	domainType public.DomainType,
+	claims domain_token.Claims,
 ) (drt *repository.DomainRegToken, err error) {
 	log := app_context.LogFromCtx(ctx)
-	tok, expireNS, err := domain_token.NewDomainRegistrationToken(key, string(domainType), orgID, validity)
+	tok, expireNS, err := domain_token.NewDomainRegistrationTokenWithClaims(key, string(domainType), orgID, validity, claims)
 	if err != nil {
 		log.Error("creating ipa domain token")
 		return nil, err
@@ -363,10 +373,220 @@ func (r *domainRepository) CreateDomainToken(
 		DomainToken:  string(tok),
 		DomainType:   domainType,
 		ExpirationNS: expireNS,
+		Claims:       claims,
 	}
 	return drt, nil
 }
 
+// ListLocationSubnets retrieve the subnet to location mappings
+// of a domain.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id that we belongs.
+// UUID is the domain uuid as provided for the API.
+// Return the list of mappings ordered as they were stored and nil
+// error on success, else nil and an error with the details.
+func (r *domainRepository) ListLocationSubnets(
+	ctx context.Context,
+	orgID string,
+	UUID uuid.UUID,
+) (output []model.DomainLocationSubnet, err error) {
+	var domainID uint
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if domainID, err = r.findDomainID(db, orgID, UUID); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = []model.DomainLocationSubnet{}
+	if err = db.
+		Where("domain_id = ?", domainID).
+		Order("id").
+		Find(&output).
+		Error; err != nil {
+		log.Error("listing subnet to location mappings")
+		return nil, err
+	}
+	return output, nil
+}
+
+// UpdateLocationSubnets replace the subnet to location mappings
+// of a domain.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id that we belongs.
+// UUID is the domain uuid as provided for the API.
+// data is the complete list of mappings for the domain.
+// Return nil on success, else an error instance.
+func (r *domainRepository) UpdateLocationSubnets(
+	ctx context.Context,
+	orgID string,
+	UUID uuid.UUID,
+	data []model.DomainLocationSubnet,
+) (err error) {
+	var domainID uint
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if domainID, err = r.findDomainID(db, orgID, UUID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if err = db.Unscoped().
+		Where("domain_id = ?", domainID).
+		Delete(&model.DomainLocationSubnet{}).
+		Error; err != nil {
+		log.Error("updating subnet to location mappings when deleting old records")
+		return err
+	}
+	for i := range data {
+		data[i].Model.ID = 0
+		data[i].DomainID = domainID
+		if err = db.Create(&data[i]).Error; err != nil {
+			log.Error("updating subnet to location mappings when creating new record")
+			return err
+		}
+	}
+	return nil
+}
+
+// FindOrgSettings retrieve the settings of an organization.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// Return the settings and nil error on success, nil and nil error
+// when the organization has no settings, else nil and an error.
+func (r *domainRepository) FindOrgSettings(
+	ctx context.Context,
+	orgID string,
+) (output *model.OrgSettings, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = &model.OrgSettings{}
+	if err = db.
+		First(output, "org_id = ?", orgID).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// SaveOrgSettings create or update the settings of an organization;
+// a nil field is stored as NULL, so the organization gets the default
+// value again.
+// ctx is the current request context with db and slog instances.
+// data is the settings to store, with the organization id.
+// Return nil on success, else an error.
+func (r *domainRepository) SaveOrgSettings(
+	ctx context.Context,
+	data *model.OrgSettings,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if data == nil {
+		err = internal_errors.NilArgError("data")
+		log.Error(err.Error())
+		return err
+	}
+	if err = r.checkCommon(db, data.OrgID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if err = db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "org_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"updated_at", "registration_approval"}),
+		}).
+		Create(data).
+		Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// FindRegistration retrieve the registration record of a domain into
+// data.Registration, which is nil when the domain was registered
+// without approval.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// data is the domain, as returned by FindByID.
+// Return nil on success, else an error instance.
+func (r *domainRepository) FindRegistration(
+	ctx context.Context,
+	orgID string,
+	data *model.Domain,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommonAndData(db, orgID, data); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if data.Registration, err = r.findRegistration(db, data.ID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// DecideRegistration store the decision about a domain which is
+// pending approval. The state is only changed when the domain is
+// still pending approval, so concurrent decisions cannot override
+// each other.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// data is the domain with the new RegistrationState, and the
+// Registration with the decision.
+// Return nil on success, an internal_errors.ErrDomainNotPending error
+// when the domain is not pending approval, else an error instance.
+func (r *domainRepository) DecideRegistration(
+	ctx context.Context,
+	orgID string,
+	data *model.Domain,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommonAndData(db, orgID, data); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if data.Registration == nil {
+		err = internal_errors.NilArgError("Registration")
+		log.Error(err.Error())
+		return err
+	}
+	tx := db.Model(&model.Domain{}).
+		Where("org_id = ? AND domain_uuid = ? AND registration_state = ?",
+			orgID, data.DomainUuid, model.DomainRegistrationPendingApproval).
+		Update("registration_state", data.RegistrationState)
+	if err = tx.Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if tx.RowsAffected == 0 {
+		err = internal_errors.ErrDomainNotPending.New(nil,
+			"domain '%s' is not pending approval", data.DomainUuid.String())
+		log.Error(err.Error())
+		return err
+	}
+	if err = db.Model(&model.DomainRegistration{}).
+		Where("id = ?", data.ID).
+		Updates(map[string]interface{}{
+			"decided_by": data.Registration.DecidedBy,
+			"decided_at": data.Registration.DecidedAt,
+			"reason":     data.Registration.Reason,
+		}).Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
 // ------- PRIVATE METHODS --------
 
 func (r *domainRepository) checkCommon(
@@ -424,138 +644,46 @@ func (r *domainRepository) checkCommonAndDataAndType(
 	return nil
 }
 
-func (r *domainRepository) createIpaDomain(
-	log *slog.Logger,
+func (r *domainRepository) findDomainID(
 	db *gorm.DB,
-	domainID uint,
-	data *model.Ipa,
-) (err error) {
-	if data == nil {
-		err = internal_errors.NilArgError("data")
-		log.Error(err.Error())
-		return err
-	}
-	data.Model.ID = domainID
-	if err = db.Omit(clause.Associations).Create(data).Error; err != nil {
-		log.Error("failed to create the ipas record")
-		return err
-	}
-	for idx := range data.CaCerts {
-		data.CaCerts[idx].Model.ID = 0
-		data.CaCerts[idx].IpaID = domainID
-		if err = db.Create(&data.CaCerts[idx]).Error; err != nil {
-			log.Error("failed to create the ipa_certs record",
-				slog.String("subject", data.CaCerts[idx].Subject),
-				slog.String("issuer", data.CaCerts[idx].Issuer),
-			)
-			return err
-		}
-	}
-	for idx := range data.Servers {
-		data.Servers[idx].Model.ID = 0
-		data.Servers[idx].IpaID = domainID
-		if err = db.Create(&data.Servers[idx]).Error; err != nil {
-			log.Error("failed to create the ipa_servers record",
-				slog.String("fqdn", data.Servers[idx].FQDN),
-			)
-			return err
-		}
-	}
-	for idx := range data.Locations {
-		data.Locations[idx].Model.ID = 0
-		data.Locations[idx].IpaID = domainID
-		if err = db.Create(&data.Locations[idx]).Error; err != nil {
-			log.Error("failed to create the ipa_locations record",
-				slog.String("name", data.Locations[idx].Name),
-			)
-			return err
-		}
+	orgID string,
+	UUID uuid.UUID,
+) (uint, error) {
+	if err := r.checkCommonAndUUID(db, orgID, UUID); err != nil {
+		return 0, err
+	}
+	domain := model.Domain{}
+	if err := db.Model(&model.Domain{}).
+		Select("id").
+		First(&domain, "org_id = ? AND domain_uuid = ?", orgID, UUID).
+		Error; err != nil {
+		return 0, r.wrapErrNotFound(err, UUID)
 	}
-	return nil
+	return domain.ID, nil
 }
 
-func (r *domainRepository) updateIpaDomain(
-	log *slog.Logger,
+// findRegistration retrieve the registration of a domain, or nil
+// when the domain has no registration record.
+func (r *domainRepository) findRegistration(
 	db *gorm.DB,
-	dataIPA *model.Ipa,
-) (err error) {
-	if log == nil {
-		err = internal_errors.NilArgError("log")
-		slog.Default().Error(err.Error())
-		return err
-	}
-	if db == nil {
-		err = internal_errors.NilArgError("db")
-		log.Error(err.Error())
-		return err
-	}
-	if dataIPA == nil {
-		err = internal_errors.NilArgError("dataIPA")
-		log.Error(err.Error())
-		return err
-	}
-	// This check avoid the Delete operation could
-	// delete all the records, to prevent that future
-	// changes could evoke not wished behaviors
-	if dataIPA.Model.ID == 0 {
-		err = fmt.Errorf("dataIPA.Model.ID cannot be 0")
-		log.Error(err.Error())
-		return err
-	}
-	domainID := dataIPA.Model.ID
-	if err = db.Unscoped().
-		Delete(dataIPA).Error; err != nil {
-		log.Error("updating ipa domain when deleting old record")
-		return err
-	}
-
-	// Being sure the same ID is used, as the relationship
-	// to domains entiry is 1-1
-	dataIPA.ID = domainID
-	if err = db.Omit(clause.Associations).
-		Create(dataIPA).
+	domainID uint,
+) (*model.DomainRegistration, error) {
+	output := &model.DomainRegistration{}
+	if err := db.
+		First(output, "id = ?", domainID).
 		Error; err != nil {
-		log.Error("updating ipa domain when creating new ipa record")
-		return err
-	}
-
-	// CaCerts
-	for i := range dataIPA.CaCerts {
-		dataIPA.CaCerts[i].Model.ID = 0
-		dataIPA.CaCerts[i].IpaID = dataIPA.ID
-		if err = db.Create(&dataIPA.CaCerts[i]).Error; err != nil {
-			log.Error("updating ipa domain when creating new ipa certificate record")
-			return err
-		}
-	}
-
-	// Servers
-	for i := range dataIPA.Servers {
-		dataIPA.Servers[i].Model.ID = 0
-		dataIPA.Servers[i].IpaID = dataIPA.ID
-		if err = db.Create(&dataIPA.Servers[i]).Error; err != nil {
-			log.Error("updating ipa domain when creating new ipa server record")
-			return err
-		}
-	}
-
-	// Locations
-	for i := range dataIPA.Locations {
-		dataIPA.Locations[i].Model.ID = 0
-		dataIPA.Locations[i].IpaID = dataIPA.ID
-		if err = db.Create(&dataIPA.Locations[i]).Error; err != nil {
-			log.Error("updating ipa domain when creating new ipa location record")
-			return err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
+		return nil, err
 	}
-
-	return nil
+	return output, nil
 }
 
 func (r *domainRepository) wrapErrNotFound(err error, UUID uuid.UUID) error {
 	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return internal_errors.NewHTTPErrorF(
-			http.StatusNotFound,
+		return internal_errors.ErrDomainNotFound.New(
+			err,
 			"unknown domain '%s'",
 			UUID.String(),
 		)
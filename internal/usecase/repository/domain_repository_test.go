@@ -23,6 +23,7 @@ import (
 	"github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
 	builder_model "github.com/podengo-project/idmsvc-backend/internal/test/builder/model"
 	test_sql "github.com/podengo-project/idmsvc-backend/internal/test/sql"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -48,57 +49,6 @@ func (s *DomainRepositorySuite) TestNewDomainRepository() {
 	})
 }
 
-func (s *DomainRepositorySuite) TestCreateIpaDomain() {
-	t := s.Suite.T()
-	domainID := uint(1)
-	data := test.BuildDomainModel(test.OrgId, domainID)
-	var (
-		err         error
-		expectedErr error
-	)
-
-	// Check nil
-	expectedErr = fmt.Errorf("code=500, message='data' cannot be nil")
-	err = s.repository.createIpaDomain(s.Log, s.DB, domainID, nil)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Error on INSERT INTO "ipas"
-	expectedErr = fmt.Errorf(`error at INSERT INTO "ipas"`)
-	test_sql.CreateIpaDomain(1, s.mock, expectedErr, domainID, data.IpaDomain)
-	err = s.repository.createIpaDomain(s.Log, s.DB, domainID, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Error on INSERT INTO "ipa_certs"
-	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_certs"`)
-	test_sql.CreateIpaDomain(2, s.mock, expectedErr, domainID, data.IpaDomain)
-	err = s.repository.createIpaDomain(s.Log, s.DB, domainID, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Error on INSERT INTO "ipa_servers"
-	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_servers"`)
-	test_sql.CreateIpaDomain(3, s.mock, expectedErr, domainID, data.IpaDomain)
-	err = s.repository.createIpaDomain(s.Log, s.DB, domainID, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Error on INSERT INTO "ipa_locations"
-	expectedErr = fmt.Errorf(`error at INSERT INTO "ipa_locations"`)
-	test_sql.CreateIpaDomain(4, s.mock, expectedErr, domainID, data.IpaDomain)
-	err = s.repository.createIpaDomain(s.Log, s.DB, domainID, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Success scenario
-	expectedErr = nil
-	test_sql.CreateIpaDomain(4, s.mock, nil, domainID, data.IpaDomain)
-	err = s.repository.createIpaDomain(s.Log, s.DB, domainID, data.IpaDomain)
-	assert.NoError(t, err)
-	require.NoError(t, s.mock.ExpectationsWereMet())
-}
-
 func (s *DomainRepositorySuite) TestUpdateAgent() {
 	t := s.Suite.T()
 	orgID := test.OrgId
@@ -129,7 +79,7 @@ func (s *DomainRepositorySuite) TestUpdateAgent() {
 	expectedErr := fmt.Errorf("Domain.Model.ID cannot be 0")
 	s.mock.MatchExpectationsInOrder(true)
 	data.Model.ID = 0
-	data.IpaDomain.Model.ID = 0
+	ipa.Data(data).Model.ID = 0
 	test_sql.UpdateAgent(0, s.mock, expectedErr, domainID, data)
 	err = s.repository.UpdateAgent(s.Ctx, orgID, data)
 	require.EqualError(t, err, expectedErr.Error())
@@ -138,7 +88,7 @@ func (s *DomainRepositorySuite) TestUpdateAgent() {
 	expectedErr = fmt.Errorf("error at record not found")
 	s.mock.MatchExpectationsInOrder(true)
 	data.Model.ID = domainID
-	data.IpaDomain.Model.ID = domainID
+	ipa.Data(data).Model.ID = domainID
 	test_sql.UpdateAgent(1, s.mock, expectedErr, domainID, data)
 	err = s.repository.UpdateAgent(s.Ctx, orgID, data)
 	require.EqualError(t, err, expectedErr.Error())
@@ -174,94 +124,6 @@ func (s *DomainRepositorySuite) TestUpdateAgent() {
 	require.NoError(t, s.mock.ExpectationsWereMet())
 }
 
-func (s *DomainRepositorySuite) TestUpdateIpaDomain() {
-	var (
-		err         error
-		expectedErr error
-	)
-	t := s.Suite.T()
-	orgID := "11111"
-	domainID := uint(1)
-	data := test.BuildDomainModel(orgID, domainID)
-
-	// Wrong arguments: log is nil
-	expectedErr = internal_errors.NilArgError("log")
-	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
-	err = s.repository.updateIpaDomain(nil, nil, nil)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Wrong arguments: db is nil
-	expectedErr = internal_errors.NilArgError("db")
-	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
-	err = s.repository.updateIpaDomain(s.Log, nil, nil)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Wrong arguments: dataIPA is nil
-	expectedErr = internal_errors.NilArgError("dataIPA")
-	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
-	err = s.repository.updateIpaDomain(s.Log, s.DB, nil)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Wrong arguments: Ipa.ID is 0 when trying to update ipa information
-	expectedErr = fmt.Errorf("dataIPA.Model.ID cannot be 0")
-	test_sql.UpdateIpaDomain(0, s.mock, expectedErr, domainID, data)
-	data.IpaDomain.Model.ID = 0
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// database error at DELETE FROM 'ipas'
-	expectedErr = fmt.Errorf("database error at DELETE FROM 'ipas'")
-	test_sql.UpdateIpaDomain(1, s.mock, expectedErr, domainID, data)
-	data.IpaDomain.Model.ID = domainID
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// database error at INSERT INTO 'ipas'
-	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipas'")
-	test_sql.UpdateIpaDomain(2, s.mock, expectedErr, domainID, data)
-	data.IpaDomain.Model.ID = domainID
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// database error at INSERT INTO 'ipa_certs'
-	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipa_certs'")
-	test_sql.UpdateIpaDomain(3, s.mock, expectedErr, domainID, data)
-	data.IpaDomain.Model.ID = domainID
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// database error at INSERT INTO 'ipa_servers'
-	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipa_servers'")
-	test_sql.UpdateIpaDomain(4, s.mock, expectedErr, domainID, data)
-	data.IpaDomain.Model.ID = domainID
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// database error at INSERT INTO 'ipa_locations'
-	expectedErr = fmt.Errorf("database error at INSERT INTO 'ipa_locations'")
-	test_sql.UpdateIpaDomain(5, s.mock, expectedErr, domainID, data)
-	data.IpaDomain.Model.ID = domainID
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.EqualError(t, err, expectedErr.Error())
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-	// Success scenario
-	expectedErr = nil
-	test_sql.UpdateIpaDomain(5, s.mock, expectedErr, domainID, data)
-	err = s.repository.updateIpaDomain(s.Log, s.DB, data.IpaDomain)
-	require.NoError(t, err)
-	require.NoError(t, s.mock.ExpectationsWereMet())
-
-}
-
 func (s *DomainRepositorySuite) TestList() {
 	t := s.T()
 	r := &domainRepository{}
@@ -282,8 +144,8 @@ func (s *DomainRepositorySuite) TestList() {
 		Title:                 pointy.String("My Domain Example"),
 		Description:           pointy.String("Description of My Domain Example"),
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain: &model.Ipa{
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
 			Model: gorm.Model{
 				ID:        1,
 				CreatedAt: currentTime,
@@ -291,7 +153,7 @@ func (s *DomainRepositorySuite) TestList() {
 				DeletedAt: gorm.DeletedAt{},
 			},
 			RealmName: pointy.String("MYDOMAIN.EXAMPLE"),
-			CaCerts: []model.IpaCert{
+			CaCerts: []ipa.IpaCert{
 				{
 					Model: gorm.Model{
 						ID:        1,
@@ -309,7 +171,7 @@ func (s *DomainRepositorySuite) TestList() {
 					Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----",
 				},
 			},
-			Servers: []model.IpaServer{
+			Servers: []ipa.IpaServer{
 				{
 					Model: gorm.Model{
 						ID:        1,
@@ -335,7 +197,7 @@ func (s *DomainRepositorySuite) TestList() {
 	ctx := context.TODO()
 	ctx = app_context.CtxWithLog(ctx, slog.Default())
 	ctx = app_context.CtxWithDB(ctx, s.DB)
-	output, count, err := r.List(ctx, "", -1, -1)
+	output, count, err := r.List(ctx, "", nil, -1, -1)
 	assert.EqualError(t, err, "'orgID' is empty")
 	assert.Equal(t, int64(0), count)
 	assert.Nil(t, output)
@@ -346,7 +208,7 @@ func (s *DomainRepositorySuite) TestList() {
 	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "domains" WHERE org_id = $1`)).
 		WithArgs(orgID).
 		WillReturnError(fmt.Errorf("an error happened"))
-	output, count, err = r.List(ctx, orgID, offset, limit)
+	output, count, err = r.List(ctx, orgID, nil, offset, limit)
 	assert.EqualError(t, err, "an error happened")
 	assert.Equal(t, int64(0), count)
 	assert.Nil(t, output)
@@ -376,7 +238,7 @@ func (s *DomainRepositorySuite) TestList() {
 			data.Type,
 			data.AutoEnrollmentEnabled,
 		))
-	output, count, err = r.List(ctx, orgID, offset, limit)
+	output, count, err = r.List(ctx, orgID, nil, offset, limit)
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), count)
 	assert.Equal(t, []model.Domain{
@@ -392,9 +254,21 @@ func (s *DomainRepositorySuite) TestList() {
 			DomainName:            data.DomainName,
 			Title:                 data.Title,
 			Description:           data.Description,
-			Type:                  pointy.Uint(model.DomainTypeIpa),
+			Type:                  pointy.Uint(ipa.TypeID),
 		},
 	}, output)
+
+	// Restricted to some domains
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "domains" WHERE org_id = $1 AND domain_uuid IN ($2)`)).
+		WithArgs(orgID, data.DomainUuid).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "domains" WHERE org_id = $1 AND domain_uuid IN ($2) AND "domains"."deleted_at" IS NULL LIMIT $3`)).
+		WithArgs(orgID, data.DomainUuid, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	output, count, err = r.List(ctx, orgID, []uuid.UUID{data.DomainUuid}, offset, limit)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	assert.Empty(t, output)
 }
 
 func (s *DomainRepositorySuite) TestFindByID() {
@@ -421,8 +295,8 @@ func (s *DomainRepositorySuite) TestFindByID() {
 		Title:                 pointy.String("My Example Domain"),
 		Description:           pointy.String("My long description for my example domain"),
 		AutoEnrollmentEnabled: pointy.Bool(true),
-		Type:                  pointy.Uint(model.DomainTypeIpa),
-		IpaDomain: &model.Ipa{
+		Type:                  pointy.Uint(ipa.TypeID),
+		TypeData: &ipa.Ipa{
 			Model: gorm.Model{
 				ID:        domainID,
 				CreatedAt: currentTime,
@@ -430,7 +304,7 @@ func (s *DomainRepositorySuite) TestFindByID() {
 			},
 			RealmName:    pointy.String("MYDOMAIN.EXAMPLE"),
 			RealmDomains: pq.StringArray{"mydomain.example"},
-			CaCerts: []model.IpaCert{
+			CaCerts: []ipa.IpaCert{
 				{
 					Model: gorm.Model{
 						CreatedAt: currentTime,
@@ -446,7 +320,7 @@ func (s *DomainRepositorySuite) TestFindByID() {
 					Pem:          "-----BEGIN CERTIFICATE-----\nMII...\n-----END CERTIFICATE-----\n",
 				},
 			},
-			Servers: []model.IpaServer{
+			Servers: []ipa.IpaServer{
 				{
 					Model: gorm.Model{
 						CreatedAt: currentTime,
@@ -462,7 +336,7 @@ func (s *DomainRepositorySuite) TestFindByID() {
 					PKInitServer:        true,
 				},
 			},
-			Locations: []model.IpaLocation{
+			Locations: []ipa.IpaLocation{
 				{
 					Model: gorm.Model{
 						CreatedAt: currentTime,
@@ -484,7 +358,7 @@ func (s *DomainRepositorySuite) TestFindByID() {
 		Description:           data.Description,
 		Type:                  nil,
 		AutoEnrollmentEnabled: data.AutoEnrollmentEnabled,
-		IpaDomain:             data.IpaDomain,
+		TypeData:              ipa.Data(data),
 	}
 	var expectedErr error
 	var err error
@@ -529,7 +403,7 @@ func (s *DomainRepositorySuite) TestFindByID() {
 	// Successful scenario
 	expectedErr = nil
 	test_sql.FindByID(1, s.mock, nil, domainID, data)
-	test_sql.FindIpaByID(4, s.mock, expectedErr, domainID, data)
+	test_sql.FindIpaByID(6, s.mock, expectedErr, domainID, data)
 	domain, err = r.FindByID(s.Ctx, data.OrgId, data.DomainUuid)
 	require.NoError(t, s.mock.ExpectationsWereMet())
 	assert.NoError(t, err)
@@ -557,7 +431,7 @@ func (s *DomainRepositorySuite) TestUpdateUser() {
 	domainID := uint(1)
 	data := test.BuildDomainModel(orgID, domainID)
 	data.Model.ID = domainID
-	data.IpaDomain.Model.ID = domainID
+	ipa.Data(data).Model.ID = domainID
 	c := app_context.CtxWithLog(app_context.CtxWithDB(context.Background(), s.DB), slog.Default())
 
 	// ctx is nil
@@ -601,6 +475,326 @@ func (s *DomainRepositorySuite) TestUpdateUser() {
 	require.NoError(t, s.mock.ExpectationsWereMet())
 }
 
+func (s *DomainRepositorySuite) expectFindDomainID(orgID string, UUID uuid.UUID) *sqlmock.ExpectedQuery {
+	return s.mock.ExpectQuery(regexp.QuoteMeta(
+		`SELECT "id" FROM "domains" `+
+			`WHERE (org_id = $1 AND domain_uuid = $2) AND "domains"."deleted_at" IS NULL `+
+			`ORDER BY "domains"."id" LIMIT $3`,
+	)).WithArgs(orgID, UUID, 1)
+}
+
+func (s *DomainRepositorySuite) TestListLocationSubnets() {
+	t := s.Suite.T()
+	orgID := test.OrgId
+	UUID := uuid.New()
+	expectList := func() *sqlmock.ExpectedQuery {
+		return s.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "domain_location_subnets" ` +
+				`WHERE domain_id = $1 AND "domain_location_subnets"."deleted_at" IS NULL ` +
+				`ORDER BY id`,
+		)).WithArgs(1)
+	}
+	columns := []string{"id", "domain_id", "subnet", "location"}
+
+	// guard orgID is empty
+	output, err := s.repository.ListLocationSubnets(s.Ctx, "", UUID)
+	assert.Nil(t, output)
+	require.EqualError(t, err, "'orgID' is empty")
+
+	// unknown domain
+	s.expectFindDomainID(orgID, UUID).WillReturnError(gorm.ErrRecordNotFound)
+	output, err = s.repository.ListLocationSubnets(s.Ctx, orgID, UUID)
+	assert.Nil(t, output)
+	require.EqualError(t, err, fmt.Sprintf("code=404, message=unknown domain '%s', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found", UUID.String()))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error
+	s.expectFindDomainID(orgID, UUID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectList().WillReturnError(gorm.ErrInvalidTransaction)
+	output, err = s.repository.ListLocationSubnets(s.Ctx, orgID, UUID)
+	assert.Nil(t, output)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// success
+	s.expectFindDomainID(orgID, UUID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectList().WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(1, 1, "192.0.2.0/24", "alpha").
+		AddRow(2, 1, "2001:db8::/32", "beta"))
+	output, err = s.repository.ListLocationSubnets(s.Ctx, orgID, UUID)
+	require.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+	require.Len(t, output, 2)
+	assert.Equal(t, "192.0.2.0/24", output[0].Subnet)
+	assert.Equal(t, "alpha", output[0].Location)
+	assert.Equal(t, "2001:db8::/32", output[1].Subnet)
+	assert.Equal(t, "beta", output[1].Location)
+}
+
+func (s *DomainRepositorySuite) TestUpdateLocationSubnets() {
+	t := s.Suite.T()
+	orgID := test.OrgId
+	UUID := uuid.New()
+	data := []model.DomainLocationSubnet{
+		{Subnet: "192.0.2.0/24", Location: "alpha"},
+	}
+	expectDelete := func() *sqlmock.ExpectedExec {
+		return s.mock.ExpectExec(regexp.QuoteMeta(
+			`DELETE FROM "domain_location_subnets" WHERE domain_id = $1`,
+		)).WithArgs(1)
+	}
+	expectInsert := func() *sqlmock.ExpectedQuery {
+		return s.mock.ExpectQuery(regexp.QuoteMeta(
+			`INSERT INTO "domain_location_subnets" `+
+				`("created_at","updated_at","deleted_at","domain_id","subnet","location") `+
+				`VALUES ($1,$2,$3,$4,$5,$6) RETURNING "id"`,
+		)).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 1, "192.0.2.0/24", "alpha")
+	}
+
+	// guard orgID is empty
+	require.EqualError(t, s.repository.UpdateLocationSubnets(s.Ctx, "", UUID, data), "'orgID' is empty")
+
+	// unknown domain
+	s.expectFindDomainID(orgID, UUID).WillReturnError(gorm.ErrRecordNotFound)
+	err := s.repository.UpdateLocationSubnets(s.Ctx, orgID, UUID, data)
+	require.EqualError(t, err, fmt.Sprintf("code=404, message=unknown domain '%s', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found", UUID.String()))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// error deleting the old mappings
+	s.expectFindDomainID(orgID, UUID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectDelete().WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.UpdateLocationSubnets(s.Ctx, orgID, UUID, data)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// error creating the new mappings
+	s.expectFindDomainID(orgID, UUID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectDelete().WillReturnResult(sqlmock.NewResult(0, 2))
+	expectInsert().WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.UpdateLocationSubnets(s.Ctx, orgID, UUID, data)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// success
+	s.expectFindDomainID(orgID, UUID).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectDelete().WillReturnResult(sqlmock.NewResult(0, 2))
+	expectInsert().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	err = s.repository.UpdateLocationSubnets(s.Ctx, orgID, UUID, data)
+	require.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+	assert.Equal(t, uint(1), data[0].DomainID)
+	assert.Equal(t, uint(3), data[0].ID)
+}
+
+func (s *DomainRepositorySuite) TestFindByIDPendingApproval() {
+	t := s.Suite.T()
+	orgID := test.OrgId
+	domainID := uint(1)
+	data := test.BuildDomainModel(orgID, domainID)
+	data.Model.ID = domainID
+	ipa.Data(data).Model.ID = domainID
+	data.RegistrationState = model.DomainRegistrationPendingApproval
+	expectRegistration := func() *sqlmock.ExpectedQuery {
+		return s.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "domain_registrations" WHERE id = $1 ORDER BY "domain_registrations"."id" LIMIT $2`,
+		)).WithArgs(domainID, 1)
+	}
+
+	// error reading the registration
+	test_sql.FindByID(1, s.mock, nil, domainID, data)
+	test_sql.FindIpaByID(6, s.mock, nil, domainID, data)
+	expectRegistration().WillReturnError(gorm.ErrInvalidTransaction)
+	output, err := s.repository.FindByID(s.Ctx, orgID, data.DomainUuid)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.Nil(t, output)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// success
+	test_sql.FindByID(1, s.mock, nil, domainID, data)
+	test_sql.FindIpaByID(6, s.mock, nil, domainID, data)
+	expectRegistration().WillReturnRows(sqlmock.NewRows([]string{
+		"id", "subscription_manager_id", "ipa_hcc_version", "ca_certs",
+	}).AddRow(domainID, test.Server1.CertCN, "0.12", "[]"))
+	output, err = s.repository.FindByID(s.Ctx, orgID, data.DomainUuid)
+	require.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+	require.NotNil(t, output.Registration)
+	assert.Equal(t, domainID, output.Registration.ID)
+	assert.Equal(t, test.Server1.CertCN, output.Registration.SubscriptionManagerID.String())
+	assert.Equal(t, "0.12", output.Registration.IpaHccVersion)
+	assert.Equal(t, []model.RegistrationCert{}, output.Registration.CaCerts)
+}
+
+func (s *DomainRepositorySuite) TestFindOrgSettings() {
+	t := s.Suite.T()
+	orgID := test.OrgId
+	query := regexp.QuoteMeta(`SELECT * FROM "org_settings" WHERE org_id = $1 ORDER BY "org_settings"."org_id" LIMIT $2`)
+
+	// orgID is empty
+	settings, err := s.repository.FindOrgSettings(s.Ctx, "")
+	assert.Nil(t, settings)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at First
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, 1).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	settings, err = s.repository.FindOrgSettings(s.Ctx, orgID)
+	assert.Nil(t, settings)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// No settings
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"org_id"}))
+	settings, err = s.repository.FindOrgSettings(s.Ctx, orgID)
+	assert.Nil(t, settings)
+	assert.NoError(t, err)
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"org_id", "registration_approval"}).
+			AddRow(orgID, true))
+	settings, err = s.repository.FindOrgSettings(s.Ctx, orgID)
+	require.NoError(t, err)
+	require.NotNil(t, settings)
+	assert.Equal(t, orgID, settings.OrgID)
+	assert.True(t, settings.RequireRegistrationApproval(false))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *DomainRepositorySuite) TestSaveOrgSettings() {
+	t := s.Suite.T()
+	data := &model.OrgSettings{
+		OrgID:                test.OrgId,
+		RegistrationApproval: pointy.Bool(true),
+	}
+	query := regexp.QuoteMeta(`INSERT INTO "org_settings" ("org_id","created_at","updated_at","registration_approval") VALUES ($1,$2,$3,$4) ON CONFLICT ("org_id") DO UPDATE SET "updated_at"="excluded"."updated_at","registration_approval"="excluded"."registration_approval"`)
+
+	// data is nil
+	err := s.repository.SaveOrgSettings(s.Ctx, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// orgID is empty
+	err = s.repository.SaveOrgSettings(s.Ctx, &model.OrgSettings{})
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at Create
+	s.mock.ExpectExec(query).
+		WithArgs(test.OrgId, sqlmock.AnyArg(), sqlmock.AnyArg(), true).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.SaveOrgSettings(s.Ctx, data)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success clearing the setting
+	data.RegistrationApproval = nil
+	s.mock.ExpectExec(query).
+		WithArgs(test.OrgId, sqlmock.AnyArg(), sqlmock.AnyArg(), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = s.repository.SaveOrgSettings(s.Ctx, data)
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *DomainRepositorySuite) TestFindRegistration() {
+	t := s.Suite.T()
+	orgID := test.OrgId
+	data := &model.Domain{Model: gorm.Model{ID: 1}}
+	query := regexp.QuoteMeta(`SELECT * FROM "domain_registrations" WHERE id = $1 ORDER BY "domain_registrations"."id" LIMIT $2`)
+
+	// data is nil
+	err := s.repository.FindRegistration(s.Ctx, orgID, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// Error at First
+	s.mock.ExpectQuery(query).
+		WithArgs(1, 1).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.FindRegistration(s.Ctx, orgID, data)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// No registration
+	s.mock.ExpectQuery(query).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	err = s.repository.FindRegistration(s.Ctx, orgID, data)
+	assert.NoError(t, err)
+	assert.Nil(t, data.Registration)
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "subscription_manager_id", "ipa_hcc_version", "ca_certs", "decided_by",
+		}).AddRow(1, test.Server1.CertCN, "0.12", "[]", "jdoe"))
+	err = s.repository.FindRegistration(s.Ctx, orgID, data)
+	require.NoError(t, err)
+	require.NotNil(t, data.Registration)
+	assert.Equal(t, "0.12", data.Registration.IpaHccVersion)
+	assert.Equal(t, pointy.String("jdoe"), data.Registration.DecidedBy)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *DomainRepositorySuite) TestDecideRegistration() {
+	t := s.Suite.T()
+	orgID := test.OrgId
+	UUID := uuid.New()
+	decidedAt := time.Now()
+	data := &model.Domain{
+		Model:             gorm.Model{ID: 1},
+		OrgId:             orgID,
+		DomainUuid:        UUID,
+		RegistrationState: model.DomainRegistrationActive,
+		Registration: &model.DomainRegistration{
+			DecidedBy: pointy.String("jdoe"),
+			DecidedAt: &decidedAt,
+		},
+	}
+	expectState := func() *sqlmock.ExpectedExec {
+		return s.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "domains" SET "registration_state"=$1 `+
+				`WHERE (org_id = $2 AND domain_uuid = $3 AND registration_state = $4) AND "domains"."deleted_at" IS NULL`,
+		)).WithArgs(model.DomainRegistrationActive, orgID, UUID, model.DomainRegistrationPendingApproval)
+	}
+	expectDecision := func() *sqlmock.ExpectedExec {
+		return s.mock.ExpectExec(regexp.QuoteMeta(
+			`UPDATE "domain_registrations" SET "decided_at"=$1,"decided_by"=$2,"reason"=$3 WHERE id = $4`,
+		)).WithArgs(&decidedAt, pointy.String("jdoe"), nil, 1)
+	}
+
+	// guards
+	require.EqualError(t, s.repository.DecideRegistration(s.Ctx, "", data), "'orgID' is empty")
+	require.EqualError(t, s.repository.DecideRegistration(s.Ctx, orgID, nil), "code=500, message='data' cannot be nil")
+	require.EqualError(t, s.repository.DecideRegistration(s.Ctx, orgID, &model.Domain{}), "code=500, message='Registration' cannot be nil")
+
+	// error updating the state
+	expectState().WillReturnError(gorm.ErrInvalidTransaction)
+	err := s.repository.DecideRegistration(s.Ctx, orgID, data)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// the domain is not pending approval
+	expectState().WillReturnResult(sqlmock.NewResult(0, 0))
+	err = s.repository.DecideRegistration(s.Ctx, orgID, data)
+	require.EqualError(t, err, fmt.Sprintf("code=409, message=domain '%s' is not pending approval, internal=IDMSVC-DOMAIN-NOT-PENDING", UUID.String()))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// error updating the decision
+	expectState().WillReturnResult(sqlmock.NewResult(0, 1))
+	expectDecision().WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.DecideRegistration(s.Ctx, orgID, data)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// success
+	expectState().WillReturnResult(sqlmock.NewResult(0, 1))
+	expectDecision().WillReturnResult(sqlmock.NewResult(0, 1))
+	err = s.repository.DecideRegistration(s.Ctx, orgID, data)
+	require.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
 // ---------------- Test for private methods ---------------------
 
 func (s *DomainRepositorySuite) TestCheckCommon() {
@@ -662,7 +856,7 @@ func (s *DomainRepositorySuite) TestCheckCommonAndDataAndType() {
 	assert.EqualError(t, err, "code=500, message='Type' cannot be nil")
 
 	err = r.checkCommonAndDataAndType(s.DB, "12345", &model.Domain{
-		Type: pointy.Uint(model.DomainTypeIpa),
+		Type: pointy.Uint(ipa.TypeID),
 	})
 	assert.NoError(t, err)
 }
@@ -675,7 +869,7 @@ func (s *DomainRepositorySuite) TestCreateDomainToken() {
 	)
 	t := s.T()
 	r := &domainRepository{}
-	drt, err := r.CreateDomainToken(s.Ctx, key, validity, testOrgID, public.RhelIdm)
+	drt, err := r.CreateDomainToken(s.Ctx, key, validity, testOrgID, public.RhelIdm, domain_token.Claims{})
 	assert.NoError(t, err)
 	assert.Equal(t, drt.DomainType, public.RhelIdm)
 	assert.NotEmpty(t, drt.DomainId)
@@ -685,6 +879,22 @@ func (s *DomainRepositorySuite) TestCreateDomainToken() {
 		domain_token.TokenDomainId(domain_token.DomainRegistrationToken(drt.DomainToken)),
 	)
 	assert.Greater(t, drt.ExpirationNS, uint64(time.Now().UnixNano()))
+
+	claims := domain_token.Claims{DomainName: "mydomain.example", Label: "ticket 1234"}
+	drt, err = r.CreateDomainToken(s.Ctx, key, validity, testOrgID, public.RhelIdm, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, claims, drt.Claims)
+	_, tokenClaims, err := domain_token.VerifyDomainRegistrationTokenKeys(
+		[][]byte{key}, string(public.RhelIdm), testOrgID,
+		domain_token.DomainRegistrationToken(drt.DomainToken),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, claims, tokenClaims)
+
+	claims.Label = strings.Repeat("x", domain_token.LabelMaxLength+1)
+	drt, err = r.CreateDomainToken(s.Ctx, key, validity, testOrgID, public.RhelIdm, claims)
+	assert.Nil(t, drt)
+	assert.Error(t, err)
 }
 
 func (s *DomainRepositorySuite) TestPrepareUpdateUser() {
@@ -754,7 +964,7 @@ func (s *DomainRepositorySuite) TestDeleteById() {
 	})
 	require.NoError(t, s.mock.ExpectationsWereMet())
 
-	expectedErr = fmt.Errorf("code=404, message=unknown domain '%s'", d.DomainUuid.String())
+	expectedErr = fmt.Errorf("code=404, message=unknown domain '%s', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found", d.DomainUuid.String())
 	test_sql.DeleteByID(1, s.mock, gorm.ErrRecordNotFound, d)
 	err := r.DeleteById(s.Ctx, d.OrgId, d.DomainUuid)
 	require.EqualError(t, err, expectedErr.Error())
@@ -766,13 +976,13 @@ func (s *DomainRepositorySuite) TestDeleteById() {
 	require.EqualError(t, err, expectedErr.Error())
 	require.NoError(t, s.mock.ExpectationsWereMet())
 
-	expectedErr = fmt.Errorf("code=404, message=unknown domain '%s'", d.DomainUuid.String())
+	expectedErr = fmt.Errorf("code=404, message=unknown domain '%s', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found", d.DomainUuid.String())
 	test_sql.DeleteByID(2, s.mock, gorm.ErrRecordNotFound, d)
 	err = r.DeleteById(s.Ctx, d.OrgId, d.DomainUuid)
 	require.EqualError(t, err, expectedErr.Error())
 	require.NoError(t, s.mock.ExpectationsWereMet())
 
-	expectedErr = fmt.Errorf("code=404, message=unknown domain '%s'", d.DomainUuid.String())
+	expectedErr = fmt.Errorf("code=404, message=unknown domain '%s', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found", d.DomainUuid.String())
 	test_sql.DeleteByID(3, s.mock, gorm.ErrRecordNotFound, d)
 	err = r.DeleteById(s.Ctx, d.OrgId, d.DomainUuid)
 	require.EqualError(t, err, expectedErr.Error())
@@ -802,7 +1012,7 @@ func (s *DomainRepositorySuite) TestWrapErrNotFound() {
 	require.NoError(t, err)
 
 	err = r.wrapErrNotFound(gorm.ErrRecordNotFound, UUID)
-	require.EqualError(t, err, fmt.Sprintf("code=404, message=unknown domain '%s'", UUID.String()))
+	require.EqualError(t, err, fmt.Sprintf("code=404, message=unknown domain '%s', internal=IDMSVC-DOMAIN-NOT-FOUND: record not found", UUID.String()))
 }
 
 func (s *DomainRepositorySuite) TestRegister() {
@@ -830,7 +1040,7 @@ func (s *DomainRepositorySuite) TestRegister() {
 	expectedErr = gorm.ErrDuplicatedKey
 	test_sql.Register(1, s.mock, expectedErr, d)
 	err = r.Register(s.Ctx, d.OrgId, d)
-	require.EqualError(t, err, fmt.Sprintf("code=409, message=domain id '%s' is already registered.", d.DomainUuid))
+	require.EqualError(t, err, fmt.Sprintf("code=409, message=domain id '%s' is already registered., internal=IDMSVC-DOMAIN-ALREADY-EXISTS", d.DomainUuid))
 	require.NoError(t, s.mock.ExpectationsWereMet())
 
 	expectedErr = gorm.ErrInvalidField
@@ -845,17 +1055,17 @@ func (s *DomainRepositorySuite) TestRegister() {
 				WithModel(gormModel).
 				WithRealmName(&realm).
 				WithRealmDomains(pq.StringArray{strings.ToLower(realm)}).
-				WithServers([]model.IpaServer{
+				WithServers([]ipa.IpaServer{
 					builder_model.NewIpaServer(
 						builder_model.NewModel().Build(),
 					).WithIpaID(domainID).Build(),
 				}).
-				WithLocations([]model.IpaLocation{
+				WithLocations([]ipa.IpaLocation{
 					builder_model.NewIpaLocation(
 						builder_model.NewModel().Build(),
 					).WithIpaID(domainID).Build(),
 				}).
-				WithCaCerts([]model.IpaCert{
+				WithCaCerts([]ipa.IpaCert{
 					builder_model.NewIpaCert(
 						builder_model.NewModel().Build(),
 						realm,
@@ -864,23 +1074,23 @@ func (s *DomainRepositorySuite) TestRegister() {
 				Build(),
 		).Build()
 	test_sql.Register(1, s.mock, nil, d)
-	test_sql.CreateIpaDomain(1, s.mock, gorm.ErrInvalidField, domainID, d.IpaDomain)
+	test_sql.CreateIpaDomain(1, s.mock, gorm.ErrInvalidField, domainID, ipa.Data(d))
 	err = r.Register(s.Ctx, d.OrgId, d)
 	require.EqualError(t, err, "invalid field")
 	require.NoError(t, s.mock.ExpectationsWereMet())
 
 	// Success case - FIXME Flaky test
 	test_sql.Register(1, s.mock, nil, d)
-	test_sql.CreateIpaDomain(4, s.mock, nil, domainID, d.IpaDomain)
+	test_sql.CreateIpaDomain(4, s.mock, nil, domainID, ipa.Data(d))
 	err = r.Register(s.Ctx, d.OrgId, d)
 	require.NoError(t, err)
 	require.NoError(t, s.mock.ExpectationsWereMet())
 
 	// IpaDomain is nil
 	test_sql.Register(1, s.mock, nil, d)
-	d.IpaDomain = nil
+	d.TypeData = nil
 	err = r.Register(s.Ctx, d.OrgId, d)
-	require.EqualError(t, err, "code=500, message='IpaDomain' cannot be nil")
+	require.EqualError(t, err, "code=500, message='TypeData' cannot be nil")
 	require.NoError(t, s.mock.ExpectationsWereMet())
 }
 
@@ -3,7 +3,6 @@ package repository
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
@@ -27,7 +26,8 @@ func NewHostRepository() repository.HostRepository {
 // Return an error when either no matching domain is found or multiple
 // domains are matching.
 //
-// Exclude domains with auto_enrollment_enabled = FALSE.
+// Exclude domains with auto_enrollment_enabled = FALSE, and the
+// domains which registration is pending approval or rejected.
 // ctx is the current request context with db and slog instances.
 // options provide filtering information to select the domain.
 // Return the matched domain and nil on success, else nil and the error
@@ -70,8 +70,13 @@ func (r *hostRepository) MatchDomain(ctx context.Context, options *interactor.Ho
 		return nil, err
 	}
 
+	notApproved := 0
 	matchedDomains := make([]model.Domain, 0, len(domains))
 	for _, domain := range domains {
+		if !domain.IsRegistrationApproved() {
+			notApproved++
+			continue
+		}
 		if domain.AutoEnrollmentEnabled == nil || !(*domain.AutoEnrollmentEnabled) {
 			continue
 		}
@@ -81,15 +86,21 @@ func (r *hostRepository) MatchDomain(ctx context.Context, options *interactor.Ho
 
 	// only one domain is currently supported. Fail if query found multiple doamins.
 	if len(matchedDomains) < 1 {
-		err = internal_errors.NewHTTPErrorF(
-			http.StatusNotFound,
-			"no matching domains",
-		)
+		if notApproved > 0 {
+			err = internal_errors.ErrHostConfDomainNotApproved.New(nil, "no approved domains")
+		} else if len(domains) > 0 {
+			err = internal_errors.ErrHostConfAutoEnrollmentDisabled.New(
+				repository.ErrAutoEnrollmentDisabled,
+				"no matching domains",
+			)
+		} else {
+			err = internal_errors.ErrHostConfNoMatch.New(nil, "no matching domains")
+		}
 		log.Error("no matching domains")
 		return nil, err
 	} else if len(matchedDomains) > 1 {
-		err = internal_errors.NewHTTPErrorF(
-			http.StatusConflict,
+		err = internal_errors.ErrHostConfMultipleMatches.New(
+			nil,
 			"matched %d domains, only one expected",
 			len(matchedDomains),
 		)
@@ -98,7 +109,7 @@ func (r *hostRepository) MatchDomain(ctx context.Context, options *interactor.Ho
 	}
 
 	// verify and fill domain object
-	output = &domains[0]
+	output = &matchedDomains[0]
 	if err = output.FillAndPreload(db); err != nil {
 		log.Error(fmt.Sprintf("preloading domain data for output.domain_id = %s", output.DomainUuid.String()))
 		return nil, err
@@ -106,6 +117,54 @@ func (r *hostRepository) MatchDomain(ctx context.Context, options *interactor.Ho
 	return output, nil
 }
 
+// MatchLocation select the location of the requesting host. The
+// location requested by the host takes precedence; otherwise the
+// client subnet is looked up in the subnet to location mappings of
+// the domain, and the most specific match wins.
+// ctx is the current request context with db and slog instances.
+// options provide the location hints of the host.
+// domain is the domain matched for the host.
+// Return the location, or nil if it could not be determined, and nil
+// error on success, else nil and the error instance.
+func (r *hostRepository) MatchLocation(
+	ctx context.Context,
+	options *interactor.HostConfOptions,
+	domain *model.Domain,
+) (location *string, err error) {
+	log := app_context.LogFromCtx(ctx)
+	if options == nil {
+		err = internal_errors.NilArgError("options")
+		log.Error(err.Error())
+		return nil, err
+	}
+	if domain == nil {
+		err = internal_errors.NilArgError("domain")
+		log.Error(err.Error())
+		return nil, err
+	}
+	if options.Location != nil {
+		return options.Location, nil
+	}
+	if options.ClientSubnet == nil {
+		return nil, nil
+	}
+	db := app_context.DBFromCtx(ctx)
+	if db == nil {
+		err = internal_errors.NilArgError("db")
+		log.Error(err.Error())
+		return nil, err
+	}
+	var subnets []model.DomainLocationSubnet
+	if err = db.
+		Where("domain_id = ?", domain.ID).
+		Find(&subnets).
+		Error; err != nil {
+		log.Error("reading the subnet to location mappings of the domain")
+		return nil, err
+	}
+	return model.ResolveLocation(subnets, *options.ClientSubnet), nil
+}
+
 // SignHostConfToken
 // ctx is the current request context with db and slog instances.
 // privs
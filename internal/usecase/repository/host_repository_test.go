@@ -5,18 +5,24 @@ package repository
 import (
 	"context"
 	"log/slog"
+	"net/netip"
+	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	api_public "github.com/podengo-project/idmsvc-backend/internal/api/public"
 	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/interactor"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
 	"github.com/podengo-project/idmsvc-backend/internal/test/builder/helper"
 	builder_model "github.com/podengo-project/idmsvc-backend/internal/test/builder/model"
 	test_sql "github.com/podengo-project/idmsvc-backend/internal/test/sql"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -54,7 +60,7 @@ func (s *SuiteHost) TestMatchDomain() {
 		Fqdn:        fqdn,
 		DomainId:    &domainId,
 		DomainName:  &domainName,
-		DomainType:  (*api_public.DomainType)(pointy.String(model.DomainTypeIpaString)),
+		DomainType:  (*api_public.DomainType)(pointy.String(ipa.TypeName)),
 	}
 	id := uint(helper.GenRandNum(0, 2^63))
 	realm := strings.ToUpper(domainName)
@@ -67,19 +73,19 @@ func (s *SuiteHost) TestMatchDomain() {
 					WithModel(builder_model.NewModel().WithID(id).Build()).
 					WithRealmName(&realm).
 					WithRealmDomains(pq.StringArray{domainName}).
-					WithServers([]model.IpaServer{
+					WithServers([]ipa.IpaServer{
 						builder_model.NewIpaServer(
 							builder_model.NewModel().Build(),
 						).WithIpaID(id).
 							WithFQDN(fqdn).
 							Build(),
 					}).
-					WithLocations([]model.IpaLocation{
+					WithLocations([]ipa.IpaLocation{
 						builder_model.NewIpaLocation(
 							builder_model.NewModel().Build(),
 						).WithIpaID(id).Build(),
 					}).
-					WithCaCerts([]model.IpaCert{
+					WithCaCerts([]ipa.IpaCert{
 						builder_model.NewIpaCert(
 							builder_model.NewModel().Build(),
 							realm,
@@ -120,7 +126,25 @@ func (s *SuiteHost) TestMatchDomain() {
 	test_sql.MatchDomain(1, s.mock, nil, options, domainsEmpty)
 	domain, err = s.repository.MatchDomain(s.Ctx, options)
 	assert.Nil(t, domain)
-	require.EqualError(t, err, "code=404, message=no matching domains")
+	require.EqualError(t, err, "code=404, message=no matching domains, internal=IDMSVC-HOSTCONF-NO-MATCH")
+
+	// Domains without auto-enrollment enabled
+	domainsDisabled := []model.Domain{
+		domains[1],
+	}
+	test_sql.MatchDomain(1, s.mock, nil, options, domainsDisabled)
+	domain, err = s.repository.MatchDomain(s.Ctx, options)
+	assert.Nil(t, domain)
+	require.ErrorIs(t, err, repository.ErrAutoEnrollmentDisabled)
+	assert.Equal(t, internal_errors.ErrHostConfAutoEnrollmentDisabled, internal_errors.DefinitionOf(err))
+
+	// Domains pending approval
+	domainPending := domains[0]
+	domainPending.RegistrationState = model.DomainRegistrationPendingApproval
+	test_sql.MatchDomain(1, s.mock, nil, options, []model.Domain{domainPending, domains[1]})
+	domain, err = s.repository.MatchDomain(s.Ctx, options)
+	assert.Nil(t, domain)
+	require.EqualError(t, err, "code=404, message=no approved domains, internal=IDMSVC-HOSTCONF-DOMAIN-NOT-APPROVED")
 
 	// More than 1 match
 	domainsMoreThan1 := []model.Domain{
@@ -130,7 +154,7 @@ func (s *SuiteHost) TestMatchDomain() {
 	test_sql.MatchDomain(1, s.mock, nil, options, domainsMoreThan1)
 	domain, err = s.repository.MatchDomain(s.Ctx, options)
 	assert.Nil(t, domain)
-	require.EqualError(t, err, "code=409, message=matched 2 domains, only one expected")
+	require.EqualError(t, err, "code=409, message=matched 2 domains, only one expected, internal=IDMSVC-HOSTCONF-MULTIPLE-MATCHES")
 
 	// Success
 	test_sql.MatchDomain(2, s.mock, nil, options, domains)
@@ -151,7 +175,7 @@ func (s *SuiteHost) TestSignHostConfToken() {
 		Fqdn:        fqdn,
 		DomainId:    &domainId,
 		DomainName:  &domainName,
-		DomainType:  (*api_public.DomainType)(pointy.String(model.DomainTypeIpaString)),
+		DomainType:  (*api_public.DomainType)(pointy.String(ipa.TypeName)),
 	}
 	id := uint(helper.GenRandNum(0, 2^63))
 	realm := strings.ToUpper(domainName)
@@ -163,19 +187,19 @@ func (s *SuiteHost) TestSignHostConfToken() {
 				WithModel(builder_model.NewModel().WithID(id).Build()).
 				WithRealmName(&realm).
 				WithRealmDomains(pq.StringArray{domainName}).
-				WithServers([]model.IpaServer{
+				WithServers([]ipa.IpaServer{
 					builder_model.NewIpaServer(
 						builder_model.NewModel().Build(),
 					).WithIpaID(id).
 						WithFQDN(fqdn).
 						Build(),
 				}).
-				WithLocations([]model.IpaLocation{
+				WithLocations([]ipa.IpaLocation{
 					builder_model.NewIpaLocation(
 						builder_model.NewModel().Build(),
 					).WithIpaID(id).Build(),
 				}).
-				WithCaCerts([]model.IpaCert{
+				WithCaCerts([]ipa.IpaCert{
 					builder_model.NewIpaCert(
 						builder_model.NewModel().Build(),
 						realm,
@@ -211,6 +235,63 @@ func (s *SuiteHost) TestSignHostConfToken() {
 	require.EqualError(t, err, "jws.Sign: no signers available. Specify an alogirthm and akey using jws.WithKey()")
 }
 
+func (s *SuiteHost) TestMatchLocation() {
+	t := s.Suite.T()
+	domain := builder_model.NewDomain(builder_model.NewModel().WithID(1).Build()).Build()
+	clientSubnet := netip.MustParsePrefix("192.0.2.10/32")
+	expectQuery := func() *sqlmock.ExpectedQuery {
+		return s.mock.ExpectQuery(regexp.QuoteMeta(
+			`SELECT * FROM "domain_location_subnets" ` +
+				`WHERE domain_id = $1 AND "domain_location_subnets"."deleted_at" IS NULL`,
+		)).WithArgs(1)
+	}
+
+	// guard options is nil
+	location, err := s.repository.MatchLocation(s.Ctx, nil, domain)
+	assert.Nil(t, location)
+	require.EqualError(t, err, "code=500, message='options' cannot be nil")
+
+	// guard domain is nil
+	location, err = s.repository.MatchLocation(s.Ctx, &interactor.HostConfOptions{}, nil)
+	assert.Nil(t, location)
+	require.EqualError(t, err, "code=500, message='domain' cannot be nil")
+
+	// no hints
+	location, err = s.repository.MatchLocation(s.Ctx, &interactor.HostConfOptions{}, domain)
+	assert.Nil(t, location)
+	require.NoError(t, err)
+
+	// the location requested by the host takes precedence
+	location, err = s.repository.MatchLocation(s.Ctx, &interactor.HostConfOptions{
+		Location:     pointy.String("alpha"),
+		ClientSubnet: &clientSubnet,
+	}, domain)
+	require.NoError(t, err)
+	assert.Equal(t, pointy.String("alpha"), location)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// database error
+	expectQuery().WillReturnError(gorm.ErrInvalidTransaction)
+	location, err = s.repository.MatchLocation(s.Ctx, &interactor.HostConfOptions{
+		ClientSubnet: &clientSubnet,
+	}, domain)
+	assert.Nil(t, location)
+	require.EqualError(t, err, gorm.ErrInvalidTransaction.Error())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+
+	// the most specific subnet wins
+	expectQuery().WillReturnRows(sqlmock.NewRows([]string{"id", "domain_id", "subnet", "location"}).
+		AddRow(1, 1, "192.0.2.0/24", "alpha").
+		AddRow(2, 1, "192.0.2.0/28", "beta").
+		AddRow(3, 1, "198.51.100.0/24", "gamma"))
+	location, err = s.repository.MatchLocation(s.Ctx, &interactor.HostConfOptions{
+		ClientSubnet: &clientSubnet,
+	}, domain)
+	require.NoError(t, err)
+	assert.Equal(t, pointy.String("beta"), location)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
 func TestSuiteHost(t *testing.T) {
 	suite.Run(t, new(SuiteHost))
 }
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/lestrrat-go/jwx/v2/jwk"
@@ -169,9 +171,9 @@ func (r *hostconfJwkRepository) GetPublicKeyArray(ctx context.Context) (pubkeys,
 }
 
 // GetPrivateSigningKeys returns a array of jwk.Keys with all valid, non-expired
-// private JWKs for signing that can be decrypted with the current main app
-// secret. Expired, invalid keys, and keys encrypted for a different main app
-// secret are ignored.
+// private JWKs for signing that can be decrypted with the current or a
+// previous main app secret. Expired, invalid keys, and keys encrypted for
+// an unknown main app secret are ignored.
 // ctx is the current request context with db and slog instances.
 func (r *hostconfJwkRepository) GetPrivateSigningKeys(ctx context.Context) (privkeys []jwk.Key, err error) {
 	db := app_context.DBFromCtx(ctx)
@@ -185,7 +187,7 @@ func (r *hostconfJwkRepository) GetPrivateSigningKeys(ctx context.Context) (priv
 	now := time.Now()
 	if err = db.
 		Where("encrypted_jwk is not NULL").
-		Where("encryption_id = ?", r.config.Secrets.HostconfEncryptionId).
+		Where("encryption_id IN ?", r.config.Secrets.HostconfEncryptionIds()).
 		Where("expires_at > ?", now). // use SQL NOW()?
 		Order("id").
 		Find(&hcjwks).Error; err != nil {
@@ -200,3 +202,43 @@ func (r *hostconfJwkRepository) GetPrivateSigningKeys(ctx context.Context) (priv
 	}
 	return privkeys, nil
 }
+
+// ReencryptJWKs encrypts with the current main app secret the private keys
+// which are encrypted with a previous main app secret.
+// ctx is the current request context with db and slog instances.
+// Return the reencrypted JWKs, and the JWKs that cannot be decrypted by
+// any generation of the main app secret, which are left unchanged.
+func (r *hostconfJwkRepository) ReencryptJWKs(ctx context.Context) (reencrypted, skipped []model.HostconfJwk, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		err = internal_errors.NilArgError("db")
+		log.Error(err.Error())
+		return nil, nil, err
+	}
+	var hcjwks []model.HostconfJwk
+	if err = db.
+		Where("encrypted_jwk is not NULL").
+		Where("encryption_id <> ?", r.config.Secrets.HostconfEncryptionId).
+		Order("id").
+		Find(&hcjwks).Error; err != nil {
+		log.Error("reencrypting JWKs when finding records")
+		return nil, nil, err
+	}
+	for _, hcjwk := range hcjwks {
+		if err = hcjwk.Reencrypt(r.config.Secrets); err != nil {
+			if errors.Is(err, model.ErrKeyDecryptionFailed) {
+				skipped = append(skipped, hcjwk)
+				continue
+			}
+			log.Error("reencrypting JWK", slog.String("kid", hcjwk.KeyId))
+			return nil, nil, err
+		}
+		if err = db.Save(&hcjwk).Error; err != nil {
+			log.Error("reencrypting JWK when saving the data")
+			return nil, nil, err
+		}
+		reencrypted = append(reencrypted, hcjwk)
+	}
+	return reencrypted, skipped, nil
+}
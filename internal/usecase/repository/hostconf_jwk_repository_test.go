@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"log/slog"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/podengo-project/idmsvc-backend/internal/config"
 	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
 	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/token/hostconf_jwk/model"
 	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
 	"github.com/podengo-project/idmsvc-backend/internal/test"
@@ -104,6 +106,55 @@ func (s *HostConfJwkRepositorySuite) TestGetPrivateSigningKeys() {
 	assert.Error(t, err)
 }
 
+func (s *HostConfJwkRepositorySuite) TestReencryptJWKs() {
+	t := s.Suite.T()
+	reencrypted, skipped, err := s.repository.ReencryptJWKs(s.ctx)
+	assert.Nil(t, reencrypted)
+	assert.Nil(t, skipped)
+	assert.Error(t, err)
+
+	// rotate the main secret
+	previousSecret := s.cfg.Application.MainSecret
+	previous, err := secrets.NewAppSecrets(previousSecret)
+	require.NoError(t, err)
+	unknown, err := secrets.NewAppSecrets(secrets.GenerateRandomMainSecret())
+	require.NoError(t, err)
+	current, err := secrets.NewAppSecrets(secrets.GenerateRandomMainSecret(), previousSecret)
+	require.NoError(t, err)
+	s.cfg.Secrets = *current
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	hcPrevious, err := model.NewHostconfJwk(*previous, expiresAt)
+	require.NoError(t, err)
+	hcPrevious.ID = 1
+	hcUnknown, err := model.NewHostconfJwk(*unknown, expiresAt)
+	require.NoError(t, err)
+	hcUnknown.ID = 2
+
+	columns := []string{"id", "created_at", "updated_at", "deleted_at", "key_id", "expires_at", "public_jwk", "encryption_id", "encrypted_jwk"}
+	rows := sqlmock.NewRows(columns)
+	for _, hc := range []*model.HostconfJwk{hcPrevious, hcUnknown} {
+		rows.AddRow(hc.ID, hc.CreatedAt, hc.UpdatedAt, nil, hc.KeyId, hc.ExpiresAt, hc.PublicJwk, hc.EncryptionId, hc.EncryptedJwk)
+	}
+	s.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "hostconf_jwks" WHERE encrypted_jwk is not NULL AND encryption_id <> $1 AND "hostconf_jwks"."deleted_at" IS NULL ORDER BY id`)).
+		WithArgs(current.HostconfEncryptionId).
+		WillReturnRows(rows)
+	s.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "hostconf_jwks" SET`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	reencrypted, skipped, err = s.repository.ReencryptJWKs(s.ctx)
+	require.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+	require.Len(t, reencrypted, 1)
+	assert.Equal(t, hcPrevious.KeyId, reencrypted[0].KeyId)
+	assert.Equal(t, current.HostconfEncryptionId, reencrypted[0].EncryptionId)
+	_, _, err = reencrypted[0].GetPrivateJWK(*current)
+	assert.NoError(t, err)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, hcUnknown.KeyId, skipped[0].KeyId)
+	assert.Equal(t, unknown.HostconfEncryptionId, skipped[0].EncryptionId)
+}
+
 func TestHostConfJwkRepositorySuite(t *testing.T) {
 	suite.Run(t, new(HostConfJwkRepositorySuite))
 }
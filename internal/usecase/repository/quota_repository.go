@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"gorm.io/gorm"
+)
+
+type quotaRepository struct{}
+
+// NewQuotaRepository create a new repository component for the
+// organization quotas.
+// Return a repository.QuotaRepository interface.
+func NewQuotaRepository() repository.QuotaRepository {
+	return &quotaRepository{}
+}
+
+// FindByOrgID retrieve the quota overrides for an organization.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// Return the overrides and nil error on success, nil and nil error
+// when the organization has no overrides, else nil and an error.
+func (r *quotaRepository) FindByOrgID(
+	ctx context.Context,
+	orgID string,
+) (output *model.OrgQuota, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = &model.OrgQuota{}
+	if err = db.
+		First(output, "org_id = ?", orgID).
+		Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// LockDomains take a transaction advisory lock on the domains of an
+// organization, so the concurrent registrations count the domains one
+// after other and can not exceed the quota together. The lock is
+// released when the current transaction ends.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// Return nil on success, else an error.
+func (r *quotaRepository) LockDomains(
+	ctx context.Context,
+	orgID string,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if err = db.Exec(
+		"SELECT pg_advisory_xact_lock(hashtext(?))",
+		"domains/"+orgID,
+	).Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// CountDomains retrieve the number of domains registered by an
+// organization.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// Return the number of domains and nil error on success, else 0
+// and an error.
+func (r *quotaRepository) CountDomains(
+	ctx context.Context,
+	orgID string,
+) (count int64, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return 0, err
+	}
+	if err = db.Model(&model.Domain{}).
+		Where("org_id = ?", orgID).
+		Count(&count).
+		Error; err != nil {
+		log.Error(err.Error())
+		return 0, err
+	}
+	return count, nil
+}
+
+// IncrementHostConf increase the host-conf usage of an organization
+// for a day, when the usage is under limit. It is a single statement,
+// so it should not run into the transaction of the request, where the
+// row would be locked until the request ends.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// day is the day of the usage; only the date (UTC) is used.
+// limit is the maximum usage for the day.
+// Return true and nil error when the usage was increased, false and
+// nil error when it reached limit, else false and an error.
+func (r *quotaRepository) IncrementHostConf(
+	ctx context.Context,
+	orgID string,
+	day time.Time,
+	limit int,
+) (counted bool, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return false, err
+	}
+	var counts []int64
+	if err = db.Raw(
+		"INSERT INTO host_conf_usage (org_id, day, count) VALUES (?, ?, 1) "+
+			"ON CONFLICT (org_id, day) DO UPDATE SET count = host_conf_usage.count + 1 "+
+			"WHERE host_conf_usage.count < ? "+
+			"RETURNING count",
+		orgID, day.UTC().Format(time.DateOnly), limit,
+	).Scan(&counts).Error; err != nil {
+		log.Error(err.Error())
+		return false, err
+	}
+	return len(counts) > 0, nil
+}
+
+// ReleaseHostConf decrease the host-conf usage of an organization for
+// a day, for a request counted by IncrementHostConf which failed.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// day is the day of the usage; only the date (UTC) is used.
+// Return nil on success, else an error.
+func (r *quotaRepository) ReleaseHostConf(
+	ctx context.Context,
+	orgID string,
+	day time.Time,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if err = db.Exec(
+		"UPDATE host_conf_usage SET count = count - 1 WHERE org_id = ? AND day = ? AND count > 0",
+		orgID, day.UTC().Format(time.DateOnly),
+	).Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *quotaRepository) checkCommon(
+	db *gorm.DB,
+	orgID string,
+) error {
+	if db == nil {
+		return internal_errors.NilArgError("db")
+	}
+	if orgID == "" {
+		return fmt.Errorf("'orgID' is empty")
+	}
+	return nil
+}
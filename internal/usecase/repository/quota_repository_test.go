@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SuiteQuota struct {
+	SuiteBase
+	repository *quotaRepository
+}
+
+// https://pkg.go.dev/github.com/stretchr/testify/suite#SetupTestSuite
+func (s *SuiteQuota) SetupTest() {
+	s.SuiteBase.SetupTest()
+	s.repository = &quotaRepository{}
+}
+
+func (s *SuiteQuota) TestNewQuotaRepository() {
+	t := s.Suite.T()
+	assert.NotPanics(t, func() {
+		_ = NewQuotaRepository()
+	})
+}
+
+func (s *SuiteQuota) TestFindByOrgID() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	query := `SELECT \* FROM "org_quotas" WHERE org_id = \$1 ORDER BY "org_quotas"."org_id" LIMIT .*`
+
+	// db is nil
+	assert.PanicsWithValue(t, "'db' could not be read", func() {
+		_, _ = s.repository.FindByOrgID(context.Background(), orgID)
+	})
+
+	// orgID is empty
+	quota, err := s.repository.FindByOrgID(s.Ctx, "")
+	assert.Nil(t, quota)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at First
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, 1).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	quota, err = s.repository.FindByOrgID(s.Ctx, orgID)
+	assert.Nil(t, quota)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// No overrides
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"org_id"}))
+	quota, err = s.repository.FindByOrgID(s.Ctx, orgID)
+	assert.Nil(t, quota)
+	assert.NoError(t, err)
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"org_id", "max_domains", "max_servers_per_domain", "max_host_conf_per_day"}).
+			AddRow(orgID, 3, nil, 100))
+	quota, err = s.repository.FindByOrgID(s.Ctx, orgID)
+	require.NoError(t, err)
+	require.NotNil(t, quota)
+	assert.Equal(t, orgID, quota.OrgID)
+	require.NotNil(t, quota.MaxDomains)
+	assert.Equal(t, 3, *quota.MaxDomains)
+	assert.Nil(t, quota.MaxServersPerDomain)
+	require.NotNil(t, quota.MaxHostConfPerDay)
+	assert.Equal(t, 100, *quota.MaxHostConfPerDay)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteQuota) TestCountDomains() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	query := `SELECT count\(\*\) FROM "domains" WHERE org_id = \$1`
+
+	// orgID is empty
+	count, err := s.repository.CountDomains(s.Ctx, "")
+	assert.Equal(t, int64(0), count)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at Count
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	count, err = s.repository.CountDomains(s.Ctx, orgID)
+	assert.Equal(t, int64(0), count)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	count, err = s.repository.CountDomains(s.Ctx, orgID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteQuota) TestLockDomains() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	query := `SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`
+
+	// orgID is empty
+	assert.EqualError(t, s.repository.LockDomains(s.Ctx, ""), "'orgID' is empty")
+
+	// Error at lock
+	s.mock.ExpectExec(query).
+		WithArgs("domains/" + orgID).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	assert.EqualError(t, s.repository.LockDomains(s.Ctx, orgID), "invalid transaction")
+
+	// Success
+	s.mock.ExpectExec(query).
+		WithArgs("domains/" + orgID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, s.repository.LockDomains(s.Ctx, orgID))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteQuota) TestIncrementHostConf() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	day := time.Date(2026, 10, 19, 23, 30, 0, 0, time.FixedZone("CEST", -2*3600))
+	query := `INSERT INTO host_conf_usage \(org_id, day, count\) VALUES \(\$1, \$2, 1\) ` +
+		`ON CONFLICT \(org_id, day\) DO UPDATE SET count = host_conf_usage.count \+ 1 ` +
+		`WHERE host_conf_usage.count < \$3 RETURNING count`
+
+	// orgID is empty
+	counted, err := s.repository.IncrementHostConf(s.Ctx, "", day, 10)
+	assert.False(t, counted)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at insert
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, "2026-10-20", 10).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	counted, err = s.repository.IncrementHostConf(s.Ctx, orgID, day, 10)
+	assert.False(t, counted)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, "2026-10-20", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+	counted, err = s.repository.IncrementHostConf(s.Ctx, orgID, day, 10)
+	assert.NoError(t, err)
+	assert.True(t, counted)
+
+	// The limit is reached, so no row is updated
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, "2026-10-20", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}))
+	counted, err = s.repository.IncrementHostConf(s.Ctx, orgID, day, 10)
+	assert.NoError(t, err)
+	assert.False(t, counted)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteQuota) TestReleaseHostConf() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	day := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	query := `UPDATE host_conf_usage SET count = count - 1 WHERE org_id = \$1 AND day = \$2 AND count > 0`
+
+	// orgID is empty
+	assert.EqualError(t, s.repository.ReleaseHostConf(s.Ctx, "", day), "'orgID' is empty")
+
+	// Error at update
+	s.mock.ExpectExec(query).
+		WithArgs(orgID, "2026-10-19").
+		WillReturnError(gorm.ErrInvalidTransaction)
+	assert.EqualError(t, s.repository.ReleaseHostConf(s.Ctx, orgID, day), "invalid transaction")
+
+	// Success
+	s.mock.ExpectExec(query).
+		WithArgs(orgID, "2026-10-19").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, s.repository.ReleaseHostConf(s.Ctx, orgID, day))
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func TestSuiteQuota(t *testing.T) {
+	suite.Run(t, new(SuiteQuota))
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/usecase/domaintype/ipa"
+)
+
+type statsRepository struct{}
+
+// NewStatsRepository create a new repository component for the
+// statistics of the service.
+// Return a repository.StatsRepository interface.
+func NewStatsRepository() repository.StatsRepository {
+	return &statsRepository{}
+}
+
+// DomainStats retrieve the statistics of the domains of all the
+// organizations.
+// ctx is the context with db and slog instances.
+// now is the time used to check the expiration of the certificates.
+// Return the statistics and nil error on success, else nil and an
+// error.
+func (r *statsRepository) DomainStats(
+	ctx context.Context,
+	now time.Time,
+) (output *model.DomainStats, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		err = internal_errors.NilArgError("db")
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = &model.DomainStats{}
+	if err = db.Model(&model.Domain{}).
+		Count(&output.Domains).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	if err = db.Raw(
+		"SELECT COUNT(ipa_servers.id) FROM domains " +
+			"LEFT JOIN ipa_servers ON ipa_servers.ipa_id = domains.id AND ipa_servers.deleted_at IS NULL " +
+			"WHERE domains.deleted_at IS NULL " +
+			"GROUP BY domains.id",
+	).Scan(&output.ServersPerDomain).Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	if err = db.Model(&ipa.IpaCert{}).
+		Where("not_after < ?", now.Add(7*24*time.Hour)).
+		Count(&output.CertsExpiringIn7Days).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	if err = db.Model(&ipa.IpaCert{}).
+		Where("not_after < ?", now.Add(30*24*time.Hour)).
+		Count(&output.CertsExpiringIn30Days).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SuiteStats struct {
+	SuiteBase
+	repository *statsRepository
+}
+
+// https://pkg.go.dev/github.com/stretchr/testify/suite#SetupTestSuite
+func (s *SuiteStats) SetupTest() {
+	s.SuiteBase.SetupTest()
+	s.repository = &statsRepository{}
+}
+
+func (s *SuiteStats) TestNewStatsRepository() {
+	t := s.Suite.T()
+	assert.NotPanics(t, func() {
+		_ = NewStatsRepository()
+	})
+}
+
+func (s *SuiteStats) TestDomainStats() {
+	t := s.Suite.T()
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	queryDomains := `SELECT count\(\*\) FROM "domains" WHERE "domains"."deleted_at" IS NULL`
+	queryServers := `SELECT COUNT\(ipa_servers.id\) FROM domains LEFT JOIN ipa_servers .* GROUP BY domains.id`
+	queryCerts := `SELECT count\(\*\) FROM "ipa_certs" WHERE not_after < \$1 AND "ipa_certs"."deleted_at" IS NULL`
+
+	// db is nil
+	assert.PanicsWithValue(t, "'db' could not be read", func() {
+		_, _ = s.repository.DomainStats(context.Background(), now)
+	})
+
+	// Error counting domains
+	s.mock.ExpectQuery(queryDomains).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	stats, err := s.repository.DomainStats(s.Ctx, now)
+	assert.Nil(t, stats)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Error counting servers
+	s.mock.ExpectQuery(queryDomains).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(queryServers).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	stats, err = s.repository.DomainStats(s.Ctx, now)
+	assert.Nil(t, stats)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Error counting certificates
+	s.mock.ExpectQuery(queryDomains).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(queryServers).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1).AddRow(3))
+	s.mock.ExpectQuery(queryCerts).
+		WithArgs(now.Add(7 * 24 * time.Hour)).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	stats, err = s.repository.DomainStats(s.Ctx, now)
+	assert.Nil(t, stats)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(queryDomains).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	s.mock.ExpectQuery(queryServers).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1).AddRow(3))
+	s.mock.ExpectQuery(queryCerts).
+		WithArgs(now.Add(7 * 24 * time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	s.mock.ExpectQuery(queryCerts).
+		WithArgs(now.Add(30 * 24 * time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+	stats, err = s.repository.DomainStats(s.Ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, &model.DomainStats{
+		Domains:               2,
+		ServersPerDomain:      []int64{1, 3},
+		CertsExpiringIn7Days:  1,
+		CertsExpiringIn30Days: 4,
+	}, stats)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func TestSuiteStats(t *testing.T) {
+	suite.Run(t, new(SuiteStats))
+}
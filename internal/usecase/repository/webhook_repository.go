@@ -0,0 +1,556 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	internal_errors "github.com/podengo-project/idmsvc-backend/internal/errors"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepository struct{}
+
+// NewWebhookRepository create a new repository component for the
+// webhooks and their deliveries.
+// Return a repository.WebhookRepository interface.
+func NewWebhookRepository() repository.WebhookRepository {
+	return &webhookRepository{}
+}
+
+// List retrieve the webhooks of an organization.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// Return the webhooks and nil error on success, else nil and an
+// error.
+func (r *webhookRepository) List(
+	ctx context.Context,
+	orgID string,
+) (output []model.Webhook, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = []model.Webhook{}
+	if err = db.
+		Where("org_id = ?", orgID).
+		Order("id").
+		Find(&output).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// Create add a webhook to an organization.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// data is the webhook to create; its uuid is generated when it is
+// not set.
+// Return nil on success, else an error.
+func (r *webhookRepository) Create(
+	ctx context.Context,
+	orgID string,
+	data *model.Webhook,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if data == nil {
+		return internal_errors.NilArgError("data")
+	}
+	data.OrgId = orgID
+	if data.WebhookUuid == uuid.Nil {
+		data.WebhookUuid = uuid.New()
+	}
+	if err = db.Create(data).Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// FindByID retrieve a webhook of an organization.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// UUID is the webhook uuid.
+// Return the webhook and nil error on success, else nil and an
+// error; the error is a not found error when the webhook does not
+// exist.
+func (r *webhookRepository) FindByID(
+	ctx context.Context,
+	orgID string,
+	UUID uuid.UUID,
+) (output *model.Webhook, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = &model.Webhook{}
+	if err = db.
+		First(output, "org_id = ? AND webhook_uuid = ?", orgID, UUID).
+		Error; err != nil {
+		err = r.wrapErrNotFound(err, UUID)
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// Update save the url, the events and the state of a webhook.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// data is the webhook to save, identified by its uuid.
+// Return nil on success, else an error; the error is a not found
+// error when the webhook does not exist.
+func (r *webhookRepository) Update(
+	ctx context.Context,
+	orgID string,
+	data *model.Webhook,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if data == nil {
+		return internal_errors.NilArgError("data")
+	}
+	result := db.
+		Model(&model.Webhook{}).
+		Select("url", "events", "enabled", "consecutive_failures", "disabled_at", "updated_at").
+		Where("org_id = ? AND webhook_uuid = ?", orgID, data.WebhookUuid).
+		Updates(data)
+	if err = result.Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if result.RowsAffected == 0 {
+		err = r.wrapErrNotFound(gorm.ErrRecordNotFound, data.WebhookUuid)
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// DeleteById remove a webhook and its deliveries.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// UUID is the webhook uuid.
+// Return nil on success, else an error; the error is a not found
+// error when the webhook does not exist.
+func (r *webhookRepository) DeleteById(
+	ctx context.Context,
+	orgID string,
+	UUID uuid.UUID,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	result := db.
+		Where("org_id = ? AND webhook_uuid = ?", orgID, UUID).
+		Delete(&model.Webhook{})
+	if err = result.Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if result.RowsAffected == 0 {
+		err = r.wrapErrNotFound(gorm.ErrRecordNotFound, UUID)
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// ListDeliveries retrieve the most recent deliveries of a webhook,
+// the newest first.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// UUID is the webhook uuid.
+// limit is the max number of deliveries.
+// Return the deliveries and nil error on success, else nil and an
+// error; the error is a not found error when the webhook does not
+// exist.
+func (r *webhookRepository) ListDeliveries(
+	ctx context.Context,
+	orgID string,
+	UUID uuid.UUID,
+	limit int,
+) (output []model.WebhookDelivery, err error) {
+	var webhook model.Webhook
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("'limit' must be greater than 0")
+	}
+	if err = db.
+		Select("id").
+		First(&webhook, "org_id = ? AND webhook_uuid = ?", orgID, UUID).
+		Error; err != nil {
+		err = r.wrapErrNotFound(err, UUID)
+		log.Error(err.Error())
+		return nil, err
+	}
+	output = []model.WebhookDelivery{}
+	if err = db.
+		Where("webhook_id = ?", webhook.ID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&output).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// Enqueue add a pending delivery of an event for every enabled
+// webhook of the organization subscribed to it. The deliveries are
+// part of the current transaction, so they are discarded when the
+// change which raised the event is rolled back.
+// ctx is the current request context with db and slog instances.
+// orgID is the organization id.
+// event is the name of the event.
+// payload is the body to deliver.
+// now is the time of the event; the first attempt is due at now.
+// Return the number of deliveries and nil error on success, else 0
+// and an error.
+func (r *webhookRepository) Enqueue(
+	ctx context.Context,
+	orgID string,
+	event string,
+	payload []byte,
+	now time.Time,
+) (count int, err error) {
+	var webhooks []model.Webhook
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if err = r.checkCommon(db, orgID); err != nil {
+		log.Error(err.Error())
+		return 0, err
+	}
+	if err = db.
+		Where("org_id = ? AND enabled = ?", orgID, true).
+		Order("id").
+		Find(&webhooks).
+		Error; err != nil {
+		log.Error(err.Error())
+		return 0, err
+	}
+	deliveries := []model.WebhookDelivery{}
+	for i := range webhooks {
+		if !webhooks[i].Subscribed(event) {
+			continue
+		}
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID:     webhooks[i].ID,
+			DeliveryUuid:  uuid.New(),
+			Event:         event,
+			Payload:       payload,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	if err = db.Create(&deliveries).Error; err != nil {
+		log.Error(err.Error())
+		return 0, err
+	}
+	return len(deliveries), nil
+}
+
+// ClaimPending lock and retrieve the pending deliveries which next
+// attempt is due, with their webhook, and postpone their next
+// attempt until leaseUntil. The locked deliveries are skipped, so
+// several instances can deliver at the same time; once the
+// transaction is committed, the claimed deliveries are not due for
+// the other instances until the lease expires, so they can be
+// attempted out of the transaction.
+// ctx is the context with db and slog instances.
+// now is the current time.
+// leaseUntil is the time the claimed deliveries are due again if
+// their attempt is not saved.
+// limit is the max number of deliveries.
+// Return the deliveries and nil error on success, else nil and an
+// error.
+func (r *webhookRepository) ClaimPending(
+	ctx context.Context,
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) (output []model.WebhookDelivery, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		return nil, internal_errors.NilArgError("db")
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("'limit' must be greater than 0")
+	}
+	output = []model.WebhookDelivery{}
+	if err = db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&output).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	if len(output) == 0 {
+		return output, nil
+	}
+	ids := make([]uint, len(output))
+	for i := range output {
+		ids[i] = output[i].ID
+	}
+	if err = db.
+		Model(&model.WebhookDelivery{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{"next_attempt_at": leaseUntil, "updated_at": now}).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// RecordAttempt save the result of a delivery attempt into the state
+// of its webhook. The state is updated relative to the current row,
+// so the attempts of concurrent dispatchers are all counted: a
+// succeeded attempt reset the consecutive failures, and a failed
+// attempt increase them and disable the webhook when they reach
+// threshold. Only the consecutive failures, enabled and disabled_at
+// are saved, so the changes of the url and events are kept.
+// ctx is the context with db and slog instances.
+// data is the webhook of the attempt; it is updated with the saved
+// state.
+// succeeded is true when the attempt succeeded.
+// threshold is the consecutive failures which disable the webhook;
+// zero never disable it.
+// now is the time of the attempt.
+// Return nil on success, else an error; the error is a not found
+// error when the webhook does not exist.
+func (r *webhookRepository) RecordAttempt(
+	ctx context.Context,
+	data *model.Webhook,
+	succeeded bool,
+	threshold int,
+	now time.Time,
+) (err error) {
+	var state struct {
+		ConsecutiveFailures int
+		Enabled             bool
+		DisabledAt          *time.Time
+	}
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		return internal_errors.NilArgError("db")
+	}
+	if data == nil {
+		return internal_errors.NilArgError("data")
+	}
+	var result *gorm.DB
+	if succeeded {
+		result = db.Raw(
+			`UPDATE webhooks SET consecutive_failures = 0, updated_at = ? `+
+				`WHERE id = ? RETURNING consecutive_failures, enabled, disabled_at`,
+			now, data.ID,
+		).Scan(&state)
+	} else {
+		result = db.Raw(
+			`UPDATE webhooks SET consecutive_failures = consecutive_failures + 1, `+
+				`enabled = enabled AND NOT (? > 0 AND consecutive_failures + 1 >= ?), `+
+				`disabled_at = CASE WHEN enabled AND ? > 0 AND consecutive_failures + 1 >= ? THEN ? ELSE disabled_at END, `+
+				`updated_at = ? `+
+				`WHERE id = ? RETURNING consecutive_failures, enabled, disabled_at`,
+			threshold, threshold, threshold, threshold, now, now, data.ID,
+		).Scan(&state)
+	}
+	if err = result.Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if result.RowsAffected == 0 {
+		err = r.wrapErrNotFound(gorm.ErrRecordNotFound, data.WebhookUuid)
+		log.Error(err.Error())
+		return err
+	}
+	data.ConsecutiveFailures = state.ConsecutiveFailures
+	data.Enabled = &state.Enabled
+	data.DisabledAt = state.DisabledAt
+	return nil
+}
+
+// UpdateDelivery save the state of a delivery after an attempt.
+// ctx is the context with db and slog instances.
+// data is the delivery to save.
+// Return nil on success, else an error.
+func (r *webhookRepository) UpdateDelivery(
+	ctx context.Context,
+	data *model.WebhookDelivery,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		return internal_errors.NilArgError("db")
+	}
+	if data == nil {
+		return internal_errors.NilArgError("data")
+	}
+	if err = db.
+		Model(&model.WebhookDelivery{}).
+		Select("status", "attempts", "next_attempt_at", "response_status", "error", "delivered_at", "updated_at").
+		Where("id = ?", data.ID).
+		Updates(data).
+		Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+// ListSecretsToReencrypt lock and return the webhooks which signing
+// secret is not encrypted with the main secret generation
+// encryptionId, including the webhooks without an encrypted secret.
+// ctx is the context with db and slog instances.
+// encryptionId is the encryption id of the current main secret.
+// Return the webhooks and nil error on success, else nil and an
+// error.
+func (r *webhookRepository) ListSecretsToReencrypt(
+	ctx context.Context,
+	encryptionId string,
+) (output []model.Webhook, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		return nil, internal_errors.NilArgError("db")
+	}
+	if encryptionId == "" {
+		return nil, internal_errors.EmptyArgError("encryptionId")
+	}
+	output = []model.Webhook{}
+	if err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("encryption_id IS NULL OR encryption_id <> ?", encryptionId).
+		Order("id").
+		Find(&output).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// ListLegacySecrets lock and return the webhooks without an
+// encrypted signing secret, created before the secrets were stored;
+// they sign with a secret derived from the current main secret.
+// ctx is the context with db and slog instances.
+// Return the webhooks and nil error on success, else nil and an
+// error.
+func (r *webhookRepository) ListLegacySecrets(
+	ctx context.Context,
+) (output []model.Webhook, err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		return nil, internal_errors.NilArgError("db")
+	}
+	output = []model.Webhook{}
+	if err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("encryption_id IS NULL").
+		Order("id").
+		Find(&output).
+		Error; err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+	return output, nil
+}
+
+// UpdateSecret save the encrypted signing secret of a webhook and its
+// encryption id.
+// ctx is the context with db and slog instances.
+// data is the webhook to save, identified by its id.
+// Return nil on success, else an error; the error is a not found
+// error when the webhook does not exist.
+func (r *webhookRepository) UpdateSecret(
+	ctx context.Context,
+	data *model.Webhook,
+) (err error) {
+	db := app_context.DBFromCtx(ctx)
+	log := app_context.LogFromCtx(ctx)
+	if db == nil {
+		return internal_errors.NilArgError("db")
+	}
+	if data == nil {
+		return internal_errors.NilArgError("data")
+	}
+	result := db.
+		Model(&model.Webhook{}).
+		Select("encrypted_secret", "encryption_id").
+		Where("id = ?", data.ID).
+		Updates(data)
+	if err = result.Error; err != nil {
+		log.Error(err.Error())
+		return err
+	}
+	if result.RowsAffected == 0 {
+		err = r.wrapErrNotFound(gorm.ErrRecordNotFound, data.WebhookUuid)
+		log.Error(err.Error())
+		return err
+	}
+	return nil
+}
+
+func (r *webhookRepository) wrapErrNotFound(err error, UUID uuid.UUID) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return internal_errors.ErrWebhookNotFound.New(
+			err,
+			"unknown webhook '%s'",
+			UUID.String(),
+		)
+	}
+	return err
+}
+
+func (r *webhookRepository) checkCommon(
+	db *gorm.DB,
+	orgID string,
+) error {
+	if db == nil {
+		return internal_errors.NilArgError("db")
+	}
+	if orgID == "" {
+		return fmt.Errorf("'orgID' is empty")
+	}
+	return nil
+}
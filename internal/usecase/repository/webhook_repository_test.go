@@ -0,0 +1,609 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+type SuiteWebhook struct {
+	SuiteBase
+	repository *webhookRepository
+}
+
+var webhookColumns = []string{
+	"id", "created_at", "updated_at", "org_id", "webhook_uuid", "url",
+	"events", "enabled", "consecutive_failures", "disabled_at",
+}
+
+// https://pkg.go.dev/github.com/stretchr/testify/suite#SetupTestSuite
+func (s *SuiteWebhook) SetupTest() {
+	s.SuiteBase.SetupTest()
+	s.repository = &webhookRepository{}
+}
+
+func (s *SuiteWebhook) TestNewWebhookRepository() {
+	t := s.Suite.T()
+	assert.NotPanics(t, func() {
+		_ = NewWebhookRepository()
+	})
+}
+
+func (s *SuiteWebhook) TestList() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	now := time.Now()
+	webhookID := uuid.New()
+	query := `SELECT \* FROM "webhooks" WHERE org_id = \$1 ORDER BY id`
+
+	// db is nil
+	assert.PanicsWithValue(t, "'db' could not be read", func() {
+		_, _ = s.repository.List(context.Background(), orgID)
+	})
+
+	// orgID is empty
+	output, err := s.repository.List(s.Ctx, "")
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at Find
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	output, err = s.repository.List(s.Ctx, orgID)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).
+			AddRow(1, now, now, orgID, webhookID, "https://itsm.example.com/hook",
+				`["domain.created","domain.deleted"]`, true, 0, nil))
+	output, err = s.repository.List(s.Ctx, orgID)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	assert.Equal(t, webhookID, output[0].WebhookUuid)
+	assert.Equal(t, "https://itsm.example.com/hook", output[0].URL)
+	assert.Equal(t, []string{"domain.created", "domain.deleted"}, output[0].Events)
+	assert.True(t, output[0].IsEnabled())
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestCreate() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	query := `INSERT INTO "webhooks" \("created_at","updated_at","org_id","webhook_uuid","url","events","enabled","consecutive_failures","disabled_at","encrypted_secret","encryption_id"\) ` +
+		`VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11\) RETURNING "id"`
+
+	// orgID is empty
+	err := s.repository.Create(s.Ctx, "", &model.Webhook{})
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// data is nil
+	err = s.repository.Create(s.Ctx, orgID, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// Error at Create
+	data := &model.Webhook{
+		URL:             "https://itsm.example.com/hook",
+		Events:          []string{"domain.updated"},
+		Enabled:         pointy.Bool(true),
+		EncryptedSecret: []byte("encrypted secret"),
+		EncryptionId:    pointy.String("0011223344556677"),
+		Secret:          "secret",
+	}
+	s.mock.ExpectQuery(query).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), orgID, sqlmock.AnyArg(), data.URL,
+			`["domain.updated"]`, true, 0, nil, data.EncryptedSecret, *data.EncryptionId).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.Create(s.Ctx, orgID, data)
+	assert.EqualError(t, err, "invalid transaction")
+	assert.Equal(t, orgID, data.OrgId)
+	assert.NotEqual(t, uuid.Nil, data.WebhookUuid)
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), orgID, data.WebhookUuid, data.URL,
+			`["domain.updated"]`, true, 0, nil, data.EncryptedSecret, *data.EncryptionId).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	err = s.repository.Create(s.Ctx, orgID, data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), data.ID)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestFindByID() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	now := time.Now()
+	webhookID := uuid.New()
+	query := `SELECT \* FROM "webhooks" WHERE org_id = \$1 AND webhook_uuid = \$2 ORDER BY "webhooks"."id" LIMIT .*`
+
+	// orgID is empty
+	output, err := s.repository.FindByID(s.Ctx, "", webhookID)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Not found
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, webhookID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	output, err = s.repository.FindByID(s.Ctx, orgID, webhookID)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "code=404, message=unknown webhook '"+webhookID.String()+"', internal=IDMSVC-WEBHOOK-NOT-FOUND: record not found")
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(orgID, webhookID, 1).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).
+			AddRow(1, now, now, orgID, webhookID, "https://itsm.example.com/hook",
+				`["host.configured"]`, false, 10, now))
+	output, err = s.repository.FindByID(s.Ctx, orgID, webhookID)
+	require.NoError(t, err)
+	require.NotNil(t, output)
+	assert.Equal(t, uint(1), output.ID)
+	assert.Equal(t, []string{"host.configured"}, output.Events)
+	assert.False(t, output.IsEnabled())
+	assert.Equal(t, 10, output.ConsecutiveFailures)
+	assert.NotNil(t, output.DisabledAt)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestUpdate() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	data := &model.Webhook{
+		WebhookUuid:         uuid.New(),
+		URL:                 "https://itsm.example.com/hook",
+		Events:              []string{"domain.updated"},
+		Enabled:             pointy.Bool(true),
+		ConsecutiveFailures: 0,
+	}
+	query := `UPDATE "webhooks" SET "updated_at"=\$1,"url"=\$2,"events"=\$3,"enabled"=\$4,"consecutive_failures"=\$5,"disabled_at"=\$6 ` +
+		`WHERE org_id = \$7 AND webhook_uuid = \$8`
+
+	// orgID is empty
+	err := s.repository.Update(s.Ctx, "", data)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// data is nil
+	err = s.repository.Update(s.Ctx, orgID, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// Error at Updates
+	s.mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), data.URL, `["domain.updated"]`, true, 0, nil, orgID, data.WebhookUuid).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.Update(s.Ctx, orgID, data)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Not found
+	s.mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), data.URL, `["domain.updated"]`, true, 0, nil, orgID, data.WebhookUuid).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = s.repository.Update(s.Ctx, orgID, data)
+	assert.EqualError(t, err, "code=404, message=unknown webhook '"+data.WebhookUuid.String()+"', internal=IDMSVC-WEBHOOK-NOT-FOUND: record not found")
+
+	// Success
+	s.mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), data.URL, `["domain.updated"]`, true, 0, nil, orgID, data.WebhookUuid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = s.repository.Update(s.Ctx, orgID, data)
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestDeleteById() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	webhookID := uuid.New()
+	query := `DELETE FROM "webhooks" WHERE org_id = \$1 AND webhook_uuid = \$2`
+
+	// orgID is empty
+	err := s.repository.DeleteById(s.Ctx, "", webhookID)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at Delete
+	s.mock.ExpectExec(query).
+		WithArgs(orgID, webhookID).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.DeleteById(s.Ctx, orgID, webhookID)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Not found
+	s.mock.ExpectExec(query).
+		WithArgs(orgID, webhookID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = s.repository.DeleteById(s.Ctx, orgID, webhookID)
+	assert.EqualError(t, err, "code=404, message=unknown webhook '"+webhookID.String()+"', internal=IDMSVC-WEBHOOK-NOT-FOUND: record not found")
+
+	// Success
+	s.mock.ExpectExec(query).
+		WithArgs(orgID, webhookID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = s.repository.DeleteById(s.Ctx, orgID, webhookID)
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestListDeliveries() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	now := time.Now()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	queryWebhook := `SELECT "id" FROM "webhooks" WHERE org_id = \$1 AND webhook_uuid = \$2 ORDER BY "webhooks"."id" LIMIT .*`
+	queryDeliveries := `SELECT \* FROM "webhook_deliveries" WHERE webhook_id = \$1 ORDER BY created_at DESC, id DESC LIMIT .*`
+
+	// orgID is empty
+	output, err := s.repository.ListDeliveries(s.Ctx, "", webhookID, 10)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// limit is not valid
+	output, err = s.repository.ListDeliveries(s.Ctx, orgID, webhookID, 0)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "'limit' must be greater than 0")
+
+	// Webhook not found
+	s.mock.ExpectQuery(queryWebhook).
+		WithArgs(orgID, webhookID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	output, err = s.repository.ListDeliveries(s.Ctx, orgID, webhookID, 10)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "code=404, message=unknown webhook '"+webhookID.String()+"', internal=IDMSVC-WEBHOOK-NOT-FOUND: record not found")
+
+	// Error at Find
+	s.mock.ExpectQuery(queryWebhook).
+		WithArgs(orgID, webhookID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	s.mock.ExpectQuery(queryDeliveries).
+		WithArgs(2, 10).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	output, err = s.repository.ListDeliveries(s.Ctx, orgID, webhookID, 10)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(queryWebhook).
+		WithArgs(orgID, webhookID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	s.mock.ExpectQuery(queryDeliveries).
+		WithArgs(2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "webhook_id", "delivery_uuid", "event", "status", "attempts", "response_status"}).
+			AddRow(5, now, 2, deliveryID, "domain.deleted", model.WebhookDeliverySucceeded, 1, 204))
+	output, err = s.repository.ListDeliveries(s.Ctx, orgID, webhookID, 10)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	assert.Equal(t, deliveryID, output[0].DeliveryUuid)
+	assert.Equal(t, model.WebhookDeliverySucceeded, output[0].Status)
+	assert.Equal(t, pointy.Int(204), output[0].ResponseStatus)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestEnqueue() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	now := time.Now()
+	payload := []byte(`{"event":"domain.created"}`)
+	queryWebhooks := `SELECT \* FROM "webhooks" WHERE org_id = \$1 AND enabled = \$2 ORDER BY id`
+	queryInsert := `INSERT INTO "webhook_deliveries" \("created_at","updated_at","webhook_id","delivery_uuid","event","payload","status","attempts","next_attempt_at","response_status","error","delivered_at"\) ` +
+		`VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10,\$11,\$12\) RETURNING "id"`
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(webhookColumns).
+			AddRow(1, now, now, orgID, uuid.New(), "https://a.example.com/hook", `["domain.created"]`, true, 0, nil).
+			AddRow(2, now, now, orgID, uuid.New(), "https://b.example.com/hook", `["host.configured"]`, true, 0, nil)
+	}
+
+	// orgID is empty
+	count, err := s.repository.Enqueue(s.Ctx, "", "domain.created", payload, now)
+	assert.Equal(t, 0, count)
+	assert.EqualError(t, err, "'orgID' is empty")
+
+	// Error at Find
+	s.mock.ExpectQuery(queryWebhooks).
+		WithArgs(orgID, true).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	count, err = s.repository.Enqueue(s.Ctx, orgID, "domain.created", payload, now)
+	assert.Equal(t, 0, count)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// No webhook subscribed
+	s.mock.ExpectQuery(queryWebhooks).
+		WithArgs(orgID, true).
+		WillReturnRows(rows())
+	count, err = s.repository.Enqueue(s.Ctx, orgID, "domain.deleted", payload, now)
+	assert.Equal(t, 0, count)
+	assert.NoError(t, err)
+
+	// Error at Create
+	s.mock.ExpectQuery(queryWebhooks).
+		WithArgs(orgID, true).
+		WillReturnRows(rows())
+	s.mock.ExpectQuery(queryInsert).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, sqlmock.AnyArg(), "domain.created", payload,
+			model.WebhookDeliveryPending, 0, now, nil, nil, nil).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	count, err = s.repository.Enqueue(s.Ctx, orgID, "domain.created", payload, now)
+	assert.Equal(t, 0, count)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(queryWebhooks).
+		WithArgs(orgID, true).
+		WillReturnRows(rows())
+	s.mock.ExpectQuery(queryInsert).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 1, sqlmock.AnyArg(), "domain.created", payload,
+			model.WebhookDeliveryPending, 0, now, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	count, err = s.repository.Enqueue(s.Ctx, orgID, "domain.created", payload, now)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestClaimPending() {
+	t := s.Suite.T()
+	const orgID = "12345"
+	now := time.Now()
+	leaseUntil := now.Add(2 * time.Minute)
+	webhookID := uuid.New()
+	queryDeliveries := `SELECT \* FROM "webhook_deliveries" WHERE status = \$1 AND next_attempt_at <= \$2 ORDER BY next_attempt_at LIMIT \$3 FOR UPDATE SKIP LOCKED`
+	queryWebhooks := `SELECT \* FROM "webhooks" WHERE "webhooks"."id" = \$1`
+	queryLease := `UPDATE "webhook_deliveries" SET "next_attempt_at"=\$1,"updated_at"=\$2 WHERE id IN \(\$3,\$4\)`
+
+	// limit is not valid
+	output, err := s.repository.ClaimPending(s.Ctx, now, leaseUntil, 0)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "'limit' must be greater than 0")
+
+	// Error at Find
+	s.mock.ExpectQuery(queryDeliveries).
+		WithArgs(model.WebhookDeliveryPending, now, 10).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	output, err = s.repository.ClaimPending(s.Ctx, now, leaseUntil, 10)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// No pending deliveries
+	s.mock.ExpectQuery(queryDeliveries).
+		WithArgs(model.WebhookDeliveryPending, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event", "status", "attempts"}))
+	output, err = s.repository.ClaimPending(s.Ctx, now, leaseUntil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, output)
+
+	// Error at Update
+	s.mock.ExpectQuery(queryDeliveries).
+		WithArgs(model.WebhookDeliveryPending, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event", "status", "attempts"}).
+			AddRow(5, 2, "domain.updated", model.WebhookDeliveryPending, 1).
+			AddRow(6, 2, "domain.deleted", model.WebhookDeliveryPending, 0))
+	s.mock.ExpectQuery(queryWebhooks).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).
+			AddRow(2, now, now, orgID, webhookID, "https://itsm.example.com/hook", `["domain.updated"]`, true, 1, nil))
+	s.mock.ExpectExec(queryLease).
+		WithArgs(leaseUntil, now, 5, 6).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	output, err = s.repository.ClaimPending(s.Ctx, now, leaseUntil, 10)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(queryDeliveries).
+		WithArgs(model.WebhookDeliveryPending, now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event", "status", "attempts"}).
+			AddRow(5, 2, "domain.updated", model.WebhookDeliveryPending, 1).
+			AddRow(6, 2, "domain.deleted", model.WebhookDeliveryPending, 0))
+	s.mock.ExpectQuery(queryWebhooks).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).
+			AddRow(2, now, now, orgID, webhookID, "https://itsm.example.com/hook", `["domain.updated"]`, true, 1, nil))
+	s.mock.ExpectExec(queryLease).
+		WithArgs(leaseUntil, now, 5, 6).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	output, err = s.repository.ClaimPending(s.Ctx, now, leaseUntil, 10)
+	require.NoError(t, err)
+	require.Len(t, output, 2)
+	require.NotNil(t, output[0].Webhook)
+	assert.Equal(t, webhookID, output[0].Webhook.WebhookUuid)
+	assert.Equal(t, 1, output[0].Attempts)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestRecordAttempt() {
+	t := s.Suite.T()
+	now := time.Now()
+	data := &model.Webhook{ID: 2, WebhookUuid: uuid.New(), Enabled: pointy.Bool(true), ConsecutiveFailures: 1}
+	querySucceeded := `UPDATE webhooks SET consecutive_failures = 0, updated_at = \$1 ` +
+		`WHERE id = \$2 RETURNING consecutive_failures, enabled, disabled_at`
+	queryFailed := `UPDATE webhooks SET consecutive_failures = consecutive_failures \+ 1, ` +
+		`enabled = enabled AND NOT \(\$1 > 0 AND consecutive_failures \+ 1 >= \$2\), ` +
+		`disabled_at = CASE WHEN enabled AND \$3 > 0 AND consecutive_failures \+ 1 >= \$4 THEN \$5 ELSE disabled_at END, ` +
+		`updated_at = \$6 WHERE id = \$7 RETURNING consecutive_failures, enabled, disabled_at`
+	columns := []string{"consecutive_failures", "enabled", "disabled_at"}
+
+	// data is nil
+	err := s.repository.RecordAttempt(s.Ctx, nil, true, 3, now)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// Error at Update
+	s.mock.ExpectQuery(querySucceeded).
+		WithArgs(now, 2).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.RecordAttempt(s.Ctx, data, true, 3, now)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// The webhook does not exist
+	s.mock.ExpectQuery(querySucceeded).
+		WithArgs(now, 2).
+		WillReturnRows(sqlmock.NewRows(columns))
+	err = s.repository.RecordAttempt(s.Ctx, data, true, 3, now)
+	assert.EqualError(t, err, "code=404, message=unknown webhook '"+data.WebhookUuid.String()+"', internal=IDMSVC-WEBHOOK-NOT-FOUND: record not found")
+
+	// Succeeded
+	s.mock.ExpectQuery(querySucceeded).
+		WithArgs(now, 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(0, true, nil))
+	err = s.repository.RecordAttempt(s.Ctx, data, true, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, data.ConsecutiveFailures)
+	assert.True(t, data.IsEnabled())
+
+	// Failed, reaching the threshold with the failures of other
+	// dispatchers
+	s.mock.ExpectQuery(queryFailed).
+		WithArgs(3, 3, 3, 3, now, now, 2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, false, now))
+	err = s.repository.RecordAttempt(s.Ctx, data, false, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, data.ConsecutiveFailures)
+	assert.False(t, data.IsEnabled())
+	require.NotNil(t, data.DisabledAt)
+	assert.Equal(t, now, *data.DisabledAt)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestUpdateDelivery() {
+	t := s.Suite.T()
+	now := time.Now()
+	data := &model.WebhookDelivery{
+		ID:             5,
+		Status:         model.WebhookDeliverySucceeded,
+		Attempts:       2,
+		ResponseStatus: pointy.Int(200),
+		DeliveredAt:    &now,
+	}
+	query := `UPDATE "webhook_deliveries" SET "updated_at"=\$1,"status"=\$2,"attempts"=\$3,"next_attempt_at"=\$4,"response_status"=\$5,"error"=\$6,"delivered_at"=\$7 ` +
+		`WHERE id = \$8`
+
+	// data is nil
+	err := s.repository.UpdateDelivery(s.Ctx, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// Error at Updates
+	s.mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), model.WebhookDeliverySucceeded, 2, nil, 200, nil, now, 5).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.UpdateDelivery(s.Ctx, data)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectExec(query).
+		WithArgs(sqlmock.AnyArg(), model.WebhookDeliverySucceeded, 2, nil, 200, nil, now, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = s.repository.UpdateDelivery(s.Ctx, data)
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestListSecretsToReencrypt() {
+	t := s.Suite.T()
+	const encryptionId = "0011223344556677"
+	now := time.Now()
+	webhookID := uuid.New()
+	query := `SELECT \* FROM "webhooks" WHERE encryption_id IS NULL OR encryption_id <> \$1 ORDER BY id FOR UPDATE`
+
+	// encryptionId is empty
+	output, err := s.repository.ListSecretsToReencrypt(s.Ctx, "")
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "code=400, message='encryptionId' cannot be empty")
+
+	// Error at Find
+	s.mock.ExpectQuery(query).
+		WithArgs(encryptionId).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	output, err = s.repository.ListSecretsToReencrypt(s.Ctx, encryptionId)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WithArgs(encryptionId).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).
+			AddRow(1, now, now, "12345", webhookID, "https://itsm.example.com/hook",
+				`["domain.created"]`, true, 0, nil))
+	output, err = s.repository.ListSecretsToReencrypt(s.Ctx, encryptionId)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	assert.Equal(t, webhookID, output[0].WebhookUuid)
+	assert.Nil(t, output[0].EncryptionId)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestListLegacySecrets() {
+	t := s.Suite.T()
+	now := time.Now()
+	webhookID := uuid.New()
+	query := `SELECT \* FROM "webhooks" WHERE encryption_id IS NULL ORDER BY id FOR UPDATE`
+
+	// Error at Find
+	s.mock.ExpectQuery(query).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	output, err := s.repository.ListLegacySecrets(s.Ctx)
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Success
+	s.mock.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows(webhookColumns).
+			AddRow(1, now, now, "12345", webhookID, "https://itsm.example.com/hook",
+				`["domain.created"]`, true, 0, nil))
+	output, err = s.repository.ListLegacySecrets(s.Ctx)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+	assert.Equal(t, webhookID, output[0].WebhookUuid)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func (s *SuiteWebhook) TestUpdateSecret() {
+	t := s.Suite.T()
+	data := &model.Webhook{
+		ID:              3,
+		WebhookUuid:     uuid.New(),
+		EncryptedSecret: []byte("encrypted secret"),
+		EncryptionId:    pointy.String("0011223344556677"),
+	}
+	query := `UPDATE "webhooks" SET "encrypted_secret"=\$1,"encryption_id"=\$2 WHERE id = \$3`
+
+	// data is nil
+	err := s.repository.UpdateSecret(s.Ctx, nil)
+	assert.EqualError(t, err, "code=500, message='data' cannot be nil")
+
+	// Error at Updates
+	s.mock.ExpectExec(query).
+		WithArgs(data.EncryptedSecret, *data.EncryptionId, 3).
+		WillReturnError(gorm.ErrInvalidTransaction)
+	err = s.repository.UpdateSecret(s.Ctx, data)
+	assert.EqualError(t, err, "invalid transaction")
+
+	// Not found
+	s.mock.ExpectExec(query).
+		WithArgs(data.EncryptedSecret, *data.EncryptionId, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = s.repository.UpdateSecret(s.Ctx, data)
+	assert.ErrorContains(t, err, "unknown webhook '"+data.WebhookUuid.String()+"'")
+
+	// Success
+	s.mock.ExpectExec(query).
+		WithArgs(data.EncryptedSecret, *data.EncryptionId, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err = s.repository.UpdateSecret(s.Ctx, data)
+	assert.NoError(t, err)
+	require.NoError(t, s.mock.ExpectationsWereMet())
+}
+
+func TestSuiteWebhook(t *testing.T) {
+	suite.Run(t, new(SuiteWebhook))
+}
@@ -0,0 +1,340 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
+	"github.com/podengo-project/idmsvc-backend/internal/interface/repository"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"gorm.io/gorm"
+)
+
+const (
+	// dispatchBatchSize is the max number of deliveries attempted by
+	// every dispatch.
+	dispatchBatchSize = 10
+	// maxResponseBody is the max bytes read from the response of a
+	// webhook, which is discarded.
+	maxResponseBody = 64 * 1024
+	// userAgent is the user agent of the deliveries.
+	userAgent = "idmsvc-webhooks"
+
+	resultSucceeded = "succeeded"
+	resultRetried   = "retried"
+	resultFailed    = "failed"
+	resultDisabled  = "disabled"
+
+	// claimLeaseMargin is added to the time the claimed deliveries
+	// are kept from the other instances.
+	claimLeaseMargin = time.Minute
+)
+
+// Dispatcher deliver the pending deliveries to their webhooks.
+type Dispatcher struct {
+	config     config.Webhooks
+	secrets    *secrets.AppSecrets
+	client     *http.Client
+	repository repository.WebhookRepository
+	metrics    *metrics.Metrics
+	now        func() time.Time
+}
+
+// errAddressNotAllowed is returned by the dialer of the deliveries
+// when the webhook host resolves to a non public address.
+var errAddressNotAllowed = errors.New("the address of the webhook is not allowed")
+
+// NewDispatcher create the dispatcher of the webhook deliveries.
+// cfg is the application configuration.
+// r is the webhook repository.
+// m is the application metrics.
+func NewDispatcher(cfg *config.Config, r repository.WebhookRepository, m *metrics.Metrics) *Dispatcher {
+	if cfg == nil {
+		panic("'cfg' is nil")
+	}
+	if r == nil {
+		panic("'r' is nil")
+	}
+	if m == nil {
+		panic("'m' is nil")
+	}
+	webhooks := cfg.Application.Webhooks
+	if webhooks.Timeout <= 0 {
+		webhooks.Timeout = config.DefaultWebhooksTimeout
+	}
+	if webhooks.MaxAttempts <= 0 {
+		webhooks.MaxAttempts = 1
+	}
+	dialer := &net.Dialer{Timeout: webhooks.Timeout}
+	if !webhooks.AllowPrivateNetworks {
+		// The address is checked once it is resolved, right before
+		// connecting, so a name resolving to a private address, or
+		// changing to it after the url was validated, is rejected.
+		dialer.Control = controlPublicAddr
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be the checked address instead of the webhook.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Dispatcher{
+		config:  webhooks,
+		secrets: &cfg.Secrets,
+		client: &http.Client{
+			Timeout:   webhooks.Timeout,
+			Transport: transport,
+			// The redirects are not followed, so the deliveries
+			// only reach the registered url.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		repository: r,
+		metrics:    m,
+		now:        time.Now,
+	}
+}
+
+// controlPublicAddr is the net.Dialer Control function which
+// reject the connections to non public addresses.
+func controlPublicAddr(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, err.Error())
+	}
+	if !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, addrPort.Addr().String())
+	}
+	return nil
+}
+
+// Dispatch attempt the pending deliveries which are due, and save
+// their state and the state of their webhooks. The deliveries are
+// claimed in a first transaction, so the other instances skip them;
+// then every delivery is posted with no transaction open, and the
+// result of every attempt is saved in its own transaction.
+// ctx is the context with db and slog instances; the db must not be
+// a transaction.
+// Return the number of attempted deliveries and nil error on
+// success, else an error if the deliveries could not be read or
+// saved.
+func (d *Dispatcher) Dispatch(ctx context.Context) (count int, err error) {
+	var deliveries []model.WebhookDelivery
+	now := d.now()
+	leaseUntil := now.Add(d.lease())
+	if err = d.transaction(ctx, func(tx context.Context) (err error) {
+		deliveries, err = d.repository.ClaimPending(tx, now, leaseUntil, dispatchBatchSize)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+	webhooks := map[uint]*model.Webhook{}
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			if webhook = delivery.Webhook; webhook == nil {
+				return count, fmt.Errorf("webhook of the delivery '%s' was not loaded", delivery.DeliveryUuid.String())
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+		result, err := d.attempt(ctx, webhook, delivery)
+		if err != nil {
+			return count, err
+		}
+		d.metrics.WebhookDeliveries.WithLabelValues(result).Inc()
+		count++
+	}
+	return count, nil
+}
+
+// lease return the time the claimed deliveries are kept from the
+// other instances, which covers the attempts of a whole batch.
+func (d *Dispatcher) lease() time.Duration {
+	return time.Duration(dispatchBatchSize)*d.config.Timeout + claimLeaseMargin
+}
+
+// transaction run fn with a transaction of the db of ctx, which is
+// committed when fn returns nil.
+func (d *Dispatcher) transaction(ctx context.Context, fn func(tx context.Context) error) error {
+	return app_context.DBFromCtx(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(app_context.CtxWithDB(ctx, tx))
+	})
+}
+
+// attempt deliver the payload of delivery to webhook, and save the
+// state of both.
+// Return the result of the attempt for the metrics, and nil error
+// when the state was saved, else an error.
+func (d *Dispatcher) attempt(
+	ctx context.Context,
+	webhook *model.Webhook,
+	delivery *model.WebhookDelivery,
+) (string, error) {
+	logger := app_context.LogFromCtx(ctx).With(
+		slog.String("org_id", webhook.OrgId),
+		slog.String("webhook", webhook.WebhookUuid.String()),
+		slog.String("delivery", delivery.DeliveryUuid.String()),
+		slog.String("event", delivery.Event),
+	)
+	delivery.Attempts++
+	if !webhook.IsEnabled() {
+		logger.Info("webhook is disabled, the delivery is cancelled")
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.Error = stringPtr("webhook is disabled")
+		return resultDisabled, d.transaction(ctx, func(tx context.Context) error {
+			return d.repository.UpdateDelivery(tx, delivery)
+		})
+	}
+
+	now := d.now()
+	status, postErr := d.post(ctx, webhook, delivery, now)
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	if postErr != nil {
+		// The details are only logged; the delivery has a generic
+		// error, as it is returned to the organization.
+		logger.Warn("failed to deliver the event",
+			slog.Int("attempts", delivery.Attempts),
+			slog.String("error", postErr.Error()),
+		)
+	}
+	enabled := webhook.IsEnabled()
+	result := resultFailed
+	if err := d.transaction(ctx, func(tx context.Context) error {
+		if err := d.repository.RecordAttempt(tx, webhook, postErr == nil, d.config.FailureThreshold, now); err != nil {
+			return err
+		}
+		result = d.deliveryResult(webhook, delivery, postErr, now)
+		return d.repository.UpdateDelivery(tx, delivery)
+	}); err != nil {
+		return result, err
+	}
+	if enabled && !webhook.IsEnabled() {
+		logger.Warn("webhook disabled after repeated failed deliveries",
+			slog.Int("consecutive_failures", webhook.ConsecutiveFailures),
+		)
+	}
+	if result == resultSucceeded {
+		logger.Info("event delivered", slog.Int("status", status))
+	}
+	return result, nil
+}
+
+// deliveryResult update the state of delivery with the error of the
+// attempt at now; a failed delivery is retried until the max
+// attempts, unless webhook is disabled.
+// Return the result of the attempt for the metrics.
+func (d *Dispatcher) deliveryResult(
+	webhook *model.Webhook,
+	delivery *model.WebhookDelivery,
+	err error,
+	now time.Time,
+) string {
+	if err == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.Error = nil
+		return resultSucceeded
+	}
+	delivery.Error = stringPtr(deliveryError(err))
+	if !webhook.IsEnabled() || delivery.Attempts >= d.config.MaxAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return resultFailed
+	}
+	next := now.Add(Backoff(delivery.Attempts, d.config.Backoff, d.config.MaxBackoff))
+	delivery.NextAttemptAt = &next
+	return resultRetried
+}
+
+// deliveryError return the category of a failed attempt, which does
+// not disclose the details of the network of the service.
+func deliveryError(err error) string {
+	var (
+		statusErr *statusError
+		dnsErr    *net.DNSError
+		netErr    net.Error
+		certErr   *tls.CertificateVerificationError
+	)
+	switch {
+	case errors.As(err, &statusErr):
+		return statusErr.Error()
+	case errors.Is(err, errAddressNotAllowed):
+		return "the address of the webhook is not allowed"
+	case errors.Is(err, ErrSecretDecryptionFailed):
+		return ErrSecretDecryptionFailed.Error()
+	case errors.As(err, &dnsErr):
+		return "the host of the webhook could not be resolved"
+	case errors.As(err, &certErr):
+		return "the certificate of the webhook is not valid"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "the webhook did not respond in time"
+	default:
+		return "the webhook could not be reached"
+	}
+}
+
+// statusError is the error of a response with a non-2xx status.
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d", e.status)
+}
+
+// post send the signed payload of delivery to the url of webhook.
+// Return the http status of the response, or 0 when there is no
+// response, and nil error when the status is 2xx.
+func (d *Dispatcher) post(
+	ctx context.Context,
+	webhook *model.Webhook,
+	delivery *model.WebhookDelivery,
+	now time.Time,
+) (int, error) {
+	secret, err := SecretOf(d.secrets, webhook)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.DeliveryUuid.String())
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(secret, now, delivery.Payload))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &statusError{status: resp.StatusCode}
+	}
+	return resp.StatusCode, nil
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
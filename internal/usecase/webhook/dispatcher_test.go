@@ -0,0 +1,407 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/config"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	app_context "github.com/podengo-project/idmsvc-backend/internal/infrastructure/context"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
+	"github.com/podengo-project/idmsvc-backend/internal/metrics"
+	"github.com/podengo-project/idmsvc-backend/internal/test"
+	repository "github.com/podengo-project/idmsvc-backend/internal/test/mock/interface/repository"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+	"gorm.io/gorm"
+)
+
+var testWebhookKey = []byte("0123456789abcdef0123456789abcdef")
+
+// receiver is a local webhook receiver which verify the signature of
+// the deliveries and reply with the configured status.
+type receiver struct {
+	t      *testing.T
+	mutex  sync.Mutex
+	status int
+	// secrets are the signing secrets of the webhooks with an
+	// encrypted secret; the others use the derived secret.
+	secrets  map[uuid.UUID]string
+	received []http.Header
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+	webhookID, err := uuid.Parse(req.URL.Query().Get("webhook"))
+	require.NoError(r.t, err)
+	unix, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(r.t, err)
+	secret, ok := r.secrets[webhookID]
+	if !ok {
+		secret = DerivedSecret(testWebhookKey, webhookID)
+	}
+	expected := Sign(secret, time.Unix(unix, 0), body)
+	assert.Equal(r.t, expected, req.Header.Get(HeaderSignature))
+	assert.Equal(r.t, http.MethodPost, req.Method)
+	assert.Equal(r.t, "application/json", req.Header.Get("Content-Type"))
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.received = append(r.received, req.Header.Clone())
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func helperDispatcher(t *testing.T, threshold int, allowPrivate bool) (*Dispatcher, *repository.WebhookRepository, *metrics.Metrics) {
+	cfg := &config.Config{}
+	cfg.Application.Webhooks = config.Webhooks{
+		Enabled:              true,
+		Timeout:              time.Second,
+		MaxAttempts:          3,
+		Backoff:              30 * time.Second,
+		MaxBackoff:           time.Hour,
+		FailureThreshold:     threshold,
+		AllowHTTP:            true,
+		AllowPrivateNetworks: allowPrivate,
+	}
+	sec, err := secrets.NewAppSecrets(secrets.GenerateRandomMainSecret())
+	require.NoError(t, err)
+	cfg.Secrets = *sec
+	cfg.Secrets.WebhookKey = testWebhookKey
+	r := repository.NewWebhookRepository(t)
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	d := NewDispatcher(cfg, r, m)
+	d.now = func() time.Time { return testDispatchNow }
+	return d, r, m
+}
+
+// helperDispatchContext return the context with a mock db, which
+// expects the transactions of the dispatcher.
+func helperDispatchContext(t *testing.T) (context.Context, sqlmock.Sqlmock) {
+	sqlMock, db, err := test.NewSqlMock(&gorm.Session{SkipHooks: true})
+	require.NoError(t, err)
+	ctx := app_context.CtxWithLog(context.Background(), slog.Default())
+	return app_context.CtxWithDB(ctx, db), sqlMock
+}
+
+// expectTransactions expect count committed transactions.
+func expectTransactions(sqlMock sqlmock.Sqlmock, count int) {
+	for i := 0; i < count; i++ {
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectCommit()
+	}
+}
+
+// recordAttempt is the RecordAttempt of the repository, applied on
+// the webhook in memory.
+func recordAttempt(threshold int) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		data := args.Get(1).(*model.Webhook)
+		if args.Bool(2) {
+			data.ConsecutiveFailures = 0
+			return
+		}
+		data.ConsecutiveFailures++
+		if threshold > 0 && data.ConsecutiveFailures >= threshold && data.IsEnabled() {
+			now := args.Get(4).(time.Time)
+			data.Enabled = pointy.Bool(false)
+			data.DisabledAt = &now
+		}
+	}
+}
+
+var testDispatchNow = time.Unix(1700000000, 0)
+
+func helperWebhookDelivery(srv *httptest.Server, id uint, enabled bool) *model.Webhook {
+	webhookID := uuid.New()
+	return &model.Webhook{
+		ID:          id,
+		OrgId:       "12345",
+		WebhookUuid: webhookID,
+		URL:         fmt.Sprintf("%s/hook?webhook=%s", srv.URL, webhookID.String()),
+		Events:      []string{"domain.created"},
+		Enabled:     pointy.Bool(enabled),
+	}
+}
+
+func TestNewDispatcher(t *testing.T) {
+	cfg := &config.Config{}
+	r := repository.NewWebhookRepository(t)
+	m := metrics.NewMetrics(prometheus.NewRegistry())
+	assert.PanicsWithValue(t, "'cfg' is nil", func() { NewDispatcher(nil, r, m) })
+	assert.PanicsWithValue(t, "'r' is nil", func() { NewDispatcher(cfg, nil, m) })
+	assert.PanicsWithValue(t, "'m' is nil", func() { NewDispatcher(cfg, r, nil) })
+	d := NewDispatcher(cfg, r, m)
+	assert.Equal(t, config.DefaultWebhooksTimeout, d.client.Timeout)
+	assert.Equal(t, 1, d.config.MaxAttempts)
+	assert.Equal(t, 10*config.DefaultWebhooksTimeout+time.Minute, d.lease())
+}
+
+func TestDispatchClaimPendingError(t *testing.T) {
+	d, r, _ := helperDispatcher(t, 10, true)
+	now := testDispatchNow
+	ctx, sqlMock := helperDispatchContext(t)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).Return(nil, fmt.Errorf("db error"))
+	count, err := d.Dispatch(ctx)
+	assert.EqualError(t, err, "db error")
+	assert.Equal(t, 0, count)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDispatchSucceeded(t *testing.T) {
+	recv := &receiver{t: t, status: http.StatusNoContent}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	d, r, m := helperDispatcher(t, 10, true)
+	ctx, sqlMock := helperDispatchContext(t)
+	now := testDispatchNow
+	webhook := helperWebhookDelivery(srv, 1, true)
+	webhook.ConsecutiveFailures = 2
+	require.NoError(t, NewSecret(d.secrets, webhook))
+	recv.secrets = map[uuid.UUID]string{webhook.WebhookUuid: webhook.Secret}
+	webhook.Secret = ""
+	deliveryID := uuid.New()
+	payload := []byte(`{"event":"domain.created"}`)
+	deliveries := []model.WebhookDelivery{
+		{
+			ID:            1,
+			WebhookID:     webhook.ID,
+			Webhook:       webhook,
+			DeliveryUuid:  deliveryID,
+			Event:         "domain.created",
+			Payload:       payload,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		},
+	}
+	expectTransactions(sqlMock, 2)
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).Return(deliveries, nil)
+	r.On("RecordAttempt", mock.Anything, webhook, true, 10, now).Run(recordAttempt(10)).Return(nil)
+	r.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(data *model.WebhookDelivery) bool {
+		return data.Status == model.WebhookDeliverySucceeded &&
+			data.Attempts == 1 &&
+			data.NextAttemptAt == nil &&
+			data.DeliveredAt != nil && data.DeliveredAt.Equal(now) &&
+			data.ResponseStatus != nil && *data.ResponseStatus == http.StatusNoContent &&
+			data.Error == nil
+	})).Return(nil)
+
+	count, err := d.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 0, webhook.ConsecutiveFailures)
+	require.Len(t, recv.received, 1)
+	assert.Equal(t, payload, recv.bodies[0])
+	assert.Equal(t, "domain.created", recv.received[0].Get(HeaderEvent))
+	assert.Equal(t, deliveryID.String(), recv.received[0].Get(HeaderDelivery))
+	assert.Equal(t, "1700000000", recv.received[0].Get(HeaderTimestamp))
+	assert.Equal(t, userAgent, recv.received[0].Get("User-Agent"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebhookDeliveries.WithLabelValues(resultSucceeded)))
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDispatchRetriedAndFailed(t *testing.T) {
+	recv := &receiver{t: t, status: http.StatusInternalServerError}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	d, r, m := helperDispatcher(t, 10, true)
+	ctx, sqlMock := helperDispatchContext(t)
+	now := testDispatchNow
+	webhook := helperWebhookDelivery(srv, 1, true)
+	deliveries := []model.WebhookDelivery{
+		{
+			ID:           1,
+			WebhookID:    webhook.ID,
+			Webhook:      webhook,
+			DeliveryUuid: uuid.New(),
+			Event:        "domain.created",
+			Payload:      []byte(`{}`),
+			Status:       model.WebhookDeliveryPending,
+		},
+		{
+			ID:           2,
+			WebhookID:    webhook.ID,
+			Webhook:      &model.Webhook{},
+			DeliveryUuid: uuid.New(),
+			Event:        "domain.created",
+			Payload:      []byte(`{}`),
+			Status:       model.WebhookDeliveryPending,
+			Attempts:     2,
+		},
+	}
+	next := now.Add(30 * time.Second)
+	expectTransactions(sqlMock, 3)
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).Return(deliveries, nil)
+	r.On("RecordAttempt", mock.Anything, webhook, false, 10, now).Run(recordAttempt(10)).Return(nil).Twice()
+	r.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(data *model.WebhookDelivery) bool {
+		return data.ID == 1 &&
+			data.Status == model.WebhookDeliveryPending &&
+			data.Attempts == 1 &&
+			data.NextAttemptAt != nil && data.NextAttemptAt.Equal(next) &&
+			data.ResponseStatus != nil && *data.ResponseStatus == http.StatusInternalServerError &&
+			data.Error != nil && *data.Error == "webhook responded with status 500"
+	})).Return(nil)
+	r.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(data *model.WebhookDelivery) bool {
+		return data.ID == 2 &&
+			data.Status == model.WebhookDeliveryFailed &&
+			data.Attempts == 3 &&
+			data.NextAttemptAt == nil
+	})).Return(nil)
+
+	count, err := d.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Len(t, recv.received, 2)
+	assert.True(t, webhook.IsEnabled())
+	assert.Equal(t, 2, webhook.ConsecutiveFailures)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebhookDeliveries.WithLabelValues(resultRetried)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebhookDeliveries.WithLabelValues(resultFailed)))
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDispatchDisableWebhook(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	d, r, m := helperDispatcher(t, 3, true)
+	ctx, sqlMock := helperDispatchContext(t)
+	now := testDispatchNow
+	webhook := helperWebhookDelivery(srv, 1, true)
+	webhook.ConsecutiveFailures = 2
+	deliveries := []model.WebhookDelivery{
+		{ID: 1, WebhookID: webhook.ID, Webhook: webhook, DeliveryUuid: uuid.New(), Event: "domain.created"},
+		{ID: 2, WebhookID: webhook.ID, Webhook: webhook, DeliveryUuid: uuid.New(), Event: "domain.created"},
+	}
+	expectTransactions(sqlMock, 3)
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).Return(deliveries, nil)
+	r.On("RecordAttempt", mock.Anything, webhook, false, 3, now).Run(recordAttempt(3)).Return(nil).Once()
+	r.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(data *model.WebhookDelivery) bool {
+		return data.ID == 1 &&
+			data.Status == model.WebhookDeliveryFailed &&
+			data.ResponseStatus == nil &&
+			data.Error != nil && *data.Error == "the webhook could not be reached"
+	})).Return(nil)
+	r.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(data *model.WebhookDelivery) bool {
+		return data.ID == 2 &&
+			data.Status == model.WebhookDeliveryFailed &&
+			data.Error != nil && *data.Error == "webhook is disabled"
+	})).Return(nil)
+
+	count, err := d.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.False(t, webhook.IsEnabled())
+	assert.Equal(t, 3, webhook.ConsecutiveFailures)
+	require.NotNil(t, webhook.DisabledAt)
+	assert.Equal(t, now, *webhook.DisabledAt)
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebhookDeliveries.WithLabelValues(resultFailed)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.WebhookDeliveries.WithLabelValues(resultDisabled)))
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDispatchPrivateAddress(t *testing.T) {
+	recv := &receiver{t: t, status: http.StatusOK}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	d, r, _ := helperDispatcher(t, 10, false)
+	ctx, sqlMock := helperDispatchContext(t)
+	now := testDispatchNow
+	webhook := helperWebhookDelivery(srv, 1, true)
+	deliveries := []model.WebhookDelivery{
+		{ID: 1, WebhookID: webhook.ID, Webhook: webhook, DeliveryUuid: uuid.New(), Event: "domain.created", Status: model.WebhookDeliveryPending},
+	}
+	expectTransactions(sqlMock, 2)
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).Return(deliveries, nil)
+	r.On("RecordAttempt", mock.Anything, webhook, false, 10, now).Run(recordAttempt(10)).Return(nil)
+	r.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(data *model.WebhookDelivery) bool {
+		return data.Status == model.WebhookDeliveryPending &&
+			data.Error != nil && *data.Error == "the address of the webhook is not allowed"
+	})).Return(nil)
+
+	count, err := d.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Len(t, recv.received, 0)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestDispatchUpdateError(t *testing.T) {
+	recv := &receiver{t: t, status: http.StatusOK}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	d, r, _ := helperDispatcher(t, 10, true)
+	ctx, sqlMock := helperDispatchContext(t)
+	now := testDispatchNow
+	webhook := helperWebhookDelivery(srv, 1, true)
+	deliveries := []model.WebhookDelivery{
+		{ID: 1, WebhookID: webhook.ID, Webhook: webhook, DeliveryUuid: uuid.New(), Event: "domain.created"},
+	}
+	expectTransactions(sqlMock, 1)
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).Return(deliveries, nil).Once()
+	r.On("RecordAttempt", mock.Anything, webhook, true, 10, now).Return(nil)
+	r.On("UpdateDelivery", mock.Anything, mock.Anything).Return(fmt.Errorf("db error"))
+	count, err := d.Dispatch(ctx)
+	assert.EqualError(t, err, "db error")
+	assert.Equal(t, 0, count)
+
+	expectTransactions(sqlMock, 1)
+	r.On("ClaimPending", mock.Anything, now, now.Add(d.lease()), dispatchBatchSize).
+		Return([]model.WebhookDelivery{{ID: 2, WebhookID: 2, DeliveryUuid: uuid.Nil}}, nil).Once()
+	count, err = d.Dispatch(ctx)
+	assert.EqualError(t, err, "webhook of the delivery '00000000-0000-0000-0000-000000000000' was not loaded")
+	assert.Equal(t, 0, count)
+	require.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// timeoutError is a net.Error which timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestDeliveryError(t *testing.T) {
+	assert.Equal(t, "webhook responded with status 503",
+		deliveryError(&statusError{status: http.StatusServiceUnavailable}))
+	assert.Equal(t, "the address of the webhook is not allowed",
+		deliveryError(&url.Error{Op: "Post", URL: "https://hooks.example.com", Err: fmt.Errorf("%w: 10.0.0.1", errAddressNotAllowed)}))
+	assert.Equal(t, "the host of the webhook could not be resolved",
+		deliveryError(&url.Error{Op: "Post", URL: "https://hooks.example.com", Err: &net.DNSError{Err: "no such host", Name: "hooks.example.com"}}))
+	assert.Equal(t, "the certificate of the webhook is not valid",
+		deliveryError(&url.Error{Op: "Post", URL: "https://hooks.example.com", Err: &tls.CertificateVerificationError{Err: fmt.Errorf("x509: unknown authority")}}))
+	assert.Equal(t, "the webhook did not respond in time",
+		deliveryError(&url.Error{Op: "Post", URL: "https://hooks.example.com", Err: timeoutError{}}))
+	assert.Equal(t, "the webhook could not be reached",
+		deliveryError(&url.Error{Op: "Post", URL: "https://10.0.0.1:8443", Err: fmt.Errorf("dial tcp 10.0.0.1:8443: connection refused")}))
+	assert.Equal(t, "the secret of the webhook could not be decrypted",
+		deliveryError(fmt.Errorf("%w: unknown encryption id '0011223344556677'", ErrSecretDecryptionFailed)))
+}
+
+func TestControlPublicAddr(t *testing.T) {
+	assert.NoError(t, controlPublicAddr("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, controlPublicAddr("tcp4", "127.0.0.1:443", nil), errAddressNotAllowed)
+	assert.ErrorIs(t, controlPublicAddr("tcp6", "[fd00::1]:443", nil), errAddressNotAllowed)
+	assert.ErrorIs(t, controlPublicAddr("tcp", "invalid", nil), errAddressNotAllowed)
+}
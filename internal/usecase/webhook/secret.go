@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
+)
+
+// secretLength is the number of random bytes of a signing secret.
+const secretLength = 32
+
+// ErrSecretDecryptionFailed is returned when the signing secret of a
+// webhook is encrypted with an unknown main secret, or it cannot be
+// decrypted.
+var ErrSecretDecryptionFailed = errors.New("the secret of the webhook could not be decrypted")
+
+// NewSecret generate a random signing secret for a new webhook. The
+// secret is set in data.Secret, and it is encrypted with the current
+// main secret generation into data.EncryptedSecret.
+// s is the application secrets.
+// data is the new webhook.
+// Return nil on success, else an error.
+func NewSecret(s *secrets.AppSecrets, data *model.Webhook) error {
+	random := make([]byte, secretLength)
+	if _, err := io.ReadFull(rand.Reader, random); err != nil {
+		return err
+	}
+	secret := hex.EncodeToString(random)
+	if err := encryptSecret(s, data, secret); err != nil {
+		return err
+	}
+	data.Secret = secret
+	return nil
+}
+
+// SecretOf return the signing secret of a webhook, decrypted with the
+// main secret generation which encrypted it. The webhooks without an
+// encrypted secret use DerivedSecret.
+// s is the application secrets.
+// data is the webhook.
+// Return the secret and nil error on success, else an empty string
+// and an error which wraps ErrSecretDecryptionFailed.
+func SecretOf(s *secrets.AppSecrets, data *model.Webhook) (string, error) {
+	if data.EncryptionId == nil {
+		return DerivedSecret(s.WebhookKey, data.WebhookUuid), nil
+	}
+	key, ok := s.WebhookEncryptionKeyFor(*data.EncryptionId)
+	if !ok {
+		return "", fmt.Errorf("%w: unknown encryption id '%s'", ErrSecretDecryptionFailed, *data.EncryptionId)
+	}
+	secret, err := openSecret(key, data.EncryptedSecret)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSecretDecryptionFailed, err)
+	}
+	return string(secret), nil
+}
+
+// ReencryptSecret encrypt the signing secret of a webhook with the
+// current main secret generation. The derived secret of a webhook
+// without an encrypted secret is stored too, so it does not change
+// when the main secret is rotated.
+// s is the application secrets.
+// data is the webhook.
+// Return nil on success, else an error.
+func ReencryptSecret(s *secrets.AppSecrets, data *model.Webhook) error {
+	secret, err := SecretOf(s, data)
+	if err != nil {
+		return err
+	}
+	return encryptSecret(s, data, secret)
+}
+
+// encryptSecret set the encrypted secret of data and the encryption id
+// of the current main secret generation.
+func encryptSecret(s *secrets.AppSecrets, data *model.Webhook, secret string) error {
+	encrypted, err := sealSecret(s.WebhookEncryptionKey, []byte(secret))
+	if err != nil {
+		return err
+	}
+	encryptionId := s.HostconfEncryptionId
+	data.EncryptedSecret = encrypted
+	data.EncryptionId = &encryptionId
+	return nil
+}
+
+// sealSecret encrypt plaintext with AES-GCM and a random nonce, which
+// is pre-pended to the ciphertext.
+func sealSecret(key []byte, plaintext []byte) ([]byte, error) {
+	aesgcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aesgcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aesgcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openSecret decrypt the output of sealSecret.
+func openSecret(key []byte, encrypted []byte) ([]byte, error) {
+	aesgcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < aesgcm.NonceSize()+aesgcm.Overhead() {
+		return nil, errors.New("encrypted secret is too short")
+	}
+	nonce := encrypted[:aesgcm.NonceSize()]
+	return aesgcm.Open(nil, nonce, encrypted[aesgcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/domain/model"
+	"github.com/podengo-project/idmsvc-backend/internal/infrastructure/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.openly.dev/pointy"
+)
+
+func TestNewSecret(t *testing.T) {
+	sec, err := secrets.NewAppSecrets(secrets.GenerateRandomMainSecret())
+	require.NoError(t, err)
+	data := &model.Webhook{WebhookUuid: uuid.New()}
+
+	require.NoError(t, NewSecret(sec, data))
+	assert.Regexp(t, "^[0-9a-f]{64}$", data.Secret)
+	assert.NotEqual(t, DerivedSecret(sec.WebhookKey, data.WebhookUuid), data.Secret)
+	require.NotNil(t, data.EncryptionId)
+	assert.Equal(t, sec.HostconfEncryptionId, *data.EncryptionId)
+	assert.NotContains(t, string(data.EncryptedSecret), data.Secret)
+
+	secret, err := SecretOf(sec, data)
+	require.NoError(t, err)
+	assert.Equal(t, data.Secret, secret)
+
+	other := &model.Webhook{WebhookUuid: data.WebhookUuid}
+	require.NoError(t, NewSecret(sec, other))
+	assert.NotEqual(t, data.Secret, other.Secret)
+}
+
+func TestSecretOf(t *testing.T) {
+	current := secrets.GenerateRandomMainSecret()
+	previous := secrets.GenerateRandomMainSecret()
+	secPrevious, err := secrets.NewAppSecrets(previous)
+	require.NoError(t, err)
+	sec, err := secrets.NewAppSecrets(current, previous)
+	require.NoError(t, err)
+
+	// The webhooks without an encrypted secret use the derived one
+	data := &model.Webhook{WebhookUuid: uuid.New()}
+	secret, err := SecretOf(sec, data)
+	require.NoError(t, err)
+	assert.Equal(t, DerivedSecret(sec.WebhookKey, data.WebhookUuid), secret)
+
+	// The secret encrypted before the rotation does not change
+	require.NoError(t, NewSecret(secPrevious, data))
+	secret, err = SecretOf(sec, data)
+	require.NoError(t, err)
+	assert.Equal(t, data.Secret, secret)
+
+	// Unknown main secret
+	data.EncryptionId = pointy.String("0011223344556677")
+	_, err = SecretOf(sec, data)
+	assert.ErrorIs(t, err, ErrSecretDecryptionFailed)
+
+	// Tampered secret
+	data.EncryptionId = pointy.String(sec.Previous[0].HostconfEncryptionId)
+	data.EncryptedSecret[len(data.EncryptedSecret)-1] ^= 0xff
+	_, err = SecretOf(sec, data)
+	assert.ErrorIs(t, err, ErrSecretDecryptionFailed)
+
+	data.EncryptedSecret = []byte("short")
+	_, err = SecretOf(sec, data)
+	assert.ErrorIs(t, err, ErrSecretDecryptionFailed)
+}
+
+func TestReencryptSecret(t *testing.T) {
+	current := secrets.GenerateRandomMainSecret()
+	previous := secrets.GenerateRandomMainSecret()
+	secPrevious, err := secrets.NewAppSecrets(previous)
+	require.NoError(t, err)
+	sec, err := secrets.NewAppSecrets(current, previous)
+	require.NoError(t, err)
+	secRotated, err := secrets.NewAppSecrets(secrets.GenerateRandomMainSecret(), current)
+	require.NoError(t, err)
+
+	// Encrypted with the previous main secret
+	data := &model.Webhook{WebhookUuid: uuid.New()}
+	require.NoError(t, NewSecret(secPrevious, data))
+	expected := data.Secret
+	require.NoError(t, ReencryptSecret(sec, data))
+	assert.Equal(t, sec.HostconfEncryptionId, *data.EncryptionId)
+	secret, err := SecretOf(secRotated, data)
+	require.NoError(t, err)
+	assert.Equal(t, expected, secret)
+
+	// The derived secret is stored, so it survives the rotation
+	legacy := &model.Webhook{WebhookUuid: uuid.New()}
+	require.NoError(t, ReencryptSecret(sec, legacy))
+	require.NotNil(t, legacy.EncryptionId)
+	secret, err = SecretOf(secRotated, legacy)
+	require.NoError(t, err)
+	assert.Equal(t, DerivedSecret(sec.WebhookKey, legacy.WebhookUuid), secret)
+
+	// Unknown main secret
+	data.EncryptionId = pointy.String("0011223344556677")
+	assert.ErrorIs(t, ReencryptSecret(sec, data), ErrSecretDecryptionFailed)
+}
@@ -0,0 +1,202 @@
+// Package webhook deliver the domain and host-conf events to the
+// webhooks of the organizations. Every delivery is signed with the
+// random secret of its webhook, and the failed deliveries are retried
+// with exponential backoff.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/podengo-project/idmsvc-backend/internal/api/public"
+)
+
+const (
+	// HeaderEvent is the name of the event of a delivery.
+	HeaderEvent = "X-Idmsvc-Event"
+	// HeaderDelivery is the uuid of a delivery; it is the same for
+	// every attempt, so the receiver can discard duplicates.
+	HeaderDelivery = "X-Idmsvc-Delivery"
+	// HeaderTimestamp is the unix time of an attempt.
+	HeaderTimestamp = "X-Idmsvc-Timestamp"
+	// HeaderSignature is the signature of an attempt.
+	HeaderSignature = "X-Idmsvc-Signature"
+
+	// signatureVersion is the prefix of the signatures, which name
+	// the signing scheme.
+	signatureVersion = "v1="
+)
+
+// DerivedSecret return the signing secret of a webhook created before
+// the secrets were stored, which is derived from the webhook key of
+// the application secrets.
+// key is the webhook key of the application secrets.
+// webhookID is the webhook uuid.
+// Return the secret as a hex string.
+func DerivedSecret(key []byte, webhookID uuid.UUID) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(webhookID.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign return the signature of an attempt, which is the HMAC-SHA256
+// of the timestamp, a dot and the body, keyed with the secret of the
+// webhook.
+// secret is the signing secret of the webhook.
+// timestamp is the time of the attempt.
+// body is the payload of the delivery.
+// Return the signature with the scheme prefix.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateURL check the url of a webhook is an absolute https url,
+// or http when allowHTTP is true. The host must be a name, which is
+// not a local name, unless allowPrivate is true; the addresses it
+// resolves to are checked when the deliveries connect, by the
+// dialer of NewDispatcher.
+// rawURL is the url to check.
+// allowHTTP accept the http scheme, for local test receivers.
+// allowPrivate accept the ip hosts and the local names, for local
+// test receivers.
+// Return nil if the url is valid, else an error with the details.
+func ValidateURL(rawURL string, allowHTTP bool, allowPrivate bool) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("'url' is not a valid url")
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return fmt.Errorf("'url' has no host")
+	}
+	if u.User != nil {
+		return fmt.Errorf("'url' cannot include credentials")
+	}
+	if u.Fragment != "" {
+		return fmt.Errorf("'url' cannot include a fragment")
+	}
+	if !allowPrivate {
+		if err = validateHost(u.Hostname()); err != nil {
+			return err
+		}
+	}
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if allowHTTP {
+			return nil
+		}
+	}
+	return fmt.Errorf("'url' scheme must be https")
+}
+
+// validateHost check the host of a webhook url is a public name.
+func validateHost(host string) error {
+	if _, err := netip.ParseAddr(host); err == nil {
+		return fmt.Errorf("'url' host cannot be an ip address")
+	}
+	name := strings.ToLower(strings.TrimSuffix(host, "."))
+	if name == "localhost" || strings.HasSuffix(name, ".localhost") {
+		return fmt.Errorf("'url' host cannot be a local name")
+	}
+	return nil
+}
+
+// nonPublicPrefixes are the networks which are not reachable from
+// internet, besides the loopback, private, link-local, unspecified
+// and multicast addresses.
+var nonPublicPrefixes = []netip.Prefix{
+	// "this" network
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// carrier-grade NAT
+	netip.MustParsePrefix("100.64.0.0/10"),
+	// IETF protocol assignments
+	netip.MustParsePrefix("192.0.0.0/24"),
+	// benchmarking
+	netip.MustParsePrefix("198.18.0.0/15"),
+	// reserved and broadcast
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// IsPublicAddr check an address is reachable from internet, so it
+// is not a loopback, private (including the unique-local ipv6),
+// link-local, carrier-grade NAT, unspecified, multicast or reserved
+// address.
+// addr is the address to check.
+// Return true when the address is public.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Backoff return the wait before the next attempt, which is doubled
+// for every failed attempt up to maxBackoff.
+// attempts is the number of failed attempts, starting at 1.
+// backoff is the wait after the first failed attempt.
+// maxBackoff is the max wait.
+func Backoff(attempts int, backoff, maxBackoff time.Duration) time.Duration {
+	wait := backoff
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxBackoff {
+		wait = maxBackoff
+	}
+	return wait
+}
+
+// Host is the host of a host.configured event.
+type Host struct {
+	InventoryId uuid.UUID `json:"inventory_id"`
+	Fqdn        string    `json:"fqdn"`
+}
+
+// Event is the payload of a delivery.
+type Event struct {
+	// Event is the name of the event.
+	Event public.WebhookEvent `json:"event"`
+	// OrgId is the organization of the domain.
+	OrgId string `json:"org_id"`
+	// OccurredAt is when the change happened.
+	OccurredAt time.Time `json:"occurred_at"`
+	// DomainId is the uuid of the domain.
+	DomainId uuid.UUID `json:"domain_id"`
+	// Domain is the domain after the change; it is not set for the
+	// domain.deleted event.
+	Domain *public.Domain `json:"domain,omitempty"`
+	// Host is the configured host of a host.configured event.
+	Host *Host `json:"host,omitempty"`
+}
+
+// Payload return the body of the deliveries for an event.
+func (e *Event) Payload() ([]byte, error) {
+	return json.Marshal(e)
+}
@@ -0,0 +1,8 @@
+-- File created by: ./bin/db-tool new active_directory_domains
+BEGIN;
+
+DROP TABLE IF EXISTS active_directory_sites;
+DROP TABLE IF EXISTS active_directory_domain_controllers;
+DROP TABLE IF EXISTS active_directories;
+
+COMMIT;
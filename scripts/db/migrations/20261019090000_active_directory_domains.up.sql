@@ -0,0 +1,53 @@
+-- File created by: ./bin/db-tool new active_directory_domains
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS active_directories (
+    id INT UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    realm_name VARCHAR(253) NOT NULL,
+    forest_name VARCHAR(253) NOT NULL,
+    update_agents TEXT NOT NULL,
+
+    CONSTRAINT fk_active_directories_id__domains_id
+        FOREIGN KEY (id)
+            REFERENCES domains(id)
+        ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS active_directory_domain_controllers (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    active_directory_id INT,
+    fqdn VARCHAR(253) NOT NULL,
+    site VARCHAR(63) DEFAULT NULL,
+    global_catalog BOOLEAN NOT NULL,
+
+    CONSTRAINT fk_ad_domain_controllers_ad_id__active_directories_id
+        FOREIGN KEY (active_directory_id)
+            REFERENCES active_directories(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS active_directory_sites (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    active_directory_id INT,
+    name VARCHAR(64) NOT NULL,
+    description TEXT DEFAULT NULL,
+
+    CONSTRAINT fk_ad_sites_ad_id__active_directories_id
+        FOREIGN KEY (active_directory_id)
+            REFERENCES active_directories(id)
+    ON DELETE CASCADE
+);
+
+COMMIT;
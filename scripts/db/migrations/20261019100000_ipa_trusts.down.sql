@@ -0,0 +1,8 @@
+-- File created by: ./bin/db-tool new ipa_trusts
+BEGIN;
+
+DROP TABLE IF EXISTS ipa_trust_id_ranges;
+DROP TABLE IF EXISTS ipa_trust_domains;
+DROP TABLE IF EXISTS ipa_trusts;
+
+COMMIT;
@@ -0,0 +1,57 @@
+-- File created by: ./bin/db-tool new ipa_trusts
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS ipa_trusts (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    ipa_id INT,
+    forest_name VARCHAR(253) NOT NULL,
+    flat_name VARCHAR(15) DEFAULT NULL,
+    sid VARCHAR(60) NOT NULL,
+    direction VARCHAR(16) NOT NULL,
+
+    CONSTRAINT fk_ipa_trusts_ipa_id__ipas_id
+        FOREIGN KEY (ipa_id)
+            REFERENCES ipas(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS ipa_trust_domains (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    ipa_trust_id INT,
+    name VARCHAR(253) NOT NULL,
+    flat_name VARCHAR(15) DEFAULT NULL,
+    sid VARCHAR(60) NOT NULL,
+
+    CONSTRAINT fk_ipa_trust_domains_ipa_trust_id__ipa_trusts_id
+        FOREIGN KEY (ipa_trust_id)
+            REFERENCES ipa_trusts(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS ipa_trust_id_ranges (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    ipa_trust_id INT,
+    name VARCHAR(255) NOT NULL,
+    base_id BIGINT NOT NULL,
+    range_size BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+
+    CONSTRAINT fk_ipa_trust_id_ranges_ipa_trust_id__ipa_trusts_id
+        FOREIGN KEY (ipa_trust_id)
+            REFERENCES ipa_trusts(id)
+    ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- File created by: ./bin/db-tool new ipa_id_ranges
+BEGIN;
+
+DROP INDEX IF EXISTS idx_ipa_id_ranges_ipa_id;
+DROP TABLE IF EXISTS ipa_id_ranges;
+
+COMMIT;
@@ -0,0 +1,26 @@
+-- File created by: ./bin/db-tool new ipa_id_ranges
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS ipa_id_ranges (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    ipa_id INT,
+    name VARCHAR(255) NOT NULL,
+    base_id BIGINT NOT NULL,
+    range_size BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    base_rid BIGINT DEFAULT NULL,
+    secondary_base_rid BIGINT DEFAULT NULL,
+
+    CONSTRAINT fk_ipa_id_ranges_ipa_id__ipas_id
+        FOREIGN KEY (ipa_id)
+            REFERENCES ipas(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_ipa_id_ranges_ipa_id ON ipa_id_ranges (ipa_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- File created by: ./bin/db-tool new domain_location_subnets
+BEGIN;
+
+DROP TABLE IF EXISTS domain_location_subnets;
+
+COMMIT;
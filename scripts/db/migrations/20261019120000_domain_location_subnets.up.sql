@@ -0,0 +1,22 @@
+-- File created by: ./bin/db-tool new domain_location_subnets
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS domain_location_subnets (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP DEFAULT NULL,
+
+    domain_id INT,
+    subnet VARCHAR(49) NOT NULL,
+    location VARCHAR(63) NOT NULL,
+
+    CONSTRAINT fk_domain_location_subnets_domain_id__domains_id
+        FOREIGN KEY (domain_id)
+            REFERENCES domains(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_location_subnets_domain_id ON domain_location_subnets (domain_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- File created by: ./bin/db-tool new rate_limit_buckets
+BEGIN;
+
+DROP TABLE IF EXISTS rate_limit_buckets;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- File created by: ./bin/db-tool new org_quotas
+BEGIN;
+
+DROP TABLE IF EXISTS host_conf_usage;
+DROP TABLE IF EXISTS org_quotas;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- File created by: ./bin/db-tool new org_quotas
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS org_quotas (
+    org_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+
+    max_domains INT DEFAULT NULL,
+    max_servers_per_domain INT DEFAULT NULL,
+    max_host_conf_per_day INT DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS host_conf_usage (
+    org_id VARCHAR(255) NOT NULL,
+    day DATE NOT NULL,
+    count INT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (org_id, day)
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- File created by: ./bin/db-tool new idempotency_keys
+BEGIN;
+
+DROP TABLE IF EXISTS idempotency_keys;
+
+COMMIT;
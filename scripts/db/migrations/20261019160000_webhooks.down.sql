@@ -0,0 +1,7 @@
+-- File created by: ./bin/db-tool new webhooks
+BEGIN;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+
+COMMIT;
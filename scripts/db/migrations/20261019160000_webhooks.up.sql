@@ -0,0 +1,45 @@
+-- File created by: ./bin/db-tool new webhooks
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS webhooks (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+
+    org_id VARCHAR(255) NOT NULL,
+    webhook_uuid UUID UNIQUE NOT NULL,
+    url VARCHAR(2048) NOT NULL,
+    events TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_org_id ON webhooks (org_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id SERIAL UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+
+    webhook_id INT NOT NULL,
+    delivery_uuid UUID UNIQUE NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    payload BYTEA NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP DEFAULT NULL,
+    response_status INT DEFAULT NULL,
+    error TEXT DEFAULT NULL,
+    delivered_at TIMESTAMP DEFAULT NULL,
+
+    CONSTRAINT fk_webhook_deliveries_webhook_id__webhooks_id
+        FOREIGN KEY (webhook_id)
+            REFERENCES webhooks(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+COMMIT;
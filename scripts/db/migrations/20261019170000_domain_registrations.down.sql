@@ -0,0 +1,10 @@
+-- File created by: ./bin/db-tool new domain_registrations
+BEGIN;
+
+DROP TABLE IF EXISTS org_settings;
+DROP TABLE IF EXISTS domain_registrations;
+
+ALTER TABLE domains
+    DROP COLUMN IF EXISTS registration_state;
+
+COMMIT;
@@ -0,0 +1,37 @@
+-- File created by: ./bin/db-tool new domain_registrations
+BEGIN;
+
+ALTER TABLE domains
+    ADD COLUMN IF NOT EXISTS registration_state VARCHAR(32) NOT NULL DEFAULT 'active';
+
+CREATE TABLE IF NOT EXISTS domain_registrations (
+    id INT UNIQUE NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+
+    subscription_manager_id VARCHAR(64) NOT NULL,
+    fqdn VARCHAR(253) DEFAULT NULL,
+    ipa_hcc_version VARCHAR(64) NOT NULL,
+    ipa_version VARCHAR(64) NOT NULL,
+    os_release_id VARCHAR(64) NOT NULL,
+    os_release_version_id VARCHAR(64) NOT NULL,
+    ca_certs TEXT NOT NULL,
+    decided_by VARCHAR(255) DEFAULT NULL,
+    decided_at TIMESTAMP DEFAULT NULL,
+    reason VARCHAR(1024) DEFAULT NULL,
+
+    CONSTRAINT fk_domain_registrations_id__domains_id
+        FOREIGN KEY (id)
+            REFERENCES domains(id)
+    ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS org_settings (
+    org_id VARCHAR(255) NOT NULL PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+
+    registration_approval BOOLEAN DEFAULT NULL
+);
+
+COMMIT;
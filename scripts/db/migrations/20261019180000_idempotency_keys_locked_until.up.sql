@@ -0,0 +1,7 @@
+-- File created by: ./bin/db-tool new idempotency_keys_locked_until
+BEGIN;
+
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- File created by: ./bin/db-tool new webhooks_encrypted_secret
+BEGIN;
+
+ALTER TABLE webhooks
+    DROP COLUMN IF EXISTS encryption_id,
+    DROP COLUMN IF EXISTS encrypted_secret;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- File created by: ./bin/db-tool new webhooks_encrypted_secret
+BEGIN;
+
+ALTER TABLE webhooks
+    ADD COLUMN IF NOT EXISTS encrypted_secret BYTEA DEFAULT NULL,
+    ADD COLUMN IF NOT EXISTS encryption_id VARCHAR(16) DEFAULT NULL;
+
+COMMIT;
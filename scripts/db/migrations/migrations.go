@@ -0,0 +1,10 @@
+// Package migrations embed the database migration scripts, so the
+// service knows the schema version it was built for.
+package migrations
+
+import "embed"
+
+// FS hold the migration scripts.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -177,7 +177,7 @@ update-api:
 	git submodule update --init --remote
 	$(MAKE) generate-api
 
-EVENTS := todo_created
+EVENTS := todo_created rbac_permission_changed
 # Generate event types
 .PHONY: generate-event
 generate-event: $(GOJSONSCHEMA) $(SCHEMA_JSON_FILES)  ## Generate event messages from schemas
@@ -207,6 +207,7 @@ MOCK_DIRS := internal/api/private \
 	internal/interface/interactor \
 	internal/interface/presenter \
 	internal/interface/event \
+	internal/interface/idempotency \
 	internal/interface/client/inventory \
 	internal/interface/client/rbac \
 	internal/interface/client/pendo \
@@ -87,7 +87,7 @@ KAFKA_CONFIG_DIR ?= $(PROJECT_DIR)/build/kafka/config
 
 # The topics used by the repository
 # Updated to follow the pattern used at playbook-dispatcher
-KAFKA_TOPICS ?= platform.idmsvc.todo-created
+KAFKA_TOPICS ?= platform.idmsvc.todo-created platform.rbac.permission-changed
 
 # The group id for the consumers; every consumer subscribed to
 # a topic with different group-id will receive a copy of the
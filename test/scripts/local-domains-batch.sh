@@ -0,0 +1,13 @@
+#!/bin/bash
+set -eo pipefail
+
+source "$(dirname "${BASH_SOURCE[0]}")/local.inc"
+
+BODY="$1"
+[ "${BODY}" != "" ] || error "BODY is empty"
+
+export X_RH_IDENTITY="${X_RH_IDENTITY:-$(identity_generator)}"
+unset X_RH_FAKE_IDENTITY
+unset CREDS
+
+exec "${REPOBASEDIR}/scripts/curl.sh" -i -X POST -d @"${BODY}" "${BASE_URL}/domains:batch"
@@ -0,0 +1,16 @@
+#!/bin/bash
+set -eo pipefail
+
+source "$(dirname "${BASH_SOURCE[0]}")/local.inc"
+
+UUID="$1"
+[ "${UUID}" != "" ] || error "UUID is empty"
+DECISION="$2"
+[ "${DECISION}" == "approve" ] || [ "${DECISION}" == "reject" ] || error "DECISION must be 'approve' or 'reject'"
+
+export X_RH_IDENTITY="${X_RH_IDENTITY:-$(identity_generator)}"
+unset X_RH_FAKE_IDENTITY
+unset CREDS
+
+# With a third argument, it is the reason of the decision
+exec "${REPOBASEDIR}/scripts/curl.sh" -i -X POST -d "{\"reason\":\"$3\"}" "${BASE_URL}/domains/${UUID}/${DECISION}"
@@ -0,0 +1,17 @@
+#!/bin/bash
+set -eo pipefail
+
+source "$(dirname "${BASH_SOURCE[0]}")/local.inc"
+
+UUID="$1"
+[ "${UUID}" != "" ] || error "UUID is empty"
+
+export X_RH_IDENTITY="${X_RH_IDENTITY:-$(identity_generator)}"
+unset X_RH_FAKE_IDENTITY
+unset CREDS
+
+# With a second argument, replace the mappings with the content of the file
+if [ "$2" != "" ]; then
+	exec "${REPOBASEDIR}/scripts/curl.sh" -i -X PUT -d @"$2" "${BASE_URL}/domains/${UUID}/location-subnets"
+fi
+exec "${REPOBASEDIR}/scripts/curl.sh" -i "${BASE_URL}/domains/${UUID}/location-subnets"